@@ -24,6 +24,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	log "github.com/golang/glog"
@@ -32,6 +33,7 @@ import (
 	"github.com/openconfig/ygot/gogen"
 	"github.com/openconfig/ygot/ygen"
 	"github.com/openconfig/ygot/ypathgen"
+	"github.com/spf13/viper"
 )
 
 const (
@@ -43,6 +45,9 @@ const (
 	schemaFn = "schema.go"
 	// interfaceFn is the filename to be used for interface code when outputting to a directory.
 	interfaceFn = "union.go"
+	// docFn is the filename to be used for the package documentation when
+	// it is output separately from the generated code.
+	docFn = "doc.go"
 	// structsFileFmt is the format string filename (missing index) to be
 	// used for files containing structs when outputting to a directory.
 	structsFileFmt = "structs-%d.go"
@@ -52,6 +57,7 @@ const (
 )
 
 var (
+	configFile              = flag.String(configFileFlag, "", "If set, a YAML or JSON file whose keys are the names of flags, whose values are used for any of these flags that are not set on the command line. The key \"modules\" specifies the list of input modules, which is used when no modules are specified on the command line.")
 	generateGoStructs       = flag.Bool("generate_structs", true, "If true, then Go code for YANG path construction (schema/Go structs) will be generated.")
 	generatePathStructs     = flag.Bool("generate_path_structs", false, "If true, then Go code for YANG path construction (path structs) will be generated.")
 	ocStructsOutputFile     = flag.String("output_file", "", "The file that the generated Go code for manipulating YANG data (schema/Go structs) should be written to. Specify \"-\" for stdout.")
@@ -64,6 +70,9 @@ var (
 	// Common flags used for GoStruct and PathStruct generation.
 	yangPaths                            = flag.String("path", "", "Comma separated list of paths to be recursively searched for included modules or submodules within the defined YANG modules.")
 	excludeModules                       = flag.String("exclude_modules", "", "Comma separated set of module names that should be excluded from code generation this can be used to ensure overlapping namespaces can be ignored.")
+	includeSubtrees                      = flag.String("include_subtrees", "", "Comma separated set of uncompressed schema paths, without module prefixes, of the subtrees for which code should be generated. When set, code is only generated for these subtrees and their ancestors.")
+	excludeSubtrees                      = flag.String("exclude_subtrees", "", "Comma separated set of uncompressed schema paths, without module prefixes, of the subtrees that should be excluded from code generation.")
+	deviationModules                     = flag.String("deviation_modules", "", "Comma separated set of YANG files containing deviations, such as those supplied by a vendor, that should be applied to the input modules. Code is not generated for the deviation modules themselves.")
 	packageName                          = flag.String("package_name", "ocstructs", "The name of the Go package that should be generated. For path struct generation, if split_pathstructs_by_module=true, this is the name of fake root package.")
 	ignoreCircDeps                       = flag.Bool("ignore_circdeps", false, "If set to true, circular dependencies between submodules are ignored.")
 	fakeRootName                         = flag.String("fakeroot_name", "", "The name of the fake root entity.")
@@ -90,6 +99,9 @@ var (
 	addAnnotations          = flag.Bool("annotations", false, "If set to true, metadata annotations are added within the generated structs.")
 	annotationPrefix        = flag.String("annotation_prefix", gogen.DefaultAnnotationPrefix, "String to be appended to each metadata field within the generated structs if annoations is set to true.")
 	addYangPresence         = flag.Bool("yangpresence", false, "If set to true, a tag will be added to the field of a generated Go struct to indicate when a YANG presence container is being used.")
+	addOCVersionTags        = flag.Bool("openconfig_version_tags", false, "If set to true, a tag will be added to the field of a generated Go struct to indicate the version of its OpenConfig module in which the corresponding YANG node was introduced, when specified by an oc-ext:openconfig-version statement on the node.")
+	addModulePrefixTags     = flag.Bool("module_prefix_tags", false, "If set to true, a tag will be added to the field of a generated Go struct containing the prefixes of the YANG modules that instantiate the corresponding YANG node, such that RFC7951 JSON members can be qualified by module prefixes rather than module names.")
+	addKeylessListIndexTags = flag.Bool("keyless_list_index_tags", false, "If set to true, a tag will be added to the field of a generated Go struct that represents a keyless list, naming a synthetic key that identifies the entries of the list by their position, such that the list can be output as gNMI notifications.")
 	generateAppend          = flag.Bool("generate_append", false, "If set to true, append methods are generated for YANG lists (Go maps) within the Go code.")
 	generateGetters         = flag.Bool("generate_getters", false, "If set to true, getter methdos that retrieve or create an element are generated for YANG container (Go struct pointer) or list (Go map) fields within the generated code.")
 	generateDelete          = flag.Bool("generate_delete", false, "If set to true, delete methods are generated for YANG lists (Go maps) within the Go code.")
@@ -100,17 +112,39 @@ var (
 	generatePopulateDefault = flag.Bool("generate_populate_defaults", false, "If set to true, a PopulateDefault method will be generated for all GoStructs which recursively populates default values.")
 	generateValidateFnName  = flag.String("validate_fn_name", "Validate", "The Name of the proxy function for the Validate functionality.")
 	generateOrderedMaps     = flag.Bool("generate_ordered_maps", true, "If set to true, ordered map structures satisfying the interface ygot.GoOrderedMap will be generated for `ordered-by user` lists instead of Go built-in maps.")
+	splitStructsByModule    = flag.Bool("split_structs_by_module", false, "If set to true, the generated GoStructs are output as one Go package per YANG module, along with a common package containing enumerated types, unions and the schema. The package containing the fake root is written to output_file, and the other packages to subdirectories of output_dir. base_import_path must also be set.")
+	commonUnionTypes        = flag.Bool("split_structs_common_unions", false, "If set to true, when split_structs_by_module=true, the types generated for unions are output into the common package, such that a single definition of each union type is referenced by all generated packages.")
+	generateStructRegistry  = flag.Bool("generate_struct_registry", false, "If set to true, a registry of constructors for the generated GoStructs, keyed by YANG module name and schema path, is included in the generated code.")
+	generateCapabilities    = flag.Bool("generate_capabilities", false, "If set to true, a descriptor of the options with which the GoStructs were generated is included in the generated code, and returned by a ΛCapabilities method of each GoStruct.")
+	generateDeltaStructs    = flag.Bool("generate_delta_structs", false, "If set to true, a delta type is generated for each GoStruct, which records which of the leaves of the GoStruct were explicitly set.")
+	binarySchema            = flag.Bool("binary_schema", false, "If set to true, the schema stored in the generated code is serialised using a compact binary representation rather than JSON, which reduces the time taken to load the schema at runtime.")
+	generatePackageDoc      = flag.Bool("generate_package_doc", false, "If set to true, the documentation of the generated GoStruct package, listing the YANG modules and their revisions, the generation options, and the schema paths covered, is written to a doc.go file in the directory of output_file, or in output_dir.")
+	generateUnionCtors      = flag.Bool("generate_union_constructors", false, "If set to true, functions that construct the values of each multi-type union from each of its subtypes, and from an arbitrary value with runtime checking, are generated.")
+	generateStringMethod    = flag.Bool("generate_string_method", false, "If set to true, a String method is generated for each GoStruct, which returns its compact RFC7951 JSON representation with the values of sensitive leaves redacted.")
+	generateDeepCopy        = flag.Bool("generate_deep_copy", false, "If set to true, a DeepCopy method is generated for each GoStruct, which copies it without using reflection, and is used by ygot.DeepCopy.")
+	generateDecimal64Type   = flag.Bool("generate_decimal64_type", false, "If set to true, leaves, leaf-lists and union subtypes with a YANG type of decimal64 are represented by the ygot.Decimal64 fixed-point type, which represents their values exactly, rather than float64.")
+	binaryType              = flag.String("binary_type", "", "A user-specified Go type, of the form <import path>.<type name>, used in place of the generated Binary type for leaves with a YANG type of binary. The type must implement ygot.BinaryValue.")
+	emptyType               = flag.String("empty_type", "", "A user-specified Go type, of the form <import path>.<type name>, used in place of the generated YANGEmpty type for leaves with a YANG type of empty. The type must implement ygot.EmptyValue.")
+	redactedSchemaPaths     = flag.String("redacted_schema_paths", "", "Comma separated list of YANG schema paths, without module prefixes, of leaves whose values are redacted by the String method generated when generate_string_method is set, in addition to those marked with the ietf-netconf-acm:default-deny-all extension.")
+	generateGetOrCreateAt   = flag.Bool("generate_get_or_create_at", false, "If set to true, a GetOrCreateAt method is generated for the fake root, which retrieves or creates the node at an arbitrary gNMI path in a single call. The schema must be included in the generated code.")
+	generateRPCs            = flag.Bool("generate_rpcs", false, "If set to true, structs are generated for the input and output statements of YANG RPCs and actions, such that they can be validated and marshalled using the ytypes RPC helpers.")
+	lazySchema              = flag.Bool("lazy_schema", false, "If set to true, the schema stored in the generated code is not loaded when the package is initialised, and each part of it is loaded when it is first required, which reduces the memory and start-up time of programs that use a small part of the schema.")
 
 	// Flags used for PathStruct generation only.
 	schemaStructPath        = flag.String("schema_struct_path", "", "The Go import path for the schema structs package. This should be specified if and only if schema structs are not being generated at the same time as path structs.")
 	generateWildcardPaths   = flag.Bool("generate_wildcard_paths", true, "Whether to generate methods for constructing wildcard paths.")
 	simplifyWildcardPaths   = flag.Bool("simplify_wildcard_paths", false, "Whether to omit the keys in the generated paths if all keys for a list node are wildcards.")
+	generateConfigState     = flag.Bool("generate_config_state_paths", true, "Whether to generate Config and State methods for leaf path structs, which return the path of the leaf within the config or state container of its parent.")
+	generateExtractMethods  = flag.Bool("generate_extract_methods", false, "Whether to generate Extract methods for leaf path structs, which decode a gNMI TypedValue into the Go type of the leaf.")
+	generateGoStructLinks   = flag.Bool("generate_gostruct_links", false, "Whether to generate ΛGoStruct methods for container and list path structs, which link each to the type of its GoStruct, such that it implements ygot.GoStructPath.")
+	pathRegistryOutputFile  = flag.String("path_registry_output_file", "", "If set, a JSON registry describing the path, YANG type, and defaults of each generated path struct is written to this file.")
 	listBuilderKeyThreshold = flag.Uint("list_builder_key_threshold", 0, "The threshold equal or over which the path structs' builder API is used for key population. 0 means infinity. This flag is only meaningful when wildcard paths are generated.")
 	pathStructSuffix        = flag.String("path_struct_suffix", "Path", "The suffix string appended to each generated path struct in order to differentiate their names from their corresponding schema struct names.")
 	splitByModule           = flag.Bool("split_pathstructs_by_module", false, "Whether to split path struct generation by module.")
-	trimPathPackagePrefix   = flag.String("trim_path_package_prefix", "", "Module prefix to trim from generated path struct package names (e.g. 'openconfig-'), when split_pathstructs_by_module=true.")
-	baseImportPath          = flag.String("base_import_path", "", "Base import path used to concatenate with module package relative paths for path struct imports when split_pathstructs_by_module=true.")
+	trimPathPackagePrefix   = flag.String("trim_path_package_prefix", "", "Module prefix to trim from generated path struct package names (e.g. 'openconfig-'), when split_pathstructs_by_module=true, or from GoStruct package names when split_structs_by_module=true.")
+	baseImportPath          = flag.String("base_import_path", "", "Base import path used to concatenate with module package relative paths for path struct imports when split_pathstructs_by_module=true, or for GoStruct imports when split_structs_by_module=true.")
 	packageSuffix           = flag.String("path_struct_package_suffix", "path", "Suffix to append to generated Go package names, when split_pathstructs_by_module=true.")
+	compactPathStructs      = flag.Bool("compact_pathstructs", false, "Whether to generate all path structs into the single file path_structs_output_file with concise documentation, for simpler vendoring of small schemas. Cannot be used with split_pathstructs_by_module or output_dir.")
 )
 
 // writeGoCodeSingleFile takes a gogen.GeneratedCode struct and writes the Go code
@@ -143,6 +177,18 @@ func writeGoCodeSingleFile(w io.Writer, goCode *gogen.GeneratedCode) error {
 		fmt.Fprintln(w, goCode.EnumTypeMap)
 	}
 
+	if len(goCode.StructRegistry) > 0 {
+		fmt.Fprintln(w, goCode.StructRegistry)
+	}
+
+	if len(goCode.Capabilities) > 0 {
+		fmt.Fprintln(w, goCode.Capabilities)
+	}
+
+	if len(goCode.MetadataAnnotations) > 0 {
+		fmt.Fprintln(w, goCode.MetadataAnnotations)
+	}
+
 	return nil
 }
 
@@ -167,7 +213,7 @@ func splitCodeByFileN(goCode *gogen.GeneratedCode, fileN int) (map[string]string
 	}
 
 	out := map[string]string{
-		schemaFn: goCode.JSONSchemaCode,
+		schemaFn: goCode.JSONSchemaCode + goCode.StructRegistry + goCode.Capabilities,
 		enumFn:   strings.Join(goCode.Enums, "\n"),
 	}
 
@@ -215,6 +261,10 @@ func splitCodeByFileN(goCode *gogen.GeneratedCode, fileN int) (map[string]string
 		code.WriteString("\n")
 	}
 	code.WriteString(goCode.EnumTypeMap)
+	if goCode.MetadataAnnotations != "" {
+		code.WriteString("\n")
+		code.WriteString(goCode.MetadataAnnotations)
+	}
 
 	out[enumMapFn] = code.String()
 	out[interfaceFn] = interfaceCode.String()
@@ -251,6 +301,84 @@ func writeFiles(dir string, out map[string]string) error {
 	return nil
 }
 
+const (
+	// configFileFlag is the name of the flag that specifies the config
+	// file, which cannot itself be set within a config file.
+	configFileFlag = "config_file"
+	// configModulesKey is the key of a config file that specifies the
+	// YANG modules for which code is generated.
+	configModulesKey = "modules"
+)
+
+// applyConfigFile reads the YAML or JSON config file fn, the format of which
+// is determined by its extension, and sets each flag of fs that is named by a
+// key of the config file to the corresponding value, unless the flag has
+// already been set, such that flags specified on the command line take
+// precedence. Lists are supplied to flags as comma-separated values. The
+// config file may also contain the key "modules", whose value is the list of
+// YANG modules for which code is generated, which is returned.
+func applyConfigFile(fs *flag.FlagSet, fn string) ([]string, error) {
+	v := viper.New()
+	v.SetConfigFile(fn)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("cannot read config file %s: %v", fn, err)
+	}
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	settings := v.AllSettings()
+	var keys []string
+	for k := range settings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var modules []string
+	for _, k := range keys {
+		if k == configModulesKey {
+			modules = v.GetStringSlice(k)
+			continue
+		}
+		if k == configFileFlag || fs.Lookup(k) == nil {
+			return nil, fmt.Errorf("config file %s: unknown option %q", fn, k)
+		}
+		if set[k] {
+			continue
+		}
+		val, err := configFlagValue(settings[k])
+		if err != nil {
+			return nil, fmt.Errorf("config file %s: invalid value for option %q: %v", fn, k, err)
+		}
+		if err := fs.Set(k, val); err != nil {
+			return nil, fmt.Errorf("config file %s: invalid value for option %q: %v", fn, k, err)
+		}
+	}
+	return modules, nil
+}
+
+// configFlagValue returns the string form of the value v of a config file
+// option, as it would be supplied on the command line.
+func configFlagValue(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		var vals []string
+		for _, e := range v {
+			s, err := configFlagValue(e)
+			if err != nil {
+				return "", err
+			}
+			vals = append(vals, s)
+		}
+		return strings.Join(vals, ","), nil
+	case map[string]interface{}:
+		return "", fmt.Errorf("nested options are not supported, got %v", v)
+	case nil:
+		return "", nil
+	}
+	return fmt.Sprint(v), nil
+}
+
 // processFlags does some minimal processing of flags where otherwise
 // inconvenient before they're passed to the code generators.
 func processFlags() {
@@ -266,10 +394,19 @@ func processFlags() {
 // to the specified file.
 func main() {
 	flag.Parse()
-	processFlags()
 	// Extract the set of modules that code is to be generated for,
 	// throwing an error if the set is empty.
 	generateModules := flag.Args()
+	if *configFile != "" {
+		modules, err := applyConfigFile(flag.CommandLine, *configFile)
+		if err != nil {
+			log.Exitf("Error: %v", err)
+		}
+		if len(generateModules) == 0 {
+			generateModules = modules
+		}
+	}
+	processFlags()
 	if len(generateModules) == 0 {
 		log.Exitln("Error: no input modules specified")
 	}
@@ -313,15 +450,43 @@ func main() {
 		}
 	}
 
+	// Determine the subtrees of the schema that the user has requested to
+	// be included in or excluded from code generation.
+	var subtreesIncluded, subtreesExcluded []string
+	if len(*includeSubtrees) > 0 {
+		subtreesIncluded = strings.Split(*includeSubtrees, ",")
+	}
+	if len(*excludeSubtrees) > 0 {
+		subtreesExcluded = strings.Split(*excludeSubtrees, ",")
+	}
+
+	// Determine the deviation modules that should be applied to the input
+	// modules.
+	var deviationFiles []string
+	if len(*deviationModules) > 0 {
+		deviationFiles = strings.Split(*deviationModules, ",")
+	}
+
+	var redactedPaths []string
+	if len(*redactedSchemaPaths) > 0 {
+		redactedPaths = strings.Split(*redactedSchemaPaths, ",")
+	}
+
 	if *generateGoStructs {
 		generateGoStructsSingleFile := *ocStructsOutputFile != ""
 		generateGoStructsMultipleFiles := *outputDir != ""
-		if generateGoStructsSingleFile && generateGoStructsMultipleFiles {
+		if generateGoStructsSingleFile && generateGoStructsMultipleFiles && !*splitStructsByModule {
 			log.Exitf("Error: cannot specify both output_file (%s) and output_dir (%s)", *ocStructsOutputFile, *outputDir)
 		}
 		if !generateGoStructsSingleFile && !generateGoStructsMultipleFiles {
 			log.Exitf("Error: Go struct generation requires a specified output file or output directory.")
 		}
+		if *splitStructsByModule && (!generateGoStructsSingleFile || !generateGoStructsMultipleFiles || *baseImportPath == "") {
+			log.Exitf("Error: when splitting GoStructs by module, output_file, output_dir, and base_import_path need to be set.")
+		}
+		if *generatePackageDoc && *ocStructsOutputFile == "-" {
+			log.Exitf("Error: package documentation cannot be generated when GoStructs are written to stdout.")
+		}
 
 		compressBehaviour, err := genutil.TranslateToCompressBehaviour(*compressPaths, *excludeState, *preferOperationalState)
 		if err != nil {
@@ -335,6 +500,10 @@ func main() {
 				ParseOptions: ygen.ParseOpts{
 					IgnoreUnsupportedStatements: *ignoreUnsupportedStatements,
 					ExcludeModules:              modsExcluded,
+					IncludeSubtrees:             subtreesIncluded,
+					ExcludeSubtrees:             subtreesExcluded,
+					DeviationModules:            deviationFiles,
+					GenerateRPCs:                *generateRPCs,
 					YANGParseOptions: yang.Options{
 						IgnoreSubmoduleCircularDependencies: *ignoreCircDeps,
 						DeviateOptions: yang.DeviateOptions{
@@ -364,6 +533,9 @@ func main() {
 				AddAnnotationFields:                 *addAnnotations,
 				AnnotationPrefix:                    *annotationPrefix,
 				AddYangPresence:                     *addYangPresence,
+				AddOpenConfigVersionTags:            *addOCVersionTags,
+				AddModulePrefixTags:                 *addModulePrefixTags,
+				AddKeylessListIndexTags:             *addKeylessListIndexTags,
 				GenerateGetters:                     *generateGetters,
 				GenerateDeleteMethod:                *generateDelete,
 				GenerateAppendMethod:                *generateAppend,
@@ -376,6 +548,25 @@ func main() {
 				AppendEnumSuffixForSimpleUnionEnums: *appendEnumSuffixForSimpleUnionEnums,
 				IgnoreShadowSchemaPaths:             *ignoreShadowSchemaPaths,
 				GenerateOrderedListsAsUnorderedMaps: !*generateOrderedMaps,
+				GenerateStructRegistry:              *generateStructRegistry,
+				GenerateCapabilities:                *generateCapabilities,
+				GenerateDeltaStructs:                *generateDeltaStructs,
+				BinarySchema:                        *binarySchema,
+				LazySchema:                          *lazySchema,
+				GeneratePackageDoc:                  *generatePackageDoc,
+				GenerateUnionConstructors:           *generateUnionCtors,
+				GenerateStringMethod:                *generateStringMethod,
+				RedactedSchemaPaths:                 redactedPaths,
+				GenerateDeepCopy:                    *generateDeepCopy,
+				GenerateDecimal64Type:               *generateDecimal64Type,
+				BinaryType:                          *binaryType,
+				EmptyType:                           *emptyType,
+				GenerateGetOrCreateAt:               *generateGetOrCreateAt,
+				CapabilitiesWildcardPaths:           *generatePathStructs && *generateWildcardPaths,
+				SplitByModule:                       *splitStructsByModule,
+				CommonUnionTypes:                    *commonUnionTypes,
+				TrimPackagePrefix:                   *trimPathPackagePrefix,
+				BaseImportPath:                      *baseImportPath,
 			},
 		)
 
@@ -385,6 +576,23 @@ func main() {
 		}
 
 		switch {
+		case *splitStructsByModule:
+			for pkgName, code := range generatedGoCode.Packages {
+				path := *ocStructsOutputFile
+				if pkgName != *packageName {
+					path = filepath.Join(*outputDir, pkgName, fmt.Sprintf("%s.go", pkgName))
+					if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+						log.Exitf("failed to create directory for package %q: %v", pkgName, err)
+					}
+				}
+				out := map[string]string{filepath.Base(path): code}
+				if pkgName == *packageName {
+					out[docFn] = generatedGoCode.PackageDoc
+				}
+				if err := writeFiles(filepath.Dir(path), out); err != nil {
+					log.Exitf("Error while writing GoStruct package %s: %v", pkgName, err)
+				}
+			}
 		case generateGoStructsSingleFile:
 			var outfh *os.File
 			switch *ocStructsOutputFile {
@@ -402,12 +610,16 @@ func main() {
 			if err := writeGoCodeSingleFile(outfh, generatedGoCode); err != nil {
 				log.Exitf("ERROR writing GoStruct Code to single file: %v\n", err)
 			}
+			if err := writeFiles(filepath.Dir(*ocStructsOutputFile), map[string]string{docFn: generatedGoCode.PackageDoc}); err != nil {
+				log.Exitf("Error while writing package documentation: %v", err)
+			}
 		case generateGoStructsMultipleFiles:
 			// Write the Go code to a series of output files.
 			out, err := splitCodeByFileN(generatedGoCode, *structsFileN)
 			if err != nil {
 				log.Exitf("ERROR writing split GoStruct Code: %v\n", err)
 			}
+			out[docFn] = generatedGoCode.PackageDoc
 			if err := writeFiles(*outputDir, out); err != nil {
 				log.Exitf("Error while writing schema struct files: %v", err)
 			}
@@ -433,6 +645,9 @@ func main() {
 	if *splitByModule && (!generatePathStructsSingleFile || !generatePathStructsMultipleFiles) {
 		log.Exitf("Error: when splitting path structs by module, both output_dir and path_structs_output_file need to be set.")
 	}
+	if *compactPathStructs && (*splitByModule || !generatePathStructsSingleFile || generatePathStructsMultipleFiles) {
+		log.Exitf("Error: compact path structs require path_structs_output_file, and cannot be used with split_pathstructs_by_module or output_dir.")
+	}
 
 	// Perform the code generation.
 	pcg := &ypathgen.GenConfig{
@@ -451,6 +666,9 @@ func main() {
 		FakeRootName:                         *fakeRootName,
 		PathStructSuffix:                     *pathStructSuffix,
 		ExcludeModules:                       modsExcluded,
+		IncludeSubtrees:                      subtreesIncluded,
+		ExcludeSubtrees:                      subtreesExcluded,
+		DeviationModules:                     deviationFiles,
 		IgnoreUnsupportedStatements:          *ignoreUnsupportedStatements,
 		YANGParseOptions: yang.Options{
 			IgnoreSubmoduleCircularDependencies: *ignoreCircDeps,
@@ -459,10 +677,15 @@ func main() {
 		ListBuilderKeyThreshold: *listBuilderKeyThreshold,
 		GenerateWildcardPaths:   *generateWildcardPaths,
 		SimplifyWildcardPaths:   *simplifyWildcardPaths,
+		GenerateExtractMethods:  *generateExtractMethods,
+		GenerateGoStructLinks:   *generateGoStructLinks,
+		SkipConfigStatePaths:    !*generateConfigState,
+		GeneratePathRegistry:    *pathRegistryOutputFile != "",
 		TrimPackagePrefix:       *trimPathPackagePrefix,
 		SplitByModule:           *splitByModule,
 		BaseImportPath:          *baseImportPath,
 		PackageSuffix:           *packageSuffix,
+		CompactOutput:           *compactPathStructs,
 	}
 
 	pathCode, _, errs := pcg.GeneratePathCode(generateModules, includePaths)
@@ -470,6 +693,20 @@ func main() {
 		log.Exitf("ERROR Generating PathStruct Code: %s\n", errs)
 	}
 
+	if *pathRegistryOutputFile != "" {
+		var entries []*ypathgen.PathRegistryEntry
+		for _, code := range pathCode {
+			entries = append(entries, code.PathRegistry...)
+		}
+		registry, err := ypathgen.MarshalPathRegistry(entries)
+		if err != nil {
+			log.Exitf("Error while marshalling path registry: %v", err)
+		}
+		if err := os.WriteFile(*pathRegistryOutputFile, registry, 0644); err != nil {
+			log.Exitf("Error while writing path registry: %v", err)
+		}
+	}
+
 	switch {
 	case *splitByModule:
 		for packageName, code := range pathCode {
@@ -15,7 +15,10 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -384,3 +387,109 @@ ChildConstructor
 		})
 	}
 }
+
+func TestApplyConfigFile(t *testing.T) {
+	tests := []struct {
+		desc          string
+		inFileName    string
+		inConfig      string
+		inArgs        []string
+		wantModules   []string
+		wantFlags     map[string]string
+		wantErrSubstr string
+	}{{
+		desc:       "YAML config",
+		inFileName: "config.yaml",
+		inConfig: `
+package_name: exampleoc
+compress_paths: true
+list_builder_key_threshold: 3
+exclude_modules:
+  - ietf-interfaces
+  - openconfig-foo
+modules:
+  - a.yang
+  - b.yang
+`,
+		wantModules: []string{"a.yang", "b.yang"},
+		wantFlags: map[string]string{
+			"package_name":               "exampleoc",
+			"compress_paths":             "true",
+			"list_builder_key_threshold": "3",
+			"exclude_modules":            "ietf-interfaces,openconfig-foo",
+		},
+	}, {
+		desc:        "JSON config",
+		inFileName:  "config.json",
+		inConfig:    `{"package_name": "exampleoc", "compress_paths": false, "modules": ["a.yang"]}`,
+		wantModules: []string{"a.yang"},
+		wantFlags: map[string]string{
+			"package_name":   "exampleoc",
+			"compress_paths": "false",
+		},
+	}, {
+		desc:       "command line takes precedence",
+		inFileName: "config.yaml",
+		inConfig: `
+package_name: exampleoc
+compress_paths: true
+`,
+		inArgs: []string{"-package_name=override"},
+		wantFlags: map[string]string{
+			"package_name":   "override",
+			"compress_paths": "true",
+		},
+	}, {
+		desc:          "unknown option",
+		inFileName:    "config.yaml",
+		inConfig:      "pakage_name: exampleoc\n",
+		wantErrSubstr: `unknown option "pakage_name"`,
+	}, {
+		desc:          "config file within config file",
+		inFileName:    "config.yaml",
+		inConfig:      "config_file: other.yaml\n",
+		wantErrSubstr: `unknown option "config_file"`,
+	}, {
+		desc:          "invalid value",
+		inFileName:    "config.yaml",
+		inConfig:      "compress_paths: sometimes\n",
+		wantErrSubstr: `invalid value for option "compress_paths"`,
+	}, {
+		desc:          "nested option",
+		inFileName:    "config.yaml",
+		inConfig:      "package_name:\n  name: exampleoc\n",
+		wantErrSubstr: "nested options are not supported",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			fs := flag.NewFlagSet("generator", flag.ContinueOnError)
+			fs.String(configFileFlag, "", "")
+			fs.String("package_name", "ocstructs", "")
+			fs.Bool("compress_paths", false, "")
+			fs.Int("list_builder_key_threshold", 0, "")
+			fs.String("exclude_modules", "", "")
+			if err := fs.Parse(tt.inArgs); err != nil {
+				t.Fatalf("cannot parse flags %v: %v", tt.inArgs, err)
+			}
+
+			fn := filepath.Join(t.TempDir(), tt.inFileName)
+			if err := os.WriteFile(fn, []byte(tt.inConfig), 0644); err != nil {
+				t.Fatalf("cannot write config file: %v", err)
+			}
+
+			gotModules, err := applyConfigFile(fs, fn)
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("applyConfigFile: did not get expected error, %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantModules, gotModules); diff != "" {
+				t.Errorf("applyConfigFile: did not get expected modules (-want, +got):\n%s", diff)
+			}
+			for name, want := range tt.wantFlags {
+				if got := fs.Lookup(name).Value.String(); got != want {
+					t.Errorf("applyConfigFile: flag %s: got %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
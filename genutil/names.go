@@ -17,6 +17,7 @@ package genutil
 import (
 	"fmt"
 	"runtime"
+	"runtime/debug"
 	"strings"
 
 	"github.com/openconfig/goyang/pkg/yang"
@@ -35,6 +36,34 @@ func CallerName() string {
 	return currentCodeFile
 }
 
+// ygotModulePath is the path of the ygot Go module.
+const ygotModulePath = "github.com/openconfig/ygot"
+
+// YgotVersion returns the version of the ygot module with which the Go binary
+// that is currently running was built, or "(devel)" if it cannot be
+// determined.
+func YgotVersion() string {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	if bi.Main.Path == ygotModulePath && bi.Main.Version != "" {
+		return bi.Main.Version
+	}
+	for _, d := range bi.Deps {
+		if d.Path != ygotModulePath {
+			continue
+		}
+		if d.Replace != nil {
+			d = d.Replace
+		}
+		if d.Version != "" {
+			return d.Version
+		}
+	}
+	return "(devel)"
+}
+
 // definingModule returns the name of the module that defined the yang.Node
 // supplied. If node is within a submodule, the parent module name is returned.
 func definingModule(node yang.Node) yang.Node {
@@ -0,0 +1,79 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/openconfig/ygot/gnmidiff"
+	"github.com/openconfig/ygot/gnmidiff/gnmiparse"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newNotifsDiffCmd() *cobra.Command {
+	notifsdiff := &cobra.Command{
+		Use:   "notifs",
+		RunE:  notifsDiff,
+		Short: "Diffs the leaves of two sets of Notifications (e.g. GetResponses from two collection runs).",
+		Args:  cobra.MinimumNArgs(2),
+	}
+
+	addFormatFlags(notifsdiff)
+	notifsdiff.Flags().StringSlice("tolerance", nil, `Numeric tolerance of the form <path>=<value> for leaves at or below path, where value is absolute, or relative when it ends with "%" (e.g. /interfaces/interface[name=*]/state/counters=5%). May be repeated.`)
+
+	return notifsdiff
+}
+
+func notifsDiff(cmd *cobra.Command, args []string) error {
+	format, err := diffFormat(os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	var tolerances []*gnmidiff.Tolerance
+	for _, s := range viper.GetStringSlice("tolerance") {
+		t, err := gnmidiff.ParseTolerance(s)
+		if err != nil {
+			return err
+		}
+		tolerances = append(tolerances, t)
+	}
+
+	notifsA, err := gnmiparse.NotifsFromFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	notifsB, err := gnmiparse.NotifsFromFile(args[1])
+	if err != nil {
+		return err
+	}
+
+	diff, err := gnmidiff.DiffNotifications(notifsA, notifsB, nil, tolerances...)
+	if err != nil {
+		return err
+	}
+	if viper.GetBool("html") {
+		report, err := diff.HTML(format)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(os.Stdout, report)
+		return nil
+	}
+	return writeDiff(os.Stderr, diff.Format(format))
+}
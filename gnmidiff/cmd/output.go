@@ -0,0 +1,99 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/openconfig/ygot/gnmidiff"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// defaultPager is the pager used when the PAGER environment variable is not
+// set.
+const defaultPager = "less -R"
+
+// addFormatFlags adds the flags controlling the text output of a diff to cmd.
+func addFormatFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("full", false, "Whether diff shows common values.")
+	cmd.Flags().Bool("html", false, "Whether diff is written to stdout as a standalone HTML report.")
+	cmd.Flags().Bool("unified", true, "Whether diff is shown in the style of a unified diff, grouped by top-level container.")
+	cmd.Flags().String("color", "auto", `When to color a unified diff: "always", "never", or "auto" to color it when it is written to a terminal.`)
+	cmd.Flags().Lookup("color").NoOptDefVal = "always"
+	cmd.Flags().Int("context", 0, "Number of common values shown around each difference within a list entry of a unified diff.")
+	cmd.Flags().Bool("pager", true, fmt.Sprintf("Whether diff written to a terminal is shown using $PAGER, or %q if it is unset.", defaultPager))
+}
+
+// diffFormat returns the format of the diff specified by the flags added by
+// addFormatFlags, where the diff is to be written to out.
+func diffFormat(out *os.File) (gnmidiff.Format, error) {
+	f := gnmidiff.Format{
+		Full:    viper.GetBool("full"),
+		Unified: viper.GetBool("unified"),
+		Context: viper.GetInt("context"),
+	}
+	if f.Context < 0 {
+		return gnmidiff.Format{}, fmt.Errorf("invalid --context %d, must not be negative", f.Context)
+	}
+	switch c := viper.GetString("color"); c {
+	case "always":
+		f.Color = true
+	case "never":
+	case "auto":
+		f.Color = isTerminal(out)
+	default:
+		return gnmidiff.Format{}, fmt.Errorf(`invalid --color %q, must be one of "always", "never" or "auto"`, c)
+	}
+	return f, nil
+}
+
+// isTerminal reports whether f is a terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// writeDiff writes the text of a diff to out. Where out is a terminal and
+// paging is enabled, the text is written to the pager, which writes it to out.
+// If the pager cannot be started, the text is written to out directly.
+func writeDiff(out *os.File, text string) error {
+	if !viper.GetBool("pager") || !isTerminal(out) {
+		_, err := io.WriteString(out, text)
+		return err
+	}
+
+	pager := os.Getenv("PAGER")
+	if strings.TrimSpace(pager) == "" {
+		pager = defaultPager
+	}
+	args := strings.Fields(pager)
+	p := exec.Command(args[0], args[1:]...)
+	p.Stdin = strings.NewReader(text)
+	p.Stdout = out
+	p.Stderr = os.Stderr
+	if err := p.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return fmt.Errorf("pager %q failed: %v", pager, err)
+		}
+		_, err := io.WriteString(out, text)
+		return err
+	}
+	return nil
+}
@@ -41,8 +41,10 @@ func RootCmd() *cobra.Command {
 		return nil
 	}
 
+	rootCmd.AddCommand(newNotifsDiffCmd())
 	rootCmd.AddCommand(newSetRequestDiffCmd())
 	rootCmd.AddCommand(newSetToNotifsDiffCmd())
+	rootCmd.AddCommand(newSnapshotToSetDiffCmd())
 
 	return rootCmd
 }
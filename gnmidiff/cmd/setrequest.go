@@ -32,14 +32,15 @@ func newSetRequestDiffCmd() *cobra.Command {
 		Args:  cobra.MinimumNArgs(2),
 	}
 
-	setdiff.Flags().Bool("full", false, "Whether diff shows common values.")
+	addFormatFlags(setdiff)
 
 	return setdiff
 }
 
 func setRequestDiff(cmd *cobra.Command, args []string) error {
-	format := gnmidiff.Format{
-		Full: viper.GetBool("full"),
+	format, err := diffFormat(os.Stderr)
+	if err != nil {
+		return err
 	}
 
 	srA, err := gnmiparse.SetRequestFromFile(args[0])
@@ -56,6 +57,13 @@ func setRequestDiff(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	fmt.Fprintf(os.Stderr, diff.Format(format))
-	return nil
+	if viper.GetBool("html") {
+		report, err := diff.HTML(format)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(os.Stdout, report)
+		return nil
+	}
+	return writeDiff(os.Stderr, diff.Format(format))
 }
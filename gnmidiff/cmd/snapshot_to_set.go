@@ -0,0 +1,69 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/openconfig/ygot/gnmidiff"
+	"github.com/openconfig/ygot/gnmidiff/gnmiparse"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newSnapshotToSetDiffCmd() *cobra.Command {
+	setdiff := &cobra.Command{
+		Use:   "snapshot-to-set",
+		RunE:  snapshotToSetDiff,
+		Short: "Diffs an RFC7951 JSON snapshot of the device and the SetRequest intent, showing what the SetRequest would change.",
+		Args:  cobra.MinimumNArgs(2),
+	}
+
+	addFormatFlags(setdiff)
+
+	return setdiff
+}
+
+func snapshotToSetDiff(cmd *cobra.Command, args []string) error {
+	format, err := diffFormat(os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	setreq, err := gnmiparse.SetRequestFromFile(args[1])
+	if err != nil {
+		return err
+	}
+
+	diff, err := gnmidiff.DiffSnapshotToSetRequest(snapshot, setreq, nil)
+	if err != nil {
+		return err
+	}
+	if viper.GetBool("html") {
+		report, err := diff.HTML(format)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(os.Stdout, report)
+		return nil
+	}
+	return writeDiff(os.Stderr, diff.Format(format))
+}
@@ -75,6 +75,17 @@ type UpdateDiff struct {
 type Format struct {
 	// Full indicates that common values are also output.
 	Full bool
+	// Unified indicates that the diff is output in the style of a unified
+	// diff, in which the differences are grouped by their top-level
+	// container, and each value in A and B is output on its own line.
+	Unified bool
+	// Color indicates that the lines of a unified diff are coloured using
+	// ANSI escape sequences, e.g., for output to a terminal.
+	Color bool
+	// Context is the number of common values surrounding each difference
+	// within a list entry that are output within a unified diff. It has no
+	// effect when Full is set, since all common values are output.
+	Context int
 	// TODO: Implement IncludeList and ExcludeList.
 	// IncludeList is a list of paths that will be included in the output.
 	// wildcards are allowed.
@@ -127,6 +138,9 @@ func (diff StructuredDiff) Format(f Format) string {
 	if f.deleteDesc == "" {
 		f.deleteTitle = "deleted only in %s"
 	}
+	if f.Unified {
+		return diff.unifiedFormat(f)
+	}
 	b.WriteString(fmt.Sprintf("%s(-%s, +%s):\n", f.title, f.aName, f.bName))
 
 	deleteDiff := diff.DeleteDiff.format(f)
@@ -0,0 +1,358 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmidiff
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// HTML outputs the SetRequestIntentDiff as a standalone HTML report.
+//
+// NOTE: Do not depend on the output of this being stable.
+func (diff SetRequestIntentDiff) HTML(f Format) (string, error) {
+	f.title = "SetRequestIntentDiff"
+	f.aName = "A"
+	f.bName = "B"
+	f.deleteTitle = "deletes/replaces"
+	f.deleteDesc = "deleted or replaced"
+	return StructuredDiff(diff).HTML(f)
+}
+
+// HTML outputs the SetToNotifsDiff as a standalone HTML report.
+//
+// NOTE: Do not depend on the output of this being stable.
+func (diff SetToNotifsDiff) HTML(f Format) (string, error) {
+	f.title = "SetToNotifsDiff"
+	f.aName = "want/SetRequest"
+	f.bName = "got/Notifications"
+	return StructuredDiff{UpdateDiff: UpdateDiff(diff)}.HTML(f)
+}
+
+// HTML outputs the SnapshotToSetDiff as a standalone HTML report.
+//
+// NOTE: Do not depend on the output of this being stable.
+func (diff SnapshotToSetDiff) HTML(f Format) (string, error) {
+	f.title = "SnapshotToSetDiff"
+	f.aName = "snapshot"
+	f.bName = "SetRequest"
+	return StructuredDiff{UpdateDiff: UpdateDiff(diff)}.HTML(f)
+}
+
+// HTML outputs the NotifsDiff as a standalone HTML report.
+//
+// NOTE: Do not depend on the output of this being stable.
+func (diff NotifsDiff) HTML(f Format) (string, error) {
+	f.title = "NotifsDiff"
+	f.aName = "A"
+	f.bName = "B"
+	return StructuredDiff{UpdateDiff: UpdateDiff(diff)}.HTML(f)
+}
+
+// HTML outputs the StructuredDiff as a standalone HTML report, which can be
+// viewed in a web browser without any external resources. Differences are
+// grouped by their top-level container, and displayed as a tree of
+// collapsible subtrees, with additions, removals and changes coloured
+// differently.
+//
+// NOTE: Do not depend on the output of this being stable.
+func (diff StructuredDiff) HTML(f Format) (string, error) {
+	if f.title == "" {
+		f.title = "StructuredDiff"
+	}
+	if f.aName == "" {
+		f.aName = "A"
+	}
+	if f.bName == "" {
+		f.bName = "B"
+	}
+	if f.deleteDesc == "" {
+		f.deleteDesc = "deleted"
+	}
+
+	root := &htmlNode{}
+	add := func(path string, l *htmlLeaf) error {
+		n, err := root.node(path)
+		if err != nil {
+			return err
+		}
+		n.Leaves = append(n.Leaves, l)
+		return nil
+	}
+
+	deletes := func(paths map[string]struct{}, kind, desc string) error {
+		for p := range paths {
+			if err := add(p, &htmlLeaf{Kind: kind, Desc: desc}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if f.Full {
+		if err := deletes(diff.CommonDeletes, htmlCommon, f.deleteDesc); err != nil {
+			return "", err
+		}
+	}
+	if err := deletes(diff.MissingDeletes, htmlRemoved, fmt.Sprintf("%s only in %s", f.deleteDesc, f.aName)); err != nil {
+		return "", err
+	}
+	if err := deletes(diff.ExtraDeletes, htmlAdded, fmt.Sprintf("%s only in %s", f.deleteDesc, f.bName)); err != nil {
+		return "", err
+	}
+
+	updates := func(paths map[string]interface{}, kind string) error {
+		for p, v := range paths {
+			if err := add(p, &htmlLeaf{Kind: kind, Value: fmt.Sprint(formatJSONValue(v))}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if f.Full {
+		if err := updates(diff.CommonUpdates, htmlCommon); err != nil {
+			return "", err
+		}
+	}
+	if err := updates(diff.MissingUpdates, htmlRemoved); err != nil {
+		return "", err
+	}
+	if err := updates(diff.ExtraUpdates, htmlAdded); err != nil {
+		return "", err
+	}
+	for p, m := range diff.MismatchedUpdates {
+		if err := add(p, &htmlLeaf{
+			Kind:  htmlChanged,
+			Value: fmt.Sprint(formatJSONValue(m.A)),
+			New:   fmt.Sprint(formatJSONValue(m.B)),
+		}); err != nil {
+			return "", err
+		}
+	}
+	root.finalise()
+	for _, ch := range root.Children {
+		ch.Top = true
+	}
+
+	var b strings.Builder
+	if err := htmlReportTemplate.Execute(&b, struct {
+		Title string
+		AName string
+		BName string
+		Root  *htmlNode
+	}{
+		Title: f.title,
+		AName: f.aName,
+		BName: f.bName,
+		Root:  root,
+	}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+const (
+	// htmlAdded is the kind of a difference that is present only in B.
+	htmlAdded = "added"
+	// htmlRemoved is the kind of a difference that is present only in A.
+	htmlRemoved = "removed"
+	// htmlChanged is the kind of a difference whose value differs between
+	// A and B.
+	htmlChanged = "changed"
+	// htmlCommon is the kind of a value that is common to A and B.
+	htmlCommon = "common"
+)
+
+// htmlLeaf is a difference at a particular path within the HTML report.
+type htmlLeaf struct {
+	// Kind is the kind of the difference, which is used as its CSS class.
+	Kind string
+	// Value is the formatted value of the update in A, or in B if the
+	// update is only present in B.
+	Value string
+	// New is the formatted value of the update in B for changed values.
+	New string
+	// Desc describes a difference that has no value, such as a delete.
+	Desc string
+}
+
+// htmlNode is a node of the tree of paths within the HTML report.
+type htmlNode struct {
+	// Name is the string representation of the path element of the node.
+	Name string
+	// Leaves are the differences at the path of the node.
+	Leaves []*htmlLeaf
+	// Children are the child nodes, sorted by name once the tree has been
+	// finalised.
+	Children []*htmlNode
+	// Counts is the number of differences of each kind within the subtree
+	// rooted at the node, computed when the tree is finalised.
+	Counts map[string]int
+	// Top indicates that the node is a top-level container.
+	Top bool
+	// Items are the children of the node that have no children, which are
+	// rendered as items within its list, computed when the tree is
+	// finalised.
+	Items []*htmlNode
+	// Subtrees are the children of the node that have children, which are
+	// rendered as collapsible subtrees, computed when the tree is
+	// finalised.
+	Subtrees []*htmlNode
+
+	children map[string]*htmlNode
+}
+
+// node returns the descendant of n at the supplied path, which is the string
+// representation of a gNMI path, creating it if it does not exist.
+func (n *htmlNode) node(path string) (*htmlNode, error) {
+	p, err := ygot.StringToStructuredPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path %q: %v", path, err)
+	}
+	for _, e := range p.Elem {
+		name, err := ygot.PathToString(&gpb.Path{Elem: []*gpb.PathElem{e}})
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %q: %v", path, err)
+		}
+		name = strings.TrimPrefix(name, "/")
+		ch, ok := n.children[name]
+		if !ok {
+			ch = &htmlNode{Name: name}
+			if n.children == nil {
+				n.children = map[string]*htmlNode{}
+			}
+			n.children[name] = ch
+			n.Children = append(n.Children, ch)
+		}
+		n = ch
+	}
+	return n, nil
+}
+
+// finalise sorts the leaves and children of the subtree rooted at n, and
+// computes the number of differences of each kind within it.
+func (n *htmlNode) finalise() {
+	n.Counts = map[string]int{}
+	for _, l := range n.Leaves {
+		n.Counts[l.Kind]++
+	}
+	sort.Slice(n.Leaves, func(i, j int) bool {
+		li, lj := n.Leaves[i], n.Leaves[j]
+		switch {
+		case li.Kind != lj.Kind:
+			return li.Kind < lj.Kind
+		case li.Desc != lj.Desc:
+			return li.Desc < lj.Desc
+		default:
+			return li.Value < lj.Value
+		}
+	})
+	sort.Slice(n.Children, func(i, j int) bool { return n.Children[i].Name < n.Children[j].Name })
+	for _, ch := range n.Children {
+		ch.finalise()
+		for k, c := range ch.Counts {
+			n.Counts[k] += c
+		}
+		if len(ch.Children) == 0 {
+			n.Items = append(n.Items, ch)
+		} else {
+			n.Subtrees = append(n.Subtrees, ch)
+		}
+	}
+}
+
+var (
+	// htmlReportTemplate is the template for the HTML report of a
+	// StructuredDiff. Each top-level container is a collapsible section,
+	// within which each subtree is collapsible. Nodes without children are
+	// rendered as items within the list of their parent.
+	htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+		// leafNode returns a node with the supplied name and leaves, such
+		// that the differences at the path of a subtree can be rendered
+		// in the same way as those of its leaf children.
+		"leafNode": func(name string, leaves []*htmlLeaf) *htmlNode {
+			return &htmlNode{Name: name, Leaves: leaves}
+		},
+	}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+h1 { font-size: 1.4em; }
+details { margin-left: 1.5em; }
+details.top { margin-left: 0; border: 1px solid #ccc; border-radius: 4px; padding: 0.3em 0.6em; margin-bottom: 0.5em; }
+summary { cursor: pointer; font-family: monospace; }
+ul { list-style: none; margin: 0.2em 0 0.2em 1.5em; padding: 0; }
+li { font-family: monospace; white-space: pre-wrap; padding: 0.1em 0.3em; }
+.added { background: #e6ffed; color: #22863a; }
+.removed { background: #ffeef0; color: #b31d28; }
+.changed { background: #fff8c5; color: #735c0f; }
+.common { color: #6a737d; }
+.count { font-family: sans-serif; font-size: 0.8em; margin-left: 0.5em; padding: 0 0.3em; border-radius: 3px; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }} (<span class="removed">-{{ .AName }}</span>, <span class="added">+{{ .BName }}</span>)</h1>
+{{- if not .Root.Children }}
+<p>No differences.</p>
+{{- end }}
+{{- range .Root.Children }}
+{{ template "node" . }}
+{{- end }}
+</body>
+</html>
+{{ define "counts" -}}
+{{ with index . "added" }}<span class="count added">+{{ . }}</span>{{ end -}}
+{{ with index . "removed" }}<span class="count removed">-{{ . }}</span>{{ end -}}
+{{ with index . "changed" }}<span class="count changed">~{{ . }}</span>{{ end -}}
+{{ with index . "common" }}<span class="count common">={{ . }}</span>{{ end -}}
+{{ end }}
+{{- define "leaves" -}}
+{{- $name := .Name }}
+{{- range .Leaves }}
+{{- if eq .Kind "changed" }}
+<li class="removed">- {{ $name }}: {{ .Value }}</li>
+<li class="added">+ {{ $name }}: {{ .New }}</li>
+{{- else if .Desc }}
+<li class="{{ .Kind }}">{{ if eq .Kind "added" }}+{{ else if eq .Kind "removed" }}-{{ else }} {{ end }} {{ $name }}: {{ .Desc }}</li>
+{{- else }}
+<li class="{{ .Kind }}">{{ if eq .Kind "added" }}+{{ else if eq .Kind "removed" }}-{{ else }} {{ end }} {{ $name }}: {{ .Value }}</li>
+{{- end }}
+{{- end }}
+{{- end }}
+{{- define "node" -}}
+<details{{ if .Top }} class="top" open{{ end }}>
+<summary>{{ .Name }}{{ template "counts" .Counts }}</summary>
+{{- if or .Leaves .Items }}
+<ul>
+{{- template "leaves" (leafNode "." .Leaves) }}
+{{- range .Items }}
+{{- template "leaves" . }}
+{{- end }}
+</ul>
+{{- end }}
+{{- range .Subtrees }}
+{{ template "node" . }}
+{{- end }}
+</details>
+{{- end }}`))
+)
@@ -0,0 +1,122 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmidiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+func TestHTML(t *testing.T) {
+	diff := SetRequestIntentDiff{
+		DeleteDiff: DeleteDiff{
+			MissingDeletes: map[string]struct{}{
+				"/interfaces/interface[name=eth1]": {},
+			},
+		},
+		UpdateDiff: UpdateDiff{
+			MissingUpdates: map[string]interface{}{
+				"/interfaces/interface[name=eth1]/config/name": "eth1",
+			},
+			ExtraUpdates: map[string]interface{}{
+				"/system/config/hostname": "<b>rosesarered</b>",
+			},
+			CommonUpdates: map[string]interface{}{
+				"/interfaces/interface[name=eth0]/config/mtu": float64(1500),
+			},
+			MismatchedUpdates: map[string]MismatchedUpdate{
+				"/interfaces/interface[name=eth0]/config/enabled": {
+					A: false,
+					B: true,
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		desc        string
+		inFormat    Format
+		wantContain []string
+		wantAbsent  []string
+	}{{
+		desc: "compact output",
+		wantContain: []string{
+			"<title>SetRequestIntentDiff</title>",
+			`<details class="top" open>
+<summary>interfaces<span class="count removed">-2</span><span class="count changed">~1</span></summary>`,
+			`<summary>interface[name=eth1]<span class="count removed">-2</span></summary>
+<ul>
+<li class="removed">- .: deleted or replaced only in A</li>
+</ul>`,
+			`<li class="removed">- enabled: false</li>
+<li class="added">+ enabled: true</li>`,
+			`<li class="removed">- name: &#34;eth1&#34;</li>`,
+			// Values must be escaped.
+			`<li class="added">+ hostname: &#34;&lt;b&gt;rosesarered&lt;/b&gt;&#34;</li>`,
+		},
+		wantAbsent: []string{"mtu"},
+	}, {
+		desc:     "full output",
+		inFormat: Format{Full: true},
+		wantContain: []string{
+			`<summary>interfaces<span class="count removed">-2</span><span class="count changed">~1</span><span class="count common">=1</span></summary>`,
+			`<li class="common">  mtu: 1500</li>`,
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := diff.HTML(tt.inFormat)
+			if err != nil {
+				t.Fatalf("HTML: got unexpected error: %v", err)
+			}
+			for _, want := range tt.wantContain {
+				if !strings.Contains(got, want) {
+					t.Errorf("HTML: output does not contain %q, got:\n%s", want, got)
+				}
+			}
+			for _, absent := range tt.wantAbsent {
+				if strings.Contains(got, absent) {
+					t.Errorf("HTML: output unexpectedly contains %q, got:\n%s", absent, got)
+				}
+			}
+			// The top-level containers must be output in order.
+			if i, j := strings.Index(got, "<summary>interfaces"), strings.Index(got, "<summary>system"); i == -1 || j == -1 || i > j {
+				t.Errorf("HTML: top-level containers are not output in order, got:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestHTMLEmptyAndInvalid(t *testing.T) {
+	got, err := SetToNotifsDiff{}.HTML(Format{})
+	if err != nil {
+		t.Fatalf("HTML: got unexpected error: %v", err)
+	}
+	for _, want := range []string{"<title>SetToNotifsDiff</title>", "No differences."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML: output does not contain %q, got:\n%s", want, got)
+		}
+	}
+
+	_, err = StructuredDiff{
+		UpdateDiff: UpdateDiff{ExtraUpdates: map[string]interface{}{"/a[=c]": 1}},
+	}.HTML(Format{})
+	if diff := errdiff.Substring(err, "invalid path"); diff != "" {
+		t.Errorf("HTML: %s", diff)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/derekparker/trie"
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/openconfig/ygot/util"
 	"github.com/openconfig/ygot/ygot"
@@ -49,6 +50,54 @@ func (intent *setRequestIntent) writeUpdate(pathToLeaf string, val interface{},
 	return nil
 }
 
+// diffLeaves compares the intent to leaves, which are the leaf values of a
+// data tree keyed by the string representation of their paths, with values in
+// their JSON_IETF representation.
+//
+//   - MissingUpdates are the leaf updates of the intent that are not in
+//     leaves.
+//   - ExtraUpdates are the leaves that are not updated by the intent and are
+//     at or below one of its deletion paths.
+//   - MismatchedUpdates are the leaf updates of the intent whose values differ
+//     from those in leaves, where A is the value in the intent and B is the
+//     value in leaves.
+func (intent *setRequestIntent) diffLeaves(leaves map[string]interface{}) UpdateDiff {
+	diff := UpdateDiff{
+		MissingUpdates:    map[string]interface{}{},
+		ExtraUpdates:      map[string]interface{}{},
+		CommonUpdates:     map[string]interface{}{},
+		MismatchedUpdates: map[string]MismatchedUpdate{},
+	}
+	for path, vIntent := range intent.Updates {
+		v, ok := leaves[path]
+		switch {
+		case ok && !reflect.DeepEqual(vIntent, v):
+			diff.MismatchedUpdates[path] = MismatchedUpdate{A: vIntent, B: v}
+		case ok:
+			diff.CommonUpdates[path] = vIntent
+		default:
+			diff.MissingUpdates[path] = vIntent
+		}
+	}
+
+	t := trie.New()
+	for path, v := range leaves {
+		if _, ok := intent.Updates[path]; !ok {
+			t.Add(path, v)
+		}
+	}
+	for delPath := range intent.Deletes {
+		// TODO: handle wildcards in delete paths (if applicable).
+		if n, ok := t.Find(delPath); ok {
+			diff.ExtraUpdates[delPath] = n.Meta()
+		}
+		for _, extraPath := range t.PrefixSearch(delPath + "/") {
+			diff.ExtraUpdates[extraPath] = leaves[extraPath]
+		}
+	}
+	return diff
+}
+
 // populateUpdate populates all leaf updates at the given path into the intent.
 //
 // For any leaf updates, the corresponding path in the intent's delete is
@@ -104,9 +153,11 @@ func (intent *setRequestIntent) populateUpdate(path string, tv *gpb.TypedValue,
 		return fmt.Errorf("gnmidiff: input schema is not valid: %+v", schema)
 	}
 	rootSchema := schema.RootSchema()
-	targetSchema, err := util.FindLeafRefSchema(rootSchema, path)
-	if err != nil {
-		return fmt.Errorf("gnmidiff: error finding target schema: %v", err)
+	targetSchema := rootSchema
+	if path != "" {
+		if targetSchema, err = util.FindLeafRefSchema(rootSchema, path); err != nil {
+			return fmt.Errorf("gnmidiff: error finding target schema: %v", err)
+		}
 	}
 	setNodeTargetSchema := rootSchema
 	// Create a new empty root since we don't want previous updates to
@@ -144,7 +195,7 @@ func (intent *setRequestIntent) populateUpdate(path string, tv *gpb.TypedValue,
 		return fmt.Errorf("gnmidiff: error unmarshalling update: %v", err)
 	}
 
-	jsonBytes, err := ygot.Marshal7951(setNodeTarget)
+	jsonBytes, err := ygot.Marshal7951(setNodeTarget, &ygot.RFC7951JSONConfig{})
 	if err != nil {
 		return fmt.Errorf("gnmidiff: error marshalling GoStruct: %v", err)
 	}
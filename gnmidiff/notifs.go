@@ -0,0 +1,212 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmidiff
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// NotifsDiff contains the difference between two sets of Notifications, e.g.,
+// those in the GetResponses of two collection runs.
+type NotifsDiff UpdateDiff
+
+// Format outputs the NotifsDiff in human-readable format.
+//
+// NOTE: Do not depend on the output of this being stable.
+func (diff NotifsDiff) Format(f Format) string {
+	f.title = "NotifsDiff"
+	f.aName = "A"
+	f.bName = "B"
+	return StructuredDiff{UpdateDiff: UpdateDiff(diff)}.Format(f)
+}
+
+// Tolerance specifies the amount by which the numeric values of leaves may
+// differ without being reported as mismatched, e.g., for counters or gauges
+// that are expected to change between two collection runs.
+//
+// The values are within the tolerance if they differ by no more than
+// Absolute, or by no more than Relative of the larger of their magnitudes.
+type Tolerance struct {
+	// Path is the path of the leaves, or of a node containing the leaves,
+	// to which the tolerance applies. Wildcard names and keys are
+	// permitted, e.g., "/interfaces/interface[name=*]/state/counters".
+	Path string
+	// Absolute is the maximum absolute difference between the values.
+	Absolute float64
+	// Relative is the maximum difference between the values, as a
+	// fraction of the larger of their magnitudes, e.g., 0.05 for 5%.
+	Relative float64
+}
+
+// ParseTolerance parses a Tolerance of the form "<path>=<value>", where the
+// value is the absolute tolerance, e.g., "/system/state/boot-time=10", or the
+// relative tolerance when it ends with "%", e.g.,
+// "/interfaces/interface[name=*]/state/counters=5%".
+func ParseTolerance(s string) (*Tolerance, error) {
+	i := strings.LastIndex(s, "=")
+	if i == -1 {
+		return nil, fmt.Errorf("gnmidiff: tolerance %q is not of the form <path>=<value>", s)
+	}
+	path, val := s[:i], s[i+1:]
+	t := &Tolerance{Path: path}
+	relative := strings.HasSuffix(val, "%")
+	f, err := strconv.ParseFloat(strings.TrimSuffix(val, "%"), 64)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("gnmidiff: invalid value in tolerance %q: %v", s, err)
+	case f < 0:
+		return nil, fmt.Errorf("gnmidiff: negative value in tolerance %q", s)
+	case relative:
+		t.Relative = f / 100
+	default:
+		t.Absolute = f
+	}
+	return t, nil
+}
+
+// tolerances are the Tolerances that apply when comparing two sets of leaves.
+type tolerances []*toleranceQuery
+
+// toleranceQuery is a Tolerance along with its parsed path.
+type toleranceQuery struct {
+	*Tolerance
+	query *gpb.Path
+}
+
+// newTolerances parses the paths of the supplied Tolerances.
+func newTolerances(ts []*Tolerance) (tolerances, error) {
+	var tqs tolerances
+	for _, t := range ts {
+		q, err := ygot.StringToStructuredPath(t.Path)
+		if err != nil {
+			return nil, fmt.Errorf("gnmidiff: invalid tolerance path %q: %v", t.Path, err)
+		}
+		tqs = append(tqs, &toleranceQuery{Tolerance: t, query: q})
+	}
+	return tqs, nil
+}
+
+// equal reports whether the JSON_IETF values a and b of the leaf at path are
+// equal, or are numeric values within a tolerance that applies to path.
+func (tqs tolerances) equal(path string, a, b interface{}) (bool, error) {
+	if reflect.DeepEqual(a, b) {
+		return true, nil
+	}
+	if len(tqs) == 0 {
+		return false, nil
+	}
+	fa, okA := numericValue(a)
+	fb, okB := numericValue(b)
+	if !okA || !okB {
+		return false, nil
+	}
+	p, err := ygot.StringToStructuredPath(path)
+	if err != nil {
+		return false, fmt.Errorf("gnmidiff: %v", err)
+	}
+	diff := math.Abs(fa - fb)
+	for _, tq := range tqs {
+		if !util.PathMatchesQuery(p, tq.query) {
+			continue
+		}
+		if diff <= tq.Absolute || diff <= tq.Relative*math.Max(math.Abs(fa), math.Abs(fb)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// numericValue returns the numeric value of the JSON_IETF value v. Numbers
+// that RFC7951 encodes as strings, such as 64-bit integers and decimal64
+// values, are also numeric values.
+func numericValue(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, false
+		}
+		return f, true
+	}
+	return 0, false
+}
+
+// DiffNotifications returns the difference between the leaves in two sets of
+// Notifications, e.g., those in the GetResponses of two collection runs of the
+// same target. Numeric leaves whose values differ within any of the supplied
+// tolerances that apply to them are reported as common rather than
+// mismatched, with the value in a.
+//
+// schema is intended to be provided via the function defined in generated
+// ygot code (e.g. exampleoc.Schema).
+// If schema is not supplied, then any input JSON values MUST conform to the OpenConfig
+// YANG style guidelines. See the following for checking compliance.
+// * https://github.com/openconfig/oc-pyang
+// * https://github.com/openconfig/public/blob/master/doc/openconfig_style_guide.md
+func DiffNotifications(a, b []*gpb.Notification, schema *ytypes.Schema, tolerances ...*Tolerance) (NotifsDiff, error) {
+	tqs, err := newTolerances(tolerances)
+	if err != nil {
+		return NotifsDiff{}, err
+	}
+	updatesA, err := notificationUpdates(a, schema)
+	if err != nil {
+		return NotifsDiff{}, fmt.Errorf("DiffNotifications while reading A: %v", err)
+	}
+	updatesB, err := notificationUpdates(b, schema)
+	if err != nil {
+		return NotifsDiff{}, fmt.Errorf("DiffNotifications while reading B: %v", err)
+	}
+
+	diff := NotifsDiff{
+		MissingUpdates:    map[string]interface{}{},
+		ExtraUpdates:      map[string]interface{}{},
+		CommonUpdates:     map[string]interface{}{},
+		MismatchedUpdates: map[string]MismatchedUpdate{},
+	}
+	for pathA, vA := range updatesA {
+		vB, ok := updatesB[pathA]
+		if !ok {
+			diff.MissingUpdates[pathA] = vA
+			continue
+		}
+		equal, err := tqs.equal(pathA, vA, vB)
+		if err != nil {
+			return NotifsDiff{}, err
+		}
+		if equal {
+			diff.CommonUpdates[pathA] = vA
+		} else {
+			diff.MismatchedUpdates[pathA] = MismatchedUpdate{A: vA, B: vB}
+		}
+	}
+	for pathB, vB := range updatesB {
+		if _, ok := updatesA[pathB]; !ok {
+			diff.ExtraUpdates[pathB] = vB
+		}
+	}
+	return diff, nil
+}
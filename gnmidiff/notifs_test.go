@@ -0,0 +1,225 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmidiff
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestDiffNotifications(t *testing.T) {
+	uintUpd := func(path string, v uint64) *gpb.Update {
+		return &gpb.Update{Path: ygot.MustStringToPath(path), Val: &gpb.TypedValue{Value: &gpb.TypedValue_UintVal{UintVal: v}}}
+	}
+	jsonUpd := func(path, v string) *gpb.Update {
+		return &gpb.Update{Path: ygot.MustStringToPath(path), Val: &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(v)}}}
+	}
+	strUpd := func(path, v string) *gpb.Update {
+		return &gpb.Update{Path: ygot.MustStringToPath(path), Val: &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: v}}}
+	}
+
+	inA := []*gpb.Notification{{
+		Prefix: ygot.MustStringToPath("/interfaces/interface[name=eth0]/state"),
+		Update: []*gpb.Update{
+			uintUpd("mtu", 1500),
+			jsonUpd("counters/in-octets", `"1000"`),
+			jsonUpd("counters/out-octets", `"2000"`),
+			strUpd("description", "uplink"),
+		},
+	}, {
+		Update: []*gpb.Update{
+			jsonUpd("/interfaces/interface[name=eth1]/state/counters/in-octets", `"1000"`),
+			strUpd("/system/state/hostname", "rtr1"),
+		},
+	}}
+	inB := []*gpb.Notification{{
+		Prefix: ygot.MustStringToPath("/interfaces/interface[name=eth0]/state"),
+		Update: []*gpb.Update{
+			uintUpd("mtu", 1501),
+			jsonUpd("counters/in-octets", `"1040"`),
+			jsonUpd("counters/out-octets", `"2200"`),
+			strUpd("description", "downlink"),
+		},
+	}, {
+		Update: []*gpb.Update{
+			jsonUpd("/interfaces/interface[name=eth1]/state/counters/in-octets", `"1060"`),
+			strUpd("/system/state/domain-name", "example.com"),
+		},
+	}}
+
+	tests := []struct {
+		desc             string
+		inTolerances     []*Tolerance
+		want             NotifsDiff
+		wantErrSubstring string
+	}{{
+		desc: "no tolerances",
+		want: NotifsDiff{
+			MissingUpdates: map[string]interface{}{
+				"/system/state/hostname": "rtr1",
+			},
+			ExtraUpdates: map[string]interface{}{
+				"/system/state/domain-name": "example.com",
+			},
+			CommonUpdates: map[string]interface{}{},
+			MismatchedUpdates: map[string]MismatchedUpdate{
+				"/interfaces/interface[name=eth0]/state/mtu":                 {A: float64(1500), B: float64(1501)},
+				"/interfaces/interface[name=eth0]/state/counters/in-octets":  {A: "1000", B: "1040"},
+				"/interfaces/interface[name=eth0]/state/counters/out-octets": {A: "2000", B: "2200"},
+				"/interfaces/interface[name=eth0]/state/description":         {A: "uplink", B: "downlink"},
+				"/interfaces/interface[name=eth1]/state/counters/in-octets":  {A: "1000", B: "1060"},
+			},
+		},
+	}, {
+		desc: "absolute and relative tolerances",
+		inTolerances: []*Tolerance{
+			{Path: "/interfaces/interface[name=*]/state/counters", Relative: 0.05},
+			{Path: "/interfaces/interface/state/mtu", Absolute: 1},
+			{Path: "/interfaces/interface[name=eth0]/state/description", Absolute: 100},
+		},
+		want: NotifsDiff{
+			MissingUpdates: map[string]interface{}{
+				"/system/state/hostname": "rtr1",
+			},
+			ExtraUpdates: map[string]interface{}{
+				"/system/state/domain-name": "example.com",
+			},
+			CommonUpdates: map[string]interface{}{
+				"/interfaces/interface[name=eth0]/state/mtu":                float64(1500),
+				"/interfaces/interface[name=eth0]/state/counters/in-octets": "1000",
+			},
+			MismatchedUpdates: map[string]MismatchedUpdate{
+				"/interfaces/interface[name=eth0]/state/counters/out-octets": {A: "2000", B: "2200"},
+				"/interfaces/interface[name=eth0]/state/description":         {A: "uplink", B: "downlink"},
+				"/interfaces/interface[name=eth1]/state/counters/in-octets":  {A: "1000", B: "1060"},
+			},
+		},
+	}, {
+		desc: "most permissive tolerance applies",
+		inTolerances: []*Tolerance{
+			{Path: "/interfaces/interface[name=*]/state/counters", Relative: 0.05},
+			{Path: "/interfaces/interface[name=eth1]", Absolute: 100},
+		},
+		want: NotifsDiff{
+			MissingUpdates: map[string]interface{}{
+				"/system/state/hostname": "rtr1",
+			},
+			ExtraUpdates: map[string]interface{}{
+				"/system/state/domain-name": "example.com",
+			},
+			CommonUpdates: map[string]interface{}{
+				"/interfaces/interface[name=eth0]/state/counters/in-octets": "1000",
+				"/interfaces/interface[name=eth1]/state/counters/in-octets": "1000",
+			},
+			MismatchedUpdates: map[string]MismatchedUpdate{
+				"/interfaces/interface[name=eth0]/state/mtu":                 {A: float64(1500), B: float64(1501)},
+				"/interfaces/interface[name=eth0]/state/counters/out-octets": {A: "2000", B: "2200"},
+				"/interfaces/interface[name=eth0]/state/description":         {A: "uplink", B: "downlink"},
+			},
+		},
+	}, {
+		desc:             "invalid tolerance path",
+		inTolerances:     []*Tolerance{{Path: "/interfaces/interface[name]", Absolute: 1}},
+		wantErrSubstring: "invalid tolerance path",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := DiffNotifications(inA, inB, nil, tt.inTolerances...)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("DiffNotifications: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("DiffNotifications (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseTolerance(t *testing.T) {
+	tests := []struct {
+		desc             string
+		in               string
+		want             *Tolerance
+		wantErrSubstring string
+	}{{
+		desc: "absolute",
+		in:   "/system/state/boot-time=10",
+		want: &Tolerance{Path: "/system/state/boot-time", Absolute: 10},
+	}, {
+		desc: "relative with keys",
+		in:   "/interfaces/interface[name=*]/state/counters=5%",
+		want: &Tolerance{Path: "/interfaces/interface[name=*]/state/counters", Relative: 0.05},
+	}, {
+		desc:             "missing value",
+		in:               "/system/state/boot-time",
+		wantErrSubstring: "not of the form",
+	}, {
+		desc:             "invalid value",
+		in:               "/system/state/boot-time=ten",
+		wantErrSubstring: "invalid value",
+	}, {
+		desc:             "negative value",
+		in:               "/system/state/boot-time=-1",
+		wantErrSubstring: "negative value",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ParseTolerance(tt.in)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("ParseTolerance: did not get expected error, %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseTolerance (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNotifsDiffFormat(t *testing.T) {
+	diff := NotifsDiff{
+		MissingUpdates: map[string]interface{}{
+			"/system/state/hostname": "rtr1",
+		},
+		ExtraUpdates: map[string]interface{}{
+			"/system/state/domain-name": "example.com",
+		},
+		CommonUpdates: map[string]interface{}{
+			"/interfaces/interface[name=eth0]/state/counters/in-octets": "1000",
+		},
+		MismatchedUpdates: map[string]MismatchedUpdate{
+			"/interfaces/interface[name=eth0]/state/counters/out-octets": {A: "2000", B: "2200"},
+		},
+	}
+	want := `NotifsDiff(-A, +B):
+- /system/state/hostname: "rtr1"
++ /system/state/domain-name: "example.com"
+m /interfaces/interface[name=eth0]/state/counters/out-octets:
+  - "2000"
+  + "2200"
+`
+	if diff := cmp.Diff(want, diff.Format(Format{})); diff != "" {
+		t.Errorf("Format (-want, +got):\n%s", diff)
+	}
+}
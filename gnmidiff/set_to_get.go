@@ -2,9 +2,7 @@ package gnmidiff
 
 import (
 	"fmt"
-	"reflect"
 
-	"github.com/derekparker/trie"
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/openconfig/ygot/ytypes"
 )
@@ -37,13 +35,17 @@ func DiffSetRequestToNotifications(setreq *gpb.SetRequest, notifs []*gpb.Notific
 	if err != nil {
 		return SetToNotifsDiff{}, fmt.Errorf("DiffSetRequestToNotifications while calculating setIntent: %v", err)
 	}
-	diff := SetToNotifsDiff{
-		MissingUpdates:    map[string]interface{}{},
-		ExtraUpdates:      map[string]interface{}{},
-		CommonUpdates:     map[string]interface{}{},
-		MismatchedUpdates: map[string]MismatchedUpdate{},
+	updates, err := notificationUpdates(notifs, schema)
+	if err != nil {
+		return SetToNotifsDiff{}, err
 	}
+	return SetToNotifsDiff(setIntent.diffLeaves(updates)), nil
+}
 
+// notificationUpdates returns the leaf updates in the supplied Notifications,
+// keyed by the string representation of their paths, with values in their
+// JSON_IETF representation.
+func notificationUpdates(notifs []*gpb.Notification, schema *ytypes.Schema) (map[string]interface{}, error) {
 	updateIntent := setRequestIntent{
 		Deletes: map[string]struct{}{},
 		Updates: map[string]interface{}{},
@@ -51,47 +53,21 @@ func DiffSetRequestToNotifications(setreq *gpb.SetRequest, notifs []*gpb.Notific
 	for _, notif := range notifs {
 		// TODO: Handle deletes in notification.
 		if len(notif.Delete) > 0 {
-			return SetToNotifsDiff{}, fmt.Errorf("Deletes in notifications not currently supported.")
+			return nil, fmt.Errorf("Deletes in notifications not currently supported.")
 		}
 		prefix, err := prefixStr(notif.Prefix)
 		if err != nil {
-			return SetToNotifsDiff{}, fmt.Errorf("gnmidiff: %v", err)
+			return nil, fmt.Errorf("gnmidiff: %v", err)
 		}
 		for _, upd := range notif.Update {
 			path, err := fullPathStr(prefix, upd.Path)
 			if err != nil {
-				return SetToNotifsDiff{}, err
+				return nil, err
 			}
 			if err := updateIntent.populateUpdate(path, upd.Val, schema, false); err != nil {
-				return SetToNotifsDiff{}, err
+				return nil, err
 			}
 		}
 	}
-	updates := updateIntent.Updates
-
-	for pathA, vA := range setIntent.Updates {
-		vB, ok := updates[pathA]
-		switch {
-		case ok && !reflect.DeepEqual(vA, vB):
-			diff.MismatchedUpdates[pathA] = MismatchedUpdate{A: vA, B: vB}
-		case ok:
-			diff.CommonUpdates[pathA] = vA
-		default:
-			diff.MissingUpdates[pathA] = vA
-		}
-		delete(updates, pathA)
-	}
-
-	t := trie.New()
-	for pathB, vB := range updates {
-		t.Add(pathB, vB)
-	}
-	for delPath := range setIntent.Deletes {
-		// TODO: handle wildcards in delete paths (if applicable).
-		for _, extraPath := range t.PrefixSearch(delPath + "/") {
-			diff.ExtraUpdates[extraPath] = updates[extraPath]
-		}
-	}
-
-	return diff, nil
+	return updateIntent.Updates, nil
 }
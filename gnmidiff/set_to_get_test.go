@@ -480,6 +480,30 @@ func TestSetRequestToNotifications(t *testing.T) {
 			CommonUpdates:     map[string]interface{}{},
 			MismatchedUpdates: map[string]MismatchedUpdate{},
 		},
+	}, {
+		desc: "delete of leaf in SetRequest",
+		inSetRequest: &gpb.SetRequest{
+			Delete: []*gpb.Path{
+				ygot.MustStringToPath("/interfaces/interface[name=eth0]/config/description"),
+			},
+		},
+		inNotifications: []*gpb.Notification{{
+			Update: []*gpb.Update{{
+				Path: ygot.MustStringToPath("/interfaces/interface[name=eth0]/config/description"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "I am an eth port"}},
+			}, {
+				Path: ygot.MustStringToPath("/interfaces/interface[name=eth0]/state/transceiver"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "FDM"}},
+			}},
+		}},
+		wantSetToNotifsDiff: SetToNotifsDiff{
+			MissingUpdates: map[string]interface{}{},
+			ExtraUpdates: map[string]interface{}{
+				"/interfaces/interface[name=eth0]/config/description": "I am an eth port",
+			},
+			CommonUpdates:     map[string]interface{}{},
+			MismatchedUpdates: map[string]MismatchedUpdate{},
+		},
 	}, {
 		desc: "SetRequest has conflicts",
 		inSetRequest: &gpb.SetRequest{
@@ -0,0 +1,108 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmidiff
+
+import (
+	"fmt"
+
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// SnapshotToSetDiff contains the changes that a SetRequest would make to a
+// snapshot of the state of the target.
+//
+//   - CommonUpdates are the leaf updates of the SetRequest that are no-ops,
+//     since the leaf has the same value in the snapshot.
+//   - MismatchedUpdates are the leaf updates of the SetRequest that modify
+//     the value of a leaf, where A is the value in the snapshot and B is the
+//     value in the SetRequest.
+//   - ExtraUpdates (+) are the leaf updates of the SetRequest that add leaves
+//     which are not present in the snapshot.
+//   - MissingUpdates (-) are the leaves of the snapshot that are removed by
+//     the deletes and replaces of the SetRequest.
+type SnapshotToSetDiff UpdateDiff
+
+// Format outputs the SnapshotToSetDiff in human-readable format.
+//
+// NOTE: Do not depend on the output of this being stable.
+func (diff SnapshotToSetDiff) Format(f Format) string {
+	f.title = "SnapshotToSetDiff"
+	f.aName = "snapshot"
+	f.bName = "SetRequest"
+	return StructuredDiff{UpdateDiff: UpdateDiff(diff)}.Format(f)
+}
+
+// IsNoOp returns true if applying the SetRequest to the snapshot would not
+// change any leaf of the snapshot.
+func (diff SnapshotToSetDiff) IsNoOp() bool {
+	return len(diff.MissingUpdates) == 0 && len(diff.ExtraUpdates) == 0 && len(diff.MismatchedUpdates) == 0
+}
+
+// DiffSnapshotToSetRequest returns the changes that the SetRequest would make
+// when applied to the target whose state is described by snapshot, which is
+// the RFC7951 JSON representation of the data tree from its root.
+//
+// schema is intended to be provided via the function defined in generated
+// ygot code (e.g. exampleoc.Schema).
+// If schema is not supplied, then the snapshot and any input JSON values MUST
+// conform to the OpenConfig YANG style guidelines. See the following for
+// checking compliance.
+// * https://github.com/openconfig/oc-pyang
+// * https://github.com/openconfig/public/blob/master/doc/openconfig_style_guide.md
+func DiffSnapshotToSetRequest(snapshot []byte, setreq *gpb.SetRequest, schema *ytypes.Schema) (SnapshotToSetDiff, error) {
+	setIntent, err := minimalSetRequestIntent(setreq, schema)
+	if err != nil {
+		return SnapshotToSetDiff{}, fmt.Errorf("DiffSnapshotToSetRequest while calculating setIntent: %v", err)
+	}
+
+	leaves, err := notificationUpdates([]*gpb.Notification{{
+		Update: []*gpb.Update{{
+			Path: &gpb.Path{},
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: snapshot}},
+		}},
+	}}, schema)
+	if err != nil {
+		return SnapshotToSetDiff{}, fmt.Errorf("DiffSnapshotToSetRequest while reading snapshot: %v", err)
+	}
+
+	// The snapshot is the first argument of the diff, hence the diff of the
+	// intent against the snapshot is inverted.
+	d := setIntent.diffLeaves(leaves)
+	diff := SnapshotToSetDiff{
+		MissingUpdates:    d.ExtraUpdates,
+		ExtraUpdates:      d.MissingUpdates,
+		CommonUpdates:     d.CommonUpdates,
+		MismatchedUpdates: map[string]MismatchedUpdate{},
+	}
+	for path, m := range d.MismatchedUpdates {
+		diff.MismatchedUpdates[path] = MismatchedUpdate{A: m.B, B: m.A}
+	}
+	return diff, nil
+}
+
+// DiffGoStructToSetRequest returns the changes that the SetRequest would make
+// when applied to the target whose state is described by snapshot, which is
+// a GoStruct representing the root of the data tree, as described by
+// DiffSnapshotToSetRequest.
+func DiffGoStructToSetRequest(snapshot ygot.GoStruct, setreq *gpb.SetRequest, schema *ytypes.Schema) (SnapshotToSetDiff, error) {
+	js, err := ygot.Marshal7951(snapshot, &ygot.RFC7951JSONConfig{AppendModuleName: true})
+	if err != nil {
+		return SnapshotToSetDiff{}, fmt.Errorf("DiffGoStructToSetRequest while marshalling snapshot: %v", err)
+	}
+	return DiffSnapshotToSetRequest(js, setreq, schema)
+}
@@ -0,0 +1,209 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmidiff
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ygot/exampleoc"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestDiffSnapshotToSetRequest(t *testing.T) {
+	eth0 := func() *exampleoc.Device {
+		d := &exampleoc.Device{}
+		i := d.GetOrCreateInterface("eth0")
+		i.Description = ygot.String("uplink")
+		i.Mtu = ygot.Uint16(1500)
+		return d
+	}
+
+	tests := []struct {
+		desc          string
+		inSnapshot    ygot.GoStruct
+		inSetRequest  *gpb.SetRequest
+		want          SnapshotToSetDiff
+		wantNoOp      bool
+		wantErr       bool
+		skipNilSchema bool
+	}{{
+		desc:       "no-op updates",
+		inSnapshot: eth0(),
+		inSetRequest: &gpb.SetRequest{
+			Update: []*gpb.Update{{
+				Path: ygot.MustStringToPath("/interfaces/interface[name=eth0]/config/description"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "uplink"}},
+			}, {
+				Path: ygot.MustStringToPath("/interfaces/interface[name=eth0]/config/mtu"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_UintVal{UintVal: 1500}},
+			}},
+		},
+		want: SnapshotToSetDiff{
+			MissingUpdates: map[string]interface{}{},
+			ExtraUpdates:   map[string]interface{}{},
+			CommonUpdates: map[string]interface{}{
+				"/interfaces/interface[name=eth0]/config/description": "uplink",
+				"/interfaces/interface[name=eth0]/config/mtu":         float64(1500),
+			},
+			MismatchedUpdates: map[string]MismatchedUpdate{},
+		},
+		wantNoOp: true,
+	}, {
+		desc:       "modified and added leaves",
+		inSnapshot: eth0(),
+		inSetRequest: &gpb.SetRequest{
+			Update: []*gpb.Update{{
+				Path: ygot.MustStringToPath("/interfaces/interface[name=eth0]/config/description"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "downlink"}},
+			}, {
+				Path: ygot.MustStringToPath("/interfaces/interface[name=eth1]"),
+				Val:  must7951(&exampleoc.Interface{Name: ygot.String("eth1")}),
+			}},
+		},
+		want: SnapshotToSetDiff{
+			MissingUpdates: map[string]interface{}{},
+			ExtraUpdates: map[string]interface{}{
+				"/interfaces/interface[name=eth1]/name":        "eth1",
+				"/interfaces/interface[name=eth1]/config/name": "eth1",
+			},
+			CommonUpdates: map[string]interface{}{},
+			MismatchedUpdates: map[string]MismatchedUpdate{
+				"/interfaces/interface[name=eth0]/config/description": {A: "uplink", B: "downlink"},
+			},
+		},
+	}, {
+		desc:       "replace removes leaves",
+		inSnapshot: eth0(),
+		inSetRequest: &gpb.SetRequest{
+			Replace: []*gpb.Update{{
+				Path: ygot.MustStringToPath("/interfaces/interface[name=eth0]"),
+				Val:  must7951(&exampleoc.Interface{Name: ygot.String("eth0"), Mtu: ygot.Uint16(1500)}),
+			}},
+		},
+		want: SnapshotToSetDiff{
+			MissingUpdates: map[string]interface{}{
+				"/interfaces/interface[name=eth0]/config/description": "uplink",
+			},
+			ExtraUpdates: map[string]interface{}{},
+			CommonUpdates: map[string]interface{}{
+				"/interfaces/interface[name=eth0]/name":        "eth0",
+				"/interfaces/interface[name=eth0]/config/name": "eth0",
+				"/interfaces/interface[name=eth0]/config/mtu":  float64(1500),
+			},
+			MismatchedUpdates: map[string]MismatchedUpdate{},
+		},
+	}, {
+		desc:       "delete of leaf and of absent subtree",
+		inSnapshot: eth0(),
+		inSetRequest: &gpb.SetRequest{
+			Delete: []*gpb.Path{
+				ygot.MustStringToPath("/interfaces/interface[name=eth0]/config/mtu"),
+				ygot.MustStringToPath("/interfaces/interface[name=eth1]"),
+			},
+		},
+		want: SnapshotToSetDiff{
+			MissingUpdates: map[string]interface{}{
+				"/interfaces/interface[name=eth0]/config/mtu": float64(1500),
+			},
+			ExtraUpdates:      map[string]interface{}{},
+			CommonUpdates:     map[string]interface{}{},
+			MismatchedUpdates: map[string]MismatchedUpdate{},
+		},
+	}, {
+		desc:       "empty SetRequest",
+		inSnapshot: eth0(),
+		want: SnapshotToSetDiff{
+			MissingUpdates:    map[string]interface{}{},
+			ExtraUpdates:      map[string]interface{}{},
+			CommonUpdates:     map[string]interface{}{},
+			MismatchedUpdates: map[string]MismatchedUpdate{},
+		},
+		wantNoOp: true,
+	}, {
+		desc:       "conflicting SetRequest",
+		inSnapshot: eth0(),
+		inSetRequest: &gpb.SetRequest{
+			Update: []*gpb.Update{{
+				Path: ygot.MustStringToPath("/interfaces/interface[name=eth0]/config/description"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+			}, {
+				Path: ygot.MustStringToPath("/interfaces/interface[name=eth0]/config/description"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "bar"}},
+			}},
+		},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			for _, withSchema := range []bool{false, true} {
+				var inSchema *ytypes.Schema
+				if withSchema {
+					var err error
+					if inSchema, err = exampleoc.Schema(); err != nil {
+						t.Fatalf("schema has error: %v", err)
+					}
+				}
+				t.Run(fmt.Sprintf("withSchema-%v", withSchema), func(t *testing.T) {
+					got, err := DiffGoStructToSetRequest(tt.inSnapshot, tt.inSetRequest, inSchema)
+					if (err != nil) != tt.wantErr {
+						t.Fatalf("got error: %v, want error: %v", err, tt.wantErr)
+					}
+					if err != nil {
+						return
+					}
+					if diff := cmp.Diff(tt.want, got); diff != "" {
+						t.Errorf("DiffGoStructToSetRequest (-want, +got):\n%s", diff)
+					}
+					if got.IsNoOp() != tt.wantNoOp {
+						t.Errorf("IsNoOp: got %v, want %v", got.IsNoOp(), tt.wantNoOp)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestSnapshotToSetDiffFormat(t *testing.T) {
+	diff := SnapshotToSetDiff{
+		MissingUpdates: map[string]interface{}{
+			"/interfaces/interface[name=eth0]/config/mtu": float64(1500),
+		},
+		ExtraUpdates: map[string]interface{}{
+			"/interfaces/interface[name=eth1]/name": "eth1",
+		},
+		CommonUpdates: map[string]interface{}{
+			"/interfaces/interface[name=eth0]/name": "eth0",
+		},
+		MismatchedUpdates: map[string]MismatchedUpdate{
+			"/interfaces/interface[name=eth0]/config/description": {A: "uplink", B: "downlink"},
+		},
+	}
+	want := `SnapshotToSetDiff(-snapshot, +SetRequest):
+- /interfaces/interface[name=eth0]/config/mtu: 1500
++ /interfaces/interface[name=eth1]/name: "eth1"
+m /interfaces/interface[name=eth0]/config/description:
+  - "uplink"
+  + "downlink"
+`
+	if diff := cmp.Diff(want, diff.Format(Format{})); diff != "" {
+		t.Errorf("Format (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmidiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+const (
+	// ansiReset resets the colour of the output.
+	ansiReset = "\x1b[0m"
+	// ansiBold is used for the title of a unified diff.
+	ansiBold = "\x1b[1m"
+	// ansiRed is used for differences that are present only in A.
+	ansiRed = "\x1b[31m"
+	// ansiGreen is used for differences that are present only in B.
+	ansiGreen = "\x1b[32m"
+	// ansiCyan is used for the header of each top-level container.
+	ansiCyan = "\x1b[36m"
+)
+
+// unifiedLine is a line of the unified format of a StructuredDiff.
+type unifiedLine struct {
+	// path is the path of the delete or update described by the line.
+	path string
+	// symbol is '-' for a difference present in A, '+' for a difference
+	// present in B, and ' ' for a value that is common to A and B.
+	symbol rune
+	// text is the description of the delete, or the value of the update.
+	text string
+	// order orders the lines with the same path, such that deletes precede
+	// updates, and the value in A precedes that in B.
+	order int
+}
+
+// unifiedGroup is the set of lines of the unified format within a top-level
+// container.
+type unifiedGroup struct {
+	// name is the path of the top-level container.
+	name string
+	// lines are the lines within the container.
+	lines []*unifiedLine
+}
+
+// pathContext returns the path of the top-level container of the supplied
+// path, and that of the innermost list entry containing it, or the empty
+// string if it is not within a list entry. The path of the top-level
+// container of a path that cannot be parsed is the path itself.
+func pathContext(path string) (string, string) {
+	p, err := ygot.StringToStructuredPath(path)
+	if err != nil || len(p.Elem) == 0 {
+		return path, ""
+	}
+	top, err := ygot.PathToString(&gpb.Path{Elem: p.Elem[:1]})
+	if err != nil {
+		return path, ""
+	}
+	for i := len(p.Elem) - 1; i >= 0; i-- {
+		if len(p.Elem[i].Key) == 0 {
+			continue
+		}
+		entry, err := ygot.PathToString(&gpb.Path{Elem: p.Elem[:i+1]})
+		if err != nil {
+			return top, ""
+		}
+		return top, entry
+	}
+	return top, ""
+}
+
+// unifiedFormat outputs the StructuredDiff in the style of a unified diff,
+// in which the differences are grouped by their top-level container. Where
+// f.Full is not set, the values that are common to A and B are output only
+// where they are within f.Context lines of a difference within the same list
+// entry.
+func (diff StructuredDiff) unifiedFormat(f Format) string {
+	var lines []*unifiedLine
+	addDeletes := func(paths map[string]struct{}, symbol rune, desc string) {
+		for p := range paths {
+			lines = append(lines, &unifiedLine{path: p, symbol: symbol, text: desc})
+		}
+	}
+	if f.Full {
+		addDeletes(diff.CommonDeletes, ' ', f.deleteDesc)
+	}
+	addDeletes(diff.MissingDeletes, '-', fmt.Sprintf("%s only in %s", f.deleteDesc, f.aName))
+	addDeletes(diff.ExtraDeletes, '+', fmt.Sprintf("%s only in %s", f.deleteDesc, f.bName))
+
+	addUpdates := func(updates map[string]interface{}, symbol rune, order int) {
+		for p, v := range updates {
+			lines = append(lines, &unifiedLine{path: p, symbol: symbol, text: fmt.Sprint(formatJSONValue(v)), order: order})
+		}
+	}
+	// Common updates are always added, such that they can be output as
+	// the context of the differences.
+	addUpdates(diff.CommonUpdates, ' ', 1)
+	addUpdates(diff.MissingUpdates, '-', 1)
+	addUpdates(diff.ExtraUpdates, '+', 2)
+	for p, m := range diff.MismatchedUpdates {
+		lines = append(lines,
+			&unifiedLine{path: p, symbol: '-', text: fmt.Sprint(formatJSONValue(m.A)), order: 1},
+			&unifiedLine{path: p, symbol: '+', text: fmt.Sprint(formatJSONValue(m.B)), order: 2},
+		)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		li, lj := lines[i], lines[j]
+		switch {
+		case li.path != lj.path:
+			return li.path < lj.path
+		case li.order != lj.order:
+			return li.order < lj.order
+		default:
+			return li.symbol < lj.symbol
+		}
+	})
+
+	// Determine the lines that are output. Each line describing a
+	// difference is output, along with the common values within Context
+	// lines of a difference within the same list entry.
+	show := make([]bool, len(lines))
+	entries := map[string][]int{}
+	groups := map[string]*unifiedGroup{}
+	var names []string
+	groupOf := make([]*unifiedGroup, len(lines))
+	for i, l := range lines {
+		top, entry := pathContext(l.path)
+		g, ok := groups[top]
+		if !ok {
+			g = &unifiedGroup{name: top}
+			groups[top] = g
+			names = append(names, top)
+		}
+		groupOf[i] = g
+		show[i] = l.symbol != ' ' || f.Full
+		if entry != "" {
+			entries[entry] = append(entries[entry], i)
+		}
+	}
+	if !f.Full && f.Context > 0 {
+		for _, idx := range entries {
+			for n, i := range idx {
+				if lines[i].symbol == ' ' {
+					continue
+				}
+				for m := n - f.Context; m <= n+f.Context; m++ {
+					if m >= 0 && m < len(idx) {
+						show[idx[m]] = true
+					}
+				}
+			}
+		}
+	}
+	for i, l := range lines {
+		if show[i] {
+			groupOf[i].lines = append(groupOf[i].lines, l)
+		}
+	}
+
+	colour := func(s, c string) string {
+		if !f.Color {
+			return s
+		}
+		return c + s + ansiReset
+	}
+	var b strings.Builder
+	b.WriteString(colour(fmt.Sprintf("%s(-%s, +%s):", f.title, f.aName, f.bName), ansiBold) + "\n")
+	b.WriteString(colour(fmt.Sprintf("--- %s", f.aName), ansiRed) + "\n")
+	b.WriteString(colour(fmt.Sprintf("+++ %s", f.bName), ansiGreen) + "\n")
+	sort.Strings(names)
+	for _, name := range names {
+		g := groups[name]
+		if len(g.lines) == 0 {
+			continue
+		}
+		b.WriteString(colour(fmt.Sprintf("@@ %s @@", g.name), ansiCyan) + "\n")
+		for _, l := range g.lines {
+			s := fmt.Sprintf("%c%s: %s", l.symbol, l.path, l.text)
+			switch l.symbol {
+			case '-':
+				s = colour(s, ansiRed)
+			case '+':
+				s = colour(s, ansiGreen)
+			}
+			b.WriteString(s + "\n")
+		}
+	}
+	return b.String()
+}
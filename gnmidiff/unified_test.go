@@ -0,0 +1,145 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmidiff
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnifiedFormat(t *testing.T) {
+	diff := SetRequestIntentDiff{
+		DeleteDiff: DeleteDiff{
+			MissingDeletes: map[string]struct{}{
+				"/interfaces/interface[name=eth2]": {},
+			},
+			CommonDeletes: map[string]struct{}{
+				"/system/config": {},
+			},
+		},
+		UpdateDiff: UpdateDiff{
+			MissingUpdates: map[string]interface{}{
+				"/interfaces/interface[name=eth0]/config/enabled": true,
+			},
+			ExtraUpdates: map[string]interface{}{
+				"/interfaces-ext/interface[name=eth0]/config/speed": "10G",
+			},
+			CommonUpdates: map[string]interface{}{
+				"/interfaces/interface[name=eth0]/config/description": "uplink",
+				"/interfaces/interface[name=eth0]/config/name":        "eth0",
+				"/interfaces/interface[name=eth0]/config/type":        "ethernetCsmacd",
+				"/interfaces/interface[name=eth0]/name":               "eth0",
+				"/interfaces/interface[name=eth1]/config/name":        "eth1",
+				"/system/config/hostname":                             "rtr1",
+			},
+			MismatchedUpdates: map[string]MismatchedUpdate{
+				"/interfaces/interface[name=eth0]/config/mtu": {A: float64(1500), B: float64(9000)},
+			},
+		},
+	}
+
+	tests := []struct {
+		desc   string
+		inDiff interface{ Format(Format) string }
+		inFmt  Format
+		want   string
+	}{{
+		desc:   "unified",
+		inDiff: diff,
+		inFmt:  Format{Unified: true},
+		want: `SetRequestIntentDiff(-A, +B):
+--- A
++++ B
+@@ /interfaces @@
+-/interfaces/interface[name=eth0]/config/enabled: true
+-/interfaces/interface[name=eth0]/config/mtu: 1500
++/interfaces/interface[name=eth0]/config/mtu: 9000
+-/interfaces/interface[name=eth2]: deleted or replaced only in A
+@@ /interfaces-ext @@
++/interfaces-ext/interface[name=eth0]/config/speed: "10G"
+`,
+	}, {
+		desc:   "unified with context",
+		inDiff: diff,
+		inFmt:  Format{Unified: true, Context: 1},
+		want: `SetRequestIntentDiff(-A, +B):
+--- A
++++ B
+@@ /interfaces @@
+ /interfaces/interface[name=eth0]/config/description: "uplink"
+-/interfaces/interface[name=eth0]/config/enabled: true
+-/interfaces/interface[name=eth0]/config/mtu: 1500
++/interfaces/interface[name=eth0]/config/mtu: 9000
+ /interfaces/interface[name=eth0]/config/name: "eth0"
+-/interfaces/interface[name=eth2]: deleted or replaced only in A
+@@ /interfaces-ext @@
++/interfaces-ext/interface[name=eth0]/config/speed: "10G"
+`,
+	}, {
+		desc:   "unified with full",
+		inDiff: diff,
+		inFmt:  Format{Unified: true, Full: true, Context: 1},
+		want: `SetRequestIntentDiff(-A, +B):
+--- A
++++ B
+@@ /interfaces @@
+ /interfaces/interface[name=eth0]/config/description: "uplink"
+-/interfaces/interface[name=eth0]/config/enabled: true
+-/interfaces/interface[name=eth0]/config/mtu: 1500
++/interfaces/interface[name=eth0]/config/mtu: 9000
+ /interfaces/interface[name=eth0]/config/name: "eth0"
+ /interfaces/interface[name=eth0]/config/type: "ethernetCsmacd"
+ /interfaces/interface[name=eth0]/name: "eth0"
+ /interfaces/interface[name=eth1]/config/name: "eth1"
+-/interfaces/interface[name=eth2]: deleted or replaced only in A
+@@ /interfaces-ext @@
++/interfaces-ext/interface[name=eth0]/config/speed: "10G"
+@@ /system @@
+ /system/config: deleted or replaced
+ /system/config/hostname: "rtr1"
+`,
+	}, {
+		desc: "unified with color",
+		inDiff: NotifsDiff{
+			MismatchedUpdates: map[string]MismatchedUpdate{
+				"/system/config/hostname": {A: "rtr1", B: "rtr2"},
+			},
+		},
+		inFmt: Format{Unified: true, Color: true},
+		want: "\x1b[1mNotifsDiff(-A, +B):\x1b[0m\n" +
+			"\x1b[31m--- A\x1b[0m\n" +
+			"\x1b[32m+++ B\x1b[0m\n" +
+			"\x1b[36m@@ /system @@\x1b[0m\n" +
+			"\x1b[31m-/system/config/hostname: \"rtr1\"\x1b[0m\n" +
+			"\x1b[32m+/system/config/hostname: \"rtr2\"\x1b[0m\n",
+	}, {
+		desc:   "no differences",
+		inDiff: NotifsDiff{},
+		inFmt:  Format{Unified: true, Context: 3},
+		want: `NotifsDiff(-A, +B):
+--- A
++++ B
+`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, tt.inDiff.Format(tt.inFmt)); diff != "" {
+				t.Errorf("Format (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -3,13 +3,18 @@ package gogen
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/genutil"
 	"github.com/openconfig/ygot/internal/igenutil"
 	"github.com/openconfig/ygot/util"
 	"github.com/openconfig/ygot/ygen"
 	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
 )
 
 // CodeGenerator is a structure that is used to pass arguments as to
@@ -24,6 +29,10 @@ type CodeGenerator struct {
 	// GoOptions stores a struct which stores Go code generation specific
 	// options for code generaton post IR generation.
 	GoOptions GoOpts
+	// Plugins are invoked for each generated struct and each of its
+	// fields, and emit additional code that is appended to the methods of
+	// the struct. See Plugin for details.
+	Plugins []Plugin
 }
 
 // GoOpts stores Go specific options for the code generation library.
@@ -37,6 +46,20 @@ type GoOpts struct {
 	// IncludeDescriptions specifies that YANG entry descriptions are added
 	// to the JSON schema. Is false by default, to reduce the size of generated schema
 	IncludeDescriptions bool
+	// BinarySchema specifies that the schema stored with the output code
+	// is serialised using the compact binary representation loaded by
+	// ytypes.LoadSchemaBinary rather than as JSON, reducing the time taken
+	// to load the schema when the generated package is initialised. It is
+	// only used when GenerateJSONSchema is set.
+	BinarySchema bool
+	// LazySchema specifies that the schema stored with the output code is
+	// not loaded when the generated package is initialised. Rather, each
+	// child of the schema root is unmarshalled when the schema of a struct
+	// within it is first required, reducing the memory and time used by
+	// programs that only use a small part of a large schema. It is only
+	// used when GenerateJSONSchema is set, and cannot be combined with
+	// BinarySchema.
+	LazySchema bool
 	// SchemaVarName is the name for the variable which stores the compressed
 	// JSON schema in the generated Go code. JSON schema output is only
 	// produced if the GenerateJSONSchema field is set to true.
@@ -57,6 +80,10 @@ type GoOpts struct {
 	// the generated structs. When set to true, a metadata field is added for each
 	// struct, and for each field of each struct. Metadata field's names are
 	// prefixed by the string specified in the AnnotationPrefix argument.
+	// A type implementing ygot.MetadataAnnotation is also generated for
+	// each metadata annotation defined using the md:annotation statement
+	// of RFC7952 within the input modules, such that the annotations can
+	// be marshalled to and unmarshalled from RFC7951 JSON.
 	AddAnnotationFields bool
 	// AnnotationPrefix specifies the string which is prefixed to the name of
 	// annotation fields. It defaults to Λ.
@@ -68,6 +95,25 @@ type GoOpts struct {
 	// a field tag of `yangPresence="true"` will only be added if the container is
 	// a YANG presence container, and will be omitted if this is not the case.
 	AddYangPresence bool
+	// AddOpenConfigVersionTags specifies whether a tag of the form
+	// `ocVersion:"<version>"` should be added to generated fields whose
+	// YANG node specifies the version of its module in which it was
+	// introduced through an oc-ext:openconfig-version statement.
+	AddOpenConfigVersionTags bool
+	// AddModulePrefixTags specifies whether a tag of the form
+	// `module-prefix:"<prefixes>"` should be added to generated fields,
+	// mirroring the module tag but containing the prefix specified by
+	// the prefix statement of each module rather than its name. The tag
+	// is used to qualify JSON members with module prefixes when
+	// rendering RFC7951 JSON with the UseModulePrefixes option.
+	AddModulePrefixTags bool
+	// AddKeylessListIndexTags specifies whether a tag of the form
+	// `keyless-index:"_index"` should be added to the fields representing
+	// keyless lists. The tag records the name of a synthetic key, whose
+	// value is the position of an entry within the list, such that
+	// ygot.TogNMINotifications and ygot.Diff can output the entries of
+	// the list. Without the tag, keyless lists cannot be output.
+	AddKeylessListIndexTags bool
 	// GenerateGetters specifies whether GetOrCreate* methods should be created
 	// for struct pointer (YANG container) and map (YANG list) fields of generated
 	// structs.
@@ -123,6 +169,115 @@ type GoOpts struct {
 	// marked `ordered-by user` will be represented using built-in Go maps
 	// instead of an ordered map Go structure.
 	GenerateOrderedListsAsUnorderedMaps bool
+	// GenerateStructRegistry specifies whether a registry of constructors
+	// for the generated structs, keyed by the YANG module to which each
+	// struct belongs and the YANG schema path of the container or list that
+	// it represents, should be generated.
+	// The registry allows code to create an instance of the GoStruct that
+	// corresponds to an arbitrary schema path at runtime.
+	GenerateStructRegistry bool
+	// GenerateGetOrCreateAt specifies whether a GetOrCreateAt method should
+	// be generated for the fake root, which retrieves the node at an
+	// arbitrary gNMI path, creating it along with the containers and list
+	// entries along the path if they do not exist. This allows a deeply
+	// nested node to be retrieved in a single call, rather than by chaining
+	// GetOrCreate* methods. The returned node can be converted to its type
+	// using ygot.GetOrCreateAt. The method is only generated when
+	// GenerateJSONSchema is set and a fake root is generated.
+	GenerateGetOrCreateAt bool
+	// GenerateCapabilities specifies whether a descriptor of the options
+	// with which the code was generated should be output, and returned by
+	// a ΛCapabilities method of each generated struct. The descriptor
+	// allows libraries to query the features supported by the generated
+	// code at runtime.
+	GenerateCapabilities bool
+	// GenerateDeltaStructs specifies whether a companion delta type should
+	// be generated for each struct. The delta type wraps the struct, and
+	// provides Set, Clear and Has methods for each leaf field that record
+	// in a bitmap whether the field was explicitly set, such that it
+	// implements ygot.DeltaStruct.
+	GenerateDeltaStructs bool
+	// CapabilitiesWildcardPaths records in the capability descriptor that
+	// path structs, including wildcard paths, are generated together with
+	// the GoStructs. It is only used when GenerateCapabilities is set.
+	CapabilitiesWildcardPaths bool
+	// GeneratePackageDoc specifies whether the documentation of the
+	// generated package should be output separately, as the contents of a
+	// doc.go file, rather than within the header of each generated file.
+	// The documentation lists the YANG modules from which the package was
+	// generated along with their revisions, the options with which it was
+	// generated, and the schema paths that it covers, in a form that can be
+	// parsed using ParsePackageDoc.
+	GeneratePackageDoc bool
+	// GenerateUnionConstructors specifies whether functions that construct
+	// the values of multi-type unions should be generated. For each union,
+	// a function named <Union>From<Type> is generated for each of its
+	// subtypes, which accepts only a value of that subtype, along with a
+	// function named <Union>From, which accepts any value and returns an
+	// error if it cannot be converted to a type within the union.
+	GenerateUnionConstructors bool
+	// GenerateStringMethod specifies whether a String method should be
+	// generated for each struct, which returns the compact RFC7951 JSON
+	// representation of the struct with sorted keys, such that its output
+	// is stable. The values of leaves that are marked with the
+	// ietf-netconf-acm:default-deny-all extension, or whose schema path is
+	// within RedactedSchemaPaths, are replaced by ygot.RedactedValue. The
+	// method is not generated for structs that have a field named String.
+	GenerateStringMethod bool
+	// RedactedSchemaPaths is a set of YANG schema paths, without module
+	// prefixes, of leaves whose values are redacted by the String method
+	// generated when GenerateStringMethod is set, e.g.,
+	// "/system/aaa/authentication/users/user/config/password".
+	RedactedSchemaPaths []string
+	// GenerateDeepCopy specifies whether a DeepCopy method should be
+	// generated for each struct, which copies the struct field-by-field
+	// rather than by using reflection. A ΛDeepCopy method is also
+	// generated, such that ygot.DeepCopy uses the generated code to copy
+	// the struct.
+	GenerateDeepCopy bool
+	// GenerateDecimal64Type specifies whether leaves, leaf-lists and union
+	// subtypes with a YANG type of decimal64 should be represented by the
+	// ygot.Decimal64 fixed-point type, which represents their values
+	// exactly, rather than float64. Decimal64 leaves are not pointers, since
+	// the zero value of ygot.Decimal64 indicates that the leaf is unset.
+	GenerateDecimal64Type bool
+	// BinaryType is a reference to a user-specified Go type, of the form
+	// "<import path>.<type name>", e.g., "example.com/lazy.Binary", that is
+	// used in place of the generated Binary type for leaves with a YANG type
+	// of binary. The type must implement ygot.BinaryValue, and a pointer to
+	// it ygot.BinaryValueSetter, and its zero value, which must be
+	// comparable, indicates that the leaf is unset. Leaf-lists and union
+	// subtypes continue to use the Binary type, and default values are not
+	// generated for leaves of the user-specified type.
+	BinaryType string
+	// EmptyType is a reference to a user-specified Go type, of the same
+	// form as BinaryType, that is used in place of the generated YANGEmpty
+	// type for leaves with a YANG type of empty. The type must implement
+	// ygot.EmptyValue, and a pointer to it ygot.EmptyValueSetter, and its
+	// zero value, which must be comparable, indicates that the leaf is
+	// not present.
+	EmptyType string
+	// SplitByModule specifies whether the generated code should also be
+	// output as a set of Go packages, with one package containing the
+	// structs for the schema tree instantiated by each YANG module. The
+	// enumerated types, unions, and schema are output into a common
+	// package, and the fake root into the package named PackageName.
+	SplitByModule bool
+	// TrimPackagePrefix is removed from the names of YANG modules when
+	// determining the names of the Go packages when SplitByModule is set,
+	// e.g., "openconfig-".
+	TrimPackagePrefix string
+	// CommonUnionTypes specifies whether the interfaces and wrapper types
+	// generated for unions are output into the common package when
+	// SplitByModule is set. Otherwise, each union type is output into
+	// the package of the first struct that uses it, and must be
+	// referenced through that package by the structs of other packages.
+	CommonUnionTypes bool
+	// BaseImportPath is the import path of the directory containing the
+	// generated packages when SplitByModule is set. The package named
+	// PackageName is expected to be imported using this path, and every
+	// other package using <BaseImportPath>/<package name>.
+	BaseImportPath string
 }
 
 // GeneratedCode contains generated code snippets that can be processed by the calling
@@ -159,6 +314,24 @@ type GeneratedCode struct {
 	RawJSONSchema []byte
 	// EnumTypeMap is a Go map that allows YANG schemapaths to be mapped to reflect.Type values.
 	EnumTypeMap string
+	// StructRegistry is a Go map, keyed by YANG module name, of maps that
+	// allow the YANG schemapaths of the module to be mapped to a function
+	// that returns a new instance of the corresponding GoStruct.
+	StructRegistry string
+	// Capabilities is a variable describing the options with which the
+	// code was generated.
+	Capabilities string
+	// MetadataAnnotations contains the types representing the RFC7952
+	// metadata annotations defined within the input YANG modules, and a
+	// map of the types keyed by qualified name, when the
+	// AddAnnotationFields option is set.
+	MetadataAnnotations string
+	// PackageDoc is the contents of the doc.go file documenting the
+	// generated package, when the GeneratePackageDoc option is set.
+	PackageDoc string
+	// Packages stores the source code of each generated Go package, keyed
+	// by package name, when the SplitByModule option is set.
+	Packages map[string]string
 }
 
 // New returns a new instance of the CodeGenerator
@@ -204,20 +377,57 @@ func checkForBinaryKeys(dir *ygen.ParsedDirectory) []error {
 //
 // If errors are encountered during code generation, an error is returned.
 func (cg *CodeGenerator) Generate(yangFiles, includePaths []string) (*GeneratedCode, util.Errors) {
-	opts := ygen.IROptions{
+	ir, err := ygen.GenerateIR(yangFiles, includePaths, cg.langMapper(), cg.irOptions())
+	if err != nil {
+		return nil, util.NewErrs(err)
+	}
+
+	return cg.GenerateFromIR(ir, yangFiles, includePaths)
+}
+
+// GenerateFromEntries generates Go code for the supplied modules, which have
+// already been parsed by the caller, returning the same GeneratedCode as
+// Generate. The modules must satisfy the requirements of
+// ygen.GenerateIRFromEntries, and are transformed in place such that they
+// must not be reused.
+func (cg *CodeGenerator) GenerateFromEntries(modules []*yang.Entry) (*GeneratedCode, util.Errors) {
+	ir, err := ygen.GenerateIRFromEntries(modules, cg.langMapper(), cg.irOptions())
+	if err != nil {
+		return nil, util.NewErrs(err)
+	}
+
+	return cg.GenerateFromIR(ir, nil, nil)
+}
+
+// langMapper returns the GoLangMapper that is used to generate the IR from
+// which code is generated, according to the Go options of the CodeGenerator.
+func (cg *CodeGenerator) langMapper() *GoLangMapper {
+	m := NewGoLangMapper(cg.GoOptions.GenerateSimpleUnions)
+	m.decimal64Type = cg.GoOptions.GenerateDecimal64Type
+	return m
+}
+
+// irOptions returns the IROptions that are used to generate the IR from which
+// Go code is generated by cg.
+func (cg *CodeGenerator) irOptions() ygen.IROptions {
+	return ygen.IROptions{
 		ParseOptions:                        cg.IROptions.ParseOptions,
 		TransformationOptions:               cg.IROptions.TransformationOptions,
 		NestedDirectories:                   false,
 		AbsoluteMapPaths:                    false,
 		AppendEnumSuffixForSimpleUnionEnums: cg.GoOptions.AppendEnumSuffixForSimpleUnionEnums,
 	}
+}
 
+// GenerateFromIR generates Go code for the supplied IR, returning the same
+// GeneratedCode as Generate. It allows an IR that has already been generated
+// to be shared by multiple code generators. The IR must have been generated
+// using a LangMapper that produces the same names as GoLangMapper, with the
+// IROptions of cg and NestedDirectories and AbsoluteMapPaths unset.
+// yangFiles and includePaths are the inputs from which the IR was generated,
+// and are recorded in the header of the generated code.
+func (cg *CodeGenerator) GenerateFromIR(ir *ygen.IR, yangFiles, includePaths []string) (*GeneratedCode, util.Errors) {
 	var codegenErr util.Errors
-	ir, err := ygen.GenerateIR(yangFiles, includePaths, NewGoLangMapper(cg.GoOptions.GenerateSimpleUnions), opts)
-	if err != nil {
-		return nil, util.AppendErr(codegenErr, err)
-	}
-
 	var rootName string
 	if cg.IROptions.TransformationOptions.GenerateFakeRoot {
 		rootName = cg.IROptions.TransformationOptions.FakeRootName
@@ -241,6 +451,17 @@ func (cg *CodeGenerator) Generate(yangFiles, includePaths []string) (*GeneratedC
 	generatedUnions := map[string]bool{}
 	enumTypeMap := map[string][]string{}
 	structSnippets := []GoStructCodeSnippet{}
+	// structRegistry stores a map, keyed by the YANG schema path of each
+	// directory, of the name of the struct generated for the directory.
+	structRegistry := map[string]string{}
+	// structModules stores a map, keyed by the YANG schema path of each
+	// directory, of the name of the module to which the directory belongs.
+	structModules := map[string]string{}
+
+	// metadataAnnotated indicates that annotation fields can be populated
+	// with the generated types of the metadata annotations defined within
+	// the input modules.
+	metadataAnnotated := cg.GoOptions.AddAnnotationFields && len(ir.MetadataAnnotations) != 0
 
 	isBuiltInType := func(fType string) bool {
 		_, ok := validGoBuiltinTypes[fType]
@@ -258,12 +479,37 @@ func (cg *CodeGenerator) Generate(yangFiles, includePaths []string) (*GeneratedC
 			codegenErr = util.AppendErrs(codegenErr, errs)
 			continue
 		}
-		structOut, errs := writeGoStruct(dir, ir.Directories, generatedUnions, cg.GoOptions)
+		structOut, errs := writeGoStruct(dir, ir.Directories, generatedUnions, ir.ModulePrefix, cg.GoOptions)
 		if errs != nil {
 			codegenErr = util.AppendErrs(codegenErr, errs)
 			continue
 		}
+		if len(cg.Plugins) != 0 {
+			code, errs := pluginCode(cg.Plugins, ir, dir)
+			if errs != nil {
+				codegenErr = util.AppendErrs(codegenErr, errs)
+				continue
+			}
+			structOut.Methods += code
+		}
+		if metadataAnnotated {
+			var b bytes.Buffer
+			if err := goMetadataAnnotationTypeMapTemplate.Execute(&b, dir.Name); err != nil {
+				codegenErr = util.AppendErr(codegenErr, err)
+				continue
+			}
+			structOut.Methods += b.String()
+		}
 		structSnippets = append(structSnippets, structOut)
+		if dir.IsFakeRoot {
+			// The fake root does not have a schema path, and is registered
+			// as the root of the schema tree.
+			structRegistry["/"] = dir.Name
+			structModules["/"] = dir.BelongingModule
+		} else {
+			structRegistry[dir.SchemaPath] = dir.Name
+			structModules[dir.SchemaPath] = dir.BelongingModule
+		}
 
 		// Record down all the enum types we encounter in each field.
 
@@ -331,7 +577,7 @@ func (cg *CodeGenerator) Generate(yangFiles, includePaths []string) (*GeneratedC
 		}
 
 		if rawSchema != nil {
-			if jsonSchema, err = writeGoSchema(rawSchema, cg.GoOptions.SchemaVarName); err != nil {
+			if jsonSchema, err = writeGoSchema(rawSchema, cg.GoOptions.SchemaVarName, cg.GoOptions.BinarySchema, cg.GoOptions.LazySchema); err != nil {
 				codegenErr = util.AppendErr(codegenErr, err)
 			}
 		}
@@ -341,21 +587,166 @@ func (cg *CodeGenerator) Generate(yangFiles, includePaths []string) (*GeneratedC
 		}
 	}
 
+	var structRegistryCode string
+	if cg.GoOptions.GenerateStructRegistry {
+		if structRegistryCode, err = generateStructRegistry(structRegistry, structModules); err != nil {
+			codegenErr = util.AppendErr(codegenErr, err)
+		}
+	}
+
+	var capabilitiesCode string
+	if cg.GoOptions.GenerateCapabilities {
+		if capabilitiesCode, err = generateCapabilities(cg.IROptions.TransformationOptions.CompressBehaviour, cg.GoOptions); err != nil {
+			codegenErr = util.AppendErr(codegenErr, err)
+		}
+	}
+
+	var metadataAnnotationsCode string
+	if metadataAnnotated {
+		if metadataAnnotationsCode, err = generateMetadataAnnotations(ir.MetadataAnnotations); err != nil {
+			codegenErr = util.AppendErr(codegenErr, err)
+		}
+	}
+
+	var packageDoc string
+	if cg.GoOptions.GeneratePackageDoc {
+		if packageDoc, err = writePackageDoc(generatePackageDoc(cg, ir, structRegistry), yangFiles, includePaths, cg); err != nil {
+			codegenErr = util.AppendErr(codegenErr, err)
+		}
+	}
+
 	// Return any errors that were encountered during code generation.
 	if len(codegenErr) != 0 {
 		return nil, codegenErr
 	}
 
-	return &GeneratedCode{
-		CommonHeader:   commonHeader,
-		OneOffHeader:   oneoffHeader,
-		Structs:        structSnippets,
-		Enums:          genum.enums,
-		EnumMap:        genum.valMap,
-		JSONSchemaCode: jsonSchema,
-		RawJSONSchema:  rawSchema,
-		EnumTypeMap:    enumTypeMapCode,
-	}, nil
+	gc := &GeneratedCode{
+		CommonHeader:        commonHeader,
+		OneOffHeader:        oneoffHeader,
+		Structs:             structSnippets,
+		Enums:               genum.enums,
+		EnumMap:             genum.valMap,
+		JSONSchemaCode:      jsonSchema,
+		RawJSONSchema:       rawSchema,
+		EnumTypeMap:         enumTypeMapCode,
+		StructRegistry:      structRegistryCode,
+		Capabilities:        capabilitiesCode,
+		MetadataAnnotations: metadataAnnotationsCode,
+		PackageDoc:          packageDoc,
+	}
+
+	if cg.GoOptions.SplitByModule {
+		if gc.Packages, err = splitByModule(gc, ir, cg.GoOptions); err != nil {
+			return nil, util.NewErrs(err)
+		}
+	}
+
+	return gc, nil
+}
+
+// generateStructRegistry outputs a map using the structRegistry template. It
+// takes an input of a map, keyed by schema path, to the name of the struct
+// that was generated for the container or list at the schema path, and a map,
+// keyed by schema path, to the name of the module to which the container or
+// list belongs. The map generated allows a module name and schemapath to be
+// mapped to a function that returns a new instance of the struct.
+func generateStructRegistry(structRegistry, structModules map[string]string) (string, error) {
+	byModule := map[string]map[string]string{}
+	for path, name := range structRegistry {
+		m := structModules[path]
+		if byModule[m] == nil {
+			byModule[m] = map[string]string{}
+		}
+		byModule[m][path] = name
+	}
+	var buf bytes.Buffer
+	if err := goStructRegistryTemplate.Execute(&buf, byModule); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// generateCapabilities outputs a variable describing the options with which
+// the code was generated using the capabilities template.
+func generateCapabilities(compressBehaviour genutil.CompressBehaviour, goOpts GoOpts) (string, error) {
+	var buf bytes.Buffer
+	if err := goCapabilitiesTemplate.Execute(&buf, ygot.Capabilities{
+		CompressBehaviour: compressBehaviour.String(),
+		Compressed:        compressBehaviour.CompressEnabled(),
+		SimpleUnions:      goOpts.GenerateSimpleUnions,
+		OrderedMaps:       !goOpts.GenerateOrderedListsAsUnorderedMaps,
+		SchemaEmbedded:    goOpts.GenerateJSONSchema,
+		Getters:           goOpts.GenerateGetters,
+		WildcardPaths:     goOpts.CapabilitiesWildcardPaths,
+		GeneratorVersion:  genutil.YgotVersion(),
+	}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// generateMetadataAnnotations outputs the types representing the supplied
+// metadata annotations, along with a map of the types keyed by the qualified
+// name of each annotation, using the metadataAnnotations template. The value
+// of each annotation is represented by the Go type corresponding to its base
+// YANG type, or by a json.RawMessage where there is no single such type.
+func generateMetadataAnnotations(annotations []*ygen.MetadataAnnotation) (string, error) {
+	type metadataAnnotation struct {
+		Name        string
+		Module      string
+		Description []string
+		TypeName    string
+		GoType      string
+	}
+
+	var as []*metadataAnnotation
+	names := map[string]bool{}
+	for _, a := range annotations {
+		name := fmt.Sprintf("Annotation_%s_%s", yang.CamelCase(a.DefiningModule), yang.CamelCase(a.Name))
+		if names[name] {
+			return "", fmt.Errorf("metadata annotation %s:%s has the same type name as another annotation, %s", a.DefiningModule, a.Name, name)
+		}
+		names[name] = true
+		var desc []string
+		if a.Description != "" {
+			for _, l := range strings.Split(a.Description, "\n") {
+				desc = append(desc, strings.TrimSpace(l))
+			}
+		}
+		as = append(as, &metadataAnnotation{
+			Name:        a.Name,
+			Module:      a.DefiningModule,
+			Description: desc,
+			TypeName:    name,
+			GoType:      metadataAnnotationGoType(a.Kind),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := goMetadataAnnotationsTemplate.Execute(&buf, as); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// metadataAnnotationGoType returns the Go type that is used to represent the
+// value of a metadata annotation with the supplied base YANG type.
+func metadataAnnotationGoType(kind yang.TypeKind) string {
+	switch kind {
+	case yang.Yint8, yang.Yint16, yang.Yint32, yang.Yint64, yang.Yuint8, yang.Yuint16, yang.Yuint32, yang.Yuint64:
+		return yang.TypeKindToName[kind]
+	case yang.Ydecimal64:
+		return "float64"
+	case yang.Ybool:
+		return "bool"
+	case yang.Ystring, yang.Yenum, yang.Yidentityref, yang.Ybits, yang.Ybinary, yang.YinstanceIdentifier:
+		// These types are encoded as JSON strings by RFC7951.
+		return "string"
+	default:
+		// Unions, leafrefs and empty values do not have a single
+		// corresponding Go type.
+		return "json.RawMessage"
+	}
 }
 
 // generateEnumTypeMap outputs a map using the enumTypeMap template. It takes an
@@ -372,11 +763,40 @@ func generateEnumTypeMap(enumTypeMap map[string][]string) (string, error) {
 
 // writeGoSchema generates Go code which serialises the rawSchema byte slice
 // provided and stores it in a variable which can be written out to the generated
-// Go code file.
-func writeGoSchema(js []byte, schemaVarName string) (string, error) {
-	jbyte, err := ygen.WriteGzippedByteSlice(js)
-	if err != nil {
-		return "", fmt.Errorf("could not write Byte slice: %v", err)
+// Go code file. If binary is set, the schema is stored using the binary
+// representation loaded by ytypes.LoadSchemaBinary, otherwise the JSON schema
+// is stored gzip compressed. If lazy is set, the ytypes.LazySchemaIndex that
+// is used to load the schema on demand is also output.
+func writeGoSchema(js []byte, schemaVarName string, binary, lazy bool) (string, error) {
+	var (
+		jbyte []byte
+		err   error
+		idx   *ytypes.LazySchemaIndex
+	)
+	if binary && lazy {
+		return "", fmt.Errorf("a lazily loaded schema cannot be stored in binary form")
+	}
+	if lazy {
+		root := &yang.Entry{}
+		if err := json.Unmarshal(js, root); err != nil {
+			return "", fmt.Errorf("could not unmarshal JSON schema: %v", err)
+		}
+		if idx, err = ytypes.NewLazySchemaIndex(root); err != nil {
+			return "", fmt.Errorf("could not index schema: %v", err)
+		}
+	}
+	if binary {
+		root := &yang.Entry{}
+		if err := json.Unmarshal(js, root); err != nil {
+			return "", fmt.Errorf("could not unmarshal JSON schema: %v", err)
+		}
+		if jbyte, err = ytypes.MarshalSchemaBinary(root); err != nil {
+			return "", fmt.Errorf("could not write binary schema: %v", err)
+		}
+	} else {
+		if jbyte, err = ygen.WriteGzippedByteSlice(js); err != nil {
+			return "", fmt.Errorf("could not write Byte slice: %v", err)
+		}
 	}
 
 	vn := defaultSchemaVarName
@@ -385,11 +805,15 @@ func writeGoSchema(js []byte, schemaVarName string) (string, error) {
 	}
 
 	in := struct {
-		VarName string
-		Schema  []string
+		VarName   string
+		Schema    []string
+		Binary    bool
+		LazyIndex *ytypes.LazySchemaIndex
 	}{
-		VarName: vn,
-		Schema:  ygen.BytesToGoByteSlice(jbyte),
+		VarName:   vn,
+		Schema:    ygen.BytesToGoByteSlice(jbyte),
+		Binary:    binary,
+		LazyIndex: idx,
 	}
 
 	var buf bytes.Buffer
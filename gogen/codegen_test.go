@@ -7,14 +7,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/openconfig/gnmi/errdiff"
 	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/openconfig/ygot/genutil"
 	"github.com/openconfig/ygot/testutil"
 	"github.com/openconfig/ygot/ygen"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
 )
 
 const (
@@ -75,6 +79,25 @@ func TestSimpleStructs(t *testing.T) {
 			},
 		},
 		wantStructsCodeFile: filepath.Join(TestRoot, "testdata/structs/openconfig-simple.formatted-txt"),
+	}, {
+		name:    "simple openconfig test, with compression, with struct registry",
+		inFiles: []string{filepath.Join(datapath, "openconfig-simple.yang")},
+		inConfig: CodeGenerator{
+			IROptions: ygen.IROptions{
+				TransformationOptions: ygen.TransformationOpts{
+					CompressBehaviour:                    genutil.PreferIntendedConfig,
+					GenerateFakeRoot:                     true,
+					ShortenEnumLeafNames:                 true,
+					UseDefiningModuleForTypedefEnumNames: true,
+					EnumerationsUseUnderscores:           true,
+				},
+			},
+			GoOptions: GoOpts{
+				GenerateSimpleUnions:   true,
+				GenerateStructRegistry: true,
+			},
+		},
+		wantStructsCodeFile: filepath.Join(TestRoot, "testdata/structs/openconfig-simple-struct-registry.formatted-txt"),
 	}, {
 		name:    "simple openconfig test, with excluded state, with compression, with enum org name trimming",
 		inFiles: []string{filepath.Join(datapath, "openconfig-simple.yang")},
@@ -276,6 +299,25 @@ func TestSimpleStructs(t *testing.T) {
 			},
 		},
 		wantStructsCodeFile: filepath.Join(TestRoot, "testdata", "structs", "openconfig-simple-annotations.formatted-txt"),
+	}, {
+		name:           "OpenConfig schema test - with metadata annotations",
+		inFiles:        []string{filepath.Join(datapath, "openconfig-simple-metadata.yang")},
+		inIncludePaths: []string{datapath},
+		inConfig: CodeGenerator{
+			IROptions: ygen.IROptions{
+				TransformationOptions: ygen.TransformationOpts{
+					CompressBehaviour:                    genutil.PreferIntendedConfig,
+					ShortenEnumLeafNames:                 true,
+					UseDefiningModuleForTypedefEnumNames: true,
+					EnumerationsUseUnderscores:           true,
+				},
+			},
+			GoOptions: GoOpts{
+				AddAnnotationFields:  true,
+				GenerateSimpleUnions: true,
+			},
+		},
+		wantStructsCodeFile: filepath.Join(TestRoot, "testdata", "structs", "openconfig-simple-metadata.formatted-txt"),
 	}, {
 		name:    "OpenConfig schema test - list and associated method (rename, new)",
 		inFiles: []string{filepath.Join(datapath, "openconfig-withlist.yang")},
@@ -1165,6 +1207,8 @@ func TestSimpleStructs(t *testing.T) {
 
 				// Write generated enumeration map out.
 				fmt.Fprint(&gotCode, gotGeneratedCode.EnumMap)
+				fmt.Fprint(&gotCode, gotGeneratedCode.StructRegistry)
+				fmt.Fprint(&gotCode, gotGeneratedCode.MetadataAnnotations)
 
 				var gotJSON map[string]interface{}
 				if tt.inConfig.GoOptions.GenerateJSONSchema {
@@ -1239,3 +1283,950 @@ func TestSimpleStructs(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateCapabilities(t *testing.T) {
+	cg := New("", ygen.IROptions{
+		TransformationOptions: ygen.TransformationOpts{
+			CompressBehaviour:          genutil.PreferOperationalState,
+			GenerateFakeRoot:           true,
+			EnumerationsUseUnderscores: true,
+		},
+	}, GoOpts{
+		GenerateJSONSchema:        true,
+		GenerateSimpleUnions:      true,
+		GenerateCapabilities:      true,
+		CapabilitiesWildcardPaths: true,
+	})
+	got, errs := cg.Generate([]string{filepath.Join(datapath, "openconfig-simple.yang")}, nil)
+	if errs != nil {
+		t.Fatalf("Generate: got unexpected errors: %v", errs)
+	}
+
+	for _, want := range []string{
+		`CompressBehaviour: "PreferOperationalState",`,
+		`Compressed:        true,`,
+		`SimpleUnions:      true,`,
+		`OrderedMaps:       true,`,
+		`SchemaEmbedded:    true,`,
+		`Getters:           false,`,
+		`WildcardPaths:     true,`,
+		fmt.Sprintf(`GeneratorVersion:  %q,`, genutil.YgotVersion()),
+	} {
+		if !strings.Contains(got.Capabilities, want) {
+			t.Errorf("Generate: capabilities do not contain %q, got:\n%s", want, got.Capabilities)
+		}
+	}
+	for _, s := range got.Structs {
+		if want := fmt.Sprintf("func (*%s) ΛCapabilities() *ygot.Capabilities { return ΛCapabilitiesDescriptor }", s.StructName); !strings.Contains(s.Methods, want) {
+			t.Errorf("Generate: methods of struct %s do not contain %q", s.StructName, want)
+		}
+	}
+}
+
+func TestGenerateDeltaStructs(t *testing.T) {
+	cg := New("", ygen.IROptions{
+		TransformationOptions: ygen.TransformationOpts{
+			CompressBehaviour:          genutil.PreferIntendedConfig,
+			GenerateFakeRoot:           true,
+			EnumerationsUseUnderscores: true,
+		},
+	}, GoOpts{
+		GenerateSimpleUnions: true,
+		GenerateDeltaStructs: true,
+	})
+	got, errs := cg.Generate([]string{filepath.Join(datapath, "openconfig-simple.yang")}, nil)
+	if errs != nil {
+		t.Fatalf("Generate: got unexpected errors: %v", errs)
+	}
+
+	for _, s := range got.Structs {
+		if s.StructName != "Parent_Child" {
+			continue
+		}
+		for _, want := range []string{
+			"type Parent_Child_Delta struct {",
+			`return d.ΛPresence.Names([]string{"Four", "One", "Three", "Two"})`,
+			"func (d *Parent_Child_Delta) SetOne(v string) {",
+			"d.Struct.One = &v\n\td.ΛPresence.Set(1)",
+			"func (d *Parent_Child_Delta) ClearOne() {",
+			"d.Struct.One = nil\n\t}\n\td.ΛPresence.Clear(1)",
+			"func (d *Parent_Child_Delta) HasOne() bool {",
+			"d.Struct.Three = 0\n\t}\n\td.ΛPresence.Clear(2)",
+		} {
+			if !strings.Contains(s.Methods, want) {
+				t.Errorf("Generate: methods of struct %s do not contain %q, got:\n%s", s.StructName, want, s.Methods)
+			}
+		}
+		return
+	}
+	t.Errorf("Generate: did not generate struct Parent_Child")
+}
+
+func TestGenerateUnionConstructors(t *testing.T) {
+	tests := []struct {
+		name         string
+		simpleUnions bool
+		want         []string
+	}{{
+		name:         "simple unions",
+		simpleUnions: true,
+		want: []string{
+			"func Outer_Inner_Leaf1_UnionFrom(i interface{}) (Outer_Inner_Leaf1_Union, error) {\n\treturn (*Outer_Inner)(nil).To_Outer_Inner_Leaf1_Union(i)\n}",
+			"func Outer_Inner_Leaf1_UnionFromE_Inner_Leaf1(v E_Inner_Leaf1) Outer_Inner_Leaf1_Union { return v }",
+			"func Outer_Inner_Leaf1_UnionFromUint64(v uint64) Outer_Inner_Leaf1_Union { return UnionUint64(v) }",
+		},
+	}, {
+		name: "wrapper unions",
+		want: []string{
+			"func Outer_Inner_Leaf1_UnionFrom(i interface{}) (Outer_Inner_Leaf1_Union, error) {\n\treturn (*Outer_Inner)(nil).To_Outer_Inner_Leaf1_Union(i)\n}",
+			"func Outer_Inner_Leaf1_UnionFromE_Inner_Leaf1(v E_Inner_Leaf1) Outer_Inner_Leaf1_Union { return &Outer_Inner_Leaf1_Union_E_Inner_Leaf1{v} }",
+			"func Outer_Inner_Leaf1_UnionFromUint64(v uint64) Outer_Inner_Leaf1_Union { return &Outer_Inner_Leaf1_Union_Uint64{v} }",
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cg := New("", ygen.IROptions{
+				TransformationOptions: ygen.TransformationOpts{
+					CompressBehaviour:                    genutil.PreferIntendedConfig,
+					ShortenEnumLeafNames:                 true,
+					UseDefiningModuleForTypedefEnumNames: true,
+					EnumerationsUseUnderscores:           true,
+				},
+			}, GoOpts{
+				GenerateSimpleUnions:      tt.simpleUnions,
+				GenerateUnionConstructors: true,
+			})
+			got, errs := cg.Generate([]string{filepath.Join(datapath, "enum-union.yang")}, []string{datapath})
+			if errs != nil {
+				t.Fatalf("Generate: got unexpected errors: %v", errs)
+			}
+
+			var interfaces strings.Builder
+			for _, s := range got.Structs {
+				interfaces.WriteString(s.Interfaces)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(interfaces.String(), want) {
+					t.Errorf("Generate: union definitions do not contain %q, got:\n%s", want, interfaces.String())
+				}
+			}
+			// Constructors are output once per union, regardless of the
+			// number of structs that use it.
+			if got := strings.Count(interfaces.String(), "func Outer_Inner_Leaf1_UnionFrom("); got != 1 {
+				t.Errorf("Generate: got %d generic constructors for Outer_Inner_Leaf1_Union, want 1", got)
+			}
+		})
+	}
+}
+
+func TestGenerateDeepCopy(t *testing.T) {
+	tests := []struct {
+		name         string
+		simpleUnions bool
+		want         []string
+	}{{
+		name:         "simple unions",
+		simpleUnions: true,
+		want: []string{
+			"func (t *Outer) DeepCopy() *Outer {",
+			"n.Inner = t.Inner.DeepCopy()",
+			"func (t *Outer_Inner) DeepCopy() *Outer_Inner {",
+			"n.Leaf1 = t.Leaf1\n",
+			"func (t *Outer_Inner) ΛDeepCopy() ygot.GoStruct { return t.DeepCopy() }",
+		},
+	}, {
+		name: "wrapper unions",
+		want: []string{
+			"func (t *Outer) DeepCopy() *Outer {",
+			"n.Inner = t.Inner.DeepCopy()",
+			"func (t *Outer_Inner) DeepCopy() *Outer_Inner {",
+			"switch v := t.Leaf1.(type) {\n\tcase *Outer_Inner_Leaf1_Union_E_Inner_Leaf1:\n\t\tn.Leaf1 = &Outer_Inner_Leaf1_Union_E_Inner_Leaf1{v.E_Inner_Leaf1}\n\tcase *Outer_Inner_Leaf1_Union_Uint64:\n\t\tn.Leaf1 = &Outer_Inner_Leaf1_Union_Uint64{v.Uint64}\n\tdefault:\n\t\tn.Leaf1 = t.Leaf1\n\t}",
+			"func (t *Outer_Inner) ΛDeepCopy() ygot.GoStruct { return t.DeepCopy() }",
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cg := New("", ygen.IROptions{
+				TransformationOptions: ygen.TransformationOpts{
+					CompressBehaviour:                    genutil.PreferIntendedConfig,
+					ShortenEnumLeafNames:                 true,
+					UseDefiningModuleForTypedefEnumNames: true,
+					EnumerationsUseUnderscores:           true,
+				},
+			}, GoOpts{
+				GenerateSimpleUnions: tt.simpleUnions,
+				GenerateDeepCopy:     true,
+			})
+			got, errs := cg.Generate([]string{filepath.Join(datapath, "enum-union.yang")}, []string{datapath})
+			if errs != nil {
+				t.Fatalf("Generate: got unexpected errors: %v", errs)
+			}
+
+			var methods strings.Builder
+			for _, s := range got.Structs {
+				methods.WriteString(s.Methods)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(methods.String(), want) {
+					t.Errorf("Generate: methods do not contain %q, got:\n%s", want, methods.String())
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateCustomBinaryEmptyTypes(t *testing.T) {
+	tests := []struct {
+		name             string
+		inOpts           GoOpts
+		wantHeader       []string
+		wantStructs      []string
+		wantErrSubstring string
+	}{{
+		name: "binary and empty types",
+		inOpts: GoOpts{
+			BinaryType:          "example.com/lazy.Binary",
+			EmptyType:           "example.com/presence.Flag",
+			GenerateLeafGetters: true,
+		},
+		wantHeader: []string{
+			`import custombinary "example.com/lazy"`,
+			`import customempty "example.com/presence"`,
+			"type CustomBinary = custombinary.Binary",
+			"type CustomEmpty = customempty.Flag",
+			"type Binary []byte",
+		},
+		wantStructs: []string{
+			"\tCustomBinary\t`path:\"config/four\"",
+			"\tCustomEmpty\t`path:\"config/e\"",
+			"func (t *Parent_Child) GetFour() CustomBinary {",
+		},
+	}, {
+		name:   "binary type only",
+		inOpts: GoOpts{BinaryType: "example.com/lazy.Binary"},
+		wantHeader: []string{
+			`import custombinary "example.com/lazy"`,
+			"type CustomBinary = custombinary.Binary",
+		},
+		wantStructs: []string{
+			"\tCustomBinary\t`path:\"config/four\"",
+			"\tYANGEmpty\t`path:\"config/e\"",
+		},
+	}, {
+		name:             "invalid type reference",
+		inOpts:           GoOpts{BinaryType: "example.com/lazy"},
+		wantErrSubstring: "not of the form",
+	}, {
+		name:             "unexported type",
+		inOpts:           GoOpts{EmptyType: "example.com/presence.flag"},
+		wantErrSubstring: "does not refer to an exported type",
+	}, {
+		name: "split by module",
+		inOpts: GoOpts{
+			BinaryType:    "example.com/lazy.Binary",
+			SplitByModule: true,
+		},
+		wantErrSubstring: "not supported when splitting code by module",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cg := New("", ygen.IROptions{
+				TransformationOptions: ygen.TransformationOpts{
+					CompressBehaviour: genutil.PreferIntendedConfig,
+				},
+			}, tt.inOpts)
+			got, errs := cg.Generate([]string{filepath.Join(datapath, "openconfig-simple.yang"), filepath.Join(datapath, "empty.yang")}, []string{datapath})
+			var err error
+			if errs != nil {
+				err = errs
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("Generate: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+
+			for _, want := range tt.wantHeader {
+				if !strings.Contains(got.OneOffHeader, want) {
+					t.Errorf("Generate: one-off header does not contain %q, got:\n%s", want, got.OneOffHeader)
+				}
+			}
+			var structs strings.Builder
+			for _, s := range got.Structs {
+				structs.WriteString(s.StructDef)
+				structs.WriteString(s.Methods)
+			}
+			for _, want := range tt.wantStructs {
+				if !strings.Contains(structs.String(), want) {
+					t.Errorf("Generate: structs do not contain %q, got:\n%s", want, structs.String())
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateDecimal64Type(t *testing.T) {
+	tests := []struct {
+		name          string
+		inOpts        GoOpts
+		wantHeader    []string
+		wantStructs   []string
+		notWantHeader []string
+	}{{
+		name: "simple unions",
+		inOpts: GoOpts{
+			GenerateDecimal64Type: true,
+			GenerateSimpleUnions:  true,
+			GenerateLeafGetters:   true,
+		},
+		wantHeader: []string{
+			"type Decimal64 = ygot.Decimal64",
+			"type UnionDecimal64 Decimal64",
+		},
+		wantStructs: []string{
+			"\tBandwidth\tDecimal64\t`path:\"config/bandwidth\"",
+			"\tWeights\t[]Decimal64\t`path:\"config/weights\"",
+			"return Decimal64{Digits: 150, Precision: 2}",
+			"case Decimal64:\n\t\treturn UnionDecimal64(v), nil",
+		},
+	}, {
+		name: "wrapper unions",
+		inOpts: GoOpts{
+			GenerateDecimal64Type: true,
+			GenerateLeafGetters:   true,
+		},
+		wantHeader: []string{
+			"type Decimal64 = ygot.Decimal64",
+		},
+		wantStructs: []string{
+			"\tBandwidth\tDecimal64\t`path:\"config/bandwidth\"",
+			"return Decimal64{Digits: 150, Precision: 2}",
+			"type Test_Threshold_Union_Decimal64 struct {\n\tDecimal64\tDecimal64\n}",
+		},
+		notWantHeader: []string{
+			"type UnionDecimal64 Decimal64",
+		},
+	}, {
+		name: "float64",
+		inOpts: GoOpts{
+			GenerateSimpleUnions: true,
+		},
+		wantStructs: []string{
+			"\tBandwidth\t*float64\t`path:\"config/bandwidth\"",
+			"\tWeights\t[]float64\t`path:\"config/weights\"",
+		},
+		notWantHeader: []string{
+			"type Decimal64 = ygot.Decimal64",
+			"type UnionDecimal64 Decimal64",
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cg := New("", ygen.IROptions{
+				TransformationOptions: ygen.TransformationOpts{
+					CompressBehaviour: genutil.PreferIntendedConfig,
+				},
+			}, tt.inOpts)
+			got, errs := cg.Generate([]string{filepath.Join(datapath, "decimal64.yang")}, []string{datapath})
+			if errs != nil {
+				t.Fatalf("Generate: got unexpected errors: %v", errs)
+			}
+
+			for _, want := range tt.wantHeader {
+				if !strings.Contains(got.OneOffHeader, want) {
+					t.Errorf("Generate: one-off header does not contain %q, got:\n%s", want, got.OneOffHeader)
+				}
+			}
+			for _, notWant := range tt.notWantHeader {
+				if strings.Contains(got.OneOffHeader, notWant) {
+					t.Errorf("Generate: one-off header unexpectedly contains %q, got:\n%s", notWant, got.OneOffHeader)
+				}
+			}
+			var structs strings.Builder
+			for _, s := range got.Structs {
+				structs.WriteString(s.StructDef)
+				structs.WriteString(s.Methods)
+				structs.WriteString(s.Interfaces)
+			}
+			for _, want := range tt.wantStructs {
+				if !strings.Contains(structs.String(), want) {
+					t.Errorf("Generate: structs do not contain %q, got:\n%s", want, structs.String())
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateModulePrefixTags(t *testing.T) {
+	tests := []struct {
+		name          string
+		inOpts        GoOpts
+		wantStructs   []string
+		notWantStruct string
+	}{{
+		name:   "module prefix tags",
+		inOpts: GoOpts{AddModulePrefixTags: true},
+		wantStructs: []string{
+			"`path:\"target\" module:\"openconfig-simple-target\" module-prefix:\"t\"`",
+			"`path:\"foo\" module:\"openconfig-simple-augment\" module-prefix:\"a\"`",
+			"`path:\"state/b\" module:\"openconfig-simple-target/openconfig-simple-augment\" module-prefix:\"t/a\"`",
+		},
+	}, {
+		name: "no module prefix tags",
+		wantStructs: []string{
+			"`path:\"foo\" module:\"openconfig-simple-augment\"`",
+		},
+		notWantStruct: "module-prefix:",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cg := New("", ygen.IROptions{
+				TransformationOptions: ygen.TransformationOpts{
+					CompressBehaviour: genutil.PreferIntendedConfig,
+					GenerateFakeRoot:  true,
+				},
+			}, tt.inOpts)
+			got, errs := cg.Generate([]string{
+				filepath.Join(datapath, "openconfig-simple-target.yang"),
+				filepath.Join(datapath, "openconfig-simple-augment.yang"),
+			}, []string{datapath})
+			if errs != nil {
+				t.Fatalf("Generate: got unexpected errors: %v", errs)
+			}
+
+			var structs strings.Builder
+			for _, s := range got.Structs {
+				structs.WriteString(s.StructDef)
+			}
+			for _, want := range tt.wantStructs {
+				if !strings.Contains(structs.String(), want) {
+					t.Errorf("Generate: structs do not contain %q, got:\n%s", want, structs.String())
+				}
+			}
+			if tt.notWantStruct != "" && strings.Contains(structs.String(), tt.notWantStruct) {
+				t.Errorf("Generate: structs unexpectedly contain %q, got:\n%s", tt.notWantStruct, structs.String())
+			}
+		})
+	}
+}
+
+func TestGenerateStructRegistryByModule(t *testing.T) {
+	cg := New("", ygen.IROptions{
+		TransformationOptions: ygen.TransformationOpts{
+			CompressBehaviour: genutil.PreferIntendedConfig,
+			GenerateFakeRoot:  true,
+		},
+	}, GoOpts{GenerateStructRegistry: true})
+	got, errs := cg.Generate([]string{
+		filepath.Join(datapath, "openconfig-simple-target.yang"),
+		filepath.Join(datapath, "openconfig-simple-augment.yang"),
+	}, []string{datapath})
+	if errs != nil {
+		t.Fatalf("Generate: got unexpected errors: %v", errs)
+	}
+
+	for _, want := range []string{
+		"\t\"\": {\n\t\t\"/\": func() ygot.GoStruct { return &Device{} },\n\t},",
+		"\t\"openconfig-simple-augment\": {\n\t\t\"/target/foo\": func() ygot.GoStruct { return &Target_Foo{} },\n\t},",
+		"\t\"openconfig-simple-target\": {\n\t\t\"/native\": func() ygot.GoStruct { return &Native{} },\n\t\t\"/target\": func() ygot.GoStruct { return &Target{} },\n\t},",
+	} {
+		if !strings.Contains(got.StructRegistry, want) {
+			t.Errorf("Generate: struct registry does not contain %q, got:\n%s", want, got.StructRegistry)
+		}
+	}
+}
+
+func TestGenerateWithDeviationModules(t *testing.T) {
+	cg := New("", ygen.IROptions{
+		ParseOptions: ygen.ParseOpts{
+			DeviationModules: []string{filepath.Join(datapath, "openconfig-simple-deviations.yang")},
+		},
+		TransformationOptions: ygen.TransformationOpts{
+			CompressBehaviour: genutil.PreferIntendedConfig,
+			GenerateFakeRoot:  true,
+		},
+	}, GoOpts{})
+	got, errs := cg.Generate([]string{filepath.Join(datapath, "openconfig-simple.yang")}, []string{datapath})
+	if errs != nil {
+		t.Fatalf("Generate: got unexpected errors: %v", errs)
+	}
+
+	var structs strings.Builder
+	for _, s := range got.Structs {
+		structs.WriteString(s.StructDef)
+	}
+	if want := "One\t*uint32"; !strings.Contains(structs.String(), want) {
+		t.Errorf("Generate: structs do not contain replaced field %q, got:\n%s", want, structs.String())
+	}
+	for _, absent := range []string{"Four", "Two", "OpenconfigSimpleDeviations"} {
+		if strings.Contains(structs.String(), absent) {
+			t.Errorf("Generate: structs contain %q, which was removed by a deviation, got:\n%s", absent, structs.String())
+		}
+	}
+}
+
+func TestGenerateRPCs(t *testing.T) {
+	rpcStructs := []string{
+		"type Reboot_Input struct",
+		"type Reboot_Output struct",
+		"type Interface_Reset_Input struct",
+		"type Interface_Reset_Input_Options struct",
+	}
+
+	for _, generateRPCs := range []bool{true, false} {
+		cg := New("", ygen.IROptions{
+			ParseOptions: ygen.ParseOpts{
+				GenerateRPCs: generateRPCs,
+			},
+			TransformationOptions: ygen.TransformationOpts{
+				CompressBehaviour: genutil.PreferIntendedConfig,
+				GenerateFakeRoot:  true,
+			},
+		}, GoOpts{GenerateJSONSchema: true})
+		got, errs := cg.Generate([]string{filepath.Join(datapath, "openconfig-rpc.yang")}, []string{datapath})
+		if errs != nil {
+			t.Fatalf("Generate(GenerateRPCs: %v): got unexpected errors: %v", generateRPCs, errs)
+		}
+
+		var structs strings.Builder
+		for _, s := range got.Structs {
+			structs.WriteString(s.StructDef)
+		}
+		for _, want := range rpcStructs {
+			if got := strings.Contains(structs.String(), want); got != generateRPCs {
+				t.Errorf("Generate(GenerateRPCs: %v): structs contain %q: %v, want: %v, got:\n%s", generateRPCs, want, got, generateRPCs, structs.String())
+			}
+		}
+		if !generateRPCs {
+			continue
+		}
+
+		gzj, err := ygen.WriteGzippedByteSlice(got.RawJSONSchema)
+		if err != nil {
+			t.Fatalf("WriteGzippedByteSlice: cannot compress schema: %v", err)
+		}
+		schema, err := ygot.GzipToSchema(gzj)
+		if err != nil {
+			t.Fatalf("GzipToSchema: cannot unmarshal schema: %v", err)
+		}
+		for name, wantKind := range map[string]yang.EntryKind{
+			"Reboot_Input":          yang.InputEntry,
+			"Reboot_Output":         yang.OutputEntry,
+			"Interface_Reset_Input": yang.InputEntry,
+		} {
+			e, ok := schema[name]
+			if !ok {
+				t.Errorf("GzipToSchema: schema for %s not found", name)
+				continue
+			}
+			if e.Kind != wantKind || e.Parent == nil || e.Parent.RPC == nil {
+				t.Errorf("GzipToSchema: schema for %s has kind %v and parent %v, want kind %v within an RPC or action", name, e.Kind, e.Parent, wantKind)
+			}
+		}
+	}
+}
+
+func TestGeneratePopulateDefaultsWhen(t *testing.T) {
+	cg := New("", ygen.IROptions{
+		TransformationOptions: ygen.TransformationOpts{
+			CompressBehaviour: genutil.PreferIntendedConfig,
+			GenerateFakeRoot:  true,
+		},
+	}, GoOpts{GeneratePopulateDefault: true})
+	got, errs := cg.Generate([]string{filepath.Join(datapath, "openconfig-when-defaults.yang")}, []string{datapath})
+	if errs != nil {
+		t.Fatalf("Generate: got unexpected errors: %v", errs)
+	}
+
+	methods := map[string]string{}
+	for _, s := range got.Structs {
+		methods[s.StructName] = s.Methods
+	}
+
+	tests := []struct {
+		desc      string
+		inStruct  string
+		wantIn    []string
+		wantNotIn []string
+	}{{
+		desc:     "container and leaf guarded by when statements",
+		inStruct: "Interface",
+		wantIn: []string{
+			"t.HoldTime = &Interface_HoldTime{}",
+			"var v uint16 = 1514",
+			"t.Ethernet.PopulateDefaults()",
+		},
+		wantNotIn: []string{
+			"ygot.BuildEmptyTree(t)",
+			"t.Ethernet = &Interface_Ethernet{}",
+			"t.LoopbackMode = &v",
+		},
+	}, {
+		desc:     "leaves within container guarded by when statement",
+		inStruct: "Interface_Ethernet",
+		wantIn: []string{
+			"ygot.BuildEmptyTree(t)",
+			"var v bool = true",
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			m, ok := methods[tt.inStruct]
+			if !ok {
+				t.Fatalf("Generate: did not get struct %s", tt.inStruct)
+			}
+			for _, w := range tt.wantIn {
+				if !strings.Contains(m, w) {
+					t.Errorf("Generate: methods of %s do not contain %q, got:\n%s", tt.inStruct, w, m)
+				}
+			}
+			for _, w := range tt.wantNotIn {
+				if strings.Contains(m, w) {
+					t.Errorf("Generate: methods of %s contain %q, got:\n%s", tt.inStruct, w, m)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateOpenConfigVersionTags(t *testing.T) {
+	tests := []struct {
+		desc      string
+		inOpts    GoOpts
+		wantIn    []string
+		wantNotIn []string
+	}{{
+		desc:      "tags not requested",
+		wantNotIn: []string{"ocVersion"},
+	}, {
+		desc:   "tags requested",
+		inOpts: GoOpts{AddOpenConfigVersionTags: true},
+		wantIn: []string{
+			`path:"config/domain-name" module:"openconfig-node-versions/openconfig-node-versions" ocVersion:"1.2.0"`,
+			`path:"config/timezone-name" module:"openconfig-node-versions/openconfig-node-versions" ocVersion:"2.0.0"`,
+		},
+		wantNotIn: []string{
+			`path:"config/hostname" module:"openconfig-node-versions/openconfig-node-versions" ocVersion`,
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			cg := New("", ygen.IROptions{
+				TransformationOptions: ygen.TransformationOpts{
+					CompressBehaviour: genutil.PreferIntendedConfig,
+					GenerateFakeRoot:  true,
+				},
+			}, tt.inOpts)
+			got, errs := cg.Generate([]string{filepath.Join(datapath, "openconfig-node-versions.yang")}, []string{datapath})
+			if errs != nil {
+				t.Fatalf("Generate: got unexpected errors: %v", errs)
+			}
+			var defs strings.Builder
+			for _, s := range got.Structs {
+				defs.WriteString(s.StructDef)
+			}
+			for _, w := range tt.wantIn {
+				if !strings.Contains(defs.String(), w) {
+					t.Errorf("Generate: structs do not contain %q, got:\n%s", w, defs.String())
+				}
+			}
+			for _, w := range tt.wantNotIn {
+				if strings.Contains(defs.String(), w) {
+					t.Errorf("Generate: structs contain %q, got:\n%s", w, defs.String())
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateFromEntries(t *testing.T) {
+	parse := func(t *testing.T) []*yang.Entry {
+		t.Helper()
+		ms := yang.NewModules()
+		ms.AddPath(datapath)
+		if err := ms.Read(filepath.Join(datapath, "openconfig-simple.yang")); err != nil {
+			t.Fatalf("cannot read module: %v", err)
+		}
+		if errs := ms.Process(); errs != nil {
+			t.Fatalf("cannot process modules: %v", errs)
+		}
+		var entries []*yang.Entry
+		for _, m := range ms.Modules {
+			entries = append(entries, yang.ToEntry(m))
+		}
+		return entries
+	}
+
+	newCodeGenerator := func() *CodeGenerator {
+		return New("", ygen.IROptions{
+			TransformationOptions: ygen.TransformationOpts{
+				CompressBehaviour: genutil.PreferIntendedConfig,
+				GenerateFakeRoot:  true,
+			},
+		}, GoOpts{})
+	}
+
+	want, errs := newCodeGenerator().Generate([]string{filepath.Join(datapath, "openconfig-simple.yang")}, []string{datapath})
+	if errs != nil {
+		t.Fatalf("Generate: got unexpected errors: %v", errs)
+	}
+
+	got, errs := newCodeGenerator().GenerateFromEntries(parse(t))
+	if errs != nil {
+		t.Fatalf("GenerateFromEntries: got unexpected errors: %v", errs)
+	}
+	// The headers record the input files, which are not known when the
+	// modules are supplied.
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(GeneratedCode{}, "CommonHeader", "OneOffHeader")); diff != "" {
+		t.Errorf("GenerateFromEntries: did not get the same code as Generate (-want, +got):\n%s", diff)
+	}
+
+	if _, errs := newCodeGenerator().GenerateFromEntries(append(parse(t), nil)); errs == nil {
+		t.Errorf("GenerateFromEntries: did not get expected error for nil module")
+	}
+}
+
+func TestGenerateGetOrCreateAt(t *testing.T) {
+	tests := []struct {
+		name    string
+		inOpts  GoOpts
+		wantGen bool
+	}{{
+		name: "with schema",
+		inOpts: GoOpts{
+			GenerateJSONSchema:    true,
+			GenerateGetOrCreateAt: true,
+		},
+		wantGen: true,
+	}, {
+		name: "without schema",
+		inOpts: GoOpts{
+			GenerateGetOrCreateAt: true,
+		},
+	}, {
+		name: "not requested",
+		inOpts: GoOpts{
+			GenerateJSONSchema: true,
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cg := New("", ygen.IROptions{
+				TransformationOptions: ygen.TransformationOpts{
+					CompressBehaviour: genutil.PreferIntendedConfig,
+					GenerateFakeRoot:  true,
+				},
+			}, tt.inOpts)
+			got, errs := cg.Generate([]string{filepath.Join(datapath, "openconfig-simple.yang")}, nil)
+			if errs != nil {
+				t.Fatalf("Generate: got unexpected errors: %v", errs)
+			}
+
+			want := "func (t *Device) GetOrCreateAt(path *gpb.Path) (interface{}, error) {"
+			for _, s := range got.Structs {
+				if s.StructName != "Device" {
+					if strings.Contains(s.Methods, "GetOrCreateAt(") {
+						t.Errorf("Generate: GetOrCreateAt generated for non-root struct %s", s.StructName)
+					}
+					continue
+				}
+				if gotGen := strings.Contains(s.Methods, want); gotGen != tt.wantGen {
+					t.Errorf("Generate: methods of the fake root contain %q: %v, want: %v, got:\n%s", want, gotGen, tt.wantGen, s.Methods)
+				}
+			}
+			if gotImport := strings.Contains(got.CommonHeader, "gpb "); gotImport != tt.wantGen {
+				t.Errorf("Generate: header imports gNMI protobuf: %v, want: %v, got:\n%s", gotImport, tt.wantGen, got.CommonHeader)
+			}
+		})
+	}
+}
+
+func TestGenerateStringMethod(t *testing.T) {
+	dir := t.TempDir()
+	for name, src := range map[string]string{
+		"ietf-netconf-acm.yang": `
+module ietf-netconf-acm {
+  prefix "nacm";
+  namespace "urn:ietf:params:xml:ns:yang:ietf-netconf-acm";
+
+  extension default-deny-all;
+}`,
+		"string-test.yang": `
+module string-test {
+  prefix "s";
+  namespace "urn:s";
+
+  import ietf-netconf-acm { prefix nacm; }
+
+  container top {
+    leaf name { type string; }
+    leaf password {
+      nacm:default-deny-all;
+      type string;
+    }
+    leaf token { type string; }
+    container keys {
+      nacm:default-deny-all;
+      leaf-list key { type string; }
+    }
+  }
+}`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatalf("cannot write YANG module %s: %v", name, err)
+		}
+	}
+
+	cg := New("", ygen.IROptions{
+		TransformationOptions: ygen.TransformationOpts{
+			GenerateFakeRoot: true,
+		},
+	}, GoOpts{
+		GenerateStringMethod: true,
+		RedactedSchemaPaths:  []string{"/top/token"},
+	})
+	got, errs := cg.Generate([]string{filepath.Join(dir, "string-test.yang")}, []string{dir})
+	if errs != nil {
+		t.Fatalf("Generate: got unexpected errors: %v", errs)
+	}
+
+	wantSensitive := map[string]map[string]bool{
+		"StringTest_Top": {
+			"Name":     false,
+			"Password": true,
+			"Token":    true,
+		},
+		"StringTest_Top_Keys": {
+			"Key": true,
+		},
+	}
+	for _, s := range got.Structs {
+		if want := fmt.Sprintf("func (t *%s) String() string { return ygot.RedactedString(t) }", s.StructName); !strings.Contains(s.Methods, want) {
+			t.Errorf("Generate: methods of struct %s do not contain %q", s.StructName, want)
+		}
+		for field, sensitive := range wantSensitive[s.StructName] {
+			var fieldDef string
+			for _, l := range strings.Split(s.StructDef, "\n") {
+				if strings.HasPrefix(strings.TrimSpace(l), field+"\t") {
+					fieldDef = l
+				}
+			}
+			if fieldDef == "" {
+				t.Errorf("Generate: struct %s does not contain field %s, got:\n%s", s.StructName, field, s.StructDef)
+				continue
+			}
+			if got := strings.Contains(fieldDef, `sensitive:"true"`); got != sensitive {
+				t.Errorf("Generate: field %s of struct %s is sensitive: %v, want: %v, got definition: %q", field, s.StructName, got, sensitive, fieldDef)
+			}
+		}
+	}
+}
+
+func TestGenerateBinarySchema(t *testing.T) {
+	cg := New("", ygen.IROptions{
+		TransformationOptions: ygen.TransformationOpts{
+			CompressBehaviour:          genutil.PreferIntendedConfig,
+			GenerateFakeRoot:           true,
+			EnumerationsUseUnderscores: true,
+		},
+	}, GoOpts{
+		GenerateJSONSchema: true,
+		BinarySchema:       true,
+	})
+	got, errs := cg.Generate([]string{filepath.Join(datapath, "openconfig-simple.yang")}, nil)
+	if errs != nil {
+		t.Fatalf("Generate: got unexpected errors: %v", errs)
+	}
+
+	if want := "if schemaTree, err = ytypes.LoadSchemaBinary(ySchema); err != nil {"; !strings.Contains(got.OneOffHeader, want) {
+		t.Errorf("Generate: header does not contain %q, got:\n%s", want, got.OneOffHeader)
+	}
+	if want := "compact binary representation"; !strings.Contains(got.JSONSchemaCode, want) {
+		t.Errorf("Generate: schema code does not contain %q, got:\n%s", want, got.JSONSchemaCode)
+	}
+
+	// The stored schema must be loadable, and equivalent to the JSON schema.
+	root := &yang.Entry{}
+	if err := json.Unmarshal(got.RawJSONSchema, root); err != nil {
+		t.Fatalf("cannot unmarshal JSON schema: %v", err)
+	}
+	b, err := ytypes.MarshalSchemaBinary(root)
+	if err != nil {
+		t.Fatalf("MarshalSchemaBinary: got unexpected error: %v", err)
+	}
+	if want := strings.Join(ygen.BytesToGoByteSlice(b), "\n\t\t"); !strings.Contains(got.JSONSchemaCode, want) {
+		t.Errorf("Generate: schema code does not contain the binary schema")
+	}
+	schema, err := ytypes.LoadSchemaBinary(b)
+	if err != nil {
+		t.Fatalf("LoadSchemaBinary: got unexpected error: %v", err)
+	}
+	if _, ok := schema["Parent_Child"]; !ok {
+		t.Errorf("LoadSchemaBinary: schema does not contain Parent_Child, got: %v", schema)
+	}
+}
+
+func TestGenerateLazySchema(t *testing.T) {
+	opts := ygen.IROptions{
+		TransformationOptions: ygen.TransformationOpts{
+			CompressBehaviour:          genutil.PreferIntendedConfig,
+			GenerateFakeRoot:           true,
+			EnumerationsUseUnderscores: true,
+		},
+	}
+	cg := New("", opts, GoOpts{
+		GenerateJSONSchema: true,
+		LazySchema:         true,
+	})
+	got, errs := cg.Generate([]string{filepath.Join(datapath, "openconfig-simple.yang")}, nil)
+	if errs != nil {
+		t.Fatalf("Generate: got unexpected errors: %v", errs)
+	}
+
+	for _, want := range []string{
+		"ΛLazySchema = ytypes.NewLazySchema(ySchema, ΛLazySchemaIndex)",
+		"schemaTree, err := ΛLazySchema.SchemaTree()",
+		"schema, err := ΛLazySchema.Entry(tn)",
+	} {
+		if !strings.Contains(got.OneOffHeader, want) {
+			t.Errorf("Generate: header does not contain %q, got:\n%s", want, got.OneOffHeader)
+		}
+	}
+	if strings.Contains(got.OneOffHeader, "SchemaTree, err = UnzipSchema()") {
+		t.Errorf("Generate: header loads the schema at initialisation, got:\n%s", got.OneOffHeader)
+	}
+	for _, want := range []string{
+		"var ΛLazySchemaIndex = &ytypes.LazySchemaIndex{",
+		`"Parent_Child": "parent",`,
+		`"Device": "",`,
+	} {
+		if !strings.Contains(got.JSONSchemaCode, want) {
+			t.Errorf("Generate: schema code does not contain %q, got:\n%s", want, got.JSONSchemaCode)
+		}
+	}
+	var validator string
+	for _, s := range got.Structs {
+		validator += s.Methods
+	}
+	if want := `schema, err := ΛLazySchema.Entry("Parent_Child")`; !strings.Contains(validator, want) {
+		t.Errorf("Generate: struct methods do not contain %q, got:\n%s", want, validator)
+	}
+
+	cg = New("", opts, GoOpts{
+		GenerateJSONSchema: true,
+		LazySchema:         true,
+		BinarySchema:       true,
+	})
+	if _, errs := cg.Generate([]string{filepath.Join(datapath, "openconfig-simple.yang")}, nil); errs == nil {
+		t.Errorf("Generate: did not get expected error for a binary lazily loaded schema")
+	}
+}
@@ -1972,3 +1972,241 @@ func TestGenerateIR(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateIRSubtrees(t *testing.T) {
+	tests := []struct {
+		desc             string
+		inInclude        []string
+		inExclude        []string
+		wantDirs         []string
+		wantErrSubstring string
+	}{{
+		desc: "no filters",
+		wantDirs: []string{
+			"/device",
+			"/openconfig-withlist/model",
+			"/openconfig-withlist/model/a/single-key",
+			"/openconfig-withlist/model/b/multi-key",
+			"/openconfig-withlist/model/c/single-key-ordered",
+		},
+	}, {
+		desc:      "include subtree",
+		inInclude: []string{"/model/b"},
+		wantDirs: []string{
+			"/device",
+			"/openconfig-withlist/model",
+			"/openconfig-withlist/model/b/multi-key",
+		},
+	}, {
+		desc:      "include leaf within list",
+		inInclude: []string{"/model/a/single-key/state/key"},
+		wantDirs: []string{
+			"/device",
+			"/openconfig-withlist/model",
+			"/openconfig-withlist/model/a/single-key",
+		},
+	}, {
+		desc:      "exclude subtree",
+		inExclude: []string{"/model/a", "/model/c/single-key-ordered"},
+		wantDirs: []string{
+			"/device",
+			"/openconfig-withlist/model",
+			"/openconfig-withlist/model/b/multi-key",
+		},
+	}, {
+		desc:      "exclude within included subtree",
+		inInclude: []string{"/model"},
+		inExclude: []string{"/model/b"},
+		wantDirs: []string{
+			"/device",
+			"/openconfig-withlist/model",
+			"/openconfig-withlist/model/a/single-key",
+			"/openconfig-withlist/model/c/single-key-ordered",
+		},
+	}, {
+		desc:             "included subtree does not exist",
+		inInclude:        []string{"/model/d"},
+		wantErrSubstring: "do not exist in the schema: [/model/d]",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ir, err := ygen.GenerateIR([]string{filepath.Join(datapath, "openconfig-withlist.yang")}, nil, NewGoLangMapper(true), ygen.IROptions{
+				ParseOptions: ygen.ParseOpts{
+					IncludeSubtrees: tt.inInclude,
+					ExcludeSubtrees: tt.inExclude,
+				},
+				TransformationOptions: ygen.TransformationOpts{
+					CompressBehaviour: genutil.PreferIntendedConfig,
+					GenerateFakeRoot:  true,
+				},
+			})
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("GenerateIR: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.wantDirs, ir.OrderedDirectoryPaths()); diff != "" {
+				t.Errorf("GenerateIR: did not get expected directories (-want, +got):\n%s", diff)
+			}
+			if tt.inInclude == nil {
+				return
+			}
+			// The keys of retained lists must be retained.
+			for _, p := range ir.OrderedDirectoryPaths() {
+				d := ir.Directories[p]
+				for _, k := range d.ListKeyYANGNames {
+					if d.Fields[k] == nil {
+						t.Errorf("GenerateIR: directory %s does not contain key field %s", p, k)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateIRDeviations(t *testing.T) {
+	tests := []struct {
+		desc             string
+		inDeviations     []string
+		wantFields       map[string]string
+		wantErrSubstring string
+	}{{
+		desc: "no deviations",
+		wantFields: map[string]string{
+			"one":   "string",
+			"two":   "string",
+			"three": "E_OpenconfigSimpleChildThree",
+			"four":  "Binary",
+		},
+	}, {
+		desc:         "deviations applied",
+		inDeviations: []string{filepath.Join(datapath, "openconfig-simple-deviations.yang")},
+		wantFields: map[string]string{
+			"one":   "uint32",
+			"three": "E_OpenconfigSimpleChildThree",
+		},
+	}, {
+		desc:             "deviation module does not exist",
+		inDeviations:     []string{filepath.Join(datapath, "does-not-exist.yang")},
+		wantErrSubstring: "does-not-exist.yang",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ir, err := ygen.GenerateIR([]string{filepath.Join(datapath, "openconfig-simple.yang")}, []string{datapath}, NewGoLangMapper(true), ygen.IROptions{
+				ParseOptions: ygen.ParseOpts{
+					DeviationModules: tt.inDeviations,
+				},
+				TransformationOptions: ygen.TransformationOpts{
+					CompressBehaviour: genutil.PreferIntendedConfig,
+					GenerateFakeRoot:  true,
+				},
+			})
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("GenerateIR: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			d, ok := ir.Directories["/openconfig-simple/parent/child"]
+			if !ok {
+				t.Fatalf("GenerateIR: did not get directory /openconfig-simple/parent/child, got: %v", ir.OrderedDirectoryPaths())
+			}
+			got := map[string]string{}
+			for name, f := range d.Fields {
+				got[name] = f.LangType.NativeType
+			}
+			if diff := cmp.Diff(tt.wantFields, got); diff != "" {
+				t.Errorf("GenerateIR: did not get expected fields (-want, +got):\n%s", diff)
+			}
+			// No code is generated for the deviation module itself.
+			for _, m := range ir.ModelData {
+				if m.GetName() == "openconfig-simple-deviations" {
+					t.Errorf("GenerateIR: got model data for deviation module %s", m.GetName())
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateIRWhenConditions(t *testing.T) {
+	ir, err := ygen.GenerateIR([]string{filepath.Join(datapath, "openconfig-when-defaults.yang")}, []string{datapath}, NewGoLangMapper(true), ygen.IROptions{
+		TransformationOptions: ygen.TransformationOpts{
+			CompressBehaviour: genutil.PreferIntendedConfig,
+			GenerateFakeRoot:  true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("GenerateIR: got unexpected error: %v", err)
+	}
+	d, ok := ir.Directories["/openconfig-when-defaults/interfaces/interface"]
+	if !ok {
+		t.Fatalf("GenerateIR: did not get directory /openconfig-when-defaults/interfaces/interface, got: %v", ir.OrderedDirectoryPaths())
+	}
+	got := map[string][]string{}
+	for name, f := range d.Fields {
+		got[name] = f.YANGDetails.WhenConditions
+	}
+	want := map[string][]string{
+		"name":          nil,
+		"type":          nil,
+		"mtu":           nil,
+		"loopback-mode": {"../type = 'LOOPBACK'"},
+		"ethernet":      {"config/type = 'ETHERNET'"},
+		"hold-time":     nil,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GenerateIR: did not get expected when conditions (-want, +got):\n%s", diff)
+	}
+}
+
+func TestGenerateIROpenConfigVersion(t *testing.T) {
+	ir, err := ygen.GenerateIR([]string{filepath.Join(datapath, "openconfig-node-versions.yang")}, []string{datapath}, NewGoLangMapper(true), ygen.IROptions{
+		TransformationOptions: ygen.TransformationOpts{
+			CompressBehaviour: genutil.PreferIntendedConfig,
+			GenerateFakeRoot:  true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("GenerateIR: got unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		desc   string
+		inDir  string
+		wantIn map[string]string
+	}{{
+		desc:  "statements on fields",
+		inDir: "/openconfig-node-versions/system",
+		wantIn: map[string]string{
+			"hostname":    "",
+			"domain-name": "1.2.0",
+			"clock":       "2.0.0",
+		},
+	}, {
+		desc:  "statements on fields and ancestors",
+		inDir: "/openconfig-node-versions/system/clock",
+		wantIn: map[string]string{
+			"timezone-name":   "2.0.0",
+			"timezone-offset": "2.1.0",
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			d, ok := ir.Directories[tt.inDir]
+			if !ok {
+				t.Fatalf("GenerateIR: did not get directory %s, got: %v", tt.inDir, ir.OrderedDirectoryPaths())
+			}
+			got := map[string]string{}
+			for name, f := range d.Fields {
+				got[name] = f.YANGDetails.OpenConfigVersion
+			}
+			if diff := cmp.Diff(tt.wantIn, got); diff != "" {
+				t.Errorf("GenerateIR: did not get expected versions (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
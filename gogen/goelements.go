@@ -35,6 +35,10 @@ const (
 	// Go code, such that an enumeration's name is of the form
 	//   <goEnumPrefix><EnumName>
 	goEnumPrefix string = "E_"
+	// unionDecimal64TypeName is the name of the type that is used for
+	// decimal64 subtypes of simple unions when the ygot.Decimal64 type is
+	// used for YANG decimal64 values.
+	unionDecimal64TypeName string = "UnionDecimal64"
 )
 
 // unionConversionSpec stores snippets that convert primitive Go types to
@@ -53,20 +57,21 @@ var (
 	// produces, such that resolved types can be checked as to whether they are
 	// Go built in types.
 	validGoBuiltinTypes = map[string]bool{
-		"int8":              true,
-		"int16":             true,
-		"int32":             true,
-		"int64":             true,
-		"uint8":             true,
-		"uint16":            true,
-		"uint32":            true,
-		"uint64":            true,
-		"float64":           true,
-		"string":            true,
-		"bool":              true,
-		"interface{}":       true,
-		ygot.BinaryTypeName: true,
-		ygot.EmptyTypeName:  true,
+		"int8":                 true,
+		"int16":                true,
+		"int32":                true,
+		"int64":                true,
+		"uint8":                true,
+		"uint16":               true,
+		"uint32":               true,
+		"uint64":               true,
+		"float64":              true,
+		"string":               true,
+		"bool":                 true,
+		"interface{}":          true,
+		ygot.BinaryTypeName:    true,
+		ygot.EmptyTypeName:     true,
+		ygot.Decimal64TypeName: true,
 	}
 
 	// simpleUnionConversionsFromKind stores the simple union conversion
@@ -89,40 +94,42 @@ var (
 	// be used within a generated struct. It is used when leaf getters are
 	// generated to return a zero value rather than the set value.
 	goZeroValues = map[string]string{
-		"int8":              "0",
-		"int16":             "0",
-		"int32":             "0",
-		"int64":             "0",
-		"uint8":             "0",
-		"uint16":            "0",
-		"uint32":            "0",
-		"uint64":            "0",
-		"float64":           "0.0",
-		"string":            `""`,
-		"bool":              "false",
-		"interface{}":       "nil",
-		ygot.BinaryTypeName: "nil",
-		ygot.EmptyTypeName:  "false",
+		"int8":                 "0",
+		"int16":                "0",
+		"int32":                "0",
+		"int64":                "0",
+		"uint8":                "0",
+		"uint16":               "0",
+		"uint32":               "0",
+		"uint64":               "0",
+		"float64":              "0.0",
+		"string":               `""`,
+		"bool":                 "false",
+		"interface{}":          "nil",
+		ygot.BinaryTypeName:    "nil",
+		ygot.EmptyTypeName:     "false",
+		ygot.Decimal64TypeName: "*new(" + ygot.Decimal64TypeName + ")",
 	}
 
 	// unionConversionSnippets stores the valid primitive types that the Go
 	// code generation produces that can be used as a union subtype, and
 	// information on how to convert it to a union-satisfying type.
 	unionConversionSnippets = map[string]*unionConversionSpec{
-		"int8":              {PrimitiveType: "int8", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["int8"] + "(v)"},
-		"int16":             {PrimitiveType: "int16", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["int16"] + "(v)"},
-		"int32":             {PrimitiveType: "int32", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["int32"] + "(v)"},
-		"int64":             {PrimitiveType: "int64", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["int64"] + "(v)"},
-		"uint8":             {PrimitiveType: "uint8", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["uint8"] + "(v)"},
-		"uint16":            {PrimitiveType: "uint16", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["uint16"] + "(v)"},
-		"uint32":            {PrimitiveType: "uint32", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["uint32"] + "(v)"},
-		"uint64":            {PrimitiveType: "uint64", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["uint64"] + "(v)"},
-		"float64":           {PrimitiveType: "float64", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["float64"] + "(v)"},
-		"string":            {PrimitiveType: "string", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["string"] + "(v)"},
-		"bool":              {PrimitiveType: "bool", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["bool"] + "(v)"},
-		"interface{}":       {PrimitiveType: "interface{}", ConversionSnippet: "&UnionUnsupported{v}"},
-		ygot.BinaryTypeName: {PrimitiveType: "[]byte", ConversionSnippet: ygot.BinaryTypeName + "(v)"},
-		ygot.EmptyTypeName:  {PrimitiveType: "bool", ConversionSnippet: ygot.EmptyTypeName + "(v)"},
+		"int8":                 {PrimitiveType: "int8", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["int8"] + "(v)"},
+		"int16":                {PrimitiveType: "int16", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["int16"] + "(v)"},
+		"int32":                {PrimitiveType: "int32", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["int32"] + "(v)"},
+		"int64":                {PrimitiveType: "int64", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["int64"] + "(v)"},
+		"uint8":                {PrimitiveType: "uint8", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["uint8"] + "(v)"},
+		"uint16":               {PrimitiveType: "uint16", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["uint16"] + "(v)"},
+		"uint32":               {PrimitiveType: "uint32", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["uint32"] + "(v)"},
+		"uint64":               {PrimitiveType: "uint64", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["uint64"] + "(v)"},
+		"float64":              {PrimitiveType: "float64", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["float64"] + "(v)"},
+		"string":               {PrimitiveType: "string", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["string"] + "(v)"},
+		"bool":                 {PrimitiveType: "bool", ConversionSnippet: ygot.SimpleUnionBuiltinGoTypes["bool"] + "(v)"},
+		"interface{}":          {PrimitiveType: "interface{}", ConversionSnippet: "&UnionUnsupported{v}"},
+		ygot.BinaryTypeName:    {PrimitiveType: "[]byte", ConversionSnippet: ygot.BinaryTypeName + "(v)"},
+		ygot.EmptyTypeName:     {PrimitiveType: "bool", ConversionSnippet: ygot.EmptyTypeName + "(v)"},
+		ygot.Decimal64TypeName: {PrimitiveType: ygot.Decimal64TypeName, ConversionSnippet: unionDecimal64TypeName + "(v)"},
 	}
 )
 
@@ -152,6 +159,11 @@ type GoLangMapper struct {
 	// NOTE: This flag will be removed as part of ygot's v1 release.
 	simpleUnions bool
 
+	// decimal64Type specifies whether the ygot.Decimal64 type is used to
+	// represent YANG decimal64 values in the generated code, rather than
+	// float64.
+	decimal64Type bool
+
 	// UnimplementedLangMapperExt ensures GoLangMapper implements the
 	// LangMapperExt interface for forwards compatibility.
 	ygen.UnimplementedLangMapperExt
@@ -164,8 +176,12 @@ func NewGoLangMapper(simpleUnions bool) *GoLangMapper {
 		definedGlobals: map[string]bool{
 			// Mark the name that is used for the binary type as a reserved name
 			// within the output structs.
-			ygot.BinaryTypeName: true,
-			ygot.EmptyTypeName:  true,
+			ygot.BinaryTypeName:    true,
+			ygot.EmptyTypeName:     true,
+			customBinaryTypeName:   true,
+			customEmptyTypeName:    true,
+			ygot.Decimal64TypeName: true,
+			unionDecimal64TypeName: true,
 		},
 		uniqueDirectoryNames: map[string]string{},
 		simpleUnions:         simpleUnions,
@@ -380,6 +396,9 @@ func (s *GoLangMapper) yangTypeToGoType(args resolveTypeArgs, compressOCPaths, s
 			DefaultValue:      defVal,
 		}, nil
 	case yang.Ydecimal64:
+		if s.decimal64Type {
+			return &ygen.MappedType{NativeType: ygot.Decimal64TypeName, ZeroValue: goZeroValues[ygot.Decimal64TypeName], DefaultValue: defVal}, nil
+		}
 		return &ygen.MappedType{NativeType: "float64", ZeroValue: goZeroValues["float64"], DefaultValue: defVal}, nil
 	case yang.Yleafref:
 		// This is a leafref, so we check what the type of the leaf that it
@@ -559,7 +578,10 @@ func generateGoDefaultValue(field *yang.Entry, mtype *ygen.MappedType, gogen *Go
 	// TODO(wenbli): In ygot v1, we should no longer
 	// support the wrapper union generated code, so this if
 	// block would be obsolete.
-	if !simpleUnions {
+	//
+	// Decimal64 defaults are not affected by the type of union that is
+	// generated, and are already Decimal64 literals.
+	if !simpleUnions && mtype.NativeType != ygot.Decimal64TypeName {
 		defaultValues = goLeafDefaults(field, mtype)
 		if len(defaultValues) != 0 && len(mtype.UnionTypes) > 1 {
 			// If the default value is applied to a union type, we will generate
@@ -685,6 +707,16 @@ func (s *GoLangMapper) yangDefaultValueToGo(value string, args resolveTypeArgs,
 		}
 		return value, ykind, nil
 	case yang.Ydecimal64:
+		if s.decimal64Type {
+			d, err := ygot.ParseDecimal64(value, uint8(args.yangType.FractionDigits))
+			if err != nil {
+				return "", yang.Ynone, fmt.Errorf("default value conversion: unable to convert default value %q to %v: %v", value, ykind, err)
+			}
+			if err := ytypes.ValidateDecimal64Restrictions(args.yangType, d); err != nil {
+				return "", yang.Ynone, fmt.Errorf("default value conversion: %q doesn't match decimal restrictions: %v", value, err)
+			}
+			return fmt.Sprintf("%s{Digits: %d, Precision: %d}", ygot.Decimal64TypeName, d.Digits, d.Precision), ykind, nil
+		}
 		val, err := strconv.ParseFloat(value, 64)
 		if err != nil {
 			return "", yang.Ynone, fmt.Errorf("default value conversion: unable to convert default value %q to %v: %v", value, ykind, err)
@@ -767,7 +799,11 @@ func (s *GoLangMapper) yangDefaultValueToGo(value string, args resolveTypeArgs,
 			snippet, convertedKind, err := s.yangDefaultValueToGo(value, resolveTypeArgs{yangType: t, contextEntry: args.contextEntry}, isSingletonUnion, compressOCPaths, skipEnumDedup, shortenEnumLeafNames, useDefiningModuleForTypedefEnumNames, enumOrgPrefixesToTrim)
 			if err == nil {
 				if !isSingletonUnion {
-					if simpleName, ok := simpleUnionConversionsFromKind[convertedKind]; ok {
+					simpleName, ok := simpleUnionConversionsFromKind[convertedKind]
+					if convertedKind == yang.Ydecimal64 && s.decimal64Type {
+						simpleName = unionDecimal64TypeName
+					}
+					if ok {
 						snippet = fmt.Sprintf("%s(%s)", simpleName, snippet)
 					}
 				}
@@ -17,6 +17,7 @@ package gogen
 import (
 	"bytes"
 	"fmt"
+	"go/token"
 	"io"
 	"sort"
 	"strings"
@@ -201,6 +202,15 @@ type goUnionInterface struct {
 	ConversionSpecs      []*unionConversionSpec // ConversionSpecs contains information on how to convert primitive types to their own union-satisfying types.
 	HasUnsupported       bool                   // HasUnsupported indicates that at least one of the union's subtypes is unsupported.
 	SubtypeDocumentation string                 // SubtypeDocumentation gives a documentation-style string on the subtypes of the union.
+	Constructors         []*unionConstructor    // Constructors describes the typed constructors that are generated for the union when GenerateUnionConstructors is set.
+}
+
+// unionConstructor describes a function that constructs a value of a union
+// from a value of one of its subtypes.
+type unionConstructor struct {
+	Suffix string // Suffix is appended to the name of the union to form the name of the constructor.
+	Type   string // Type is the Go type of the subtype from which the union value is constructed.
+	Value  string // Value is the code snippet that converts the input value v to the union type.
 }
 
 // generatedGoStruct is used to repesent a Go structure to be handed to a template for output.
@@ -209,6 +219,7 @@ type generatedGoStruct struct {
 	YANGPath        string           // YANGPath is the schema path of the struct being output.
 	Fields          []*goStructField // Fields is the slice of fields of the struct, described as goStructField structs.
 	BelongingModule string           // BelongingModule is the module in which namespace the GoStruct belongs.
+	LazySchema      bool             // LazySchema indicates that the schema for the struct is retrieved from the lazily loaded schema.
 }
 
 // yangFieldMap maps a YANG identifier to its Go identifier.
@@ -272,6 +283,56 @@ type generatedLeafSetter struct {
 	Receiver string
 }
 
+// generatedDeltaStruct is used to represent the parameters required to
+// generate a delta type for a GoStruct, which records which of the leaf
+// fields of the GoStruct were explicitly set.
+type generatedDeltaStruct struct {
+	// Receiver is the name of the GoStruct that is wrapped by the delta.
+	Receiver string
+	// Leaves are the leaf fields of the GoStruct, whose index within the
+	// slice is the index of the field within the presence bitmap.
+	Leaves []*generatedLeafGetter
+}
+
+// generatedDeepCopyMethod is used to represent the parameters required to
+// generate a DeepCopy method for a GoStruct, which copies the GoStruct
+// field-by-field without using reflection.
+type generatedDeepCopyMethod struct {
+	// Receiver is the name of the GoStruct that is copied.
+	Receiver string
+	// Fields are the fields of the GoStruct, in the order that they are
+	// copied.
+	Fields []*deepCopyField
+}
+
+// deepCopyField describes how a field of a GoStruct is copied by the
+// generated DeepCopy method.
+type deepCopyField struct {
+	// Name is the name of the field.
+	Name string
+	// Type is the Go type of the field.
+	Type string
+	// Kind determines the code that is generated to copy the field. It is
+	// one of "value", "ptr", "struct", "map", "orderedmap", "structslice",
+	// "slice", "binaryslice", "union" or "unionslice".
+	Kind string
+	// UnionCases are the cases of the type switch that copies the values
+	// of a union, or of each element of a leaf-list of unions, whose
+	// subtypes are not immutable. Values of other subtypes are assigned
+	// directly.
+	UnionCases []*deepCopyUnionCase
+}
+
+// deepCopyUnionCase is a case of the type switch that copies the value of a
+// union.
+type deepCopyUnionCase struct {
+	// Type is the Go type of the value of the union.
+	Type string
+	// Copy is an expression which copies the value v of the union, which
+	// is of type Type.
+	Copy string
+}
+
 // generatedDefaultMethod is used to represent parameters required to generate
 // a PopulateDefaults method for a GoStruct that recursively populates default
 // values within the subtree.
@@ -286,6 +347,19 @@ type generatedDefaultMethod struct {
 	ChildOrderedListNames []string
 	// Leaves represent the leaf fields of the GoStruct.
 	Leaves []*generatedLeafGetter
+	// ContainerTypes maps the names of the container fields of the
+	// GoStruct to the names of their struct types.
+	ContainerTypes map[string]string
+	// WhenGuarded is the set of names of the container and leaf fields of
+	// the GoStruct that are guarded by when statements. Since the
+	// conditions of when statements are not evaluated by the generated
+	// code, such containers are not instantiated and such leaves are not
+	// populated, such that nodes whose conditions do not hold are not
+	// created.
+	WhenGuarded map[string]bool
+	// HasWhenGuardedContainer indicates whether any of the container
+	// fields of the GoStruct are guarded by when statements.
+	HasWhenGuardedContainer bool
 }
 
 // mustMakeTemplate generates a template.Template for a particular named source
@@ -298,6 +372,7 @@ var (
 	// goCommonHeaderTemplate is populated and output at the top of the generated code package
 	goCommonHeaderTemplate = mustMakeTemplate("commonHeader", `
 {{- /**/ -}}
+{{- if not .GoOptions.GeneratePackageDoc -}}
 /*
 Package {{ .PackageName }} is a generated package which contains definitions
 of structs which represent a YANG schema. The generated schema can be
@@ -314,6 +389,7 @@ Imported modules were sourced from:
 	- {{ $importPath }}
 {{- end }}
 */
+{{ end -}}
 package {{ .PackageName }}
 
 import (
@@ -327,7 +403,7 @@ import (
 	"{{ .GoOptions.GoyangImportPath }}"
 	"{{ .GoOptions.YtypesImportPath }}"
 {{- end }}
-{{- if .GoOptions.IncludeModelData }}
+{{- if or .GoOptions.IncludeModelData .GetOrCreateAt }}
 	gpb "{{ .GoOptions.GNMIProtoPath }}"
 {{- end }}
 )
@@ -336,6 +412,14 @@ import (
 	// goOneOffHeaderTemplate defines the template for package code that should
 	// be output in only one file.
 	goOneOffHeaderTemplate = mustMakeTemplate("oneoffHeader", `
+{{- with .CustomBinaryType }}
+import custombinary "{{ .ImportPath }}"
+{{- end }}
+{{- with .CustomEmptyType }}
+import customempty "{{ .ImportPath }}"
+{{- end }}
+{{- if or .CustomBinaryType .CustomEmptyType }}
+{{ end }}
 // {{ .BinaryTypeName }} is a type that is used for fields that have a YANG type of
 // binary. It is used such that binary fields can be distinguished from
 // leaf-lists of uint8s (which are mapped to []uint8, equivalent to
@@ -347,6 +431,27 @@ type {{ .BinaryTypeName }} []byte
 // in the generated code.
 type {{ .EmptyTypeName }} bool
 
+{{- with .CustomBinaryType }}
+
+// {{ .Alias }} is the user-specified type, {{ .ImportPath }}.{{ .Name }},
+// that is used for leaves that have a YANG type of binary.
+type {{ .Alias }} = custombinary.{{ .Name }}
+{{- end }}
+
+{{- with .CustomEmptyType }}
+
+// {{ .Alias }} is the user-specified type, {{ .ImportPath }}.{{ .Name }},
+// that is used for leaves that have a YANG type of empty.
+type {{ .Alias }} = customempty.{{ .Name }}
+{{- end }}
+
+{{- if .GoOptions.GenerateDecimal64Type }}
+
+// Decimal64 is the fixed-point type that is used for fields that have a YANG
+// type of decimal64, such that their values are represented exactly.
+type Decimal64 = ygot.Decimal64
+{{- end }}
+
 {{- if .GoOptions.GenerateSimpleUnions }}
 
 // UnionInt8 is an int8 type assignable to unions of which it is a subtype.
@@ -375,6 +480,15 @@ type UnionUint64 uint64
 
 // UnionFloat64 is a float64 type assignable to unions of which it is a subtype.
 type UnionFloat64 float64
+{{- if .GoOptions.GenerateDecimal64Type }}
+
+// UnionDecimal64 is a Decimal64 type assignable to unions of which it is a subtype.
+type UnionDecimal64 Decimal64
+
+// IsYANGLeafValue marks UnionDecimal64 as the value of a YANG leaf, rather than
+// a struct representing a YANG container.
+func (UnionDecimal64) IsYANGLeafValue() {}
+{{- end }}
 
 // UnionString is a string type assignable to unions of which it is a subtype.
 type UnionString string
@@ -392,6 +506,21 @@ type UnionUnsupported struct {
 
 {{- if .GenerateSchema }}
 
+{{- if .GoOptions.LazySchema }}
+
+var (
+	// ΛLazySchema is the schema from which the generated code was produced,
+	// each part of which is loaded when the schema of a struct within it is
+	// first required.
+	ΛLazySchema = ytypes.NewLazySchema(ySchema, ΛLazySchemaIndex)
+	ΛEnumTypes map[string][]reflect.Type
+)
+
+func init() {
+	initΛEnumTypes()
+}
+{{- else }}
+
 var (
 	SchemaTree map[string]*yang.Entry
 	ΛEnumTypes map[string][]reflect.Type
@@ -404,6 +533,7 @@ func init() {
 		panic("schema error: " +  err.Error())
 	}
 }
+{{- end }}
 
 // Schema returns the details of the generated schema.
 func Schema() (*ytypes.Schema, error) {
@@ -419,16 +549,35 @@ func Schema() (*ytypes.Schema, error) {
 	}, nil
 }
 
+{{- if .GoOptions.LazySchema }}
+// UnzipSchema loads the entire lazily loaded schema and returns a map of
+// yang.Entry nodes, keyed by the name of the struct that the yang.Entry
+// describes the schema for. The schema is loaded once, and the returned
+// map and entries are shared between callers, such that they must not be
+// modified.
+func UnzipSchema() (map[string]*yang.Entry, error) {
+	schemaTree, err := ΛLazySchema.SchemaTree()
+	if err != nil {
+		return nil, fmt.Errorf("could not unzip the schema; %v", err)
+	}
+	return schemaTree, nil
+}
+{{- else }}
 // UnzipSchema unzips the zipped schema and returns a map of yang.Entry nodes,
 // keyed by the name of the struct that the yang.Entry describes the schema for.
 func UnzipSchema() (map[string]*yang.Entry, error) {
 	var schemaTree map[string]*yang.Entry
 	var err error
+	{{- if .GoOptions.BinarySchema }}
+	if schemaTree, err = ytypes.LoadSchemaBinary(ySchema); err != nil {
+	{{- else }}
 	if schemaTree, err = ygot.GzipToSchema(ySchema); err != nil {
+	{{- end }}
 		return nil, fmt.Errorf("could not unzip the schema; %v", err)
 	}
 	return schemaTree, nil
 }
+{{- end }}
 
 // Unmarshal unmarshals data, which must be RFC7951 JSON format, into
 // destStruct, which must be non-nil and the correct GoStruct type. It returns
@@ -438,10 +587,17 @@ func UnzipSchema() (map[string]*yang.Entry, error) {
 // thrown for unknown fields in the input JSON.
 func Unmarshal(data []byte, destStruct ygot.GoStruct, opts ...ytypes.UnmarshalOpt) error {
 	tn := reflect.TypeOf(destStruct).Elem().Name()
+	{{- if .GoOptions.LazySchema }}
+	schema, err := ΛLazySchema.Entry(tn)
+	if err != nil {
+		return err
+	}
+	{{- else }}
 	schema, ok := SchemaTree[tn]
 	if !ok {
 		return fmt.Errorf("could not find schema for type %s", tn )
 	}
+	{{- end }}
 	var jsonTree interface{}
 	if err := json.Unmarshal([]byte(data), &jsonTree); err != nil {
 		return err
@@ -501,7 +657,15 @@ func (*{{ .StructName }}) IsYANGGoStruct() {}
 	goStructValidatorTemplate = mustMakeTemplate("structValidator", `
 // Validate validates s against the YANG schema corresponding to its type.
 func (t *{{ .StructName }}) ΛValidate(opts ...ygot.ValidationOption) error {
+	{{- if .LazySchema }}
+	schema, err := ΛLazySchema.Entry("{{ .StructName }}")
+	if err != nil {
+		return err
+	}
+	if err := ytypes.Validate(schema, t, opts...); err != nil {
+	{{- else }}
 	if err := ytypes.Validate(SchemaTree["{{ .StructName }}"], t, opts...); err != nil {
+	{{- end }}
 		return err
 	}
 	return nil
@@ -611,6 +775,62 @@ func (t *{{ .Receiver }}) Get{{ .Name }}() {{ .Type }} {
 func (t *{{ .Receiver }}) Set{{ .Name }}(v {{ .Type }}) {
 	t.{{ .Name }} = {{ if .IsPtr -}} & {{- end -}} v
 }
+`)
+
+	// goDeltaStructTemplate is a template for generating a delta type for
+	// a GoStruct, along with methods that set, clear and check the presence
+	// of each of its leaves.
+	goDeltaStructTemplate = mustMakeTemplate("deltaStruct", `
+// {{ .Receiver }}_Delta is a shallow delta of the {{ .Receiver }} struct, which
+// records which of its leaves were explicitly set, such that a leaf that is
+// set to its zero value can be distinguished from a leaf that is unset.
+type {{ .Receiver }}_Delta struct {
+	// Struct is the {{ .Receiver }} whose leaves are set by the delta.
+	Struct *{{ .Receiver }}
+	// ΛPresence records the leaves of Struct that were explicitly set.
+	ΛPresence ygot.PresenceBitmap
+}
+
+// ΛDeltaStruct returns the {{ .Receiver }} that is wrapped by the delta.
+func (d *{{ .Receiver }}_Delta) ΛDeltaStruct() ygot.GoStruct {
+	return d.Struct
+}
+
+// ΛPresentFields returns the names of the fields of {{ .Receiver }} that were
+// explicitly set within the delta.
+func (d *{{ .Receiver }}_Delta) ΛPresentFields() []string {
+	return d.ΛPresence.Names([]string{
+		{{- range $i, $Leaf := .Leaves }}{{ if $i }}, {{ end }}"{{ $Leaf.Name }}"{{ end -}}
+	})
+}
+{{- $Receiver := .Receiver }}
+{{- range $i, $Leaf := .Leaves }}
+
+// Set{{ $Leaf.Name }} sets the value of the leaf {{ $Leaf.Name }} of the
+// {{ $Receiver }} struct, and marks it as explicitly set.
+func (d *{{ $Receiver }}_Delta) Set{{ $Leaf.Name }}(v {{ $Leaf.Type }}) {
+	if d.Struct == nil {
+		d.Struct = &{{ $Receiver }}{}
+	}
+	d.Struct.{{ $Leaf.Name }} = {{ if $Leaf.IsPtr -}} & {{- end -}} v
+	d.ΛPresence.Set({{ $i }})
+}
+
+// Clear{{ $Leaf.Name }} unsets the leaf {{ $Leaf.Name }} of the {{ $Receiver }}
+// struct, and marks it as not explicitly set.
+func (d *{{ $Receiver }}_Delta) Clear{{ $Leaf.Name }}() {
+	if d.Struct != nil {
+		d.Struct.{{ $Leaf.Name }} = {{ if $Leaf.IsPtr -}} nil {{- else -}} {{ $Leaf.Zero }} {{- end }}
+	}
+	d.ΛPresence.Clear({{ $i }})
+}
+
+// Has{{ $Leaf.Name }} returns true if the leaf {{ $Leaf.Name }} of the
+// {{ $Receiver }} struct was explicitly set.
+func (d *{{ $Receiver }}_Delta) Has{{ $Leaf.Name }}() bool {
+	return d.ΛPresence.Has({{ $i }})
+}
+{{- end }}
 `)
 
 	// goDefaultMethodTemplate is a template for generating a PopulateDefaults method
@@ -619,14 +839,30 @@ func (t *{{ .Receiver }}) Set{{ .Name }}(v {{ .Type }}) {
 // PopulateDefaults recursively populates unset leaf fields in the {{ .Receiver }}
 // with default values as specified in the YANG schema, instantiating any nil
 // container fields.
+{{- if .WhenGuarded }}
+// Since the conditions of when statements are not evaluated, containers and
+// leaves that are guarded by when statements are not instantiated, such that
+// nodes whose conditions do not hold are not created. Containers guarded by
+// when statements that are already instantiated are populated.
+{{- end }}
 func (t *{{ .Receiver }}) PopulateDefaults() {
 	if (t == nil) {
 		return
 	}
+	{{- if .HasWhenGuardedContainer }}
+	{{- range $containerName := .ChildContainerNames }}
+	{{- if not (index $.WhenGuarded $containerName) }}
+	if t.{{ $containerName }} == nil {
+		t.{{ $containerName }} = &{{ index $.ContainerTypes $containerName }}{}
+	}
+	{{- end }}
+	{{- end }}
+	{{- else }}
 	ygot.BuildEmptyTree(t)
+	{{- end }}
 
 	{{- range $Leaf := .Leaves }}
-	{{- if $Leaf.Default }}
+	{{- if and $Leaf.Default (not (index $.WhenGuarded $Leaf.Name)) }}
 	if t.{{ $Leaf.Name }} == {{ if $Leaf.IsPtr -}} nil {{- else }} {{ $Leaf.Zero }} {{- end }} {
 		{{- if $Leaf.IsPtr }}
 		var v {{ $Leaf.Type }} = {{ $Leaf.Default }}
@@ -695,6 +931,291 @@ func initΛEnumTypes(){
 	{{- end }}
   }
 }
+`)
+
+	// goStructRegistryTemplate provides a template to output a map, keyed by
+	// YANG module name, of maps, keyed by YANG schema path, of functions that
+	// return a new instance of the GoStruct generated for the container or
+	// list at the schema path.
+	goStructRegistryTemplate = mustMakeTemplate("structRegistry", `
+// ΛStructRegistry is a map, keyed by the name of a YANG module, of the
+// registry of the GoStructs that belong to the module. Each registry is a map,
+// keyed by a YANG schema path, of functions that return a new instance of the
+// GoStruct that represents the container or list at the path. The fake root,
+// which does not belong to a module, is registered under the empty string.
+// The naming of the map ensures that there are no clashes with valid YANG
+// identifiers.
+var ΛStructRegistry = map[string]map[string]func() ygot.GoStruct{
+{{- range $module, $structs := . }}
+	"{{ $module }}": {
+	{{- range $schemapath, $name := $structs }}
+		"{{ $schemapath }}": func() ygot.GoStruct { return &{{ $name }}{} },
+	{{- end }}
+	},
+{{- end }}
+}
+
+// NewGoStructForPath returns a new instance of the GoStruct that represents
+// the container or list at the supplied YANG schema path, which must not
+// include module names or list keys, within the registry of the named YANG
+// module. The fake root is found within the registry of the empty module name.
+func NewGoStructForPath(module, schemaPath string) (ygot.GoStruct, error) {
+	if fn, ok := ΛStructRegistry[module][schemaPath]; ok {
+		return fn(), nil
+	}
+	return nil, fmt.Errorf("no GoStruct found for schema path %s in module %q", schemaPath, module)
+}
+`)
+
+	// goCapabilitiesTemplate provides a template to output a variable
+	// describing the options with which the code was generated.
+	goCapabilitiesTemplate = mustMakeTemplate("capabilities", `
+// ΛCapabilitiesDescriptor describes the options with which the GoStructs in
+// this package were generated.
+var ΛCapabilitiesDescriptor = &ygot.Capabilities{
+	CompressBehaviour: "{{ .CompressBehaviour }}",
+	Compressed:        {{ .Compressed }},
+	SimpleUnions:      {{ .SimpleUnions }},
+	OrderedMaps:       {{ .OrderedMaps }},
+	SchemaEmbedded:    {{ .SchemaEmbedded }},
+	Getters:           {{ .Getters }},
+	WildcardPaths:     {{ .WildcardPaths }},
+	GeneratorVersion:  "{{ .GeneratorVersion }}",
+}
+`)
+
+	// goMetadataAnnotationsTemplate provides a template to output a type
+	// representing each RFC7952 metadata annotation defined within the
+	// input YANG modules, along with a map, keyed by the qualified name of
+	// each annotation, of the types.
+	goMetadataAnnotationsTemplate = mustMakeTemplate("metadataAnnotations", `
+{{- range $a := . }}
+// {{ $a.TypeName }} represents the value of the "{{ $a.Name }}" metadata
+// annotation defined by the {{ $a.Module }} module. It implements the
+// ygot.MetadataAnnotation interface, such that it can be stored within
+// annotation fields.
+{{- if $a.Description }}
+//
+{{- range $line := $a.Description }}
+// {{ $line }}
+{{- end }}
+{{- end }}
+type {{ $a.TypeName }} struct {
+	Value {{ $a.GoType }}
+}
+
+// ΛMetadataName returns the name of the annotation, qualified by the name of
+// the module that defines it.
+func (*{{ $a.TypeName }}) ΛMetadataName() string {
+	return "{{ $a.Module }}:{{ $a.Name }}"
+}
+
+// MarshalJSON marshals the value of {{ $a.TypeName }} to RFC7951 JSON.
+func (a *{{ $a.TypeName }}) MarshalJSON() ([]byte, error) {
+	return ygot.MarshalMetadataValue(a.Value)
+}
+
+// UnmarshalJSON unmarshals the RFC7951 JSON value of the annotation into
+// {{ $a.TypeName }}.
+func (a *{{ $a.TypeName }}) UnmarshalJSON(b []byte) error {
+	return ygot.UnmarshalMetadataValue(b, &a.Value)
+}
+{{ end }}
+// ΛMetadataAnnotationTypes is a map, keyed by the name of each metadata
+// annotation qualified by the name of the module that defines it, of the
+// types that represent the annotations. The naming of the map ensures that
+// there are no clashes with valid YANG identifiers.
+var ΛMetadataAnnotationTypes = map[string]reflect.Type{
+{{- range $a := . }}
+	"{{ $a.Module }}:{{ $a.Name }}": reflect.TypeOf({{ $a.TypeName }}{}),
+{{- end }}
+}
+`)
+
+	// goMetadataAnnotationTypeMapTemplate provides a template to output an
+	// accessor method with a generated struct as receiver, which returns
+	// the map of the types of the metadata annotations.
+	goMetadataAnnotationTypeMapTemplate = mustMakeTemplate("metadataAnnotationTypeMap", `
+// ΛMetadataAnnotationTypeMap returns a map, keyed by qualified name, of the
+// types of the metadata annotations that can be stored within the annotation
+// fields of {{ . }}.
+func (*{{ . }}) ΛMetadataAnnotationTypeMap() map[string]reflect.Type {
+	return ΛMetadataAnnotationTypes
+}
+`)
+
+	// goPackageDocTemplate provides a template to output the doc.go file
+	// of a generated package, which describes the YANG modules and options
+	// from which the package was generated, and the schema paths that it
+	// covers. The sections of the documentation are parsed by
+	// ParsePackageDoc, such that their format must not be changed
+	// independently of it.
+	goPackageDocTemplate = mustMakeTemplate("packageDoc", `
+{{- /**/ -}}
+/*
+Package {{ .Doc.PackageName }} is a generated package which contains definitions
+of structs which represent a YANG schema. The generated schema can be
+compressed by a series of transformations (compression was {{ .CompressEnabled }}
+in this case).
+
+This package was generated by {{ .GeneratingBinary }}
+using the following YANG input files:
+{{- range $inputFile := .YANGFiles }}
+	- {{ $inputFile }}
+{{- end }}
+Imported modules were sourced from:
+{{- range $importPath := .IncludePaths }}
+	- {{ $importPath }}
+{{- end }}
+
+# Modules
+
+The package was generated from the following YANG modules, each of which is
+listed with its revision, version and organization, or "-" where the module
+does not specify a value:
+{{ range $line := .ModuleTable }}
+	{{ $line }}
+{{- end }}
+
+# Options
+
+The package was generated with the following options:
+{{ range $opt := .Doc.Options }}
+	{{ $opt.Name }}: {{ $opt.Value }}
+{{- end }}
+
+# Schema paths
+
+The package contains structs for the following YANG schema paths:
+{{ range $path := .Doc.SchemaPaths }}
+  - {{ $path.Path }}: [{{ $path.Struct }}]
+{{- end }}
+*/
+package {{ .Doc.PackageName }}
+`)
+
+	// goCapabilitiesMethodTemplate provides a template to output a method
+	// that has a generated struct as receiver, and returns the descriptor
+	// of the options with which the code was generated.
+	goCapabilitiesMethodTemplate = mustMakeTemplate("capabilitiesMethod", `
+// ΛCapabilities returns the options with which {{ .StructName }} was generated.
+func (*{{ .StructName }}) ΛCapabilities() *ygot.Capabilities { return ΛCapabilitiesDescriptor }
+`)
+
+	// goStringMethodTemplate provides a template to output a String method
+	// that has a generated struct as receiver, and returns its RFC7951 JSON
+	// representation with the values of sensitive leaves redacted.
+	goStringMethodTemplate = mustMakeTemplate("stringMethod", `
+// String returns a compact RFC7951 JSON representation of {{ .StructName }},
+// in which the values of sensitive leaves are redacted.
+func (t *{{ .StructName }}) String() string { return ygot.RedactedString(t) }
+`)
+
+	// goDeepCopyMethodTemplate provides a template to output a DeepCopy
+	// method for a struct, which copies it field-by-field, along with a
+	// ΛDeepCopy method such that ygot.DeepCopy uses the generated method.
+	goDeepCopyMethodTemplate = mustMakeTemplate("deepCopyMethod", `
+// DeepCopy returns a deep copy of {{ .Receiver }}, which is made without
+// using reflection. Empty lists and leaf-lists are not copied.
+func (t *{{ .Receiver }}) DeepCopy() *{{ .Receiver }} {
+	if t == nil {
+		return nil
+	}
+	n := &{{ .Receiver }}{}
+	{{- range $f := .Fields }}
+	{{- if eq $f.Kind "value" }}
+	n.{{ $f.Name }} = t.{{ $f.Name }}
+	{{- else if eq $f.Kind "ptr" }}
+	if t.{{ $f.Name }} != nil {
+		v := *t.{{ $f.Name }}
+		n.{{ $f.Name }} = &v
+	}
+	{{- else if eq $f.Kind "struct" }}
+	n.{{ $f.Name }} = t.{{ $f.Name }}.DeepCopy()
+	{{- else if eq $f.Kind "orderedmap" }}
+	if t.{{ $f.Name }}.Len() != 0 {
+		n.{{ $f.Name }} = t.{{ $f.Name }}.DeepCopy()
+	}
+	{{- else if eq $f.Kind "map" }}
+	if len(t.{{ $f.Name }}) != 0 {
+		n.{{ $f.Name }} = make({{ $f.Type }}, len(t.{{ $f.Name }}))
+		for k, v := range t.{{ $f.Name }} {
+			n.{{ $f.Name }}[k] = v.DeepCopy()
+		}
+	}
+	{{- else if eq $f.Kind "structslice" }}
+	if len(t.{{ $f.Name }}) != 0 {
+		n.{{ $f.Name }} = make({{ $f.Type }}, 0, len(t.{{ $f.Name }}))
+		for _, v := range t.{{ $f.Name }} {
+			n.{{ $f.Name }} = append(n.{{ $f.Name }}, v.DeepCopy())
+		}
+	}
+	{{- else if eq $f.Kind "slice" }}
+	if len(t.{{ $f.Name }}) != 0 {
+		n.{{ $f.Name }} = append({{ $f.Type }}(nil), t.{{ $f.Name }}...)
+	}
+	{{- else if eq $f.Kind "binaryslice" }}
+	if len(t.{{ $f.Name }}) != 0 {
+		n.{{ $f.Name }} = make({{ $f.Type }}, 0, len(t.{{ $f.Name }}))
+		for _, v := range t.{{ $f.Name }} {
+			n.{{ $f.Name }} = append(n.{{ $f.Name }}, append(Binary(nil), v...))
+		}
+	}
+	{{- else if eq $f.Kind "union" }}
+	switch v := t.{{ $f.Name }}.(type) {
+	{{- range $c := $f.UnionCases }}
+	case {{ $c.Type }}:
+		n.{{ $f.Name }} = {{ $c.Copy }}
+	{{- end }}
+	default:
+		n.{{ $f.Name }} = t.{{ $f.Name }}
+	}
+	{{- else if eq $f.Kind "unionslice" }}
+	if len(t.{{ $f.Name }}) != 0 {
+		n.{{ $f.Name }} = make({{ $f.Type }}, 0, len(t.{{ $f.Name }}))
+		for _, e := range t.{{ $f.Name }} {
+			switch v := e.(type) {
+			{{- range $c := $f.UnionCases }}
+			case {{ $c.Type }}:
+				e = {{ $c.Copy }}
+			{{- end }}
+			}
+			n.{{ $f.Name }} = append(n.{{ $f.Name }}, e)
+		}
+	}
+	{{- end }}
+	{{- end }}
+	return n
+}
+
+// ΛDeepCopy returns a deep copy of {{ .Receiver }}, such that it implements
+// the ygot.DeepCopier interface.
+func (t *{{ .Receiver }}) ΛDeepCopy() ygot.GoStruct { return t.DeepCopy() }
+`)
+
+	// goGetOrCreateAtTemplate defines a template that generates a method of
+	// the fake root that retrieves or creates the node at an arbitrary path.
+	goGetOrCreateAtTemplate = mustMakeTemplate("getOrCreateAt", `
+// GetOrCreateAt retrieves the node at the supplied path, which is relative to
+// {{ .StructName }}, creating it, along with the containers and list entries
+// along the path, if it does not exist. The node can be converted to its type
+// using ygot.GetOrCreateAt. Note that {{ .StructName }} may be modified even if
+// an error is returned.
+func (t *{{ .StructName }}) GetOrCreateAt(path *gpb.Path) (interface{}, error) {
+	{{- if .LazySchema }}
+	schema, err := ΛLazySchema.Entry("{{ .StructName }}")
+	if err != nil {
+		return nil, err
+	}
+	{{- else }}
+	schema := SchemaTree["{{ .StructName }}"]
+	{{- end }}
+	node, _, err := ytypes.GetOrCreateNode(schema, t, path)
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
 `)
 
 	// goEnumTypeMapAccessTemplate provides a template to output an accessor
@@ -722,18 +1243,41 @@ func (*{{ .StructName }}) ΛBelongingModule() string {
 	// which code generation was performed.
 	schemaVarTemplate = mustMakeTemplate("schemaVar", `
 var (
+	{{- if .Binary }}
+	// {{ .VarName }} is a byte slice containing a compact binary representation
+	// of the YANG schema from which the Go code was generated, which can be
+	// loaded using ytypes.LoadSchemaBinary.
+	{{- else }}
 	// {{ .VarName }} is a byte slice contain a gzip compressed representation of the
 	// YANG schema from which the Go code was generated. When uncompressed the
 	// contents of the byte slice is a JSON document containing an object, keyed
 	// on the name of the generated struct, and containing the JSON marshalled
 	// contents of a goyang yang.Entry struct, which defines the schema for the
 	// fields within the struct.
+	{{- end }}
 	{{ .VarName }} = []byte{
 {{- range $i, $line := .Schema }}
 		{{ $line }}
 {{- end }}
 	}
 )
+{{- if .LazyIndex }}
+
+// ΛLazySchemaIndex maps the generated structs to the children of the schema
+// root that contain their schema, such that each can be loaded on demand.
+var ΛLazySchemaIndex = &ytypes.LazySchemaIndex{
+	Structs: map[string]string{
+{{- range $name, $top := .LazyIndex.Structs }}
+		"{{ $name }}": "{{ $top }}",
+{{- end }}
+	},
+	Deps: map[string][]string{
+{{- range $top, $deps := .LazyIndex.Deps }}
+		"{{ $top }}": { {{- range $i, $d := $deps }}{{ if $i }}, {{ end }}"{{ $d }}"{{ end -}} },
+{{- end }}
+	},
+}
+{{- end }}
 `)
 
 	// unionTypeTemplate outputs the type that corresponds to a multi-type union
@@ -838,6 +1382,25 @@ func (t *{{ .ParentReceiver }}) To_{{ .Name }}(i interface{}) ({{ .Name }}, erro
 	{{- end -}}
 	]", i, i)
 }
+`)
+
+	// unionConstructorsTemplate defines a template that outputs functions
+	// that construct a value of a union, such that the membership of a value
+	// within the union is checked at compile time by the typed constructors,
+	// or immediately by the generic constructor.
+	unionConstructorsTemplate = mustMakeTemplate("unionConstructors", `
+{{- $intfName := .Name }}
+// {{ .Name }}From takes an input interface{} and attempts to convert it to a
+// value of the {{ .Name }} union. It returns an error if the interface{} supplied
+// cannot be converted to a type within the union.
+func {{ .Name }}From(i interface{}) ({{ .Name }}, error) {
+	return (*{{ .ParentReceiver }})(nil).To_{{ .Name }}(i)
+}
+{{ range $c := .Constructors }}
+// {{ $intfName }}From{{ $c.Suffix }} returns the {{ $c.Type }} v as a value of the
+// {{ $intfName }} union.
+func {{ $intfName }}From{{ $c.Suffix }}(v {{ $c.Type }}) {{ $intfName }} { return {{ $c.Value }} }
+{{ end -}}
 `)
 )
 
@@ -893,6 +1456,9 @@ func writeGoHeader(yangFiles, includePaths []string, cfg *CodeGenerator, rootNam
 		EmptyTypeName    string           // EmptyTypeName is the name of the type used for YANG empty types.
 		FakeRootName     string           // FakeRootName is the name of the fake root struct in the YANG type
 		ModelData        []*gpb.ModelData // ModelData contains the gNMI ModelData definition for the input types.
+		GetOrCreateAt    bool             // GetOrCreateAt indicates that a GetOrCreateAt method, which uses the gNMI protobuf, is generated for the fake root.
+		CustomBinaryType *customGoType    // CustomBinaryType is the user-specified type used for YANG binary leaves, if any.
+		CustomEmptyType  *customGoType    // CustomEmptyType is the user-specified type used for YANG empty leaves, if any.
 	}{
 		PackageName:      cfg.GoOptions.PackageName,
 		YANGFiles:        yangFiles,
@@ -906,9 +1472,21 @@ func writeGoHeader(yangFiles, includePaths []string, cfg *CodeGenerator, rootNam
 		ModelData:        modelData,
 	}
 
+	var err error
+	if s.CustomBinaryType, err = parseCustomGoType(cfg.GoOptions.BinaryType, customBinaryTypeName); err != nil {
+		return "", "", fmt.Errorf("invalid binary type: %v", err)
+	}
+	if s.CustomEmptyType, err = parseCustomGoType(cfg.GoOptions.EmptyType, customEmptyTypeName); err != nil {
+		return "", "", fmt.Errorf("invalid empty type: %v", err)
+	}
+	if (s.CustomBinaryType != nil || s.CustomEmptyType != nil) && cfg.GoOptions.SplitByModule {
+		return "", "", fmt.Errorf("user-specified binary and empty types are not supported when splitting code by module")
+	}
+
 	s.FakeRootName = "nil"
 	if cfg.IROptions.TransformationOptions.GenerateFakeRoot && rootName != "" {
 		s.FakeRootName = fmt.Sprintf("&%s{}", rootName)
+		s.GetOrCreateAt = cfg.GoOptions.GenerateGetOrCreateAt && cfg.GoOptions.GenerateJSONSchema
 	}
 
 	var common bytes.Buffer
@@ -924,6 +1502,44 @@ func writeGoHeader(yangFiles, includePaths []string, cfg *CodeGenerator, rootNam
 	return common.String(), oneoff.String(), nil
 }
 
+const (
+	// customBinaryTypeName is the name of the alias of the user-specified
+	// type that is used for leaves with a YANG type of binary.
+	customBinaryTypeName = "CustomBinary"
+	// customEmptyTypeName is the name of the alias of the user-specified
+	// type that is used for leaves with a YANG type of empty.
+	customEmptyTypeName = "CustomEmpty"
+)
+
+// customGoType is a user-specified Go type that is used in place of a
+// generated type, which is referred to by an alias in the generated code.
+type customGoType struct {
+	// ImportPath is the import path of the package containing the type.
+	ImportPath string
+	// Name is the name of the type within its package.
+	Name string
+	// Alias is the name of the alias of the type in the generated code.
+	Alias string
+}
+
+// parseCustomGoType parses the reference ref to a Go type, of the form
+// "<import path>.<type name>", returning the type with the supplied alias.
+// It returns nil if ref is empty.
+func parseCustomGoType(ref, alias string) (*customGoType, error) {
+	if ref == "" {
+		return nil, nil
+	}
+	i := strings.LastIndex(ref, ".")
+	if i <= strings.LastIndex(ref, "/") {
+		return nil, fmt.Errorf("%q is not of the form <import path>.<type name>", ref)
+	}
+	t := &customGoType{ImportPath: ref[:i], Name: ref[i+1:], Alias: alias}
+	if !token.IsIdentifier(t.Name) || !token.IsExported(t.Name) {
+		return nil, fmt.Errorf("%q does not refer to an exported type", ref)
+	}
+	return t, nil
+}
+
 // IsScalarField determines which fields should be converted to pointers when
 // outputting structs; this is done to allow checks against nil.
 func IsScalarField(field *ygen.NodeDetails) bool {
@@ -940,6 +1556,9 @@ func IsScalarField(field *ygen.NodeDetails) bool {
 	// an unmapped type (interface{}), byte slice, or a leaflist can also use nil already, so they should also not be pointers.
 	case field.LangType.NativeType == ygot.BinaryTypeName, field.LangType.NativeType == ygot.EmptyTypeName, field.LangType.NativeType == "interface{}":
 		return false
+	// a Decimal64 uses its zero value to indicate that it is unset.
+	case field.LangType.NativeType == ygot.Decimal64TypeName:
+		return false
 	}
 	return true
 }
@@ -954,6 +1573,8 @@ func IsScalarField(field *ygen.NodeDetails) bool {
 //   - goStructElements - All existing YANG directories (for looking up children).
 //   - generatedUnions - Running map of generated unions to avoid generating the
 //     same union twice.
+//   - modulePrefix - Function returning the prefix of the YANG module with the
+//     supplied name, used when module prefix tags are generated.
 //   - goOpts - Go specific code generation options as a GoOpts struct.
 //
 // writeGoStruct returns a GoStructCodeSnippet which contains
@@ -962,7 +1583,7 @@ func IsScalarField(field *ygen.NodeDetails) bool {
 //     of targetStruct (listKeys).
 //  3. Methods with the struct corresponding to targetStruct as a receiver, e.g., for each
 //     list a NewListMember() method is generated.
-func writeGoStruct(targetStruct *ygen.ParsedDirectory, goStructElements map[string]*ygen.ParsedDirectory, generatedUnions map[string]bool, goOpts GoOpts) (GoStructCodeSnippet, []error) {
+func writeGoStruct(targetStruct *ygen.ParsedDirectory, goStructElements map[string]*ygen.ParsedDirectory, generatedUnions map[string]bool, modulePrefix func(string) string, goOpts GoOpts) (GoStructCodeSnippet, []error) {
 	if targetStruct == nil {
 		return GoStructCodeSnippet{}, []error{fmt.Errorf("cannot create code for nil targetStruct")}
 	}
@@ -975,6 +1596,7 @@ func writeGoStruct(targetStruct *ygen.ParsedDirectory, goStructElements map[stri
 		StructName:      targetStruct.Name,
 		YANGPath:        targetStruct.Path,
 		BelongingModule: targetStruct.BelongingModule,
+		LazySchema:      goOpts.LazySchema,
 	}
 
 	// associatedListKeyStructs is a slice containing the key structures for any multi-keyed
@@ -999,7 +1621,9 @@ func writeGoStruct(targetStruct *ygen.ParsedDirectory, goStructElements map[stri
 	var associatedLeafSetters []*generatedLeafSetter
 
 	associatedDefaultMethod := generatedDefaultMethod{
-		Receiver: targetStruct.Name,
+		Receiver:       targetStruct.Name,
+		ContainerTypes: map[string]string{},
+		WhenGuarded:    map[string]bool{},
 	}
 
 	// definedNameMap defines a map, keyed by YANG identifier to the Go struct field name.
@@ -1011,6 +1635,9 @@ func writeGoStruct(targetStruct *ygen.ParsedDirectory, goStructElements map[stri
 	// genUnionSet stores a set of union type names such that we can process
 	// each appearance of a union type within the struct once and only once.
 	genUnionSet := map[string]bool{}
+	// deepCopyUnions stores, keyed by the name of each union field of the
+	// struct, the cases of the type switch used to copy its values.
+	deepCopyUnions := map[string][]*deepCopyUnionCase{}
 
 	annotationPrefix := goOpts.AnnotationPrefix
 	// Set the default annotation prefix if it is unset.
@@ -1089,6 +1716,11 @@ func writeGoStruct(targetStruct *ygen.ParsedDirectory, goStructElements map[stri
 				IsYANGContainer: true,
 			}
 			associatedDefaultMethod.ChildContainerNames = append(associatedDefaultMethod.ChildContainerNames, fieldName)
+			associatedDefaultMethod.ContainerTypes[fieldName] = dir.Name
+			if len(field.YANGDetails.WhenConditions) != 0 {
+				associatedDefaultMethod.WhenGuarded[fieldName] = true
+				associatedDefaultMethod.HasWhenGuardedContainer = true
+			}
 		case ygen.LeafNode, ygen.LeafListNode:
 			// Only if this union has more than one subtype do we generate the union;
 			// otherwise, we use that subtype directly.
@@ -1145,9 +1777,14 @@ func writeGoStruct(targetStruct *ygen.ParsedDirectory, goStructElements map[stri
 				}
 				// Create the subtype documentation string.
 				intf.SubtypeDocumentation = strings.Join(genTypes, ", ")
+				intf.Constructors = unionConstructors(intf, goOpts.GenerateSimpleUnions)
 				genUnions = append(genUnions, intf)
 			}
 
+			if len(field.LangType.UnionTypes) > 1 {
+				deepCopyUnions[fieldName] = deepCopyUnionCases(field.LangType.NativeType, field.LangType.UnionTypes, goOpts.GenerateSimpleUnions)
+			}
+
 			fType := field.LangType.NativeType
 			zeroValue := field.LangType.ZeroValue
 
@@ -1162,6 +1799,19 @@ func writeGoStruct(targetStruct *ygen.ParsedDirectory, goStructElements map[stri
 
 			scalarField := IsScalarField(field)
 
+			// Leaves with a YANG type of binary or empty are represented
+			// by the aliases of the user-specified types where these are
+			// supplied, which are not pointers, as for the generated types.
+			defaultValue := field.LangType.DefaultValue
+			if field.Type == ygen.LeafNode {
+				switch {
+				case fType == ygot.BinaryTypeName && goOpts.BinaryType != "":
+					fType, zeroValue, defaultValue = customBinaryTypeName, "*new("+customBinaryTypeName+")", nil
+				case fType == ygot.EmptyTypeName && goOpts.EmptyType != "":
+					fType, zeroValue = customEmptyTypeName, "*new("+customEmptyTypeName+")"
+				}
+			}
+
 			definedNameMap[fName].IsPtr = scalarField
 
 			// If we are generating leaf getters, then append the relevant information
@@ -1173,8 +1823,11 @@ func writeGoStruct(targetStruct *ygen.ParsedDirectory, goStructElements map[stri
 				Zero:     zeroValue,
 				IsPtr:    scalarField,
 				Receiver: targetStruct.Name,
-				Default:  field.LangType.DefaultValue,
+				Default:  defaultValue,
 			})
+			if len(field.YANGDetails.WhenConditions) != 0 {
+				associatedDefaultMethod.WhenGuarded[fieldName] = true
+			}
 
 			// If we are generating leaf setters, then append the relevant information
 			// to the associatedLeafSetters slice to be generated along with other
@@ -1233,6 +1886,37 @@ func writeGoStruct(targetStruct *ygen.ParsedDirectory, goStructElements map[stri
 		tagBuf.WriteString(` module:"`)
 		addSchemaPathsToBuffers(field.MappedPathModules, false)
 
+		// modulePrefixes maps the supplied module paths to the prefixes
+		// of the modules.
+		modulePrefixes := func(modulePaths [][]string) ([][]string, error) {
+			var prefixes [][]string
+			for _, path := range modulePaths {
+				var p []string
+				for _, mod := range path {
+					prefix := ""
+					if modulePrefix != nil {
+						prefix = modulePrefix(mod)
+					}
+					if prefix == "" {
+						return nil, fmt.Errorf("cannot find prefix of module %s for field %s", mod, field.YANGDetails.Path)
+					}
+					p = append(p, prefix)
+				}
+				prefixes = append(prefixes, p)
+			}
+			return prefixes, nil
+		}
+
+		if goOpts.AddModulePrefixTags {
+			prefixes, err := modulePrefixes(field.MappedPathModules)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			tagBuf.WriteString(` module-prefix:"`)
+			addSchemaPathsToBuffers(prefixes, false)
+		}
+
 		if goOpts.IgnoreShadowSchemaPaths {
 			if len(field.ShadowMappedPaths) > 0 {
 				tagBuf.WriteString(` shadow-path:"`)
@@ -1242,6 +1926,15 @@ func writeGoStruct(targetStruct *ygen.ParsedDirectory, goStructElements map[stri
 				// Append a tag indicating the module that instantiates this field.
 				tagBuf.WriteString(` shadow-module:"`)
 				addSchemaPathsToBuffers(field.ShadowMappedPathModules, false)
+				if goOpts.AddModulePrefixTags {
+					prefixes, err := modulePrefixes(field.ShadowMappedPathModules)
+					if err != nil {
+						errs = append(errs, err)
+						continue
+					}
+					tagBuf.WriteString(` shadow-module-prefix:"`)
+					addSchemaPathsToBuffers(prefixes, false)
+				}
 			}
 		}
 
@@ -1253,6 +1946,20 @@ func writeGoStruct(targetStruct *ygen.ParsedDirectory, goStructElements map[stri
 			}
 		}
 
+		if goOpts.AddOpenConfigVersionTags && field.YANGDetails.OpenConfigVersion != "" {
+			tagBuf.WriteString(fmt.Sprintf(` ocVersion:"%s"`, field.YANGDetails.OpenConfigVersion))
+		}
+
+		if goOpts.AddKeylessListIndexTags && field.Type == ygen.ListNode && strings.HasPrefix(fieldDef.Type, "[]") {
+			// Keyless lists are represented as slices, whose entries
+			// are identified by a synthetic key holding their position.
+			tagBuf.WriteString(fmt.Sprintf(` keyless-index:"%s"`, ygot.KeylessListIndexKey))
+		}
+
+		if goOpts.GenerateStringMethod && isRedactedField(field, goOpts.RedactedSchemaPaths) {
+			tagBuf.WriteString(` sensitive:"true"`)
+		}
+
 		fieldDef.Tags = tagBuf.String()
 
 		// Append the generated field definition to the set of fields of the struct.
@@ -1366,12 +2073,16 @@ func writeGoStruct(targetStruct *ygen.ParsedDirectory, goStructElements map[stri
 	// are used for multi-type unions within the struct.
 	var interfaceBuf bytes.Buffer
 	for _, intf := range genUnions {
+		// The constructors of a union are output along with the
+		// definition of its type, since they are not methods.
+		var emitConstructors bool
 		if goOpts.GenerateSimpleUnions {
 			if _, ok := generatedUnions[intf.Name]; !ok {
 				if err := unionTypeSimpleTemplate.Execute(&interfaceBuf, intf); err != nil {
 					errs = append(errs, err)
 				}
 				generatedUnions[intf.Name] = true
+				emitConstructors = goOpts.GenerateUnionConstructors
 			}
 			if err := unionHelperSimpleTemplate.Execute(&interfaceBuf, intf); err != nil {
 				errs = append(errs, err)
@@ -1382,11 +2093,17 @@ func writeGoStruct(targetStruct *ygen.ParsedDirectory, goStructElements map[stri
 					errs = append(errs, err)
 				}
 				generatedUnions[intf.Name] = true
+				emitConstructors = goOpts.GenerateUnionConstructors
 			}
 			if err := unionHelperTemplate.Execute(&interfaceBuf, intf); err != nil {
 				errs = append(errs, err)
 			}
 		}
+		if emitConstructors {
+			if err := unionConstructorsTemplate.Execute(&interfaceBuf, intf); err != nil {
+				errs = append(errs, err)
+			}
+		}
 	}
 
 	if goOpts.GenerateJSONSchema {
@@ -1397,12 +2114,54 @@ func writeGoStruct(targetStruct *ygen.ParsedDirectory, goStructElements map[stri
 		if err := generateEnumTypeMapAccessor(&methodBuf, structDef); err != nil {
 			errs = append(errs, err)
 		}
+
+		if goOpts.GenerateGetOrCreateAt && targetStruct.IsFakeRoot {
+			if hasFieldNamed(structDef, "At") {
+				errs = append(errs, fmt.Errorf("cannot generate GetOrCreateAt method for %s, which has a field named At", structDef.StructName))
+			} else if err := goGetOrCreateAtTemplate.Execute(&methodBuf, structDef); err != nil {
+				errs = append(errs, err)
+			}
+		}
 	}
 
 	if err := generateBelongingModuleFunction(&methodBuf, structDef); err != nil {
 		errs = append(errs, err)
 	}
 
+	if goOpts.GenerateDeltaStructs {
+		if err := goDeltaStructTemplate.Execute(&methodBuf, generatedDeltaStruct{
+			Receiver: targetStruct.Name,
+			Leaves:   associatedLeafGetters,
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if goOpts.GenerateCapabilities {
+		if err := goCapabilitiesMethodTemplate.Execute(&methodBuf, structDef); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if goOpts.GenerateDeepCopy {
+		if hasFieldNamed(structDef, "DeepCopy") {
+			errs = append(errs, fmt.Errorf("cannot generate DeepCopy method for %s, which has a field named DeepCopy", structDef.StructName))
+		} else if err := goDeepCopyMethodTemplate.Execute(&methodBuf, deepCopyMethod(structDef, deepCopyUnions)); err != nil {
+			errs = append(errs, err)
+		}
+		for _, s := range associatedOrderedMapStructs {
+			if err := generateOrderedMapDeepCopy(&methodBuf, s); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if goOpts.GenerateStringMethod && !hasFieldNamed(structDef, "String") {
+		if err := goStringMethodTemplate.Execute(&methodBuf, structDef); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	return GoStructCodeSnippet{
 		StructName: structDef.StructName,
 		StructDef:  structBuf.String(),
@@ -1559,6 +2318,141 @@ func generateEnumTypeMapAccessor(b *bytes.Buffer, s generatedGoStruct) error {
 	return goEnumTypeMapAccessTemplate.Execute(b, s)
 }
 
+// unionConstructors returns the typed constructors of the union described by
+// intf, with one constructor for each of its subtypes other than those that
+// are not supported. simpleUnions indicates whether the union is generated
+// as a simple union, in which case the subtypes are converted to the union
+// using the typedefs of the built-in types, rather than wrapper structs.
+func unionConstructors(intf goUnionInterface, simpleUnions bool) []*unionConstructor {
+	var cs []*unionConstructor
+	for tn, t := range intf.Types {
+		if t == "interface{}" {
+			continue
+		}
+		c := &unionConstructor{
+			Suffix: yang.CamelCase(t),
+			Type:   t,
+		}
+		switch {
+		case !simpleUnions:
+			c.Value = fmt.Sprintf("&%s_%s{v}", intf.Name, tn)
+		case unionConversionSnippets[t] != nil:
+			c.Value = unionConversionSnippets[t].ConversionSnippet
+		default:
+			// Enumerated and other named types implement the union
+			// directly.
+			c.Value = "v"
+		}
+		cs = append(cs, c)
+	}
+	sort.Slice(cs, func(i, j int) bool { return cs[i].Suffix < cs[j].Suffix })
+	return cs
+}
+
+// isRedactedField reports whether the value of the leaf or leaf-list field
+// should be redacted by the generated String method, which is the case if it
+// is marked as sensitive in the YANG schema, or its schema path is within
+// redactedPaths.
+func isRedactedField(field *ygen.NodeDetails, redactedPaths []string) bool {
+	if field.Type != ygen.LeafNode && field.Type != ygen.LeafListNode {
+		return false
+	}
+	if field.YANGDetails.Sensitive {
+		return true
+	}
+	for _, p := range redactedPaths {
+		if p == field.YANGDetails.SchemaPath || (field.YANGDetails.ShadowSchemaPath != "" && p == field.YANGDetails.ShadowSchemaPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// deepCopyMethod returns the description of the DeepCopy method of the
+// struct s, whose union fields are described by unions, which is keyed by
+// the name of each union field.
+func deepCopyMethod(s generatedGoStruct, unions map[string][]*deepCopyUnionCase) generatedDeepCopyMethod {
+	m := generatedDeepCopyMethod{Receiver: s.StructName}
+	for _, f := range s.Fields {
+		df := &deepCopyField{Name: f.Name, Type: f.Type}
+		cases, isUnion := unions[f.Name]
+		switch {
+		case f.IsYANGContainer:
+			df.Kind = "struct"
+		case f.IsYANGList && strings.HasPrefix(f.Type, "map["):
+			df.Kind = "map"
+		case f.IsYANGList && strings.HasPrefix(f.Type, "[]"):
+			df.Kind = "structslice"
+		case f.IsYANGList:
+			df.Kind = "orderedmap"
+		case f.IsScalarField:
+			df.Kind = "ptr"
+		case isUnion && len(cases) != 0:
+			df.Kind = "union"
+			if strings.HasPrefix(f.Type, "[]") {
+				df.Kind = "unionslice"
+			}
+			df.UnionCases = cases
+		case f.Type == "[]"+ygot.BinaryTypeName:
+			df.Kind = "binaryslice"
+		case f.Type == ygot.BinaryTypeName, strings.HasPrefix(f.Type, "[]"):
+			df.Kind = "slice"
+		default:
+			df.Kind = "value"
+		}
+		m.Fields = append(m.Fields, df)
+	}
+	return m
+}
+
+// deepCopyUnionCases returns the cases of the type switch that copies a value
+// of the union named unionName, whose subtypes are unionTypes. Only subtypes
+// whose values are not immutable have a case. simpleUnions indicates that
+// the union is represented using typedefs of its subtypes, rather than
+// wrapper structs.
+func deepCopyUnionCases(unionName string, unionTypes map[string]ygen.MappedUnionSubtype, simpleUnions bool) []*deepCopyUnionCase {
+	var typeNames []string
+	for t := range unionTypes {
+		typeNames = append(typeNames, t)
+	}
+	sort.Strings(typeNames)
+
+	var cases []*deepCopyUnionCase
+	for _, t := range typeNames {
+		if simpleUnions {
+			switch t {
+			case ygot.BinaryTypeName:
+				cases = append(cases, &deepCopyUnionCase{Type: t, Copy: "append(Binary(nil), v...)"})
+			case "interface{}":
+				cases = append(cases, &deepCopyUnionCase{Type: "*UnionUnsupported", Copy: "&UnionUnsupported{v.Value}"})
+			}
+			continue
+		}
+		tn := yang.CamelCase(t)
+		if t == "interface{}" {
+			tn = "Interface"
+		}
+		wrapper := fmt.Sprintf("%s_%s", unionName, tn)
+		c := &deepCopyUnionCase{Type: "*" + wrapper, Copy: fmt.Sprintf("&%s{v.%s}", wrapper, tn)}
+		if t == ygot.BinaryTypeName {
+			c.Copy = fmt.Sprintf("&%s{append(Binary(nil), v.%s...)}", wrapper, tn)
+		}
+		cases = append(cases, c)
+	}
+	return cases
+}
+
+// hasFieldNamed reports whether the struct s has a field with the supplied
+// name.
+func hasFieldNamed(s generatedGoStruct, name string) bool {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // generateBelongingModuleFunction generates a function which returns the
 // belonging module as a string.
 func generateBelongingModuleFunction(b io.Writer, s generatedGoStruct) error {
@@ -607,6 +607,60 @@ func (t *QStruct) ΛValidate(opts ...ygot.ValidationOption) error {
 // that are included in the generated code.
 func (t *QStruct) ΛEnumTypeMap() map[string][]reflect.Type { return ΛEnumTypes }
 
+// ΛBelongingModule returns the name of the module that defines the namespace
+// of QStruct.
+func (*QStruct) ΛBelongingModule() string {
+	return "exmod"
+}
+`,
+		},
+	}, {
+		name: "struct with keyless list and index tags",
+		inStructToMap: &ygen.ParsedDirectory{
+			Name: "QStruct",
+			Fields: map[string]*ygen.NodeDetails{
+				"a-list": {
+					Name: "AList",
+					YANGDetails: ygen.YANGNodeDetails{
+						Name:              "a-list",
+						Defaults:          nil,
+						RootElementModule: "exmod",
+						Path:              "/root-module/q-struct/a-list",
+						LeafrefTargetPath: "",
+					},
+					Type:                    ygen.ListNode,
+					LangType:                nil,
+					MappedPaths:             [][]string{{"a-list"}},
+					MappedPathModules:       [][]string{{"exmod"}},
+					ShadowMappedPaths:       nil,
+					ShadowMappedPathModules: nil,
+				},
+			},
+			Path:            "/root-module/q-struct",
+			BelongingModule: "exmod",
+		},
+		inOtherStructMap: map[string]*ygen.ParsedDirectory{
+			"/root-module/q-struct/a-list": {
+				Name:            "QStruct_AList",
+				BelongingModule: "exmod",
+			},
+		},
+		inGoOpts: GoOpts{
+			AddKeylessListIndexTags: true,
+		},
+		want: wantGoStructOut{
+			structs: `
+// QStruct represents the /root-module/q-struct YANG schema element.
+type QStruct struct {
+	AList	[]*QStruct_AList	` + "`" + `path:"a-list" module:"exmod" keyless-index:"_index"` + "`" + `
+}
+
+// IsYANGGoStruct ensures that QStruct implements the yang.GoStruct
+// interface. This allows functions that need to handle this struct to
+// identify it as being generated by ygen.
+func (*QStruct) IsYANGGoStruct() {}
+`,
+			methods: `
 // ΛBelongingModule returns the name of the module that defines the namespace
 // of QStruct.
 func (*QStruct) ΛBelongingModule() string {
@@ -2356,7 +2410,7 @@ func (t *Container) To_Container_U1_Union(i interface{}) (Container_U1_Union, er
 			tt.inOtherStructMap[tt.inStructToMap.Path] = tt.inStructToMap
 			// Always generate the JSON schema for this test.
 			generatedUnions := map[string]bool{}
-			got, errs := writeGoStruct(tt.inStructToMap, tt.inOtherStructMap, generatedUnions, tt.inGoOpts)
+			got, errs := writeGoStruct(tt.inStructToMap, tt.inOtherStructMap, generatedUnions, nil, tt.inGoOpts)
 
 			if len(errs) != 0 && !tt.want.wantErr {
 				t.Fatalf("%s writeGoStruct(targetStruct: %v): received unexpected errors: %v",
@@ -315,6 +315,24 @@ func (o *{{ .StructName }}) AppendNew(
 	o.valueMap[key] = newElement
 	return newElement, nil
 }
+`)
+
+	goOrderedMapDeepCopyTemplate = mustMakeTemplate("orderedMapDeepCopy", `
+// DeepCopy returns a deep copy of {{ .StructName }}, in which each of its
+// values is copied using its DeepCopy method.
+func (o *{{ .StructName }}) DeepCopy() *{{ .StructName }} {
+	if o == nil {
+		return nil
+	}
+	n := &{{ .StructName }}{
+		keys:     append([]{{ .KeyName }}(nil), o.keys...),
+		valueMap: make(map[{{ .KeyName }}]*{{ .ListTypeName }}, len(o.valueMap)),
+	}
+	for k, v := range o.valueMap {
+		n.valueMap[k] = v.DeepCopy()
+	}
+	return n
+}
 `)
 )
 
@@ -325,3 +343,7 @@ func generateOrderedMapParentMethods(buf *bytes.Buffer, method *generatedOrdered
 func generateOrderedMapStruct(buf *bytes.Buffer, method *generatedOrderedMapStruct) error {
 	return goOrderedMapTemplate.Execute(buf, method)
 }
+
+func generateOrderedMapDeepCopy(buf *bytes.Buffer, method *generatedOrderedMapStruct) error {
+	return goOrderedMapDeepCopyTemplate.Execute(buf, method)
+}
@@ -0,0 +1,250 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gogen
+
+import (
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/openconfig/ygot/ygen"
+)
+
+const (
+	// packageDocModulesHeading is the heading of the section of the
+	// package documentation that lists the input YANG modules.
+	packageDocModulesHeading = "Modules"
+	// packageDocOptionsHeading is the heading of the section of the
+	// package documentation that lists the generation options.
+	packageDocOptionsHeading = "Options"
+	// packageDocPathsHeading is the heading of the section of the package
+	// documentation that lists the schema paths covered by the package.
+	packageDocPathsHeading = "Schema paths"
+	// packageDocNone is output within the package documentation in place
+	// of a value that is not specified by a YANG module.
+	packageDocNone = "-"
+)
+
+// PackageDoc describes the contents of the package documentation that is
+// generated when the GeneratePackageDoc option is set. It can be recovered
+// from the generated doc.go file using ParsePackageDoc.
+type PackageDoc struct {
+	// PackageName is the name of the generated package.
+	PackageName string
+	// Modules are the YANG modules from which the package was generated,
+	// sorted by name.
+	Modules []*PackageDocModule
+	// Options are the non-default options with which the package was
+	// generated, in the order in which they are declared.
+	Options []*PackageDocOption
+	// SchemaPaths are the YANG schema paths of the containers and lists
+	// for which structs are generated, sorted by path.
+	SchemaPaths []*PackageDocPath
+}
+
+// PackageDocModule describes a YANG module from which a package was
+// generated.
+type PackageDocModule struct {
+	// Name is the name of the module.
+	Name string
+	// Revision is the most recent revision date of the module.
+	Revision string
+	// Version is the openconfig-version of the module.
+	Version string
+	// Organization is the organization that publishes the module.
+	Organization string
+}
+
+// PackageDocOption describes an option with which a package was generated.
+type PackageDocOption struct {
+	// Name is the name of the option, expressed as the path of its field
+	// within the CodeGenerator, e.g., "GoOptions.GenerateGetters".
+	Name string
+	// Value is the value of the option.
+	Value string
+}
+
+// PackageDocPath describes a YANG schema path that is covered by a package.
+type PackageDocPath struct {
+	// Path is the schema path of the container or list, or "/" for the
+	// fake root.
+	Path string
+	// Struct is the name of the struct generated for the path.
+	Struct string
+}
+
+// generatePackageDoc returns the PackageDoc of the package generated from ir
+// by cg, where structRegistry maps the schema path of each generated struct
+// to its name.
+func generatePackageDoc(cg *CodeGenerator, ir *ygen.IR, structRegistry map[string]string) *PackageDoc {
+	doc := &PackageDoc{PackageName: cg.GoOptions.PackageName}
+	for _, md := range ir.ModelData {
+		doc.Modules = append(doc.Modules, &PackageDocModule{
+			Name:         md.GetName(),
+			Revision:     ir.ModuleRevision(md.GetName()),
+			Version:      md.GetVersion(),
+			Organization: strings.Join(strings.Fields(md.GetOrganization()), " "),
+		})
+	}
+	sort.Slice(doc.Modules, func(i, j int) bool { return doc.Modules[i].Name < doc.Modules[j].Name })
+
+	doc.Options = append(packageDocOptions("IROptions", reflect.ValueOf(cg.IROptions)), packageDocOptions("GoOptions", reflect.ValueOf(cg.GoOptions))...)
+
+	for path, name := range structRegistry {
+		doc.SchemaPaths = append(doc.SchemaPaths, &PackageDocPath{Path: path, Struct: name})
+	}
+	sort.Slice(doc.SchemaPaths, func(i, j int) bool { return doc.SchemaPaths[i].Path < doc.SchemaPaths[j].Path })
+	return doc
+}
+
+// packageDocOptions returns the options that are set to a non-zero value
+// within the options struct v, whose names are prefixed by prefix. Options
+// that are structs are expanded into the options that they contain.
+func packageDocOptions(prefix string, v reflect.Value) []*PackageDocOption {
+	var opts []*PackageDocOption
+	for i := 0; i < v.NumField(); i++ {
+		ft, fv := v.Type().Field(i), v.Field(i)
+		if !ft.IsExported() || fv.IsZero() {
+			continue
+		}
+		name := fmt.Sprintf("%s.%s", prefix, ft.Name)
+		switch fv.Kind() {
+		case reflect.Struct:
+			opts = append(opts, packageDocOptions(name, fv)...)
+		case reflect.Slice:
+			var vals []string
+			for j := 0; j < fv.Len(); j++ {
+				vals = append(vals, fmt.Sprint(fv.Index(j).Interface()))
+			}
+			opts = append(opts, &PackageDocOption{Name: name, Value: strings.Join(vals, ",")})
+		case reflect.Bool, reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			opts = append(opts, &PackageDocOption{Name: name, Value: fmt.Sprint(fv.Interface())})
+		}
+	}
+	return opts
+}
+
+// moduleTable returns the lines of the table of modules that is output in
+// the package documentation, in which columns are aligned using spaces and
+// unspecified values are replaced by packageDocNone.
+func (d *PackageDoc) moduleTable() ([]string, error) {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	orNone := func(s string) string {
+		if s == "" {
+			return packageDocNone
+		}
+		return s
+	}
+	for _, m := range d.Modules {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", m.Name, orNone(m.Revision), orNone(m.Version), orNone(m.Organization))
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	if buf.Len() == 0 {
+		return nil, nil
+	}
+	var lines []string
+	for _, l := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		lines = append(lines, strings.TrimRight(l, " "))
+	}
+	return lines, nil
+}
+
+// writePackageDoc returns the contents of the doc.go file that documents the
+// package described by doc, which is generated from yangFiles, found within
+// includePaths, by cg.
+func writePackageDoc(doc *PackageDoc, yangFiles, includePaths []string, cg *CodeGenerator) (string, error) {
+	table, err := doc.moduleTable()
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := goPackageDocTemplate.Execute(&buf, struct {
+		Doc              *PackageDoc
+		ModuleTable      []string
+		YANGFiles        []string
+		IncludePaths     []string
+		CompressEnabled  bool
+		GeneratingBinary string
+	}{
+		Doc:              doc,
+		ModuleTable:      table,
+		YANGFiles:        yangFiles,
+		IncludePaths:     includePaths,
+		CompressEnabled:  cg.IROptions.TransformationOptions.CompressBehaviour.CompressEnabled(),
+		GeneratingBinary: cg.Caller,
+	}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ParsePackageDoc parses the PackageDoc from the source of the doc.go file
+// that is output when the GeneratePackageDoc option is set, such that tools
+// can determine the modules and options from which a generated package was
+// produced.
+func ParsePackageDoc(src []byte) (*PackageDoc, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "doc.go", src, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse package documentation: %v", err)
+	}
+	if f.Doc == nil {
+		return nil, fmt.Errorf("package %s has no package documentation", f.Name.Name)
+	}
+
+	doc := &PackageDoc{PackageName: f.Name.Name}
+	var section string
+	for _, line := range strings.Split(f.Doc.Text(), "\n") {
+		if h, ok := strings.CutPrefix(line, "# "); ok {
+			section = h
+			continue
+		}
+		switch {
+		case section == packageDocModulesHeading && strings.HasPrefix(line, "\t"):
+			fields := strings.Fields(line)
+			if len(fields) < 4 {
+				return nil, fmt.Errorf("invalid module %q, want name, revision, version and organization", strings.TrimSpace(line))
+			}
+			m := &PackageDocModule{Name: fields[0], Revision: fields[1], Version: fields[2], Organization: strings.Join(fields[3:], " ")}
+			for _, s := range []*string{&m.Revision, &m.Version, &m.Organization} {
+				if *s == packageDocNone {
+					*s = ""
+				}
+			}
+			doc.Modules = append(doc.Modules, m)
+		case section == packageDocOptionsHeading && strings.HasPrefix(line, "\t"):
+			name, value, ok := strings.Cut(strings.TrimSpace(line), ": ")
+			if !ok {
+				return nil, fmt.Errorf("invalid option %q, want name: value", strings.TrimSpace(line))
+			}
+			doc.Options = append(doc.Options, &PackageDocOption{Name: name, Value: value})
+		case section == packageDocPathsHeading && strings.HasPrefix(line, "  - "):
+			path, link, ok := strings.Cut(strings.TrimPrefix(line, "  - "), ": ")
+			if !ok || !strings.HasPrefix(link, "[") || !strings.HasSuffix(link, "]") {
+				return nil, fmt.Errorf("invalid schema path %q, want path: [struct]", strings.TrimSpace(line))
+			}
+			doc.SchemaPaths = append(doc.SchemaPaths, &PackageDocPath{Path: path, Struct: strings.Trim(link, "[]")})
+		}
+	}
+	return doc, nil
+}
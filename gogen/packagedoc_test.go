@@ -0,0 +1,155 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gogen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygot/ygen"
+)
+
+const packageDocTestModule = `
+module doc-test {
+  prefix "d";
+  namespace "urn:d";
+  organization "Test
+    organization";
+
+  revision 2023-01-01 {
+    description "Initial revision.";
+  }
+
+  revision 2024-02-03 {
+    description "Most recent revision.";
+  }
+
+  container top {
+    list item {
+      key "name";
+      leaf name { type string; }
+    }
+  }
+}
+`
+
+func TestGeneratePackageDoc(t *testing.T) {
+	yangFile := filepath.Join(t.TempDir(), "doc-test.yang")
+	if err := os.WriteFile(yangFile, []byte(packageDocTestModule), 0644); err != nil {
+		t.Fatalf("cannot write YANG module: %v", err)
+	}
+
+	cg := New("gen", ygen.IROptions{
+		TransformationOptions: ygen.TransformationOpts{
+			GenerateFakeRoot: true,
+			FakeRootName:     "root",
+		},
+	}, GoOpts{
+		PackageName:        "docpkg",
+		GenerateGetters:    true,
+		GeneratePackageDoc: true,
+	})
+	got, errs := cg.Generate([]string{yangFile}, nil)
+	if errs != nil {
+		t.Fatalf("Generate: got unexpected errors: %v", errs)
+	}
+
+	if strings.Contains(got.CommonHeader, "/*") || !strings.HasPrefix(got.CommonHeader, "package docpkg") {
+		t.Errorf("Generate: header contains package documentation, got:\n%s", got.CommonHeader)
+	}
+	for _, want := range []string{
+		"Package docpkg is a generated package",
+		"This package was generated by gen\nusing the following YANG input files:\n\t- " + yangFile,
+		"\tdoc-test  2024-02-03  -  Test organization\n",
+		"  - /top/item: [DocTest_Top_Item]\n",
+	} {
+		if !strings.Contains(got.PackageDoc, want) {
+			t.Errorf("Generate: package documentation does not contain %q, got:\n%s", want, got.PackageDoc)
+		}
+	}
+
+	gotDoc, err := ParsePackageDoc([]byte(got.PackageDoc))
+	if err != nil {
+		t.Fatalf("ParsePackageDoc: got unexpected error: %v", err)
+	}
+	wantDoc := &PackageDoc{
+		PackageName: "docpkg",
+		Modules: []*PackageDocModule{{
+			Name:         "doc-test",
+			Revision:     "2024-02-03",
+			Organization: "Test organization",
+		}},
+		Options: []*PackageDocOption{
+			{Name: "IROptions.TransformationOptions.GenerateFakeRoot", Value: "true"},
+			{Name: "IROptions.TransformationOptions.FakeRootName", Value: "root"},
+			{Name: "GoOptions.PackageName", Value: "docpkg"},
+			{Name: "GoOptions.GoyangImportPath", Value: "github.com/openconfig/goyang/pkg/yang"},
+			{Name: "GoOptions.YgotImportPath", Value: "github.com/openconfig/ygot/ygot"},
+			{Name: "GoOptions.YtypesImportPath", Value: "github.com/openconfig/ygot/ytypes"},
+			{Name: "GoOptions.GenerateGetters", Value: "true"},
+			{Name: "GoOptions.GNMIProtoPath", Value: "github.com/openconfig/gnmi/proto/gnmi"},
+			{Name: "GoOptions.GeneratePackageDoc", Value: "true"},
+		},
+		SchemaPaths: []*PackageDocPath{
+			{Path: "/", Struct: "Root"},
+			{Path: "/top", Struct: "DocTest_Top"},
+			{Path: "/top/item", Struct: "DocTest_Top_Item"},
+		},
+	}
+	if diff := cmp.Diff(wantDoc, gotDoc); diff != "" {
+		t.Errorf("ParsePackageDoc: did not get expected PackageDoc (-want, +got):\n%s", diff)
+	}
+}
+
+func TestParsePackageDocErrors(t *testing.T) {
+	tests := []struct {
+		desc             string
+		in               string
+		wantErrSubstring string
+	}{{
+		desc:             "invalid Go source",
+		in:               "not go",
+		wantErrSubstring: "cannot parse package documentation",
+	}, {
+		desc:             "no documentation",
+		in:               "package p\n",
+		wantErrSubstring: "package p has no package documentation",
+	}, {
+		desc:             "invalid module",
+		in:               "/*\n# Modules\n\n\tmod 2024-01-01\n*/\npackage p\n",
+		wantErrSubstring: `invalid module "mod 2024-01-01"`,
+	}, {
+		desc:             "invalid option",
+		in:               "/*\n# Options\n\n\tGoOptions.GenerateGetters\n*/\npackage p\n",
+		wantErrSubstring: `invalid option "GoOptions.GenerateGetters"`,
+	}, {
+		desc:             "invalid schema path",
+		in:               "/*\n# Schema paths\n\n  - /top: Top\n*/\npackage p\n",
+		wantErrSubstring: `invalid schema path "- /top: Top"`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := ParsePackageDoc([]byte(tt.in))
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Errorf("ParsePackageDoc: %s", diff)
+			}
+		})
+	}
+}
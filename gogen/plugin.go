@@ -0,0 +1,87 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gogen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openconfig/ygot/ygen"
+)
+
+// Plugin is an interface implemented by integrators to emit additional code
+// within the generated Go package, such as custom methods of the generated
+// structs, without modifying the code generator. Plugins are supplied in the
+// Plugins field of the CodeGenerator, and are invoked in order for each
+// directory that a struct is generated for, and each of its fields.
+//
+// The code returned by a plugin is appended to the methods of the struct of
+// the directory, such that it is output alongside the struct, including when
+// the generated code is split by module. It must be valid Go code at package
+// scope, and may only refer to the packages that are imported by the
+// generated code, i.e., encoding/json, fmt, reflect and the ygot package, and
+// the ytypes and goyang packages when the schema is generated.
+type Plugin interface {
+	// Name returns the name of the plugin, which is used to identify it
+	// within errors.
+	Name() string
+	// Directory returns the code that should be emitted for the directory
+	// dir of the IR ir, or the empty string if no code should be emitted.
+	Directory(ir *ygen.IR, dir *ygen.ParsedDirectory) (string, error)
+	// Field returns the code that should be emitted for the field of the
+	// directory dir of the IR ir, or the empty string if no code should be
+	// emitted. Fields are visited in the order in which they are output
+	// within the generated struct.
+	Field(ir *ygen.IR, dir *ygen.ParsedDirectory, field *ygen.NodeDetails) (string, error)
+}
+
+// pluginCode returns the code that is emitted by the supplied plugins for the
+// directory dir of the IR ir, which consists of the code emitted for the
+// directory by each plugin, followed by the code emitted for each of its
+// fields.
+func pluginCode(plugins []Plugin, ir *ygen.IR, dir *ygen.ParsedDirectory) (string, []error) {
+	var (
+		b    strings.Builder
+		errs []error
+	)
+	for _, p := range plugins {
+		code, err := p.Directory(ir, dir)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("plugin %s failed for directory %s: %v", p.Name(), dir.Path, err))
+			continue
+		}
+		writePluginCode(&b, code)
+		for _, fn := range dir.OrderedFieldNames() {
+			code, err := p.Field(ir, dir, dir.Fields[fn])
+			if err != nil {
+				errs = append(errs, fmt.Errorf("plugin %s failed for field %s of directory %s: %v", p.Name(), fn, dir.Path, err))
+				continue
+			}
+			writePluginCode(&b, code)
+		}
+	}
+	return b.String(), errs
+}
+
+// writePluginCode writes the code emitted by a plugin to b, if it is not
+// empty, separated from the preceding code by an empty line.
+func writePluginCode(b *strings.Builder, code string) {
+	if strings.TrimSpace(code) == "" {
+		return
+	}
+	b.WriteString("\n")
+	b.WriteString(strings.TrimRight(code, "\n"))
+	b.WriteString("\n")
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gogen
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygot/genutil"
+	"github.com/openconfig/ygot/ygen"
+)
+
+// testPlugin is a Plugin that emits a method returning the schema path of
+// each struct, and a constant for each leaf.
+type testPlugin struct {
+	// failField is the name of a field for which the plugin returns an
+	// error.
+	failField string
+}
+
+func (*testPlugin) Name() string { return "test" }
+
+func (*testPlugin) Directory(_ *ygen.IR, dir *ygen.ParsedDirectory) (string, error) {
+	return fmt.Sprintf("func (*%s) SchemaPath() string { return %q }\n", dir.Name, dir.SchemaPath), nil
+}
+
+func (p *testPlugin) Field(_ *ygen.IR, dir *ygen.ParsedDirectory, field *ygen.NodeDetails) (string, error) {
+	if field.Name == p.failField {
+		return "", fmt.Errorf("cannot handle field")
+	}
+	if field.Type != ygen.LeafNode {
+		return "", nil
+	}
+	return fmt.Sprintf("const %s_%s_Name = %q", dir.Name, field.Name, field.YANGDetails.Name), nil
+}
+
+func TestPlugins(t *testing.T) {
+	tests := []struct {
+		desc             string
+		inPlugins        []Plugin
+		wantMethods      map[string][]string
+		wantErrSubstring string
+	}{{
+		desc:      "plugin emits code",
+		inPlugins: []Plugin{&testPlugin{}},
+		wantMethods: map[string][]string{
+			"Parent": {
+				"\nfunc (*Parent) SchemaPath() string { return \"/parent\" }\n",
+			},
+			"Parent_Child": {
+				"\nfunc (*Parent_Child) SchemaPath() string { return \"/parent/child\" }\n",
+				"\nconst Parent_Child_Four_Name = \"four\"\n",
+				"\nconst Parent_Child_One_Name = \"one\"\n",
+			},
+		},
+	}, {
+		desc:             "plugin returns error",
+		inPlugins:        []Plugin{&testPlugin{failField: "One"}},
+		wantErrSubstring: "plugin test failed for field one of directory /openconfig-simple/parent/child: cannot handle field",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			cg := New("", ygen.IROptions{
+				TransformationOptions: ygen.TransformationOpts{
+					CompressBehaviour: genutil.PreferIntendedConfig,
+				},
+			}, GoOpts{})
+			cg.Plugins = tt.inPlugins
+			got, errs := cg.Generate([]string{filepath.Join(datapath, "openconfig-simple.yang")}, []string{datapath})
+			var err error
+			if errs != nil {
+				err = errs
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("Generate: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			methods := map[string]string{}
+			for _, s := range got.Structs {
+				methods[s.StructName] = s.Methods
+			}
+			for name, want := range tt.wantMethods {
+				for _, w := range want {
+					if !strings.Contains(methods[name], w) {
+						t.Errorf("Generate: methods of %s do not contain %q, got:\n%s", name, w, methods[name])
+					}
+				}
+			}
+		})
+	}
+}
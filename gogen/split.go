@@ -0,0 +1,426 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gogen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openconfig/ygot/ygen"
+)
+
+const (
+	// commonPackageSuffix is the suffix appended to the name of the root
+	// package to form the name of the package that contains the code that
+	// is shared by all packages when the generated code is split by
+	// module.
+	commonPackageSuffix = "common"
+)
+
+// packageNameReplacePattern matches all characters allowed in YANG module
+// names, but not in Go package names.
+var packageNameReplacePattern = regexp.MustCompile("[._-]")
+
+// modulePackageName returns the name of the Go package that contains the
+// structs for the YANG schema tree instantiated by the module rootModuleName
+// when the generated code is split by module. trimPrefix is removed from the
+// module name if it is non-empty.
+func modulePackageName(rootModuleName, trimPrefix string) string {
+	name := rootModuleName
+	if trimPrefix != "" {
+		name = strings.TrimPrefix(name, trimPrefix)
+	}
+	return strings.ToLower(packageNameReplacePattern.ReplaceAllString(name, ""))
+}
+
+// splitByModule divides the supplied generated code, which is for a single Go
+// package, into a set of Go packages, returning a map keyed by package name of
+// the source code of each package. The structs for the schema tree
+// instantiated by each YANG module are output into a package per module. The
+// enumerated types, union types, and the compressed schema, which may be
+// referenced by any struct, are output into a common package. The package
+// named by opts.PackageName contains the fake root, and the Schema function
+// that returns it. If the fake root is not generated, the Schema function is
+// output into the common package.
+//
+// References between the packages are qualified with the name of the
+// referenced package, which is imported using opts.BaseImportPath. If
+// opts.CommonUnionTypes is set, the types generated for unions are output
+// into the common package rather than the package of the struct within
+// whose code snippet they were first generated, such that all packages
+// refer to a single definition of each union type. An error is
+// returned if the generated packages would have an import cycle, which may
+// occur where structs instantiated by different modules reference one
+// another.
+func splitByModule(code *GeneratedCode, ir *ygen.IR, opts GoOpts) (map[string]string, error) {
+	rootPkg := opts.PackageName
+	commonPkg := rootPkg + commonPackageSuffix
+
+	// Determine the package of each struct.
+	structPkg := map[string]string{}
+	modules := map[string]string{}
+	for _, dir := range ir.Directories {
+		pkg := rootPkg
+		if !dir.IsFakeRoot {
+			pkg = modulePackageName(dir.RootElementModule, opts.TrimPackagePrefix)
+			if pkg == rootPkg || pkg == commonPkg {
+				return nil, fmt.Errorf("package name %s for module %s conflicts with a generated package", pkg, dir.RootElementModule)
+			}
+			modules[pkg] = dir.RootElementModule
+		}
+		structPkg[dir.Name] = pkg
+	}
+
+	// declPkg stores the package of each top-level type, function,
+	// variable, and constant declared within the code snippet of a struct.
+	declPkg := map[string]string{}
+	var src strings.Builder
+	src.WriteString(code.CommonHeader)
+	src.WriteString(code.OneOffHeader)
+	for _, snippet := range code.Structs {
+		s := snippet.String()
+		f, err := parser.ParseFile(token.NewFileSet(), "", "package p\n"+s, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse generated code for struct %s: %v", snippet.StructName, err)
+		}
+		unionTypes := map[string]bool{}
+		if opts.CommonUnionTypes && snippet.Interfaces != "" {
+			uf, err := parser.ParseFile(token.NewFileSet(), "", "package p\n"+snippet.Interfaces, parser.SkipObjectResolution)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse generated union types for struct %s: %v", snippet.StructName, err)
+			}
+			for _, d := range uf.Decls {
+				if gd, ok := d.(*ast.GenDecl); ok && gd.Tok == token.TYPE {
+					for _, n := range declNames(d) {
+						unionTypes[n] = true
+					}
+				}
+			}
+		}
+		for _, d := range f.Decls {
+			for _, n := range declNames(d) {
+				// Union types that are output into the common
+				// package are not assigned to the struct's
+				// package, and nor are their methods.
+				if !unionTypes[n] {
+					declPkg[n] = structPkg[snippet.StructName]
+				}
+			}
+		}
+		fmt.Fprintln(&src, s)
+	}
+	for _, s := range code.Enums {
+		fmt.Fprintln(&src, s)
+	}
+	for _, s := range []string{code.EnumMap, code.JSONSchemaCode, code.EnumTypeMap, code.StructRegistry, code.Capabilities, code.MetadataAnnotations} {
+		fmt.Fprintln(&src, s)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src.String(), parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse generated code: %v", err)
+	}
+
+	// Index the top-level declarations, such that references to them can
+	// be found.
+	var decls []ast.Decl
+	var imports []*ast.ImportSpec
+	topLevel := map[interface{}]int{}
+	for _, d := range file.Decls {
+		if gd, ok := d.(*ast.GenDecl); ok && gd.Tok == token.IMPORT {
+			for _, s := range gd.Specs {
+				imports = append(imports, s.(*ast.ImportSpec))
+			}
+			continue
+		}
+		i := len(decls)
+		decls = append(decls, d)
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			topLevel[d] = i
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				topLevel[s] = i
+			}
+		}
+	}
+
+	// Determine the references made by each declaration.
+	type ref struct {
+		ident *ast.Ident
+		decl  int
+	}
+	refs := make([][]ref, len(decls))
+	usedImports := make([]map[string]bool, len(decls))
+	for i, d := range decls {
+		usedImports[i] = map[string]bool{}
+		// Keys of struct literals are field names rather than
+		// references.
+		fieldKeys := map[*ast.Ident]bool{}
+		ast.Inspect(d, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.CompositeLit:
+				if _, isMap := n.Type.(*ast.MapType); !isMap {
+					for _, e := range n.Elts {
+						if kv, ok := e.(*ast.KeyValueExpr); ok {
+							if k, ok := kv.Key.(*ast.Ident); ok {
+								fieldKeys[k] = true
+							}
+						}
+					}
+				}
+			case *ast.SelectorExpr:
+				if x, ok := n.X.(*ast.Ident); ok && x.Obj == nil {
+					usedImports[i][x.Name] = true
+				}
+			case *ast.Ident:
+				if n.Obj == nil || fieldKeys[n] {
+					return true
+				}
+				if j, ok := topLevel[n.Obj.Decl]; ok && j != i {
+					refs[i] = append(refs[i], ref{ident: n, decl: j})
+				}
+			}
+			return true
+		})
+	}
+
+	// Assign each declaration to a package. Methods belong to the package
+	// of their receiver. Declarations that are not within the code snippet
+	// of a struct belong to the common package unless they reference a
+	// struct, in which case they belong to the root package.
+	pkgs := make([]string, len(decls))
+	for i, d := range decls {
+		for _, n := range declNames(d) {
+			if p, ok := declPkg[n]; ok {
+				pkgs[i] = p
+			}
+		}
+	}
+	for i, d := range decls {
+		if fd, ok := d.(*ast.FuncDecl); ok && fd.Recv != nil {
+			pkgs[i] = declPkg[receiverTypeName(fd)]
+		}
+	}
+	for i := range decls {
+		if pkgs[i] != "" {
+			continue
+		}
+		pkgs[i] = commonPkg
+		for _, r := range refs[i] {
+			if p := pkgs[r.decl]; p != "" && p != commonPkg {
+				pkgs[i] = rootPkg
+				break
+			}
+		}
+	}
+	// Qualify references between packages.
+	deps := map[string]map[string]bool{}
+	for i := range decls {
+		for _, r := range refs[i] {
+			from, to := pkgs[i], pkgs[r.decl]
+			if from == to {
+				continue
+			}
+			if !ast.IsExported(r.ident.Name) {
+				return nil, fmt.Errorf("generated package %s cannot reference unexported identifier %s in package %s", from, r.ident.Name, to)
+			}
+			r.ident.Name = to + "." + r.ident.Name
+			if deps[from] == nil {
+				deps[from] = map[string]bool{}
+			}
+			deps[from][to] = true
+		}
+	}
+	if cycle := findImportCycle(deps); cycle != nil {
+		return nil, fmt.Errorf("generated packages have an import cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	cmap := ast.NewCommentMap(fset, file, file.Comments)
+	out := map[string]string{}
+	bodies := map[string]*bytes.Buffer{}
+	pkgImports := map[string]map[string]bool{}
+	for i, d := range decls {
+		p := pkgs[i]
+		if bodies[p] == nil {
+			bodies[p] = &bytes.Buffer{}
+			pkgImports[p] = map[string]bool{}
+		}
+		if err := printer.Fprint(bodies[p], fset, &printer.CommentedNode{Node: d, Comments: cmap.Filter(d).Comments()}); err != nil {
+			return nil, fmt.Errorf("cannot print generated code for package %s: %v", p, err)
+		}
+		bodies[p].WriteString("\n\n")
+		for n := range usedImports[i] {
+			pkgImports[p][n] = true
+		}
+	}
+
+	for p, body := range bodies {
+		var b bytes.Buffer
+		switch p {
+		case rootPkg:
+			if file.Doc != nil {
+				fmt.Fprintf(&b, "/*\n%s*/\n", file.Doc.Text())
+			}
+		case commonPkg:
+			fmt.Fprintf(&b, "// Package %s is a generated package which contains the enumerated types,\n// union types, and schema that are shared by the generated packages\n// for the structs of package %s.\n", p, rootPkg)
+		default:
+			fmt.Fprintf(&b, "// Package %s is a generated package which contains the definitions of\n// structs for the YANG schema tree instantiated by the module %s.\n", p, modules[p])
+		}
+		// The imports are output as two groups, the first containing
+		// the standard library packages.
+		var stdImports, otherImports []string
+		for _, is := range imports {
+			ipath, err := strconv.Unquote(is.Path.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid import path %s: %v", is.Path.Value, err)
+			}
+			name := path.Base(ipath)
+			spec := strconv.Quote(ipath)
+			if is.Name != nil {
+				name = is.Name.Name
+				spec = name + " " + spec
+			}
+			switch {
+			case !pkgImports[p][name]:
+			case strings.Contains(strings.Split(ipath, "/")[0], "."):
+				otherImports = append(otherImports, spec)
+			default:
+				stdImports = append(stdImports, spec)
+			}
+		}
+		for dep := range deps[p] {
+			otherImports = append(otherImports, strconv.Quote(path.Join(opts.BaseImportPath, dep)))
+		}
+		sort.Strings(stdImports)
+		sort.Strings(otherImports)
+		fmt.Fprintf(&b, "package %s\n\nimport (\n", p)
+		for _, is := range stdImports {
+			fmt.Fprintf(&b, "\t%s\n", is)
+		}
+		if len(stdImports) != 0 && len(otherImports) != 0 {
+			b.WriteString("\n")
+		}
+		for _, is := range otherImports {
+			fmt.Fprintf(&b, "\t%s\n", is)
+		}
+		b.WriteString(")\n\n")
+		b.Write(body.Bytes())
+
+		formatted, err := format.Source(b.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("cannot format generated code for package %s: %v", p, err)
+		}
+		out[p] = string(formatted)
+	}
+	return out, nil
+}
+
+// declNames returns the names of the types, functions, variables and
+// constants declared by the supplied top-level declaration. Methods are not
+// included.
+func declNames(d ast.Decl) []string {
+	var names []string
+	switch d := d.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil {
+			names = append(names, d.Name.Name)
+		}
+	case *ast.GenDecl:
+		for _, s := range d.Specs {
+			switch s := s.(type) {
+			case *ast.TypeSpec:
+				names = append(names, s.Name.Name)
+			case *ast.ValueSpec:
+				for _, n := range s.Names {
+					names = append(names, n.Name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// receiverTypeName returns the name of the type of the receiver of the method
+// fd.
+func receiverTypeName(fd *ast.FuncDecl) string {
+	t := fd.Recv.List[0].Type
+	if s, ok := t.(*ast.StarExpr); ok {
+		t = s.X
+	}
+	if id, ok := t.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+// findImportCycle returns the packages that form an import cycle within the
+// supplied dependency graph, which is keyed by package name, or nil if there
+// is no cycle.
+func findImportCycle(deps map[string]map[string]bool) []string {
+	var pkgs []string
+	for p := range deps {
+		pkgs = append(pkgs, p)
+	}
+	sort.Strings(pkgs)
+
+	state := map[string]int{}
+	var stack []string
+	var visit func(p string) []string
+	visit = func(p string) []string {
+		switch state[p] {
+		case 1:
+			for i, s := range stack {
+				if s == p {
+					return append(append([]string{}, stack[i:]...), p)
+				}
+			}
+		case 2:
+			return nil
+		}
+		state[p] = 1
+		stack = append(stack, p)
+		var next []string
+		for d := range deps[p] {
+			next = append(next, d)
+		}
+		sort.Strings(next)
+		for _, d := range next {
+			if c := visit(d); c != nil {
+				return c
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[p] = 2
+		return nil
+	}
+	for _, p := range pkgs {
+		if c := visit(p); c != nil {
+			return c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gogen
+
+import (
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygot/genutil"
+	"github.com/openconfig/ygot/ygen"
+)
+
+func TestSplitByModule(t *testing.T) {
+	tests := []struct {
+		desc             string
+		inFakeRoot       bool
+		inPackageName    string
+		inTrimPrefix     string
+		inCommonUnions   bool
+		wantPackages     []string
+		wantContains     map[string][]string
+		wantNotContains  map[string][]string
+		wantErrSubstring string
+	}{{
+		desc:          "with fake root",
+		inFakeRoot:    true,
+		inPackageName: "oc",
+		wantPackages:  []string{"enummodule", "oc", "occommon", "openconfigunione", "openconfigwithlist"},
+		wantContains: map[string][]string{
+			"oc": {
+				"type Device struct",
+				"*openconfigwithlist.Model",
+				`"github.com/openconfig/ygot/oc/openconfigwithlist"`,
+				"func Schema() (*ytypes.Schema, error)",
+				"Unmarshal:  occommon.Unmarshal",
+			},
+			"occommon": {
+				"type E_OpenconfigUnione_DupEnum_A int64",
+				"func Unmarshal(",
+				"var ΛEnum =",
+				"func (E_OpenconfigUnione_Component_Power) Documentation_for_Platform_Component_Power_Union() {}",
+			},
+			"openconfigunione": {
+				"type DupEnum struct",
+				"A occommon.E_OpenconfigUnione_DupEnum_A",
+				"occommon.SchemaTree[\"DupEnum\"]",
+				"type Platform_Component_Power_Union interface",
+			},
+			"openconfigwithlist": {
+				"type Model struct",
+				"SingleKeyOrdered *Model_SingleKeyOrdered_OrderedMap",
+			},
+		},
+	}, {
+		desc:          "without fake root, with trimmed prefix",
+		inPackageName: "oc",
+		inTrimPrefix:  "openconfig-",
+		wantPackages:  []string{"enummodule", "occommon", "unione", "withlist"},
+		wantContains: map[string][]string{
+			"occommon": {
+				"Root:       nil",
+			},
+		},
+	}, {
+		desc:           "with union types in common package",
+		inFakeRoot:     true,
+		inPackageName:  "oc",
+		inCommonUnions: true,
+		wantPackages:   []string{"enummodule", "oc", "occommon", "openconfigunione", "openconfigwithlist"},
+		wantContains: map[string][]string{
+			"occommon": {
+				"type Platform_Component_Power_Union interface",
+				"func (E_OpenconfigUnione_Component_Power) Documentation_for_Platform_Component_Power_Union() {}",
+			},
+			"openconfigunione": {
+				"Power      occommon.Platform_Component_Power_Union",
+				"func (t *Platform_Component) To_Platform_Component_Power_Union(i interface{}) (occommon.Platform_Component_Power_Union, error)",
+			},
+		},
+		wantNotContains: map[string][]string{
+			"openconfigunione": {
+				"type Platform_Component_Power_Union interface",
+			},
+		},
+	}, {
+		desc:             "conflicting package name",
+		inPackageName:    "enummodule",
+		wantErrSubstring: "conflicts with a generated package",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			cg := New("", ygen.IROptions{
+				TransformationOptions: ygen.TransformationOpts{
+					CompressBehaviour:          genutil.PreferIntendedConfig,
+					GenerateFakeRoot:           tt.inFakeRoot,
+					EnumerationsUseUnderscores: true,
+				},
+			}, GoOpts{
+				PackageName:          tt.inPackageName,
+				GenerateJSONSchema:   true,
+				GenerateSimpleUnions: true,
+				SplitByModule:        true,
+				TrimPackagePrefix:    tt.inTrimPrefix,
+				CommonUnionTypes:     tt.inCommonUnions,
+				BaseImportPath:       "github.com/openconfig/ygot/oc",
+			})
+			got, errs := cg.Generate([]string{
+				filepath.Join(datapath, "openconfig-withlist.yang"),
+				filepath.Join(datapath, "enum-module.yang"),
+				filepath.Join(datapath, "openconfig-unione.yang"),
+			}, nil)
+			var err error
+			if errs != nil {
+				err = errs
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("Generate: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+
+			var gotPackages []string
+			for name, code := range got.Packages {
+				gotPackages = append(gotPackages, name)
+				f, err := parser.ParseFile(token.NewFileSet(), "", code, parser.PackageClauseOnly)
+				if err != nil {
+					t.Errorf("package %s: cannot parse generated code: %v", name, err)
+					continue
+				}
+				if f.Name.Name != name {
+					t.Errorf("package %s: got package clause %s", name, f.Name.Name)
+				}
+			}
+			sort.Strings(gotPackages)
+			if diff := cmp.Diff(tt.wantPackages, gotPackages); diff != "" {
+				t.Errorf("Generate: did not get expected packages (-want, +got):\n%s", diff)
+			}
+			for pkg, want := range tt.wantContains {
+				for _, s := range want {
+					if !strings.Contains(got.Packages[pkg], s) {
+						t.Errorf("package %s: generated code does not contain %q", pkg, s)
+					}
+				}
+			}
+			for pkg, notWant := range tt.wantNotContains {
+				for _, s := range notWant {
+					if strings.Contains(got.Packages[pkg], s) {
+						t.Errorf("package %s: generated code contains %q", pkg, s)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFindImportCycle(t *testing.T) {
+	tests := []struct {
+		desc   string
+		inDeps map[string]map[string]bool
+		want   []string
+	}{{
+		desc: "no cycle",
+		inDeps: map[string]map[string]bool{
+			"root": {"a": true, "b": true, "common": true},
+			"a":    {"common": true},
+			"b":    {"a": true, "common": true},
+		},
+	}, {
+		desc: "cycle",
+		inDeps: map[string]map[string]bool{
+			"root": {"a": true},
+			"a":    {"b": true},
+			"b":    {"a": true},
+		},
+		want: []string{"a", "b", "a"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, findImportCycle(tt.inDeps)); diff != "" {
+				t.Errorf("findImportCycle: (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -710,6 +710,80 @@ func (*Device) ΛBelongingModule() string {
 	return ""
 }
 
+// DeepCopy returns a deep copy of Device, which is made without
+// using reflection. Empty lists and leaf-lists are not copied.
+func (t *Device) DeepCopy() *Device {
+	if t == nil {
+		return nil
+	}
+	n := &Device{}
+	if len(t.ΛMetadata) != 0 {
+		n.ΛMetadata = append([]ygot.Annotation(nil), t.ΛMetadata...)
+	}
+	if t.OrderedList.Len() != 0 {
+		n.OrderedList = t.OrderedList.DeepCopy()
+	}
+	if len(t.ΛOrderedList) != 0 {
+		n.ΛOrderedList = append([]ygot.Annotation(nil), t.ΛOrderedList...)
+	}
+	if t.OrderedMultikeyedList.Len() != 0 {
+		n.OrderedMultikeyedList = t.OrderedMultikeyedList.DeepCopy()
+	}
+	if len(t.ΛOrderedMultikeyedList) != 0 {
+		n.ΛOrderedMultikeyedList = append([]ygot.Annotation(nil), t.ΛOrderedMultikeyedList...)
+	}
+	n.OtherData = t.OtherData.DeepCopy()
+	if len(t.ΛOtherData) != 0 {
+		n.ΛOtherData = append([]ygot.Annotation(nil), t.ΛOtherData...)
+	}
+	if len(t.UnorderedList) != 0 {
+		n.UnorderedList = make(map[string]*UnorderedList, len(t.UnorderedList))
+		for k, v := range t.UnorderedList {
+			n.UnorderedList[k] = v.DeepCopy()
+		}
+	}
+	if len(t.ΛUnorderedList) != 0 {
+		n.ΛUnorderedList = append([]ygot.Annotation(nil), t.ΛUnorderedList...)
+	}
+	return n
+}
+
+// ΛDeepCopy returns a deep copy of Device, such that it implements
+// the ygot.DeepCopier interface.
+func (t *Device) ΛDeepCopy() ygot.GoStruct { return t.DeepCopy() }
+
+// DeepCopy returns a deep copy of OrderedList_OrderedMap, in which each of its
+// values is copied using its DeepCopy method.
+func (o *OrderedList_OrderedMap) DeepCopy() *OrderedList_OrderedMap {
+	if o == nil {
+		return nil
+	}
+	n := &OrderedList_OrderedMap{
+		keys:     append([]string(nil), o.keys...),
+		valueMap: make(map[string]*OrderedList, len(o.valueMap)),
+	}
+	for k, v := range o.valueMap {
+		n.valueMap[k] = v.DeepCopy()
+	}
+	return n
+}
+
+// DeepCopy returns a deep copy of OrderedMultikeyedList_OrderedMap, in which each of its
+// values is copied using its DeepCopy method.
+func (o *OrderedMultikeyedList_OrderedMap) DeepCopy() *OrderedMultikeyedList_OrderedMap {
+	if o == nil {
+		return nil
+	}
+	n := &OrderedMultikeyedList_OrderedMap{
+		keys:     append([]OrderedMultikeyedList_Key(nil), o.keys...),
+		valueMap: make(map[OrderedMultikeyedList_Key]*OrderedMultikeyedList, len(o.valueMap)),
+	}
+	for k, v := range o.valueMap {
+		n.valueMap[k] = v.DeepCopy()
+	}
+	return n
+}
+
 // OrderedList represents the /ctestschema/ordered-lists/ordered-list YANG schema element.
 type OrderedList struct {
 	ΛMetadata    []ygot.Annotation                   `path:"@" ygotAnnotation:"true"`
@@ -1018,6 +1092,73 @@ func (*OrderedList) ΛBelongingModule() string {
 	return "ctestschema"
 }
 
+// DeepCopy returns a deep copy of OrderedList, which is made without
+// using reflection. Empty lists and leaf-lists are not copied.
+func (t *OrderedList) DeepCopy() *OrderedList {
+	if t == nil {
+		return nil
+	}
+	n := &OrderedList{}
+	if len(t.ΛMetadata) != 0 {
+		n.ΛMetadata = append([]ygot.Annotation(nil), t.ΛMetadata...)
+	}
+	if t.Key != nil {
+		v := *t.Key
+		n.Key = &v
+	}
+	if len(t.ΛKey) != 0 {
+		n.ΛKey = append([]ygot.Annotation(nil), t.ΛKey...)
+	}
+	if t.OrderedList.Len() != 0 {
+		n.OrderedList = t.OrderedList.DeepCopy()
+	}
+	if len(t.ΛOrderedList) != 0 {
+		n.ΛOrderedList = append([]ygot.Annotation(nil), t.ΛOrderedList...)
+	}
+	if t.ParentKey != nil {
+		v := *t.ParentKey
+		n.ParentKey = &v
+	}
+	if len(t.ΛParentKey) != 0 {
+		n.ΛParentKey = append([]ygot.Annotation(nil), t.ΛParentKey...)
+	}
+	if t.RoValue != nil {
+		v := *t.RoValue
+		n.RoValue = &v
+	}
+	if len(t.ΛRoValue) != 0 {
+		n.ΛRoValue = append([]ygot.Annotation(nil), t.ΛRoValue...)
+	}
+	if t.Value != nil {
+		v := *t.Value
+		n.Value = &v
+	}
+	if len(t.ΛValue) != 0 {
+		n.ΛValue = append([]ygot.Annotation(nil), t.ΛValue...)
+	}
+	return n
+}
+
+// ΛDeepCopy returns a deep copy of OrderedList, such that it implements
+// the ygot.DeepCopier interface.
+func (t *OrderedList) ΛDeepCopy() ygot.GoStruct { return t.DeepCopy() }
+
+// DeepCopy returns a deep copy of OrderedList_OrderedList_OrderedMap, in which each of its
+// values is copied using its DeepCopy method.
+func (o *OrderedList_OrderedList_OrderedMap) DeepCopy() *OrderedList_OrderedList_OrderedMap {
+	if o == nil {
+		return nil
+	}
+	n := &OrderedList_OrderedList_OrderedMap{
+		keys:     append([]string(nil), o.keys...),
+		valueMap: make(map[string]*OrderedList_OrderedList, len(o.valueMap)),
+	}
+	for k, v := range o.valueMap {
+		n.valueMap[k] = v.DeepCopy()
+	}
+	return n
+}
+
 // OrderedList_OrderedList represents the /ctestschema/ordered-lists/ordered-list/ordered-lists/ordered-list YANG schema element.
 type OrderedList_OrderedList struct {
 	ΛMetadata  []ygot.Annotation `path:"@" ygotAnnotation:"true"`
@@ -1130,6 +1271,44 @@ func (*OrderedList_OrderedList) ΛBelongingModule() string {
 	return "ctestschema"
 }
 
+// DeepCopy returns a deep copy of OrderedList_OrderedList, which is made without
+// using reflection. Empty lists and leaf-lists are not copied.
+func (t *OrderedList_OrderedList) DeepCopy() *OrderedList_OrderedList {
+	if t == nil {
+		return nil
+	}
+	n := &OrderedList_OrderedList{}
+	if len(t.ΛMetadata) != 0 {
+		n.ΛMetadata = append([]ygot.Annotation(nil), t.ΛMetadata...)
+	}
+	if t.Key != nil {
+		v := *t.Key
+		n.Key = &v
+	}
+	if len(t.ΛKey) != 0 {
+		n.ΛKey = append([]ygot.Annotation(nil), t.ΛKey...)
+	}
+	if t.ParentKey != nil {
+		v := *t.ParentKey
+		n.ParentKey = &v
+	}
+	if len(t.ΛParentKey) != 0 {
+		n.ΛParentKey = append([]ygot.Annotation(nil), t.ΛParentKey...)
+	}
+	if t.Value != nil {
+		v := *t.Value
+		n.Value = &v
+	}
+	if len(t.ΛValue) != 0 {
+		n.ΛValue = append([]ygot.Annotation(nil), t.ΛValue...)
+	}
+	return n
+}
+
+// ΛDeepCopy returns a deep copy of OrderedList_OrderedList, such that it implements
+// the ygot.DeepCopier interface.
+func (t *OrderedList_OrderedList) ΛDeepCopy() ygot.GoStruct { return t.DeepCopy() }
+
 // OrderedMultikeyedList represents the /ctestschema-rootmod/ordered-multikeyed-lists/ordered-multikeyed-list YANG schema element.
 type OrderedMultikeyedList struct {
 	ΛMetadata []ygot.Annotation `path:"@" ygotAnnotation:"true"`
@@ -1265,6 +1444,51 @@ func (*OrderedMultikeyedList) ΛBelongingModule() string {
 	return "ctestschema"
 }
 
+// DeepCopy returns a deep copy of OrderedMultikeyedList, which is made without
+// using reflection. Empty lists and leaf-lists are not copied.
+func (t *OrderedMultikeyedList) DeepCopy() *OrderedMultikeyedList {
+	if t == nil {
+		return nil
+	}
+	n := &OrderedMultikeyedList{}
+	if len(t.ΛMetadata) != 0 {
+		n.ΛMetadata = append([]ygot.Annotation(nil), t.ΛMetadata...)
+	}
+	if t.Key1 != nil {
+		v := *t.Key1
+		n.Key1 = &v
+	}
+	if len(t.ΛKey1) != 0 {
+		n.ΛKey1 = append([]ygot.Annotation(nil), t.ΛKey1...)
+	}
+	if t.Key2 != nil {
+		v := *t.Key2
+		n.Key2 = &v
+	}
+	if len(t.ΛKey2) != 0 {
+		n.ΛKey2 = append([]ygot.Annotation(nil), t.ΛKey2...)
+	}
+	if t.RoValue != nil {
+		v := *t.RoValue
+		n.RoValue = &v
+	}
+	if len(t.ΛRoValue) != 0 {
+		n.ΛRoValue = append([]ygot.Annotation(nil), t.ΛRoValue...)
+	}
+	if t.Value != nil {
+		v := *t.Value
+		n.Value = &v
+	}
+	if len(t.ΛValue) != 0 {
+		n.ΛValue = append([]ygot.Annotation(nil), t.ΛValue...)
+	}
+	return n
+}
+
+// ΛDeepCopy returns a deep copy of OrderedMultikeyedList, such that it implements
+// the ygot.DeepCopier interface.
+func (t *OrderedMultikeyedList) ΛDeepCopy() ygot.GoStruct { return t.DeepCopy() }
+
 // OtherData represents the /ctestschema/other-data YANG schema element.
 type OtherData struct {
 	ΛMetadata []ygot.Annotation `path:"@" ygotAnnotation:"true"`
@@ -1326,6 +1550,30 @@ func (*OtherData) ΛBelongingModule() string {
 	return "ctestschema"
 }
 
+// DeepCopy returns a deep copy of OtherData, which is made without
+// using reflection. Empty lists and leaf-lists are not copied.
+func (t *OtherData) DeepCopy() *OtherData {
+	if t == nil {
+		return nil
+	}
+	n := &OtherData{}
+	if len(t.ΛMetadata) != 0 {
+		n.ΛMetadata = append([]ygot.Annotation(nil), t.ΛMetadata...)
+	}
+	if t.Motd != nil {
+		v := *t.Motd
+		n.Motd = &v
+	}
+	if len(t.ΛMotd) != 0 {
+		n.ΛMotd = append([]ygot.Annotation(nil), t.ΛMotd...)
+	}
+	return n
+}
+
+// ΛDeepCopy returns a deep copy of OtherData, such that it implements
+// the ygot.DeepCopier interface.
+func (t *OtherData) ΛDeepCopy() ygot.GoStruct { return t.DeepCopy() }
+
 // UnorderedList represents the /ctestschema/unordered-lists/unordered-list YANG schema element.
 type UnorderedList struct {
 	ΛMetadata []ygot.Annotation `path:"@" ygotAnnotation:"true"`
@@ -1420,6 +1668,37 @@ func (*UnorderedList) ΛBelongingModule() string {
 	return "ctestschema"
 }
 
+// DeepCopy returns a deep copy of UnorderedList, which is made without
+// using reflection. Empty lists and leaf-lists are not copied.
+func (t *UnorderedList) DeepCopy() *UnorderedList {
+	if t == nil {
+		return nil
+	}
+	n := &UnorderedList{}
+	if len(t.ΛMetadata) != 0 {
+		n.ΛMetadata = append([]ygot.Annotation(nil), t.ΛMetadata...)
+	}
+	if t.Key != nil {
+		v := *t.Key
+		n.Key = &v
+	}
+	if len(t.ΛKey) != 0 {
+		n.ΛKey = append([]ygot.Annotation(nil), t.ΛKey...)
+	}
+	if t.Value != nil {
+		v := *t.Value
+		n.Value = &v
+	}
+	if len(t.ΛValue) != 0 {
+		n.ΛValue = append([]ygot.Annotation(nil), t.ΛValue...)
+	}
+	return n
+}
+
+// ΛDeepCopy returns a deep copy of UnorderedList, such that it implements
+// the ygot.DeepCopier interface.
+func (t *UnorderedList) ΛDeepCopy() ygot.GoStruct { return t.DeepCopy() }
+
 var (
 	// ySchema is a byte slice contain a gzip compressed representation of the
 	// YANG schema from which the Go code was generated. When uncompressed the
@@ -24,5 +24,6 @@ go run ../../../generator/generator.go -path="." -output_file=ctestschema.go \
   -generate_simple_unions \
   -generate_populate_defaults \
   -annotations \
+  -generate_deep_copy \
   ../yang/ctestschema.yang ../yang/ctestschema-rootmod.yang
 gofmt -w -s ctestschema.go
@@ -0,0 +1,336 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary openconfig_generator generates a complete set of Go bindings for
+// OpenConfig devices, consisting of GoStructs, their JSON schema, and path
+// structs, from a release of the OpenConfig models at
+// github.com/openconfig/public. The models are fetched using git, unless an
+// existing checkout is supplied, and the bindings are generated using the
+// options that are recommended for OpenConfig devices, which are those used to
+// generate the exampleoc package. For example:
+//
+//	go run github.com/openconfig/ygot/openconfig_generator -release=v4.0.0 -package_name=oc -output_dir=oc
+//
+// generates the package oc, within the directory oc, from the v4.0.0 release
+// of the models. Specifying the release pins the generated bindings, such that
+// regenerating them produces the same code.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	log "github.com/golang/glog"
+	"github.com/openconfig/ygot/genutil"
+	"github.com/openconfig/ygot/gogen"
+	"github.com/openconfig/ygot/ygen"
+	"github.com/openconfig/ygot/ygotgen"
+	"github.com/openconfig/ygot/ypathgen"
+)
+
+const (
+	// defaultRepo is the git repository containing the OpenConfig models.
+	defaultRepo = "https://github.com/openconfig/public.git"
+	// checkoutName is the name of the directory to which the OpenConfig
+	// models are fetched.
+	checkoutName = "public"
+	// modelsDir is the directory of a checkout of the OpenConfig models
+	// containing the released modules.
+	modelsDir = "release/models"
+	// thirdPartyDir is the directory of a checkout of the OpenConfig
+	// models containing the IETF and IANA modules that they import.
+	thirdPartyDir = "third_party"
+)
+
+// defaultModules are the modules, relative to the release/models directory
+// of the OpenConfig models, for which bindings are generated by default. They
+// describe the configuration and state of a typical network device.
+var defaultModules = []string{
+	"network-instance/openconfig-network-instance.yang",
+	"optical-transport/openconfig-optical-amplifier.yang",
+	"optical-transport/openconfig-terminal-device.yang",
+	"optical-transport/openconfig-transport-line-protection.yang",
+	"platform/openconfig-platform.yang",
+	"bgp/openconfig-bgp-policy.yang",
+	"policy/openconfig-routing-policy.yang",
+	"lacp/openconfig-lacp.yang",
+	"system/openconfig-system.yang",
+	"stp/openconfig-spanning-tree.yang",
+	"interfaces/openconfig-interfaces.yang",
+	"interfaces/openconfig-if-ip.yang",
+	"interfaces/openconfig-if-aggregate.yang",
+	"interfaces/openconfig-if-ethernet.yang",
+	"interfaces/openconfig-if-ip-ext.yang",
+	"relay-agent/openconfig-relay-agent.yang",
+	"aft/openconfig-aft-network-instance.yang",
+	"lldp/openconfig-lldp.yang",
+}
+
+var (
+	release        = flag.String("release", "", "The tag of the release of the OpenConfig models, e.g. v4.0.0, from which bindings are generated. If unset, the latest commit of the default branch is used, such that the generated bindings are not reproducible.")
+	repo           = flag.String("repo", defaultRepo, "The URL of the git repository from which the OpenConfig models are fetched.")
+	checkout       = flag.String("models_checkout", "", "If set, the directory of an existing checkout of the OpenConfig models, which is used rather than fetching the models. It cannot be specified with release.")
+	modules        = flag.String("modules", strings.Join(defaultModules, ","), "Comma separated list of the YANG modules, relative to the release/models directory of the OpenConfig models, for which bindings are generated.")
+	excludeModules = flag.String("exclude_modules", "ietf-interfaces", "Comma separated set of module names that should be excluded from code generation.")
+	outputDir      = flag.String("output_dir", ".", "The directory to which the generated bindings are written. It is created if it does not exist.")
+	goOutputFile   = flag.String("go_output_file", "oc.go", "The name of the file within output_dir to which the GoStructs and their JSON schema are written.")
+	pathOutputFile = flag.String("path_structs_output_file", "ocpath.go", "The name of the file within output_dir to which the path structs are written.")
+	packageName    = flag.String("package_name", "oc", "The name of the Go package of the generated bindings.")
+	callerName     = flag.String("caller_name", "openconfig_generator", "The name of the generator binary that should be recorded in output files.")
+)
+
+// splitFlag returns the comma-separated elements of the supplied flag value,
+// or nil if it is empty.
+func splitFlag(v string) []string {
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+// recommendedConfig returns the ygotgen configuration that is recommended for
+// generating bindings for OpenConfig devices, with the supplied caller name,
+// package name and excluded modules. Both GoStructs, including their JSON
+// schema, and path structs within the same package are generated.
+func recommendedConfig(caller, pkgName string, excluded []string) *ygotgen.Config {
+	pathOpts := ypathgen.NewDefaultConfig("")
+	pathOpts.PackageName = pkgName
+	pathOpts.GeneratingBinary = caller
+	pathOpts.ListBuilderKeyThreshold = 3
+
+	return &ygotgen.Config{
+		Caller: caller,
+		ParseOptions: ygen.ParseOpts{
+			ExcludeModules: excluded,
+		},
+		TransformationOptions: ygen.TransformationOpts{
+			CompressBehaviour:                    genutil.PreferIntendedConfig,
+			GenerateFakeRoot:                     true,
+			FakeRootName:                         "device",
+			ShortenEnumLeafNames:                 true,
+			EnumOrgPrefixesToTrim:                []string{"openconfig"},
+			UseDefiningModuleForTypedefEnumNames: true,
+			EnumerationsUseUnderscores:           true,
+		},
+		AppendEnumSuffixForSimpleUnionEnums: true,
+		GoOptions: &gogen.GoOpts{
+			PackageName:             pkgName,
+			GenerateJSONSchema:      true,
+			YgotImportPath:          genutil.GoDefaultYgotImportPath,
+			YtypesImportPath:        genutil.GoDefaultYtypesImportPath,
+			GoyangImportPath:        genutil.GoDefaultGoyangImportPath,
+			GenerateRenameMethod:    true,
+			AddAnnotationFields:     true,
+			AnnotationPrefix:        gogen.DefaultAnnotationPrefix,
+			GenerateGetters:         true,
+			GenerateLeafGetters:     true,
+			GenerateAppendMethod:    true,
+			GeneratePopulateDefault: true,
+			GenerateSimpleUnions:    true,
+		},
+		PathOptions: pathOpts,
+	}
+}
+
+// fetchModels fetches the OpenConfig models from the git repository at
+// repoURL to the directory dir, which must not exist. If tag is non-empty,
+// the models are fetched at that tag, otherwise the latest commit of the
+// default branch is fetched.
+func fetchModels(repoURL, tag, dir string) error {
+	args := []string{"-c", "advice.detachedHead=false", "clone", "--quiet", "--depth=1"}
+	if tag != "" {
+		args = append(args, "--branch="+tag)
+	}
+	args = append(args, repoURL, dir)
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		if tag != "" {
+			return fmt.Errorf("cannot fetch release %s of the OpenConfig models from %s: %v", tag, repoURL, err)
+		}
+		return fmt.Errorf("cannot fetch the OpenConfig models from %s: %v", repoURL, err)
+	}
+	return nil
+}
+
+// inputPaths returns the paths of the supplied modules, which are relative
+// to the release/models directory of the checkout of the OpenConfig models
+// named root, and the paths that are searched for the modules that they
+// import. The returned paths are relative to the parent directory of root,
+// such that they do not depend upon where the models were fetched to, and
+// hence must be used from that directory.
+func inputPaths(root string, mods []string) ([]string, []string, error) {
+	if len(mods) == 0 {
+		return nil, nil, fmt.Errorf("no input modules specified")
+	}
+	base := filepath.Base(root)
+	var yangFiles []string
+	for _, m := range mods {
+		if _, err := os.Stat(filepath.Join(root, modelsDir, m)); err != nil {
+			return nil, nil, fmt.Errorf("module %s not found within the OpenConfig models at %s: %v", m, root, err)
+		}
+		yangFiles = append(yangFiles, filepath.Join(base, modelsDir, m))
+	}
+	includePaths := []string{
+		filepath.Join(base, modelsDir, "..."),
+		filepath.Join(base, thirdPartyDir, "..."),
+	}
+	return yangFiles, includePaths, nil
+}
+
+// generatedHeader returns the comment that is prepended to each generated
+// file, which records the release of the OpenConfig models from which it was
+// generated, or whether it was generated from an existing checkout of them.
+func generatedHeader(caller, tag string, fromCheckout bool) string {
+	var from string
+	switch {
+	case fromCheckout:
+		from = "a checkout of the OpenConfig models"
+	case tag != "":
+		from = fmt.Sprintf("release %s of the OpenConfig models", tag)
+	default:
+		from = "the latest OpenConfig models"
+	}
+	return fmt.Sprintf("// Code generated by %s from %s. DO NOT EDIT.\n\n", caller, from)
+}
+
+// generate generates the bindings for the supplied modules of the checkout
+// of the OpenConfig models at root using cfg, and returns the contents of
+// the GoStruct and path struct files, each of which is prefixed by header.
+// The working directory must be the parent directory of root.
+func generate(root string, mods []string, cfg *ygotgen.Config, header string) (string, string, error) {
+	yangFiles, includePaths, err := inputPaths(root, mods)
+	if err != nil {
+		return "", "", err
+	}
+	code, errs := ygotgen.Generate(yangFiles, includePaths, cfg)
+	if errs != nil {
+		return "", "", fmt.Errorf("cannot generate code: %v", errs)
+	}
+	pathCode, ok := code.Paths[cfg.PathOptions.PackageName]
+	if !ok || len(code.Paths) != 1 {
+		return "", "", fmt.Errorf("expected path structs in a single package %s, got %d packages", cfg.PathOptions.PackageName, len(code.Paths))
+	}
+
+	goCode, err := formatCode(header + goCodeString(code.Structs))
+	if err != nil {
+		return "", "", fmt.Errorf("cannot format GoStruct code: %v", err)
+	}
+	pathCodeString, err := formatCode(header + pathCode.String())
+	if err != nil {
+		return "", "", fmt.Errorf("cannot format path struct code: %v", err)
+	}
+	return goCode, pathCodeString, nil
+}
+
+// formatCode returns the supplied Go code formatted and simplified in the
+// same way as gofmt -s.
+func formatCode(code string) (string, error) {
+	b, err := format.Source([]byte(code))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// goCodeString returns the supplied generated GoStruct code as a single Go
+// source file.
+func goCodeString(goCode *gogen.GeneratedCode) string {
+	var b strings.Builder
+	b.WriteString(goCode.CommonHeader)
+	b.WriteString(goCode.OneOffHeader)
+	for _, snippet := range goCode.Structs {
+		fmt.Fprintln(&b, snippet.String())
+	}
+	for _, snippet := range goCode.Enums {
+		fmt.Fprintln(&b, snippet)
+	}
+	fmt.Fprintln(&b, goCode.EnumMap)
+	for _, s := range []string{goCode.JSONSchemaCode, goCode.EnumTypeMap, goCode.StructRegistry} {
+		if s != "" {
+			fmt.Fprintln(&b, s)
+		}
+	}
+	return b.String()
+}
+
+// run fetches the OpenConfig models if required, and generates and writes the
+// bindings specified by the command-line flags.
+func run() error {
+	if flag.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments %v, modules are specified using -modules", flag.Args())
+	}
+	if *release != "" && *checkout != "" {
+		return fmt.Errorf("release and models_checkout cannot both be specified")
+	}
+
+	out, err := filepath.Abs(*outputDir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(out, 0755); err != nil {
+		return fmt.Errorf("cannot create output directory %s: %v", out, err)
+	}
+
+	root := *checkout
+	if root == "" {
+		tmp, err := os.MkdirTemp("", "openconfig_generator")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp)
+		root = filepath.Join(tmp, checkoutName)
+		if err := fetchModels(*repo, *release, root); err != nil {
+			return err
+		}
+	}
+	if root, err = filepath.Abs(root); err != nil {
+		return err
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(filepath.Dir(root)); err != nil {
+		return err
+	}
+	defer os.Chdir(wd)
+
+	cfg := recommendedConfig(*callerName, *packageName, splitFlag(*excludeModules))
+	goCode, pathCode, err := generate(root, splitFlag(*modules), cfg, generatedHeader(*callerName, *release, *checkout != ""))
+	if err != nil {
+		return err
+	}
+	for fn, code := range map[string]string{*goOutputFile: goCode, *pathOutputFile: pathCode} {
+		if err := os.WriteFile(filepath.Join(out, fn), []byte(code), 0644); err != nil {
+			return fmt.Errorf("cannot write generated code: %v", err)
+		}
+	}
+	return nil
+}
+
+// main parses command-line flags to determine the release of the OpenConfig
+// models and the modules for which bindings should be generated, and generates
+// them using the recommended options.
+func main() {
+	flag.Parse()
+	if err := run(); err != nil {
+		log.Exitf("Error: %v", err)
+	}
+}
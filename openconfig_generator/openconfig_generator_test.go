@@ -0,0 +1,248 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+const (
+	// testModule is a module within the release/models directory of the
+	// test checkout of the OpenConfig models.
+	testModule = `module openconfig-test {
+  prefix "oc-test";
+  namespace "urn:oct";
+
+  import openconfig-test-types { prefix "oc-tt"; }
+
+  container test {
+    container config {
+      leaf name { type string; }
+      leaf mode { type oc-tt:mode; default "FAST"; }
+    }
+    container state {
+      config false;
+      leaf name { type string; }
+      leaf mode { type oc-tt:mode; default "FAST"; }
+    }
+  }
+}
+`
+	// testTypesModule is a module within the third_party directory of the
+	// test checkout of the OpenConfig models, which is imported by
+	// testModule.
+	testTypesModule = `module openconfig-test-types {
+  prefix "oc-tt";
+  namespace "urn:octt";
+
+  typedef mode {
+    type enumeration {
+      enum FAST;
+      enum SLOW;
+    }
+  }
+}
+`
+)
+
+// writeTestCheckout writes a checkout of the OpenConfig models containing
+// testModule to the directory named public within dir, and returns its path.
+func writeTestCheckout(t *testing.T, dir string) string {
+	t.Helper()
+	root := filepath.Join(dir, checkoutName)
+	for fn, contents := range map[string]string{
+		filepath.Join(root, modelsDir, "test", "openconfig-test.yang"):           testModule,
+		filepath.Join(root, thirdPartyDir, "test", "openconfig-test-types.yang"): testTypesModule,
+	} {
+		if err := os.MkdirAll(filepath.Dir(fn), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fn, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// chdir changes the working directory to dir for the duration of the test.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	root := writeTestCheckout(t, dir)
+	chdir(t, dir)
+
+	tests := []struct {
+		desc             string
+		inModules        []string
+		wantGo           []string
+		wantPath         []string
+		wantErrSubstring string
+	}{{
+		desc:      "generate bindings",
+		inModules: []string{"test/openconfig-test.yang"},
+		wantGo: []string{
+			"// Code generated by openconfig_generator from release v1.2.3 of the OpenConfig models. DO NOT EDIT.\n",
+			"  - public/release/models/test/openconfig-test.yang\n",
+			"  - public/third_party/...\n",
+			"package oc\n",
+			"type Device struct {",
+			"func (t *Test) PopulateDefaults() {",
+			"func (t *Test) GetName() string {",
+			"ΛName",
+			"TestTypes_Mode_FAST",
+			"func Schema() (*ytypes.Schema, error) {",
+		},
+		wantPath: []string{
+			"// Code generated by openconfig_generator from release v1.2.3 of the OpenConfig models. DO NOT EDIT.\n",
+			"package oc\n",
+			"type DevicePath struct {",
+			"func (n *DevicePath) Test() *TestPath {",
+		},
+	}, {
+		desc:             "missing module",
+		inModules:        []string{"test/openconfig-missing.yang"},
+		wantErrSubstring: "module test/openconfig-missing.yang not found",
+	}, {
+		desc:             "no modules",
+		wantErrSubstring: "no input modules",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			goCode, pathCode, err := generate(root, tt.inModules, recommendedConfig("openconfig_generator", "oc", nil), generatedHeader("openconfig_generator", "v1.2.3", false))
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("generate: did not get expected error, %s", diff)
+			}
+			for _, want := range tt.wantGo {
+				if !strings.Contains(goCode, want) {
+					t.Errorf("generate: GoStruct code does not contain %q", want)
+				}
+			}
+			for _, want := range tt.wantPath {
+				if !strings.Contains(pathCode, want) {
+					t.Errorf("generate: path struct code does not contain %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratedHeader(t *testing.T) {
+	tests := []struct {
+		desc           string
+		inTag          string
+		inFromCheckout bool
+		want           string
+	}{{
+		desc:  "release",
+		inTag: "v4.0.0",
+		want:  "// Code generated by gen from release v4.0.0 of the OpenConfig models. DO NOT EDIT.\n\n",
+	}, {
+		desc: "latest",
+		want: "// Code generated by gen from the latest OpenConfig models. DO NOT EDIT.\n\n",
+	}, {
+		desc:           "checkout",
+		inFromCheckout: true,
+		want:           "// Code generated by gen from a checkout of the OpenConfig models. DO NOT EDIT.\n\n",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := generatedHeader("gen", tt.inTag, tt.inFromCheckout); got != tt.want {
+				t.Errorf("generatedHeader(%q, %v): got %q, want %q", tt.inTag, tt.inFromCheckout, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchModels(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	// Create a repository containing the test checkout, with a release
+	// tag, and a subsequent commit on the default branch.
+	repoDir := t.TempDir()
+	root := writeTestCheckout(t, repoDir)
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", root, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "--quiet")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "release")
+	git("tag", "v1.0.0")
+	if err := os.WriteFile(filepath.Join(root, "NEW"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "latest")
+
+	tests := []struct {
+		desc             string
+		inTag            string
+		wantNew          bool
+		wantErrSubstring string
+	}{{
+		desc:  "release",
+		inTag: "v1.0.0",
+	}, {
+		desc:    "latest",
+		wantNew: true,
+	}, {
+		desc:             "missing release",
+		inTag:            "v9.9.9",
+		wantErrSubstring: "cannot fetch release v9.9.9",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), checkoutName)
+			err := fetchModels("file://"+root, tt.inTag, dir)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("fetchModels: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if _, err := os.Stat(filepath.Join(dir, modelsDir, "test", "openconfig-test.yang")); err != nil {
+				t.Errorf("fetchModels: module not fetched: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "NEW")); (err == nil) != tt.wantNew {
+				t.Errorf("fetchModels: got latest commit %v, want %v", err == nil, tt.wantNew)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -36,6 +37,7 @@ var (
 	yangPaths              = flag.String("path", "", "Comma separated list of paths to be recursively searched for included modules or submodules within the defined YANG modules.")
 	compressPaths          = flag.Bool("compress_paths", false, "If set to true, the schema's paths are compressed, according to OpenConfig YANG module conventions.")
 	excludeModules         = flag.String("exclude_modules", "", "Comma separated set of module names that should be excluded from code generation. This can be used to ensure overlapping namespaces can be ignored.")
+	deviationModules       = flag.String("deviation_modules", "", "Comma separated set of YANG files containing deviations, such as those supplied by a vendor, that should be applied to the input modules. Code is not generated for the deviation modules themselves.")
 	packageName            = flag.String("package_name", "openconfig", "The name of the Proto package that generated messages should belong to as their parent.")
 	enumPackageName        = flag.String("enum_package_name", "enums", "The name of the package within the generated package that should contain global enum definitions.")
 	outputDir              = flag.String("output_dir", "", "The path to which files should be output, hierarchical folders are created for the generated messages.")
@@ -53,6 +55,7 @@ var (
 	preferOperationalState = flag.Bool("prefer_operational_state", false, "If set to true, state (config false) fields in the YANG schema are preferred over intended config leaves in the generated messages with compressed schema paths. This flag is only valid for compress_paths=true and exclude_state=false.")
 	skipEnumDedup          = flag.Bool("skip_enum_deduplication", false, "If set to true, all leaves of type enumeration will have a unique enum output for them, rather than sharing a common type (default behaviour).")
 	goPackageBase          = flag.String("go_package_base", "", "Base name for the Go packages that are to be generated - this value is included in the go_package option of the generated protobufs - and has generated packages' names appended to it.")
+	consistencyCasesFile   = flag.String("consistency_cases_file", "", "If set, the path of a file to which cases that encode a sample value of each leaf as both RFC7951 JSON and protobuf text are written, as a JSON array. The cases can be used to check that the generated protobufs encode values identically to the Go structs generated for the same schema. Requires generate_fakeroot to be set.")
 )
 
 // main parses command-line flags to determine the set of YANG modules for
@@ -95,6 +98,13 @@ func main() {
 		}
 	}
 
+	// Determine the deviation modules that should be applied to the input
+	// modules.
+	var deviationFiles []string
+	if len(*deviationModules) > 0 {
+		deviationFiles = strings.Split(*deviationModules, ",")
+	}
+
 	compressBehaviour, err := genutil.TranslateToCompressBehaviour(*compressPaths, *excludeState, *preferOperationalState)
 	if err != nil {
 		log.Exitf("ERROR Generating Proto Code: %s\n", err)
@@ -105,7 +115,8 @@ func main() {
 		*callerName,
 		ygen.IROptions{
 			ParseOptions: ygen.ParseOpts{
-				ExcludeModules: modsExcluded,
+				ExcludeModules:   modsExcluded,
+				DeviationModules: deviationFiles,
 				YANGParseOptions: yang.Options{
 					IgnoreSubmoduleCircularDependencies: *ignoreCircDeps,
 				},
@@ -135,6 +146,20 @@ func main() {
 		log.Exitf("%v\n", errs)
 	}
 
+	if *consistencyCasesFile != "" {
+		cases, errs := cg.GenerateConsistencyCases(generateModules, includePaths)
+		if errs != nil {
+			log.Exitf("%v\n", errs)
+		}
+		b, err := json.MarshalIndent(cases, "", "  ")
+		if err != nil {
+			log.Exitf("could not marshal consistency cases, got error: %v", err)
+		}
+		if err := os.WriteFile(*consistencyCasesFile, b, 0644); err != nil {
+			log.Exitf("could not write file %v, got error: %v", *consistencyCasesFile, err)
+		}
+	}
+
 	for _, p := range generatedProtoCode.Packages {
 		fp := filepath.Join(append([]string{*outputDir}, p.FilePath[:len(p.FilePath)-1]...)...)
 		if err := os.MkdirAll(fp, 0755); err != nil {
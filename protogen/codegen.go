@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/openconfig/ygot/util"
 	"github.com/openconfig/ygot/ygen"
 )
@@ -99,14 +100,28 @@ type Proto3Package struct {
 // It returns a GeneratedCode struct containing the messages that are to be
 // output, along with any associated values (e.g., enumerations).
 func (cg *CodeGenerator) Generate(yangFiles, includePaths []string) (*GeneratedCode, util.Errors) {
-	basePackageName := cg.ProtoOptions.PackageName
-	if basePackageName == "" {
-		basePackageName = DefaultBasePackageName
-	}
-	enumPackageName := cg.ProtoOptions.EnumPackageName
-	if enumPackageName == "" {
-		enumPackageName = DefaultEnumPackageName
-	}
+	return cg.generate(yangFiles, includePaths, func(langMapper ygen.LangMapper, opts ygen.IROptions) (*ygen.IR, error) {
+		return ygen.GenerateIR(yangFiles, includePaths, langMapper, opts)
+	})
+}
+
+// GenerateFromEntries generates Protobuf 3 code for the supplied modules,
+// which have already been parsed by the caller, returning the same
+// GeneratedCode as Generate. The modules must satisfy the requirements of
+// ygen.GenerateIRFromEntries, and are transformed in place such that they
+// must not be reused.
+func (cg *CodeGenerator) GenerateFromEntries(modules []*yang.Entry) (*GeneratedCode, util.Errors) {
+	return cg.generate(nil, nil, func(langMapper ygen.LangMapper, opts ygen.IROptions) (*ygen.IR, error) {
+		return ygen.GenerateIRFromEntries(modules, langMapper, opts)
+	})
+}
+
+// generate generates Protobuf 3 code from the IR that is returned by
+// generateIR for the LangMapper and IROptions used for proto generation.
+// yangFiles and includePaths are the inputs from which the IR is generated,
+// and are recorded in the header of the generated code.
+func (cg *CodeGenerator) generate(yangFiles, includePaths []string, generateIR func(ygen.LangMapper, ygen.IROptions) (*ygen.IR, error)) (*GeneratedCode, util.Errors) {
+	basePackageName, enumPackageName := cg.packageNames()
 	ywrapperPath := cg.ProtoOptions.YwrapperPath
 	if ywrapperPath == "" {
 		ywrapperPath = DefaultYwrapperPath
@@ -116,17 +131,7 @@ func (cg *CodeGenerator) Generate(yangFiles, includePaths []string) (*GeneratedC
 		yextPath = DefaultYextPath
 	}
 
-	// This flag is always true for proto generation.
-	cg.IROptions.TransformationOptions.UseDefiningModuleForTypedefEnumNames = true
-	opts := ygen.IROptions{
-		ParseOptions:                        cg.IROptions.ParseOptions,
-		TransformationOptions:               cg.IROptions.TransformationOptions,
-		NestedDirectories:                   cg.ProtoOptions.NestedMessages,
-		AbsoluteMapPaths:                    true,
-		AppendEnumSuffixForSimpleUnionEnums: true,
-	}
-
-	ir, err := ygen.GenerateIR(yangFiles, includePaths, NewProtoLangMapper(basePackageName, enumPackageName), opts)
+	ir, err := cg.protoIR(generateIR)
 	if err != nil {
 		return nil, util.NewErrs(err)
 	}
@@ -257,3 +262,36 @@ func (cg *CodeGenerator) Generate(yangFiles, includePaths []string) (*GeneratedC
 
 	return genProto, nil
 }
+
+// packageNames returns the names of the base package and of the package
+// containing global enumerations that are used for the generated protobufs,
+// substituting the defaults for those that are not specified.
+func (cg *CodeGenerator) packageNames() (string, string) {
+	basePackageName := cg.ProtoOptions.PackageName
+	if basePackageName == "" {
+		basePackageName = DefaultBasePackageName
+	}
+	enumPackageName := cg.ProtoOptions.EnumPackageName
+	if enumPackageName == "" {
+		enumPackageName = DefaultEnumPackageName
+	}
+	return basePackageName, enumPackageName
+}
+
+// protoIR returns the IR that is returned by generateIR for the LangMapper
+// and IROptions used for proto generation.
+func (cg *CodeGenerator) protoIR(generateIR func(ygen.LangMapper, ygen.IROptions) (*ygen.IR, error)) (*ygen.IR, error) {
+	basePackageName, enumPackageName := cg.packageNames()
+
+	// This flag is always true for proto generation.
+	cg.IROptions.TransformationOptions.UseDefiningModuleForTypedefEnumNames = true
+	opts := ygen.IROptions{
+		ParseOptions:                        cg.IROptions.ParseOptions,
+		TransformationOptions:               cg.IROptions.TransformationOptions,
+		NestedDirectories:                   cg.ProtoOptions.NestedMessages,
+		AbsoluteMapPaths:                    true,
+		AppendEnumSuffixForSimpleUnionEnums: true,
+	}
+
+	return generateIR(NewProtoLangMapper(basePackageName, enumPackageName), opts)
+}
@@ -0,0 +1,671 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protogen
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/genutil"
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygen"
+)
+
+// ConsistencyCase is a pair of encodings of the same data tree: one as
+// RFC7951 JSON, which can be unmarshalled into the root GoStruct generated for
+// a schema, and one in the protobuf text format, which can be unmarshalled
+// into the root message generated by this package for the same schema. Each
+// case populates a single leaf or leaf-list with a sample value, along with
+// the keys of the lists between it and the root, such that users of both
+// bindings of a schema can check that they encode each value identically, for
+// example, by comparing the gNMI notifications that are rendered from each.
+type ConsistencyCase struct {
+	// Name uniquely identifies the case. It is the schema path of the
+	// populated leaf, followed by the suffix of the name of the oneof field
+	// in which the value is set for values of union leaves.
+	Name string `json:"name"`
+	// Path is the schema path of the populated leaf, excluding the module
+	// name.
+	Path string `json:"path"`
+	// Type is the name of the YANG built-in type of the sample value.
+	Type string `json:"type"`
+	// JSON is the data tree encoded as RFC7951 JSON.
+	JSON string `json:"json"`
+	// Prototext is the data tree encoded in the protobuf text format.
+	Prototext string `json:"prototext"`
+}
+
+// GenerateConsistencyCases returns a ConsistencyCase for each value of each
+// leaf and leaf-list within the schema described by the input set of YANG
+// files, with included modules being searched for in includePaths. A case is
+// returned for the value of each member of a union. The IR is generated with
+// the options used by Generate, which must specify that a fake root is
+// generated, since the cases are rooted at it.
+//
+// The sample values satisfy the ranges of numeric types and the lengths of
+// string types, but not the patterns of string types.
+func (cg *CodeGenerator) GenerateConsistencyCases(yangFiles, includePaths []string) ([]*ConsistencyCase, util.Errors) {
+	ir, err := cg.protoIR(func(langMapper ygen.LangMapper, opts ygen.IROptions) (*ygen.IR, error) {
+		return ygen.GenerateIR(yangFiles, includePaths, langMapper, opts)
+	})
+	if err != nil {
+		return nil, util.NewErrs(err)
+	}
+	return consistencyCases(ir)
+}
+
+// consistencyCases returns the ConsistencyCases for the supplied IR, which
+// must have been generated for proto generation.
+func consistencyCases(ir *ygen.IR) ([]*ConsistencyCase, util.Errors) {
+	var root *ygen.ParsedDirectory
+	for _, d := range ir.Directories {
+		if d.IsFakeRoot {
+			root = d
+		}
+	}
+	if root == nil {
+		return nil, util.NewErrs(fmt.Errorf("consistency cases cannot be generated without a fake root"))
+	}
+
+	g := &consistencyGen{
+		ir:       ir,
+		listKeys: map[string]map[string]interface{}{},
+	}
+	g.walk(root, nil)
+	if g.errs != nil {
+		return nil, g.errs
+	}
+	return g.cases, nil
+}
+
+// consistencyGen stores the state of the generation of ConsistencyCases.
+type consistencyGen struct {
+	// ir is the IR for which cases are generated.
+	ir *ygen.IR
+	// listKeys stores the JSON values of the keys of the list entry that is
+	// populated for each list, keyed by the path of the list and then by
+	// the YANG name of the key.
+	listKeys map[string]map[string]interface{}
+	// cases are the cases that have been generated.
+	cases []*ConsistencyCase
+	// errs are the errors encountered during generation.
+	errs util.Errors
+}
+
+// textNode is a field set within a message in the protobuf text format.
+type textNode struct {
+	// name is the name of the field.
+	name string
+	// value is the value of a scalar field.
+	value string
+	// children are the fields set within a message field.
+	children []*textNode
+}
+
+// textLevel is a message field that is set along the path from the root
+// message to a populated leaf.
+type textLevel struct {
+	// name is the name of the field.
+	name string
+	// keys are the fields of the key message of a keyed list.
+	keys []*textNode
+	// listField is the name of the field of the key message of a keyed list
+	// that contains the list member.
+	listField string
+}
+
+// wrap returns the field described by the level, containing n.
+func (l *textLevel) wrap(n *textNode) *textNode {
+	if l.listField == "" {
+		return &textNode{name: l.name, children: []*textNode{n}}
+	}
+	children := append([]*textNode{}, l.keys...)
+	return &textNode{name: l.name, children: append(children, &textNode{name: l.listField, children: []*textNode{n}})}
+}
+
+// walk generates the cases for the leaves within the supplied directory and
+// its descendants. levels are the message fields between the root message
+// and the message generated for the directory.
+func (g *consistencyGen) walk(dir *ygen.ParsedDirectory, levels []*textLevel) {
+	fieldNames, enumNames := protoFieldNames(dir, g.ir)
+	for _, name := range dir.OrderedFieldNames() {
+		if _, ok := dir.ListKeys[name]; ok {
+			continue
+		}
+		field := dir.Fields[name]
+		switch field.Type {
+		case ygen.ContainerNode, ygen.ListNode:
+			child, ok := g.ir.Directories[field.YANGDetails.Path]
+			if !ok {
+				g.errs = util.AppendErr(g.errs, fmt.Errorf("could not resolve %s into a defined message", field.YANGDetails.Path))
+				continue
+			}
+			l := &textLevel{name: fieldNames[name]}
+			if field.Type == ygen.ListNode && len(child.ListKeys) != 0 {
+				keys, err := g.keys(child, field)
+				if err != nil {
+					g.errs = util.AppendErr(g.errs, err)
+					continue
+				}
+				l.keys, l.listField = keys, safeProtoIdentifierName(field.Name)
+			}
+			g.walk(child, append(levels[:len(levels):len(levels)], l))
+		case ygen.LeafNode, ygen.LeafListNode:
+			if err := g.addLeafCases(field, fieldNames[name], enumNames[name], levels); err != nil {
+				g.errs = util.AppendErr(g.errs, err)
+			}
+		}
+	}
+}
+
+// protoFieldNames returns the names of the fields of the message generated
+// for dir, keyed by the YANG name of the field that they correspond to. In
+// addition, it returns the names of the enumerations that are embedded in
+// the message for leaves whose type is a simple enumeration, keyed likewise.
+// The names are made unique in the same order as by genProto3Msg.
+func protoFieldNames(dir *ygen.ParsedDirectory, ir *ygen.IR) (map[string]string, map[string]string) {
+	fieldNames, enumNames := map[string]string{}, map[string]string{}
+	definedFieldNames := map[string]bool{}
+	for _, name := range dir.OrderedFieldNames() {
+		if _, ok := dir.ListKeys[name]; ok {
+			continue
+		}
+		field := dir.Fields[name]
+		fieldNames[name] = genutil.MakeNameUnique(field.Name, definedFieldNames)
+		if field.Type != ygen.LeafNode && field.Type != ygen.LeafListNode {
+			continue
+		}
+		if isSimpleEnum(field.LangType, ir) {
+			enumNames[name] = genutil.MakeNameUnique(field.LangType.NativeType, definedFieldNames)
+		}
+	}
+	return fieldNames, enumNames
+}
+
+// isSimpleEnum returns true if the supplied type is an enumeration that is
+// embedded within the message in which it is used.
+func isSimpleEnum(mt *ygen.MappedType, ir *ygen.IR) bool {
+	if !mt.IsEnumeratedValue {
+		return false
+	}
+	enum, ok := ir.Enums[mt.EnumeratedYANGTypeKey]
+	return ok && enum.Kind == ygen.SimpleEnumerationType
+}
+
+// keys returns the fields of the key message of the supplied list directory,
+// which is contained in the supplied field. The value of each key is the
+// first of the sample values of its type, and is also recorded for use in
+// the JSON encoding of the list.
+func (g *consistencyGen) keys(dir *ygen.ParsedDirectory, listField *ygen.NodeDetails) ([]*textNode, error) {
+	var nodes []*textNode
+	jsonKeys := map[string]interface{}{}
+	definedFieldNames := map[string]bool{}
+	for _, k := range dir.OrderedListKeyNames() {
+		kf, ok := dir.Fields[k]
+		if !ok {
+			return nil, fmt.Errorf("list %s included a key %s that did not exist", dir.Path, k)
+		}
+		mt := dir.ListKeys[k].LangType
+		name := genutil.MakeNameUnique(kf.Name, definedFieldNames)
+		if listField.Name == kf.Name {
+			name = fmt.Sprintf("%s_%s", name, protoMatchingListNameKeySuffix)
+		}
+		var enumName string
+		if isSimpleEnum(mt, g.ir) {
+			enumName = genutil.MakeNameUnique(mt.NativeType, definedFieldNames)
+		}
+
+		vals, err := g.values(kf, mt, name, enumName, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(vals) == 0 {
+			return nil, fmt.Errorf("no sample value for key %s of list %s", k, dir.Path)
+		}
+		jsonKeys[k] = vals[0].json
+		nodes = append(nodes, vals[0].node)
+	}
+	g.listKeys[dir.Path] = jsonKeys
+	return nodes, nil
+}
+
+// addLeafCases adds a case for each sample value of the supplied leaf or
+// leaf-list field, whose field in the protobuf message is named name. If the
+// type of the field is a simple enumeration, enumName is the name of the
+// enumeration embedded in the message for it.
+func (g *consistencyGen) addLeafCases(field *ygen.NodeDetails, name, enumName string, levels []*textLevel) error {
+	e := g.ir.Entry(field.YANGDetails.Path)
+	if e == nil {
+		return fmt.Errorf("could not find schema entry for %s", field.YANGDetails.Path)
+	}
+	vals, err := g.values(field, field.LangType, name, enumName, true)
+	if err != nil {
+		return err
+	}
+	for _, v := range vals {
+		jv := v.json
+		if field.Type == ygen.LeafListNode {
+			jv = []interface{}{jv}
+		}
+		js, err := g.jsonDoc(e, jv)
+		if err != nil {
+			return err
+		}
+
+		n := v.node
+		for i := len(levels) - 1; i >= 0; i-- {
+			n = levels[i].wrap(n)
+		}
+		var b strings.Builder
+		writeText(&b, []*textNode{n}, "")
+
+		c := &ConsistencyCase{
+			Name:      field.YANGDetails.SchemaPath,
+			Path:      field.YANGDetails.SchemaPath,
+			Type:      v.kind.String(),
+			JSON:      js,
+			Prototext: b.String(),
+		}
+		if v.member != "" {
+			c.Name = fmt.Sprintf("%s %s", c.Name, v.member)
+		}
+		g.cases = append(g.cases, c)
+	}
+	return nil
+}
+
+// writeText writes the supplied fields to b in the protobuf text format,
+// indenting each line with indent.
+func writeText(b *strings.Builder, nodes []*textNode, indent string) {
+	for _, n := range nodes {
+		if n.children == nil {
+			fmt.Fprintf(b, "%s%s: %s\n", indent, n.name, n.value)
+			continue
+		}
+		fmt.Fprintf(b, "%s%s {\n", indent, n.name)
+		writeText(b, n.children, indent+"  ")
+		fmt.Fprintf(b, "%s}\n", indent)
+	}
+}
+
+// jsonDoc returns the RFC7951 JSON document in which the node described by
+// the supplied schema entry has the value v, along with the keys of the list
+// entries between it and the root.
+func (g *consistencyGen) jsonDoc(e *yang.Entry, v interface{}) (string, error) {
+	for {
+		mod, err := e.InstantiatingModule()
+		if err != nil {
+			return "", err
+		}
+		p := e.Parent
+		for p != nil && (p.IsChoice() || p.IsCase()) {
+			p = p.Parent
+		}
+		if p == nil || p.Parent == nil {
+			// Members of the top-level object are always namespace
+			// qualified.
+			b, err := json.MarshalIndent(map[string]interface{}{fmt.Sprintf("%s:%s", mod, e.Name): v}, "", "  ")
+			if err != nil {
+				return "", err
+			}
+			return string(b), nil
+		}
+
+		pmod, err := p.InstantiatingModule()
+		if err != nil {
+			return "", err
+		}
+		name := e.Name
+		if pmod != mod {
+			name = fmt.Sprintf("%s:%s", mod, e.Name)
+		}
+		obj := map[string]interface{}{name: v}
+		if !p.IsList() {
+			v = obj
+			e = p
+			continue
+		}
+		keys, ok := g.listKeys[p.Path()]
+		if !ok && p.Key != "" {
+			return "", fmt.Errorf("no key values for list %s", p.Path())
+		}
+		for k, kv := range keys {
+			if k != e.Name {
+				obj[k] = kv
+			}
+		}
+		v = []interface{}{obj}
+		e = p
+	}
+}
+
+// leafValue is a sample value of a leaf.
+type leafValue struct {
+	// member is the suffix of the name of the oneof field in which the
+	// value is set, for values of union leaves.
+	member string
+	// kind is the YANG built-in type of the value.
+	kind yang.TypeKind
+	// json is the value in RFC7951 JSON.
+	json interface{}
+	// node is the field of the protobuf message which is set to the value.
+	node *textNode
+}
+
+// values returns the sample values of the supplied leaf field, whose type in
+// the protobuf message is mt and whose field is named name. enumName is the
+// name of the enumeration embedded in the message for the field, if its type
+// is a simple enumeration. wrapped specifies whether scalar values are
+// contained within ywrapper messages, which is the case for fields other than
+// list keys. A value is returned for each member of a union.
+func (g *consistencyGen) values(field *ygen.NodeDetails, mt *ygen.MappedType, name, enumName string, wrapped bool) ([]*leafValue, error) {
+	e := g.ir.Entry(field.YANGDetails.Path)
+	if e == nil {
+		return nil, fmt.Errorf("could not find schema entry for %s", field.YANGDetails.Path)
+	}
+	target, err := util.ResolveIfLeafRef(e)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve leafref %s: %v", field.YANGDetails.Path, err)
+	}
+	isLeafList := field.Type == ygen.LeafListNode
+
+	if len(mt.UnionTypes) == 0 {
+		t := target.Type
+		if t.Kind == yang.Yunion {
+			// A union which contains a single protobuf type is mapped to
+			// that type, and is populated using its first member.
+			sts := unionSubtypes(t, target)
+			if len(sts) == 0 {
+				return nil, fmt.Errorf("no supported union members for %s", field.YANGDetails.Path)
+			}
+			t = sts[0]
+		}
+		var enum *ygen.EnumeratedYANGType
+		if mt.IsEnumeratedValue {
+			enum = g.ir.Enums[mt.EnumeratedYANGTypeKey]
+			if enumName == "" && enum != nil {
+				enumName = enum.Name
+			}
+		}
+		s, err := sampleValue(t, enum, enumName)
+		if err != nil {
+			return nil, fmt.Errorf("cannot generate sample value for %s: %v", field.YANGDetails.Path, err)
+		}
+		return []*leafValue{{
+			kind: s.kind,
+			json: s.json,
+			node: s.field(name, wrapped && !mt.IsEnumeratedValue),
+		}}, nil
+	}
+
+	members := g.unionMembers(target, mt)
+	var typeNames []string
+	for tn := range mt.UnionTypes {
+		typeNames = append(typeNames, tn)
+	}
+	sort.Strings(typeNames)
+
+	var vals []*leafValue
+	for _, tn := range typeNames {
+		t, ok := members[tn]
+		if !ok {
+			continue
+		}
+		var (
+			enum     *ygen.EnumeratedYANGType
+			enumName string
+		)
+		if key := mt.UnionTypes[tn].EnumeratedYANGTypeKey; key != "" {
+			enum = g.ir.Enums[key]
+			enumName = enum.Name
+			if enum.Kind == ygen.SimpleEnumerationType || enum.Kind == ygen.UnionEnumerationType {
+				// Enumerations within unions that are embedded within
+				// the message are named according to the type name.
+				enumName = tn
+			}
+		}
+		s, err := sampleValue(t, enum, enumName)
+		if err != nil {
+			return nil, fmt.Errorf("cannot generate sample value for %s, type %s: %v", field.YANGDetails.Path, tn, err)
+		}
+		tp := strings.Split(tn, ".")
+		member := strings.ToLower(tp[len(tp)-1])
+		n := s.field(fmt.Sprintf("%s_%s", name, member), false)
+		if isLeafList {
+			// A leaf-list of unions is mapped to a repeated message
+			// containing the union's fields.
+			n = &textNode{name: name, children: []*textNode{n}}
+		}
+		vals = append(vals, &leafValue{
+			member: member,
+			kind:   s.kind,
+			json:   s.json,
+			node:   n,
+		})
+	}
+	return vals, nil
+}
+
+// unionSubtypes returns the non-union subtypes of the supplied union type, in
+// the order that they are defined, resolving subtypes that are leafrefs to
+// the type of their target relative to the supplied context entry.
+func unionSubtypes(t *yang.YangType, ctx *yang.Entry) []*yang.YangType {
+	var sts []*yang.YangType
+	for _, st := range t.Type {
+		switch st.Kind {
+		case yang.Yunion:
+			sts = append(sts, unionSubtypes(st, ctx)...)
+		case yang.Yleafref:
+			target, err := util.FindLeafRefSchema(ctx, st.Path)
+			if err != nil || target.Type == nil || target.Type.Kind == yang.Yunion {
+				continue
+			}
+			sts = append(sts, target.Type)
+		default:
+			sts = append(sts, st)
+		}
+	}
+	return sts
+}
+
+// unionMembers returns the YANG type of the values that are set in each of
+// the fields of the oneof to which a union leaf is mapped, keyed by the name
+// of the protobuf type of the field. As in protoUnionSubTypes, the first
+// subtype of the union that maps to each protobuf type is used.
+func (g *consistencyGen) unionMembers(e *yang.Entry, mt *ygen.MappedType) map[string]*yang.YangType {
+	var enumTypeNames []string
+	for tn, st := range mt.UnionTypes {
+		if st.EnumeratedYANGTypeKey != "" {
+			enumTypeNames = append(enumTypeNames, tn)
+		}
+	}
+	sort.Strings(enumTypeNames)
+
+	members := map[string]*yang.YangType{}
+	for _, st := range unionSubtypes(e.Type, e) {
+		var tn string
+		switch st.Kind {
+		case yang.Yenum, yang.Yidentityref:
+			for _, n := range enumTypeNames {
+				if enumMatches(g.ir.Enums[mt.UnionTypes[n].EnumeratedYANGTypeKey], st) {
+					tn = n
+					break
+				}
+			}
+		default:
+			tn = protoScalarTypeName(st.Kind)
+		}
+		if _, ok := members[tn]; tn != "" && !ok {
+			members[tn] = st
+		}
+	}
+	return members
+}
+
+// protoScalarTypeName returns the name of the protobuf type to which a YANG
+// type of the supplied kind is mapped within a list key or a union, as by
+// yangTypeToProtoScalarType, or the empty string if it is not a scalar type.
+func protoScalarTypeName(k yang.TypeKind) string {
+	switch k {
+	case yang.Yint8, yang.Yint16, yang.Yint32, yang.Yint64:
+		return "sint64"
+	case yang.Yuint8, yang.Yuint16, yang.Yuint32, yang.Yuint64:
+		return "uint64"
+	case yang.Ybinary:
+		return "bytes"
+	case yang.Ybool, yang.Yempty:
+		return "bool"
+	case yang.Ystring:
+		return "string"
+	case yang.Ydecimal64:
+		return ywrapperAccessor + "Decimal64Value"
+	}
+	return ""
+}
+
+// enumMatches returns true if the supplied enumerated type was generated for
+// the YANG type t.
+func enumMatches(enum *ygen.EnumeratedYANGType, t *yang.YangType) bool {
+	switch {
+	case enum == nil:
+		return false
+	case t.Kind == yang.Yidentityref:
+		return enum.Kind == ygen.IdentityType && t.IdentityBase != nil && t.IdentityBase.Name == enum.IdentityBaseName
+	case enum.Kind == ygen.IdentityType || t.Enum == nil:
+		return false
+	}
+	names := t.Enum.Names()
+	if len(names) != len(enum.ValToYANGDetails) {
+		return false
+	}
+	sort.Strings(names)
+	var enumNames []string
+	for _, v := range enum.ValToYANGDetails {
+		enumNames = append(enumNames, v.Name)
+	}
+	sort.Strings(enumNames)
+	for i := range names {
+		if names[i] != enumNames[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sample is a sample value of a YANG type.
+type sample struct {
+	// kind is the YANG built-in type of the value.
+	kind yang.TypeKind
+	// json is the value in RFC7951 JSON.
+	json interface{}
+	// text is the value of a scalar field in the protobuf text format. For
+	// decimal64 values, which are always messages, it is unset and digits
+	// and precision are set instead.
+	text string
+	// digits and precision are the values of the fields of the
+	// Decimal64Value message for decimal64 values.
+	digits, precision string
+}
+
+// field returns a field named name which is set to the sample value. If
+// wrapped is true, the value is contained within a ywrapper message.
+func (s *sample) field(name string, wrapped bool) *textNode {
+	switch {
+	case s.kind == yang.Ydecimal64:
+		return &textNode{name: name, children: []*textNode{
+			{name: "digits", value: s.digits},
+			{name: "precision", value: s.precision},
+		}}
+	case wrapped:
+		return &textNode{name: name, children: []*textNode{{name: "value", value: s.text}}}
+	}
+	return &textNode{name: name, value: s.text}
+}
+
+// sampleValue returns a sample value of the YANG type t. For enumeration and
+// identityref types, enum must be the enumerated type generated for t, and
+// enumName the name of the protobuf enum to which it is mapped.
+func sampleValue(t *yang.YangType, enum *ygen.EnumeratedYANGType, enumName string) (*sample, error) {
+	s := &sample{kind: t.Kind}
+	switch t.Kind {
+	case yang.Yint8, yang.Yint16, yang.Yint32, yang.Yint64:
+		i, err := inRange(t.Range, yang.FromInt(-42)).Int()
+		if err != nil {
+			return nil, err
+		}
+		s.text = strconv.FormatInt(i, 10)
+		s.json = i
+		if t.Kind == yang.Yint64 {
+			s.json = s.text
+		}
+	case yang.Yuint8, yang.Yuint16, yang.Yuint32, yang.Yuint64:
+		u := inRange(t.Range, yang.FromUint(42)).Value
+		s.text = strconv.FormatUint(u, 10)
+		s.json = u
+		if t.Kind == yang.Yuint64 {
+			s.json = s.text
+		}
+	case yang.Ydecimal64:
+		n := inRange(t.Range, yang.Number{Value: 15, FractionDigits: uint8(t.FractionDigits)})
+		s.json = n.String()
+		digits := strconv.FormatUint(n.Value, 10)
+		if n.Negative {
+			digits = "-" + digits
+		}
+		s.digits, s.precision = digits, strconv.Itoa(int(n.FractionDigits))
+	case yang.Ystring:
+		v := "sample"
+		if len(t.Length) != 0 {
+			if l := yang.FromInt(int64(len(v))); !t.Length.Contains(yang.YangRange{{Min: l, Max: l}}) {
+				v = strings.Repeat("a", int(t.Length[0].Min.Value))
+			}
+		}
+		s.json, s.text = v, strconv.Quote(v)
+	case yang.Ybinary:
+		v := []byte{0x01, 0x02}
+		s.json, s.text = base64.StdEncoding.EncodeToString(v), `"\001\002"`
+	case yang.Ybool:
+		s.json, s.text = true, "true"
+	case yang.Yempty:
+		s.json, s.text = []interface{}{nil}, "true"
+	case yang.Yenum, yang.Yidentityref:
+		if enum == nil || len(enum.ValToYANGDetails) == 0 {
+			return nil, fmt.Errorf("no values for enumerated type %s", t.Name)
+		}
+		v := enum.ValToYANGDetails[0]
+		s.json = v.Name
+		if t.Kind == yang.Yidentityref {
+			s.json = fmt.Sprintf("%s:%s", v.DefiningModule, v.Name)
+		}
+		s.text = fmt.Sprintf("%s_%s", strings.ToUpper(enumName), safeProtoIdentifierName(v.Name))
+	default:
+		return nil, fmt.Errorf("unsupported type %v", t.Kind)
+	}
+	return s, nil
+}
+
+// inRange returns n if it is within the supplied range, or the minimum of
+// the range otherwise.
+func inRange(r yang.YangRange, n yang.Number) yang.Number {
+	if len(r) == 0 || r.Contains(yang.YangRange{{Min: n, Max: n}}) {
+		return n
+	}
+	return r[0].Min
+}
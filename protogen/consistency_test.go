@@ -0,0 +1,108 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protogen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openconfig/ygot/genutil"
+	"github.com/openconfig/ygot/testutil"
+	"github.com/openconfig/ygot/ygen"
+)
+
+// formatConsistencyCases returns a textual representation of the supplied
+// cases for comparison with a golden file.
+func formatConsistencyCases(cases []*ConsistencyCase) string {
+	var b strings.Builder
+	for _, c := range cases {
+		fmt.Fprintf(&b, "== %s (%s), path %s\n%s\n%s", c.Name, c.Type, c.Path, c.JSON, c.Prototext)
+	}
+	return b.String()
+}
+
+func TestGenerateConsistencyCases(t *testing.T) {
+	tests := []struct {
+		name      string
+		inFiles   []string
+		inConfig  CodeGenerator
+		wantFile  string
+		wantErrIn string
+	}{{
+		name:    "compressed schema",
+		inFiles: []string{filepath.Join(TestRoot, "testdata", "proto", "consistency.yang")},
+		inConfig: CodeGenerator{
+			IROptions: ygen.IROptions{
+				TransformationOptions: ygen.TransformationOpts{
+					CompressBehaviour: genutil.PreferIntendedConfig,
+					GenerateFakeRoot:  true,
+				},
+			},
+		},
+		wantFile: filepath.Join(TestRoot, "testdata", "proto", "consistency.compress.cases-txt"),
+	}, {
+		name:    "uncompressed schema",
+		inFiles: []string{filepath.Join(TestRoot, "testdata", "proto", "consistency.yang")},
+		inConfig: CodeGenerator{
+			IROptions: ygen.IROptions{
+				TransformationOptions: ygen.TransformationOpts{
+					GenerateFakeRoot: true,
+				},
+			},
+			ProtoOptions: ProtoOpts{
+				NestedMessages: true,
+			},
+		},
+		wantFile: filepath.Join(TestRoot, "testdata", "proto", "consistency.nocompress.cases-txt"),
+	}, {
+		name:      "no fake root",
+		inFiles:   []string{filepath.Join(TestRoot, "testdata", "proto", "consistency.yang")},
+		wantErrIn: "without a fake root",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cg := New("", tt.inConfig.IROptions, tt.inConfig.ProtoOptions)
+			got, errs := cg.GenerateConsistencyCases(tt.inFiles, nil)
+			if errs != nil {
+				if tt.wantErrIn == "" || !strings.Contains(errs.Error(), tt.wantErrIn) {
+					t.Fatalf("GenerateConsistencyCases(%v): got unexpected errors: %v", tt.inFiles, errs)
+				}
+				return
+			}
+			if tt.wantErrIn != "" {
+				t.Fatalf("GenerateConsistencyCases(%v): did not get expected error, want error containing %q", tt.inFiles, tt.wantErrIn)
+			}
+
+			for _, c := range got {
+				if !json.Valid([]byte(c.JSON)) {
+					t.Errorf("GenerateConsistencyCases(%v): case %s has invalid JSON: %s", tt.inFiles, c.Name, c.JSON)
+				}
+			}
+
+			want, err := os.ReadFile(tt.wantFile)
+			if err != nil {
+				t.Fatalf("os.ReadFile(%s): could not read golden file: %v", tt.wantFile, err)
+			}
+			if diff, _ := testutil.GenerateUnifiedDiff(string(want), formatConsistencyCases(got)); diff != "" {
+				t.Errorf("GenerateConsistencyCases(%v): did not get expected cases, diff(-want, +got):\n%s", tt.inFiles, diff)
+			}
+		})
+	}
+}
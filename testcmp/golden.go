@@ -0,0 +1,39 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testcmp
+
+import (
+	"fmt"
+
+	"github.com/openconfig/ygot/testutil"
+	"github.com/openconfig/ygot/ygot"
+)
+
+// GoStructGoldenDiff emits the JSON of the supplied GoStruct using
+// ygot.EmitJSON with the supplied configuration, and compares it with the
+// golden file at the supplied path using testutil.JSONGoldenDiff, such that
+// cosmetic differences in the encoding of the JSON are ignored. If cfg is
+// nil, RFC7951 JSON is emitted. It returns a unified diff between the golden
+// file and the emitted JSON, or an empty string if they are equal.
+func GoStructGoldenDiff(gs ygot.GoStruct, goldenFile string, cfg *ygot.EmitJSONConfig, opts ...testutil.CanonicalJSONOpt) (string, error) {
+	if cfg == nil {
+		cfg = &ygot.EmitJSONConfig{Format: ygot.RFC7951}
+	}
+	js, err := ygot.EmitJSON(gs, cfg)
+	if err != nil {
+		return "", fmt.Errorf("cannot emit JSON for %T: %v", gs, err)
+	}
+	return testutil.JSONGoldenDiff([]byte(js), goldenFile, opts...)
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testcmp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygot/exampleoc"
+	"github.com/openconfig/ygot/testutil"
+	"github.com/openconfig/ygot/ygot"
+)
+
+func TestGoStructGoldenDiff(t *testing.T) {
+	dir := t.TempDir()
+	writeGolden := func(name, content string) string {
+		fn := filepath.Join(dir, name)
+		if err := os.WriteFile(fn, []byte(content), 0644); err != nil {
+			t.Fatalf("cannot write golden file %s: %v", name, err)
+		}
+		return fn
+	}
+
+	d := &exampleoc.Device{}
+	d.GetOrCreateSystem().Hostname = ygot.String("dev1")
+
+	tests := []struct {
+		desc             string
+		inGoldenFile     string
+		inConfig         *ygot.EmitJSONConfig
+		inOpts           []testutil.CanonicalJSONOpt
+		wantDiffSubstr   string
+		wantErrSubstring string
+	}{{
+		desc:         "equal RFC7951 JSON",
+		inGoldenFile: writeGolden("rfc7951.json", `{"system": {"config": {"hostname": "dev1"}}}`),
+	}, {
+		desc:           "different value",
+		inGoldenFile:   writeGolden("different.json", `{"system": {"config": {"hostname": "dev2"}}}`),
+		wantDiffSubstr: `+      "hostname": "dev1"`,
+	}, {
+		desc:         "RFC7951 JSON with module names",
+		inGoldenFile: writeGolden("modules.json", `{"openconfig-system:system": {"config": {"hostname": "dev1"}}}`),
+		inConfig: &ygot.EmitJSONConfig{
+			Format: ygot.RFC7951,
+			RFC7951Config: &ygot.RFC7951JSONConfig{
+				AppendModuleName: true,
+			},
+		},
+	}, {
+		desc:         "module prefixes stripped",
+		inGoldenFile: writeGolden("stripped.json", `{"openconfig-system:system": {"config": {"hostname": "dev1"}}}`),
+		inOpts:       []testutil.CanonicalJSONOpt{testutil.StripModulePrefixes{}},
+	}, {
+		desc:         "internal JSON",
+		inGoldenFile: writeGolden("internal.json", `{"system": {"config": {"hostname": "dev1"}}}`),
+		inConfig:     &ygot.EmitJSONConfig{Format: ygot.Internal},
+	}, {
+		desc:             "missing golden file",
+		inGoldenFile:     filepath.Join(dir, "does-not-exist.json"),
+		wantErrSubstring: "cannot read golden file",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			diff, err := GoStructGoldenDiff(d, tt.inGoldenFile, tt.inConfig, tt.inOpts...)
+			if d := errdiff.Substring(err, tt.wantErrSubstring); d != "" {
+				t.Fatalf("GoStructGoldenDiff: %s", d)
+			}
+			switch {
+			case tt.wantDiffSubstr == "" && diff != "":
+				t.Errorf("GoStructGoldenDiff: got unexpected diff:\n%s", diff)
+			case !strings.Contains(diff, tt.wantDiffSubstr):
+				t.Errorf("GoStructGoldenDiff: diff does not contain %q, got:\n%s", tt.wantDiffSubstr, diff)
+			}
+		})
+	}
+}
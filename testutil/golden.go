@@ -0,0 +1,174 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// CanonicalJSONOpt is an interface implemented by the options that can be
+// supplied to CanonicalJSON and JSONGoldenDiff.
+type CanonicalJSONOpt interface {
+	IsCanonicalJSONOpt()
+}
+
+// StripModulePrefixes is a CanonicalJSONOpt specifying that the module name
+// prefixes of the member names of RFC7951 JSON objects are removed, such
+// that JSON in which a member name is qualified with its module is equal to
+// JSON in which it is not. This is useful where the placement of module
+// prefixes differs between encoders, e.g., when the module of a node changes
+// due to an augment.
+type StripModulePrefixes struct{}
+
+// IsCanonicalJSONOpt marks StripModulePrefixes as a CanonicalJSONOpt.
+func (StripModulePrefixes) IsCanonicalJSONOpt() {}
+
+// hasStripModulePrefixes determines whether the opt slice contains at least
+// one instance of the StripModulePrefixes option.
+func hasStripModulePrefixes(opts []CanonicalJSONOpt) bool {
+	for _, o := range opts {
+		if _, ok := o.(StripModulePrefixes); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CanonicalJSON returns a canonical form of the supplied JSON document, such
+// that documents differing only in cosmetic aspects of their encoding have the
+// same canonical form. In the canonical form:
+//   - the members of each object are sorted by name, and each member and
+//     array element is output on its own line, indented by two spaces.
+//   - numbers are formatted as integers if they are written without a
+//     fraction or exponent, and otherwise using the shortest representation
+//     of their float64 value, such that 1.50, 1.5 and 15e-1 are equal.
+//   - characters are not escaped for embedding within HTML.
+//
+// Module name prefixes of member names are removed if the StripModulePrefixes
+// option is supplied.
+func CanonicalJSON(b []byte, opts ...CanonicalJSONOpt) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	if d.More() {
+		return nil, fmt.Errorf("invalid JSON: unexpected data after the top-level value")
+	}
+
+	cv, err := canonicalJSONValue(v, hasStripModulePrefixes(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+	if err := e.Encode(cv); err != nil {
+		return nil, fmt.Errorf("cannot encode canonical JSON: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// canonicalJSONValue returns the canonical form of the decoded JSON value v.
+// Module name prefixes are removed from member names if stripPrefixes is set.
+func canonicalJSONValue(v interface{}, stripPrefixes bool) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, cv := range v {
+			name := k
+			if stripPrefixes {
+				if i := strings.Index(k, ":"); i != -1 {
+					name = k[i+1:]
+				}
+			}
+			if _, ok := out[name]; ok {
+				return nil, fmt.Errorf("duplicate member %q in JSON object, from member %q", name, k)
+			}
+			ccv, err := canonicalJSONValue(cv, stripPrefixes)
+			if err != nil {
+				return nil, err
+			}
+			out[name] = ccv
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, e := range v {
+			ce, err := canonicalJSONValue(e, stripPrefixes)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, ce)
+		}
+		return out, nil
+	case json.Number:
+		return canonicalJSONNumber(v)
+	default:
+		return v, nil
+	}
+}
+
+// canonicalJSONNumber returns the canonical form of the JSON number n.
+func canonicalJSONNumber(n json.Number) (json.Number, error) {
+	s := n.String()
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return json.Number(strconv.FormatInt(i, 10)), nil
+		}
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return json.Number(strconv.FormatUint(u, 10)), nil
+		}
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return "", fmt.Errorf("invalid number %s in JSON: %v", s, err)
+	}
+	return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), nil
+}
+
+// JSONGoldenDiff compares the supplied JSON document with the JSON document
+// stored in the golden file at the supplied path, after converting both to
+// their canonical form using CanonicalJSON with the supplied options. It
+// returns a unified diff between the canonical forms of the golden file and
+// the document, or an empty string if they are equal. This allows tests to
+// compare JSON emitted by ygot with a golden file without failing due to
+// cosmetic changes in the encoding of the JSON.
+func JSONGoldenDiff(got []byte, goldenFile string, opts ...CanonicalJSONOpt) (string, error) {
+	golden, err := os.ReadFile(goldenFile)
+	if err != nil {
+		return "", fmt.Errorf("cannot read golden file: %v", err)
+	}
+	want, err := CanonicalJSON(golden, opts...)
+	if err != nil {
+		return "", fmt.Errorf("cannot canonicalise golden file %s: %v", goldenFile, err)
+	}
+	cgot, err := CanonicalJSON(got, opts...)
+	if err != nil {
+		return "", fmt.Errorf("cannot canonicalise JSON: %v", err)
+	}
+	if bytes.Equal(want, cgot) {
+		return "", nil
+	}
+	return GenerateUnifiedDiff(string(want), string(cgot))
+}
@@ -0,0 +1,165 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	tests := []struct {
+		name             string
+		in               string
+		inOpts           []CanonicalJSONOpt
+		want             string
+		wantErrSubstring string
+	}{{
+		name: "member order and indentation",
+		in:   `{"b": [1, {"d": true, "c": null}], "a": "x<y"}`,
+		want: `{
+  "a": "x<y",
+  "b": [
+    1,
+    {
+      "c": null,
+      "d": true
+    }
+  ]
+}
+`,
+	}, {
+		name: "numbers",
+		in:   `[1.50, 15e-1, 1.0, 10, -0, 18446744073709551615, 1e21]`,
+		want: `[
+  1.5,
+  1.5,
+  1,
+  10,
+  0,
+  18446744073709551615,
+  1e+21
+]
+`,
+	}, {
+		name: "module prefixes retained",
+		in:   `{"mod:a": {"b": 1}}`,
+		want: `{
+  "mod:a": {
+    "b": 1
+  }
+}
+`,
+	}, {
+		name:   "module prefixes stripped",
+		in:     `{"mod:a": {"other:b": 1, "c": "mod:VALUE"}}`,
+		inOpts: []CanonicalJSONOpt{StripModulePrefixes{}},
+		want: `{
+  "a": {
+    "b": 1,
+    "c": "mod:VALUE"
+  }
+}
+`,
+	}, {
+		name:             "duplicate member after stripping prefixes",
+		in:               `{"a:x": 1, "b:x": 2}`,
+		inOpts:           []CanonicalJSONOpt{StripModulePrefixes{}},
+		wantErrSubstring: "duplicate member",
+	}, {
+		name:             "invalid JSON",
+		in:               `{"a": `,
+		wantErrSubstring: "invalid JSON",
+	}, {
+		name:             "trailing data",
+		in:               `{} {}`,
+		wantErrSubstring: "unexpected data",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CanonicalJSON([]byte(tt.in), tt.inOpts...)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("CanonicalJSON: %s", diff)
+			}
+			if string(got) != tt.want && tt.wantErrSubstring == "" {
+				t.Errorf("CanonicalJSON: got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONGoldenDiff(t *testing.T) {
+	golden := filepath.Join(t.TempDir(), "golden.json")
+	if err := os.WriteFile(golden, []byte(`{"mod:a": {"b": 1.0, "c": ["x"]}}`), 0644); err != nil {
+		t.Fatalf("cannot write golden file: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		in               string
+		inGoldenFile     string
+		inOpts           []CanonicalJSONOpt
+		wantDiffSubstr   string
+		wantErrSubstring string
+	}{{
+		name: "cosmetic differences",
+		in:   `{"mod:a":{"c":["x"],"b":1}}`,
+	}, {
+		name:           "module prefix differences",
+		in:             `{"mod:a": {"b": 1, "mod:c": ["x"]}}`,
+		wantDiffSubstr: `+    "mod:c": [`,
+	}, {
+		name:   "module prefix differences ignored",
+		in:     `{"a": {"b": 1, "mod:c": ["x"]}}`,
+		inOpts: []CanonicalJSONOpt{StripModulePrefixes{}},
+	}, {
+		name:           "different value",
+		in:             `{"mod:a": {"b": 2, "c": ["x"]}}`,
+		wantDiffSubstr: "-    \"b\": 1,\n+    \"b\": 2,",
+	}, {
+		name:             "missing golden file",
+		in:               `{}`,
+		inGoldenFile:     filepath.Join(t.TempDir(), "does-not-exist.json"),
+		wantErrSubstring: "cannot read golden file",
+	}, {
+		name:             "invalid JSON",
+		in:               `{`,
+		wantErrSubstring: "cannot canonicalise JSON",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goldenFile := golden
+			if tt.inGoldenFile != "" {
+				goldenFile = tt.inGoldenFile
+			}
+			diff, err := JSONGoldenDiff([]byte(tt.in), goldenFile, tt.inOpts...)
+			if d := errdiff.Substring(err, tt.wantErrSubstring); d != "" {
+				t.Fatalf("JSONGoldenDiff: %s", d)
+			}
+			switch {
+			case tt.wantDiffSubstr == "" && diff != "":
+				t.Errorf("JSONGoldenDiff: got unexpected diff:\n%s", diff)
+			case !strings.Contains(diff, tt.wantDiffSubstr):
+				t.Errorf("JSONGoldenDiff: diff does not contain %q, got:\n%s", tt.wantDiffSubstr, diff)
+			}
+		})
+	}
+}
@@ -26,6 +26,12 @@ func (e Errors) Error() string {
 	return ToString([]error(e))
 }
 
+// Unwrap returns the errors within e, such that errors.Is and errors.As
+// match an error if any of the errors within e match it.
+func (e Errors) Unwrap() []error {
+	return []error(e)
+}
+
 // String implements the stringer#String method.
 func (e Errors) String() string {
 	return e.Error()
@@ -88,7 +94,7 @@ func ToString(errors []error) string {
 func PrefixErrors(errs Errors, pfx string) Errors {
 	var nerr Errors
 	for _, err := range errs {
-		nerr = append(nerr, fmt.Errorf("%s: %s", pfx, err))
+		nerr = append(nerr, fmt.Errorf("%s: %w", pfx, err))
 	}
 	return nerr
 }
@@ -44,6 +44,17 @@ func TestToString(t *testing.T) {
 	}
 }
 
+func TestUnwrap(t *testing.T) {
+	target := errors.New("target")
+	errs := PrefixErrors(Errors{errors.New("one"), fmt.Errorf("two: %w", target)}, "a")
+	if !errors.Is(errs, target) {
+		t.Errorf("errors.Is(%v, %v): got false, want true", errs, target)
+	}
+	if errors.Is(testErrs, target) {
+		t.Errorf("errors.Is(%v, %v): got true, want false", testErrs, target)
+	}
+}
+
 func TestNewErrs(t *testing.T) {
 	var errs Errors
 	errs = NewErrs(nil)
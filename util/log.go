@@ -0,0 +1,77 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"sync"
+
+	log "github.com/golang/glog"
+)
+
+// Logger is the interface through which the ygot libraries log messages. It
+// can be implemented to route the messages to a logging library other than
+// glog, which is used by default.
+type Logger interface {
+	// Infof logs an informational message.
+	Infof(format string, args ...any)
+	// Warningf logs a warning, e.g., that a deprecated behaviour is used.
+	Warningf(format string, args ...any)
+	// Errorf logs an error that does not cause the calling function to fail.
+	Errorf(format string, args ...any)
+}
+
+var (
+	// loggerMu protects logger.
+	loggerMu sync.RWMutex
+	// logger is the Logger returned by Log.
+	logger Logger = glogLogger{}
+)
+
+// SetLogger sets the Logger through which the ygot libraries log messages.
+// If l is nil, the default Logger, which uses glog, is restored.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = glogLogger{}
+	}
+	loggerMu.Lock()
+	defer loggerMu.Unlock()
+	logger = l
+}
+
+// Log returns the Logger through which the ygot libraries log messages.
+func Log() Logger {
+	loggerMu.RLock()
+	defer loggerMu.RUnlock()
+	return logger
+}
+
+// glogLogger is the default Logger, which logs using glog. Messages are
+// attributed to the caller of the Logger's methods.
+type glogLogger struct{}
+
+// Infof logs an informational message using glog.
+func (glogLogger) Infof(format string, args ...any) {
+	log.InfoDepthf(1, format, args...)
+}
+
+// Warningf logs a warning using glog.
+func (glogLogger) Warningf(format string, args ...any) {
+	log.WarningDepthf(1, format, args...)
+}
+
+// Errorf logs an error using glog.
+func (glogLogger) Errorf(format string, args ...any) {
+	log.ErrorDepthf(1, format, args...)
+}
@@ -22,8 +22,6 @@ import (
 	"github.com/kylelemons/godebug/pretty"
 	"github.com/openconfig/goyang/pkg/yang"
 
-	log "github.com/golang/glog"
-
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
 )
 
@@ -32,6 +30,12 @@ func IsTypeStruct(t reflect.Type) bool {
 	return t.Kind() == reflect.Struct
 }
 
+// IsTypeLeafValue reports whether t is a struct type that represents the value
+// of a YANG leaf, such as ygot.Decimal64, rather than a YANG container.
+func IsTypeLeafValue(t reflect.Type) bool {
+	return IsTypeStruct(t) && t.Implements(leafValueType)
+}
+
 // IsTypeStructPtr reports whether v is a struct ptr type.
 func IsTypeStructPtr(t reflect.Type) bool {
 	if t == reflect.TypeOf(nil) {
@@ -138,7 +142,8 @@ func IsValueSlice(v reflect.Value) bool {
 	return v.Kind() == reflect.Slice
 }
 
-// IsValueScalar reports whether v is a scalar type.
+// IsValueScalar reports whether v is a scalar type. Structs that are the
+// values of YANG leaves, such as ygot.Decimal64, are scalars.
 func IsValueScalar(v reflect.Value) bool {
 	if IsNilOrInvalidValue(v) {
 		return false
@@ -149,6 +154,9 @@ func IsValueScalar(v reflect.Value) bool {
 		}
 		v = v.Elem()
 	}
+	if IsTypeLeafValue(v.Type()) {
+		return true
+	}
 	return !IsValueStruct(v) && !IsValueMap(v) && !IsValueSlice(v)
 }
 
@@ -1012,7 +1020,7 @@ func getNodesList(schema *yang.Entry, root interface{}, path *gpb.Path) ([]inter
 func pathStructTagKey(f reflect.StructField) string {
 	p, err := RelativeSchemaPath(f)
 	if err != nil {
-		log.Errorf("struct field %s does not have a path tag, bad schema?", f.Name)
+		Log().Errorf("struct field %s does not have a path tag, bad schema?", f.Name)
 		return ""
 	}
 	return p[len(p)-1]
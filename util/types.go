@@ -16,6 +16,7 @@ package util
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/openconfig/goyang/pkg/yang"
 )
@@ -36,6 +37,18 @@ func YangIntTypeBits(t yang.TypeKind) (int, error) {
 	return 0, fmt.Errorf("type is not an int")
 }
 
+// leafValue is a convenience interface for struct types, such as
+// ygot.Decimal64, that represent the value of a YANG leaf. It is here to avoid
+// a circular dependency.
+type leafValue interface {
+	// IsYANGLeafValue is a marker method that indicates that the struct
+	// implements the leafValue interface.
+	IsYANGLeafValue()
+}
+
+// leafValueType is the reflect.Type of the leafValue interface.
+var leafValueType = reflect.TypeOf((*leafValue)(nil)).Elem()
+
 // goOrderedMap is a convenience interface for ygot.GoOrderedMap. It is here
 // to avoid a circular dependency.
 type goOrderedMap interface {
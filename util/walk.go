@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"reflect"
 
-	log "github.com/golang/glog"
 	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/openconfig/ygot/internal/yreflect"
 )
@@ -214,6 +213,9 @@ func walkFieldInternal(visitor Visitor, node WalkNode, o *WalkOptions) {
 			}
 		}
 
+	case IsTypeLeafValue(t):
+		// Structs that represent the values of leaves, such as
+		// ygot.Decimal64, have no children.
 	case IsTypeStructPtr(t):
 		t = t.Elem()
 		if !IsNilOrInvalidValue(v) {
@@ -250,7 +252,7 @@ func walkFieldInternal(visitor Visitor, node WalkNode, o *WalkOptions) {
 					e := fmt.Errorf("forEachFieldInternal could not find child schema with path %v from schema name %s", p, ni.Schema.Name)
 					DbgPrint(e.Error())
 					// TODO(wenovus) Consider making this into an error.
-					log.Errorln(e)
+					Log().Errorf("%v", e)
 					continue
 				}
 				nn.PathFromParent = p
@@ -317,6 +319,9 @@ func walkDataFieldInternal(visitor Visitor, node WalkNode, o *WalkOptions) {
 		}); err != nil {
 			o.WalkErrors.Collect(err)
 		}
+	case IsTypeLeafValue(t):
+		// Structs that represent the values of leaves, such as
+		// ygot.Decimal64, are not recursed into.
 	case IsTypeStructPtr(t):
 		// A struct pointer in a GoStruct is a pointer to another container within
 		// the YANG, therefore we dereference the pointer and then recurse. If the
@@ -361,7 +366,7 @@ func walkDataFieldInternal(visitor Visitor, node WalkNode, o *WalkOptions) {
 	case IsTypeSlice(t):
 		// Only iterate in the data tree if the slice is of structs, otherwise
 		// for leaf-lists we only run once.
-		if !IsTypeStructPtr(t.Elem()) && !IsTypeStruct(t.Elem()) {
+		if !IsTypeStructPtr(t.Elem()) && !IsTypeStruct(t.Elem()) || IsTypeLeafValue(t.Elem()) {
 			return
 		}
 
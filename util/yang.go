@@ -78,6 +78,29 @@ func TopLevelModule(schema *yang.Entry) *yang.Entry {
 	return root
 }
 
+// RPCInputOutput returns the input and output statements of the RPC or
+// action e, omitting those that are not present. It returns nil if e is not
+// an RPC or action.
+func RPCInputOutput(e *yang.Entry) []*yang.Entry {
+	if e.RPC == nil {
+		return nil
+	}
+	var entries []*yang.Entry
+	for _, io := range []*yang.Entry{e.RPC.Input, e.RPC.Output} {
+		if io != nil {
+			entries = append(entries, io)
+		}
+	}
+	return entries
+}
+
+// IsRPCInputOutput returns true if the entry is the input or output
+// statement of an RPC or action. Such entries contain data nodes in the same
+// way as a container.
+func IsRPCInputOutput(e *yang.Entry) bool {
+	return e != nil && (e.Kind == yang.InputEntry || e.Kind == yang.OutputEntry)
+}
+
 // HasOnlyChild returns true if the directory passed to it only has a single
 // element below it.
 func HasOnlyChild(e *yang.Entry) bool {
@@ -208,6 +231,13 @@ func IsYgotAnnotation(s reflect.StructField) bool {
 	return ok
 }
 
+// IsSensitive reports whether struct field s is a leaf or leaf-list whose
+// value is sensitive, and should be redacted when it is displayed.
+func IsSensitive(s reflect.StructField) bool {
+	_, ok := s.Tag.Lookup("sensitive")
+	return ok
+}
+
 // IsYangPresence reports whether struct field s is a YANG presence container.
 func IsYangPresence(s reflect.StructField) bool {
 	_, ok := s.Tag.Lookup("yangPresence")
@@ -567,11 +597,13 @@ func ResolveIfLeafRef(schema *yang.Entry) (*yang.Entry, error) {
 
 	orig := schema
 	s := schema
+	var followed []*yang.Entry
 	for ykind := s.Type.Kind; ykind == yang.Yleafref; {
-		ns, err := FindLeafRefSchema(s, s.Type.Path)
+		ns, err := FollowLeafRef(followed, s, s.Type.Path)
 		if err != nil {
 			return schema, err
 		}
+		followed = append(followed, s)
 		s = ns
 		ykind = s.Type.Kind
 	}
@@ -582,6 +614,67 @@ func ResolveIfLeafRef(schema *yang.Entry) (*yang.Entry, error) {
 	return s, nil
 }
 
+// MaxLeafRefDepth is the maximum number of leafrefs that are followed in
+// succession by the utilities that resolve the type of a leafref, such as
+// ResolveIfLeafRef, before they return a *LeafRefDepthError. It bounds the
+// work done for malformed or adversarial schemas, and should only be changed
+// before any such utility is used.
+var MaxLeafRefDepth = 64
+
+// LeafRefCycleError is the error returned when following the leafrefs of a
+// schema leads back to a leafref that has already been followed, such that
+// its type can never be resolved.
+type LeafRefCycleError struct {
+	// Cycle are the schema paths of the leafrefs forming the cycle, in the
+	// order in which they are followed, starting and ending with the same
+	// leafref.
+	Cycle []string
+}
+
+// Error implements the error interface.
+func (e *LeafRefCycleError) Error() string {
+	return fmt.Sprintf("leafref cycle: %s", strings.Join(e.Cycle, " -> "))
+}
+
+// LeafRefDepthError is the error returned when the number of leafrefs that
+// are followed in succession exceeds MaxLeafRefDepth.
+type LeafRefDepthError struct {
+	// MaxDepth is the limit that was exceeded.
+	MaxDepth int
+	// Path is the schema path of the leafref that was not followed.
+	Path string
+}
+
+// Error implements the error interface.
+func (e *LeafRefDepthError) Error() string {
+	return fmt.Sprintf("leafref %s exceeds the maximum depth of %d leafrefs", e.Path, e.MaxDepth)
+}
+
+// FollowLeafRef returns the schema of the node referred to by the leafref
+// path of the schema, which may be the path of a member of a union type. The
+// schemas of the leafrefs that have already been followed in order to reach
+// schema, if any, are supplied in followed, such that recursive utilities
+// that resolve leafrefs can detect cycles and excessive depth. A
+// *LeafRefCycleError is returned if schema has already been followed, and a
+// *LeafRefDepthError is returned if MaxLeafRefDepth leafrefs have already been
+// followed.
+func FollowLeafRef(followed []*yang.Entry, schema *yang.Entry, path string) (*yang.Entry, error) {
+	for i, e := range followed {
+		if e != schema {
+			continue
+		}
+		var cycle []string
+		for _, c := range followed[i:] {
+			cycle = append(cycle, c.Path())
+		}
+		return nil, &LeafRefCycleError{Cycle: append(cycle, schema.Path())}
+	}
+	if len(followed) >= MaxLeafRefDepth {
+		return nil, &LeafRefDepthError{MaxDepth: MaxLeafRefDepth, Path: schema.Path()}
+	}
+	return FindLeafRefSchema(schema, path)
+}
+
 // ListKeyFieldsMap returns a map[string]bool where the keys of the map
 // are the fields that are the keys of the list described by the supplied
 // yang.Entry. In the case the yang.Entry does not described a keyed list,
@@ -595,3 +688,42 @@ func ListKeyFieldsMap(e *yang.Entry) map[string]bool {
 	}
 	return r
 }
+
+// ListUniqueConstraints returns the "unique" statements of the list described
+// by the supplied yang.Entry. Each constraint is returned as the descendant
+// schema node identifiers that it refers to, relative to the list and with
+// module prefixes removed, such as "config/name". The statements are read
+// from the Extra field of the entry, in which they are stored either as
+// *yang.Value, or as a map if the schema was unmarshalled from JSON. nil is
+// returned if the entry has no unique statements.
+func ListUniqueConstraints(e *yang.Entry) [][]string {
+	var constraints [][]string
+	for _, u := range e.Extra["unique"] {
+		var arg string
+		switch v := u.(type) {
+		case *yang.Value:
+			if v == nil {
+				continue
+			}
+			arg = v.Name
+		case map[string]interface{}:
+			// Schemas that were unmarshalled from JSON store the
+			// fields of each yang.Value in a map.
+			n, ok := v["Name"].(string)
+			if !ok {
+				continue
+			}
+			arg = n
+		default:
+			continue
+		}
+		var paths []string
+		for _, p := range strings.Fields(arg) {
+			paths = append(paths, StripModulePrefixesStr(p))
+		}
+		if len(paths) != 0 {
+			constraints = append(constraints, paths)
+		}
+	}
+	return constraints
+}
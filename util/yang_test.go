@@ -15,10 +15,13 @@
 package util
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/kylelemons/godebug/pretty"
 	"github.com/openconfig/gnmi/errdiff"
 	"github.com/openconfig/goyang/pkg/yang"
@@ -354,6 +357,47 @@ func TestYangChildren(t *testing.T) {
 	}
 }
 
+func TestRPCInputOutput(t *testing.T) {
+	input := &yang.Entry{Name: "input", Kind: yang.InputEntry}
+	output := &yang.Entry{Name: "output", Kind: yang.OutputEntry}
+	tests := []struct {
+		desc    string
+		inEntry *yang.Entry
+		want    []*yang.Entry
+	}{{
+		desc:    "rpc with input and output",
+		inEntry: &yang.Entry{Name: "rpc", RPC: &yang.RPCEntry{Input: input, Output: output}},
+		want:    []*yang.Entry{input, output},
+	}, {
+		desc:    "action with output only",
+		inEntry: &yang.Entry{Name: "action", RPC: &yang.RPCEntry{Output: output}},
+		want:    []*yang.Entry{output},
+	}, {
+		desc:    "rpc without input or output",
+		inEntry: &yang.Entry{Name: "rpc", RPC: &yang.RPCEntry{}},
+	}, {
+		desc:    "container",
+		inEntry: &yang.Entry{Name: "container", Kind: yang.DirectoryEntry},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := RPCInputOutput(tt.inEntry)
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreUnexported(yang.Entry{})); diff != "" {
+				t.Errorf("RPCInputOutput: did not get expected entries, diff(-want,+got):\n%s", diff)
+			}
+			for _, e := range got {
+				if !IsRPCInputOutput(e) {
+					t.Errorf("IsRPCInputOutput(%s): got false, want true", e.Name)
+				}
+			}
+		})
+	}
+	if IsRPCInputOutput(&yang.Entry{Kind: yang.DirectoryEntry}) {
+		t.Errorf("IsRPCInputOutput(container): got true, want false")
+	}
+}
+
 // TestIsConfig tests the isConfig function to ensure that the config parameter is correctly
 // determined.
 func TestIsConfig(t *testing.T) {
@@ -1019,6 +1063,33 @@ func TestIsYgotAnnotation(t *testing.T) {
 	}
 }
 
+func TestIsSensitive(t *testing.T) {
+	type testStruct struct {
+		Yes *string `sensitive:"true"`
+		No  *string
+	}
+
+	tests := []struct {
+		name string
+		in   reflect.StructField
+		want bool
+	}{{
+		name: "sensitive field",
+		in:   reflect.TypeOf(testStruct{}).Field(0),
+		want: true,
+	}, {
+		name: "standard field",
+		in:   reflect.TypeOf(testStruct{}).Field(1),
+		want: false,
+	}}
+
+	for _, tt := range tests {
+		if got := IsSensitive(tt.in); got != tt.want {
+			t.Errorf("%s: IsSensitive(%#v): did not get expected result, got: %v, want: %v", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
 func TestIsYangPresence(t *testing.T) {
 	type testStruct struct {
 		Yes *string `yangPresence:"true"`
@@ -1904,6 +1975,30 @@ func TestResolveIfLeafRef(t *testing.T) {
 						},
 						ListAttr: yang.NewDefaultListAttr(),
 					},
+					"cycle-a": {
+						Name: "cycle-a",
+						Kind: yang.LeafEntry,
+						Type: &yang.YangType{
+							Kind: yang.Yleafref,
+							Path: "../cycle-b",
+						},
+					},
+					"cycle-b": {
+						Name: "cycle-b",
+						Kind: yang.LeafEntry,
+						Type: &yang.YangType{
+							Kind: yang.Yleafref,
+							Path: "../cycle-a",
+						},
+					},
+					"self": {
+						Name: "self",
+						Kind: yang.LeafEntry,
+						Type: &yang.YangType{
+							Kind: yang.Yleafref,
+							Path: "../self",
+						},
+					},
 				},
 			},
 		},
@@ -1992,6 +2087,16 @@ func TestResolveIfLeafRef(t *testing.T) {
 			in:      containerWithLeafListSchema.Dir["container2"].Dir["missing-path"],
 			wantErr: `leafref schema missing-path has empty path`,
 		},
+		{
+			desc:    "container2/cycle-a",
+			in:      containerWithLeafListSchema.Dir["container2"].Dir["cycle-a"],
+			wantErr: `leafref cycle: /container/container2/cycle-a -> /container/container2/cycle-b -> /container/container2/cycle-a`,
+		},
+		{
+			desc:    "container2/self",
+			in:      containerWithLeafListSchema.Dir["container2"].Dir["self"],
+			wantErr: `leafref cycle: /container/container2/self -> /container/container2/self`,
+		},
 	}
 
 	populateParentField(nil, containerWithLeafListSchema)
@@ -2012,6 +2117,106 @@ func TestResolveIfLeafRef(t *testing.T) {
 	}
 }
 
+func TestFollowLeafRef(t *testing.T) {
+	root := &yang.Entry{
+		Name: "root",
+		Kind: yang.DirectoryEntry,
+		Dir: map[string]*yang.Entry{
+			"target": {
+				Name: "target",
+				Kind: yang.LeafEntry,
+				Type: &yang.YangType{Kind: yang.Yint32},
+			},
+		},
+	}
+	// ref-0 refers to ref-1, which refers to ref-2, and so on, with ref-3
+	// referring to target.
+	for i := 0; i < 4; i++ {
+		path := fmt.Sprintf("../ref-%d", i+1)
+		if i == 3 {
+			path = "../target"
+		}
+		name := fmt.Sprintf("ref-%d", i)
+		root.Dir[name] = &yang.Entry{
+			Name: name,
+			Kind: yang.LeafEntry,
+			Type: &yang.YangType{Kind: yang.Yleafref, Path: path},
+		}
+	}
+	populateParentField(nil, root)
+	ref := func(i int) *yang.Entry { return root.Dir[fmt.Sprintf("ref-%d", i)] }
+
+	tests := []struct {
+		desc             string
+		inFollowed       []*yang.Entry
+		inSchema         *yang.Entry
+		inMaxDepth       int
+		want             *yang.Entry
+		wantCycle        []string
+		wantDepthErr     bool
+		wantErrSubstring string
+	}{{
+		desc:       "follows leafref",
+		inFollowed: []*yang.Entry{ref(0), ref(1)},
+		inSchema:   ref(2),
+		inMaxDepth: 64,
+		want:       ref(3),
+	}, {
+		desc:             "cycle",
+		inFollowed:       []*yang.Entry{ref(0), ref(1), ref(2)},
+		inSchema:         ref(1),
+		inMaxDepth:       64,
+		wantCycle:        []string{"/root/ref-1", "/root/ref-2", "/root/ref-1"},
+		wantErrSubstring: "leafref cycle: /root/ref-1 -> /root/ref-2 -> /root/ref-1",
+	}, {
+		desc:             "depth exceeded",
+		inFollowed:       []*yang.Entry{ref(0), ref(1)},
+		inSchema:         ref(2),
+		inMaxDepth:       2,
+		wantDepthErr:     true,
+		wantErrSubstring: "leafref /root/ref-2 exceeds the maximum depth of 2 leafrefs",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			defer func(d int) { MaxLeafRefDepth = d }(MaxLeafRefDepth)
+			MaxLeafRefDepth = tt.inMaxDepth
+
+			got, err := FollowLeafRef(tt.inFollowed, tt.inSchema, tt.inSchema.Type.Path)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("FollowLeafRef: did not get expected error, %s", diff)
+			}
+			var cycleErr *LeafRefCycleError
+			if errors.As(err, &cycleErr) {
+				if diff := cmp.Diff(tt.wantCycle, cycleErr.Cycle); diff != "" {
+					t.Errorf("FollowLeafRef: did not get expected cycle, (-want, +got):\n%s", diff)
+				}
+			} else if tt.wantCycle != nil {
+				t.Errorf("FollowLeafRef: got error %v, want *LeafRefCycleError", err)
+			}
+			var depthErr *LeafRefDepthError
+			if got, want := errors.As(err, &depthErr), tt.wantDepthErr; got != want {
+				t.Errorf("FollowLeafRef: got *LeafRefDepthError %v, want %v", got, want)
+			}
+			if got != tt.want {
+				t.Errorf("FollowLeafRef: got schema %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// ResolveIfLeafRef follows four leafrefs to reach the target.
+	defer func(d int) { MaxLeafRefDepth = d }(MaxLeafRefDepth)
+	MaxLeafRefDepth = 3
+	var depthErr *LeafRefDepthError
+	if _, err := ResolveIfLeafRef(ref(0)); !errors.As(err, &depthErr) {
+		t.Errorf("ResolveIfLeafRef: got error %v, want *LeafRefDepthError", err)
+	}
+	MaxLeafRefDepth = 4
+	if got, err := ResolveIfLeafRef(ref(0)); err != nil || got != root.Dir["target"] {
+		t.Errorf("ResolveIfLeafRef: got (%v, %v), want target schema", got, err)
+	}
+}
+
 func TestListKeyFieldsMap(t *testing.T) {
 	tests := []struct {
 		desc  string
@@ -2058,3 +2263,54 @@ func TestListKeyFieldsMap(t *testing.T) {
 		})
 	}
 }
+
+func TestListUniqueConstraints(t *testing.T) {
+	tests := []struct {
+		desc  string
+		entry *yang.Entry
+		want  [][]string
+	}{{
+		desc:  "no unique statements",
+		entry: &yang.Entry{},
+	}, {
+		desc: "parsed unique statements",
+		entry: &yang.Entry{
+			Extra: map[string][]interface{}{
+				"unique": {
+					&yang.Value{Name: "a b"},
+					&yang.Value{Name: "pfx:config/pfx:c"},
+				},
+			},
+		},
+		want: [][]string{{"a", "b"}, {"config/c"}},
+	}, {
+		desc: "unique statement unmarshalled from JSON",
+		entry: &yang.Entry{
+			Extra: map[string][]interface{}{
+				"unique": {
+					map[string]interface{}{"Name": "config/a  config/b"},
+				},
+			},
+		},
+		want: [][]string{{"config/a", "config/b"}},
+	}, {
+		desc: "invalid unique statements",
+		entry: &yang.Entry{
+			Extra: map[string][]interface{}{
+				"unique": {
+					(*yang.Value)(nil),
+					map[string]interface{}{"Name": 42},
+					&yang.Value{Name: " "},
+				},
+			},
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, ListUniqueConstraints(tt.entry)); diff != "" {
+				t.Errorf("ListUniqueConstraints: did not get expected constraints, (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ package ygen
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	log "github.com/golang/glog"
@@ -50,6 +51,30 @@ type ParseOpts struct {
 	// github.com/openconfig/goyang/pkg/yang library. These specify how the
 	// input YANG files should be parsed.
 	YANGParseOptions yang.Options
+	// IncludeSubtrees specifies the schema paths of the subtrees of the
+	// schema for which code should be generated. When specified, code is
+	// generated only for the nodes within these subtrees, their
+	// ancestors, and the keys of any lists that are ancestors. The paths
+	// are uncompressed schema paths without module prefixes or choice and
+	// case nodes, e.g., "/interfaces/interface/state/counters".
+	IncludeSubtrees []string
+	// ExcludeSubtrees specifies the schema paths of subtrees of the schema
+	// for which code should not be generated, using the same format as
+	// IncludeSubtrees. Exclusions take precedence over inclusions.
+	ExcludeSubtrees []string
+	// DeviationModules specifies the files of YANG modules containing
+	// deviations, such as those supplied by a vendor, that should be
+	// applied to the input modules when building the IR. Nodes that are
+	// deviated as not-supported are removed from the schema, and the
+	// other deviations, such as those replacing the type of a leaf, are
+	// applied to the deviated nodes. Code is not generated for the
+	// deviation modules themselves.
+	DeviationModules []string
+	// GenerateRPCs specifies whether the input and output statements of
+	// YANG RPCs and actions should be mapped to directories, such that
+	// request and response structs are generated for them. By default,
+	// RPCs and actions are not included in the generated code.
+	GenerateRPCs bool
 }
 
 // TransformationOpts specifies transformations to the generated code with
@@ -128,11 +153,13 @@ type yangEnum struct {
 }
 
 // processModules takes a list of the filenames of YANG modules (yangFiles),
-// and a list of paths in which included modules or submodules may be found,
-// and returns a processed set of yang.Entry pointers which correspond to the
-// generated code for the modules. If errors are returned during the Goyang
-// processing of the modules, these errors are returned.
-func processModules(yangFiles, includePaths []string, options yang.Options) ([]*yang.Entry, util.Errors) {
+// a list of the filenames of YANG modules containing deviations to be applied
+// to them (deviationFiles), and a list of paths in which included modules or
+// submodules may be found, and returns a processed set of yang.Entry pointers
+// which correspond to the generated code for the modules. The modules read
+// from deviationFiles are not returned. If errors are returned during the
+// Goyang processing of the modules, these errors are returned.
+func processModules(yangFiles, deviationFiles, includePaths []string, options yang.Options) ([]*yang.Entry, util.Errors) {
 	// Initialise the set of YANG modules within the Goyang parsing package.
 	moduleSet := yang.NewModules()
 	// Propagate the options for the YANG library through to the parsing
@@ -151,6 +178,24 @@ func processModules(yangFiles, includePaths []string, options yang.Options) ([]*
 		errs = util.AppendErr(errs, moduleSet.Read(name))
 	}
 
+	// Read the deviation modules into the same module set, such that
+	// Goyang applies their deviations when the modules are processed.
+	// The modules that are added to the set by reading the deviation
+	// files are recorded such that no code is generated for them.
+	readMods := map[string]bool{}
+	for _, m := range moduleSet.Modules {
+		readMods[m.Name] = true
+	}
+	deviationMods := map[string]bool{}
+	for _, name := range deviationFiles {
+		errs = util.AppendErr(errs, moduleSet.Read(name))
+	}
+	for _, m := range moduleSet.Modules {
+		if !readMods[m.Name] {
+			deviationMods[m.Name] = true
+		}
+	}
+
 	if errs != nil {
 		return nil, errs
 	}
@@ -163,6 +208,9 @@ func processModules(yangFiles, includePaths []string, options yang.Options) ([]*
 	var modNames []string
 	mods := make(map[string]*yang.Module)
 	for _, m := range moduleSet.Modules {
+		if deviationMods[m.Name] {
+			continue
+		}
 		if mods[m.Name] == nil {
 			mods[m.Name] = m
 			modNames = append(modNames, m.Name)
@@ -203,6 +251,15 @@ type mappedYANGDefinitions struct {
 	// modelData stores the details of the set of modules that were parsed to produce
 	// the code. It is optionally returned in the generated code.
 	modelData []*gpb.ModelData
+	// moduleRevisions stores the most recent revision of each of the parsed
+	// modules, keyed by module name.
+	moduleRevisions map[string]string
+	// modulePrefixes stores the prefix of each of the parsed modules, keyed
+	// by module name.
+	modulePrefixes map[string]string
+	// metadataAnnotations stores the metadata annotations that are defined
+	// within the parsed modules.
+	metadataAnnotations []*MetadataAnnotation
 }
 
 // mappedDefinitions finds the set of directory and enumeration entities
@@ -216,10 +273,20 @@ type mappedYANGDefinitions struct {
 // It returns a mappedYANGDefinitions struct populated with the directory, enum
 // entries in the input schemas as well as the calculated schema tree.
 func mappedDefinitions(yangFiles, includePaths []string, opts IROptions) (*mappedYANGDefinitions, util.Errors) {
-	modules, errs := processModules(yangFiles, includePaths, opts.ParseOptions.YANGParseOptions)
+	modules, errs := processModules(yangFiles, opts.ParseOptions.DeviationModules, includePaths, opts.ParseOptions.YANGParseOptions)
 	if errs != nil {
 		return nil, errs
 	}
+	return mappedDefinitionsFromModules(modules, opts)
+}
+
+// mappedDefinitionsFromModules finds the set of directory and enumeration
+// entities that are mapped to objects within output code in a language
+// agnostic manner from the supplied modules, which are the entries for YANG
+// modules that have been parsed and processed by Goyang. The modules are
+// transformed in place according to the compression behaviour in opts.
+func mappedDefinitionsFromModules(modules []*yang.Entry, opts IROptions) (*mappedYANGDefinitions, util.Errors) {
+	var errs util.Errors
 
 	// Build a map of excluded modules to simplify lookup.
 	excluded := map[string]bool{}
@@ -233,19 +300,14 @@ func mappedDefinitions(yangFiles, includePaths []string, opts IROptions) (*mappe
 	enums := map[string]*yang.Entry{}
 	var rootElems, treeElems []*yang.Entry
 	for _, module := range modules {
-		// Need to transform the AST based on compression behaviour.
-		genutil.TransformEntry(module, opts.TransformationOptions.CompressBehaviour)
-
-		errs = append(errs, findMappableEntities(module, dirs, enums, opts.ParseOptions.ExcludeModules, opts.TransformationOptions.CompressBehaviour.CompressEnabled(), opts.ParseOptions.IgnoreUnsupportedStatements, modules)...)
 		if module == nil {
 			errs = append(errs, errors.New("found a nil module in the returned module set"))
 			continue
 		}
 
+		// Need to transform the AST based on compression behaviour.
+		genutil.TransformEntry(module, opts.TransformationOptions.CompressBehaviour)
 		for _, e := range module.Dir {
-			if !excluded[module.Name] {
-				rootElems = append(rootElems, e)
-			}
 			treeElems = append(treeElems, e)
 		}
 	}
@@ -255,12 +317,31 @@ func mappedDefinitions(yangFiles, includePaths []string, opts IROptions) (*mappe
 
 	// Build the schematree for the modules provided - we build for all of the
 	// root elements, since we might need to reference a part of the schema that
-	// we are not outputting for leafref lookups.
+	// we are not outputting for leafref lookups. The schematree is built prior
+	// to pruning any subtrees, such that leafrefs can still be resolved.
 	st, err := yangschema.BuildTree(treeElems)
 	if err != nil {
 		return nil, []error{err}
 	}
 
+	if len(opts.ParseOptions.IncludeSubtrees) != 0 || len(opts.ParseOptions.ExcludeSubtrees) != 0 {
+		if err := pruneSubtrees(modules, opts.ParseOptions.IncludeSubtrees, opts.ParseOptions.ExcludeSubtrees); err != nil {
+			return nil, util.NewErrs(err)
+		}
+	}
+
+	for _, module := range modules {
+		errs = append(errs, findMappableEntities(module, dirs, enums, opts.ParseOptions.ExcludeModules, opts.TransformationOptions.CompressBehaviour.CompressEnabled(), opts.ParseOptions.IgnoreUnsupportedStatements, opts.ParseOptions.GenerateRPCs, modules)...)
+		if !excluded[module.Name] {
+			for _, e := range module.Dir {
+				rootElems = append(rootElems, e)
+			}
+		}
+	}
+	if errs != nil {
+		return nil, errs
+	}
+
 	// If we were asked to generate a fake root entity, then go and find the top-level entities that
 	// we were asked for.
 	if opts.TransformationOptions.GenerateFakeRoot {
@@ -283,15 +364,254 @@ func mappedDefinitions(yangFiles, includePaths []string, opts IROptions) (*mappe
 		return nil, util.NewErrs(fmt.Errorf("cannot extract model data, %v", err))
 	}
 
+	annotations, err := findMetadataAnnotations(modules)
+	if err != nil {
+		return nil, util.NewErrs(err)
+	}
+
 	return &mappedYANGDefinitions{
-		directoryEntries: dirs,
-		enumEntries:      enums,
-		schematree:       st,
-		modules:          ms,
-		modelData:        modelData,
+		directoryEntries:    dirs,
+		enumEntries:         enums,
+		schematree:          st,
+		modules:             ms,
+		modelData:           modelData,
+		moduleRevisions:     findModuleRevisions(modules),
+		modulePrefixes:      findModulePrefixes(modules),
+		metadataAnnotations: annotations,
 	}, nil
 }
 
+// findMetadataAnnotations returns the metadata annotations that are defined
+// using the md:annotation statement of the ietf-yang-metadata module within
+// the supplied modules, sorted by defining module and name. The type of each
+// annotation is resolved to its base YANG type, such that a native type can
+// be used to represent its value.
+func findMetadataAnnotations(modules []*yang.Entry) ([]*MetadataAnnotation, error) {
+	var annotations []*MetadataAnnotation
+	for _, m := range modules {
+		mod, ok := m.Node.(*yang.Module)
+		if !ok {
+			continue
+		}
+		exts, err := yang.MatchingExtensions(mod, "ietf-yang-metadata", "annotation")
+		if err != nil {
+			return nil, fmt.Errorf("cannot find metadata annotations in module %s, %v", mod.Name, err)
+		}
+		for _, ext := range exts {
+			a := &MetadataAnnotation{
+				Name:           ext.Argument,
+				DefiningModule: mod.Name,
+			}
+			for _, s := range ext.SubStatements() {
+				switch s.Keyword {
+				case "type":
+					a.TypeName = s.Argument
+				case "description":
+					a.Description = s.Argument
+				}
+			}
+			if a.TypeName == "" {
+				return nil, fmt.Errorf("metadata annotation %s:%s does not have a type", mod.Name, a.Name)
+			}
+			if a.Kind, err = resolveTypeKind(mod, a.TypeName); err != nil {
+				return nil, fmt.Errorf("cannot resolve type of metadata annotation %s:%s, %v", mod.Name, a.Name, err)
+			}
+			annotations = append(annotations, a)
+		}
+	}
+	sort.Slice(annotations, func(i, j int) bool {
+		if annotations[i].DefiningModule != annotations[j].DefiningModule {
+			return annotations[i].DefiningModule < annotations[j].DefiningModule
+		}
+		return annotations[i].Name < annotations[j].Name
+	})
+	return annotations, nil
+}
+
+// resolveTypeKind returns the base YANG type of the type with the supplied
+// name, which is either a built-in type, or the name of a typedef, optionally
+// prefixed with the prefix of the module that defines it, as referenced from
+// within mod.
+func resolveTypeKind(mod *yang.Module, typeName string) (yang.TypeKind, error) {
+	if k, ok := yang.TypeKindFromName[typeName]; ok {
+		return k, nil
+	}
+	var prefix, name string
+	if i := strings.Index(typeName, ":"); i != -1 {
+		prefix, name = typeName[:i], typeName[i+1:]
+	} else {
+		name = typeName
+	}
+	defMod := yang.FindModuleByPrefix(mod, prefix)
+	if defMod == nil {
+		return yang.Ynone, fmt.Errorf("cannot find module with prefix %q", prefix)
+	}
+	typedefs := defMod.Typedef
+	for _, i := range defMod.Include {
+		if i.Module != nil {
+			typedefs = append(typedefs, i.Module.Typedef...)
+		}
+	}
+	for _, td := range typedefs {
+		if td.Name == name && td.YangType != nil {
+			return td.YangType.Kind, nil
+		}
+	}
+	return yang.Ynone, fmt.Errorf("cannot find typedef %s in module %s", name, defMod.Name)
+}
+
+// findModuleRevisions returns a map, keyed by module name, of the most recent
+// revision date of each of the supplied modules. Modules that do not have a
+// revision statement are not included in the map.
+func findModuleRevisions(modules []*yang.Entry) map[string]string {
+	revs := map[string]string{}
+	for _, m := range modules {
+		mod, ok := m.Node.(*yang.Module)
+		if !ok {
+			continue
+		}
+		for _, r := range mod.Revision {
+			// Revisions are dates of the form YYYY-MM-DD, such that
+			// the most recent is the greatest lexicographically.
+			if r.Name > revs[m.Name] {
+				revs[m.Name] = r.Name
+			}
+		}
+	}
+	return revs
+}
+
+// findModulePrefixes returns a map, keyed by module name, of the prefix
+// specified by the prefix statement of each of the supplied modules.
+func findModulePrefixes(modules []*yang.Entry) map[string]string {
+	prefixes := map[string]string{}
+	for _, m := range modules {
+		mod, ok := m.Node.(*yang.Module)
+		if !ok || mod.Prefix == nil {
+			continue
+		}
+		prefixes[m.Name] = mod.Prefix.Name
+	}
+	return prefixes
+}
+
+// pruneSubtrees removes the nodes of the supplied modules that are not
+// within, or an ancestor of, any of the subtrees whose schema paths are
+// specified in include, or that are within any of the subtrees specified in
+// exclude. If include is empty, all nodes that are not excluded are retained.
+// The keys of lists that are retained are always retained, including those
+// within the list's config and state containers, such that the list can be
+// mapped. Directories that are left empty by pruning are removed. An error
+// is returned if any path in include does not exist in the schema.
+func pruneSubtrees(modules []*yang.Entry, include, exclude []string) error {
+	var includePaths, excludePaths [][]string
+	for _, p := range include {
+		includePaths = append(includePaths, util.SplitPath(strings.TrimPrefix(p, "/")))
+	}
+	for _, p := range exclude {
+		excludePaths = append(excludePaths, util.SplitPath(strings.TrimPrefix(p, "/")))
+	}
+
+	found := map[int]bool{}
+	for _, m := range modules {
+		if m != nil {
+			pruneSubtreesInternal(m, nil, includePaths, excludePaths, found)
+		}
+	}
+
+	var errs []string
+	for i, p := range include {
+		if !found[i] {
+			errs = append(errs, p)
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("subtrees to be included do not exist in the schema: %v", errs)
+	}
+	return nil
+}
+
+// pruneSubtreesInternal is an internal part of pruneSubtrees, which prunes
+// the children of e, whose data tree path is path. found records the indices
+// of the include paths that exist in the schema.
+func pruneSubtreesInternal(e *yang.Entry, path []string, include, exclude [][]string, found map[int]bool) {
+	for name, ch := range e.Dir {
+		chPath := path
+		if !util.IsChoiceOrCase(ch) {
+			chPath = append(append([]string{}, path...), ch.Name)
+		}
+
+		if matchesAnyPrefix(chPath, exclude) {
+			delete(e.Dir, name)
+			continue
+		}
+
+		var within, ancestor bool
+		for i, inc := range include {
+			switch {
+			case isPathPrefix(inc, chPath):
+				within = true
+				if len(inc) == len(chPath) {
+					found[i] = true
+				}
+			case isPathPrefix(chPath, inc):
+				ancestor = true
+			}
+		}
+
+		switch {
+		case len(include) == 0, within, ancestor:
+			hadChildren := len(ch.Dir) != 0
+			pruneSubtreesInternal(ch, chPath, include, exclude, found)
+			// Directories that are left empty by pruning are removed.
+			if hadChildren && len(ch.Dir) == 0 {
+				delete(e.Dir, name)
+			}
+		case isListKeyEntry(e, ch):
+		case util.IsConfigState(ch) && util.IsKeyedList(e):
+			// Retain only the keys of the list within its config and
+			// state containers.
+			for n, gch := range ch.Dir {
+				if !isListKeyEntry(e, gch) {
+					delete(ch.Dir, n)
+				}
+			}
+		default:
+			delete(e.Dir, name)
+		}
+	}
+}
+
+// isListKeyEntry reports whether ch is a leaf that has the same name as one of
+// the keys of the list l.
+func isListKeyEntry(l, ch *yang.Entry) bool {
+	return util.IsKeyedList(l) && ch.IsLeaf() && util.ListKeyFieldsMap(l)[ch.Name]
+}
+
+// matchesAnyPrefix reports whether any of the supplied prefixes is a prefix of
+// path.
+func matchesAnyPrefix(path []string, prefixes [][]string) bool {
+	for _, p := range prefixes {
+		if isPathPrefix(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPathPrefix reports whether prefix is a prefix of, or is equal to, path.
+func isPathPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // findMappableEntities finds the descendants of a yang.Entry (e) that should be mapped in
 // the generated code. The descendants that represent directories are appended to the dirs
 // map (keyed by the schema path). Those that represent enumerated types (identityref, enumeration,
@@ -301,7 +621,7 @@ func mappedDefinitions(yangFiles, includePaths []string, opts IROptions) (*mappe
 // mapped with path compression enabled. The set of modules that the current code generation
 // is processing is specified by the modules slice. This function returns slice of errors
 // encountered during processing.
-func findMappableEntities(e *yang.Entry, dirs map[string]*yang.Entry, enums map[string]*yang.Entry, excludeModules []string, compressPaths, ignoreUnsupportedStatements bool, modules []*yang.Entry) util.Errors {
+func findMappableEntities(e *yang.Entry, dirs map[string]*yang.Entry, enums map[string]*yang.Entry, excludeModules []string, compressPaths, ignoreUnsupportedStatements, generateRPCs bool, modules []*yang.Entry) util.Errors {
 	// Skip entities who are defined within a module that we have been instructed
 	// not to generate code for.
 	for _, s := range excludeModules {
@@ -326,12 +646,12 @@ func findMappableEntities(e *yang.Entry, dirs map[string]*yang.Entry, enums map[
 			// If this is a config or state container and we are compressing paths
 			// then we do not want to map this container - but we do want to map its
 			// children.
-			errs = util.AppendErrs(errs, findMappableEntities(ch, dirs, enums, excludeModules, compressPaths, ignoreUnsupportedStatements, modules))
+			errs = util.AppendErrs(errs, findMappableEntities(ch, dirs, enums, excludeModules, compressPaths, ignoreUnsupportedStatements, generateRPCs, modules))
 		case util.HasOnlyChild(ch) && util.Children(ch)[0].IsList() && compressPaths:
 			// This is a surrounding container for a list, and we are compressing
 			// paths, so we don't want to map it but again we do want to map its
 			// children.
-			errs = util.AppendErrs(errs, findMappableEntities(ch, dirs, enums, excludeModules, compressPaths, ignoreUnsupportedStatements, modules))
+			errs = util.AppendErrs(errs, findMappableEntities(ch, dirs, enums, excludeModules, compressPaths, ignoreUnsupportedStatements, generateRPCs, modules))
 		case util.IsChoiceOrCase(ch):
 			// Don't map for a choice or case node itself, and rather skip over it.
 			// However, we must walk each branch to find the first container that
@@ -351,12 +671,12 @@ func findMappableEntities(e *yang.Entry, dirs map[string]*yang.Entry, enums map[
 				if gch.IsContainer() || gch.IsList() {
 					dirs[fmt.Sprintf("%s/%s", ch.Parent.Path(), gch.Name)] = gch
 				}
-				errs = util.AppendErrs(errs, findMappableEntities(gch, dirs, enums, excludeModules, compressPaths, ignoreUnsupportedStatements, modules))
+				errs = util.AppendErrs(errs, findMappableEntities(gch, dirs, enums, excludeModules, compressPaths, ignoreUnsupportedStatements, generateRPCs, modules))
 			}
 		case ch.IsContainer(), ch.IsList():
 			dirs[ch.Path()] = ch
 			// Recurse down the tree.
-			errs = util.AppendErrs(errs, findMappableEntities(ch, dirs, enums, excludeModules, compressPaths, ignoreUnsupportedStatements, modules))
+			errs = util.AppendErrs(errs, findMappableEntities(ch, dirs, enums, excludeModules, compressPaths, ignoreUnsupportedStatements, generateRPCs, modules))
 		case ch.Kind == yang.AnyDataEntry:
 			continue
 		default:
@@ -367,6 +687,19 @@ func findMappableEntities(e *yang.Entry, dirs map[string]*yang.Entry, enums map[
 			errs = util.AppendErr(errs, fmt.Errorf("unsupported statement type (%v) in findMappableEntities for %s", ch.Kind, ch.Path()))
 		}
 	}
+
+	if !generateRPCs {
+		return errs
+	}
+	// RPCs and actions are not returned as children of e, and hence are
+	// handled separately. Their input and output statements are mapped in
+	// the same way as containers.
+	for _, ch := range e.Dir {
+		for _, io := range util.RPCInputOutput(ch) {
+			dirs[io.Path()] = io
+			errs = util.AppendErrs(errs, findMappableEntities(io, dirs, enums, excludeModules, compressPaths, ignoreUnsupportedStatements, generateRPCs, modules))
+		}
+	}
 	return errs
 }
 
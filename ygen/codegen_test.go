@@ -407,7 +407,7 @@ func TestFindMappableEntities(t *testing.T) {
 			structs := make(map[string]*yang.Entry)
 			enums := make(map[string]*yang.Entry)
 
-			errs := findMappableEntities(tt.in, structs, enums, tt.inSkipModules, compress, tt.inIgnoreUnsupportedStatements, tt.inModules)
+			errs := findMappableEntities(tt.in, structs, enums, tt.inSkipModules, compress, tt.inIgnoreUnsupportedStatements, false, tt.inModules)
 
 			var err error
 			switch {
@@ -267,12 +267,16 @@ func getOrderedDirDetails(langMapper LangMapper, directory map[string]*Directory
 					LeafrefTargetPath: target.Path(),
 					Description:       field.Description,
 					ConfigFalse:       !util.IsConfig(field),
+					WhenConditions:    whenConditions(field, dir.Entry),
 				},
 				MappedPaths:             mp,
 				MappedPathModules:       mm,
 				ShadowMappedPaths:       smp,
 				ShadowMappedPathModules: smm,
 			}
+			if nd.YANGDetails.OpenConfigVersion, err = openConfigVersion(field); err != nil {
+				return nil, err
+			}
 			if hasShadowField {
 				nd.YANGDetails.ShadowSchemaPath = util.SchemaTreePathNoModule(shadowField)
 			}
@@ -291,6 +295,9 @@ func getOrderedDirDetails(langMapper LangMapper, directory map[string]*Directory
 
 				nd.Type = t
 				nd.LangType = mtype
+				if nd.YANGDetails.Sensitive, err = isSensitive(field); err != nil {
+					return nil, err
+				}
 			case field.IsList():
 				nd.Type = ListNode
 				nd.YANGDetails.OrderedByUser = field.ListAttr.OrderedByUser
@@ -326,6 +333,66 @@ func getOrderedDirDetails(langMapper LangMapper, directory map[string]*Directory
 	return dirDets, nil
 }
 
+// isSensitive reports whether the supplied leaf or leaf-list is sensitive,
+// as indicated by the ietf-netconf-acm default-deny-all extension (RFC8341)
+// being specified for the leaf or any of its ancestors.
+func isSensitive(e *yang.Entry) (bool, error) {
+	for ; e != nil; e = e.Parent {
+		exts, err := yang.MatchingEntryExtensions(e, "ietf-netconf-acm", "default-deny-all")
+		if err != nil {
+			return false, fmt.Errorf("cannot retrieve ietf-netconf-acm extensions: %v", err)
+		}
+		if len(exts) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// openConfigVersion returns the argument of the oc-ext:openconfig-version
+// statement specified on the supplied entry, or on its closest ancestor
+// that is defined in the same module as the entry. An empty string is
+// returned if there is no such statement.
+func openConfigVersion(e *yang.Entry) (string, error) {
+	if e.Node == nil {
+		return "", nil
+	}
+	mod := yang.RootNode(e.Node)
+	for ; e != nil && e.Parent != nil; e = e.Parent {
+		if e.Node == nil || yang.RootNode(e.Node) != mod {
+			break
+		}
+		exts, err := yang.MatchingEntryExtensions(e, "openconfig-extensions", "openconfig-version")
+		if err != nil {
+			return "", fmt.Errorf("cannot retrieve openconfig-extensions extensions: %v", err)
+		}
+		if len(exts) > 0 {
+			return exts[0].Argument, nil
+		}
+	}
+	return "", nil
+}
+
+// whenConditions returns the XPath expressions of the when statements that
+// guard the supplied field of the directory dir. These are the when
+// statements of the field, and of each of its ancestors that are below dir,
+// along with those of the augment statements that add them.
+func whenConditions(field, dir *yang.Entry) []string {
+	var conds []string
+	for e := field; e != nil && e.Parent != nil && e.Path() != dir.Path(); e = e.Parent {
+		if w, ok := e.GetWhenXPath(); ok {
+			conds = append(conds, w)
+		}
+		if e.Node == nil {
+			continue
+		}
+		if a, ok := e.Node.ParentNode().(*yang.Augment); ok && a.When != nil {
+			conds = append(conds, a.When.Name)
+		}
+	}
+	return conds
+}
+
 // FindSchemaPath finds the relative or absolute schema path of a given field
 // of a Directory. The Field is specified as a name in order to guarantee its
 // existence before processing.
@@ -75,7 +75,36 @@ func GenerateIR(yangFiles, includePaths []string, langMapper LangMapper, opts IR
 	if errs != nil {
 		return nil, errs
 	}
+	return generateIR(mdef, langMapper, opts)
+}
+
+// GenerateIRFromEntries creates the ygen intermediate representation for a
+// set of YANG modules that have already been parsed by the caller, rather
+// than read from files. Each of the supplied modules must be the entry for a
+// YANG module that is returned by yang.ToEntry after the set of modules
+// containing it has been processed, i.e., after yang.Modules.Process has been
+// called, such that any deviations have already been applied. The
+// DeviationModules and YANGParseOptions fields of the supplied IROptions are
+// hence not used. All modules that are referenced by the supplied modules,
+// e.g., for leafref or identityref lookups, must be supplied.
+//
+// The supplied modules are transformed in place whilst the IR is generated,
+// such that they must not be reused to generate another IR.
+//
+// The supplied LangMapper and IROptions are used as described for
+// GenerateIR.
+func GenerateIRFromEntries(modules []*yang.Entry, langMapper LangMapper, opts IROptions) (*IR, error) {
+	mdef, errs := mappedDefinitionsFromModules(modules, opts)
+	if errs != nil {
+		return nil, errs
+	}
+	return generateIR(mdef, langMapper, opts)
+}
 
+// generateIR creates the ygen intermediate representation from the supplied
+// mapped definitions, using the supplied LangMapper and IROptions.
+func generateIR(mdef *mappedYANGDefinitions, langMapper LangMapper, opts IROptions) (*IR, error) {
+	var errs util.Errors
 	enumSet, genEnums, errs := findEnumSet(mdef.enumEntries, opts.TransformationOptions.CompressBehaviour.CompressEnabled(), !opts.TransformationOptions.EnumerationsUseUnderscores, opts.TransformationOptions.SkipEnumDeduplication, opts.TransformationOptions.ShortenEnumLeafNames, opts.TransformationOptions.UseDefiningModuleForTypedefEnumNames, opts.AppendEnumSuffixForSimpleUnionEnums, opts.TransformationOptions.EnumOrgPrefixesToTrim)
 	if errs != nil {
 		return nil, errs
@@ -178,11 +207,14 @@ func GenerateIR(yangFiles, includePaths []string, langMapper LangMapper, opts IR
 	}
 
 	return &IR{
-		Directories:   dirDets,
-		Enums:         enumDefinitionMap,
-		ModelData:     mdef.modelData,
-		opts:          opts,
-		fakeroot:      rootEntry,
-		parsedModules: mdef.modules,
+		Directories:         dirDets,
+		Enums:               enumDefinitionMap,
+		ModelData:           mdef.modelData,
+		MetadataAnnotations: mdef.metadataAnnotations,
+		opts:                opts,
+		fakeroot:            rootEntry,
+		parsedModules:       mdef.modules,
+		moduleRevisions:     mdef.moduleRevisions,
+		modulePrefixes:      mdef.modulePrefixes,
 	}, nil
 }
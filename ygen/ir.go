@@ -17,6 +17,7 @@ package ygen
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/openconfig/goyang/pkg/yang"
@@ -302,6 +303,11 @@ type IR struct {
 	// ModelData stores the metadata extracted from the input YANG modules.
 	ModelData []*gpb.ModelData
 
+	// MetadataAnnotations is the set of metadata annotations that are
+	// defined using the md:annotation statement of RFC7952 within the
+	// input YANG modules, sorted by defining module and name.
+	MetadataAnnotations []*MetadataAnnotation
+
 	// opts stores the IROptions that were used to generate the IR.
 	opts IROptions
 
@@ -312,6 +318,77 @@ type IR struct {
 	// fakeroot stores the fake root's AST node for creating a serialized
 	// version of the AST if needed.
 	fakeroot *yang.Entry
+
+	// moduleRevisions stores the most recent revision of each input YANG
+	// module, keyed by module name.
+	moduleRevisions map[string]string
+
+	// modulePrefixes stores the prefix of each input YANG module, keyed by
+	// module name.
+	modulePrefixes map[string]string
+}
+
+// MetadataAnnotation describes a metadata annotation that is defined using
+// the md:annotation extension statement of the ietf-yang-metadata module
+// (RFC7952) within an input YANG module.
+type MetadataAnnotation struct {
+	// Name is the name of the annotation.
+	Name string
+	// DefiningModule is the name of the module that defines the
+	// annotation, which qualifies its name when it is encoded.
+	DefiningModule string
+	// Description is the description of the annotation, if any.
+	Description string
+	// TypeName is the name of the type of the value of the annotation, as
+	// specified within the YANG module.
+	TypeName string
+	// Kind is the base YANG type of the value of the annotation, once any
+	// typedefs have been resolved.
+	Kind yang.TypeKind
+}
+
+// ModuleRevision returns the most recent revision date of the input YANG
+// module with the supplied name, or the empty string if the module has no
+// revision statements or is not an input module.
+func (ir *IR) ModuleRevision(name string) string {
+	if ir == nil {
+		return ""
+	}
+	return ir.moduleRevisions[name]
+}
+
+// ModulePrefix returns the prefix specified by the prefix statement of the
+// input YANG module with the supplied name, or the empty string if the module
+// is not an input module.
+func (ir *IR) ModulePrefix(name string) string {
+	if ir == nil {
+		return ""
+	}
+	return ir.modulePrefixes[name]
+}
+
+// Entry returns the YANG schema entry of the node with the supplied absolute
+// YANG schema path, which includes the module name as well as choice and case
+// elements, as stored in the Path field of YANGNodeDetails. It returns nil if
+// there is no such node within the modules used to generate the IR.
+func (ir *IR) Entry(path string) *yang.Entry {
+	if ir == nil {
+		return nil
+	}
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for _, m := range ir.parsedModules {
+		if m.Name != parts[0] {
+			continue
+		}
+		e := m
+		for _, p := range parts[1:] {
+			if e = e.Dir[p]; e == nil {
+				return nil
+			}
+		}
+		return e
+	}
+	return nil
 }
 
 // OrderedDirectoryPaths returns the absolute YANG paths of all ParsedDirectory
@@ -709,6 +786,27 @@ type YANGNodeDetails struct {
 	// statement in YANG:
 	// https://datatracker.ietf.org/doc/html/rfc7950#section-7.21.1
 	ConfigFalse bool
+	// Sensitive indicates whether the node is a leaf or leaf-list whose
+	// value is sensitive, as indicated by the ietf-netconf-acm
+	// default-deny-all extension being specified for the node or one of
+	// its ancestors:
+	// https://datatracker.ietf.org/doc/html/rfc8341#section-3.5.2
+	Sensitive bool
+	// WhenConditions are the XPath expressions of the when statements
+	// that guard the node, i.e., the conditions under which the node may
+	// exist in the data tree. They include the when statements of the
+	// node itself, of the augment statements that add it, and of the
+	// schema nodes between the node and its parent directory, such as
+	// choice and case statements, and containers that are removed by
+	// compression. The conditions closest to the node are listed first.
+	WhenConditions []string
+	// OpenConfigVersion is the version of DefiningModule in which the node
+	// was introduced, as specified by an oc-ext:openconfig-version
+	// statement on the node, or on its closest ancestor that is defined in
+	// the same module. Statements on the module itself are not considered,
+	// since they specify the current version of the module rather than
+	// when the node was added. It is empty if no such statement exists.
+	OpenConfigVersion string
 }
 
 // EnumeratedValueType is used to indicate the source YANG type
@@ -43,7 +43,18 @@ func buildJSONTree(ms []*yang.Entry, dn map[string]string, fakeroot *yang.Entry,
 	}
 	for _, m := range ms {
 		annotateChildren(m, dn, inclDescriptions)
-		for _, ch := range util.Children(m) {
+		chs := util.Children(m)
+		// RPCs are included in the tree only where a type is generated for
+		// their input or output statements.
+		for _, ch := range m.Dir {
+			for _, io := range util.RPCInputOutput(ch) {
+				if _, ok := dn[io.Path()]; ok {
+					chs = append(chs, ch)
+					break
+				}
+			}
+		}
+		for _, ch := range chs {
 			if _, ex := rootEntry.Dir[ch.Name]; ex {
 				return nil, fmt.Errorf("overlapping root children for key %s", ch.Name)
 			}
@@ -90,6 +101,17 @@ func annotateChildren(e *yang.Entry, dn map[string]string, inclDescriptions bool
 			annotateChildren(ch, dn, inclDescriptions)
 		}
 	}
+	// The input and output statements of RPCs and actions are serialised
+	// within the RPC entry, and are annotated in the same way as containers.
+	for _, ch := range e.Dir {
+		if ch.RPC == nil {
+			continue
+		}
+		annotateEntry(ch, dn, inclDescriptions)
+		for _, io := range util.RPCInputOutput(ch) {
+			annotateChildren(io, dn, inclDescriptions)
+		}
+	}
 }
 
 // annotateEntry modifies the yang.Entry e to:
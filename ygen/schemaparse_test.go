@@ -289,6 +289,56 @@ func TestBuildJSONTree(t *testing.T) {
 	}
 }
 
+func TestBuildJSONTreeListAttr(t *testing.T) {
+	module := &yang.Entry{
+		Name: "a-module",
+		Kind: yang.DirectoryEntry,
+	}
+	list := &yang.Entry{
+		Name:     "bounded-list",
+		Kind:     yang.DirectoryEntry,
+		Parent:   module,
+		ListAttr: &yang.ListAttr{MinElements: 1, MaxElements: 3},
+		Dir:      map[string]*yang.Entry{},
+	}
+	leafList := &yang.Entry{
+		Name:     "unbounded-leaf-list",
+		Kind:     yang.LeafEntry,
+		Parent:   module,
+		Type:     &yang.YangType{Kind: yang.Ystring},
+		ListAttr: yang.NewDefaultListAttr(),
+	}
+	module.Dir = map[string]*yang.Entry{
+		"bounded-list":        list,
+		"unbounded-leaf-list": leafList,
+	}
+
+	gotb, err := buildJSONTree([]*yang.Entry{module}, map[string]string{}, nil, false, false)
+	if err != nil {
+		t.Fatalf("buildJSONTree: got unexpected error: %v", err)
+	}
+	got := &yang.Entry{}
+	if err := json.Unmarshal(gotb, got); err != nil {
+		t.Fatalf("cannot unmarshal JSON tree: %v", err)
+	}
+
+	// The size constraints of lists and leaf-lists must be retained in the
+	// schema, since they are enforced when validating the generated structs.
+	for name, want := range map[string]*yang.ListAttr{
+		"bounded-list":        {MinElements: 1, MaxElements: 3},
+		"unbounded-leaf-list": yang.NewDefaultListAttr(),
+	} {
+		e, ok := got.Dir[name]
+		if !ok {
+			t.Errorf("did not find %s in JSON tree", name)
+			continue
+		}
+		if diff := cmp.Diff(want, e.ListAttr); diff != "" {
+			t.Errorf("%s: did not get expected ListAttr, diff(-want, +got):\n%s", name, diff)
+		}
+	}
+}
+
 func TestWriteGzippedByteSlice(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,352 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"strings"
+
+	"github.com/openconfig/ygot/internal/yreflect"
+	"github.com/openconfig/ygot/util"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// AnonymizeCategory is a category of potentially sensitive data that is
+// replaced with a pseudonym by Anonymize.
+type AnonymizeCategory int64
+
+const (
+	// AnonymizeIPAddress indicates that a leaf contains IPv4 or IPv6
+	// addresses or prefixes. IPv4 addresses are replaced by addresses within
+	// 10.0.0.0/8, and IPv6 addresses by addresses within fd00::/8. The prefix
+	// length of prefixes is retained. Each address is mapped independently,
+	// such that the relationships between addresses, e.g., membership of a
+	// subnet, are not retained.
+	AnonymizeIPAddress AnonymizeCategory = iota
+	// AnonymizeHostname indicates that a leaf contains a hostname or domain
+	// name, which is replaced by a name of the form "host-<hash>".
+	AnonymizeHostname
+	// AnonymizeDescription indicates that a leaf contains free-form text,
+	// which is replaced by text of the form "description-<hash>".
+	AnonymizeDescription
+	// AnonymizeSecret indicates that a leaf contains a secret, such as a
+	// password or key, which is replaced by a value of the form
+	// "secret-<hash>".
+	AnonymizeSecret
+)
+
+// String returns the name of the category.
+func (c AnonymizeCategory) String() string {
+	switch c {
+	case AnonymizeIPAddress:
+		return "IP address"
+	case AnonymizeHostname:
+		return "hostname"
+	case AnonymizeDescription:
+		return "description"
+	case AnonymizeSecret:
+		return "secret"
+	default:
+		return fmt.Sprintf("unknown category %d", int64(c))
+	}
+}
+
+// AnonymizeOpts specifies the data that is replaced by Anonymize.
+type AnonymizeOpts struct {
+	// Paths maps the data tree paths of leaves and leaf-lists, without list
+	// keys, e.g., "/interfaces/interface/config/description", to the
+	// category of the data that they contain. Leaves whose paths are not
+	// within Paths are copied unchanged. Where a GoStruct field is mapped to
+	// more than one path, e.g., for list keys in compressed GoStructs, the
+	// category of any of its paths is used.
+	Paths map[string]AnonymizeCategory
+	// Key is the key from which pseudonyms are derived. The same value
+	// within a category is always replaced by the same pseudonym for a
+	// particular key, such that references between leaves, e.g., leafrefs,
+	// remain valid. Key should not be shared along with the anonymized data,
+	// since the original values could otherwise be guessed.
+	Key []byte
+}
+
+// Anonymize returns a copy of the supplied GoStruct in which the values of the
+// leaves and leaf-lists that are specified by opts are replaced by
+// deterministic pseudonyms, such that the copy can be shared without
+// disclosing the replaced data. The supplied GoStruct is not modified.
+//
+// Anonymized leaves must be of string type, and pseudonyms are chosen such
+// that they are valid values of the common types for each category. Since
+// the pseudonyms of list keys are used as the keys of the copy's lists, two
+// list entries whose keys are mapped to the same pseudonym result in an
+// error. Patterns and lengths specific to a schema are not considered, so
+// the copy should be validated before it is shared.
+func Anonymize(s GoStruct, opts *AnonymizeOpts) (GoStruct, error) {
+	if opts == nil {
+		return nil, fmt.Errorf("nil AnonymizeOpts")
+	}
+	c, err := DeepCopy(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fields that are mapped to more than one path are visited once per
+	// path, hence each leaf is first recorded such that it is only replaced
+	// once.
+	type anonLeaf struct {
+		path string
+		v    reflect.Value
+		cat  AnonymizeCategory
+	}
+	var leaves []*anonLeaf
+	seen := map[uintptr]bool{}
+	if err := forEachSetLeaf(c, func(paths []*gnmipb.Path, v reflect.Value) error {
+		for _, p := range paths {
+			ps := pathWithoutKeys(p)
+			cat, ok := opts.Paths[ps]
+			if !ok {
+				continue
+			}
+			if addr := v.Addr().Pointer(); !seen[addr] {
+				seen[addr] = true
+				leaves = append(leaves, &anonLeaf{path: ps, v: v, cat: cat})
+			}
+			return nil
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if len(leaves) == 0 {
+		return c, nil
+	}
+
+	for _, l := range leaves {
+		if err := anonymizeValue(l.v, l.cat, opts.Key); err != nil {
+			return nil, fmt.Errorf("%s: %v", l.path, err)
+		}
+	}
+
+	// Since list keys may have been anonymized, the keys of lists are
+	// rebuilt from their entries.
+	if err := rekeyLists(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// pathWithoutKeys returns the string form of p without any list keys.
+func pathWithoutKeys(p *gnmipb.Path) string {
+	var b strings.Builder
+	for _, e := range p.GetElem() {
+		b.WriteString("/")
+		b.WriteString(e.GetName())
+	}
+	return b.String()
+}
+
+// anonymizeValue replaces the string value, or values, of the leaf or
+// leaf-list v with pseudonyms for category cat, derived from key.
+func anonymizeValue(v reflect.Value, cat AnonymizeCategory, key []byte) error {
+	switch {
+	case v.Kind() == reflect.Ptr && v.Type().Elem().Kind() == reflect.String:
+		p, err := pseudonym(v.Elem().String(), cat, key)
+		if err != nil {
+			return err
+		}
+		nv := reflect.New(v.Type().Elem())
+		nv.Elem().SetString(p)
+		v.Set(nv)
+	case v.Kind() == reflect.Interface && v.Elem().Kind() == reflect.String:
+		// Simple union types whose value is of a string type.
+		p, err := pseudonym(v.Elem().String(), cat, key)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(p).Convert(v.Elem().Type()))
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		nv := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			p, err := pseudonym(v.Index(i).String(), cat, key)
+			if err != nil {
+				return err
+			}
+			nv.Index(i).SetString(p)
+		}
+		v.Set(nv)
+	default:
+		return fmt.Errorf("cannot anonymize %s leaf of non-string type %v", cat, v.Type())
+	}
+	return nil
+}
+
+// pseudonym returns the pseudonym of s for category cat, derived from key.
+func pseudonym(s string, cat AnonymizeCategory, key []byte) (string, error) {
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%d:%s", cat, s)
+	sum := mac.Sum(nil)
+	hash := hex.EncodeToString(sum[:4])
+
+	switch cat {
+	case AnonymizeIPAddress:
+		return anonymizeIP(s, sum)
+	case AnonymizeHostname:
+		return "host-" + hash, nil
+	case AnonymizeDescription:
+		return "description-" + hash, nil
+	case AnonymizeSecret:
+		return "secret-" + hash, nil
+	default:
+		return "", fmt.Errorf("invalid anonymization category %d", int64(cat))
+	}
+}
+
+// anonymizeIP returns the pseudonym of the IP address or prefix s, with
+// the address derived from sum.
+func anonymizeIP(s string, sum []byte) (string, error) {
+	addrStr, prefixLen, isPrefix := strings.Cut(s, "/")
+	addr, err := netip.ParseAddr(addrStr)
+	if err != nil {
+		return "", fmt.Errorf("%q is not an IP address or prefix", s)
+	}
+
+	var na netip.Addr
+	if addr.Is4() {
+		na = netip.AddrFrom4([4]byte{10, sum[0], sum[1], sum[2]})
+	} else {
+		var b [16]byte
+		b[0] = 0xfd
+		copy(b[1:], sum[:15])
+		na = netip.AddrFrom16(b)
+	}
+
+	if !isPrefix {
+		return na.String(), nil
+	}
+	p, err := netip.ParsePrefix(na.String() + "/" + prefixLen)
+	if err != nil {
+		return "", fmt.Errorf("%q is not an IP address or prefix", s)
+	}
+	return p.String(), nil
+}
+
+// rekeyLists rebuilds the keys of each keyed list within the supplied GoStruct
+// from the values of the key leaves of its entries.
+func rekeyLists(s GoStruct) error {
+	iterFunc := func(ni *util.NodeInfo, in, out interface{}) (util.IterationAction, util.Errors) {
+		if reflect.DeepEqual(ni.StructField, reflect.StructField{}) || ni.FieldKey.IsValid() || util.IsValueNil(ni.FieldValue) {
+			return util.ContinueIteration, nil
+		}
+
+		v := ni.FieldValue
+		if om, ok := v.Interface().(GoOrderedMap); ok {
+			// Appending the entries to a new ordered map computes their
+			// keys, and retains their order.
+			nv := reflect.New(v.Type().Elem())
+			var err error
+			if rerr := yreflect.RangeOrderedMap(om, func(_, e reflect.Value) bool {
+				err = yreflect.AppendIntoOrderedMap(nv.Interface().(GoOrderedMap), e.Interface())
+				return err == nil
+			}); rerr != nil {
+				return util.ContinueIteration, util.NewErrs(rerr)
+			}
+			if err != nil {
+				return util.ContinueIteration, util.NewErrs(err)
+			}
+			v.Set(nv)
+			return util.ContinueIteration, nil
+		}
+		if v.Kind() != reflect.Map || !v.CanSet() {
+			return util.ContinueIteration, nil
+		}
+
+		nv := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k, err := listKey(v.Type().Key(), iter.Value())
+			if err != nil {
+				return util.ContinueIteration, util.NewErrs(err)
+			}
+			if nv.MapIndex(k).IsValid() {
+				return util.ContinueIteration, util.NewErrs(fmt.Errorf("duplicate key %v in list %s after anonymization", k.Interface(), ni.StructField.Name))
+			}
+			nv.SetMapIndex(k, iter.Value())
+		}
+		v.Set(nv)
+		return util.ContinueIteration, nil
+	}
+
+	if errs := util.ForEachDataField2(s, nil, nil, iterFunc); errs != nil {
+		return errs
+	}
+	return nil
+}
+
+// listKey returns the key of type keyT for the list entry e, using the values
+// of the entry's key leaves.
+func listKey(keyT reflect.Type, e reflect.Value) (reflect.Value, error) {
+	kh, ok := e.Interface().(KeyHelperGoStruct)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("list entry of type %v does not implement KeyHelperGoStruct", e.Type())
+	}
+	km, err := kh.ΛListKeyMap()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	toKeyType := func(val interface{}, t reflect.Type) (reflect.Value, error) {
+		rv := reflect.ValueOf(val)
+		switch {
+		case !rv.IsValid():
+			return reflect.Value{}, fmt.Errorf("invalid key value for entry of type %v", e.Type())
+		case rv.Type().AssignableTo(t):
+			return rv, nil
+		case rv.Type().ConvertibleTo(t):
+			return rv.Convert(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("key value of type %v cannot be used as key of type %v", rv.Type(), t)
+	}
+
+	if keyT.Kind() != reflect.Struct {
+		if len(km) != 1 {
+			return reflect.Value{}, fmt.Errorf("got %d keys for entry of type %v, want 1", len(km), e.Type())
+		}
+		for _, val := range km {
+			return toKeyType(val, keyT)
+		}
+	}
+
+	k := reflect.New(keyT).Elem()
+	for i := 0; i < keyT.NumField(); i++ {
+		name, ok := keyT.Field(i).Tag.Lookup("path")
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("field %s of key type %v has no path tag", keyT.Field(i).Name, keyT)
+		}
+		val, ok := km[name]
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("no value for key %s of entry of type %v", name, e.Type())
+		}
+		kv, err := toKeyType(val, keyT.Field(i).Type)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		k.Field(i).Set(kv)
+	}
+	return k, nil
+}
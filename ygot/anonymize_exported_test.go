@@ -0,0 +1,56 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ygot/integration_tests/schemaops/ctestschema"
+	"github.com/openconfig/ygot/internal/ytestutil"
+	"github.com/openconfig/ygot/ygot"
+)
+
+func TestAnonymizeOrderedMap(t *testing.T) {
+	in := &ctestschema.Device{OrderedList: ctestschema.GetOrderedMap(t)}
+	got, err := ygot.Anonymize(in, &ygot.AnonymizeOpts{
+		Paths: map[string]ygot.AnonymizeCategory{
+			"/ordered-lists/ordered-list/config/key":   ygot.AnonymizeHostname,
+			"/ordered-lists/ordered-list/config/value": ygot.AnonymizeDescription,
+		},
+		Key: []byte("test-key"),
+	})
+	if err != nil {
+		t.Fatalf("Anonymize: got unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(&ctestschema.Device{OrderedList: ctestschema.GetOrderedMap(t)}, in, ytestutil.OrderedMapCmpOptions...); diff != "" {
+		t.Errorf("Anonymize: input GoStruct was modified (-want, +got):\n%s", diff)
+	}
+
+	om := got.(*ctestschema.Device).OrderedList
+	if om.Len() != 2 {
+		t.Fatalf("Anonymize: got %d entries, want 2", om.Len())
+	}
+	for i, e := range om.Values() {
+		if !strings.HasPrefix(e.GetKey(), "host-") || !strings.HasPrefix(e.GetValue(), "description-") {
+			t.Errorf("Anonymize: entry %d is not anonymized: %v", i, e)
+		}
+		// The entries must be retrievable by their anonymized keys.
+		if om.Get(e.GetKey()) != e {
+			t.Errorf("Anonymize: entry %d cannot be retrieved by its key %q", i, e.GetKey())
+		}
+	}
+}
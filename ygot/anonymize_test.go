@@ -0,0 +1,268 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygot/testutil"
+)
+
+type anonRoot struct {
+	Hostname   *string                           `path:"system/config/hostname"`
+	DNSServer  []string                          `path:"system/dns/config/server"`
+	Domain     testutil.TestUnion                `path:"system/dns/config/domain"`
+	Peer       map[string]*anonPeer              `path:"peers/peer"`
+	Neighbor   map[anonNeighborKey]*anonNeighbor `path:"neighbors/neighbor"`
+	AnnotatedA []Annotation                      `path:"@a" ygotAnnotation:"true"`
+}
+
+func (*anonRoot) IsYANGGoStruct() {}
+
+type anonPeer struct {
+	Address     *string `path:"config/address|address"`
+	Description *string `path:"config/description"`
+	Port        *uint16 `path:"config/port"`
+}
+
+func (*anonPeer) IsYANGGoStruct() {}
+
+func (p *anonPeer) ΛListKeyMap() (map[string]interface{}, error) {
+	return map[string]interface{}{"address": *p.Address}, nil
+}
+
+type anonNeighborKey struct {
+	Address string `path:"address"`
+	Vrf     string `path:"vrf"`
+}
+
+type anonNeighbor struct {
+	Address *string `path:"address"`
+	Vrf     *string `path:"vrf"`
+}
+
+func (*anonNeighbor) IsYANGGoStruct() {}
+
+func (n *anonNeighbor) ΛListKeyMap() (map[string]interface{}, error) {
+	return map[string]interface{}{"address": *n.Address, "vrf": *n.Vrf}, nil
+}
+
+func newAnonRoot() *anonRoot {
+	return &anonRoot{
+		Hostname:  String("router1.example.com"),
+		DNSServer: []string{"192.0.2.53", "2001:db8::53"},
+		Domain:    testutil.UnionString("example.com"),
+		Peer: map[string]*anonPeer{
+			"192.0.2.1": {
+				Address:     String("192.0.2.1"),
+				Description: String("uplink to customer A"),
+				Port:        Uint16(179),
+			},
+		},
+		Neighbor: map[anonNeighborKey]*anonNeighbor{
+			{Address: "192.0.2.1", Vrf: "customer-a"}: {
+				Address: String("192.0.2.1"),
+				Vrf:     String("customer-a"),
+			},
+		},
+	}
+}
+
+func TestAnonymize(t *testing.T) {
+	key := []byte("test-key")
+	p := func(s string, cat AnonymizeCategory) string {
+		t.Helper()
+		ps, err := pseudonym(s, cat, key)
+		if err != nil {
+			t.Fatalf("cannot create pseudonym for %q: %v", s, err)
+		}
+		return ps
+	}
+
+	tests := []struct {
+		desc             string
+		inPaths          map[string]AnonymizeCategory
+		want             func() *anonRoot
+		wantErrSubstring string
+	}{{
+		desc: "no anonymized paths",
+		want: newAnonRoot,
+	}, {
+		desc: "leaves, leaf-lists, unions and list keys",
+		inPaths: map[string]AnonymizeCategory{
+			"/system/config/hostname":        AnonymizeHostname,
+			"/system/dns/config/server":      AnonymizeIPAddress,
+			"/system/dns/config/domain":      AnonymizeHostname,
+			"/peers/peer/address":            AnonymizeIPAddress,
+			"/peers/peer/config/description": AnonymizeDescription,
+			"/neighbors/neighbor/address":    AnonymizeIPAddress,
+		},
+		want: func() *anonRoot {
+			addr := p("192.0.2.1", AnonymizeIPAddress)
+			return &anonRoot{
+				Hostname:  String(p("router1.example.com", AnonymizeHostname)),
+				DNSServer: []string{p("192.0.2.53", AnonymizeIPAddress), p("2001:db8::53", AnonymizeIPAddress)},
+				Domain:    testutil.UnionString(p("example.com", AnonymizeHostname)),
+				Peer: map[string]*anonPeer{
+					addr: {
+						Address:     String(addr),
+						Description: String(p("uplink to customer A", AnonymizeDescription)),
+						Port:        Uint16(179),
+					},
+				},
+				Neighbor: map[anonNeighborKey]*anonNeighbor{
+					{Address: addr, Vrf: "customer-a"}: {
+						Address: String(addr),
+						Vrf:     String("customer-a"),
+					},
+				},
+			}
+		},
+	}, {
+		desc: "non-string leaf",
+		inPaths: map[string]AnonymizeCategory{
+			"/peers/peer/config/port": AnonymizeSecret,
+		},
+		wantErrSubstring: "non-string type",
+	}, {
+		desc: "invalid IP address",
+		inPaths: map[string]AnonymizeCategory{
+			"/system/config/hostname": AnonymizeIPAddress,
+		},
+		wantErrSubstring: "is not an IP address or prefix",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			in := newAnonRoot()
+			got, err := Anonymize(in, &AnonymizeOpts{Paths: tt.inPaths, Key: key})
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("Anonymize: %s", diff)
+			}
+			if diff := cmp.Diff(newAnonRoot(), in); diff != "" {
+				t.Errorf("Anonymize: input GoStruct was modified (-want, +got):\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want(), got); diff != "" {
+				t.Errorf("Anonymize: did not get expected GoStruct (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAnonymizeDuplicateKeys(t *testing.T) {
+	in := &anonRoot{
+		Peer: map[string]*anonPeer{
+			"a": {Address: String("a")},
+			"b": {Address: String("b")},
+		},
+	}
+	// The key leaves of both entries have the same value, and hence the same
+	// pseudonym, as would be the case for a collision of pseudonyms.
+	in.Peer["b"].Address = String("a")
+	if _, err := Anonymize(in, &AnonymizeOpts{Paths: map[string]AnonymizeCategory{"/peers/peer/address": AnonymizeHostname}}); err == nil || !strings.Contains(err.Error(), "duplicate key") {
+		t.Errorf("Anonymize: got error %v, want duplicate key error", err)
+	}
+}
+
+func TestPseudonym(t *testing.T) {
+	key := []byte("test-key")
+	tests := []struct {
+		desc             string
+		in               string
+		inCategory       AnonymizeCategory
+		wantPrefix       string
+		wantErrSubstring string
+	}{{
+		desc:       "hostname",
+		in:         "router1",
+		inCategory: AnonymizeHostname,
+		wantPrefix: "host-",
+	}, {
+		desc:       "description",
+		in:         "uplink",
+		inCategory: AnonymizeDescription,
+		wantPrefix: "description-",
+	}, {
+		desc:       "secret",
+		in:         "hunter2",
+		inCategory: AnonymizeSecret,
+		wantPrefix: "secret-",
+	}, {
+		desc:       "IPv4 address",
+		in:         "192.0.2.1",
+		inCategory: AnonymizeIPAddress,
+		wantPrefix: "10.",
+	}, {
+		desc:       "IPv6 address",
+		in:         "2001:db8::1",
+		inCategory: AnonymizeIPAddress,
+		wantPrefix: "fd",
+	}, {
+		desc:             "invalid prefix length",
+		in:               "192.0.2.0/33",
+		inCategory:       AnonymizeIPAddress,
+		wantErrSubstring: "is not an IP address or prefix",
+	}, {
+		desc:             "invalid category",
+		in:               "foo",
+		inCategory:       AnonymizeCategory(42),
+		wantErrSubstring: "invalid anonymization category",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := pseudonym(tt.in, tt.inCategory, key)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("pseudonym: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if !strings.HasPrefix(got, tt.wantPrefix) {
+				t.Errorf("pseudonym: got %q, want prefix %q", got, tt.wantPrefix)
+			}
+			again, err := pseudonym(tt.in, tt.inCategory, key)
+			if err != nil || again != got {
+				t.Errorf("pseudonym: got %q, %v on second call, want %q", again, err, got)
+			}
+			if other, _ := pseudonym(tt.in, tt.inCategory, []byte("other-key")); other == got {
+				t.Errorf("pseudonym: got same pseudonym %q for different keys", got)
+			}
+		})
+	}
+}
+
+func TestPseudonymPrefix(t *testing.T) {
+	for _, in := range []string{"192.0.2.0/24", "2001:db8::/32"} {
+		got, err := pseudonym(in, AnonymizeIPAddress, nil)
+		if err != nil {
+			t.Fatalf("pseudonym(%q): got unexpected error: %v", in, err)
+		}
+		gotPrefix, err := netip.ParsePrefix(got)
+		if err != nil {
+			t.Fatalf("pseudonym(%q): got invalid prefix %q: %v", in, got, err)
+		}
+		if want := netip.MustParsePrefix(in).Bits(); gotPrefix.Bits() != want {
+			t.Errorf("pseudonym(%q): got prefix length %d, want %d", in, gotPrefix.Bits(), want)
+		}
+	}
+}
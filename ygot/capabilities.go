@@ -0,0 +1,230 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/openconfig/ygot/internal/yreflect"
+	"github.com/openconfig/ygot/util"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Capabilities describes the options with which a set of GoStructs was
+// generated. It allows libraries that handle GoStructs from arbitrary
+// generated packages to determine which features the generated code supports.
+type Capabilities struct {
+	// CompressBehaviour is the name of the compression behaviour with
+	// which the GoStructs were generated, e.g., "PreferIntendedConfig" or
+	// "Uncompressed".
+	CompressBehaviour string
+	// Compressed indicates that the schema paths of the GoStructs are
+	// compressed.
+	Compressed bool
+	// SimpleUnions indicates that union leaves are represented using
+	// simple typedefs of their subtypes rather than wrapper structs.
+	SimpleUnions bool
+	// OrderedMaps indicates that lists that are ordered-by user are
+	// represented using ordered map types.
+	OrderedMaps bool
+	// SchemaEmbedded indicates that the schema is embedded in the generated
+	// code, such that the GoStructs implement ValidatedGoStruct.
+	SchemaEmbedded bool
+	// Getters indicates that GetOrCreate methods are generated for
+	// container and list fields.
+	Getters bool
+	// WildcardPaths indicates that path structs, including methods for
+	// constructing wildcard paths, were generated together with the
+	// GoStructs.
+	WildcardPaths bool
+	// GeneratorVersion is the version of ygot that generated the code.
+	GeneratorVersion string
+}
+
+// CapabilitiesProvider is an interface implemented by GoStructs that were
+// generated with a descriptor of the options with which they were generated.
+type CapabilitiesProvider interface {
+	// ΛCapabilities returns the options with which the GoStruct was
+	// generated.
+	ΛCapabilities() *Capabilities
+}
+
+// StructCapabilities returns the options with which the GoStruct s was
+// generated. The returned bool is false if s does not describe its
+// capabilities, e.g., because it was generated without the descriptor.
+func StructCapabilities(s GoStruct) (*Capabilities, bool) {
+	cp, ok := s.(CapabilitiesProvider)
+	if !ok {
+		return nil, false
+	}
+	return cp.ΛCapabilities(), true
+}
+
+// ModuleCapability describes a YANG module that is supported by a target,
+// e.g., as advertised in the supported_models field of a gNMI
+// CapabilityResponse.
+type ModuleCapability struct {
+	// Name is the name of the module.
+	Name string
+	// Revision is the revision of the module that is supported by the
+	// target. If it is empty, any revision of the module is supported.
+	Revision string
+}
+
+// ModuleCapabilitiesFromModelData returns the ModuleCapabilities that
+// correspond to the supplied gNMI ModelData messages, as returned in the
+// supported_models field of a gNMI CapabilityResponse.
+func ModuleCapabilitiesFromModelData(models []*gnmipb.ModelData) []ModuleCapability {
+	var caps []ModuleCapability
+	for _, m := range models {
+		caps = append(caps, ModuleCapability{Name: m.GetName(), Revision: m.GetVersion()})
+	}
+	return caps
+}
+
+// PruneToCapabilitiesOpt is an interface implemented by the options of
+// PruneToCapabilities.
+type PruneToCapabilitiesOpt interface {
+	// IsPruneToCapabilitiesOpt is a marker method.
+	IsPruneToCapabilitiesOpt()
+}
+
+// PruneGeneratedModels is a PruneToCapabilitiesOpt that specifies the modules
+// from which the GoStruct was generated, e.g., the ΓModelData variable of the
+// generated code. When it is supplied, a module is only retained if its
+// revision matches the revision supported by the target.
+type PruneGeneratedModels struct {
+	// Models are the modules from which the GoStruct was generated.
+	Models []*gnmipb.ModelData
+}
+
+// IsPruneToCapabilitiesOpt marks PruneGeneratedModels as a
+// PruneToCapabilitiesOpt.
+func (*PruneGeneratedModels) IsPruneToCapabilitiesOpt() {}
+
+// PruneToCapabilities removes the subtrees of the GoStruct s that belong to a
+// module that is not in the supported set, such that a single GoStruct can be
+// sent to targets that support different sets of modules, e.g., where only
+// some targets support the module that augments a container.
+//
+// The module to which a field belongs is the module of the last element of
+// its "module" struct tag. If the field has no module tag, and is a GoStruct,
+// then the module returned by its ΛBelongingModule method is used. Fields
+// whose module cannot be determined are retained.
+func PruneToCapabilities(s GoStruct, supported []ModuleCapability, opts ...PruneToCapabilitiesOpt) error {
+	if util.IsValueNil(s) {
+		return fmt.Errorf("invalid input to PruneToCapabilities, got nil value: %v", s)
+	}
+
+	generated := map[string]string{}
+	for _, o := range opts {
+		if gm, ok := o.(*PruneGeneratedModels); ok {
+			for _, m := range gm.Models {
+				generated[m.GetName()] = m.GetVersion()
+			}
+		}
+	}
+	mods := map[string]bool{}
+	for _, c := range supported {
+		if rev, ok := generated[c.Name]; ok && c.Revision != "" && rev != "" && rev != c.Revision {
+			continue
+		}
+		mods[c.Name] = true
+	}
+
+	v := reflect.ValueOf(s)
+	if !util.IsValueStructPtr(v) {
+		return fmt.Errorf("invalid input to PruneToCapabilities, got %T, want struct pointer", s)
+	}
+	return pruneToModules(v.Elem(), mods)
+}
+
+// fieldModule returns the module to which the struct field f, with the value
+// v, belongs, or the empty string if it cannot be determined.
+func fieldModule(f reflect.StructField, v reflect.Value) string {
+	if tag, ok := f.Tag.Lookup("module"); ok {
+		// The module of each path that the field is mapped to is the
+		// same, hence the first is used.
+		ms := strings.Split(strings.Split(tag, "|")[0], "/")
+		if m := ms[len(ms)-1]; m != "" {
+			return m
+		}
+	}
+	if m, ok := v.Interface().(belongingModuler); ok && !util.IsValueNil(v.Interface()) {
+		return m.ΛBelongingModule()
+	}
+	return ""
+}
+
+// pruneToModules removes the fields of the struct v that belong to a module
+// that is not in mods, and recurses into the remaining fields.
+func pruneToModules(v reflect.Value, mods map[string]bool) error {
+	for i := 0; i < v.NumField(); i++ {
+		fv, ft := v.Field(i), v.Type().Field(i)
+		if util.IsYgotAnnotation(ft) || fv.IsZero() {
+			continue
+		}
+		if m := fieldModule(ft, fv); m != "" && !mods[m] {
+			fv.Set(reflect.Zero(ft.Type))
+			continue
+		}
+
+		if om, ok := fv.Interface().(GoOrderedMap); ok {
+			var err error
+			if rerr := yreflect.RangeOrderedMap(om, func(_, e reflect.Value) bool {
+				err = pruneEntryToModules(e, mods)
+				return err == nil
+			}); rerr != nil {
+				return rerr
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		switch {
+		case util.IsValueStructPtr(fv):
+			if err := pruneToModules(fv.Elem(), mods); err != nil {
+				return err
+			}
+		case util.IsValueMap(fv):
+			for _, k := range fv.MapKeys() {
+				if err := pruneEntryToModules(fv.MapIndex(k), mods); err != nil {
+					return err
+				}
+			}
+		case util.IsValueSlice(fv):
+			for j := 0; j < fv.Len(); j++ {
+				if err := pruneEntryToModules(fv.Index(j), mods); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// pruneEntryToModules removes the fields of the list entry v that belong to a
+// module that is not in mods. v is ignored if it is not a struct pointer,
+// e.g., when it is an element of a leaf-list.
+func pruneEntryToModules(v reflect.Value, mods map[string]bool) error {
+	if !util.IsValueStructPtr(v) || v.IsNil() {
+		return nil
+	}
+	return pruneToModules(v.Elem(), mods)
+}
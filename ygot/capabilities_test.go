@@ -0,0 +1,221 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+var testCapabilities = &Capabilities{
+	CompressBehaviour: "PreferIntendedConfig",
+	Compressed:        true,
+	GeneratorVersion:  "v0.0.0-test",
+}
+
+type capabilitiesStruct struct {
+	Leaf *string `path:"leaf"`
+}
+
+func (*capabilitiesStruct) IsYANGGoStruct()              {}
+func (*capabilitiesStruct) ΛCapabilities() *Capabilities { return testCapabilities }
+
+type noCapabilitiesStruct struct {
+	Leaf *string `path:"leaf"`
+}
+
+func (*noCapabilitiesStruct) IsYANGGoStruct() {}
+
+func TestStructCapabilities(t *testing.T) {
+	tests := []struct {
+		desc   string
+		in     GoStruct
+		want   *Capabilities
+		wantOK bool
+	}{{
+		desc:   "struct with capabilities",
+		in:     &capabilitiesStruct{},
+		want:   testCapabilities,
+		wantOK: true,
+	}, {
+		desc: "struct without capabilities",
+		in:   &noCapabilitiesStruct{},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, ok := StructCapabilities(tt.in)
+			if ok != tt.wantOK {
+				t.Errorf("StructCapabilities: got ok %v, want %v", ok, tt.wantOK)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("StructCapabilities: (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type pruneCapRoot struct {
+	System     *pruneCapSystem           `path:"system" module:"sys"`
+	Interfaces map[string]*pruneCapIface `path:"interfaces/interface" module:"if/if"`
+}
+
+func (*pruneCapRoot) IsYANGGoStruct() {}
+
+type pruneCapSystem struct {
+	Hostname *string      `path:"config/hostname" module:"sys/sys"`
+	Location *string      `path:"config/location" module:"sys/sys-aug"`
+	Ntp      *pruneCapNtp `path:"ntp" module:"sys-ntp"`
+}
+
+func (*pruneCapSystem) IsYANGGoStruct() {}
+
+type pruneCapNtp struct {
+	Enabled *bool `path:"config/enabled" module:"sys-ntp/sys-ntp"`
+}
+
+func (*pruneCapNtp) IsYANGGoStruct() {}
+
+type pruneCapIface struct {
+	Name *string `path:"config/name|name" module:"if/if|if"`
+	Vlan *uint16 `path:"config/vlan" module:"if/if-vlan"`
+}
+
+func (*pruneCapIface) IsYANGGoStruct() {}
+
+func TestPruneToCapabilities(t *testing.T) {
+	newRoot := func() *pruneCapRoot {
+		return &pruneCapRoot{
+			System: &pruneCapSystem{
+				Hostname: String("rtr1"),
+				Location: String("lab"),
+				Ntp:      &pruneCapNtp{Enabled: Bool(true)},
+			},
+			Interfaces: map[string]*pruneCapIface{
+				"eth0": {Name: String("eth0"), Vlan: Uint16(10)},
+				"eth1": {Name: String("eth1")},
+			},
+		}
+	}
+	allModules := []ModuleCapability{{Name: "sys"}, {Name: "sys-aug"}, {Name: "sys-ntp"}, {Name: "if"}, {Name: "if-vlan"}}
+	generated := &PruneGeneratedModels{Models: []*gnmipb.ModelData{{Name: "sys-ntp", Version: "2.0.0"}}}
+
+	tests := []struct {
+		desc             string
+		in               GoStruct
+		inSupported      []ModuleCapability
+		inOpts           []PruneToCapabilitiesOpt
+		want             GoStruct
+		wantErrSubstring string
+	}{{
+		desc:        "all modules supported",
+		in:          newRoot(),
+		inSupported: allModules,
+		want:        newRoot(),
+	}, {
+		desc:        "augmenting leaves not supported",
+		in:          newRoot(),
+		inSupported: []ModuleCapability{{Name: "sys"}, {Name: "sys-ntp"}, {Name: "if"}},
+		want: func() *pruneCapRoot {
+			r := newRoot()
+			r.System.Location = nil
+			r.Interfaces["eth0"].Vlan = nil
+			return r
+		}(),
+	}, {
+		desc:        "container module not supported",
+		in:          newRoot(),
+		inSupported: []ModuleCapability{{Name: "sys"}, {Name: "sys-aug"}, {Name: "if"}, {Name: "if-vlan"}},
+		want: func() *pruneCapRoot {
+			r := newRoot()
+			r.System.Ntp = nil
+			return r
+		}(),
+	}, {
+		desc:        "subtrees of unsupported module removed",
+		in:          newRoot(),
+		inSupported: []ModuleCapability{{Name: "if"}},
+		want: &pruneCapRoot{
+			Interfaces: map[string]*pruneCapIface{
+				"eth0": {Name: String("eth0")},
+				"eth1": {Name: String("eth1")},
+			},
+		},
+	}, {
+		desc:        "revision mismatch with generated models",
+		in:          newRoot(),
+		inSupported: append([]ModuleCapability{{Name: "sys-ntp", Revision: "1.0.0"}}, allModules[:2]...),
+		inOpts:      []PruneToCapabilitiesOpt{generated},
+		want: func() *pruneCapRoot {
+			r := newRoot()
+			r.System.Ntp = nil
+			r.Interfaces = nil
+			return r
+		}(),
+	}, {
+		desc:        "revision match with generated models",
+		in:          newRoot(),
+		inSupported: append([]ModuleCapability{{Name: "sys-ntp", Revision: "2.0.0"}}, allModules[:2]...),
+		inOpts:      []PruneToCapabilitiesOpt{generated},
+		want: func() *pruneCapRoot {
+			r := newRoot()
+			r.Interfaces = nil
+			return r
+		}(),
+	}, {
+		desc:        "revision ignored without generated models",
+		in:          newRoot(),
+		inSupported: append([]ModuleCapability{{Name: "sys-ntp", Revision: "1.0.0"}}, allModules[:2]...),
+		want: func() *pruneCapRoot {
+			r := newRoot()
+			r.Interfaces = nil
+			return r
+		}(),
+	}, {
+		desc:             "nil GoStruct",
+		in:               (*pruneCapRoot)(nil),
+		wantErrSubstring: "got nil value",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := PruneToCapabilities(tt.in, tt.inSupported, tt.inOpts...)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("PruneToCapabilities: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, tt.in); diff != "" {
+				t.Errorf("PruneToCapabilities: (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestModuleCapabilitiesFromModelData(t *testing.T) {
+	got := ModuleCapabilitiesFromModelData([]*gnmipb.ModelData{
+		{Name: "openconfig-interfaces", Organization: "OpenConfig working group", Version: "3.0.0"},
+		{Name: "openconfig-system"},
+	})
+	want := []ModuleCapability{{Name: "openconfig-interfaces", Revision: "3.0.0"}, {Name: "openconfig-system"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ModuleCapabilitiesFromModelData: (-want, +got):\n%s", diff)
+	}
+}
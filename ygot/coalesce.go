@@ -0,0 +1,250 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// CoalesceOpt is an interface that is implemented by the options that can be
+// supplied to CoalesceNotifications.
+type CoalesceOpt interface {
+	IsCoalesceOpt()
+}
+
+// CoalesceLongestPrefix is a CoalesceOpt that specifies that the prefix of
+// each non-atomic Notification returned by CoalesceNotifications is the
+// longest path that is common to all of its updates and deletes, such that
+// the paths within it are as short as possible. Each update and delete retains
+// at least the last element of its path.
+type CoalesceLongestPrefix struct{}
+
+// IsCoalesceOpt implements the CoalesceOpt interface.
+func (*CoalesceLongestPrefix) IsCoalesceOpt() {}
+
+// hasCoalesceLongestPrefix determines whether there is an instance of
+// CoalesceLongestPrefix within the supplied CoalesceOpt slice.
+func hasCoalesceLongestPrefix(opts []CoalesceOpt) bool {
+	for _, o := range opts {
+		if _, ok := o.(*CoalesceLongestPrefix); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CoalesceNotifications returns the smallest set of Notifications that has
+// the same effect as the supplied Notifications, e.g., those captured from
+// multiple subscriptions, when they are applied in order of their timestamps.
+// Notifications with the same timestamp are applied in the order in which
+// they are supplied, and, within a Notification, deletes are applied before
+// updates, per the gNMI specification. Specifically:
+//   - An update is removed if the same path is updated later, or if the path,
+//     or one of its ancestors, is deleted later.
+//   - A delete is removed if the same path, or one of its ancestors, is
+//     deleted later, or if the same path is updated later with a scalar
+//     value, since that value replaces the deleted leaf.
+//   - Non-atomic Notifications that have the same timestamp and prefix are
+//     merged, and those left with neither updates nor deletes are removed.
+//
+// Atomic Notifications are returned unchanged, and their updates neither
+// supersede nor are superseded by those of other Notifications, since they
+// must be applied in their entirety. The returned Notifications are sorted by
+// timestamp, and the supplied Notifications are not modified.
+func CoalesceNotifications(notifs []*gnmipb.Notification, opts ...CoalesceOpt) ([]*gnmipb.Notification, error) {
+	ordered := append([]*gnmipb.Notification{}, notifs...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].GetTimestamp() < ordered[j].GetTimestamp()
+	})
+
+	// Walk the Notifications from the last to be applied to the first,
+	// recording the paths that are updated and deleted later, such that
+	// superseded updates and deletes can be identified.
+	updatedLater := map[string]bool{}
+	deletedLater := map[string]bool{}
+	keptUpdates := make([][]bool, len(ordered))
+	keptDeletes := make([][]bool, len(ordered))
+	for i := len(ordered) - 1; i >= 0; i-- {
+		n := ordered[i]
+		if n.GetAtomic() {
+			continue
+		}
+		keptUpdates[i] = make([]bool, len(n.GetUpdate()))
+		for j := len(n.GetUpdate()) - 1; j >= 0; j-- {
+			u := n.GetUpdate()[j]
+			keys, err := coalesceKeys(n.GetPrefix(), u.GetPath())
+			if err != nil {
+				return nil, err
+			}
+			leaf := keys[len(keys)-1]
+			keptUpdates[i][j] = !updatedLater[leaf] && !anyKey(deletedLater, keys)
+			if _, ok := updatedLater[leaf]; !ok {
+				updatedLater[leaf] = !isJSONValue(u.GetVal())
+			}
+		}
+		keptDeletes[i] = make([]bool, len(n.GetDelete()))
+		for j := len(n.GetDelete()) - 1; j >= 0; j-- {
+			keys, err := coalesceKeys(n.GetPrefix(), n.GetDelete()[j])
+			if err != nil {
+				return nil, err
+			}
+			node := keys[len(keys)-1]
+			keptDeletes[i][j] = !updatedLater[node] && !anyKey(deletedLater, keys)
+			deletedLater[node] = true
+		}
+	}
+
+	// Merge the remaining updates and deletes of the Notifications with
+	// the same timestamp and prefix, in the order in which they are applied.
+	var out []*gnmipb.Notification
+	merged := map[string]*gnmipb.Notification{}
+	for i, n := range ordered {
+		if n.GetAtomic() {
+			out = append(out, proto.Clone(n).(*gnmipb.Notification))
+			continue
+		}
+		key, err := coalesceKey(n)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := merged[key]
+		if !ok {
+			m = &gnmipb.Notification{
+				Timestamp: n.GetTimestamp(),
+				Prefix:    proto.Clone(n.GetPrefix()).(*gnmipb.Path),
+			}
+			if n.GetPrefix() == nil {
+				m.Prefix = nil
+			}
+			merged[key] = m
+			out = append(out, m)
+		}
+		for j, d := range n.GetDelete() {
+			if keptDeletes[i][j] {
+				m.Delete = append(m.Delete, proto.Clone(d).(*gnmipb.Path))
+			}
+		}
+		for j, u := range n.GetUpdate() {
+			if keptUpdates[i][j] {
+				m.Update = append(m.Update, proto.Clone(u).(*gnmipb.Update))
+			}
+		}
+	}
+
+	var coalesced []*gnmipb.Notification
+	for _, n := range out {
+		if !n.GetAtomic() && len(n.Update) == 0 && len(n.Delete) == 0 {
+			continue
+		}
+		if !n.GetAtomic() && hasCoalesceLongestPrefix(opts) {
+			longestPrefix(n)
+		}
+		coalesced = append(coalesced, n)
+	}
+	return coalesced, nil
+}
+
+// coalesceKey returns the key identifying the Notifications that are merged
+// with n by CoalesceNotifications.
+func coalesceKey(n *gnmipb.Notification) (string, error) {
+	p, err := PathToString(&gnmipb.Path{Elem: n.GetPrefix().GetElem()})
+	if err != nil {
+		return "", fmt.Errorf("invalid prefix %v: %v", n.GetPrefix(), err)
+	}
+	return fmt.Sprintf("%d|%s|%s|%s", n.GetTimestamp(), n.GetPrefix().GetOrigin(), n.GetPrefix().GetTarget(), p), nil
+}
+
+// coalesceKeys returns the keys identifying the path, which is relative to
+// prefix, and each of its ancestors, from the root to the path itself. The
+// keys are qualified by the origin and target of the path.
+func coalesceKeys(prefix, path *gnmipb.Path) ([]string, error) {
+	origin := path.GetOrigin()
+	if origin == "" {
+		origin = prefix.GetOrigin()
+	}
+	elems := append(append([]*gnmipb.PathElem{}, prefix.GetElem()...), path.GetElem()...)
+	keys := make([]string, 0, len(elems)+1)
+	for i := 0; i <= len(elems); i++ {
+		p, err := PathToString(&gnmipb.Path{Elem: elems[:i]})
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %v with prefix %v: %v", path, prefix, err)
+		}
+		keys = append(keys, fmt.Sprintf("%s|%s|%s", origin, prefix.GetTarget(), p))
+	}
+	return keys, nil
+}
+
+// anyKey reports whether any of the supplied keys is within m.
+func anyKey(m map[string]bool, keys []string) bool {
+	for _, k := range keys {
+		if m[k] {
+			return true
+		}
+	}
+	return false
+}
+
+// isJSONValue reports whether the value of an update is JSON-encoded, and
+// hence may describe a subtree that is merged with the existing data rather
+// than replacing it.
+func isJSONValue(tv *gnmipb.TypedValue) bool {
+	switch tv.GetValue().(type) {
+	case *gnmipb.TypedValue_JsonVal, *gnmipb.TypedValue_JsonIetfVal:
+		return true
+	}
+	return false
+}
+
+// longestPrefix moves the elements that are common to the paths of all of
+// the updates and deletes of the Notification n, other than the last element
+// of each path, to its prefix.
+func longestPrefix(n *gnmipb.Notification) {
+	paths := append([]*gnmipb.Path{}, n.Delete...)
+	for _, u := range n.Update {
+		if u.Path == nil {
+			u.Path = &gnmipb.Path{}
+		}
+		paths = append(paths, u.Path)
+	}
+
+	common := len(paths[0].GetElem()) - 1
+	for _, p := range paths {
+		common = min(common, len(p.GetElem())-1)
+	}
+	for i := 0; i < common; i++ {
+		for _, p := range paths[1:] {
+			if !proto.Equal(p.Elem[i], paths[0].Elem[i]) {
+				common = i
+				break
+			}
+		}
+	}
+	if common <= 0 {
+		return
+	}
+
+	if n.Prefix == nil {
+		n.Prefix = &gnmipb.Path{}
+	}
+	n.Prefix.Elem = append(n.Prefix.Elem, paths[0].Elem[:common]...)
+	for _, p := range paths {
+		p.Elem = p.Elem[common:]
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestCoalesceNotifications(t *testing.T) {
+	path := func(s string) *gnmipb.Path {
+		return &gnmipb.Path{Elem: mustPathElem(s)}
+	}
+	upd := func(s string, v string) *gnmipb.Update {
+		return &gnmipb.Update{Path: path(s), Val: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: v}}}
+	}
+	jsonUpd := func(s string, v string) *gnmipb.Update {
+		return &gnmipb.Update{Path: path(s), Val: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(v)}}}
+	}
+
+	tests := []struct {
+		desc             string
+		in               []*gnmipb.Notification
+		inOpts           []CoalesceOpt
+		want             []*gnmipb.Notification
+		wantErrSubstring string
+	}{{
+		desc: "merge notifications with the same timestamp and prefix",
+		in: []*gnmipb.Notification{{
+			Timestamp: 42,
+			Prefix:    path("/interfaces/interface[name=eth0]"),
+			Update:    []*gnmipb.Update{upd("config/description", "uplink")},
+		}, {
+			Timestamp: 42,
+			Prefix:    path("/interfaces/interface[name=eth0]"),
+			Delete:    []*gnmipb.Path{path("config/mtu")},
+			Update:    []*gnmipb.Update{upd("config/name", "eth0")},
+		}, {
+			Timestamp: 42,
+			Prefix:    &gnmipb.Path{Target: "dut", Elem: mustPathElem("/interfaces/interface[name=eth0]")},
+			Update:    []*gnmipb.Update{upd("config/name", "eth0")},
+		}},
+		want: []*gnmipb.Notification{{
+			Timestamp: 42,
+			Prefix:    path("/interfaces/interface[name=eth0]"),
+			Delete:    []*gnmipb.Path{path("config/mtu")},
+			Update:    []*gnmipb.Update{upd("config/description", "uplink"), upd("config/name", "eth0")},
+		}, {
+			Timestamp: 42,
+			Prefix:    &gnmipb.Path{Target: "dut", Elem: mustPathElem("/interfaces/interface[name=eth0]")},
+			Update:    []*gnmipb.Update{upd("config/name", "eth0")},
+		}},
+	}, {
+		desc: "later updates and deletes supersede earlier ones",
+		in: []*gnmipb.Notification{{
+			Timestamp: 30,
+			Update:    []*gnmipb.Update{upd("/system/config/hostname", "rtr3")},
+		}, {
+			Timestamp: 10,
+			Update: []*gnmipb.Update{
+				upd("/system/config/hostname", "rtr1"),
+				upd("/system/config/domain-name", "example.com"),
+				upd("/interfaces/interface[name=eth0]/config/description", "uplink"),
+			},
+		}, {
+			Timestamp: 20,
+			Prefix:    path("/interfaces"),
+			Delete:    []*gnmipb.Path{path("interface[name=eth0]")},
+			Update:    []*gnmipb.Update{upd("interface[name=eth0]/config/name", "eth0")},
+		}, {
+			Timestamp: 20,
+			Delete:    []*gnmipb.Path{path("/system/config/domain-name"), path("/system/config/login-banner")},
+		}, {
+			Timestamp: 30,
+			Update:    []*gnmipb.Update{upd("/system/config/login-banner", "hello")},
+		}},
+		want: []*gnmipb.Notification{{
+			Timestamp: 20,
+			Prefix:    path("/interfaces"),
+			Delete:    []*gnmipb.Path{path("interface[name=eth0]")},
+			Update:    []*gnmipb.Update{upd("interface[name=eth0]/config/name", "eth0")},
+		}, {
+			Timestamp: 20,
+			Delete:    []*gnmipb.Path{path("/system/config/domain-name")},
+		}, {
+			Timestamp: 30,
+			Update: []*gnmipb.Update{
+				upd("/system/config/hostname", "rtr3"),
+				upd("/system/config/login-banner", "hello"),
+			},
+		}},
+	}, {
+		desc: "delete is not superseded by later JSON update",
+		in: []*gnmipb.Notification{{
+			Timestamp: 10,
+			Delete:    []*gnmipb.Path{path("/system/config")},
+		}, {
+			Timestamp: 20,
+			Update:    []*gnmipb.Update{jsonUpd("/system/config", `{"hostname": "rtr1"}`)},
+		}},
+		want: []*gnmipb.Notification{{
+			Timestamp: 10,
+			Delete:    []*gnmipb.Path{path("/system/config")},
+		}, {
+			Timestamp: 20,
+			Update:    []*gnmipb.Update{jsonUpd("/system/config", `{"hostname": "rtr1"}`)},
+		}},
+	}, {
+		desc: "atomic notifications are unchanged",
+		in: []*gnmipb.Notification{{
+			Timestamp: 10,
+			Prefix:    path("/system/config"),
+			Atomic:    true,
+			Update:    []*gnmipb.Update{upd("hostname", "rtr1")},
+		}, {
+			Timestamp: 10,
+			Prefix:    path("/system/config"),
+			Update:    []*gnmipb.Update{upd("hostname", "rtr1")},
+		}, {
+			Timestamp: 20,
+			Delete:    []*gnmipb.Path{path("/system")},
+		}},
+		want: []*gnmipb.Notification{{
+			Timestamp: 10,
+			Prefix:    path("/system/config"),
+			Atomic:    true,
+			Update:    []*gnmipb.Update{upd("hostname", "rtr1")},
+		}, {
+			Timestamp: 20,
+			Delete:    []*gnmipb.Path{path("/system")},
+		}},
+	}, {
+		desc: "longest common prefix",
+		in: []*gnmipb.Notification{{
+			Timestamp: 10,
+			Prefix:    path("/interfaces"),
+			Delete:    []*gnmipb.Path{path("interface[name=eth0]/config/mtu")},
+			Update: []*gnmipb.Update{
+				upd("interface[name=eth0]/config/name", "eth0"),
+				upd("interface[name=eth0]/config/description", "uplink"),
+			},
+		}, {
+			Timestamp: 20,
+			Update: []*gnmipb.Update{
+				upd("/interfaces/interface[name=eth0]/config/name", "eth0"),
+				upd("/system/config/hostname", "rtr1"),
+			},
+		}, {
+			Timestamp: 30,
+			Update:    []*gnmipb.Update{upd("/system/config/hostname", "rtr2")},
+		}},
+		inOpts: []CoalesceOpt{&CoalesceLongestPrefix{}},
+		want: []*gnmipb.Notification{{
+			Timestamp: 10,
+			Prefix:    path("/interfaces/interface[name=eth0]/config"),
+			Delete:    []*gnmipb.Path{path("mtu")},
+			Update:    []*gnmipb.Update{upd("description", "uplink")},
+		}, {
+			Timestamp: 20,
+			Prefix:    path("/interfaces/interface[name=eth0]/config"),
+			Update:    []*gnmipb.Update{upd("name", "eth0")},
+		}, {
+			Timestamp: 30,
+			Prefix:    path("/system/config"),
+			Update:    []*gnmipb.Update{upd("hostname", "rtr2")},
+		}},
+	}, {
+		desc: "invalid path",
+		in: []*gnmipb.Notification{{
+			Timestamp: 10,
+			Update:    []*gnmipb.Update{{Path: &gnmipb.Path{Elem: []*gnmipb.PathElem{{}}}}},
+		}},
+		wantErrSubstring: "invalid path",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var in []*gnmipb.Notification
+			for _, n := range tt.in {
+				in = append(in, proto.Clone(n).(*gnmipb.Notification))
+			}
+			got, err := CoalesceNotifications(in, tt.inOpts...)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("CoalesceNotifications: did not get expected error, %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("CoalesceNotifications (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.in, in, protocmp.Transform()); diff != "" {
+				t.Errorf("CoalesceNotifications modified input (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
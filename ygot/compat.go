@@ -0,0 +1,259 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/ygot/util"
+)
+
+// generatedMethod describes a method that the ygot generator adds to
+// GoStructs, and which the helper functions of ygot and ytypes use.
+type generatedMethod struct {
+	// name is the name of the method.
+	name string
+	// iface is an interface type that consists of the method.
+	iface reflect.Type
+	// provides describes what the method provides to the helper functions.
+	provides string
+	// fallback describes the behaviour of the helper functions when the
+	// method is not implemented. It is empty if the helper functions
+	// return an error when the method is not implemented.
+	fallback string
+	// listMember indicates that the method is only generated for
+	// GoStructs that are members of keyed lists.
+	listMember bool
+}
+
+// generatedMethods are the generated methods of GoStructs used by helper
+// functions. Methods that were added by more recent versions of the generator
+// are handled gracefully by the helper functions when they are not
+// implemented, as described by their fallback.
+var generatedMethods = []*generatedMethod{{
+	name:     "ΛValidate",
+	iface:    reflect.TypeOf((*validatedGoStruct)(nil)).Elem(),
+	provides: "validation of the GoStruct against its schema by ValidateGoStruct",
+	fallback: "ValidateGoStruct uses the Validate method, if it is implemented",
+}, {
+	name:     "ΛEnumTypeMap",
+	iface:    reflect.TypeOf((*enumTypeMapper)(nil)).Elem(),
+	provides: "the enumerated types of the GoStruct's leaves, used when unmarshalling enumerated and union leaves",
+}, {
+	name:       "ΛListKeyMap",
+	iface:      reflect.TypeOf((*KeyHelperGoStruct)(nil)).Elem(),
+	provides:   "the keys of the list entry, used when rendering its path",
+	listMember: true,
+}, {
+	name:     "ΛBelongingModule",
+	iface:    reflect.TypeOf((*belongingModuler)(nil)).Elem(),
+	provides: "the module that defines the namespace of the GoStruct, used when marshalling RPC and action input and output",
+}, {
+	name:     "ΛDeepCopy",
+	iface:    reflect.TypeOf((*DeepCopier)(nil)).Elem(),
+	provides: "copying of the GoStruct by DeepCopy without using reflection",
+	fallback: "DeepCopy copies the GoStruct using reflection",
+}, {
+	name:     "ΛCapabilities",
+	iface:    reflect.TypeOf((*CapabilitiesProvider)(nil)).Elem(),
+	provides: "the options with which the GoStruct was generated, as returned by StructCapabilities",
+	fallback: "StructCapabilities reports that the capabilities are unknown",
+}}
+
+// MissingMethod describes a generated method that is used by the helper
+// functions of ygot and ytypes, and which a GoStruct does not implement,
+// typically because it was generated by an older version of ygot.
+type MissingMethod struct {
+	// Type is the name of the GoStruct type, e.g., "*oc.Interface".
+	Type string
+	// Method is the name of the method that is not implemented.
+	Method string
+	// Provides describes what regenerating the GoStruct with the current
+	// version of ygot would provide.
+	Provides string
+	// Fallback describes the behaviour of the helper functions when the
+	// method is not implemented. It is empty if the helper functions that
+	// use the method return an error.
+	Fallback string
+}
+
+// String returns a human-readable description of the missing method.
+func (m *MissingMethod) String() string {
+	return fmt.Sprintf("%s.%s (%s)", m.Type, m.Method, m.Provides)
+}
+
+// CompatibilityError is the error returned when a GoStruct does not implement
+// a generated method that is required by a helper function, because it was
+// generated by an older version of ygot. It wraps ErrOlderGeneratedCode.
+type CompatibilityError struct {
+	// Missing are the methods that are not implemented.
+	Missing []*MissingMethod
+}
+
+// Error implements the error interface, listing what regenerating the
+// GoStructs would provide.
+func (e *CompatibilityError) Error() string {
+	var ms []string
+	for _, m := range e.Missing {
+		ms = append(ms, m.String())
+	}
+	return fmt.Sprintf("%v, regenerating the GoStructs with the current version of ygot would provide: %s", ErrOlderGeneratedCode, strings.Join(ms, "; "))
+}
+
+// Unwrap returns ErrOlderGeneratedCode.
+func (e *CompatibilityError) Unwrap() error {
+	return ErrOlderGeneratedCode
+}
+
+// missingMethod returns a CompatibilityError for the generated method with
+// the supplied name, which is not implemented by the GoStruct s.
+func missingMethod(s any, name string) error {
+	for _, m := range generatedMethods {
+		if m.name == name {
+			return &CompatibilityError{Missing: []*MissingMethod{{
+				Type:     fmt.Sprintf("%T", s),
+				Method:   m.name,
+				Provides: m.provides,
+				Fallback: m.fallback,
+			}}}
+		}
+	}
+	return fmt.Errorf("%T does not implement %s", s, name)
+}
+
+// CheckCompatibility returns the generated methods that are used by the helper
+// functions of ygot and ytypes, and which are not implemented by the type of
+// the GoStruct s, or by the types of the GoStructs that it may contain. Such
+// methods are typically missing because the GoStructs were generated by an
+// older version of ygot. The methods are sorted by type and name.
+//
+// A *CompatibilityError is returned if any of the missing methods are
+// required, i.e., the helper functions that use them have no fallback and
+// return an error. Missing methods for which there is a fallback are returned
+// without an error, since the helper functions can be used with degraded
+// behaviour, as described by their Fallback.
+func CheckCompatibility(s GoStruct) ([]*MissingMethod, error) {
+	if util.IsValueNil(s) {
+		return nil, fmt.Errorf("invalid input to CheckCompatibility, got nil value: %v", s)
+	}
+
+	var missing []*MissingMethod
+	seen := map[reflect.Type]bool{}
+	var check func(t reflect.Type, listMember bool)
+	check = func(t reflect.Type, listMember bool) {
+		if seen[t] || !t.Implements(reflect.TypeOf((*GoStruct)(nil)).Elem()) {
+			return
+		}
+		seen[t] = true
+
+		for _, m := range generatedMethods {
+			if t.Implements(m.iface) || (m.listMember && !listMember) {
+				continue
+			}
+			mm := &MissingMethod{
+				Type:     t.String(),
+				Method:   m.name,
+				Provides: m.provides,
+				Fallback: m.fallback,
+			}
+			if m.name == "ΛValidate" && !t.Implements(reflect.TypeOf((*legacyValidatedGoStruct)(nil)).Elem()) {
+				mm.Fallback = ""
+			}
+			missing = append(missing, mm)
+		}
+
+		if !util.IsTypeStructPtr(t) {
+			return
+		}
+		st := t.Elem()
+		for i := 0; i < st.NumField(); i++ {
+			ft := st.Field(i).Type
+			switch {
+			case ft.Implements(reflect.TypeOf((*GoOrderedMap)(nil)).Elem()):
+				// The entries of ordered maps are returned by their
+				// generated Get method.
+				if get, ok := ft.MethodByName("Get"); ok && get.Type.NumOut() == 1 {
+					check(get.Type.Out(0), true)
+				}
+			case ft.Kind() == reflect.Map:
+				check(ft.Elem(), true)
+			case ft.Kind() == reflect.Slice:
+				check(ft.Elem(), false)
+			case util.IsTypeStructPtr(ft):
+				check(ft, false)
+			}
+		}
+	}
+	check(reflect.TypeOf(s), false)
+
+	sort.Slice(missing, func(i, j int) bool {
+		if missing[i].Type != missing[j].Type {
+			return missing[i].Type < missing[j].Type
+		}
+		return missing[i].Method < missing[j].Method
+	})
+
+	var required []*MissingMethod
+	for _, m := range missing {
+		if m.Fallback == "" {
+			required = append(required, m)
+		}
+	}
+	if len(required) != 0 {
+		return missing, &CompatibilityError{Missing: required}
+	}
+	return missing, nil
+}
+
+// BelongingModule returns the name of the module that defines the namespace
+// of the GoStruct s, as returned by its generated ΛBelongingModule method. A
+// *CompatibilityError is returned if s was generated by a version of ygot
+// that does not generate the method.
+func BelongingModule(s GoStruct) (string, error) {
+	m, ok := s.(belongingModuler)
+	if !ok {
+		return "", missingMethod(s, "ΛBelongingModule")
+	}
+	return m.ΛBelongingModule(), nil
+}
+
+// legacyValidatedGoStruct is an interface implemented by GoStructs that were
+// generated by versions of ygot that named the validation method Validate,
+// rather than ΛValidate, or by GoStructs generated with the default
+// validation proxy function name.
+type legacyValidatedGoStruct interface {
+	// Validate compares the contents of the implementing struct against
+	// the YANG schema.
+	Validate(...ValidationOption) error
+}
+
+// enumTypeMapper is an interface implemented by GoStructs that describe the
+// enumerated types of their leaves.
+type enumTypeMapper interface {
+	// ΛEnumTypeMap returns the set of enumerated types that are contained
+	// in the generated code.
+	ΛEnumTypeMap() map[string][]reflect.Type
+}
+
+// belongingModuler is an interface implemented by GoStructs that describe the
+// module that defines their namespace.
+type belongingModuler interface {
+	// ΛBelongingModule returns the module in which the GoStruct was
+	// defined.
+	ΛBelongingModule() string
+}
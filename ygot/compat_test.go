@@ -0,0 +1,233 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+// currentRoot is a GoStruct that implements all the generated methods.
+type currentRoot struct {
+	List  map[string]*currentList `path:"list"`
+	Child *currentChild           `path:"child"`
+}
+
+func (*currentRoot) IsYANGGoStruct()                              {}
+func (*currentRoot) ΛValidate(...ValidationOption) error          { return nil }
+func (*currentRoot) ΛEnumTypeMap() map[string][]reflect.Type      { return nil }
+func (*currentRoot) ΛBelongingModule() string                     { return "" }
+func (*currentRoot) ΛCapabilities() *Capabilities                 { return testCapabilities }
+func (s *currentRoot) ΛDeepCopy() GoStruct                        { return s }
+func (*currentList) IsYANGGoStruct()                              {}
+func (*currentList) ΛValidate(...ValidationOption) error          { return nil }
+func (*currentList) ΛEnumTypeMap() map[string][]reflect.Type      { return nil }
+func (*currentList) ΛBelongingModule() string                     { return "mod" }
+func (*currentList) ΛCapabilities() *Capabilities                 { return testCapabilities }
+func (s *currentList) ΛDeepCopy() GoStruct                        { return s }
+func (*currentList) ΛListKeyMap() (map[string]interface{}, error) { return nil, nil }
+func (*currentChild) IsYANGGoStruct()                             {}
+func (*currentChild) ΛValidate(...ValidationOption) error         { return nil }
+func (*currentChild) ΛEnumTypeMap() map[string][]reflect.Type     { return nil }
+func (*currentChild) ΛBelongingModule() string                    { return "mod" }
+func (*currentChild) ΛCapabilities() *Capabilities                { return testCapabilities }
+func (s *currentChild) ΛDeepCopy() GoStruct                       { return s }
+
+type currentList struct {
+	Key *string `path:"key"`
+}
+
+type currentChild struct {
+	Leaf *string `path:"leaf"`
+}
+
+// olderRoot is a GoStruct generated by a version of ygot that generates
+// neither ΛCapabilities nor ΛDeepCopy.
+type olderRoot struct {
+	List map[string]*olderList `path:"list"`
+}
+
+func (*olderRoot) IsYANGGoStruct()                              {}
+func (*olderRoot) ΛValidate(...ValidationOption) error          { return nil }
+func (*olderRoot) ΛEnumTypeMap() map[string][]reflect.Type      { return nil }
+func (*olderRoot) ΛBelongingModule() string                     { return "" }
+func (*olderList) IsYANGGoStruct()                              {}
+func (*olderList) ΛValidate(...ValidationOption) error          { return nil }
+func (*olderList) ΛEnumTypeMap() map[string][]reflect.Type      { return nil }
+func (*olderList) ΛBelongingModule() string                     { return "mod" }
+func (*olderList) ΛListKeyMap() (map[string]interface{}, error) { return nil, nil }
+
+type olderList struct {
+	Key *string `path:"key"`
+}
+
+// legacyRoot is a GoStruct generated by a version of ygot that generates
+// Validate rather than ΛValidate, and does not generate ΛBelongingModule.
+type legacyRoot struct {
+	Child *legacyChild `path:"child"`
+}
+
+func (*legacyRoot) IsYANGGoStruct()                          {}
+func (*legacyRoot) Validate(...ValidationOption) error       { return errors.New("legacy validation") }
+func (*legacyRoot) ΛEnumTypeMap() map[string][]reflect.Type  { return nil }
+func (*legacyChild) IsYANGGoStruct()                         {}
+func (*legacyChild) Validate(...ValidationOption) error      { return nil }
+func (*legacyChild) ΛEnumTypeMap() map[string][]reflect.Type { return nil }
+
+type legacyChild struct {
+	Leaf *string `path:"leaf"`
+}
+
+// unvalidatedRoot is a GoStruct that implements no generated methods.
+type unvalidatedRoot struct {
+	Leaf *string `path:"leaf"`
+}
+
+func (*unvalidatedRoot) IsYANGGoStruct() {}
+
+func TestCheckCompatibility(t *testing.T) {
+	tests := []struct {
+		desc             string
+		in               GoStruct
+		want             []*MissingMethod
+		wantErrSubstring string
+		wantErrMissing   []string
+	}{{
+		desc: "current GoStructs",
+		in:   &currentRoot{},
+	}, {
+		desc: "older GoStructs with fallbacks",
+		in:   &olderRoot{},
+		want: []*MissingMethod{{
+			Type:     "*ygot.olderList",
+			Method:   "ΛCapabilities",
+			Fallback: "StructCapabilities reports that the capabilities are unknown",
+		}, {
+			Type:     "*ygot.olderList",
+			Method:   "ΛDeepCopy",
+			Fallback: "DeepCopy copies the GoStruct using reflection",
+		}, {
+			Type:     "*ygot.olderRoot",
+			Method:   "ΛCapabilities",
+			Fallback: "StructCapabilities reports that the capabilities are unknown",
+		}, {
+			Type:     "*ygot.olderRoot",
+			Method:   "ΛDeepCopy",
+			Fallback: "DeepCopy copies the GoStruct using reflection",
+		}},
+	}, {
+		desc: "legacy GoStructs missing required methods",
+		in:   &legacyRoot{},
+		want: []*MissingMethod{{
+			Type:   "*ygot.legacyChild",
+			Method: "ΛBelongingModule",
+		}, {
+			Type:     "*ygot.legacyChild",
+			Method:   "ΛCapabilities",
+			Fallback: "StructCapabilities reports that the capabilities are unknown",
+		}, {
+			Type:     "*ygot.legacyChild",
+			Method:   "ΛDeepCopy",
+			Fallback: "DeepCopy copies the GoStruct using reflection",
+		}, {
+			Type:     "*ygot.legacyChild",
+			Method:   "ΛValidate",
+			Fallback: "ValidateGoStruct uses the Validate method, if it is implemented",
+		}, {
+			Type:   "*ygot.legacyRoot",
+			Method: "ΛBelongingModule",
+		}, {
+			Type:     "*ygot.legacyRoot",
+			Method:   "ΛCapabilities",
+			Fallback: "StructCapabilities reports that the capabilities are unknown",
+		}, {
+			Type:     "*ygot.legacyRoot",
+			Method:   "ΛDeepCopy",
+			Fallback: "DeepCopy copies the GoStruct using reflection",
+		}, {
+			Type:     "*ygot.legacyRoot",
+			Method:   "ΛValidate",
+			Fallback: "ValidateGoStruct uses the Validate method, if it is implemented",
+		}},
+		wantErrSubstring: "regenerating the GoStructs with the current version of ygot would provide: *ygot.legacyChild.ΛBelongingModule",
+		wantErrMissing:   []string{"*ygot.legacyChild.ΛBelongingModule", "*ygot.legacyRoot.ΛBelongingModule"},
+	}, {
+		desc:             "nil GoStruct",
+		in:               (*currentRoot)(nil),
+		wantErrSubstring: "got nil value",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := CheckCompatibility(tt.in)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("CheckCompatibility: did not get expected error, %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(MissingMethod{}, "Provides")); diff != "" {
+				t.Errorf("CheckCompatibility: did not get expected missing methods, diff(-want,+got):\n%s", diff)
+			}
+			if tt.wantErrMissing == nil {
+				return
+			}
+			var cerr *CompatibilityError
+			if !errors.As(err, &cerr) {
+				t.Fatalf("CheckCompatibility: got error %v, want *CompatibilityError", err)
+			}
+			if !errors.Is(err, ErrOlderGeneratedCode) {
+				t.Errorf("CheckCompatibility: got error %v, want error wrapping ErrOlderGeneratedCode", err)
+			}
+			var gotMissing []string
+			for _, m := range cerr.Missing {
+				gotMissing = append(gotMissing, m.Type+"."+m.Method)
+			}
+			if diff := cmp.Diff(tt.wantErrMissing, gotMissing); diff != "" {
+				t.Errorf("CheckCompatibility: did not get expected required methods, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCompatibilityFallbacks(t *testing.T) {
+	if err := ValidateGoStruct(&legacyRoot{}); err == nil || err.Error() != "legacy validation" {
+		t.Errorf("ValidateGoStruct: got error %v, want error from legacy Validate method", err)
+	}
+
+	err := ValidateGoStruct(&unvalidatedRoot{})
+	if diff := errdiff.Substring(err, "would provide: *ygot.unvalidatedRoot.ΛValidate"); diff != "" {
+		t.Errorf("ValidateGoStruct: did not get expected error, %s", diff)
+	}
+	if !errors.Is(err, ErrOlderGeneratedCode) {
+		t.Errorf("ValidateGoStruct: got error %v, want error wrapping ErrOlderGeneratedCode", err)
+	}
+
+	if got, err := BelongingModule(&currentChild{}); err != nil || got != "mod" {
+		t.Errorf("BelongingModule: got (%q, %v), want (\"mod\", nil)", got, err)
+	}
+	if _, err := BelongingModule(&legacyChild{}); !errors.Is(err, ErrOlderGeneratedCode) {
+		t.Errorf("BelongingModule: got error %v, want error wrapping ErrOlderGeneratedCode", err)
+	}
+
+	if _, ok := StructCapabilities(&olderRoot{}); ok {
+		t.Errorf("StructCapabilities: got ok for GoStruct without ΛCapabilities, want !ok")
+	}
+	if _, err := DeepCopy(&olderRoot{List: map[string]*olderList{"a": {Key: String("a")}}}); err != nil {
+		t.Errorf("DeepCopy: got unexpected error for GoStruct without ΛDeepCopy: %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/util"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// PruneByDataType in-place removes the branches and leaf nodes of the
+// GoStruct s, whose schema is supplied, that are not returned for a gNMI
+// GetRequest that specifies the DataType dt. Per the gNMI specification:
+//   - CONFIG retains the read-write ("config true") data.
+//   - STATE retains the read-only ("config false") data, including the
+//     applied configuration.
+//   - OPERATIONAL retains the read-only data that is not a copy of
+//     configuration, i.e., derived state.
+//   - ALL retains all data.
+//
+// For compressed GoStructs, a leaf that represents both a configuration leaf
+// and its corresponding applied configuration leaf is retained for both CONFIG
+// and STATE, but not for OPERATIONAL. For uncompressed GoStructs, a leaf within
+// an OpenConfig "state" container is considered to be applied configuration
+// if there is a leaf at the same path within the sibling "config" container.
+//
+// The keys of list entries are always retained such that the entries remain
+// addressable. Containers that are empty after pruning are removed.
+func PruneByDataType(schema *yang.Entry, s GoStruct, dt gnmipb.GetRequest_DataType) error {
+	if dt == gnmipb.GetRequest_ALL {
+		return nil
+	}
+	pruneIterFunc := func(ni *util.NodeInfo, in, out interface{}) util.Errors {
+		if ni == nil || util.IsNilOrInvalidValue(ni.FieldValue) || ni.FieldValue.IsZero() {
+			return nil
+		}
+		// The top-level GoStruct cannot be written to since it is
+		// unaddressable, so the best we can do is to skip writing to
+		// it, and prune its children.
+		if ni.Parent == nil {
+			return nil
+		}
+		// Directories are retained unless they are entirely read-only
+		// and configuration is requested, since they may contain data
+		// of any type.
+		if ni.Schema.IsDir() && (dt != gnmipb.GetRequest_CONFIG || util.IsConfig(ni.Schema)) {
+			return nil
+		}
+		if isListKeyLeaf(ni.Schema) || InDataType(ni.Schema, dt) {
+			return nil
+		}
+		ni.FieldValue.Set(reflect.Zero(ni.FieldValue.Type()))
+		return nil
+	}
+	if errs := util.ForEachField(schema, s, nil, nil, pruneIterFunc); errs != nil {
+		return errs
+	}
+	PruneEmptyBranches(s)
+	return nil
+}
+
+// InDataType reports whether the data described by the schema entry e is
+// returned for a gNMI GetRequest that specifies the DataType dt. See
+// PruneByDataType for the classification used.
+func InDataType(e *yang.Entry, dt gnmipb.GetRequest_DataType) bool {
+	_, compressedLeaf := e.Annotation[GoCompressedLeafAnnotation]
+	switch dt {
+	case gnmipb.GetRequest_CONFIG:
+		return util.IsConfig(e) || compressedLeaf
+	case gnmipb.GetRequest_STATE:
+		return !util.IsConfig(e) || compressedLeaf
+	case gnmipb.GetRequest_OPERATIONAL:
+		return !util.IsConfig(e) && !compressedLeaf && !isAppliedConfig(e)
+	}
+	return true
+}
+
+// isAppliedConfig reports whether e is within an OpenConfig "state" container
+// and mirrors an entry at the same path within the sibling "config"
+// container, i.e., it reports applied configuration.
+func isAppliedConfig(e *yang.Entry) bool {
+	var rel []string
+	for n := e; n.Parent != nil; n = n.Parent {
+		if n.Name == "state" && n.IsDir() {
+			if len(rel) == 0 {
+				return false
+			}
+			c := n.Parent.Dir["config"]
+			for i := len(rel) - 1; i >= 0 && c != nil; i-- {
+				c = c.Dir[rel[i]]
+			}
+			return c != nil
+		}
+		rel = append(rel, n.Name)
+	}
+	return false
+}
+
+// isListKeyLeaf reports whether e is a leaf that is a key of its parent list.
+// For compressed schemas, the key leaves of a list may be within the list's
+// config or state container.
+func isListKeyLeaf(e *yang.Entry) bool {
+	if !e.IsLeaf() {
+		return false
+	}
+	p := e.Parent
+	if p != nil && util.IsConfigState(p) && p.Parent != nil && util.IsCompressedSchema(p) {
+		p = p.Parent
+	}
+	return util.IsKeyedList(p) && util.ListKeyFieldsMap(p)[e.Name]
+}
+
+// FilterNotificationsByDataType returns the subset of the supplied
+// Notifications whose updates and deletions are returned for a gNMI
+// GetRequest that specifies the DataType dt, using the classification
+// described by PruneByDataType. The schema supplied must be the root of the
+// uncompressed YANG schema against which the Notifications' paths are
+// expressed, e.g., the entry for the root of a generated schema tree.
+// Notifications that contain no updates or deletions after filtering are
+// omitted. The input Notifications are not modified.
+//
+// Each update path must refer to a leaf or leaf-list, since JSON-encoded
+// values of containers cannot be filtered. Deletions of containers and lists
+// are always retained, since they may remove data of any type.
+func FilterNotificationsByDataType(schema *yang.Entry, ns []*gnmipb.Notification, dt gnmipb.GetRequest_DataType) ([]*gnmipb.Notification, error) {
+	if dt == gnmipb.GetRequest_ALL {
+		return ns, nil
+	}
+	var filtered []*gnmipb.Notification
+	for _, n := range ns {
+		var dels []*gnmipb.Path
+		for _, p := range n.Delete {
+			e, err := schemaForNotificationPath(schema, n.Prefix, p)
+			if err != nil {
+				return nil, err
+			}
+			if e.IsDir() || InDataType(e, dt) {
+				dels = append(dels, p)
+			}
+		}
+		var upds []*gnmipb.Update
+		for _, u := range n.Update {
+			e, err := schemaForNotificationPath(schema, n.Prefix, u.Path)
+			if err != nil {
+				return nil, err
+			}
+			if e.IsDir() {
+				return nil, fmt.Errorf("cannot filter update to path %v, which does not refer to a leaf or leaf-list", u.Path)
+			}
+			if InDataType(e, dt) {
+				upds = append(upds, u)
+			}
+		}
+		if len(dels) == 0 && len(upds) == 0 {
+			continue
+		}
+		filtered = append(filtered, &gnmipb.Notification{
+			Timestamp: n.Timestamp,
+			Prefix:    n.Prefix,
+			Atomic:    n.Atomic,
+			Delete:    dels,
+			Update:    upds,
+		})
+	}
+	return filtered, nil
+}
+
+// schemaForNotificationPath returns the schema entry for the supplied path,
+// which is relative to prefix, by traversing the supplied root schema.
+func schemaForNotificationPath(schema *yang.Entry, prefix, path *gnmipb.Path) (*yang.Entry, error) {
+	e := schema
+	for _, pe := range append(append([]*gnmipb.PathElem{}, prefix.GetElem()...), path.GetElem()...) {
+		name := util.StripModulePrefix(pe.GetName())
+		child := e.Dir[name]
+		if child == nil || util.IsChoiceOrCase(child) {
+			// The child may be within a choice or case node, which
+			// does not appear in data tree paths.
+			child = nil
+			for _, c := range util.FindFirstNonChoiceOrCase(e) {
+				if c.Name == name {
+					child = c
+					break
+				}
+			}
+		}
+		if child == nil {
+			return nil, fmt.Errorf("cannot find schema for element %q of path %v", name, path)
+		}
+		e = child
+	}
+	return e, nil
+}
@@ -0,0 +1,290 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/goyang/pkg/yang"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+type dataTypeDevice struct {
+	Interface map[string]*dataTypeInterface `path:"interfaces/interface"`
+}
+
+func (*dataTypeDevice) IsYANGGoStruct() {}
+
+type dataTypeInterface struct {
+	Name   *string                  `path:"name"`
+	Config *dataTypeInterfaceConfig `path:"config"`
+	State  *dataTypeInterfaceState  `path:"state"`
+}
+
+func (*dataTypeInterface) IsYANGGoStruct() {}
+
+type dataTypeInterfaceConfig struct {
+	Name *string `path:"name"`
+	Mtu  *uint16 `path:"mtu"`
+}
+
+func (*dataTypeInterfaceConfig) IsYANGGoStruct() {}
+
+type dataTypeInterfaceState struct {
+	Name    *string `path:"name"`
+	Mtu     *uint16 `path:"mtu"`
+	Counter *uint64 `path:"counter"`
+}
+
+func (*dataTypeInterfaceState) IsYANGGoStruct() {}
+
+func dataTypeSchema() *yang.Entry {
+	leaf := func(name string, kind yang.TypeKind) *yang.Entry {
+		return &yang.Entry{Name: name, Kind: yang.LeafEntry, Type: &yang.YangType{Kind: kind}}
+	}
+	schema := &yang.Entry{
+		Name: "device",
+		Kind: yang.DirectoryEntry,
+		Dir: map[string]*yang.Entry{
+			"interfaces": {
+				Name: "interfaces",
+				Kind: yang.DirectoryEntry,
+				Dir: map[string]*yang.Entry{
+					"interface": {
+						Name:     "interface",
+						Kind:     yang.DirectoryEntry,
+						ListAttr: yang.NewDefaultListAttr(),
+						Key:      "name",
+						Dir: map[string]*yang.Entry{
+							"name": leaf("name", yang.Ystring),
+							"config": {
+								Name: "config",
+								Kind: yang.DirectoryEntry,
+								Dir: map[string]*yang.Entry{
+									"name": leaf("name", yang.Ystring),
+									"mtu":  leaf("mtu", yang.Yuint16),
+								},
+							},
+							"state": {
+								Name:   "state",
+								Kind:   yang.DirectoryEntry,
+								Config: yang.TSFalse,
+								Dir: map[string]*yang.Entry{
+									"name":    leaf("name", yang.Ystring),
+									"mtu":     leaf("mtu", yang.Yuint16),
+									"counter": leaf("counter", yang.Yuint64),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	addParents(schema)
+	return schema
+}
+
+func dataTypeStruct() *dataTypeDevice {
+	return &dataTypeDevice{
+		Interface: map[string]*dataTypeInterface{
+			"eth0": {
+				Name:   String("eth0"),
+				Config: &dataTypeInterfaceConfig{Name: String("eth0"), Mtu: Uint16(1500)},
+				State:  &dataTypeInterfaceState{Name: String("eth0"), Mtu: Uint16(1500), Counter: Uint64(42)},
+			},
+		},
+	}
+}
+
+func TestPruneByDataType(t *testing.T) {
+	tests := []struct {
+		desc   string
+		inType gnmipb.GetRequest_DataType
+		want   *dataTypeDevice
+	}{{
+		desc:   "all",
+		inType: gnmipb.GetRequest_ALL,
+		want:   dataTypeStruct(),
+	}, {
+		desc:   "config",
+		inType: gnmipb.GetRequest_CONFIG,
+		want: &dataTypeDevice{
+			Interface: map[string]*dataTypeInterface{
+				"eth0": {
+					Name:   String("eth0"),
+					Config: &dataTypeInterfaceConfig{Name: String("eth0"), Mtu: Uint16(1500)},
+				},
+			},
+		},
+	}, {
+		desc:   "state",
+		inType: gnmipb.GetRequest_STATE,
+		want: &dataTypeDevice{
+			Interface: map[string]*dataTypeInterface{
+				"eth0": {
+					Name:  String("eth0"),
+					State: &dataTypeInterfaceState{Name: String("eth0"), Mtu: Uint16(1500), Counter: Uint64(42)},
+				},
+			},
+		},
+	}, {
+		desc:   "operational",
+		inType: gnmipb.GetRequest_OPERATIONAL,
+		want: &dataTypeDevice{
+			Interface: map[string]*dataTypeInterface{
+				"eth0": {
+					Name:  String("eth0"),
+					State: &dataTypeInterfaceState{Counter: Uint64(42)},
+				},
+			},
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := dataTypeStruct()
+			if err := PruneByDataType(dataTypeSchema(), got, tt.inType); err != nil {
+				t.Fatalf("PruneByDataType: unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("PruneByDataType: (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInDataTypeCompressedLeaf(t *testing.T) {
+	schema := dataTypeSchema()
+	e := schema.Dir["interfaces"].Dir["interface"].Dir["state"].Dir["mtu"]
+	e.Annotation = map[string]interface{}{GoCompressedLeafAnnotation: struct{}{}}
+
+	for dt, want := range map[gnmipb.GetRequest_DataType]bool{
+		gnmipb.GetRequest_ALL:         true,
+		gnmipb.GetRequest_CONFIG:      true,
+		gnmipb.GetRequest_STATE:       true,
+		gnmipb.GetRequest_OPERATIONAL: false,
+	} {
+		if got := InDataType(e, dt); got != want {
+			t.Errorf("InDataType(%s): got %v, want %v", dt, got, want)
+		}
+	}
+}
+
+func TestFilterNotificationsByDataType(t *testing.T) {
+	path := func(s string) *gnmipb.Path {
+		p, err := StringToStructuredPath(s)
+		if err != nil {
+			t.Fatalf("cannot parse path %s: %v", s, err)
+		}
+		return p
+	}
+	update := func(p string, val uint64) *gnmipb.Update {
+		return &gnmipb.Update{
+			Path: path(p),
+			Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_UintVal{UintVal: val}},
+		}
+	}
+
+	in := []*gnmipb.Notification{{
+		Timestamp: 42,
+		Prefix:    path("/interfaces/interface[name=eth0]"),
+		Update: []*gnmipb.Update{
+			update("config/mtu", 1500),
+			update("state/mtu", 1500),
+			update("state/counter", 42),
+		},
+	}, {
+		Timestamp: 84,
+		Delete: []*gnmipb.Path{
+			path("/interfaces/interface[name=eth1]"),
+			path("/interfaces/interface[name=eth0]/config/mtu"),
+		},
+	}}
+
+	tests := []struct {
+		desc             string
+		in               []*gnmipb.Notification
+		inType           gnmipb.GetRequest_DataType
+		want             []*gnmipb.Notification
+		wantErrSubstring string
+	}{{
+		desc:   "all",
+		in:     in,
+		inType: gnmipb.GetRequest_ALL,
+		want:   in,
+	}, {
+		desc:   "config",
+		in:     in,
+		inType: gnmipb.GetRequest_CONFIG,
+		want: []*gnmipb.Notification{{
+			Timestamp: 42,
+			Prefix:    path("/interfaces/interface[name=eth0]"),
+			Update:    []*gnmipb.Update{update("config/mtu", 1500)},
+		}, {
+			Timestamp: 84,
+			Delete: []*gnmipb.Path{
+				path("/interfaces/interface[name=eth1]"),
+				path("/interfaces/interface[name=eth0]/config/mtu"),
+			},
+		}},
+	}, {
+		desc:   "operational",
+		in:     in,
+		inType: gnmipb.GetRequest_OPERATIONAL,
+		want: []*gnmipb.Notification{{
+			Timestamp: 42,
+			Prefix:    path("/interfaces/interface[name=eth0]"),
+			Update:    []*gnmipb.Update{update("state/counter", 42)},
+		}, {
+			Timestamp: 84,
+			Delete:    []*gnmipb.Path{path("/interfaces/interface[name=eth1]")},
+		}},
+	}, {
+		desc: "unknown path",
+		in: []*gnmipb.Notification{{
+			Update: []*gnmipb.Update{update("/interfaces/interface[name=eth0]/state/bogus", 1)},
+		}},
+		inType:           gnmipb.GetRequest_STATE,
+		wantErrSubstring: `cannot find schema for element "bogus"`,
+	}, {
+		desc: "update to container",
+		in: []*gnmipb.Notification{{
+			Update: []*gnmipb.Update{update("/interfaces/interface[name=eth0]/state", 1)},
+		}},
+		inType:           gnmipb.GetRequest_STATE,
+		wantErrSubstring: "does not refer to a leaf or leaf-list",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := FilterNotificationsByDataType(dataTypeSchema(), tt.in, tt.inType)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("FilterNotificationsByDataType: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("FilterNotificationsByDataType: (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Decimal64TypeName is the name of the type that is used for YANG
+	// decimal64 fields in the output structs when they are generated
+	// using the Decimal64 type rather than float64.
+	Decimal64TypeName string = "Decimal64"
+	// maxDecimal64Precision is the maximum number of fraction digits of a
+	// YANG decimal64, per RFC7950 Section 9.3.4.
+	maxDecimal64Precision = 18
+)
+
+// Decimal64 is a fixed-point representation of a YANG decimal64 value, which
+// is equal to Digits * 10^-Precision. Unlike float64, it represents every
+// value of a decimal64 exactly, and retains the number of fraction digits of
+// its type.
+//
+// Precision is the number of fraction digits of the YANG type of the value,
+// which is between 1 and 18. The zero value of Decimal64, which has a
+// Precision of 0, is therefore not a valid decimal64, and is used to indicate
+// that a leaf of the type is unset.
+type Decimal64 struct {
+	// Digits is the value of the decimal64 without its decimal point.
+	Digits int64
+	// Precision is the number of fraction digits of the value.
+	Precision uint8
+}
+
+// IsYANGLeafValue is a marker method that indicates that Decimal64 is the
+// value of a YANG leaf, rather than a struct representing a YANG container.
+func (Decimal64) IsYANGLeafValue() {}
+
+// ParseDecimal64 parses s, a decimal number such as "-12.50", returning it as
+// a Decimal64 with the supplied precision. An error is returned if the
+// precision is not between 1 and 18, or s is not a decimal number, or cannot
+// be represented using the precision, i.e., it has more significant fraction
+// digits than the precision, or overflows a decimal64.
+func ParseDecimal64(s string, precision uint8) (Decimal64, error) {
+	if precision < 1 || precision > maxDecimal64Precision {
+		return Decimal64{}, fmt.Errorf("invalid decimal64 precision %d, must be between 1 and %d", precision, maxDecimal64Precision)
+	}
+
+	num := s
+	var sign string
+	if num != "" && (num[0] == '-' || num[0] == '+') {
+		sign, num = num[:1], num[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(num, ".")
+	if intPart == "" || !isDecimalDigits(intPart) || !isDecimalDigits(fracPart) || strings.HasSuffix(num, ".") {
+		return Decimal64{}, fmt.Errorf("%q is not a valid decimal number", s)
+	}
+
+	// Trailing zeros do not change the value, so are permitted beyond the
+	// precision.
+	if len(fracPart) > int(precision) {
+		if strings.TrimRight(fracPart[precision:], "0") != "" {
+			return Decimal64{}, fmt.Errorf("%q has more than %d fraction digits", s, precision)
+		}
+		fracPart = fracPart[:precision]
+	}
+	fracPart += strings.Repeat("0", int(precision)-len(fracPart))
+
+	digits, err := strconv.ParseInt(sign+intPart+fracPart, 10, 64)
+	if err != nil {
+		return Decimal64{}, fmt.Errorf("%q cannot be represented as a decimal64 with %d fraction digits", s, precision)
+	}
+	return Decimal64{Digits: digits, Precision: precision}, nil
+}
+
+// isDecimalDigits reports whether s consists only of the digits 0-9.
+func isDecimalDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the canonical representation of the value of d, per RFC7950
+// Section 9.3.2, which has at least one digit before and after the decimal
+// point, and no trailing zeros after the first fraction digit.
+func (d Decimal64) String() string {
+	abs := strconv.FormatUint(absInt64(d.Digits), 10)
+	if p := int(d.Precision); len(abs) <= p {
+		abs = strings.Repeat("0", p-len(abs)+1) + abs
+	}
+	point := len(abs) - int(d.Precision)
+	frac := strings.TrimRight(abs[point:], "0")
+	if frac == "" {
+		frac = "0"
+	}
+
+	var sign string
+	if d.Digits < 0 {
+		sign = "-"
+	}
+	return sign + abs[:point] + "." + frac
+}
+
+// absInt64 returns the absolute value of i, which is representable as a
+// uint64 for all values of i.
+func absInt64(i int64) uint64 {
+	if i < 0 {
+		return uint64(-(i + 1)) + 1
+	}
+	return uint64(i)
+}
+
+// MarshalJSON marshals d as a JSON number with its exact value, which is used
+// for the internal JSON format. RFC7951 JSON represents it as a string.
+func (d Decimal64) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Float64 returns the float64 nearest to the value of d.
+func (d Decimal64) Float64() float64 {
+	// The canonical string is always a valid float.
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+func TestParseDecimal64(t *testing.T) {
+	tests := []struct {
+		name             string
+		inString         string
+		inPrecision      uint8
+		want             Decimal64
+		wantErrSubstring string
+	}{{
+		name:        "integer",
+		inString:    "42",
+		inPrecision: 2,
+		want:        Decimal64{Digits: 4200, Precision: 2},
+	}, {
+		name:        "fraction",
+		inString:    "-12.5",
+		inPrecision: 2,
+		want:        Decimal64{Digits: -1250, Precision: 2},
+	}, {
+		name:        "explicit positive sign",
+		inString:    "+0.01",
+		inPrecision: 2,
+		want:        Decimal64{Digits: 1, Precision: 2},
+	}, {
+		name:        "trailing zeros beyond precision",
+		inString:    "1.2500",
+		inPrecision: 2,
+		want:        Decimal64{Digits: 125, Precision: 2},
+	}, {
+		name:        "maximum precision",
+		inString:    "-9.223372036854775807",
+		inPrecision: 18,
+		want:        Decimal64{Digits: -9223372036854775807, Precision: 18},
+	}, {
+		name:        "minimum value with precision 1",
+		inString:    "-922337203685477580.8",
+		inPrecision: 1,
+		want:        Decimal64{Digits: math.MinInt64, Precision: 1},
+	}, {
+		name:             "too many fraction digits",
+		inString:         "1.125",
+		inPrecision:      2,
+		wantErrSubstring: "more than 2 fraction digits",
+	}, {
+		name:             "overflow",
+		inString:         "922337203685477580.8",
+		inPrecision:      1,
+		wantErrSubstring: "cannot be represented",
+	}, {
+		name:             "invalid precision",
+		inString:         "1",
+		inPrecision:      19,
+		wantErrSubstring: "invalid decimal64 precision",
+	}, {
+		name:             "zero precision",
+		inString:         "1",
+		wantErrSubstring: "invalid decimal64 precision",
+	}, {
+		name:             "exponent",
+		inString:         "1e3",
+		inPrecision:      2,
+		wantErrSubstring: "not a valid decimal number",
+	}, {
+		name:             "missing fraction digits",
+		inString:         "1.",
+		inPrecision:      2,
+		wantErrSubstring: "not a valid decimal number",
+	}, {
+		name:             "missing integer digits",
+		inString:         ".5",
+		inPrecision:      2,
+		wantErrSubstring: "not a valid decimal number",
+	}, {
+		name:             "empty",
+		inPrecision:      2,
+		wantErrSubstring: "not a valid decimal number",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDecimal64(tt.inString, tt.inPrecision)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("ParseDecimal64(%q, %d): did not get expected error, %s", tt.inString, tt.inPrecision, diff)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseDecimal64(%q, %d): got %#v, want %#v", tt.inString, tt.inPrecision, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimal64String(t *testing.T) {
+	tests := []struct {
+		in          Decimal64
+		wantString  string
+		wantFloat64 float64
+	}{
+		{in: Decimal64{Digits: 1250, Precision: 2}, wantString: "12.5", wantFloat64: 12.5},
+		{in: Decimal64{Digits: 1200, Precision: 2}, wantString: "12.0", wantFloat64: 12},
+		{in: Decimal64{Digits: -5, Precision: 3}, wantString: "-0.005", wantFloat64: -0.005},
+		{in: Decimal64{Digits: 0, Precision: 1}, wantString: "0.0", wantFloat64: 0},
+		{in: Decimal64{Digits: 1, Precision: 18}, wantString: "0.000000000000000001", wantFloat64: 1e-18},
+		{in: Decimal64{Digits: math.MinInt64, Precision: 1}, wantString: "-922337203685477580.8", wantFloat64: -922337203685477580.8},
+	}
+
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.wantString {
+			t.Errorf("%#v.String(): got %q, want %q", tt.in, got, tt.wantString)
+		}
+		if got := tt.in.Float64(); got != tt.wantFloat64 {
+			t.Errorf("%#v.Float64(): got %v, want %v", tt.in, got, tt.wantFloat64)
+		}
+		if got, err := tt.in.MarshalJSON(); err != nil || string(got) != tt.wantString {
+			t.Errorf("%#v.MarshalJSON(): got %s, %v, want %s, nil", tt.in, got, err, tt.wantString)
+		}
+		// The canonical representation must be parsed to the same value.
+		if got, err := ParseDecimal64(tt.in.String(), tt.in.Precision); err != nil || got != tt.in {
+			t.Errorf("ParseDecimal64(%q, %d): got %#v, %v, want %#v, nil", tt.in.String(), tt.in.Precision, got, err, tt.in)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"sync"
+)
+
+var (
+	// defaultsMu protects the process-wide default options.
+	defaultsMu sync.RWMutex
+	// defaultRFC7951Config is the RFC7951JSONConfig that is used by the
+	// functions that marshal RFC7951 JSON when they are not supplied with
+	// a configuration. A nil value indicates that there is no default.
+	defaultRFC7951Config *RFC7951JSONConfig
+)
+
+// SetDefaultRFC7951JSONConfig sets the RFC7951JSONConfig that is used by
+// EmitJSON, ConstructIETFJSON, Marshal7951, EncodeTypedValue and the
+// functions that call them whenever they are not explicitly supplied with an
+// RFC7951JSONConfig. A configuration that is supplied explicitly overrides the
+// default in its entirety, rather than being merged with it. Supplying a nil
+// configuration clears the default.
+//
+// The default only applies to the JSON that is returned to the caller. It is
+// not used where ygot or its companion packages encode values for their own
+// purposes, such as the fingerprints returned by Fingerprint, the
+// Notifications returned by TogNMINotifications and ytypes.GetNotifications,
+// or the values encoded by ytypes.SetRequestBuilder, ytypes.MirrorConfigToState
+// and gnmidiff, whose output therefore does not depend on the default.
+//
+// The configuration is copied, such that subsequent changes to c do not
+// affect the default. It is safe to call SetDefaultRFC7951JSONConfig
+// concurrently with marshalling functions, although it is generally expected
+// to be called once during the initialisation of a program.
+func SetDefaultRFC7951JSONConfig(c *RFC7951JSONConfig) {
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+	defaultRFC7951Config = copyRFC7951JSONConfig(c)
+}
+
+// DefaultRFC7951JSONConfig returns a copy of the RFC7951JSONConfig that was
+// set by SetDefaultRFC7951JSONConfig, or nil if no default is set.
+func DefaultRFC7951JSONConfig() *RFC7951JSONConfig {
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+	return copyRFC7951JSONConfig(defaultRFC7951Config)
+}
+
+// rfc7951ConfigOrDefault returns c if it is non-nil, and otherwise the
+// default RFC7951JSONConfig, which may be nil.
+func rfc7951ConfigOrDefault(c *RFC7951JSONConfig) *RFC7951JSONConfig {
+	if c != nil {
+		return c
+	}
+	return DefaultRFC7951JSONConfig()
+}
+
+// copyRFC7951JSONConfig returns a copy of the supplied RFC7951JSONConfig.
+func copyRFC7951JSONConfig(c *RFC7951JSONConfig) *RFC7951JSONConfig {
+	if c == nil {
+		return nil
+	}
+	cpy := *c
+	if c.RewriteModuleNames != nil {
+		cpy.RewriteModuleNames = make(map[string]string, len(c.RewriteModuleNames))
+		for k, v := range c.RewriteModuleNames {
+			cpy.RewriteModuleNames[k] = v
+		}
+	}
+	return &cpy
+}
@@ -0,0 +1,177 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestDefaultRFC7951JSONConfig(t *testing.T) {
+	t.Cleanup(func() { SetDefaultRFC7951JSONConfig(nil) })
+
+	if got := DefaultRFC7951JSONConfig(); got != nil {
+		t.Fatalf("DefaultRFC7951JSONConfig: got %v, want nil", got)
+	}
+
+	in := &RFC7951JSONConfig{
+		PreferShadowPath:   true,
+		RewriteModuleNames: map[string]string{"a": "b"},
+	}
+	SetDefaultRFC7951JSONConfig(in)
+	// Changes to the supplied configuration must not affect the default.
+	in.PreferShadowPath = false
+	in.RewriteModuleNames["a"] = "c"
+
+	want := &RFC7951JSONConfig{
+		PreferShadowPath:   true,
+		RewriteModuleNames: map[string]string{"a": "b"},
+	}
+	got := DefaultRFC7951JSONConfig()
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("DefaultRFC7951JSONConfig: did not get expected config (-want, +got):\n%s", diff)
+	}
+	// Changes to the returned configuration must not affect the default.
+	got.PreferShadowPath = false
+	if diff := cmp.Diff(want, DefaultRFC7951JSONConfig()); diff != "" {
+		t.Errorf("DefaultRFC7951JSONConfig: default was modified (-want, +got):\n%s", diff)
+	}
+}
+
+func TestDefaultRFC7951JSONConfigInherited(t *testing.T) {
+	t.Cleanup(func() { SetDefaultRFC7951JSONConfig(nil) })
+	SetDefaultRFC7951JSONConfig(&RFC7951JSONConfig{PreferShadowPath: true})
+
+	in := &renderExample{Str: String("hello")}
+
+	tests := []struct {
+		desc string
+		fn   func() (string, error)
+		want string
+	}{{
+		desc: "ConstructIETFJSON with default",
+		fn: func() (string, error) {
+			j, err := ConstructIETFJSON(in, nil)
+			if err != nil {
+				return "", err
+			}
+			return keyOf(j), nil
+		},
+		want: "srt",
+	}, {
+		desc: "ConstructIETFJSON with override",
+		fn: func() (string, error) {
+			j, err := ConstructIETFJSON(in, &RFC7951JSONConfig{})
+			if err != nil {
+				return "", err
+			}
+			return keyOf(j), nil
+		},
+		want: "str",
+	}, {
+		desc: "Marshal7951 with default",
+		fn: func() (string, error) {
+			b, err := Marshal7951(in)
+			return string(b), err
+		},
+		want: `{"srt":"hello"}`,
+	}, {
+		desc: "Marshal7951 with override",
+		fn: func() (string, error) {
+			b, err := Marshal7951(in, &RFC7951JSONConfig{})
+			return string(b), err
+		},
+		want: `{"str":"hello"}`,
+	}, {
+		desc: "EmitJSON with default",
+		fn: func() (string, error) {
+			return EmitJSON(in, &EmitJSONConfig{Format: RFC7951, SkipValidation: true, Indent: " "})
+		},
+		want: "{\n \"srt\": \"hello\"\n}",
+	}, {
+		desc: "EncodeTypedValue with default",
+		fn: func() (string, error) {
+			tv, err := EncodeTypedValue(in, gnmipb.Encoding_JSON_IETF)
+			return string(tv.GetJsonIetfVal()), err
+		},
+		want: "{\n  \"srt\": \"hello\"\n}",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := tt.fn()
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// keyOf returns an arbitrary key of the supplied map.
+func keyOf(m map[string]any) string {
+	for k := range m {
+		return k
+	}
+	return ""
+}
+
+// defaultsParent is a GoStruct whose child is rendered as a JSON_IETF subtree
+// by TogNMINotifications.
+type defaultsParent struct {
+	Child *renderExample `path:"child"`
+}
+
+func (*defaultsParent) IsYANGGoStruct() {}
+
+func TestDefaultRFC7951JSONConfigNotUsedInternally(t *testing.T) {
+	in := &defaultsParent{Child: &renderExample{Str: String("hello")}}
+	notifs := func() ([]*gnmipb.Notification, error) {
+		return TogNMINotifications(in, 42, GNMINotificationsConfig{UsePathElem: true, JSONIETFUpdateDepth: 1})
+	}
+
+	wantNotifs, err := notifs()
+	if err != nil {
+		t.Fatalf("TogNMINotifications: got unexpected error: %v", err)
+	}
+	wantFingerprint, err := Fingerprint(in)
+	if err != nil {
+		t.Fatalf("Fingerprint: got unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() { SetDefaultRFC7951JSONConfig(nil) })
+	SetDefaultRFC7951JSONConfig(&RFC7951JSONConfig{AppendModuleName: true, PreferShadowPath: true})
+
+	gotNotifs, err := notifs()
+	if err != nil {
+		t.Fatalf("TogNMINotifications: got unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(wantNotifs, gotNotifs, protocmp.Transform()); diff != "" {
+		t.Errorf("TogNMINotifications: output depends on default config (-without default, +with default):\n%s", diff)
+	}
+	gotFingerprint, err := Fingerprint(in)
+	if err != nil {
+		t.Fatalf("Fingerprint: got unexpected error: %v", err)
+	}
+	if gotFingerprint != wantFingerprint {
+		t.Errorf("Fingerprint: got %d with default config, want %d", gotFingerprint, wantFingerprint)
+	}
+}
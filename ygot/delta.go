@@ -0,0 +1,134 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/openconfig/ygot/util"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// PresenceBitmap records which of a set of fields, identified by their index,
+// have been explicitly set. The zero value is an empty bitmap.
+type PresenceBitmap []uint64
+
+// Set marks the field with index i as present.
+func (b *PresenceBitmap) Set(i int) {
+	w := i / 64
+	if w >= len(*b) {
+		nb := make(PresenceBitmap, w+1)
+		copy(nb, *b)
+		*b = nb
+	}
+	(*b)[w] |= 1 << (uint(i) % 64)
+}
+
+// Clear marks the field with index i as not present.
+func (b *PresenceBitmap) Clear(i int) {
+	if w := i / 64; w < len(*b) {
+		(*b)[w] &^= 1 << (uint(i) % 64)
+	}
+}
+
+// Has returns true if the field with index i is marked as present.
+func (b PresenceBitmap) Has(i int) bool {
+	w := i / 64
+	return w < len(b) && b[w]&(1<<(uint(i)%64)) != 0
+}
+
+// Names returns the elements of names whose index is marked as present in
+// the bitmap, in the order in which they appear in names.
+func (b PresenceBitmap) Names(names []string) []string {
+	var present []string
+	for i, n := range names {
+		if b.Has(i) {
+			present = append(present, n)
+		}
+	}
+	return present
+}
+
+// DeltaStruct is an interface implemented by the generated delta types of
+// GoStructs. A delta wraps a GoStruct, and records which of its leaf fields
+// were explicitly set, such that a field that was set to its zero value, or
+// for enumerated fields, to UNSET, can be distinguished from a field that was
+// not set.
+type DeltaStruct interface {
+	// ΛDeltaStruct returns the GoStruct that is wrapped by the delta.
+	ΛDeltaStruct() GoStruct
+	// ΛPresentFields returns the names of the fields of the wrapped
+	// GoStruct that were explicitly set.
+	ΛPresentFields() []string
+}
+
+// DeltaNotification returns a gNMI Notification containing the leaves of the
+// supplied DeltaStruct that were explicitly set. The paths of the leaves are
+// relative to the supplied prefix, which is used as the prefix of the
+// Notification, and ts is used as its timestamp. A leaf that was explicitly
+// set to a value that cannot be represented in gNMI, such as the UNSET value
+// of an enumerated type, or a nil value, is included as a delete of the
+// leaf's paths rather than as an update.
+func DeltaNotification(d DeltaStruct, ts int64, prefix *gnmipb.Path) (*gnmipb.Notification, error) {
+	n := &gnmipb.Notification{
+		Timestamp: ts,
+		Prefix:    prefix,
+	}
+	s := d.ΛDeltaStruct()
+	if util.IsValueNil(s) {
+		return n, nil
+	}
+
+	sv := reflect.ValueOf(s).Elem()
+	for _, name := range d.ΛPresentFields() {
+		ft, ok := sv.Type().FieldByName(name)
+		if !ok {
+			return nil, fmt.Errorf("%T does not have a field named %s", s, name)
+		}
+		paths, err := util.SchemaPaths(ft)
+		if err != nil {
+			return nil, fmt.Errorf("%T: %v", s, err)
+		}
+
+		val, err := EncodeTypedValue(sv.FieldByIndex(ft.Index).Interface(), gnmipb.Encoding_JSON)
+		if err != nil {
+			return nil, fmt.Errorf("%T: cannot encode field %s, %v", s, name, err)
+		}
+		if sval, ok := val.GetValue().(*gnmipb.TypedValue_StringVal); ok && sval.StringVal == "" && isEnumField(ft) {
+			// Enumerated fields set to UNSET are encoded as an empty string.
+			val = nil
+		}
+
+		for _, p := range paths {
+			path := &gnmipb.Path{}
+			for _, e := range p {
+				path.Elem = append(path.Elem, &gnmipb.PathElem{Name: e})
+			}
+			if val == nil {
+				n.Delete = append(n.Delete, path)
+				continue
+			}
+			n.Update = append(n.Update, &gnmipb.Update{Path: path, Val: val})
+		}
+	}
+	return n, nil
+}
+
+// isEnumField returns true if the field ft has a GoEnum type.
+func isEnumField(ft reflect.StructField) bool {
+	return ft.Type.Implements(reflect.TypeOf((*GoEnum)(nil)).Elem())
+}
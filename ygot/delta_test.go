@@ -0,0 +1,137 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestPresenceBitmap(t *testing.T) {
+	var b PresenceBitmap
+	for _, i := range []int{0, 3, 64, 130} {
+		b.Set(i)
+	}
+	b.Clear(3)
+	b.Clear(1000)
+
+	for i, want := range map[int]bool{0: true, 1: false, 3: false, 64: true, 129: false, 130: true, 1000: false} {
+		if got := b.Has(i); got != want {
+			t.Errorf("Has(%d): got %v, want %v", i, got, want)
+		}
+	}
+
+	names := []string{"a", "b", "c", "d"}
+	b = nil
+	b.Set(1)
+	b.Set(3)
+	if diff := cmp.Diff([]string{"b", "d"}, b.Names(names)); diff != "" {
+		t.Errorf("Names: did not get expected names (-want, +got):\n%s", diff)
+	}
+}
+
+// renderExampleDelta is a hand-written delta type for renderExample of the
+// form generated for GoStructs.
+type renderExampleDelta struct {
+	Struct    *renderExample
+	ΛPresence PresenceBitmap
+}
+
+func (d *renderExampleDelta) ΛDeltaStruct() GoStruct { return d.Struct }
+
+func (d *renderExampleDelta) ΛPresentFields() []string {
+	return d.ΛPresence.Names([]string{"Str", "IntVal", "EnumField", "LeafList", "Missing"})
+}
+
+func TestDeltaNotification(t *testing.T) {
+	tests := []struct {
+		desc             string
+		inStruct         *renderExample
+		inPresent        []int
+		inPrefix         *gnmipb.Path
+		want             *gnmipb.Notification
+		wantErrSubstring string
+	}{{
+		desc:      "only present leaves",
+		inStruct:  &renderExample{Str: String("hello"), IntVal: Int32(42), LeafList: []string{"one"}},
+		inPresent: []int{0, 3},
+		inPrefix:  &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "prefix"}}},
+		want: &gnmipb.Notification{
+			Timestamp: 42,
+			Prefix:    &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "prefix"}}},
+			Update: []*gnmipb.Update{{
+				Path: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "str"}}},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: "hello"}},
+			}, {
+				Path: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "leaf-list"}}},
+				Val: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_LeaflistVal{LeaflistVal: &gnmipb.ScalarArray{
+					Element: []*gnmipb.TypedValue{{Value: &gnmipb.TypedValue_StringVal{StringVal: "one"}}},
+				}}},
+			}},
+		},
+	}, {
+		desc:      "enum explicitly set",
+		inStruct:  &renderExample{EnumField: EnumTestVALONE},
+		inPresent: []int{2},
+		want: &gnmipb.Notification{
+			Timestamp: 42,
+			Update: []*gnmipb.Update{{
+				Path: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "enum"}}},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: "VAL_ONE"}},
+			}},
+		},
+	}, {
+		desc:      "zero values explicitly set",
+		inStruct:  &renderExample{},
+		inPresent: []int{1, 2},
+		want: &gnmipb.Notification{
+			Timestamp: 42,
+			Delete: []*gnmipb.Path{
+				{Elem: []*gnmipb.PathElem{{Name: "int-val"}}},
+				{Elem: []*gnmipb.PathElem{{Name: "enum"}}},
+			},
+		},
+	}, {
+		desc:      "nil struct",
+		inPresent: []int{0},
+		want:      &gnmipb.Notification{Timestamp: 42},
+	}, {
+		desc:             "unknown field",
+		inStruct:         &renderExample{},
+		inPresent:        []int{4},
+		wantErrSubstring: "does not have a field named Missing",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			d := &renderExampleDelta{Struct: tt.inStruct}
+			for _, i := range tt.inPresent {
+				d.ΛPresence.Set(i)
+			}
+			got, err := DeltaNotification(d, 42, tt.inPrefix)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("DeltaNotification: %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("DeltaNotification: did not get expected notification (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -15,9 +15,11 @@
 package ygot
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/kylelemons/godebug/pretty"
@@ -104,6 +106,10 @@ func nodeValuePath(ni *util.NodeInfo, schemaPaths [][]string) (*pathSpec, error)
 		return nil, err
 	}
 
+	if isKeylessList(ni.Parent.FieldValue, ni.Parent.StructField) && util.IsValueStructPtr(ni.FieldValue) {
+		return nodeKeylessListPath(ni, cp)
+	}
+
 	if l, ok := ni.FieldValue.Interface().(KeyHelperGoStruct); ok {
 		return nodeMapPath(l, cp)
 	}
@@ -111,6 +117,48 @@ func nodeValuePath(ni *util.NodeInfo, schemaPaths [][]string) (*pathSpec, error)
 	return nodeChildPath(cp, schemaPaths)
 }
 
+// isKeylessList reports whether v, the value of the struct field f, is a
+// keyless list whose entries are identified by their position. Such lists are
+// represented as a slice of struct pointers in the generated code, and f has a
+// keyless-index tag.
+func isKeylessList(v reflect.Value, f reflect.StructField) bool {
+	return v.IsValid() && util.IsTypeSlice(v.Type()) && util.IsTypeStructPtr(v.Type().Elem()) && hasKeylessListIndex(f)
+}
+
+// nodeKeylessListPath returns the data tree path of the keyless list entry
+// described by ni, whose parent is the keyless list at parentPath. The entry
+// is identified by its position within the list, as described by
+// KeylessListIndexKey.
+func nodeKeylessListPath(ni *util.NodeInfo, parentPath *pathSpec) (*pathSpec, error) {
+	if parentPath == nil || parentPath.gNMIPaths == nil {
+		// we cannot have a list member that does not have a list parent.
+		return nil, fmt.Errorf("invalid list member with no parent")
+	}
+
+	list := ni.Parent.FieldValue
+	idx := -1
+	for i := 0; i < list.Len(); i++ {
+		if list.Index(i).Pointer() == ni.FieldValue.Pointer() {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return nil, fmt.Errorf("could not find entry %v within keyless list %s", ni.FieldValue.Interface(), ni.StructField.Name)
+	}
+
+	key := keylessListIndexKey(ni.StructField)
+	gPaths := []*gnmipb.Path{}
+	for _, p := range parentPath.gNMIPaths {
+		np := proto.Clone(p).(*gnmipb.Path)
+		np.Elem[len(p.Elem)-1].Key = map[string]string{key: strconv.Itoa(idx)}
+		gPaths = append(gPaths, np)
+	}
+	return &pathSpec{
+		gNMIPaths: gPaths,
+	}, nil
+}
+
 // nodeRootPath returns the gNMI path of a node at the root of a GoStruct tree -
 // since such nodes do not have a parent, then the path returned is entirely
 // gleaned from the schema path supplied.
@@ -172,7 +220,7 @@ func nodeMapPath(list KeyHelperGoStruct, parentPath *pathSpec) (*pathSpec, error
 	// Convert the keys into a string.
 	strkeys, err := keyMapAsStrings(keys)
 	if err != nil {
-		return nil, fmt.Errorf("cannot convert keys to map[string]string: %v", err)
+		return nil, fmt.Errorf("cannot convert keys to map[string]string: %w", err)
 	}
 
 	if parentPath == nil || parentPath.gNMIPaths == nil {
@@ -307,7 +355,7 @@ func findSetLeaves(s GoStruct, orderedMapAsLeaf bool, opts ...DiffOpt) (map[*pat
 		orderedMap, isOrderedMap := ival.(GoOrderedMap)
 
 		// Ignore non-data, or default data values.
-		if util.IsNilOrInvalidValue(ni.FieldValue) || util.IsValueNilOrDefault(ni.FieldValue.Interface()) || util.IsValueMap(ni.FieldValue) {
+		if util.IsNilOrInvalidValue(ni.FieldValue) || util.IsValueNilOrDefault(ni.FieldValue.Interface()) || util.IsValueMap(ni.FieldValue) || isKeylessList(ni.FieldValue, ni.StructField) {
 			return
 		}
 		// Ignore structs unless it is an ordered map and we're
@@ -390,7 +438,7 @@ func leastSpecificPath(paths [][]string) []string {
 func appendUpdate(n *gnmipb.Notification, path string, pathInfo *pathInfo) error {
 	v, err := EncodeTypedValue(pathInfo.val, gnmipb.Encoding_PROTO)
 	if err != nil {
-		return fmt.Errorf("cannot represent field value %v as TypedValue for path %v: %v", pathInfo.val, path, err)
+		return fmt.Errorf("cannot represent field value %v as TypedValue for path %v: %w", pathInfo.val, path, err)
 	}
 	n.Update = append(n.Update, &gnmipb.Update{
 		Path: pathInfo.path,
@@ -476,8 +524,13 @@ func (*DiffPathOpt) IsDiffOpt() {}
 // The returned gNMI Notification cannot be put on the wire unmodified, since
 // it does not specify a timestamp - and may not contain the absolute paths
 // to the fields specified if a GoStruct that does not represent the root of
-// a YANG schema tree is not supplied as original and modified.
+// a YANG schema tree is not supplied as original and modified. The
+// DiffNotificationOpt option can be used to stamp the Notification with a
+// timestamp and origin.
 func Diff(original, modified GoStruct, opts ...DiffOpt) (*gnmipb.Notification, error) {
+	if err := checkDiffNotificationOpt(opts); err != nil {
+		return nil, err
+	}
 	notifs, err := diff(original, modified, false, opts...)
 	switch len(notifs) {
 	case 0:
@@ -559,7 +612,10 @@ func FormatDiff(n *gnmipb.Notification) string {
 // The returned gNMI Notification cannot be put on the wire unmodified, since
 // it does not specify a timestamp - and may not contain the absolute paths
 // to the fields specified if a GoStruct that does not represent the root of
-// a YANG schema tree is not supplied as original and modified.
+// a YANG schema tree is not supplied as original and modified. The
+// DiffNotificationOpt option can be used to stamp the Notifications with a
+// timestamp and origin, and to return atomic Notifications for the
+// telemetry-atomic subtrees that it specifies.
 func DiffWithAtomic(original, modified GoStruct, opts ...DiffOpt) ([]*gnmipb.Notification, error) {
 	return diff(original, modified, true, opts...)
 }
@@ -587,11 +643,11 @@ func orderedMapLeaves(orderedMap GoOrderedMap, parent *gnmiPath, preferShadowPat
 			errs.Add(fmt.Errorf("%v: was not a valid GoStruct", parent))
 			return true
 		}
-		errs.Add(findUpdatedLeaves(&atomicLeaves, goStruct, childPath, preferShadowPath))
+		errs.Add(findUpdatedLeaves(context.Background(), &atomicLeaves, goStruct, childPath, preferShadowPath, 0))
 		return true
 	}); err != nil {
 		errs.Add(err)
-		return nil, nil, errs.Err()
+		return nil, nil, listErr(errs)
 	}
 
 	// TODO(wenbli): Make this more robust by potentially introducing another struct
@@ -607,7 +663,7 @@ func orderedMapLeaves(orderedMap GoOrderedMap, parent *gnmiPath, preferShadowPat
 		errs.Add(err)
 	}
 
-	return atomicLeaves, subtreePath, errs.Err()
+	return atomicLeaves, subtreePath, listErr(errs)
 }
 
 // orderedMapNotif returns an atomic Notification for the given ordered map.
@@ -657,46 +713,128 @@ func diff(original, modified GoStruct, withAtomic bool, opts ...DiffOpt) ([]*gnm
 		return nil, fmt.Errorf("cannot diff structs of different types, original: %T, modified: %T", original, modified)
 	}
 
-	origLeaves, err := findSetLeaves(original, withAtomic, opts...)
+	origRoot, modRoot := original, modified
+	subtree := hasDiffSubtreeOpt(opts)
+	var subtreeRoot *gnmipb.Path
+	if subtree != nil {
+		var walkOpts []WalkOpt
+		if po := hasDiffPathOpt(opts); po != nil && po.PreferShadowPath {
+			walkOpts = append(walkOpts, &WalkPreferShadowPath{})
+		}
+		var err error
+		if origRoot, modRoot, subtreeRoot, err = subtreeRoots(original, modified, subtree.Path, withAtomic, walkOpts); err != nil {
+			return nil, err
+		}
+	}
+
+	origLeaves, err := findSetLeaves(origRoot, withAtomic, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("could not extract set leaves from original struct: %v", err)
+		return nil, fmt.Errorf("could not extract set leaves from original struct: %w", err)
 	}
 
-	modLeaves, err := findSetLeaves(modified, withAtomic, opts...)
+	modLeaves, err := findSetLeaves(modRoot, withAtomic, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("could not extract set leaves from modified struct: %v", err)
+		return nil, fmt.Errorf("could not extract set leaves from modified struct: %w", err)
+	}
+
+	if subtree != nil {
+		origLeaves = subtreeLeaves(origLeaves, subtreeRoot, subtree.Path)
+		modLeaves = subtreeLeaves(modLeaves, subtreeRoot, subtree.Path)
 	}
 
 	origLeavesStr, err := toStringPathMap(origLeaves)
 	if err != nil {
-		return nil, fmt.Errorf("could not convert leaf path map to string path map: %v", err)
+		return nil, fmt.Errorf("could not convert leaf path map to string path map: %w", err)
 	}
 	modLeavesStr, err := toStringPathMap(modLeaves)
 	if err != nil {
-		return nil, fmt.Errorf("could not convert leaf path map to string path map: %v", err)
+		return nil, fmt.Errorf("could not convert leaf path map to string path map: %w", err)
+	}
+
+	diffopts := hasDiffPathOpt(opts)
+	preferShadowPath := diffopts != nil && diffopts.PreferShadowPath
+
+	// updateVal returns the value that modVal is to be updated to, taking
+	// into account whether it is a secret. secretPath is the absolute
+	// path of the leaf.
+	updateVal := func(modVal *pathInfo, secretPath *gnmipb.Path) *pathInfo {
+		if sp := hasSecretPlaceholders(opts); sp != nil {
+			if name, ok := secretName(sp.Store, []*gnmipb.Path{secretPath}); ok {
+				return &pathInfo{val: SecretPlaceholder(name), path: modVal.path}
+			}
+		}
+		return modVal
 	}
 
 	var atomicNotifs []*gnmipb.Notification
 	n := &gnmipb.Notification{}
 	processUpdate := func(path string, modVal *pathInfo) error {
 		if orderedMap, isOrderedMap := modVal.val.(GoOrderedMap); isOrderedMap {
-			diffopts := hasDiffPathOpt(opts)
-			preferShadowPath := diffopts != nil && diffopts.PreferShadowPath
-			notif, err := orderedMapNotif(orderedMap, newPathElemGNMIPath(modVal.path.GetElem()), 0, preferShadowPath)
+			atomicLeaves, subtreePath, err := orderedMapLeaves(orderedMap, newPathElemGNMIPath(modVal.path.GetElem()), preferShadowPath)
 			if err != nil {
 				return err
 			}
+			// The leaves of atomic updates are subject to the same
+			// secret replacement as non-atomic updates.
+			for i, pv := range atomicLeaves {
+				p, err := pv.path.p.ToProto()
+				if err != nil {
+					return err
+				}
+				in := &pathInfo{val: pv.val, path: p}
+				if v := updateVal(in, p); v != in {
+					atomicLeaves[i] = &pathval{path: pv.path, val: v.val}
+				}
+			}
+			var notif *gnmipb.Notification
+			if len(atomicLeaves) != 0 {
+				if notif, err = createAtomicNotif(atomicLeaves, 0, subtreePath); err != nil {
+					return err
+				}
+			}
 			atomicNotifs = append(atomicNotifs, notif)
 		} else {
 			// The contents of the value should indicate that value a has changed
 			// to value b.
-			if err := appendUpdate(n, path, modVal); err != nil {
+			if err := appendUpdate(n, path, updateVal(modVal, modVal.path)); err != nil {
 				return err
 			}
 		}
 		return nil
 	}
 
+	// Leaves within telemetry-atomic subtrees are compared per instance of
+	// the subtree, rather than individually.
+	notifOpt := hasDiffNotificationOpt(opts)
+	if withAtomic && notifOpt != nil && len(notifOpt.AtomicSubtrees) != 0 {
+		instances, err := splitAtomicSubtrees(notifOpt.AtomicSubtrees, origLeavesStr, modLeavesStr)
+		if err != nil {
+			return nil, err
+		}
+		keys := make([]string, 0, len(instances))
+		for k := range instances {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			in := instances[k]
+			switch {
+			case !in.changed():
+			case len(in.mod) == 0:
+				n.Delete = append(n.Delete, &gnmipb.Path{Elem: in.path.GetElem()})
+			default:
+				notif, err := in.notification(func(v *pathInfo) *pathInfo {
+					abs := &gnmipb.Path{Elem: append(append([]*gnmipb.PathElem{}, in.path.GetElem()...), v.path.GetElem()...)}
+					return updateVal(v, abs)
+				}, preferShadowPath)
+				if err != nil {
+					return nil, err
+				}
+				atomicNotifs = append(atomicNotifs, notif)
+			}
+		}
+	}
+
 	for origPath, origVal := range origLeavesStr {
 		if modVal, ok := modLeavesStr[origPath]; ok {
 			if !reflect.DeepEqual(origVal.val, modVal.val) {
@@ -730,9 +868,12 @@ func diff(original, modified GoStruct, withAtomic bool, opts ...DiffOpt) ([]*gnm
 		}
 	}
 
-	if len(n.Delete)+len(n.Update) == 0 {
-		return atomicNotifs, nil
+	notifs := atomicNotifs
+	if len(n.Delete)+len(n.Update) != 0 {
+		notifs = append([]*gnmipb.Notification{n}, atomicNotifs...)
 	}
-
-	return append([]*gnmipb.Notification{n}, atomicNotifs...), nil
+	if notifOpt != nil {
+		stampNotifications(notifOpt, notifs)
+	}
+	return notifs, nil
 }
@@ -795,3 +795,364 @@ func TestDiffOrderedMap(t *testing.T) {
 		})
 	}
 }
+
+func TestDiffSubtree(t *testing.T) {
+	device := func(motd string, entries map[string]string) *ctestschema.Device {
+		d := &ctestschema.Device{
+			OtherData:     &ctestschema.OtherData{Motd: ygot.String(motd)},
+			UnorderedList: map[string]*ctestschema.UnorderedList{},
+		}
+		for k, v := range entries {
+			d.UnorderedList[k] = &ctestschema.UnorderedList{Key: ygot.String(k), Value: ygot.String(v)}
+		}
+		return d
+	}
+	strVal := func(s string) *gnmipb.TypedValue {
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: s}}
+	}
+	orig := device("hello", map[string]string{"a": "one", "b": "two"})
+	mod := device("goodbye", map[string]string{"a": "three", "c": "four"})
+
+	tests := []struct {
+		desc   string
+		inPath string
+		want   *gnmipb.Notification
+	}{{
+		desc:   "list entry present in both structs",
+		inPath: "/unordered-lists/unordered-list[key=a]",
+		want: &gnmipb.Notification{
+			Update: []*gnmipb.Update{{
+				Path: mustPath("/unordered-lists/unordered-list[key=a]/config/value"),
+				Val:  strVal("three"),
+			}},
+		},
+	}, {
+		desc:   "container within list entry",
+		inPath: "/unordered-lists/unordered-list[key=a]/config",
+		want: &gnmipb.Notification{
+			Update: []*gnmipb.Update{{
+				Path: mustPath("/unordered-lists/unordered-list[key=a]/config/value"),
+				Val:  strVal("three"),
+			}},
+		},
+	}, {
+		desc:   "list entry deleted",
+		inPath: "/unordered-lists/unordered-list[key=b]",
+		want: &gnmipb.Notification{
+			Delete: []*gnmipb.Path{
+				mustPath("/unordered-lists/unordered-list[key=b]/config/key"),
+				mustPath("/unordered-lists/unordered-list[key=b]/key"),
+				mustPath("/unordered-lists/unordered-list[key=b]/config/value"),
+			},
+		},
+	}, {
+		desc:   "all list entries",
+		inPath: "/unordered-lists/unordered-list",
+		want: &gnmipb.Notification{
+			Delete: []*gnmipb.Path{
+				mustPath("/unordered-lists/unordered-list[key=b]/config/key"),
+				mustPath("/unordered-lists/unordered-list[key=b]/key"),
+				mustPath("/unordered-lists/unordered-list[key=b]/config/value"),
+			},
+			Update: []*gnmipb.Update{{
+				Path: mustPath("/unordered-lists/unordered-list[key=a]/config/value"),
+				Val:  strVal("three"),
+			}, {
+				Path: mustPath("/unordered-lists/unordered-list[key=c]/config/key"),
+				Val:  strVal("c"),
+			}, {
+				Path: mustPath("/unordered-lists/unordered-list[key=c]/key"),
+				Val:  strVal("c"),
+			}, {
+				Path: mustPath("/unordered-lists/unordered-list[key=c]/config/value"),
+				Val:  strVal("four"),
+			}},
+		},
+	}, {
+		desc:   "leaf",
+		inPath: "/other-data/config/motd",
+		want: &gnmipb.Notification{
+			Update: []*gnmipb.Update{{
+				Path: mustPath("/other-data/config/motd"),
+				Val:  strVal("goodbye"),
+			}},
+		},
+	}, {
+		desc:   "unpopulated subtree",
+		inPath: "/ordered-lists",
+		want:   &gnmipb.Notification{},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ygot.Diff(orig, mod, ygot.DiffSubtree(mustPath(tt.inPath)))
+			if err != nil {
+				t.Fatalf("Diff: got unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(got, tt.want, testutil.NotificationComparer()); diff != "" {
+				t.Errorf("Diff: did not get expected Notification (-got, +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiffWithAtomicSubtree(t *testing.T) {
+	orig := &ctestschema.Device{
+		OrderedList: ctestschema.GetOrderedMap(t),
+		OtherData:   &ctestschema.OtherData{Motd: ygot.String("hello")},
+	}
+	mod := &ctestschema.Device{
+		OrderedList: ctestschema.GetOrderedMapLonger(t),
+		OtherData:   &ctestschema.OtherData{Motd: ygot.String("goodbye")},
+	}
+
+	// An ordered list is compared as a whole when the subtree is within it.
+	got, err := ygot.DiffWithAtomic(orig, mod, ygot.DiffSubtree(mustPath("/ordered-lists/ordered-list[key=foo]")))
+	if err != nil {
+		t.Fatalf("DiffWithAtomic: got unexpected error: %v", err)
+	}
+	if len(got) != 1 || !got[0].GetAtomic() {
+		t.Fatalf("DiffWithAtomic: got %v, want a single atomic Notification", got)
+	}
+	if diff := cmp.Diff(got[0].GetPrefix(), mustPath("/ordered-lists"), protocmp.Transform()); diff != "" {
+		t.Errorf("DiffWithAtomic: did not get expected prefix (-got, +want):\n%s", diff)
+	}
+
+	got, err = ygot.DiffWithAtomic(orig, mod, ygot.DiffSubtree(mustPath("/other-data")))
+	if err != nil {
+		t.Fatalf("DiffWithAtomic: got unexpected error: %v", err)
+	}
+	want := []*gnmipb.Notification{{
+		Update: []*gnmipb.Update{{
+			Path: mustPath("/other-data/config/motd"),
+			Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: "goodbye"}},
+		}},
+	}}
+	if diff := cmp.Diff(got, want, protocmp.Transform()); diff != "" {
+		t.Errorf("DiffWithAtomic: did not get expected Notifications (-got, +want):\n%s", diff)
+	}
+}
+
+// orderedListSecretStore is a SecretStore in which the value of each entry of
+// the ordered list of ctestschema is a secret named by the key of the entry.
+type orderedListSecretStore struct{}
+
+func (orderedListSecretStore) SecretName(p *gnmipb.Path) (string, bool) {
+	if len(p.GetElem()) != 4 || p.GetElem()[1].GetName() != "ordered-list" || p.GetElem()[3].GetName() != "value" {
+		return "", false
+	}
+	return "value-" + p.GetElem()[1].GetKey()["key"], true
+}
+
+func (orderedListSecretStore) SecretValue(name string) (string, error) { return name, nil }
+
+func TestDiffWithAtomicSecretPlaceholders(t *testing.T) {
+	orig := &ctestschema.Device{OrderedList: ctestschema.GetOrderedMap(t)}
+	mod := &ctestschema.Device{OrderedList: ctestschema.GetOrderedMapLonger(t)}
+
+	got, err := ygot.DiffWithAtomic(orig, mod, &ygot.SecretPlaceholders{Store: orderedListSecretStore{}})
+	if err != nil {
+		t.Fatalf("DiffWithAtomic: got unexpected error: %v", err)
+	}
+	if len(got) != 1 || !got[0].GetAtomic() {
+		t.Fatalf("DiffWithAtomic: got %v, want a single atomic Notification", got)
+	}
+	var values int
+	for _, u := range got[0].GetUpdate() {
+		elems := u.GetPath().GetElem()
+		if elems[len(elems)-1].GetName() != "value" {
+			continue
+		}
+		values++
+		want := ygot.SecretPlaceholder("value-" + elems[0].GetKey()["key"])
+		if got := u.GetVal().GetStringVal(); got != want {
+			t.Errorf("DiffWithAtomic: got value %q for %v, want %q", got, u.GetPath(), want)
+		}
+	}
+	if values != 3 {
+		t.Errorf("DiffWithAtomic: got %d values, want 3", values)
+	}
+}
+
+func TestDiffNotificationOpt(t *testing.T) {
+	device := func(motd string, entries map[string]string) *ctestschema.Device {
+		d := &ctestschema.Device{
+			OtherData:     &ctestschema.OtherData{Motd: ygot.String(motd)},
+			UnorderedList: map[string]*ctestschema.UnorderedList{},
+		}
+		for k, v := range entries {
+			d.UnorderedList[k] = &ctestschema.UnorderedList{Key: ygot.String(k), Value: ygot.String(v)}
+		}
+		return d
+	}
+	strVal := func(s string) *gnmipb.TypedValue {
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: s}}
+	}
+	originPath := func(s string) *gnmipb.Path {
+		p := mustPath(s)
+		p.Origin = "openconfig"
+		return p
+	}
+	entryUpdates := func(key, value string) []*gnmipb.Update {
+		return []*gnmipb.Update{{
+			Path: mustPath("config/key"),
+			Val:  strVal(key),
+		}, {
+			Path: mustPath("config/value"),
+			Val:  strVal(value),
+		}, {
+			Path: mustPath("key"),
+			Val:  strVal(key),
+		}}
+	}
+	orig := device("hello", map[string]string{"a": "one", "b": "two", "d": "five"})
+	mod := device("goodbye", map[string]string{"a": "three", "c": "four", "d": "five"})
+
+	tests := []struct {
+		desc   string
+		inOpts []ygot.DiffOpt
+		want   []*gnmipb.Notification
+	}{{
+		desc: "timestamp",
+		inOpts: []ygot.DiffOpt{
+			&ygot.DiffNotificationOpt{Timestamp: 42},
+			ygot.DiffSubtree(mustPath("/other-data")),
+		},
+		want: []*gnmipb.Notification{{
+			Timestamp: 42,
+			Update: []*gnmipb.Update{{
+				Path: mustPath("/other-data/config/motd"),
+				Val:  strVal("goodbye"),
+			}},
+		}},
+	}, {
+		desc: "timestamp and origin",
+		inOpts: []ygot.DiffOpt{
+			&ygot.DiffNotificationOpt{Timestamp: 42, Origin: "openconfig"},
+			ygot.DiffSubtree(mustPath("/other-data")),
+		},
+		want: []*gnmipb.Notification{{
+			Timestamp: 42,
+			Update: []*gnmipb.Update{{
+				Path: originPath("/other-data/config/motd"),
+				Val:  strVal("goodbye"),
+			}},
+		}},
+	}, {
+		desc: "atomic subtrees",
+		inOpts: []ygot.DiffOpt{&ygot.DiffNotificationOpt{
+			Timestamp:      42,
+			Origin:         "openconfig",
+			AtomicSubtrees: []*gnmipb.Path{mustPath("/unordered-lists/unordered-list")},
+		}},
+		want: []*gnmipb.Notification{{
+			Timestamp: 42,
+			Update: []*gnmipb.Update{{
+				Path: originPath("/other-data/config/motd"),
+				Val:  strVal("goodbye"),
+			}},
+			Delete: []*gnmipb.Path{
+				originPath("/unordered-lists/unordered-list[key=b]"),
+			},
+		}, {
+			Timestamp: 42,
+			Atomic:    true,
+			Prefix:    originPath("/unordered-lists/unordered-list[key=a]"),
+			Update:    entryUpdates("a", "three"),
+		}, {
+			Timestamp: 42,
+			Atomic:    true,
+			Prefix:    originPath("/unordered-lists/unordered-list[key=c]"),
+			Update:    entryUpdates("c", "four"),
+		}},
+	}, {
+		desc: "atomic subtree with keys",
+		inOpts: []ygot.DiffOpt{&ygot.DiffNotificationOpt{
+			AtomicSubtrees: []*gnmipb.Path{mustPath("/unordered-lists/unordered-list[key=a]")},
+		}},
+		want: []*gnmipb.Notification{{
+			Update: []*gnmipb.Update{{
+				Path: mustPath("/other-data/config/motd"),
+				Val:  strVal("goodbye"),
+			}, {
+				Path: mustPath("/unordered-lists/unordered-list[key=c]/config/key"),
+				Val:  strVal("c"),
+			}, {
+				Path: mustPath("/unordered-lists/unordered-list[key=c]/config/value"),
+				Val:  strVal("four"),
+			}, {
+				Path: mustPath("/unordered-lists/unordered-list[key=c]/key"),
+				Val:  strVal("c"),
+			}},
+			Delete: []*gnmipb.Path{
+				mustPath("/unordered-lists/unordered-list[key=b]/config/key"),
+				mustPath("/unordered-lists/unordered-list[key=b]/config/value"),
+				mustPath("/unordered-lists/unordered-list[key=b]/key"),
+			},
+		}, {
+			Atomic: true,
+			Prefix: mustPath("/unordered-lists/unordered-list[key=a]"),
+			Update: entryUpdates("a", "three"),
+		}},
+	}}
+
+	sortPaths := protocmp.SortRepeated(func(a, b *gnmipb.Path) bool {
+		as, _ := ygot.PathToString(a)
+		bs, _ := ygot.PathToString(b)
+		return as < bs
+	})
+	sortUpdates := protocmp.SortRepeated(func(a, b *gnmipb.Update) bool {
+		as, _ := ygot.PathToString(a.GetPath())
+		bs, _ := ygot.PathToString(b.GetPath())
+		return as < bs
+	})
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ygot.DiffWithAtomic(orig, mod, tt.inOpts...)
+			if err != nil {
+				t.Fatalf("DiffWithAtomic: got unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(got, tt.want, protocmp.Transform(), sortPaths, sortUpdates); diff != "" {
+				t.Errorf("DiffWithAtomic: did not get expected Notifications (-got, +want):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("ordered map within atomic subtree", func(t *testing.T) {
+		got, err := ygot.DiffWithAtomic(&ctestschema.Device{}, &ctestschema.Device{OrderedList: ctestschema.GetOrderedMap(t)}, &ygot.DiffNotificationOpt{
+			AtomicSubtrees: []*gnmipb.Path{mustPath("/ordered-lists")},
+		})
+		if err != nil {
+			t.Fatalf("DiffWithAtomic: got unexpected error: %v", err)
+		}
+		want, err := ygot.DiffWithAtomic(&ctestschema.Device{}, &ctestschema.Device{OrderedList: ctestschema.GetOrderedMap(t)})
+		if err != nil {
+			t.Fatalf("DiffWithAtomic: got unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(got, want, protocmp.Transform()); diff != "" {
+			t.Errorf("DiffWithAtomic: did not get expected Notifications (-got, +want):\n%s", diff)
+		}
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		got, err := ygot.Diff(orig, mod, &ygot.DiffNotificationOpt{Timestamp: 42}, ygot.DiffSubtree(mustPath("/other-data")))
+		if err != nil {
+			t.Fatalf("Diff: got unexpected error: %v", err)
+		}
+		want := &gnmipb.Notification{
+			Timestamp: 42,
+			Update: []*gnmipb.Update{{
+				Path: mustPath("/other-data/config/motd"),
+				Val:  strVal("goodbye"),
+			}},
+		}
+		if diff := cmp.Diff(got, want, protocmp.Transform()); diff != "" {
+			t.Errorf("Diff: did not get expected Notification (-got, +want):\n%s", diff)
+		}
+
+		_, err = ygot.Diff(orig, mod, &ygot.DiffNotificationOpt{AtomicSubtrees: []*gnmipb.Path{mustPath("/unordered-lists/unordered-list")}})
+		if diff := errdiff.Substring(err, "atomic subtrees can only be used with DiffWithAtomic"); diff != "" {
+			t.Errorf("Diff: did not get expected error, %s", diff)
+		}
+	})
+}
@@ -0,0 +1,221 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/openconfig/ygot/util"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// DiffNotificationOpt is a DiffOpt that populates the Notifications returned
+// by Diff and DiffWithAtomic such that they can be sent within gNMI
+// SubscribeResponse messages without further processing, for example by a
+// gNMI server that serves ON_CHANGE subscriptions from changes to its intended
+// configuration.
+type DiffNotificationOpt struct {
+	// Timestamp is the timestamp, in nanoseconds since the Unix epoch, that
+	// each returned Notification is stamped with.
+	Timestamp int64
+	// Origin is the origin that the path of each update and delete within
+	// the returned Notifications is qualified with. For atomic
+	// Notifications, whose paths are relative to their prefix, the origin
+	// is set on the prefix.
+	Origin string
+	// AtomicSubtrees are the paths, relative to the supplied GoStructs, of
+	// the nodes that are marked telemetry-atomic. Where a leaf within an
+	// instance of such a subtree has changed, an atomic Notification is
+	// returned whose prefix is the path of the instance, and which contains
+	// every leaf within the instance in the modified GoStruct. Where the
+	// instance does not exist within the modified GoStruct, its path is
+	// deleted within the non-atomic Notification. The keys of list
+	// elements may be omitted or be wildcards, in which case every entry of
+	// the list is matched. AtomicSubtrees can only be used with
+	// DiffWithAtomic, since Diff returns a single Notification.
+	AtomicSubtrees []*gnmipb.Path
+}
+
+// IsDiffOpt marks DiffNotificationOpt as a diff option.
+func (*DiffNotificationOpt) IsDiffOpt() {}
+
+// hasDiffNotificationOpt returns the first DiffNotificationOpt from an opts
+// slice, or nil if there isn't one.
+func hasDiffNotificationOpt(opts []DiffOpt) *DiffNotificationOpt {
+	for _, o := range opts {
+		switch v := o.(type) {
+		case *DiffNotificationOpt:
+			return v
+		}
+	}
+	return nil
+}
+
+// atomicSubtreeInstance returns the path of the instance of the outermost
+// atomic subtree in subtrees that contains path, and true if there is one.
+func atomicSubtreeInstance(subtrees []*gnmipb.Path, path *gnmipb.Path) (*gnmipb.Path, bool) {
+	var instance *gnmipb.Path
+	for _, s := range subtrees {
+		query := &gnmipb.Path{Elem: s.GetElem()}
+		if !util.PathMatchesQuery(&gnmipb.Path{Elem: path.GetElem()}, query) {
+			continue
+		}
+		if instance == nil || len(query.Elem) < len(instance.Elem) {
+			instance = &gnmipb.Path{Elem: path.GetElem()[:len(query.Elem)]}
+		}
+	}
+	return instance, instance != nil
+}
+
+// atomicSubtreeLeaves holds the leaves of an instance of an atomic subtree
+// within the original and modified GoStructs.
+type atomicSubtreeLeaves struct {
+	path      *gnmipb.Path
+	orig, mod map[string]*pathInfo
+}
+
+// splitAtomicSubtrees removes the leaves that are within instances of the
+// supplied atomic subtrees from origLeaves and modLeaves, and returns them
+// grouped by instance, keyed by the string path of the instance.
+func splitAtomicSubtrees(subtrees []*gnmipb.Path, origLeaves, modLeaves map[string]*pathInfo) (map[string]*atomicSubtreeLeaves, error) {
+	instances := map[string]*atomicSubtreeLeaves{}
+	split := func(leaves map[string]*pathInfo, isMod bool) error {
+		for p, v := range leaves {
+			ip, ok := atomicSubtreeInstance(subtrees, v.path)
+			if !ok {
+				continue
+			}
+			key, err := PathToString(ip)
+			if err != nil {
+				return err
+			}
+			in, ok := instances[key]
+			if !ok {
+				in = &atomicSubtreeLeaves{path: ip, orig: map[string]*pathInfo{}, mod: map[string]*pathInfo{}}
+				instances[key] = in
+			}
+			if isMod {
+				in.mod[p] = v
+			} else {
+				in.orig[p] = v
+			}
+			delete(leaves, p)
+		}
+		return nil
+	}
+	if err := split(origLeaves, false); err != nil {
+		return nil, err
+	}
+	if err := split(modLeaves, true); err != nil {
+		return nil, err
+	}
+	return instances, nil
+}
+
+// changed reports whether the leaves of the atomic subtree instance differ
+// between the original and modified GoStructs.
+func (a *atomicSubtreeLeaves) changed() bool {
+	if len(a.orig) != len(a.mod) {
+		return true
+	}
+	for p, o := range a.orig {
+		m, ok := a.mod[p]
+		if !ok || !reflect.DeepEqual(o.val, m.val) {
+			return true
+		}
+	}
+	return false
+}
+
+// notification returns an atomic Notification containing each of the leaves
+// of the atomic subtree instance within the modified GoStruct, with paths
+// relative to the instance. The value of each leaf is determined by updateVal.
+func (a *atomicSubtreeLeaves) notification(updateVal func(*pathInfo) *pathInfo, preferShadowPath bool) (*gnmipb.Notification, error) {
+	n := &gnmipb.Notification{
+		Prefix: &gnmipb.Path{Elem: a.path.GetElem()},
+		Atomic: true,
+	}
+	relPath := func(p *gnmipb.Path) *gnmipb.Path {
+		return &gnmipb.Path{Elem: p.GetElem()[len(a.path.GetElem()):]}
+	}
+
+	paths := make([]string, 0, len(a.mod))
+	for p := range a.mod {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		v := a.mod[p]
+		orderedMap, isOrderedMap := v.val.(GoOrderedMap)
+		if !isOrderedMap {
+			if err := appendUpdate(n, p, updateVal(&pathInfo{val: v.val, path: relPath(v.path)})); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		// The entries of an ordered map within the subtree are expanded
+		// into their leaves, in the order of the map.
+		leaves, _, err := orderedMapLeaves(orderedMap, newPathElemGNMIPath(v.path.GetElem()), preferShadowPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range leaves {
+			lp, err := l.path.p.ToProto()
+			if err != nil {
+				return nil, err
+			}
+			if err := appendUpdate(n, p, updateVal(&pathInfo{val: l.val, path: relPath(lp)})); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// stampNotifications sets the timestamp and origin specified by o within the
+// supplied Notifications.
+func stampNotifications(o *DiffNotificationOpt, notifs []*gnmipb.Notification) {
+	for _, n := range notifs {
+		n.Timestamp = o.Timestamp
+		if o.Origin == "" {
+			continue
+		}
+		if n.Atomic {
+			if n.Prefix == nil {
+				n.Prefix = &gnmipb.Path{}
+			}
+			n.Prefix.Origin = o.Origin
+			continue
+		}
+		for _, u := range n.Update {
+			u.Path.Origin = o.Origin
+		}
+		for _, d := range n.Delete {
+			d.Origin = o.Origin
+		}
+	}
+}
+
+// checkDiffNotificationOpt returns an error if the DiffNotificationOpt within
+// opts cannot be used with Diff.
+func checkDiffNotificationOpt(opts []DiffOpt) error {
+	if o := hasDiffNotificationOpt(opts); o != nil && len(o.AtomicSubtrees) != 0 {
+		return fmt.Errorf("atomic subtrees can only be used with DiffWithAtomic")
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+	"maps"
+
+	"google.golang.org/protobuf/proto"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// DiffSubtreeOpt is a DiffOpt that restricts the comparison performed by Diff
+// to the subtree of the supplied GoStructs at Path, which is relative to the
+// GoStructs. Only the parts of the GoStructs that are on the path to the
+// subtree are traversed before the subtree itself is compared, such that the
+// cost of the comparison is proportional to the size of the subtree. The paths
+// within the returned Notification remain relative to the supplied GoStructs.
+//
+// The keys of list elements within Path may be omitted, in which case the
+// subtrees of all entries of the list are compared. The origin and target of
+// Path are ignored.
+type DiffSubtreeOpt struct {
+	// Path is the path of the subtree to be compared.
+	Path *gnmipb.Path
+}
+
+// IsDiffOpt marks DiffSubtreeOpt as a diff option.
+func (*DiffSubtreeOpt) IsDiffOpt() {}
+
+// DiffSubtree returns a DiffOpt that restricts the comparison performed by
+// Diff to the subtree at path, as described by DiffSubtreeOpt.
+func DiffSubtree(path *gnmipb.Path) *DiffSubtreeOpt {
+	return &DiffSubtreeOpt{Path: path}
+}
+
+// hasDiffSubtreeOpt returns the first DiffSubtreeOpt from an opts slice, or
+// nil if there isn't one.
+func hasDiffSubtreeOpt(opts []DiffOpt) *DiffSubtreeOpt {
+	for _, o := range opts {
+		switch v := o.(type) {
+		case *DiffSubtreeOpt:
+			return v
+		}
+	}
+	return nil
+}
+
+// subtreeRoots returns the GoStructs within original and modified from which
+// the leaves of the subtree at path are to be found, along with their path,
+// which is the deepest path of a container or list entry that is an ancestor
+// of, or equal to, path and is populated in both original and modified. If
+// orderedMapAsLeaf is set, ordered lists are not traversed, since they are
+// compared as a single leaf.
+func subtreeRoots(original, modified GoStruct, path *gnmipb.Path, orderedMapAsLeaf bool, opts []WalkOpt) (GoStruct, GoStruct, *gnmipb.Path, error) {
+	origNodes, err := subtreeAncestors(original, path, orderedMapAsLeaf, opts)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot find subtree in original struct: %v", err)
+	}
+	modNodes, err := subtreeAncestors(modified, path, orderedMapAsLeaf, opts)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot find subtree in modified struct: %v", err)
+	}
+
+	// The root of the GoStructs is always an ancestor of the subtree.
+	origRoot, modRoot, rootPath := original, modified, &gnmipb.Path{}
+	for p, on := range origNodes {
+		mn, ok := modNodes[p]
+		if !ok || len(on.path.GetElem()) <= len(rootPath.GetElem()) {
+			continue
+		}
+		origRoot, modRoot, rootPath = on.node, mn.node, on.path
+	}
+	return origRoot, modRoot, rootPath, nil
+}
+
+// subtreeNode is a container or list entry within a GoStruct.
+type subtreeNode struct {
+	// node is the GoStruct representing the container or list entry.
+	node GoStruct
+	// path is the path of the node within the GoStruct.
+	path *gnmipb.Path
+}
+
+// subtreeAncestors returns the containers and list entries of s whose paths
+// are ancestors of, or equal to, path, keyed by the string form of their path.
+// If orderedMapAsLeaf is set, ordered lists are not traversed.
+func subtreeAncestors(s GoStruct, path *gnmipb.Path, orderedMapAsLeaf bool, opts []WalkOpt) (map[string]*subtreeNode, error) {
+	nodes := map[string]*subtreeNode{}
+	err := Walk(s, func(p *gnmipb.Path, node any) error {
+		elems := p.GetElem()
+		if len(elems) == 0 {
+			return nil
+		}
+		if len(elems) > len(path.GetElem()) || !subtreePathsMatch(elems, path.GetElem()) {
+			return SkipSubtree
+		}
+		switch n := node.(type) {
+		case GoOrderedMap:
+			if orderedMapAsLeaf {
+				return SkipSubtree
+			}
+		case GoStruct:
+			// List entries are only ancestors of the subtree if the
+			// subtree path specifies their keys.
+			for i, e := range elems {
+				if !maps.Equal(e.GetKey(), path.GetElem()[i].GetKey()) {
+					return SkipSubtree
+				}
+			}
+			ps, err := PathToString(p)
+			if err != nil {
+				return err
+			}
+			nodes[ps] = &subtreeNode{node: n, path: proto.Clone(p).(*gnmipb.Path)}
+		}
+		return nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// subtreePathsMatch reports whether the paths a and b are equal over the
+// length of the shorter of them, such that one is an ancestor of, or equal
+// to, the other. The keys of an element are only compared if they are
+// specified in both paths.
+func subtreePathsMatch(a, b []*gnmipb.PathElem) bool {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i].GetName() != b[i].GetName() {
+			return false
+		}
+		if len(a[i].GetKey()) != 0 && len(b[i].GetKey()) != 0 && !maps.Equal(a[i].GetKey(), b[i].GetKey()) {
+			return false
+		}
+	}
+	return true
+}
+
+// subtreeLeaves returns the leaves, as returned by findSetLeaves for the
+// GoStruct at root, that are within the subtree at path, with their paths
+// prefixed by root such that they are relative to the GoStruct from which the
+// subtree was resolved.
+func subtreeLeaves(leaves map[*pathSpec]interface{}, root, path *gnmipb.Path) map[*pathSpec]interface{} {
+	out := map[*pathSpec]interface{}{}
+	for ps, v := range leaves {
+		var paths []*gnmipb.Path
+		for _, p := range ps.gNMIPaths {
+			fp := &gnmipb.Path{}
+			for _, e := range root.GetElem() {
+				fp.Elem = append(fp.Elem, proto.Clone(e).(*gnmipb.PathElem))
+			}
+			fp.Elem = append(fp.Elem, p.GetElem()...)
+			if subtreePathsMatch(fp.GetElem(), path.GetElem()) {
+				paths = append(paths, fp)
+			}
+		}
+		if len(paths) != 0 {
+			out[&pathSpec{gNMIPaths: paths}] = v
+		}
+	}
+	return out
+}
@@ -1553,6 +1553,32 @@ func TestDiff(t *testing.T) {
 				},
 			}},
 		},
+	}, {
+		desc: "keyless list entries identified by position",
+		inOrig: &renderExample{
+			IndexedKeylessList: []*renderExampleList{
+				{Val: String("trillian")},
+				{Val: String("arthur")},
+			},
+		},
+		inMod: &renderExample{
+			IndexedKeylessList: []*renderExampleList{
+				{Val: String("arthur")},
+			},
+		},
+		want: &gnmipb.Notification{
+			Update: []*gnmipb.Update{{
+				Path: &gnmipb.Path{Elem: mustPathElem("indexed-keyless-list[_index=0]/val")},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{"arthur"}},
+			}, {
+				Path: &gnmipb.Path{Elem: mustPathElem("indexed-keyless-list[_index=0]/state/val")},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{"arthur"}},
+			}},
+			Delete: []*gnmipb.Path{
+				{Elem: mustPathElem("indexed-keyless-list[_index=1]/val")},
+				{Elem: mustPathElem("indexed-keyless-list[_index=1]/state/val")},
+			},
+		},
 	}}
 
 	for _, tt := range tests {
@@ -0,0 +1,127 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"errors"
+
+	"github.com/openconfig/gnmi/errlist"
+)
+
+// The following errors identify the classes of the errors returned by ygot
+// and ytypes, such that callers can determine the class of an error using
+// errors.Is, rather than by matching its message. The errors returned are
+// not equal to these errors, but wrap them.
+var (
+	// ErrInvalidPath is the class of errors returned when a path cannot be
+	// parsed, or is not well-formed.
+	ErrInvalidPath = errors.New("invalid path")
+	// ErrUnsetKey is the class of errors returned when a key of a list
+	// entry is not set.
+	ErrUnsetKey = errors.New("unset list key")
+	// ErrUnknownEnumValue is the class of errors returned when an
+	// enumerated value, or its name, is not defined by its enumerated type.
+	ErrUnknownEnumValue = errors.New("unknown enumerated value")
+	// ErrOlderGeneratedCode is the class of errors returned when a GoStruct
+	// does not implement a generated method that is required, because it
+	// was generated by an older version of ygot.
+	ErrOlderGeneratedCode = errors.New("GoStruct generated by an older version of ygot")
+)
+
+// SchemaMismatchError is the error returned when data does not correspond to
+// the schema at a particular path, e.g., when JSON that is unmarshalled
+// contains a field that does not exist in the schema.
+type SchemaMismatchError struct {
+	// Path is the schema path at which the mismatch was found.
+	Path string
+	// Err describes the mismatch.
+	Err error
+}
+
+// Error implements the error interface. The message is that of Err, such
+// that the messages of existing errors are unchanged by their being reported
+// as a SchemaMismatchError.
+func (e *SchemaMismatchError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error describing the mismatch.
+func (e *SchemaMismatchError) Unwrap() error {
+	return e.Err
+}
+
+// ClassifyError returns an error that has the same message as err, and which
+// wraps both class, which should be one of the error classes defined by this
+// package, and err. This allows the class of an error to be determined using
+// errors.Is without changing its message. It returns nil if err is nil.
+func ClassifyError(class, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{class: class, err: err}
+}
+
+// classifiedError is an error that belongs to a class of errors.
+type classifiedError struct {
+	// class is the class of the error.
+	class error
+	// err is the underlying error.
+	err error
+}
+
+// Error implements the error interface, returning the message of the
+// underlying error.
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the class and the underlying error.
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.class, e.err}
+}
+
+// unwrappableErrList is an errlist.Error which can be unwrapped to the errors
+// that it contains, such that errors.Is and errors.As match an error if any of
+// the errors within the list match it.
+type unwrappableErrList struct {
+	el errlist.Error
+}
+
+// Error implements the error interface.
+func (e unwrappableErrList) Error() string {
+	return e.el.Error()
+}
+
+// Errors implements the errlist.Errors interface, returning the errors within
+// the list.
+func (e unwrappableErrList) Errors() []error {
+	return e.el.Errors()
+}
+
+// Unwrap returns the errors within the list.
+func (e unwrappableErrList) Unwrap() []error {
+	return e.el.Errors()
+}
+
+// listErr returns the error for the supplied list of errors, or nil if it
+// contains no errors. The returned error can be unwrapped to the errors within
+// the list.
+func listErr(errs interface{ Err() error }) error {
+	err := errs.Err()
+	if el, ok := err.(errlist.Error); ok {
+		return unwrappableErrList{el: el}
+	}
+	return err
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestClassifyError(t *testing.T) {
+	if err := ClassifyError(ErrInvalidPath, nil); err != nil {
+		t.Errorf("ClassifyError(nil): got %v, want nil", err)
+	}
+
+	inner := errors.New("inner")
+	err := ClassifyError(ErrUnsetKey, fmt.Errorf("outer: %w", inner))
+	if got, want := err.Error(), "outer: inner"; got != want {
+		t.Errorf("ClassifyError: got message %q, want %q", got, want)
+	}
+	for _, target := range []error{ErrUnsetKey, inner} {
+		if !errors.Is(err, target) {
+			t.Errorf("errors.Is(%v, %v): got false, want true", err, target)
+		}
+	}
+	if errors.Is(err, ErrInvalidPath) {
+		t.Errorf("errors.Is(%v, %v): got true, want false", err, ErrInvalidPath)
+	}
+}
+
+func TestSchemaMismatchError(t *testing.T) {
+	err := fmt.Errorf("unmarshal failed: %w", &SchemaMismatchError{Path: "/a/b", Err: errors.New("unexpected field c")})
+	var sme *SchemaMismatchError
+	if !errors.As(err, &sme) {
+		t.Fatalf("errors.As(%v): did not find SchemaMismatchError", err)
+	}
+	if got, want := sme.Path, "/a/b"; got != want {
+		t.Errorf("SchemaMismatchError: got path %s, want %s", got, want)
+	}
+	if got, want := err.Error(), "unmarshal failed: unexpected field c"; got != want {
+		t.Errorf("SchemaMismatchError: got message %q, want %q", got, want)
+	}
+}
+
+func TestErrorClasses(t *testing.T) {
+	tests := []struct {
+		desc      string
+		inFn      func() error
+		wantClass error
+	}{{
+		desc: "invalid string path",
+		inFn: func() error {
+			_, err := StringToStructuredPath("/a/b[c=]")
+			return err
+		},
+		wantClass: ErrInvalidPath,
+	}, {
+		desc: "invalid string slice path",
+		inFn: func() error {
+			_, err := StringToStringSlicePath("/a/b[c]d")
+			return err
+		},
+		wantClass: ErrInvalidPath,
+	}, {
+		desc: "invalid structured path",
+		inFn: func() error {
+			_, err := PathToString(&gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: ""}}})
+			return err
+		},
+		wantClass: ErrInvalidPath,
+	}, {
+		desc: "unset key",
+		inFn: func() error {
+			_, err := PathKeyFromStruct(reflect.ValueOf(&pathElemUnserialisable{}))
+			return err
+		},
+		wantClass: ErrUnsetKey,
+	}, {
+		desc: "unset key within rendering",
+		inFn: func() error {
+			_, err := ConstructIETFJSON(&renderExample{
+				EnumList: map[EnumTest]*renderExampleEnumList{
+					EnumTestUNSET: {Key: EnumTestUNSET},
+				},
+			}, nil)
+			return err
+		},
+		wantClass: ErrUnsetKey,
+	}, {
+		desc: "unknown enum value",
+		inFn: func() error {
+			_, err := EnumName(EnumTest(42))
+			return err
+		},
+		wantClass: ErrUnknownEnumValue,
+	}, {
+		desc: "unknown enum value within rendering",
+		inFn: func() error {
+			_, err := ConstructIETFJSON(&renderExample{EnumField: EnumTest(42)}, nil)
+			return err
+		},
+		wantClass: ErrUnknownEnumValue,
+	}, {
+		desc: "unknown enum name",
+		inFn: func() error {
+			_, err := DecodeTypedValue[EnumTest](&gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: "VAL_FORTY_TWO"}})
+			return err
+		},
+		wantClass: ErrUnknownEnumValue,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := tt.inFn()
+			if err == nil {
+				t.Fatalf("did not get expected error")
+			}
+			if !errors.Is(err, tt.wantClass) {
+				t.Errorf("errors.Is(%v, %v): got false, want true", err, tt.wantClass)
+			}
+		})
+	}
+}
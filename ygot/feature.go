@@ -0,0 +1,190 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/openconfig/ygot/util"
+)
+
+// Logger is the interface through which the ygot libraries log messages,
+// including the warnings about the stages of features.
+type Logger = util.Logger
+
+// SetLogger sets the Logger through which the ygot libraries log messages.
+// If l is nil, the default Logger, which uses glog, is restored.
+func SetLogger(l Logger) {
+	util.SetLogger(l)
+}
+
+// FeatureStage is the stage of the rollout of a feature, which changes the
+// behaviour of the ygot libraries, between releases.
+type FeatureStage int
+
+const (
+	// FeatureOptIn is the stage of a feature that is disabled unless it
+	// is explicitly enabled.
+	FeatureOptIn FeatureStage = iota
+	// FeatureWarnDefault is the stage of a feature that is disabled unless
+	// it is explicitly enabled, and that is to be enabled by default in a
+	// future release. When the behaviour of the feature is relied upon
+	// while it is not explicitly set, a warning is logged.
+	FeatureWarnDefault
+	// FeatureDefault is the stage of a feature that is enabled unless it
+	// is explicitly disabled. When the behaviour of the feature is relied
+	// upon while it is explicitly disabled, a warning is logged that the
+	// previous behaviour is deprecated.
+	FeatureDefault
+)
+
+// String returns a human readable name for the FeatureStage.
+func (s FeatureStage) String() string {
+	switch s {
+	case FeatureOptIn:
+		return "opt-in"
+	case FeatureWarnDefault:
+		return "warn-default"
+	case FeatureDefault:
+		return "default"
+	}
+	return fmt.Sprintf("FeatureStage(%d)", int(s))
+}
+
+// Feature is a change in the behaviour of the ygot libraries that can be
+// enabled or disabled by users, such that it can be rolled out across
+// releases. Features are registered by the package that implements them
+// using RegisterFeature, and set by users using SetFeature.
+type Feature struct {
+	name        string
+	description string
+	stage       FeatureStage
+
+	mu sync.Mutex
+	// set indicates that the feature was explicitly set by SetFeature.
+	set bool
+	// enabled stores the value that the feature was explicitly set to.
+	enabled bool
+	// warned indicates that the warning for the stage of the feature was
+	// logged, such that it is only logged once.
+	warned bool
+}
+
+var (
+	// featuresMu protects features.
+	featuresMu sync.RWMutex
+	// features stores the registered features, keyed by name.
+	features = map[string]*Feature{}
+)
+
+// RegisterFeature registers a feature with the supplied name, description and
+// stage, returning it. It is intended to be called during the initialisation
+// of the package implementing the feature, and panics if a feature with the
+// same name is already registered.
+func RegisterFeature(name, description string, stage FeatureStage) *Feature {
+	featuresMu.Lock()
+	defer featuresMu.Unlock()
+	if _, ok := features[name]; ok {
+		panic(fmt.Sprintf("feature %q is already registered", name))
+	}
+	f := &Feature{name: name, description: description, stage: stage}
+	features[name] = f
+	return f
+}
+
+// SetFeature explicitly enables or disables the feature with the supplied
+// name. An error is returned if no such feature is registered.
+func SetFeature(name string, enabled bool) error {
+	f, err := lookupFeature(name)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.set, f.enabled = true, enabled
+	return nil
+}
+
+// ClearFeature clears the explicit setting of the feature with the supplied
+// name, such that its default for its stage is used. An error is returned if
+// no such feature is registered.
+func ClearFeature(name string) error {
+	f, err := lookupFeature(name)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.set, f.enabled = false, false
+	return nil
+}
+
+// Features returns the registered features, sorted by name.
+func Features() []*Feature {
+	featuresMu.RLock()
+	defer featuresMu.RUnlock()
+	var fs []*Feature
+	for _, f := range features {
+		fs = append(fs, f)
+	}
+	sort.Slice(fs, func(i, j int) bool { return fs[i].name < fs[j].name })
+	return fs
+}
+
+// lookupFeature returns the registered feature with the supplied name.
+func lookupFeature(name string) (*Feature, error) {
+	featuresMu.RLock()
+	defer featuresMu.RUnlock()
+	f, ok := features[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown feature %q", name)
+	}
+	return f, nil
+}
+
+// Name returns the name of the feature.
+func (f *Feature) Name() string { return f.name }
+
+// Description returns the description of the feature.
+func (f *Feature) Description() string { return f.description }
+
+// Stage returns the stage of the feature.
+func (f *Feature) Stage() FeatureStage { return f.stage }
+
+// Enabled reports whether the feature is enabled. It is called by the package
+// implementing the feature where its behaviour differs from the behaviour
+// without it, such that a warning can be logged, the first time it is called,
+// if the behaviour is to change in a future release.
+func (f *Feature) Enabled() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case f.set:
+		if !f.enabled && f.stage == FeatureDefault && !f.warned {
+			f.warned = true
+			util.Log().Warningf("ygot: feature %q (%s) is disabled; the behaviour without it is deprecated, and will be removed in a future release", f.name, f.description)
+		}
+		return f.enabled
+	case f.stage == FeatureWarnDefault:
+		if !f.warned {
+			f.warned = true
+			util.Log().Warningf("ygot: feature %q (%s) is disabled, but will be enabled by default in a future release; enable it using SetFeature to adopt the new behaviour, or disable it explicitly to retain the current behaviour", f.name, f.description)
+		}
+		return false
+	}
+	return f.stage == FeatureDefault
+}
@@ -0,0 +1,152 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+// recordingLogger is a Logger that records the warnings that are logged.
+type recordingLogger struct {
+	warnings []string
+}
+
+func (*recordingLogger) Infof(string, ...any)  {}
+func (*recordingLogger) Errorf(string, ...any) {}
+func (l *recordingLogger) Warningf(format string, args ...any) {
+	l.warnings = append(l.warnings, fmt.Sprintf(format, args...))
+}
+
+func TestFeature(t *testing.T) {
+	tests := []struct {
+		name  string
+		stage FeatureStage
+		// inSet is the value that the feature is explicitly set to, if
+		// it is non-nil.
+		inSet        *bool
+		wantEnabled  bool
+		wantWarnings int
+	}{{
+		name:  "opt-in unset",
+		stage: FeatureOptIn,
+	}, {
+		name:        "opt-in enabled",
+		stage:       FeatureOptIn,
+		inSet:       Bool(true),
+		wantEnabled: true,
+	}, {
+		name:         "warn-default unset",
+		stage:        FeatureWarnDefault,
+		wantWarnings: 1,
+	}, {
+		name:        "warn-default enabled",
+		stage:       FeatureWarnDefault,
+		inSet:       Bool(true),
+		wantEnabled: true,
+	}, {
+		name:  "warn-default disabled",
+		stage: FeatureWarnDefault,
+		inSet: Bool(false),
+	}, {
+		name:        "default unset",
+		stage:       FeatureDefault,
+		wantEnabled: true,
+	}, {
+		name:         "default disabled",
+		stage:        FeatureDefault,
+		inSet:        Bool(false),
+		wantWarnings: 1,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &recordingLogger{}
+			SetLogger(l)
+			defer SetLogger(nil)
+
+			name := "test-feature-" + tt.name
+			f := RegisterFeature(name, "a test feature", tt.stage)
+			if tt.inSet != nil {
+				if err := SetFeature(name, *tt.inSet); err != nil {
+					t.Fatalf("SetFeature(%q, %v): got unexpected error: %v", name, *tt.inSet, err)
+				}
+			}
+
+			// Warnings are only logged the first time that the feature
+			// is checked.
+			for i := 0; i < 2; i++ {
+				if got := f.Enabled(); got != tt.wantEnabled {
+					t.Errorf("Enabled(): got %v, want %v", got, tt.wantEnabled)
+				}
+			}
+			if got := len(l.warnings); got != tt.wantWarnings {
+				t.Errorf("Enabled(): got %d warnings (%v), want %d", got, l.warnings, tt.wantWarnings)
+			}
+
+			if err := ClearFeature(name); err != nil {
+				t.Fatalf("ClearFeature(%q): got unexpected error: %v", name, err)
+			}
+			if got, want := f.Enabled(), tt.stage == FeatureDefault; got != want {
+				t.Errorf("Enabled() after ClearFeature: got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestFeatureRegistry(t *testing.T) {
+	f := RegisterFeature("test-registry-b", "b", FeatureOptIn)
+	RegisterFeature("test-registry-a", "a", FeatureDefault)
+
+	var got []string
+	for _, f := range Features() {
+		got = append(got, f.Name())
+	}
+	if diff := cmp.Diff([]string{"test-registry-a", "test-registry-b"}, filterPrefix(got, "test-registry-")); diff != "" {
+		t.Errorf("Features(): did not get expected features (-want, +got):\n%s", diff)
+	}
+	if f.Description() != "b" || f.Stage() != FeatureOptIn {
+		t.Errorf("got feature with description %q and stage %v, want %q and %v", f.Description(), f.Stage(), "b", FeatureOptIn)
+	}
+
+	if diff := errdiff.Substring(SetFeature("test-registry-unknown", true), `unknown feature "test-registry-unknown"`); diff != "" {
+		t.Errorf("SetFeature: did not get expected error, %s", diff)
+	}
+	if diff := errdiff.Substring(ClearFeature("test-registry-unknown"), `unknown feature "test-registry-unknown"`); diff != "" {
+		t.Errorf("ClearFeature: did not get expected error, %s", diff)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("RegisterFeature: did not panic when registering a duplicate feature")
+		}
+	}()
+	RegisterFeature("test-registry-a", "a", FeatureDefault)
+}
+
+// filterPrefix returns the strings in ss that start with prefix.
+func filterPrefix(ss []string, prefix string) []string {
+	var out []string
+	for _, s := range ss {
+		if strings.HasPrefix(s, prefix) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
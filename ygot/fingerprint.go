@@ -0,0 +1,230 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/openconfig/ygot/util"
+	"google.golang.org/protobuf/proto"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// FingerprintOpt is an interface implemented by options to Fingerprint.
+type FingerprintOpt interface {
+	// IsFingerprintOpt is a marker method for each FingerprintOpt.
+	IsFingerprintOpt()
+}
+
+// FingerprintPreferShadowPath specifies that Fingerprint should use the
+// "shadow-path" tags of the fields of the GoStruct to determine the paths of
+// the leaves that are hashed, rather than the "path" tags, where both are
+// present.
+type FingerprintPreferShadowPath struct{}
+
+// IsFingerprintOpt marks FingerprintPreferShadowPath as a valid
+// FingerprintOpt.
+func (*FingerprintPreferShadowPath) IsFingerprintOpt() {}
+
+// FingerprintIgnorePaths specifies paths, relative to the GoStruct, of
+// subtrees that are not included in the fingerprint computed by Fingerprint,
+// such as those containing counters or timestamps that change regardless of
+// the configuration. Paths may contain "*" as a wildcard name or key value,
+// and keys that are not specified match any value.
+type FingerprintIgnorePaths struct {
+	// Paths are the paths of the subtrees to be ignored.
+	Paths []*gnmipb.Path
+}
+
+// IsFingerprintOpt marks FingerprintIgnorePaths as a valid FingerprintOpt.
+func (*FingerprintIgnorePaths) IsFingerprintOpt() {}
+
+// Fingerprint returns a hash of the populated leaves and leaf-lists of the
+// GoStruct s, covering both their paths and values, such that a change in a
+// data tree can be detected by comparing the fingerprints of two snapshots
+// rather than computing the Diff between them.
+//
+// The fingerprint depends only on the contents of s, and not on the order in
+// which it was populated or the order in which the entries of unordered lists
+// are stored. The order of the entries of ordered lists, and of the values of
+// leaf-lists, is included in the fingerprint. Annotations are not included.
+// Leaves are hashed using the string form of their path and a canonical form
+// of their gNMI TypedValue, in which enumerated values are represented by
+// their names, such that the fingerprint of a data tree is the same across
+// processes, and across versions of the generated code and of ygot, for the
+// same schema.
+//
+// Distinct data trees may have the same fingerprint, although this is
+// unlikely, such that equal fingerprints should not be used to conclude that
+// two data trees are identical where this is critical. The fingerprint of a
+// GoStruct with no populated leaves is zero.
+func Fingerprint(s GoStruct, opts ...FingerprintOpt) (uint64, error) {
+	var (
+		walkOpts []WalkOpt
+		ignore   PathTrie[bool]
+	)
+	for _, o := range opts {
+		switch v := o.(type) {
+		case *FingerprintPreferShadowPath:
+			walkOpts = append(walkOpts, &WalkPreferShadowPath{})
+		case *FingerprintIgnorePaths:
+			for _, p := range v.Paths {
+				if err := ignore.Insert(p, true); err != nil {
+					return 0, fmt.Errorf("invalid ignored path: %v", err)
+				}
+			}
+		}
+	}
+
+	var (
+		sum uint64
+		// orderedLists are the paths of the ordered lists within s,
+		// mapped to the paths of their entries in order.
+		orderedLists = map[string][]string{}
+	)
+	err := Walk(s, func(path *gnmipb.Path, node any) error {
+		if _, ok := ignore.LongestPrefix(path); ok {
+			return SkipSubtree
+		}
+		switch node.(type) {
+		case GoOrderedMap:
+			p, err := PathToString(path)
+			if err != nil {
+				return err
+			}
+			orderedLists[p] = []string{}
+			return nil
+		case GoStruct:
+			if len(path.GetElem()) == 0 {
+				return nil
+			}
+			// Record the path of the entry if it is within an
+			// ordered list.
+			last := path.GetElem()[len(path.GetElem())-1]
+			if len(last.GetKey()) == 0 {
+				return nil
+			}
+			listPath := proto.Clone(path).(*gnmipb.Path)
+			listPath.Elem[len(listPath.Elem)-1].Key = nil
+			lp, err := PathToString(listPath)
+			if err != nil {
+				return err
+			}
+			if entries, ok := orderedLists[lp]; ok {
+				ep, err := PathToString(path)
+				if err != nil {
+					return err
+				}
+				orderedLists[lp] = append(entries, ep)
+			}
+			return nil
+		}
+
+		v := reflect.ValueOf(node)
+		if util.IsValueMap(v) || (util.IsValueSlice(v) && util.IsTypeStructPtr(v.Type().Elem())) {
+			// Lists are represented by their entries.
+			return nil
+		}
+		p, err := PathToString(path)
+		if err != nil {
+			return err
+		}
+		tv, err := EncodeTypedValue(node, gnmipb.Encoding_JSON_IETF, &RFC7951JSONConfig{})
+		if err != nil {
+			return fmt.Errorf("cannot encode value at %s: %v", p, err)
+		}
+		val, err := fingerprintTypedValue(tv)
+		if err != nil {
+			return fmt.Errorf("cannot encode value at %s: %v", p, err)
+		}
+		sum += fingerprintHash(p, val)
+		return nil
+	}, walkOpts...)
+	if err != nil {
+		return 0, fmt.Errorf("cannot fingerprint GoStruct: %v", err)
+	}
+	for p, entries := range orderedLists {
+		sum += fingerprintHash(append([]string{p, "#order"}, entries...)...)
+	}
+	return sum, nil
+}
+
+// fingerprintTypedValue returns the canonical form of the TypedValue tv that
+// is hashed by Fingerprint, which is prefixed by the type of the value such
+// that values of different types with the same string form are distinct.
+func fingerprintTypedValue(tv *gnmipb.TypedValue) (string, error) {
+	switch v := tv.GetValue().(type) {
+	case nil:
+		return "nil", nil
+	case *gnmipb.TypedValue_StringVal:
+		return "string:" + v.StringVal, nil
+	case *gnmipb.TypedValue_IntVal:
+		return "int:" + strconv.FormatInt(v.IntVal, 10), nil
+	case *gnmipb.TypedValue_UintVal:
+		return "uint:" + strconv.FormatUint(v.UintVal, 10), nil
+	case *gnmipb.TypedValue_BoolVal:
+		return "bool:" + strconv.FormatBool(v.BoolVal), nil
+	case *gnmipb.TypedValue_BytesVal:
+		return "bytes:" + base64.StdEncoding.EncodeToString(v.BytesVal), nil
+	case *gnmipb.TypedValue_DecimalVal:
+		// The canonical form of the value has no trailing fraction zeros,
+		// such that equal values with different precisions are equal.
+		p := v.DecimalVal.GetPrecision()
+		if p > maxDecimal64Precision {
+			return "", fmt.Errorf("invalid decimal64 precision %d", p)
+		}
+		return "decimal:" + Decimal64{Digits: v.DecimalVal.GetDigits(), Precision: uint8(p)}.String(), nil
+	case *gnmipb.TypedValue_DoubleVal:
+		return "double:" + strconv.FormatFloat(v.DoubleVal, 'g', -1, 64), nil
+	//lint:ignore SA1019 Specifically handling deprecated gNMI FloatVal field.
+	case *gnmipb.TypedValue_FloatVal:
+		//lint:ignore SA1019 Specifically handling deprecated gNMI FloatVal field.
+		return "double:" + strconv.FormatFloat(float64(v.FloatVal), 'g', -1, 32), nil
+	case *gnmipb.TypedValue_LeaflistVal:
+		var elems []string
+		for _, e := range v.LeaflistVal.GetElement() {
+			s, err := fingerprintTypedValue(e)
+			if err != nil {
+				return "", err
+			}
+			elems = append(elems, strconv.Quote(s))
+		}
+		return "leaflist:[" + strings.Join(elems, ",") + "]", nil
+	default:
+		return "", fmt.Errorf("unsupported TypedValue type %T", v)
+	}
+}
+
+// fingerprintHash returns the hash of the supplied strings.
+func fingerprintHash(s ...string) uint64 {
+	h := fnv.New64a()
+	var l [8]byte
+	for _, v := range s {
+		// Since YANG strings may contain any character, each string is
+		// prefixed by its length such that the boundaries between them
+		// are hashed.
+		binary.BigEndian.PutUint64(l[:], uint64(len(v)))
+		h.Write(l[:])
+		h.Write([]byte(v))
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,168 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot_test
+
+import (
+	"testing"
+
+	"github.com/openconfig/ygot/integration_tests/schemaops/ctestschema"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// fingerprintDevice returns a Device containing unordered and ordered lists,
+// whose ordered list entries are appended in the order of orderedKeys.
+func fingerprintDevice(t *testing.T, orderedKeys ...string) *ctestschema.Device {
+	t.Helper()
+	d := &ctestschema.Device{
+		OtherData: &ctestschema.OtherData{Motd: ygot.String("hello")},
+		UnorderedList: map[string]*ctestschema.UnorderedList{
+			"a": {Key: ygot.String("a"), Value: ygot.String("one")},
+			"b": {Key: ygot.String("b"), Value: ygot.String("two")},
+		},
+		OrderedList: &ctestschema.OrderedList_OrderedMap{},
+	}
+	for _, k := range orderedKeys {
+		if _, err := d.OrderedList.AppendNew(k); err != nil {
+			t.Fatalf("cannot append %s to ordered list: %v", k, err)
+		}
+	}
+	return d
+}
+
+func mustFingerprint(t *testing.T, d ygot.GoStruct, opts ...ygot.FingerprintOpt) uint64 {
+	t.Helper()
+	f, err := ygot.Fingerprint(d, opts...)
+	if err != nil {
+		t.Fatalf("Fingerprint: got unexpected error: %v", err)
+	}
+	return f
+}
+
+func TestFingerprint(t *testing.T) {
+	base := mustFingerprint(t, fingerprintDevice(t, "x", "y"))
+
+	if got := mustFingerprint(t, &ctestschema.Device{}); got != 0 {
+		t.Errorf("Fingerprint of empty Device: got %d, want 0", got)
+	}
+
+	cp, err := ygot.DeepCopy(fingerprintDevice(t, "x", "y"))
+	if err != nil {
+		t.Fatalf("cannot copy Device: %v", err)
+	}
+	if got := mustFingerprint(t, cp); got != base {
+		t.Errorf("Fingerprint of copied Device: got %d, want %d", got, base)
+	}
+
+	tests := []struct {
+		desc   string
+		modify func(*ctestschema.Device)
+	}{{
+		desc:   "changed leaf",
+		modify: func(d *ctestschema.Device) { d.OtherData.Motd = ygot.String("goodbye") },
+	}, {
+		desc:   "removed leaf",
+		modify: func(d *ctestschema.Device) { d.OtherData.Motd = nil },
+	}, {
+		desc: "values moved between list entries",
+		modify: func(d *ctestschema.Device) {
+			d.UnorderedList["a"].Value, d.UnorderedList["b"].Value = d.UnorderedList["b"].Value, d.UnorderedList["a"].Value
+		},
+	}, {
+		desc:   "removed list entry",
+		modify: func(d *ctestschema.Device) { delete(d.UnorderedList, "b") },
+	}, {
+		desc: "reordered ordered list",
+		modify: func(d *ctestschema.Device) {
+			d.OrderedList = fingerprintDevice(t, "y", "x").OrderedList
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			d := fingerprintDevice(t, "x", "y")
+			tt.modify(d)
+			if got := mustFingerprint(t, d); got == base {
+				t.Errorf("Fingerprint: got unchanged fingerprint %d for modified Device", got)
+			}
+		})
+	}
+}
+
+func TestFingerprintStable(t *testing.T) {
+	d := &ctestschema.Device{
+		OtherData: &ctestschema.OtherData{Motd: ygot.String("hello")},
+	}
+	// The fingerprint is computed from the canonical form of the paths and
+	// values of the tree, such that it must not change between processes
+	// or versions of ygot for the same schema.
+	if got, want := mustFingerprint(t, d), uint64(9064403516507386251); got != want {
+		t.Errorf("Fingerprint: got %d, want %d", got, want)
+	}
+}
+
+func TestFingerprintIgnorePaths(t *testing.T) {
+	tests := []struct {
+		desc   string
+		inPath *gpb.Path
+		modify func(*ctestschema.Device)
+	}{{
+		desc:   "ignored container",
+		inPath: &gpb.Path{Elem: []*gpb.PathElem{{Name: "other-data"}}},
+		modify: func(d *ctestschema.Device) { d.OtherData.Motd = ygot.String("goodbye") },
+	}, {
+		desc: "ignored leaf in all list entries",
+		inPath: &gpb.Path{Elem: []*gpb.PathElem{
+			{Name: "unordered-lists"},
+			{Name: "unordered-list", Key: map[string]string{"key": "*"}},
+			{Name: "config"},
+			{Name: "value"},
+		}},
+		modify: func(d *ctestschema.Device) {
+			d.UnorderedList["a"].Value = ygot.String("three")
+			d.UnorderedList["b"].Value = nil
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			opt := &ygot.FingerprintIgnorePaths{Paths: []*gpb.Path{tt.inPath}}
+			want := mustFingerprint(t, fingerprintDevice(t, "x"), opt)
+			if want == mustFingerprint(t, fingerprintDevice(t, "x")) {
+				t.Fatalf("Fingerprint: ignoring %v did not change the fingerprint", tt.inPath)
+			}
+			d := fingerprintDevice(t, "x")
+			tt.modify(d)
+			if got := mustFingerprint(t, d, opt); got != want {
+				t.Errorf("Fingerprint: got %d for Device modified within ignored path, want %d", got, want)
+			}
+		})
+	}
+}
+
+func BenchmarkFingerprint(b *testing.B) {
+	d := &ctestschema.Device{UnorderedList: map[string]*ctestschema.UnorderedList{}}
+	for i := 0; i < 1000; i++ {
+		k := string(rune('a'+i%26)) + string(rune('a'+i/26))
+		d.UnorderedList[k] = &ctestschema.UnorderedList{Key: ygot.String(k), Value: ygot.String(k)}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ygot.Fingerprint(d); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
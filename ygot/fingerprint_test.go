@@ -0,0 +1,83 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"reflect"
+	"testing"
+)
+
+// decimalExample is a GoStruct containing a Decimal64 leaf, as generated
+// with the decimal64 type enabled.
+type decimalExample struct {
+	Val  Decimal64   `path:"val"`
+	List []Decimal64 `path:"list"`
+}
+
+func (*decimalExample) IsYANGGoStruct()                         {}
+func (*decimalExample) ΛValidate(...ValidationOption) error     { return nil }
+func (*decimalExample) ΛEnumTypeMap() map[string][]reflect.Type { return nil }
+func (*decimalExample) ΛBelongingModule() string                { return "" }
+
+func TestFingerprintHashBoundaries(t *testing.T) {
+	tests := []struct {
+		desc string
+		a, b []string
+	}{{
+		desc: "NUL moved between strings",
+		a:    []string{"/a\x00string:b", "string:c"},
+		b:    []string{"/a", "string:b\x00string:c"},
+	}, {
+		desc: "characters moved between strings",
+		a:    []string{"/ab", "c"},
+		b:    []string{"/a", "bc"},
+	}, {
+		desc: "ordered list entries containing separators",
+		a:    []string{"/l", "#order", "/l[k=a,b]", "/l[k=c]"},
+		b:    []string{"/l", "#order", "/l[k=a]", "b]", "/l[k=c]"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if fingerprintHash(tt.a...) == fingerprintHash(tt.b...) {
+				t.Errorf("fingerprintHash(%q) == fingerprintHash(%q), want distinct hashes", tt.a, tt.b)
+			}
+		})
+	}
+}
+
+func TestFingerprintDecimal64(t *testing.T) {
+	fingerprint := func(s GoStruct) uint64 {
+		t.Helper()
+		f, err := Fingerprint(s)
+		if err != nil {
+			t.Fatalf("Fingerprint(%v): got unexpected error: %v", s, err)
+		}
+		return f
+	}
+
+	base := fingerprint(&decimalExample{Val: Decimal64{Digits: 150, Precision: 2}})
+	if got := fingerprint(&decimalExample{Val: Decimal64{Digits: 15, Precision: 1}}); got != base {
+		t.Errorf("Fingerprint of 1.5 with precision 1: got %d, want %d as for 1.50 with precision 2", got, base)
+	}
+	if got := fingerprint(&decimalExample{Val: Decimal64{Digits: 151, Precision: 2}}); got == base {
+		t.Errorf("Fingerprint of 1.51: got %d, want distinct from 1.50", got)
+	}
+
+	withList := fingerprint(&decimalExample{List: []Decimal64{{Digits: 10, Precision: 1}, {Digits: -25, Precision: 1}}})
+	if got := fingerprint(&decimalExample{List: []Decimal64{{Digits: 100, Precision: 2}, {Digits: -2500, Precision: 3}}}); got != withList {
+		t.Errorf("Fingerprint of leaf-list with differing precisions: got %d, want %d", got, withList)
+	}
+}
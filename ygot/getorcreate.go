@@ -0,0 +1,51 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// GetOrCreateAtRoot is an interface implemented by the generated fake root
+// struct when the GetOrCreateAt method is generated for it.
+type GetOrCreateAtRoot interface {
+	// GetOrCreateAt retrieves the node at the supplied path, creating it
+	// if it does not exist.
+	GetOrCreateAt(path *gnmipb.Path) (interface{}, error)
+}
+
+// GetOrCreateAt retrieves the node at the supplied path within root, creating
+// it, along with the containers and list entries along the path, if it does
+// not exist, and returns it as type T. For example, the IPv4 container of a
+// subinterface can be retrieved using:
+//
+//	ipv4, err := ygot.GetOrCreateAt[*oc.Interface_Subinterface_Ipv4](d, path)
+//
+// An error is returned if the node cannot be retrieved, or if it is not of
+// type T. Note that root may be modified even if an error is returned.
+func GetOrCreateAt[T any](root GetOrCreateAtRoot, path *gnmipb.Path) (T, error) {
+	var zero T
+	n, err := root.GetOrCreateAt(path)
+	if err != nil {
+		return zero, err
+	}
+	v, ok := n.(T)
+	if !ok {
+		return zero, fmt.Errorf("node at path %v has type %T, not %T", path, n, zero)
+	}
+	return v, nil
+}
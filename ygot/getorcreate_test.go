@@ -0,0 +1,70 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+	"testing"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+type getOrCreateAtChild struct {
+	Name *string
+}
+
+// getOrCreateAtRoot is a fake root that returns a child for each path with a
+// single element, and an error for any other path.
+type getOrCreateAtRoot struct {
+	children map[string]*getOrCreateAtChild
+}
+
+func (r *getOrCreateAtRoot) GetOrCreateAt(path *gnmipb.Path) (interface{}, error) {
+	if len(path.GetElem()) != 1 {
+		return nil, fmt.Errorf("unsupported path %v", path)
+	}
+	n := path.GetElem()[0].GetName()
+	if r.children[n] == nil {
+		r.children[n] = &getOrCreateAtChild{Name: String(n)}
+	}
+	return r.children[n], nil
+}
+
+func TestGetOrCreateAt(t *testing.T) {
+	root := &getOrCreateAtRoot{children: map[string]*getOrCreateAtChild{}}
+
+	got, err := GetOrCreateAt[*getOrCreateAtChild](root, mustTriePath(t, "/a"))
+	if err != nil {
+		t.Fatalf("GetOrCreateAt: got unexpected error: %v", err)
+	}
+	if got != root.children["a"] || got.Name == nil || *got.Name != "a" {
+		t.Errorf("GetOrCreateAt: did not get the created child, got: %v", got)
+	}
+
+	again, err := GetOrCreateAt[*getOrCreateAtChild](root, mustTriePath(t, "/a"))
+	if err != nil {
+		t.Fatalf("GetOrCreateAt: got unexpected error: %v", err)
+	}
+	if again != got {
+		t.Errorf("GetOrCreateAt: did not get the existing child, got: %p, want: %p", again, got)
+	}
+
+	if _, err := GetOrCreateAt[*getOrCreateAtRoot](root, mustTriePath(t, "/b")); err == nil {
+		t.Errorf("GetOrCreateAt: did not get expected error for mismatched type")
+	}
+	if _, err := GetOrCreateAt[*getOrCreateAtChild](root, mustTriePath(t, "/a/b")); err == nil {
+		t.Errorf("GetOrCreateAt: did not get expected error from root")
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPatchOp is an operation within an RFC6902 JSON Patch document.
+type jsonPatchOp struct {
+	// Op is the operation, one of "add", "remove" or "replace".
+	Op string
+	// Path is the RFC6901 JSON Pointer to the value that the operation
+	// applies to.
+	Path string
+	// Value is the value that is added, or that replaces the existing
+	// value. It is not used for "remove" operations.
+	Value any
+}
+
+// MarshalJSON implements the json.Marshaler interface, such that the value
+// of an operation is only output for operations that use it, since a value
+// may be null.
+func (o *jsonPatchOp) MarshalJSON() ([]byte, error) {
+	if o.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{Op: o.Op, Path: o.Path})
+	}
+	return json.Marshal(struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}{Op: o.Op, Path: o.Path, Value: o.Value})
+}
+
+// JSONPatch returns an RFC6902 JSON Patch document which, when applied to
+// the RFC7951 JSON representation of original, results in the RFC7951 JSON
+// representation of modified. The representations are those returned by
+// ConstructIETFJSON with the supplied configuration, such that the values
+// and member names within the patch are rendered in the same way, and the
+// paths of the operations are JSON Pointers formed of the member names.
+//
+// The patch consists of "add", "remove" and "replace" operations. Values
+// are compared recursively, such that a changed leaf results in an
+// operation that replaces the leaf alone. The entries of YANG lists and
+// leaf-lists, which are represented as JSON arrays, are compared by their
+// position within the array: where an array is longer in one of the
+// representations, the trailing entries are removed or added.
+func JSONPatch(original, modified GoStruct, cfg *RFC7951JSONConfig) ([]byte, error) {
+	if reflect.TypeOf(original) != reflect.TypeOf(modified) {
+		return nil, fmt.Errorf("cannot create JSON Patch between structs of different types, %T != %T", original, modified)
+	}
+
+	a, err := patchJSONValue(original, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot render original struct: %w", err)
+	}
+	b, err := patchJSONValue(modified, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot render modified struct: %w", err)
+	}
+
+	ops := []*jsonPatchOp{}
+	diffJSONValues(&ops, "", a, b)
+	return json.Marshal(ops)
+}
+
+// patchJSONValue returns the RFC7951 JSON representation of s, as it would be
+// unmarshalled from its encoded form, such that values of different Go types
+// that have the same encoding are equal. Numbers are unmarshalled as
+// json.Number such that their precision is maintained.
+func patchJSONValue(s GoStruct, cfg *RFC7951JSONConfig) (any, error) {
+	j, err := ConstructIETFJSON(s, cfg)
+	if err != nil {
+		return nil, err
+	}
+	js, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	d := json.NewDecoder(bytes.NewReader(js))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// diffJSONValues appends to ops the operations that transform the JSON value
+// a, found at the JSON Pointer path, to b.
+func diffJSONValues(ops *[]*jsonPatchOp, path string, a, b any) {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		var names []string
+		for n := range av {
+			names = append(names, n)
+		}
+		for n := range bv {
+			if _, ok := av[n]; !ok {
+				names = append(names, n)
+			}
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			p := path + "/" + escapeJSONPointer(n)
+			aChild, inA := av[n]
+			bChild, inB := bv[n]
+			switch {
+			case !inB:
+				*ops = append(*ops, &jsonPatchOp{Op: "remove", Path: p})
+			case !inA:
+				*ops = append(*ops, &jsonPatchOp{Op: "add", Path: p, Value: bChild})
+			default:
+				diffJSONValues(ops, p, aChild, bChild)
+			}
+		}
+		return
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		common := len(av)
+		if len(bv) < common {
+			common = len(bv)
+		}
+		for i := 0; i < common; i++ {
+			diffJSONValues(ops, path+"/"+strconv.Itoa(i), av[i], bv[i])
+		}
+		// Entries are removed from the end of the array, such that the
+		// index of each entry is unchanged by the removal of the
+		// preceding entries.
+		for i := len(av) - 1; i >= common; i-- {
+			*ops = append(*ops, &jsonPatchOp{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+		}
+		for i := common; i < len(bv); i++ {
+			*ops = append(*ops, &jsonPatchOp{Op: "add", Path: path + "/" + strconv.Itoa(i), Value: bv[i]})
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*ops = append(*ops, &jsonPatchOp{Op: "replace", Path: path, Value: b})
+	}
+}
+
+// escapeJSONPointer escapes the supplied reference token for use within an
+// RFC6901 JSON Pointer.
+func escapeJSONPointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
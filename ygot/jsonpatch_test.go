@@ -0,0 +1,128 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+func TestJSONPatch(t *testing.T) {
+	tests := []struct {
+		name             string
+		inOriginal       GoStruct
+		inModified       GoStruct
+		inConfig         *RFC7951JSONConfig
+		want             string
+		wantErrSubstring string
+	}{{
+		name:       "no differences",
+		inOriginal: &renderExample{Str: String("foo")},
+		inModified: &renderExample{Str: String("foo")},
+		want:       `[]`,
+	}, {
+		name:       "leaves added, removed and replaced",
+		inOriginal: &renderExample{Str: String("foo"), FloatVal: Float64(1.5)},
+		inModified: &renderExample{Str: String("bar"), IntVal: Int32(42), Int64Val: Int64(42)},
+		want: `[
+			{"op": "remove", "path": "/floatval"},
+			{"op": "add", "path": "/int-val", "value": 42},
+			{"op": "add", "path": "/int64-val", "value": "42"},
+			{"op": "replace", "path": "/str", "value": "bar"}
+		]`,
+	}, {
+		name:       "enumerated value replaced",
+		inOriginal: &renderExample{EnumField: EnumTestVALONE},
+		inModified: &renderExample{EnumField: EnumTestVALTWO},
+		want:       `[{"op": "replace", "path": "/enum", "value": "VAL_TWO"}]`,
+	}, {
+		name:       "container added",
+		inOriginal: &renderExample{},
+		inModified: &renderExample{Ch: &renderExampleChild{Val: Uint64(10)}},
+		want:       `[{"op": "add", "path": "/ch", "value": {"val": "10"}}]`,
+	}, {
+		name:       "leaf within container removed",
+		inOriginal: &renderExample{Ch: &renderExampleChild{Val: Uint64(10), Enum: EnumTestVALONE}},
+		inModified: &renderExample{Ch: &renderExampleChild{Val: Uint64(10)}},
+		want:       `[{"op": "remove", "path": "/ch/enum"}]`,
+	}, {
+		name:       "leaf-list shortened",
+		inOriginal: &renderExample{LeafList: []string{"a", "b", "c"}},
+		inModified: &renderExample{LeafList: []string{"a", "x"}},
+		want: `[
+			{"op": "replace", "path": "/leaf-list/1", "value": "x"},
+			{"op": "remove", "path": "/leaf-list/2"}
+		]`,
+	}, {
+		name:       "leaf-list lengthened",
+		inOriginal: &renderExample{LeafList: []string{"a"}},
+		inModified: &renderExample{LeafList: []string{"a", "b", "c"}},
+		want: `[
+			{"op": "add", "path": "/leaf-list/1", "value": "b"},
+			{"op": "add", "path": "/leaf-list/2", "value": "c"}
+		]`,
+	}, {
+		name:       "list entries",
+		inOriginal: &renderExample{List: map[uint32]*renderExampleList{1: {Val: String("one")}, 2: {Val: String("two")}, 3: {Val: String("three")}}},
+		inModified: &renderExample{List: map[uint32]*renderExampleList{1: {Val: String("uno")}, 2: {Val: String("two")}}},
+		want: `[
+			{"op": "replace", "path": "/list/0/state/val", "value": "uno"},
+			{"op": "replace", "path": "/list/0/val", "value": "uno"},
+			{"op": "remove", "path": "/list/2"}
+		]`,
+	}, {
+		name:       "empty leaf added",
+		inOriginal: &renderExample{},
+		inModified: &renderExample{Empty: true},
+		want:       `[{"op": "add", "path": "/empty", "value": [null]}]`,
+	}, {
+		name:             "different types",
+		inOriginal:       &renderExample{},
+		inModified:       &renderExampleChild{},
+		wantErrSubstring: "different types",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONPatch(tt.inOriginal, tt.inModified, tt.inConfig)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("JSONPatch: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+
+			var gotOps, wantOps []any
+			if err := json.Unmarshal(got, &gotOps); err != nil {
+				t.Fatalf("JSONPatch: cannot unmarshal output %s: %v", got, err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &wantOps); err != nil {
+				t.Fatalf("cannot unmarshal want: %v", err)
+			}
+			if diff := cmp.Diff(wantOps, gotOps); diff != "" {
+				t.Errorf("JSONPatch: did not get expected patch, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEscapeJSONPointer(t *testing.T) {
+	if got, want := escapeJSONPointer("a/b~c"), "a~1b~0c"; got != want {
+		t.Errorf("escapeJSONPointer: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/util"
+)
+
+// JSONSchemaDialect is the URI of the JSON Schema dialect, draft 2020-12, of
+// the documents returned by JSONSchema.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns a JSON Schema document, using the draft 2020-12 dialect,
+// that describes the RFC7951 JSON representation of the type of the GoStruct
+// s, as rendered by EmitJSON with the RFC7951 format and AppendModuleName set.
+// This allows payloads to be validated by tools that do not support YANG, such
+// as API gateways and UI form builders. schema is the schema of s, e.g.,
+// exampleoc.SchemaTree["Device"]. Only the type of s is used, hence s may be
+// a nil pointer.
+//
+// When s is the fake root, the members of the document are the top-level
+// containers of the schema, qualified by the names of their modules. The
+// document for a single top-level container is returned by supplying its
+// GoStruct and schema.
+//
+// Values are described as specified by RFC7951, e.g., 64-bit integers and
+// decimal64 values are strings. The patterns of strings are the POSIX patterns
+// of their types where these are specified, otherwise they are the anchored
+// XSD patterns. Annotations are not described.
+func JSONSchema(s GoStruct, schema *yang.Entry) ([]byte, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("nil schema for GoStruct %T", s)
+	}
+	doc, err := structJSONSchema(reflect.TypeOf(s), schema, "")
+	if err != nil {
+		return nil, err
+	}
+	doc["$schema"] = JSONSchemaDialect
+	if schema.Description != "" {
+		doc["description"] = schema.Description
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// objectJSONSchema returns the JSON Schema of an object with no properties.
+func objectJSONSchema() map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"properties":           map[string]any{},
+		"additionalProperties": false,
+	}
+}
+
+// structJSONSchema returns the JSON Schema of the RFC7951 representation of
+// the GoStruct type t, whose schema is supplied. parentMod is the module
+// within which the GoStruct is defined, such that the names of members within
+// other modules are qualified by their module names.
+func structJSONSchema(t reflect.Type, schema *yang.Entry, parentMod string) (map[string]any, error) {
+	if !util.IsTypeStructPtr(t) {
+		return nil, fmt.Errorf("type %v for schema %s is not a struct pointer", t, schema.Name)
+	}
+	cfg := jsonOutputConfig{
+		jType:         RFC7951,
+		rfc7951Config: &RFC7951JSONConfig{AppendModuleName: true},
+	}
+
+	obj := objectJSONSchema()
+	st := t.Elem()
+	for i := 0; i < st.NumField(); i++ {
+		ft := st.Field(i)
+		if util.IsYgotAnnotation(ft) {
+			continue
+		}
+
+		prependmods, chMod, err := prependmodsJSON(ft, parentMod, cfg)
+		if err != nil {
+			return nil, err
+		}
+		paths, err := structTagToLibPaths(ft, newStringSliceGNMIPath([]string{}), false)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", ft.Name, err)
+		}
+		if prependmods != nil && len(paths) != len(prependmods) {
+			return nil, fmt.Errorf("%s: number of paths and modules in struct tag not the same: (paths: %v, modules: %v)", ft.Name, len(paths), len(prependmods))
+		}
+
+		cschema, err := util.ChildSchema(schema, ft)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", ft.Name, err)
+		}
+		if cschema == nil {
+			return nil, fmt.Errorf("cannot find schema for field %s of %v", ft.Name, t)
+		}
+		value, err := fieldJSONSchema(ft.Type, cschema, chMod)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", ft.Name, err)
+		}
+
+		for i, p := range paths {
+			props := obj["properties"].(map[string]any)
+			for j := 0; j < p.Len(); j++ {
+				k, err := p.StringElemAt(j)
+				if err != nil {
+					return nil, err
+				}
+				if prependmods != nil && prependmods[i][j] != "" {
+					k = fmt.Sprintf("%s:%s", prependmods[i][j], k)
+				}
+				if j == p.Len()-1 {
+					props[k] = value
+					break
+				}
+				// Intermediate containers are removed from the
+				// GoStructs by path compression.
+				child, ok := props[k].(map[string]any)
+				if !ok {
+					child = objectJSONSchema()
+					props[k] = child
+				}
+				props = child["properties"].(map[string]any)
+			}
+		}
+	}
+	return obj, nil
+}
+
+// fieldJSONSchema returns the JSON Schema of the RFC7951 representation of a
+// field of a GoStruct, whose type and schema are supplied. mod is the module
+// within which the field is defined.
+func fieldJSONSchema(t reflect.Type, schema *yang.Entry, mod string) (map[string]any, error) {
+	var s map[string]any
+	switch {
+	case schema.IsLeaf():
+		var err error
+		if s, err = typeJSONSchema(nil, schema, schema.Type); err != nil {
+			return nil, err
+		}
+	case schema.IsLeafList():
+		items, err := typeJSONSchema(nil, schema, schema.Type)
+		if err != nil {
+			return nil, err
+		}
+		s = map[string]any{"type": "array", "items": items}
+	case schema.IsList():
+		et := t
+		switch {
+		case t.Implements(reflect.TypeOf((*GoOrderedMap)(nil)).Elem()):
+			get, ok := t.MethodByName("Get")
+			if !ok || get.Type.NumOut() != 1 {
+				return nil, fmt.Errorf("ordered map type %v does not have a Get method", t)
+			}
+			et = get.Type.Out(0)
+		case t.Kind() == reflect.Map, t.Kind() == reflect.Slice:
+			et = t.Elem()
+		}
+		items, err := structJSONSchema(et, schema, mod)
+		if err != nil {
+			return nil, err
+		}
+		if schema.Key != "" {
+			items["required"] = strings.Fields(schema.Key)
+		}
+		s = map[string]any{"type": "array", "items": items}
+	default:
+		var err error
+		if s, err = structJSONSchema(t, schema, mod); err != nil {
+			return nil, err
+		}
+	}
+	if schema.Description != "" {
+		s["description"] = schema.Description
+	}
+	return s, nil
+}
+
+// typeJSONSchema returns the JSON Schema of the RFC7951 representation of a
+// value of the YANG type t of the leaf or leaf-list schema. followed are the
+// schemas of the leafrefs that have been resolved in order to reach schema.
+func typeJSONSchema(followed []*yang.Entry, schema *yang.Entry, t *yang.YangType) (map[string]any, error) {
+	if t == nil {
+		return nil, fmt.Errorf("nil type for schema %s", schema.Name)
+	}
+	switch t.Kind {
+	case yang.Yint8, yang.Yint16, yang.Yint32, yang.Yuint8, yang.Yuint16, yang.Yuint32:
+		s := map[string]any{"type": "integer"}
+		addRangeJSONSchema(s, t.Range, "minimum", "maximum")
+		return s, nil
+	case yang.Yint64:
+		return map[string]any{"type": "string", "pattern": `^-?[0-9]+$`}, nil
+	case yang.Yuint64:
+		return map[string]any{"type": "string", "pattern": `^[0-9]+$`}, nil
+	case yang.Ydecimal64:
+		return map[string]any{"type": "string", "pattern": `^-?[0-9]+(\.[0-9]+)?$`}, nil
+	case yang.Ystring:
+		s := map[string]any{"type": "string"}
+		addRangeJSONSchema(s, t.Length, "minLength", "maxLength")
+		addPatternJSONSchema(s, t)
+		return s, nil
+	case yang.Ybool:
+		return map[string]any{"type": "boolean"}, nil
+	case yang.Yempty:
+		// RFC7951 represents a leaf of type empty as [null].
+		return map[string]any{
+			"type":     "array",
+			"items":    map[string]any{"type": "null"},
+			"minItems": 1,
+			"maxItems": 1,
+		}, nil
+	case yang.Yenum:
+		var names []string
+		if t.Enum != nil {
+			names = t.Enum.Names()
+		}
+		return map[string]any{"type": "string", "enum": names}, nil
+	case yang.Ybinary:
+		return map[string]any{"type": "string", "contentEncoding": "base64"}, nil
+	case yang.Yidentityref, yang.Ybits, yang.YinstanceIdentifier:
+		return map[string]any{"type": "string"}, nil
+	case yang.Yleafref:
+		target, err := util.FollowLeafRef(followed, schema, t.Path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve leafref %s of schema %s: %w", t.Path, schema.Name, err)
+		}
+		return typeJSONSchema(append(followed[:len(followed):len(followed)], schema), target, target.Type)
+	case yang.Yunion:
+		var anyOf []any
+		for _, ut := range t.Type {
+			s, err := typeJSONSchema(followed, schema, ut)
+			if err != nil {
+				return nil, err
+			}
+			anyOf = append(anyOf, s)
+		}
+		return map[string]any{"anyOf": anyOf}, nil
+	}
+	return map[string]any{}, nil
+}
+
+// addRangeJSONSchema adds the constraints of the YANG range or length r to
+// the JSON Schema s, using the supplied keywords for the minimum and maximum
+// values.
+func addRangeJSONSchema(s map[string]any, r yang.YangRange, minKeyword, maxKeyword string) {
+	var ranges []any
+	for _, rr := range r {
+		ranges = append(ranges, map[string]any{
+			minKeyword: json.Number(rr.Min.String()),
+			maxKeyword: json.Number(rr.Max.String()),
+		})
+	}
+	switch len(ranges) {
+	case 0:
+	case 1:
+		for k, v := range ranges[0].(map[string]any) {
+			s[k] = v
+		}
+	default:
+		s["anyOf"] = ranges
+	}
+}
+
+// addPatternJSONSchema adds the patterns of the string type t to the JSON
+// Schema s. XSD patterns are implicitly anchored, and are hence anchored
+// explicitly, since JSON Schema patterns are not.
+func addPatternJSONSchema(s map[string]any, t *yang.YangType) {
+	patterns := t.POSIXPattern
+	if len(patterns) == 0 {
+		for _, p := range t.Pattern {
+			patterns = append(patterns, fmt.Sprintf("^(?:%s)$", p))
+		}
+	}
+	switch len(patterns) {
+	case 0:
+	case 1:
+		s["pattern"] = patterns[0]
+	default:
+		var allOf []any
+		for _, p := range patterns {
+			allOf = append(allOf, map[string]any{"pattern": p})
+		}
+		s["allOf"] = allOf
+	}
+}
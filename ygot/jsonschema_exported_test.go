@@ -0,0 +1,226 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/integration_tests/schemaops/ctestschema"
+	"github.com/openconfig/ygot/ygot"
+)
+
+func TestJSONSchema(t *testing.T) {
+	str := map[string]any{"type": "string"}
+	object := func(props map[string]any) map[string]any {
+		return map[string]any{"type": "object", "properties": props, "additionalProperties": false}
+	}
+	list := func(keys []any, props map[string]any) map[string]any {
+		items := object(props)
+		items["required"] = keys
+		return map[string]any{"type": "array", "items": items}
+	}
+
+	tests := []struct {
+		desc             string
+		in               ygot.GoStruct
+		inSchema         *yang.Entry
+		inMember         string
+		want             map[string]any
+		wantErrSubstring string
+	}{{
+		desc:     "top-level container with keyed list",
+		inSchema: ctestschema.SchemaTree["Device"],
+		in:       &ctestschema.Device{},
+		inMember: "ctestschema:unordered-lists",
+		want: object(map[string]any{
+			"unordered-list": list([]any{"key"}, map[string]any{
+				"key": str,
+				"config": object(map[string]any{
+					"key":   str,
+					"value": str,
+				}),
+			}),
+		}),
+	}, {
+		desc:     "qualified list within other module with uint64 key",
+		inSchema: ctestschema.SchemaTree["Device"],
+		in:       &ctestschema.Device{},
+		inMember: "ctestschema-rootmod:ordered-multikeyed-lists",
+		want: object(map[string]any{
+			"ctestschema:ordered-multikeyed-list": list([]any{"key1", "key2"}, map[string]any{
+				"key1": str,
+				"key2": map[string]any{"type": "string", "pattern": "^[0-9]+$"},
+				"config": object(map[string]any{
+					"key1":  str,
+					"key2":  map[string]any{"type": "string", "pattern": "^[0-9]+$"},
+					"value": str,
+				}),
+				"state": object(map[string]any{
+					"ro-value": str,
+				}),
+			}),
+		}),
+	}, {
+		desc:     "container qualified as in Marshal7951",
+		inSchema: ctestschema.SchemaTree["OtherData"],
+		in:       &ctestschema.OtherData{},
+		inMember: "ctestschema:config",
+		want: object(map[string]any{
+			"motd": str,
+		}),
+	}, {
+		desc:             "GoStruct not matching schema",
+		inSchema:         ctestschema.SchemaTree["Device"],
+		in:               &ctestschema.OtherData{},
+		wantErrSubstring: "cannot find schema",
+	}, {
+		desc:             "nil schema",
+		in:               &ctestschema.Device{},
+		wantErrSubstring: "nil schema",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ygot.JSONSchema(tt.in, tt.inSchema)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("JSONSchema: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			var doc map[string]any
+			if err := json.Unmarshal(got, &doc); err != nil {
+				t.Fatalf("JSONSchema: cannot unmarshal document: %v", err)
+			}
+			if doc["$schema"] != ygot.JSONSchemaDialect {
+				t.Errorf("JSONSchema: got $schema %v, want %s", doc["$schema"], ygot.JSONSchemaDialect)
+			}
+			member := doc["properties"].(map[string]any)[tt.inMember]
+			if diff := cmp.Diff(tt.want, member); diff != "" {
+				t.Errorf("JSONSchema: did not get expected schema for %s, diff(-want,+got):\n%s", tt.inMember, diff)
+			}
+		})
+	}
+}
+
+// validateJSONSchema checks the JSON value v against the subset of JSON Schema
+// used by the documents returned by ygot.JSONSchema.
+func validateJSONSchema(s map[string]any, v any, path string) error {
+	if anyOf, ok := s["anyOf"].([]any); ok {
+		for _, as := range anyOf {
+			if validateJSONSchema(as.(map[string]any), v, path) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %v does not match any schema of anyOf", path, v)
+	}
+
+	switch s["type"] {
+	case "object":
+		o, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: %v is not an object", path, v)
+		}
+		props := s["properties"].(map[string]any)
+		for k, kv := range o {
+			ps, ok := props[k]
+			if !ok {
+				return fmt.Errorf("%s: unexpected member %s", path, k)
+			}
+			if err := validateJSONSchema(ps.(map[string]any), kv, path+"/"+k); err != nil {
+				return err
+			}
+		}
+		if req, ok := s["required"].([]any); ok {
+			for _, r := range req {
+				if _, ok := o[r.(string)]; !ok {
+					return fmt.Errorf("%s: missing required member %s", path, r)
+				}
+			}
+		}
+	case "array":
+		a, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s: %v is not an array", path, v)
+		}
+		for i, e := range a {
+			if err := validateJSONSchema(s["items"].(map[string]any), e, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: %v is not a string", path, v)
+		}
+		if p, ok := s["pattern"].(string); ok && !regexp.MustCompile(p).MatchString(str) {
+			return fmt.Errorf("%s: %q does not match pattern %s", path, str, p)
+		}
+	case "integer", "number":
+		if _, ok := v.(float64); !ok {
+			return fmt.Errorf("%s: %v is not a number", path, v)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: %v is not a boolean", path, v)
+		}
+	}
+	return nil
+}
+
+func TestJSONSchemaDescribesRFC7951(t *testing.T) {
+	d := &ctestschema.Device{
+		OrderedList: ctestschema.GetOrderedMap(t),
+		OtherData:   &ctestschema.OtherData{Motd: ygot.String("hello")},
+	}
+	ul, err := d.NewUnorderedList("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ul.Value = ygot.String("foo-val")
+	ml, err := d.AppendNewOrderedMultikeyedList("foo", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ml.Value = ygot.String("foo-val")
+
+	js, err := ygot.Marshal7951(d, &ygot.RFC7951JSONConfig{AppendModuleName: true})
+	if err != nil {
+		t.Fatalf("cannot marshal GoStruct: %v", err)
+	}
+	var v any
+	if err := json.Unmarshal(js, &v); err != nil {
+		t.Fatalf("cannot unmarshal JSON: %v", err)
+	}
+
+	doc, err := ygot.JSONSchema(d, ctestschema.SchemaTree["Device"])
+	if err != nil {
+		t.Fatalf("JSONSchema: %v", err)
+	}
+	var s map[string]any
+	if err := json.Unmarshal(doc, &s); err != nil {
+		t.Fatalf("JSONSchema: cannot unmarshal document: %v", err)
+	}
+
+	if err := validateJSONSchema(s, v, ""); err != nil {
+		t.Errorf("JSONSchema: RFC7951 JSON %s does not match document: %v", js, err)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+func TestTypeJSONSchema(t *testing.T) {
+	enum := yang.NewEnumType()
+	enum.Set("RED", 0)
+	enum.Set("BLUE", 1)
+
+	container := &yang.Entry{
+		Name: "container",
+		Kind: yang.DirectoryEntry,
+		Dir:  map[string]*yang.Entry{},
+	}
+	target := &yang.Entry{
+		Name:   "target",
+		Kind:   yang.LeafEntry,
+		Type:   &yang.YangType{Kind: yang.Yint64},
+		Parent: container,
+	}
+	leaf := &yang.Entry{
+		Name:   "leaf",
+		Kind:   yang.LeafEntry,
+		Parent: container,
+	}
+	loop := &yang.Entry{
+		Name:   "loop",
+		Kind:   yang.LeafEntry,
+		Type:   &yang.YangType{Kind: yang.Yleafref, Path: "../leaf"},
+		Parent: container,
+	}
+	container.Dir["target"] = target
+	container.Dir["leaf"] = leaf
+	container.Dir["loop"] = loop
+
+	tests := []struct {
+		desc             string
+		in               *yang.YangType
+		want             map[string]any
+		wantErrSubstring string
+	}{{
+		desc: "integer with ranges",
+		in: &yang.YangType{Kind: yang.Yuint16, Range: yang.YangRange{
+			{Min: yang.FromInt(1), Max: yang.FromInt(10)},
+			{Min: yang.FromInt(20), Max: yang.FromInt(30)},
+		}},
+		want: map[string]any{"type": "integer", "anyOf": []any{
+			map[string]any{"minimum": json.Number("1"), "maximum": json.Number("10")},
+			map[string]any{"minimum": json.Number("20"), "maximum": json.Number("30")},
+		}},
+	}, {
+		desc: "decimal64",
+		in:   &yang.YangType{Kind: yang.Ydecimal64, FractionDigits: 2},
+		want: map[string]any{"type": "string", "pattern": `^-?[0-9]+(\.[0-9]+)?$`},
+	}, {
+		desc: "string with length and XSD pattern",
+		in: &yang.YangType{Kind: yang.Ystring, Pattern: []string{"a+"}, Length: yang.YangRange{
+			{Min: yang.FromInt(1), Max: yang.FromInt(8)},
+		}},
+		want: map[string]any{"type": "string", "pattern": "^(?:a+)$", "minLength": json.Number("1"), "maxLength": json.Number("8")},
+	}, {
+		desc: "string with POSIX patterns",
+		in:   &yang.YangType{Kind: yang.Ystring, Pattern: []string{"a+"}, POSIXPattern: []string{"^a+$", "^.{2}$"}},
+		want: map[string]any{"type": "string", "allOf": []any{
+			map[string]any{"pattern": "^a+$"},
+			map[string]any{"pattern": "^.{2}$"},
+		}},
+	}, {
+		desc: "empty",
+		in:   &yang.YangType{Kind: yang.Yempty},
+		want: map[string]any{"type": "array", "items": map[string]any{"type": "null"}, "minItems": 1, "maxItems": 1},
+	}, {
+		desc: "enumeration",
+		in:   &yang.YangType{Kind: yang.Yenum, Enum: enum},
+		want: map[string]any{"type": "string", "enum": []string{"BLUE", "RED"}},
+	}, {
+		desc: "union",
+		in: &yang.YangType{Kind: yang.Yunion, Type: []*yang.YangType{
+			{Kind: yang.Ybool},
+			{Kind: yang.Ybinary},
+		}},
+		want: map[string]any{"anyOf": []any{
+			map[string]any{"type": "boolean"},
+			map[string]any{"type": "string", "contentEncoding": "base64"},
+		}},
+	}, {
+		desc: "leafref",
+		in:   &yang.YangType{Kind: yang.Yleafref, Path: "../target"},
+		want: map[string]any{"type": "string", "pattern": `^-?[0-9]+$`},
+	}, {
+		desc:             "unresolvable leafref",
+		in:               &yang.YangType{Kind: yang.Yleafref, Path: "../missing"},
+		wantErrSubstring: "cannot resolve leafref",
+	}, {
+		desc:             "leafref cycle",
+		in:               &yang.YangType{Kind: yang.Yleafref, Path: "../loop"},
+		wantErrSubstring: "leafref cycle: /container/leaf -> /container/loop -> /container/leaf",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			leaf.Type = tt.in
+			got, err := typeJSONSchema(nil, leaf, tt.in)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("typeJSONSchema: did not get expected error, %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("typeJSONSchema: did not get expected schema, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// MetadataAnnotation is an Annotation that represents a metadata annotation
+// defined using the md:annotation statement of the ietf-yang-metadata module
+// (RFC7952). When annotation fields are generated, a type implementing
+// MetadataAnnotation is generated for each metadata annotation defined within
+// the input YANG modules.
+//
+// Where all of the Annotations within an annotation field are
+// MetadataAnnotations, the field is marshalled to JSON as an object whose
+// members are named by the ΛMetadataName of each annotation, as described by
+// RFC7952, rather than as an array.
+type MetadataAnnotation interface {
+	Annotation
+	// ΛMetadataName returns the name of the annotation, qualified by the
+	// name of the module that defines it, e.g., "ietf-origin:origin".
+	ΛMetadataName() string
+}
+
+// MetadataAnnotationTypeMapper is an interface implemented by GoStructs whose
+// annotation fields can be populated with generated MetadataAnnotations when
+// unmarshalling JSON.
+type MetadataAnnotationTypeMapper interface {
+	// ΛMetadataAnnotationTypeMap returns a map, keyed by the qualified name
+	// of each metadata annotation, of the type that represents it. A
+	// pointer to the type must implement MetadataAnnotation.
+	ΛMetadataAnnotationTypeMap() map[string]reflect.Type
+}
+
+// MarshalMetadataValue marshals v, the value of a metadata annotation, to
+// RFC7951 JSON. As per RFC7951, 64-bit integers and decimal64 values, which
+// are represented as float64, are encoded as JSON strings.
+func MarshalMetadataValue(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case int64:
+		return json.Marshal(strconv.FormatInt(v, 10))
+	case uint64:
+		return json.Marshal(strconv.FormatUint(v, 10))
+	case float64:
+		return json.Marshal(strconv.FormatFloat(v, 'f', -1, 64))
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalMetadataValue unmarshals the RFC7951 JSON value of a metadata
+// annotation, b, into v, which must be a pointer. 64-bit integers and
+// decimal64 values are accepted either as JSON strings, as specified by
+// RFC7951, or as JSON numbers.
+func UnmarshalMetadataValue(b []byte, v any) error {
+	var s string
+	switch v := v.(type) {
+	case *int64:
+		if err := unmarshalMetadataNumber(b, &s); err != nil {
+			return err
+		}
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid int64 metadata value %s: %v", b, err)
+		}
+		*v = i
+		return nil
+	case *uint64:
+		if err := unmarshalMetadataNumber(b, &s); err != nil {
+			return err
+		}
+		u, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid uint64 metadata value %s: %v", b, err)
+		}
+		*v = u
+		return nil
+	case *float64:
+		if err := unmarshalMetadataNumber(b, &s); err != nil {
+			return err
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid decimal64 metadata value %s: %v", b, err)
+		}
+		*v = f
+		return nil
+	}
+	return json.Unmarshal(b, v)
+}
+
+// unmarshalMetadataNumber stores the string representation of the number b,
+// which is either a JSON string or a JSON number, in s.
+func unmarshalMetadataNumber(b []byte, s *string) error {
+	if err := json.Unmarshal(b, s); err == nil {
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return fmt.Errorf("invalid numeric metadata value %s: %v", b, err)
+	}
+	*s = n.String()
+	return nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+type metadataOwner struct {
+	Value string
+}
+
+func (*metadataOwner) ΛMetadataName() string          { return "test-module:owner" }
+func (a *metadataOwner) MarshalJSON() ([]byte, error) { return MarshalMetadataValue(a.Value) }
+func (a *metadataOwner) UnmarshalJSON(b []byte) error { return UnmarshalMetadataValue(b, &a.Value) }
+
+type metadataTimestamp struct {
+	Value uint64
+}
+
+func (*metadataTimestamp) ΛMetadataName() string          { return "test-module:timestamp" }
+func (a *metadataTimestamp) MarshalJSON() ([]byte, error) { return MarshalMetadataValue(a.Value) }
+func (a *metadataTimestamp) UnmarshalJSON(b []byte) error { return UnmarshalMetadataValue(b, &a.Value) }
+
+type metadataStruct struct {
+	ΛMetadata []Annotation `path:"@" ygotAnnotation:"true"`
+	Leaf      *string      `path:"leaf"`
+	ΛLeaf     []Annotation `path:"@leaf" ygotAnnotation:"true"`
+}
+
+func (*metadataStruct) IsYANGGoStruct() {}
+
+func TestConstructJSONMetadataAnnotations(t *testing.T) {
+	tests := []struct {
+		desc             string
+		in               *metadataStruct
+		want             map[string]any
+		wantErrSubstring string
+	}{{
+		desc: "metadata annotations of struct and leaf",
+		in: &metadataStruct{
+			ΛMetadata: []Annotation{&metadataOwner{Value: "alice"}, &metadataTimestamp{Value: 18446744073709551615}},
+			Leaf:      String("value"),
+			ΛLeaf:     []Annotation{&metadataOwner{Value: "bob"}},
+		},
+		want: map[string]any{
+			"@": map[string]any{
+				"test-module:owner":     "alice",
+				"test-module:timestamp": "18446744073709551615",
+			},
+			"leaf": "value",
+			"@leaf": map[string]any{
+				"test-module:owner": "bob",
+			},
+		},
+	}, {
+		desc: "other annotations are marshalled as an array",
+		in: &metadataStruct{
+			ΛMetadata: []Annotation{&metadataOwner{Value: "alice"}, &testAnnotation{AnnotationFieldOne: "a"}},
+		},
+		want: map[string]any{
+			"@": []any{"alice", map[string]any{"field": "a"}},
+		},
+	}, {
+		desc: "duplicate metadata annotations",
+		in: &metadataStruct{
+			ΛMetadata: []Annotation{&metadataOwner{Value: "alice"}, &metadataOwner{Value: "bob"}},
+		},
+		wantErrSubstring: "duplicate metadata annotation test-module:owner",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ConstructIETFJSON(tt.in, nil)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("ConstructIETFJSON: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ConstructIETFJSON (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMetadataValue(t *testing.T) {
+	tests := []struct {
+		desc     string
+		in       any
+		wantJSON string
+		// out is a pointer to a new value of the type of in.
+		out any
+	}{
+		{desc: "int8", in: int8(-1), wantJSON: `-1`, out: new(int8)},
+		{desc: "uint32", in: uint32(2), wantJSON: `2`, out: new(uint32)},
+		{desc: "int64", in: int64(-9223372036854775808), wantJSON: `"-9223372036854775808"`, out: new(int64)},
+		{desc: "uint64", in: uint64(18446744073709551615), wantJSON: `"18446744073709551615"`, out: new(uint64)},
+		{desc: "decimal64", in: 3.25, wantJSON: `"3.25"`, out: new(float64)},
+		{desc: "bool", in: true, wantJSON: `true`, out: new(bool)},
+		{desc: "string", in: "s", wantJSON: `"s"`, out: new(string)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := MarshalMetadataValue(tt.in)
+			if err != nil {
+				t.Fatalf("MarshalMetadataValue: %v", err)
+			}
+			if string(b) != tt.wantJSON {
+				t.Errorf("MarshalMetadataValue: got %s, want %s", b, tt.wantJSON)
+			}
+			if err := UnmarshalMetadataValue(b, tt.out); err != nil {
+				t.Fatalf("UnmarshalMetadataValue: %v", err)
+			}
+			if got := reflect.ValueOf(tt.out).Elem().Interface(); got != tt.in {
+				t.Errorf("UnmarshalMetadataValue: got %v, want %v", got, tt.in)
+			}
+		})
+	}
+
+	var u uint64
+	if err := UnmarshalMetadataValue([]byte("42"), &u); err != nil || u != 42 {
+		t.Errorf("UnmarshalMetadataValue(42): got (%d, %v), want (42, nil)", u, err)
+	}
+	if err := UnmarshalMetadataValue([]byte(`"forty-two"`), &u); err == nil {
+		t.Errorf("UnmarshalMetadataValue(\"forty-two\"): got nil error, want error")
+	}
+}
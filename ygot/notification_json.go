@@ -0,0 +1,222 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/openconfig/goyang/pkg/yang"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// notificationJSON is the JSON representation of a Notification that is
+// output by NotificationsToJSON.
+type notificationJSON struct {
+	// Timestamp is the timestamp of the Notification in nanoseconds since
+	// the Unix epoch.
+	Timestamp int64 `json:"timestamp,omitempty"`
+	// Time is the timestamp of the Notification in RFC3339 format.
+	Time string `json:"time,omitempty"`
+	// Target is the target of the prefix of the Notification.
+	Target string `json:"target,omitempty"`
+	// Atomic indicates that the Notification is atomic.
+	Atomic bool `json:"atomic,omitempty"`
+	// Update maps the paths of the updates, including the prefix, to
+	// their decoded values.
+	Update map[string]any `json:"update,omitempty"`
+	// Delete contains the paths of the deletions, including the prefix.
+	Delete []string `json:"delete,omitempty"`
+}
+
+// NotificationsToJSON renders the supplied Notifications as an indented JSON
+// document that is intended to be read when debugging, rather than to be
+// parsed. The document is an array containing an object for each
+// Notification, in which the updates are represented by an object whose
+// members are the paths of the updated nodes, including the prefix of the
+// Notification, and whose values are the decoded values of the updates. The
+// paths of deleted nodes are listed separately, such that deletions can be
+// distinguished from updates. Scalar values are rendered as the corresponding
+// JSON types, JSON-encoded values are embedded within the document rather than
+// rendered as base64-encoded strings, and leaf-lists are rendered as arrays.
+//
+// If schema is non-nil, it must be the root of the uncompressed YANG schema
+// against which the Notifications' paths are expressed, e.g., the entry for
+// the root of a generated schema tree. It is used to render integer values of
+// enumerated leaves as the names of the corresponding enumeration values.
+// Values of identityref leaves are carried as names within gNMI TypedValues,
+// and hence are rendered unchanged. Paths that are not found within the schema are rendered without decoding,
+// since the Notifications being debugged may not be valid against it.
+func NotificationsToJSON(schema *yang.Entry, ns []*gnmipb.Notification) ([]byte, error) {
+	out := []*notificationJSON{}
+	for _, n := range ns {
+		nj := &notificationJSON{
+			Timestamp: n.GetTimestamp(),
+			Target:    n.GetPrefix().GetTarget(),
+			Atomic:    n.GetAtomic(),
+		}
+		if n.GetTimestamp() != 0 {
+			nj.Time = time.Unix(0, n.GetTimestamp()).UTC().Format(time.RFC3339Nano)
+		}
+		for _, p := range n.GetDelete() {
+			ps, err := notificationPathString(n.GetPrefix(), p)
+			if err != nil {
+				return nil, err
+			}
+			nj.Delete = append(nj.Delete, ps)
+		}
+		for _, u := range n.GetUpdate() {
+			ps, err := notificationPathString(n.GetPrefix(), u.GetPath())
+			if err != nil {
+				return nil, err
+			}
+			var e *yang.Entry
+			if schema != nil {
+				// The schema is only used to decode values, such
+				// that paths that cannot be found are tolerated.
+				e, _ = schemaForNotificationPath(schema, n.GetPrefix(), u.GetPath())
+			}
+			v, err := decodeTypedValueForJSON(e, u.GetVal())
+			if err != nil {
+				return nil, fmt.Errorf("cannot decode value of update to %s: %v", ps, err)
+			}
+			if nj.Update == nil {
+				nj.Update = map[string]any{}
+			}
+			nj.Update[ps] = v
+		}
+		out = append(out, nj)
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// notificationPathString returns the string representation of the supplied
+// path, which is relative to prefix.
+func notificationPathString(prefix, path *gnmipb.Path) (string, error) {
+	p := &gnmipb.Path{
+		Elem: append(append([]*gnmipb.PathElem{}, prefix.GetElem()...), path.GetElem()...),
+	}
+	s, err := PathToString(p)
+	if err != nil {
+		return "", fmt.Errorf("cannot convert path %v to string: %v", p, err)
+	}
+	if origin := path.GetOrigin(); origin != "" {
+		s = origin + ":" + s
+	} else if origin := prefix.GetOrigin(); origin != "" {
+		s = origin + ":" + s
+	}
+	return s, nil
+}
+
+// decodeTypedValueForJSON returns the value of tv in a form that can be
+// marshalled to JSON. If e is non-nil, it is the schema of the leaf or
+// leaf-list whose value is tv, and integer values of enumerated leaves are
+// returned as the names of the enumeration values.
+func decodeTypedValueForJSON(e *yang.Entry, tv *gnmipb.TypedValue) (any, error) {
+	switch v := tv.GetValue().(type) {
+	case nil:
+		return nil, nil
+	case *gnmipb.TypedValue_StringVal:
+		return v.StringVal, nil
+	case *gnmipb.TypedValue_AsciiVal:
+		return v.AsciiVal, nil
+	case *gnmipb.TypedValue_BoolVal:
+		return v.BoolVal, nil
+	case *gnmipb.TypedValue_IntVal:
+		if name, ok := enumName(e, v.IntVal); ok {
+			return name, nil
+		}
+		return v.IntVal, nil
+	case *gnmipb.TypedValue_UintVal:
+		if v.UintVal <= math.MaxInt64 {
+			if name, ok := enumName(e, int64(v.UintVal)); ok {
+				return name, nil
+			}
+		}
+		return v.UintVal, nil
+	case *gnmipb.TypedValue_DoubleVal:
+		return jsonFloat(v.DoubleVal), nil
+	//lint:ignore SA1019 Specifically handling deprecated gNMI FloatVal field.
+	case *gnmipb.TypedValue_FloatVal:
+		//lint:ignore SA1019 Specifically handling deprecated gNMI FloatVal field.
+		return jsonFloat(float64(v.FloatVal)), nil
+	//lint:ignore SA1019 Specifically handling deprecated gNMI DecimalVal field.
+	case *gnmipb.TypedValue_DecimalVal:
+		//lint:ignore SA1019 Specifically handling deprecated gNMI DecimalVal field.
+		d := v.DecimalVal
+		return json.Number(strconv.FormatFloat(float64(d.GetDigits())/math.Pow10(int(d.GetPrecision())), 'f', int(d.GetPrecision()), 64)), nil
+	case *gnmipb.TypedValue_BytesVal:
+		// Binary values are marshalled as base64-encoded strings, as
+		// specified by RFC7951.
+		return v.BytesVal, nil
+	case *gnmipb.TypedValue_ProtoBytes:
+		return v.ProtoBytes, nil
+	case *gnmipb.TypedValue_JsonVal:
+		return decodeEmbeddedJSON(v.JsonVal), nil
+	case *gnmipb.TypedValue_JsonIetfVal:
+		return decodeEmbeddedJSON(v.JsonIetfVal), nil
+	case *gnmipb.TypedValue_LeaflistVal:
+		vals := []any{}
+		for _, elem := range v.LeaflistVal.GetElement() {
+			ev, err := decodeTypedValueForJSON(e, elem)
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, ev)
+		}
+		return vals, nil
+	case *gnmipb.TypedValue_AnyVal:
+		return map[string]any{
+			"@type": v.AnyVal.GetTypeUrl(),
+			"value": v.AnyVal.GetValue(),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported TypedValue type %T", v)
+	}
+}
+
+// enumName returns the name of the value v of the enumeration that is the
+// type of the leaf or leaf-list e, and true if e is an enumerated leaf that
+// defines a value v.
+func enumName(e *yang.Entry, v int64) (string, bool) {
+	if e == nil || e.Type == nil || e.Type.Kind != yang.Yenum || e.Type.Enum == nil {
+		return "", false
+	}
+	name, ok := e.Type.Enum.ValueMap()[v]
+	return name, ok
+}
+
+// jsonFloat returns the value f in a form that can be marshalled to JSON,
+// which does not support NaN and infinite values.
+func jsonFloat(f float64) any {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return f
+}
+
+// decodeEmbeddedJSON returns the JSON document b as a value that is
+// marshalled as the same document, such that it is embedded within the
+// output of NotificationsToJSON, or as a string if it is not valid JSON.
+func decodeEmbeddedJSON(b []byte) any {
+	if !json.Valid(b) {
+		return string(b)
+	}
+	return json.RawMessage(b)
+}
@@ -0,0 +1,166 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/goyang/pkg/yang"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// notificationsJSONSchema returns a schema containing an enumerated leaf and
+// a leaf-list at /parent/state.
+func notificationsJSONSchema() *yang.Entry {
+	enum := yang.NewEnumType()
+	enum.Set("UP", 0)
+	enum.Set("DOWN", 1)
+	root := &yang.Entry{Name: "device", Kind: yang.DirectoryEntry, Dir: map[string]*yang.Entry{}}
+	parent := &yang.Entry{Name: "parent", Kind: yang.DirectoryEntry, Parent: root, Dir: map[string]*yang.Entry{}}
+	state := &yang.Entry{Name: "state", Kind: yang.DirectoryEntry, Parent: parent, Dir: map[string]*yang.Entry{}}
+	state.Dir["status"] = &yang.Entry{Name: "status", Kind: yang.LeafEntry, Parent: state, Type: &yang.YangType{Kind: yang.Yenum, Enum: enum}}
+	state.Dir["history"] = &yang.Entry{Name: "history", Kind: yang.LeafEntry, Parent: state, ListAttr: &yang.ListAttr{}, Type: &yang.YangType{Kind: yang.Yenum, Enum: enum}}
+	state.Dir["counter"] = &yang.Entry{Name: "counter", Kind: yang.LeafEntry, Parent: state, Type: &yang.YangType{Kind: yang.Yuint64}}
+	parent.Dir["state"] = state
+	root.Dir["parent"] = parent
+	return root
+}
+
+func TestNotificationsToJSON(t *testing.T) {
+	path := func(elems ...string) *gnmipb.Path {
+		p := &gnmipb.Path{}
+		for _, e := range elems {
+			p.Elem = append(p.Elem, &gnmipb.PathElem{Name: e})
+		}
+		return p
+	}
+
+	tests := []struct {
+		desc             string
+		inSchema         *yang.Entry
+		inNotifications  []*gnmipb.Notification
+		want             string
+		wantErrSubstring string
+	}{{
+		desc: "no notifications",
+		want: "[]",
+	}, {
+		desc: "updates and deletes without schema",
+		inNotifications: []*gnmipb.Notification{{
+			Timestamp: 1000000001,
+			Prefix:    &gnmipb.Path{Target: "dut", Elem: []*gnmipb.PathElem{{Name: "parent"}}},
+			Update: []*gnmipb.Update{{
+				Path: path("state", "status"),
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_IntVal{IntVal: 1}},
+			}, {
+				Path: path("state", "counter"),
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_UintVal{UintVal: 42}},
+			}, {
+				Path: path("config"),
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`{"name":"x","mtu":1500}`)}},
+			}, {
+				Path: path("state", "key"),
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_BytesVal{BytesVal: []byte("abc")}},
+			}, {
+				Path: path("state", "ratio"),
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_DoubleVal{DoubleVal: math.Inf(1)}},
+			}},
+			Delete: []*gnmipb.Path{path("state", "old")},
+		}},
+		want: `[
+  {
+    "timestamp": 1000000001,
+    "time": "1970-01-01T00:00:01.000000001Z",
+    "target": "dut",
+    "update": {
+      "/parent/config": {
+        "name": "x",
+        "mtu": 1500
+      },
+      "/parent/state/counter": 42,
+      "/parent/state/key": "YWJj",
+      "/parent/state/ratio": "+Inf",
+      "/parent/state/status": 1
+    },
+    "delete": [
+      "/parent/state/old"
+    ]
+  }
+]`,
+	}, {
+		desc:     "enumerated values decoded with schema",
+		inSchema: notificationsJSONSchema(),
+		inNotifications: []*gnmipb.Notification{{
+			Atomic: true,
+			Update: []*gnmipb.Update{{
+				Path: path("parent", "state", "status"),
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_IntVal{IntVal: 1}},
+			}, {
+				Path: path("parent", "state", "history"),
+				Val: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_LeaflistVal{LeaflistVal: &gnmipb.ScalarArray{
+					Element: []*gnmipb.TypedValue{
+						{Value: &gnmipb.TypedValue_UintVal{UintVal: 0}},
+						{Value: &gnmipb.TypedValue_StringVal{StringVal: "DOWN"}},
+					},
+				}}},
+			}, {
+				Path: path("parent", "state", "counter"),
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_UintVal{UintVal: 1}},
+			}, {
+				Path: path("parent", "state", "unknown"),
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_IntVal{IntVal: 1}},
+			}},
+		}},
+		want: `[
+  {
+    "atomic": true,
+    "update": {
+      "/parent/state/counter": 1,
+      "/parent/state/history": [
+        "UP",
+        "DOWN"
+      ],
+      "/parent/state/status": "DOWN",
+      "/parent/state/unknown": 1
+    }
+  }
+]`,
+	}, {
+		desc: "invalid path",
+		inNotifications: []*gnmipb.Notification{{
+			Delete: []*gnmipb.Path{{Elem: []*gnmipb.PathElem{{Name: ""}}}},
+		}},
+		wantErrSubstring: "cannot convert path",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := NotificationsToJSON(tt.inSchema, tt.inNotifications)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("NotificationsToJSON: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, string(got)); diff != "" {
+				t.Errorf("NotificationsToJSON: did not get expected JSON (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/openconfig/gnmi/errlist"
+	"github.com/openconfig/ygot/util"
+)
+
+// unsetEnumValue is the value of an enumerated field that marks it as unset
+// in an overlay. Generated enumerated types only use non-negative values.
+const unsetEnumValue = -1
+
+// unsetSentinels maps the type of each sentinel returned by Unset, UnsetSlice
+// or UnsetMap to the sentinel.
+var unsetSentinels sync.Map
+
+// unsetBox is used to allocate pointer sentinels, such that each sentinel has
+// a unique address even when T has zero size.
+type unsetBox[T any] struct {
+	v T
+	_ byte
+}
+
+// Unset returns the sentinel value which, when used as the value of a pointer
+// field of an overlay supplied to ApplyOverlay, marks the corresponding field
+// of the base struct to be deleted. It can be used for leaves, containers and
+// ordered lists, as well as for the entries of keyed lists, e.g.:
+//
+//	overlay.Hostname = ygot.Unset[string]()
+//	overlay.Interface["eth0"] = ygot.Unset[oc.Interface]()
+//
+// The sentinel is identified by its address, so it must not be modified.
+func Unset[T any]() *T {
+	s, _ := unsetSentinels.LoadOrStore(reflect.TypeOf((*T)(nil)), &(&unsetBox[T]{}).v)
+	return s.(*T)
+}
+
+// UnsetSlice returns the sentinel value which, when used as the value of a
+// leaf-list or unkeyed list field of an overlay supplied to ApplyOverlay,
+// marks the corresponding field of the base struct to be deleted.
+func UnsetSlice[T any]() []T {
+	s, _ := unsetSentinels.LoadOrStore(reflect.TypeOf([]T(nil)), make([]T, 0, 1))
+	return s.([]T)
+}
+
+// UnsetMap returns the sentinel value which, when used as the value of a keyed
+// list field of an overlay supplied to ApplyOverlay, marks the corresponding
+// field of the base struct to be deleted.
+func UnsetMap[K comparable, V any]() map[K]V {
+	s, _ := unsetSentinels.LoadOrStore(reflect.TypeOf(map[K]V(nil)), map[K]V{})
+	return s.(map[K]V)
+}
+
+// UnsetEnum returns the sentinel value which, when used as the value of an
+// enumerated field of an overlay supplied to ApplyOverlay, marks the
+// corresponding field of the base struct to be deleted.
+func UnsetEnum[T interface {
+	GoEnum
+	~int64
+}]() T {
+	return T(unsetEnumValue)
+}
+
+// isUnset reports whether v is a sentinel returned by Unset, UnsetSlice,
+// UnsetMap or UnsetEnum.
+func isUnset(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return false
+		}
+		s, ok := unsetSentinels.Load(v.Type())
+		if !ok {
+			return false
+		}
+		// Slices that share the backing array of the sentinel, or the
+		// sentinel map, are only the sentinel if they are empty.
+		return reflect.ValueOf(s).Pointer() == v.Pointer() && (v.Kind() == reflect.Ptr || v.Len() == 0)
+	case reflect.Int64:
+		_, isEnum := v.Interface().(GoEnum)
+		return isEnum && v.Int() == unsetEnumValue
+	}
+	return false
+}
+
+// ApplyOverlay applies the overlay GoStruct to the base GoStruct, returning the
+// result as a new GoStruct, and leaving base and overlay unmodified. If the
+// input structs are of different types, an error is returned.
+//
+// Fields of the overlay that are set to the sentinels returned by Unset,
+// UnsetSlice, UnsetMap and UnsetEnum delete the corresponding fields of base.
+// Other fields that are populated in the overlay are merged into base, as per
+// MergeStructs with MergeOverwriteExistingFields. Leaf-lists and unkeyed lists
+// that are populated in the overlay replace those of base. Union fields and the
+// entries of ordered maps cannot be deleted using sentinels.
+//
+// The supplied MergeOpts are used when merging the overlay. In particular,
+// MergeReportConflicts can be used to retrieve the set of leaves of base that
+// are overwritten by a different value.
+func ApplyOverlay(base, overlay GoStruct, opts ...MergeOpt) (GoStruct, error) {
+	if reflect.TypeOf(base) != reflect.TypeOf(overlay) {
+		return nil, fmt.Errorf("cannot apply overlay that is not of the same type as the base struct, %T != %T", overlay, base)
+	}
+
+	dst, err := deepCopy(base, mergeEmptyMapsEnabled(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, &MergeOverwriteExistingFields{})
+	if err := overlayStruct(reflect.ValueOf(dst).Elem(), reflect.ValueOf(overlay).Elem(), "", opts...); err != nil {
+		return nil, fmt.Errorf("error applying overlay to new struct: %w", err)
+	}
+	return dst, nil
+}
+
+// overlayStruct applies the fields of the overlay struct srcVal to the dstVal
+// struct in-place. accessPath is the programmatic access path to the struct.
+//
+// It fails-slow: accumulates errors prior to return.
+func overlayStruct(dstVal, srcVal reflect.Value, accessPath string, opts ...MergeOpt) error {
+	if srcVal.Type() != dstVal.Type() {
+		return fmt.Errorf("cannot apply overlay %s to %s", srcVal.Type().Name(), dstVal.Type().Name())
+	}
+
+	var errs errlist.Error
+	errs.Separator = "\n"
+	for i := 0; i < srcVal.NumField(); i++ {
+		errs.Add(overlayField(dstVal.Field(i), srcVal.Field(i), accessPath+"."+srcVal.Type().Field(i).Name, opts...))
+	}
+	return listErr(errs)
+}
+
+// overlayField applies the overlay struct field srcField to the struct field
+// dstField. accessPath is the programmatic access path to the field.
+func overlayField(dstField, srcField reflect.Value, accessPath string, opts ...MergeOpt) error {
+	if isUnset(srcField) {
+		dstField.Set(reflect.Zero(dstField.Type()))
+		return nil
+	}
+
+	_, isOrderedMap := srcField.Interface().(GoOrderedMap)
+	switch {
+	case util.IsValueStructPtr(srcField) && !isOrderedMap:
+		// Containers are overlaid field by field, such that any sentinels
+		// within them are applied.
+		d := dstField
+		if util.IsNilOrInvalidValue(d) {
+			d = reflect.New(srcField.Type().Elem())
+		}
+		if err := overlayStruct(d.Elem(), srcField.Elem(), accessPath, opts...); err != nil {
+			return err
+		}
+		dstField.Set(d)
+		return nil
+	case util.IsValueMap(srcField) && util.IsTypeStructPtr(srcField.Type().Elem()):
+		if srcField.Len() == 0 {
+			return copyMapField(dstField, srcField, accessPath, opts...)
+		}
+		if dstField.IsNil() {
+			dstField.Set(reflect.MakeMapWithSize(srcField.Type(), srcField.Len()))
+		}
+		var errs errlist.Error
+		errs.Separator = "\n"
+		for _, k := range srcField.MapKeys() {
+			v := srcField.MapIndex(k)
+			if isUnset(v) {
+				dstField.SetMapIndex(k, reflect.Value{})
+				continue
+			}
+			if v.IsNil() {
+				errs.Add(fmt.Errorf("map key %v, got nil value", k.Interface()))
+				continue
+			}
+			d := dstField.MapIndex(k)
+			if !d.IsValid() {
+				d = reflect.New(v.Type().Elem())
+			}
+			if err := overlayStruct(d.Elem(), v.Elem(), fmt.Sprintf("%s[%#v]", accessPath, k.Interface()), opts...); err != nil {
+				errs.Add(err)
+				continue
+			}
+			dstField.SetMapIndex(k, d)
+		}
+		if dstField.Len() == 0 && !mergeEmptyMapsEnabled(opts) {
+			dstField.Set(reflect.Zero(dstField.Type()))
+		}
+		return listErr(errs)
+	case util.IsValueSlice(srcField) && srcField.Len() != 0:
+		if _, ok := srcField.Interface().([]Annotation); ok {
+			break
+		}
+		// Leaf-lists and unkeyed lists in the overlay replace those in the
+		// base struct, since their entries cannot otherwise be removed.
+		dstField.Set(reflect.Zero(dstField.Type()))
+	}
+	return copyField(dstField, srcField, accessPath, opts...)
+}
@@ -0,0 +1,176 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+func TestApplyOverlay(t *testing.T) {
+	tests := []struct {
+		name          string
+		inBase        GoStruct
+		inOverlay     GoStruct
+		inOpts        []MergeOpt
+		want          GoStruct
+		wantConflicts []*MergeConflict
+		wantErr       string
+	}{{
+		name:      "leaves overwritten and added",
+		inBase:    &copyTest{StringField: String("base"), Uint32Field: Uint32(1)},
+		inOverlay: &copyTest{StringField: String("overlay"), Uint16Field: Uint16(2)},
+		want:      &copyTest{StringField: String("overlay"), Uint32Field: Uint32(1), Uint16Field: Uint16(2)},
+	}, {
+		name:      "leaf unset",
+		inBase:    &copyTest{StringField: String("base"), Uint32Field: Uint32(1)},
+		inOverlay: &copyTest{StringField: Unset[string]()},
+		want:      &copyTest{Uint32Field: Uint32(1)},
+	}, {
+		name:      "unset leaf not in base",
+		inBase:    &copyTest{Uint32Field: Uint32(1)},
+		inOverlay: &copyTest{StringField: Unset[string]()},
+		want:      &copyTest{Uint32Field: Uint32(1)},
+	}, {
+		name:      "value equal to unset sentinel is not unset",
+		inBase:    &copyTest{StringField: String("base")},
+		inOverlay: &copyTest{StringField: String("")},
+		want:      &copyTest{StringField: String("")},
+	}, {
+		name:      "container unset",
+		inBase:    &copyTest{StringField: String("base"), StructPointer: &copyTest{StringField: String("child")}},
+		inOverlay: &copyTest{StructPointer: Unset[copyTest]()},
+		want:      &copyTest{StringField: String("base")},
+	}, {
+		name: "leaf within container unset",
+		inBase: &copyTest{
+			StructPointer: &copyTest{StringField: String("child"), Uint32Field: Uint32(1)},
+		},
+		inOverlay: &copyTest{
+			StructPointer: &copyTest{StringField: Unset[string](), Uint16Field: Uint16(2)},
+		},
+		want: &copyTest{
+			StructPointer: &copyTest{Uint32Field: Uint32(1), Uint16Field: Uint16(2)},
+		},
+	}, {
+		name: "list entries unset and overlaid",
+		inBase: &copyTest{
+			StringMap: map[string]*copyTest{
+				"one": {StringField: String("one")},
+				"two": {StringField: String("two"), Uint32Field: Uint32(2)},
+			},
+		},
+		inOverlay: &copyTest{
+			StringMap: map[string]*copyTest{
+				"one":   Unset[copyTest](),
+				"two":   {Uint32Field: Unset[uint32](), Uint16Field: Uint16(2)},
+				"three": {StringField: String("three")},
+			},
+		},
+		want: &copyTest{
+			StringMap: map[string]*copyTest{
+				"two":   {StringField: String("two"), Uint16Field: Uint16(2)},
+				"three": {StringField: String("three")},
+			},
+		},
+	}, {
+		name:      "all list entries unset",
+		inBase:    &copyTest{StringMap: map[string]*copyTest{"one": {StringField: String("one")}}},
+		inOverlay: &copyTest{StringMap: map[string]*copyTest{"one": Unset[copyTest]()}},
+		want:      &copyTest{},
+	}, {
+		name: "lists unset",
+		inBase: &copyTest{
+			StringField: String("base"),
+			StringMap:   map[string]*copyTest{"one": {StringField: String("one")}},
+			StructSlice: []*copyTest{{StringField: String("one")}},
+		},
+		inOverlay: &copyTest{StringMap: UnsetMap[string, *copyTest](), StructSlice: UnsetSlice[*copyTest]()},
+		want:      &copyTest{StringField: String("base")},
+	}, {
+		name:      "empty list is not unset",
+		inBase:    &copyTest{StringMap: map[string]*copyTest{"one": {StringField: String("one")}}},
+		inOverlay: &copyTest{StringMap: map[string]*copyTest{}},
+		want:      &copyTest{StringMap: map[string]*copyTest{"one": {StringField: String("one")}}},
+	}, {
+		name:      "enum overwritten",
+		inBase:    &copyTest{EnumValue: EnumTypeValue},
+		inOverlay: &copyTest{EnumValue: EnumTypeValueTwo},
+		want:      &copyTest{EnumValue: EnumTypeValueTwo},
+	}, {
+		name:      "enum unset",
+		inBase:    &copyTest{EnumValue: EnumTypeValue, StringField: String("base")},
+		inOverlay: &copyTest{EnumValue: UnsetEnum[enumType]()},
+		want:      &copyTest{StringField: String("base")},
+	}, {
+		name:      "leaf-list replaced",
+		inBase:    &copyTest{StringSlice: []string{"a", "b"}},
+		inOverlay: &copyTest{StringSlice: []string{"b", "c"}},
+		want:      &copyTest{StringSlice: []string{"b", "c"}},
+	}, {
+		name:      "leaf-list unset",
+		inBase:    &copyTest{StringSlice: []string{"a", "b"}, StringField: String("base")},
+		inOverlay: &copyTest{StringSlice: UnsetSlice[string]()},
+		want:      &copyTest{StringField: String("base")},
+	}, {
+		name:      "overwritten leaves reported",
+		inBase:    &copyTest{StringField: String("base"), Uint32Field: Uint32(1)},
+		inOverlay: &copyTest{StringField: String("overlay"), Uint32Field: Uint32(1)},
+		inOpts:    []MergeOpt{&MergeReportConflicts{}},
+		want:      &copyTest{StringField: String("overlay"), Uint32Field: Uint32(1)},
+		wantConflicts: []*MergeConflict{{
+			Path: "StringField",
+			Dst:  "base",
+			Src:  "overlay",
+		}},
+	}, {
+		name:      "different types",
+		inBase:    &copyTest{},
+		inOverlay: &mergeTest{},
+		wantErr:   "cannot apply overlay that is not of the same type as the base struct",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantBase, err := DeepCopy(tt.inBase)
+			if err != nil {
+				t.Fatalf("DeepCopy(%v): unexpected error with testdata, %v", tt.inBase, err)
+			}
+
+			got, err := ApplyOverlay(tt.inBase, tt.inOverlay, tt.inOpts...)
+			if diff := errdiff.Substring(err, tt.wantErr); diff != "" {
+				t.Fatalf("ApplyOverlay: did not get expected error, %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ApplyOverlay: did not get expected returned struct (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(wantBase, tt.inBase); diff != "" {
+				t.Errorf("ApplyOverlay: base struct was modified (-want, +got):\n%s", diff)
+			}
+
+			var gotConflicts []*MergeConflict
+			for _, o := range tt.inOpts {
+				if r, ok := o.(*MergeReportConflicts); ok {
+					gotConflicts = r.Conflicts
+				}
+			}
+			if diff := cmp.Diff(tt.wantConflicts, gotConflicts); diff != "" {
+				t.Errorf("ApplyOverlay: did not get expected conflicts (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
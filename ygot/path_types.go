@@ -17,6 +17,8 @@ package ygot
 import (
 	"fmt"
 
+	"github.com/openconfig/gnmi/errlist"
+
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
 )
 
@@ -39,8 +41,25 @@ type PathStruct interface {
 	relPath() ([]*gpb.PathElem, []error)
 }
 
-// NewNodePath is the constructor for NodePath.
+// GoStructPath is an interface that is implemented by generated path structs
+// of containers and lists when ypathgen is configured to link them to their
+// GoStruct types. T is the type of the GoStruct that the path struct refers
+// to, such that generic functions accepting a GoStructPath can return values
+// of the correct type without inspecting the path struct at runtime.
+type GoStructPath[T GoStruct] interface {
+	PathStruct
+	// ΛGoStruct returns the nil value of the GoStruct type that the path
+	// struct refers to.
+	ΛGoStruct() T
+}
+
+// NewNodePath is the constructor for NodePath. The parent p is marked as
+// used, such that modifying its keys subsequently is reported as an error by
+// ValidateKeys.
 func NewNodePath(relSchemaPath []string, keys map[string]interface{}, p PathStruct) *NodePath {
+	if pn, ok := p.(interface{ nodePath() *NodePath }); ok && pn.nodePath() != nil {
+		pn.nodePath().used = true
+	}
 	return &NodePath{relSchemaPath: relSchemaPath, keys: keys, p: p}
 }
 
@@ -52,6 +71,15 @@ type NodePath struct {
 	relSchemaPath []string
 	keys          map[string]interface{}
 	p             PathStruct
+	// keySet records the keys whose values have been set by ModifyKey.
+	keySet map[string]bool
+	// used indicates that the NodePath has been used as the parent of
+	// another path, or has been validated by ValidateKeys, such that its
+	// keys should no longer be modified.
+	used bool
+	// keyErrs are the errors in the use of ModifyKey, which are reported
+	// by ValidateKeys.
+	keyErrs errlist.List
 }
 
 // fakeRootPathStruct is an interface that is implemented by the fake root path
@@ -121,11 +149,44 @@ func ResolveRelPath(n PathStruct) ([]*gpb.PathElem, []error) {
 	return n.relPath()
 }
 
-// ModifyKey updates a NodePath's key value.
+// ModifyKey updates a NodePath's key value. Setting a key more than once, or
+// after the NodePath has been used, is recorded as an error that is reported
+// by ValidateKeys.
 func ModifyKey(n *NodePath, name string, value interface{}) {
+	if n.keySet[name] {
+		n.keyErrs.Add(fmt.Errorf("key %q set more than once, most recently to %v", name, value))
+	}
+	if n.used {
+		n.keyErrs.Add(fmt.Errorf("key %q set to %v after the path was used", name, value))
+	}
+	if n.keySet == nil {
+		n.keySet = map[string]bool{}
+	}
+	n.keySet[name] = true
 	n.keys[name] = value
 }
 
+// ValidateKeys returns an error if any key of the NodePath n was set more than
+// once by ModifyKey, or after n was used as the parent of another path or was
+// validated. Keys that are not set retain their wildcard values. It is used
+// by the Build method of the path structs of lists that use the builder API,
+// and marks n as used.
+func ValidateKeys(n *NodePath) error {
+	n.used = true
+	return n.keyErrs.Err()
+}
+
+// WithRelSchemaPath returns a copy of the NodePath n, with its relative schema
+// path replaced by relSchemaPath. It is used to obtain the path of a node's
+// counterpart with the same parent, e.g., the config version of a state leaf.
+func WithRelSchemaPath(n *NodePath, relSchemaPath []string) *NodePath {
+	keys := make(map[string]interface{}, len(n.keys))
+	for k, v := range n.keys {
+		keys[k] = v
+	}
+	return &NodePath{relSchemaPath: relSchemaPath, keys: keys, p: n.p}
+}
+
 // relPath converts the information stored in NodePath into the partial
 // []*gpb.PathElem representing the node's relative path.
 func (n *NodePath) relPath() ([]*gpb.PathElem, []error) {
@@ -154,3 +215,7 @@ func (n *NodePath) relPath() ([]*gpb.PathElem, []error) {
 }
 
 func (n *NodePath) parent() PathStruct { return n.p }
+
+// nodePath returns n, such that the NodePath embedded within a path struct can
+// be retrieved.
+func (n *NodePath) nodePath() *NodePath { return n }
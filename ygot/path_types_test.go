@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -169,3 +170,106 @@ func TestResolveRelPath(t *testing.T) {
 		})
 	}
 }
+
+func TestWithRelSchemaPath(t *testing.T) {
+	root := deviceRoot{NewDeviceRootBase("FOO")}
+	in := &NodePath{
+		relSchemaPath: []string{"state", "mtu"},
+		keys:          map[string]interface{}{},
+		p: &NodePath{
+			relSchemaPath: []string{"interfaces", "interface"},
+			keys:          map[string]interface{}{"name": "eth0"},
+			p:             root,
+		},
+	}
+
+	got := WithRelSchemaPath(in, []string{"config", "mtu"})
+	gotPath, _, errs := ResolvePath(got)
+	if errs != nil {
+		t.Fatalf("ResolvePath: got unexpected errors: %v", errs)
+	}
+	wantPath, err := StringToStructuredPath("/interfaces/interface[name=eth0]/config/mtu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPath.Target = "FOO"
+	if diff := cmp.Diff(wantPath, gotPath, cmp.Comparer(proto.Equal)); diff != "" {
+		t.Errorf("WithRelSchemaPath returned diff (-want, +got):\n%s", diff)
+	}
+
+	// The original NodePath must be unmodified.
+	if diff := cmp.Diff([]string{"state", "mtu"}, in.relSchemaPath); diff != "" {
+		t.Errorf("WithRelSchemaPath modified input NodePath (-want, +got):\n%s", diff)
+	}
+}
+
+// listPathAny is a path struct for a list that uses the builder API.
+type listPathAny struct {
+	*NodePath
+}
+
+func TestValidateKeys(t *testing.T) {
+	root := deviceRoot{NewDeviceRootBase("FOO")}
+	newList := func() *listPathAny {
+		return &listPathAny{NewNodePath([]string{"values", "value"}, map[string]interface{}{"a": "*", "b": "*"}, root)}
+	}
+
+	tests := []struct {
+		name             string
+		inFn             func(n *listPathAny)
+		wantKeys         map[string]interface{}
+		wantErrSubstring string
+	}{{
+		name:     "all keys wildcards",
+		inFn:     func(n *listPathAny) {},
+		wantKeys: map[string]interface{}{"a": "*", "b": "*"},
+	}, {
+		name: "each key set once",
+		inFn: func(n *listPathAny) {
+			ModifyKey(n.NodePath, "a", 1)
+			ModifyKey(n.NodePath, "b", 2)
+		},
+		wantKeys: map[string]interface{}{"a": 1, "b": 2},
+	}, {
+		name: "key set twice",
+		inFn: func(n *listPathAny) {
+			ModifyKey(n.NodePath, "a", 1)
+			ModifyKey(n.NodePath, "a", 2)
+		},
+		wantErrSubstring: `key "a" set more than once, most recently to 2`,
+	}, {
+		name: "key set after child path constructed",
+		inFn: func(n *listPathAny) {
+			ModifyKey(n.NodePath, "a", 1)
+			NewNodePath([]string{"state", "c"}, map[string]interface{}{}, n)
+			ModifyKey(n.NodePath, "b", 2)
+		},
+		wantErrSubstring: `key "b" set to 2 after the path was used`,
+	}, {
+		name: "key set after validation",
+		inFn: func(n *listPathAny) {
+			if err := ValidateKeys(n.NodePath); err != nil {
+				t.Fatalf("ValidateKeys: unexpected error: %v", err)
+			}
+			ModifyKey(n.NodePath, "a", 1)
+		},
+		wantErrSubstring: `key "a" set to 1 after the path was used`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newList()
+			tt.inFn(n)
+			err := ValidateKeys(n.NodePath)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("ValidateKeys: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.wantKeys, n.keys); diff != "" {
+				t.Errorf("ValidateKeys: did not get expected keys (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -51,7 +51,7 @@ func PathToString(path *gnmipb.Path) (string, error) {
 // only the name.
 func PathToSchemaPath(path *gnmipb.Path) (string, error) {
 	if path == nil {
-		return "", fmt.Errorf("received nil path in PathToSchemaPath")
+		return "", ClassifyError(ErrInvalidPath, fmt.Errorf("received nil path in PathToSchemaPath"))
 	}
 
 	//lint:ignore SA1019 Specifically handling deprecated gNMI Element fields.
@@ -61,18 +61,18 @@ func PathToSchemaPath(path *gnmipb.Path) (string, error) {
 		for _, e := range path.Element {
 			elem, _, err := extractKV(e)
 			if err != nil {
-				return "", fmt.Errorf("cannot extract element name from %s, %v", e, err)
+				return "", ClassifyError(ErrInvalidPath, fmt.Errorf("cannot extract element name from %s, %w", e, err))
 			}
 			sp = append(sp, elem)
 		}
 		s, err := elementsToString(sp)
-		return "/" + stdpath.Join(s...), err
+		return "/" + stdpath.Join(s...), ClassifyError(ErrInvalidPath, err)
 	}
 
 	var p []string
 	for i, e := range path.Elem {
 		if e.Name == "" {
-			return "", fmt.Errorf("empty name for PathElem at index %d", i)
+			return "", ClassifyError(ErrInvalidPath, fmt.Errorf("empty name for PathElem at index %d", i))
 		}
 		p = append(p, e.Name)
 	}
@@ -88,24 +88,25 @@ func PathToSchemaPath(path *gnmipb.Path) (string, error) {
 // and post-0.4.0 paths, the pre-0.4.0 version is returned.
 func PathToStrings(path *gnmipb.Path) ([]string, error) {
 	if path == nil {
-		return nil, fmt.Errorf("received nil path in PathToStrings")
+		return nil, ClassifyError(ErrInvalidPath, fmt.Errorf("received nil path in PathToStrings"))
 	}
 
 	//lint:ignore SA1019 Specifically handling deprecated gNMI Element fields.
 	if path.Element != nil {
 		//lint:ignore SA1019 Specifically handling deprecated gNMI Element fields.
-		return elementsToString(path.Element)
+		p, err := elementsToString(path.Element)
+		return p, ClassifyError(ErrInvalidPath, err)
 	}
 
 	var p []string
 	for i, e := range path.Elem {
 		if e.Name == "" {
-			return nil, fmt.Errorf("empty name for PathElem at index %d", i)
+			return nil, ClassifyError(ErrInvalidPath, fmt.Errorf("empty name for PathElem at index %d", i))
 		}
 
 		elem, err := elemToString(e.Name, e.Key)
 		if err != nil {
-			return nil, fmt.Errorf("failed formatting PathElem at index %d: %v", i, err)
+			return nil, ClassifyError(ErrInvalidPath, fmt.Errorf("failed formatting PathElem at index %d: %w", i, err))
 		}
 		p = append(p, elem)
 	}
@@ -202,11 +203,11 @@ func StringToStringSlicePath(path string) (*gnmipb.Path, error) {
 		// Run through extractKV to ensure that the path is valid.
 		name, kv, err := extractKV(p)
 		if err != nil {
-			return nil, fmt.Errorf("error parsing path %q: %v", path, err)
+			return nil, ClassifyError(ErrInvalidPath, fmt.Errorf("error parsing path %q: %w", path, err))
 		}
 		fpath, err := elemToString(name, kv)
 		if err != nil {
-			return nil, fmt.Errorf("error formatting path %q: %v", path, err)
+			return nil, ClassifyError(ErrInvalidPath, fmt.Errorf("error formatting path %q: %w", path, err))
 		}
 		//lint:ignore SA1019 Specifically handling deprecated gNMI Element fields.
 		gpath.Element = append(gpath.Element, fpath)
@@ -224,7 +225,7 @@ func StringToStructuredPath(path string) (*gnmipb.Path, error) {
 	for _, p := range parts {
 		name, kv, err := extractKV(p)
 		if err != nil {
-			return nil, fmt.Errorf("error parsing path %s: %v", path, err)
+			return nil, ClassifyError(ErrInvalidPath, fmt.Errorf("error parsing path %s: %w", path, err))
 		}
 		gpath.Elem = append(gpath.Elem, &gnmipb.PathElem{
 			Name: name,
@@ -0,0 +1,259 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+	"sort"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// PathTrie is a trie keyed by the elements of gNMI paths, which stores a
+// value of type T for each path inserted into it. It allows the value of the
+// longest inserted path that is a prefix of a particular path to be found
+// without comparing the path to each inserted path, such that it can be used
+// to classify paths according to a policy, e.g., whether a path is allowed or
+// denied by a set of authorization rules.
+//
+// Inserted paths may contain wildcards, with the same semantics as
+// util.PathMatchesQuery: an element named "*" matches an element of any name,
+// a key whose value is "*" matches any value of the key, and keys that are
+// not specified match any value. The origins "" and "openconfig" are treated
+// as equivalent. Multi-level wildcards ("...") are not supported.
+//
+// The zero value of a PathTrie is an empty trie that is ready to use. A
+// PathTrie must not be modified concurrently with other operations on it.
+type PathTrie[T any] struct {
+	// roots are the roots of the trie, keyed by the origin of the paths
+	// that are inserted beneath them.
+	roots map[string]*pathTrieNode[T]
+	// size is the number of paths for which a value is stored.
+	size int
+}
+
+// pathTrieNode is a node within a PathTrie.
+type pathTrieNode[T any] struct {
+	// set indicates whether a value is stored at the node.
+	set bool
+	// value is the value stored at the node.
+	value T
+	// children are the child edges of the node, keyed by the name of the
+	// path element that they represent.
+	children map[string][]*pathTrieEdge[T]
+}
+
+// pathTrieEdge is an edge between a node of a PathTrie and one of its
+// children, representing a single path element.
+type pathTrieEdge[T any] struct {
+	// keys are the keys of the path element.
+	keys map[string]string
+	// node is the child node.
+	node *pathTrieNode[T]
+}
+
+// pathTrieOrigin returns the origin under which the path p is stored.
+func pathTrieOrigin(p *gnmipb.Path) string {
+	if o := p.GetOrigin(); o != "openconfig" {
+		return o
+	}
+	return ""
+}
+
+// Insert stores the value v for the path p within the trie, replacing any
+// value that is already stored for the same path. An error is returned if p
+// contains a nil element, or an element without a name.
+func (t *PathTrie[T]) Insert(p *gnmipb.Path, v T) error {
+	if t.roots == nil {
+		t.roots = map[string]*pathTrieNode[T]{}
+	}
+	origin := pathTrieOrigin(p)
+	n := t.roots[origin]
+	if n == nil {
+		n = &pathTrieNode[T]{}
+		t.roots[origin] = n
+	}
+
+	for i, e := range p.GetElem() {
+		if e.GetName() == "" {
+			return fmt.Errorf("invalid element %d of path %v, name must be specified", i, p)
+		}
+		n = n.child(e, true)
+	}
+	if !n.set {
+		t.size++
+	}
+	n.set, n.value = true, v
+	return nil
+}
+
+// Get returns the value stored for the path p, and whether a value is
+// stored. Wildcards within p are not expanded, such that p must be equal to
+// the inserted path.
+func (t *PathTrie[T]) Get(p *gnmipb.Path) (T, bool) {
+	var zero T
+	n := t.roots[pathTrieOrigin(p)]
+	for _, e := range p.GetElem() {
+		if n == nil {
+			return zero, false
+		}
+		n = n.child(e, false)
+	}
+	if n == nil || !n.set {
+		return zero, false
+	}
+	return n.value, true
+}
+
+// Delete removes the value stored for the path p, reporting whether a value
+// was stored. As for Get, p must be equal to the inserted path.
+func (t *PathTrie[T]) Delete(p *gnmipb.Path) bool {
+	n := t.roots[pathTrieOrigin(p)]
+	for _, e := range p.GetElem() {
+		if n == nil {
+			return false
+		}
+		n = n.child(e, false)
+	}
+	if n == nil || !n.set {
+		return false
+	}
+	var zero T
+	n.set, n.value = false, zero
+	t.size--
+	return true
+}
+
+// Len returns the number of paths for which a value is stored.
+func (t *PathTrie[T]) Len() int {
+	return t.size
+}
+
+// LongestPrefix returns the value stored for the longest inserted path that
+// matches a prefix of the path p, including p itself, and whether such a path
+// exists. Where more than one inserted path of the same length matches, the
+// most specific path is used, comparing elements from the root: elements that
+// match by name are preferred to wildcard names, and elements specifying more
+// key values are preferred to those with wildcard or unspecified keys. Paths
+// that are equally specific are used in the order in which they were inserted.
+func (t *PathTrie[T]) LongestPrefix(p *gnmipb.Path) (T, bool) {
+	var zero T
+	root := t.roots[pathTrieOrigin(p)]
+	if root == nil {
+		return zero, false
+	}
+	n, _ := root.longestPrefix(p.GetElem(), 0)
+	if n == nil {
+		return zero, false
+	}
+	return n.value, true
+}
+
+// child returns the child of n for the path element e, whose keys must be
+// equal to those of e. If create is set, the child is created if it does not
+// exist, otherwise nil is returned.
+func (n *pathTrieNode[T]) child(e *gnmipb.PathElem, create bool) *pathTrieNode[T] {
+	for _, c := range n.children[e.GetName()] {
+		if pathTrieKeysEqual(c.keys, e.GetKey()) {
+			return c.node
+		}
+	}
+	if !create {
+		return nil
+	}
+	if n.children == nil {
+		n.children = map[string][]*pathTrieEdge[T]{}
+	}
+	c := &pathTrieEdge[T]{keys: map[string]string{}, node: &pathTrieNode[T]{}}
+	for k, v := range e.GetKey() {
+		c.keys[k] = v
+	}
+	// Edges are ordered from the most to the least specific, such that
+	// the most specific matching path is found first.
+	edges := n.children[e.GetName()]
+	i := sort.Search(len(edges), func(i int) bool {
+		return pathTrieKeysSpecificity(edges[i].keys) < pathTrieKeysSpecificity(c.keys)
+	})
+	n.children[e.GetName()] = append(edges[:i], append([]*pathTrieEdge[T]{c}, edges[i:]...)...)
+	return c.node
+}
+
+// pathTrieKeysSpecificity returns the number of keys that specify a value
+// other than a wildcard.
+func pathTrieKeysSpecificity(keys map[string]string) int {
+	var n int
+	for _, v := range keys {
+		if v != "*" {
+			n++
+		}
+	}
+	return n
+}
+
+// longestPrefix returns the node storing a value whose path within the
+// subtree rooted at n is the longest matching a prefix of elems, along with
+// the length of its path relative to the root, where n is at depth. nil is
+// returned if no such node exists.
+func (n *pathTrieNode[T]) longestPrefix(elems []*gnmipb.PathElem, depth int) (*pathTrieNode[T], int) {
+	var (
+		best    *pathTrieNode[T]
+		bestLen = -1
+	)
+	if n.set {
+		best, bestLen = n, depth
+	}
+	if len(elems) == 0 || elems[0] == nil {
+		return best, bestLen
+	}
+	e := elems[0]
+	for _, name := range []string{e.GetName(), "*"} {
+		for _, c := range n.children[name] {
+			if !pathTrieKeysMatch(c.keys, e.GetKey()) {
+				continue
+			}
+			if m, l := c.node.longestPrefix(elems[1:], depth+1); m != nil && l > bestLen {
+				best, bestLen = m, l
+			}
+		}
+		if e.GetName() == "*" {
+			break
+		}
+	}
+	return best, bestLen
+}
+
+// pathTrieKeysEqual reports whether the keys a and b are equal.
+func pathTrieKeysEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// pathTrieKeysMatch reports whether the keys of a path element match the
+// keys of an inserted path element, query, which may contain wildcard values.
+func pathTrieKeysMatch(query, keys map[string]string) bool {
+	for qk, qv := range query {
+		if v, ok := keys[qk]; !ok || (qv != "*" && qv != v) {
+			return false
+		}
+	}
+	return true
+}
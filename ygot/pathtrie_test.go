@@ -0,0 +1,157 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"testing"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func mustTriePath(t *testing.T, s string) *gnmipb.Path {
+	t.Helper()
+	p, err := StringToStructuredPath(s)
+	if err != nil {
+		t.Fatalf("cannot parse path %s: %v", s, err)
+	}
+	return p
+}
+
+func TestPathTrieLongestPrefix(t *testing.T) {
+	var trie PathTrie[string]
+	for p, v := range map[string]string{
+		"/":                                      "root",
+		"/interfaces":                            "interfaces",
+		"/interfaces/interface[name=eth0]":       "eth0",
+		"/interfaces/interface[name=*]/state":    "any-state",
+		"/interfaces/interface/state/counters":   "counters",
+		"/interfaces/interface[name=eth0]/state": "eth0-state",
+		"/*/interface[name=eth1]/config":         "wildcard-name",
+		"/system/config/hostname":                "hostname",
+	} {
+		if err := trie.Insert(mustTriePath(t, p), v); err != nil {
+			t.Fatalf("Insert(%s): got unexpected error: %v", p, err)
+		}
+	}
+	if got, want := trie.Len(), 8; got != want {
+		t.Errorf("Len: got %d, want %d", got, want)
+	}
+
+	tests := []struct {
+		desc     string
+		inPath   string
+		inOrigin string
+		want     string
+	}{{
+		desc:   "root",
+		inPath: "/",
+		want:   "root",
+	}, {
+		desc:   "no matching prefix other than root",
+		inPath: "/network-instances",
+		want:   "root",
+	}, {
+		desc:   "exact match",
+		inPath: "/interfaces/interface[name=eth0]",
+		want:   "eth0",
+	}, {
+		desc:   "longer path than inserted path",
+		inPath: "/interfaces/interface[name=eth0]/config/mtu",
+		want:   "eth0",
+	}, {
+		desc:   "exact key preferred over shorter path",
+		inPath: "/interfaces/interface[name=eth0]/state/oper-status",
+		want:   "eth0-state",
+	}, {
+		desc:   "wildcard key",
+		inPath: "/interfaces/interface[name=eth2]/state/oper-status",
+		want:   "any-state",
+	}, {
+		desc:   "unspecified key matches any value",
+		inPath: "/interfaces/interface[name=eth2]/state/counters/in-pkts",
+		want:   "counters",
+	}, {
+		desc:   "wildcard name",
+		inPath: "/interfaces/interface[name=eth1]/config/mtu",
+		want:   "wildcard-name",
+	}, {
+		desc:   "key value does not match",
+		inPath: "/interfaces/interface[name=eth2]/config/mtu",
+		want:   "interfaces",
+	}, {
+		desc:   "missing key does not match",
+		inPath: "/interfaces/interface/state",
+		want:   "interfaces",
+	}, {
+		desc:     "openconfig origin",
+		inPath:   "/system/config/hostname",
+		inOrigin: "openconfig",
+		want:     "hostname",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			p := mustTriePath(t, tt.inPath)
+			p.Origin = tt.inOrigin
+			got, ok := trie.LongestPrefix(p)
+			if !ok {
+				t.Fatalf("LongestPrefix(%s): did not find a matching path", tt.inPath)
+			}
+			if got != tt.want {
+				t.Errorf("LongestPrefix(%s): got %s, want %s", tt.inPath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathTrie(t *testing.T) {
+	var trie PathTrie[bool]
+	if _, ok := trie.LongestPrefix(mustTriePath(t, "/a")); ok {
+		t.Errorf("LongestPrefix: got match in empty trie")
+	}
+
+	a := mustTriePath(t, "/a[k=1]/b")
+	if err := trie.Insert(a, true); err != nil {
+		t.Fatalf("Insert(%v): got unexpected error: %v", a, err)
+	}
+	if err := trie.Insert(&gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "a"}, {}}}, true); err == nil {
+		t.Errorf("Insert: did not get expected error for element without name")
+	}
+
+	if v, ok := trie.Get(mustTriePath(t, "/a[k=1]/b")); !ok || !v {
+		t.Errorf("Get(/a[k=1]/b): got (%v, %v), want (true, true)", v, ok)
+	}
+	for _, p := range []string{"/a[k=1]", "/a/b", "/a[k=*]/b", "/a[k=1]/b/c"} {
+		if _, ok := trie.Get(mustTriePath(t, p)); ok {
+			t.Errorf("Get(%s): got value for path that was not inserted", p)
+		}
+	}
+	if _, ok := trie.LongestPrefix(&gnmipb.Path{Origin: "other", Elem: a.Elem}); ok {
+		t.Errorf("LongestPrefix: got match for path with different origin")
+	}
+
+	if trie.Delete(mustTriePath(t, "/a[k=1]")) {
+		t.Errorf("Delete(/a[k=1]): got true for path that was not inserted")
+	}
+	if !trie.Delete(a) {
+		t.Errorf("Delete(%v): got false for inserted path", a)
+	}
+	if _, ok := trie.LongestPrefix(mustTriePath(t, "/a[k=1]/b/c")); ok {
+		t.Errorf("LongestPrefix: got match after path was deleted")
+	}
+	if got := trie.Len(); got != 0 {
+		t.Errorf("Len: got %d, want 0", got)
+	}
+}
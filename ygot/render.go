@@ -15,10 +15,13 @@
 package ygot
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/openconfig/gnmi/errlist"
@@ -39,6 +42,18 @@ const (
 	// EmptyTypeName is the name of the type that is used for YANG
 	// empty fields in the output structs.
 	EmptyTypeName string = "YANGEmpty"
+	// KeylessListIndexKey is the name of the synthetic key that identifies
+	// an entry of a keyless list within a gNMI path. Since the entries of
+	// keyless lists have no keys, the value of the synthetic key is the
+	// position of the entry within the list, starting at 0. The name of the
+	// key can be overridden for a particular list by the keyless-index tag
+	// of its field. Only keyless lists whose field has a keyless-index tag,
+	// which is added by the generator when keyless list index tags are
+	// enabled, are output by TogNMINotifications and Diff.
+	KeylessListIndexKey string = "_index"
+	// RedactedValue is output in place of the value of a sensitive leaf or
+	// leaf-list when sensitive leaves are redacted.
+	RedactedValue string = "<redacted>"
 )
 
 var (
@@ -46,20 +61,21 @@ var (
 	// generation produces for simple union types given a regular leaf type
 	// name in Go.
 	SimpleUnionBuiltinGoTypes = map[string]string{
-		"int8":         "UnionInt8",
-		"int16":        "UnionInt16",
-		"int32":        "UnionInt32",
-		"int64":        "UnionInt64",
-		"uint8":        "UnionUint8",
-		"uint16":       "UnionUint16",
-		"uint32":       "UnionUint32",
-		"uint64":       "UnionUint64",
-		"float64":      "UnionFloat64",
-		"string":       "UnionString",
-		"bool":         "UnionBool",
-		"interface{}":  "*UnionUnsupported",
-		BinaryTypeName: BinaryTypeName,
-		EmptyTypeName:  EmptyTypeName,
+		"int8":            "UnionInt8",
+		"int16":           "UnionInt16",
+		"int32":           "UnionInt32",
+		"int64":           "UnionInt64",
+		"uint8":           "UnionUint8",
+		"uint16":          "UnionUint16",
+		"uint32":          "UnionUint32",
+		"uint64":          "UnionUint64",
+		"float64":         "UnionFloat64",
+		"string":          "UnionString",
+		"bool":            "UnionBool",
+		"interface{}":     "*UnionUnsupported",
+		BinaryTypeName:    BinaryTypeName,
+		EmptyTypeName:     EmptyTypeName,
+		Decimal64TypeName: "UnionDecimal64",
 	}
 
 	// unionSingletonUnderlyingTypes stores the underlying types of the
@@ -67,18 +83,19 @@ var (
 	// represent union subtypes for the "Simplified Union Leaf" way of
 	// representatiing unions in the Go generated code.
 	unionSingletonUnderlyingTypes = map[string]reflect.Type{
-		"UnionInt8":    reflect.TypeOf(int8(0)),
-		"UnionInt16":   reflect.TypeOf(int16(0)),
-		"UnionInt32":   reflect.TypeOf(int32(0)),
-		"UnionInt64":   reflect.TypeOf(int64(0)),
-		"UnionUint8":   reflect.TypeOf(uint8(0)),
-		"UnionUint16":  reflect.TypeOf(uint16(0)),
-		"UnionUint32":  reflect.TypeOf(uint32(0)),
-		"UnionUint64":  reflect.TypeOf(uint64(0)),
-		"UnionFloat64": reflect.TypeOf(float64(0.0)),
-		"UnionString":  reflect.TypeOf(string("")),
-		"UnionBool":    reflect.TypeOf(bool(true)),
-		EmptyTypeName:  reflect.TypeOf(bool(true)),
+		"UnionInt8":      reflect.TypeOf(int8(0)),
+		"UnionInt16":     reflect.TypeOf(int16(0)),
+		"UnionInt32":     reflect.TypeOf(int32(0)),
+		"UnionInt64":     reflect.TypeOf(int64(0)),
+		"UnionUint8":     reflect.TypeOf(uint8(0)),
+		"UnionUint16":    reflect.TypeOf(uint16(0)),
+		"UnionUint32":    reflect.TypeOf(uint32(0)),
+		"UnionUint64":    reflect.TypeOf(uint64(0)),
+		"UnionFloat64":   reflect.TypeOf(float64(0.0)),
+		"UnionString":    reflect.TypeOf(string("")),
+		"UnionBool":      reflect.TypeOf(bool(true)),
+		EmptyTypeName:    reflect.TypeOf(bool(true)),
+		"UnionDecimal64": reflect.TypeOf(Decimal64{}),
 		// Note: BinaryTypeName is missing here since it's a slice.
 	}
 )
@@ -231,7 +248,7 @@ func (g *gnmiPath) PathElemAt(i int) (*gnmipb.PathElem, error) {
 		return nil, errors.New("invalid call to PathElemAt() on a non-PathElem path")
 	}
 
-	if i > g.Len() || i < 0 {
+	if i >= g.Len() || i < 0 {
 		return nil, fmt.Errorf("invalid index %d for gnmiPath len %d", i, g.Len())
 	}
 
@@ -246,7 +263,7 @@ func (g *gnmiPath) StringElemAt(i int) (string, error) {
 		return "", errors.New("invalid call to StringElemAt() on a non-string element path")
 	}
 
-	if i > g.Len() || i < 0 {
+	if i >= g.Len() || i < 0 {
 		return "", fmt.Errorf("invalid index %d for gnmiPath len %d", i, g.Len())
 	}
 
@@ -354,6 +371,19 @@ type GNMINotificationsConfig struct {
 	// prefix that concatenates the given prefix with the relative path of
 	// the ordered map from the given node.
 	PathElemPrefix []*gnmipb.PathElem
+	// JSONIETFUpdateDepth specifies the depth, expressed as a number of
+	// path elements relative to the input GoStruct, at which subtrees are
+	// rendered as a single Update whose value is the JSON_IETF encoding of
+	// the subtree, rather than as an Update per leaf. For example, a depth
+	// of 2 for the root of an OpenConfig schema renders each entry of
+	// /interfaces/interface as a single Update. Each container or list
+	// entry whose path has at least the specified number of elements, and
+	// whose parent's path has fewer, is rendered as a single Update at its
+	// own path. Leaves whose paths have fewer elements, and ordered lists
+	// that are not within such a subtree, are rendered as if the depth
+	// were not specified. If unset or zero, each leaf is rendered as its
+	// own Update. It may only be used if UsePathElem is set.
+	JSONIETFUpdateDepth int
 }
 
 // TogNMINotifications takes an input GoStruct and renders it to slice of
@@ -362,7 +392,9 @@ type GNMINotificationsConfig struct {
 // in the message if relevant. If there are any `ordered-by user` lists within
 // the input struct, then they will be treated as "telemetry-atomic", and put
 // into separate atomic notifications after the initial notification containing
-// the non-atomic updates.
+// the non-atomic updates. Entries of keyless lists whose field has a
+// keyless-index tag are identified by their position within the list, as
+// described by KeylessListIndexKey; other keyless lists cannot be output.
 //
 // Note: Within the generated notifications there could be data sharing for
 // space and compute optimization. Make a deep copy if one plans to modify the
@@ -373,7 +405,58 @@ type GNMINotificationsConfig struct {
 // abstraction. It can also be refactored to simply use the findSetleaves function
 // which has a cleaner implementation using the reworked iterfunction util.
 func TogNMINotifications(s GoStruct, ts int64, cfg GNMINotificationsConfig) ([]*gnmipb.Notification, error) {
+	return togNMINotifications(context.Background(), s, ts, cfg)
+}
+
+// RenderContextOpt is an interface implemented by the options that can be
+// supplied to the functions that render a GoStruct subject to a context, such
+// as TogNMINotificationsContext and ConstructIETFJSONContext.
+type RenderContextOpt interface {
+	// IsRenderContextOpt is a marker method.
+	IsRenderContextOpt()
+}
+
+// PartialResultsOnCancel is a RenderContextOpt that specifies that, when the
+// context is cancelled or its deadline is exceeded before rendering completes,
+// the output rendered so far is returned along with the context's error,
+// rather than no output. The partial output may omit any part of the GoStruct,
+// including some of the keys of a list entry.
+type PartialResultsOnCancel struct{}
 
+// IsRenderContextOpt implements the RenderContextOpt interface.
+func (*PartialResultsOnCancel) IsRenderContextOpt() {}
+
+// hasPartialResultsOnCancel determines whether there is an instance of
+// PartialResultsOnCancel within the supplied RenderContextOpt slice.
+func hasPartialResultsOnCancel(opts []RenderContextOpt) bool {
+	for _, o := range opts {
+		if _, ok := o.(*PartialResultsOnCancel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// TogNMINotificationsContext renders the input GoStruct to a slice of
+// Notification messages in the same way as TogNMINotifications, but stops
+// rendering promptly when ctx is cancelled or its deadline is exceeded, in
+// which case ctx's error is returned. The Notifications containing the leaves
+// rendered before rendering stopped are also returned if the
+// PartialResultsOnCancel option is supplied.
+func TogNMINotificationsContext(ctx context.Context, s GoStruct, ts int64, cfg GNMINotificationsConfig, opts ...RenderContextOpt) ([]*gnmipb.Notification, error) {
+	msgs, err := togNMINotifications(ctx, s, ts, cfg)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if err != nil || !hasPartialResultsOnCancel(opts) {
+			return nil, ctxErr
+		}
+		return msgs, ctxErr
+	}
+	return msgs, err
+}
+
+// togNMINotifications implements TogNMINotifications, stopping the traversal
+// of s when ctx is done, such that the leaves found so far are rendered.
+func togNMINotifications(ctx context.Context, s GoStruct, ts int64, cfg GNMINotificationsConfig) ([]*gnmipb.Notification, error) {
 	var pfx *gnmiPath
 	if cfg.UsePathElem {
 		pfx = newPathElemGNMIPath(cfg.PathElemPrefix)
@@ -381,8 +464,18 @@ func TogNMINotifications(s GoStruct, ts int64, cfg GNMINotificationsConfig) ([]*
 		pfx = newStringSliceGNMIPath(cfg.StringSlicePrefix)
 	}
 
+	var jsonPathLen int
+	switch {
+	case cfg.JSONIETFUpdateDepth < 0:
+		return nil, fmt.Errorf("invalid JSON_IETF update depth %d", cfg.JSONIETFUpdateDepth)
+	case cfg.JSONIETFUpdateDepth > 0 && !cfg.UsePathElem:
+		return nil, fmt.Errorf("JSON_IETF update depth can only be specified for PathElem paths")
+	case cfg.JSONIETFUpdateDepth > 0:
+		jsonPathLen = pfx.Len() + cfg.JSONIETFUpdateDepth
+	}
+
 	leaves := map[*path]any{}
-	if err := findUpdatedLeaves(leaves, s, pfx, false); err != nil {
+	if err := findUpdatedLeaves(ctx, leaves, s, pfx, false, jsonPathLen); err != nil {
 		return nil, err
 	}
 
@@ -422,13 +515,13 @@ func findUpdatedOrderedListLeaves(leaves any, s GoOrderedMap, parent *gnmiPath,
 	atomicLeaves, subtreePath, err := orderedMapLeaves(s, parent, preferShadowPath)
 	if err != nil {
 		errs.Add(err)
-		return errs.Err()
+		return listErr(errs)
 	}
 
 	if len(atomicLeaves) > 0 {
 		leavesMap[&path{subtreePath}] = atomicLeaves
 	}
-	return errs.Err()
+	return listErr(errs)
 }
 
 // findUpdatedLeaves appends the valid leaves that are within the supplied
@@ -438,8 +531,16 @@ func findUpdatedOrderedListLeaves(leaves any, s GoOrderedMap, parent *gnmiPath,
 // lists, or containers - represented as maps or struct pointers), the function
 // is called recursively on them.
 //
+// If jsonPathLen is non-zero, then GoStructs whose paths have at least
+// jsonPathLen elements are added to the leaves map as a single value, rather
+// than being recursed into, such that they are rendered as a JSON_IETF
+// encoded subtree.
+//
+// The traversal stops, without error, when ctx is done, such that the leaves
+// found so far are within the leaves map.
+//
 // Note: the returned paths use a shallow copy of the parentPath.
-func findUpdatedLeaves(leaves any, s GoStruct, parent *gnmiPath, preferShadowPath bool) error {
+func findUpdatedLeaves(ctx context.Context, leaves any, s GoStruct, parent *gnmiPath, preferShadowPath bool, jsonPathLen int) error {
 	// addLeaf is the function that must be used to add a single leaf or
 	// atomic update to the input cache of leaves. The reason this is
 	// different is because atomic values must be added in a different way
@@ -467,6 +568,16 @@ func findUpdatedLeaves(leaves any, s GoStruct, parent *gnmiPath, preferShadowPat
 
 	var errs errlist.List
 
+	// addChild adds the leaves of the child GoStruct at path p, or the
+	// GoStruct itself if it is to be rendered as a JSON_IETF subtree.
+	addChild := func(child GoStruct, p *gnmiPath) error {
+		if jsonPathLen > 0 && p.Len() >= jsonPathLen {
+			addLeaf(&path{p}, child)
+			return nil
+		}
+		return findUpdatedLeaves(ctx, leaves, child, p, preferShadowPath, jsonPathLen)
+	}
+
 	if !parent.isValid() {
 		return fmt.Errorf("invalid parent specified: %v", parent)
 	}
@@ -474,13 +585,16 @@ func findUpdatedLeaves(leaves any, s GoStruct, parent *gnmiPath, preferShadowPat
 	sval := reflect.ValueOf(s)
 	if s == nil || util.IsValueNil(sval) || !sval.IsValid() || !util.IsValueStructPtr(sval) {
 		errs.Add(fmt.Errorf("input struct for %v was not valid", parent))
-		return errs.Err()
+		return listErr(errs)
 	}
 	sval = sval.Elem()
 
 	stype := sval.Type()
 
 	for i := 0; i < sval.NumField(); i++ {
+		if ctx.Err() != nil {
+			break
+		}
 		fval := sval.Field(i)
 		ftype := stype.Field(i)
 
@@ -498,10 +612,38 @@ func findUpdatedLeaves(leaves any, s GoStruct, parent *gnmiPath, preferShadowPat
 			continue
 		}
 
+		// User-specified types for binary and empty leaves are leaves
+		// regardless of their kind, which are unset if they have their
+		// zero value. An empty leaf is only marshalled if it is present.
+		if fval.CanInterface() {
+			switch v := fval.Interface().(type) {
+			case BinaryValue, EmptyValue:
+				if e, ok := v.(EmptyValue); fval.IsZero() || ok && !e.YANGEmpty() {
+					continue
+				}
+				for _, p := range mapPaths {
+					addLeaf(&path{p}, v)
+				}
+				continue
+			case Decimal64:
+				// An unset Decimal64 leaf has the zero value.
+				if fval.IsZero() {
+					continue
+				}
+				for _, p := range mapPaths {
+					addLeaf(&path{p}, v)
+				}
+				continue
+			}
+		}
+
 		switch fval.Kind() {
 		case reflect.Map:
 			// We need to map each child along with its key value.
 			for _, k := range fval.MapKeys() {
+				if ctx.Err() != nil {
+					break
+				}
 				childPath, err := mapValuePath(k, fval.MapIndex(k), mapPaths[0])
 				if err != nil {
 					errs.Add(err)
@@ -513,7 +655,7 @@ func findUpdatedLeaves(leaves any, s GoStruct, parent *gnmiPath, preferShadowPat
 					errs.Add(fmt.Errorf("%v: was not a valid GoStruct", mapPaths[0]))
 					continue
 				}
-				errs.Add(findUpdatedLeaves(leaves, goStruct, childPath, preferShadowPath))
+				errs.Add(addChild(goStruct, childPath))
 			}
 		case reflect.Ptr:
 			if ol, ok := fval.Interface().(GoOrderedMap); ok {
@@ -528,7 +670,7 @@ func findUpdatedLeaves(leaves any, s GoStruct, parent *gnmiPath, preferShadowPat
 						errs.Add(fmt.Errorf("%v: was not a valid GoStruct", mapPaths[0]))
 						continue
 					}
-					errs.Add(findUpdatedLeaves(leaves, goStruct, mapPaths[0], preferShadowPath))
+					errs.Add(addChild(goStruct, mapPaths[0]))
 				default:
 					for _, p := range mapPaths {
 						addLeaf(&path{p}, fval.Interface())
@@ -537,9 +679,31 @@ func findUpdatedLeaves(leaves any, s GoStruct, parent *gnmiPath, preferShadowPat
 			}
 		case reflect.Slice:
 			if fval.Type().Elem().Kind() == reflect.Ptr {
-				// This is a keyless list - currently unsupported for mapping since there is
-				// not an explicit path that can be used.
-				errs.Add(fmt.Errorf("unimplemented: keyless list cannot be output: %v", mapPaths[0]))
+				if !hasKeylessListIndex(ftype) {
+					// Without a synthetic key there is no explicit
+					// path that can be used for the entries.
+					errs.Add(fmt.Errorf("unimplemented: keyless list cannot be output: %v", mapPaths[0]))
+					continue
+				}
+				// This is a keyless list, each entry of which is
+				// identified by its position within the list.
+				for j := 0; j < fval.Len() && ctx.Err() == nil; j++ {
+					if fval.Index(j).IsNil() {
+						continue
+					}
+					childPath, err := keylessListEntryPath(j, keylessListIndexKey(ftype), mapPaths[0])
+					if err != nil {
+						errs.Add(err)
+						continue
+					}
+
+					goStruct, ok := fval.Index(j).Interface().(GoStruct)
+					if !ok {
+						errs.Add(fmt.Errorf("%v: was not a valid GoStruct", mapPaths[0]))
+						continue
+					}
+					errs.Add(addChild(goStruct, childPath))
+				}
 				continue
 			}
 			// This is a leaf-list, so add it as though it were a leaf.
@@ -580,7 +744,7 @@ func findUpdatedLeaves(leaves any, s GoStruct, parent *gnmiPath, preferShadowPat
 			}
 		}
 	}
-	return errs.Err()
+	return listErr(errs)
 }
 
 // mapValuePath calculates the gNMI Path of a map element with the specified
@@ -597,7 +761,7 @@ func mapValuePath(key, value reflect.Value, parentPath *gnmiPath) (*gnmiPath, er
 		childPath = &gnmiPath{}
 		keyval, err := KeyValueAsString(key.Interface())
 		if err != nil {
-			return nil, fmt.Errorf("can't append path element key: %v", err)
+			return nil, fmt.Errorf("can't append path element key: %w", err)
 		}
 		// We copy the elements from the existing elementPath such that when updating
 		// it, then the elements are not modified when the paths are changed.
@@ -612,6 +776,55 @@ func mapValuePath(key, value reflect.Value, parentPath *gnmiPath) (*gnmiPath, er
 	return appendgNMIPathElemKey(value, childPath)
 }
 
+// keylessListIndexKey returns the name of the synthetic key that identifies
+// the entries of the keyless list represented by the struct field f.
+func keylessListIndexKey(f reflect.StructField) string {
+	if k := f.Tag.Get("keyless-index"); k != "" {
+		return k
+	}
+	return KeylessListIndexKey
+}
+
+// hasKeylessListIndex reports whether the struct field f, which represents a
+// keyless list, has a keyless-index tag, such that its entries can be
+// identified by their position within the list.
+func hasKeylessListIndex(f reflect.StructField) bool {
+	_, ok := f.Tag.Lookup("keyless-index")
+	return ok
+}
+
+// keylessListEntryPath returns the path of the entry at position i of the
+// keyless list at parentPath. For PathElem paths, the position is specified
+// as the value of the synthetic key named key.
+func keylessListEntryPath(i int, key string, parentPath *gnmiPath) (*gnmiPath, error) {
+	if parentPath == nil {
+		return nil, fmt.Errorf("nil list path supplied for keyless list entry %d", i)
+	}
+
+	if parentPath.isStringSlicePath() {
+		childPath := &gnmiPath{}
+		childPath.stringSlicePath = append(childPath.stringSlicePath, parentPath.stringSlicePath...)
+		childPath.stringSlicePath = append(childPath.stringSlicePath, strconv.Itoa(i))
+		return childPath, nil
+	}
+
+	if parentPath.Len() == 0 {
+		return nil, fmt.Errorf("invalid path element path length, can't append index to 0 length path: %v", parentPath.pathElemPath)
+	}
+
+	np := parentPath.Copy()
+	e, err := np.LastPathElem()
+	if err != nil {
+		return nil, err
+	}
+	newElem := proto.Clone(e).(*gnmipb.PathElem)
+	newElem.Key = map[string]string{key: strconv.Itoa(i)}
+	if err := np.SetIndex(np.Len()-1, newElem); err != nil {
+		return nil, err
+	}
+	return np, nil
+}
+
 // appendgNMIPathElemKey takes an input reflect.Value which must implement KeyHelperGoStruct
 // and appends the keys from it to the last entry in the supplied mapPath, which must be a
 // gNMI PathElem message.
@@ -645,7 +858,7 @@ func appendgNMIPathElemKey(v reflect.Value, p *gnmiPath) (*gnmiPath, error) {
 
 	k, err := PathKeyFromStruct(v)
 	if err != nil {
-		return nil, fmt.Errorf("cannot extract keys: %v", err)
+		return nil, fmt.Errorf("cannot extract keys: %w", err)
 	}
 	newElem.Key = k
 
@@ -674,7 +887,9 @@ func PathKeyFromStruct(v reflect.Value) (map[string]string, error) {
 
 	km, err := gs.ΛListKeyMap()
 	if err != nil {
-		return nil, err
+		// The generated ΛListKeyMap methods only return an error when a
+		// key of the list entry is not set.
+		return nil, ClassifyError(ErrUnsetKey, err)
 	}
 
 	k, err := keyMapAsStrings(km)
@@ -709,7 +924,7 @@ func KeyValueAsString(v any) (string, error) {
 	if _, isEnum := v.(GoEnum); isEnum {
 		name, _, err := enumFieldToString(kv, false)
 		if err != nil {
-			return "", fmt.Errorf("cannot resolve enumerated type in key, got err: %v", err)
+			return "", fmt.Errorf("cannot resolve enumerated type in key, got err: %w", err)
 		}
 		return name, nil
 	}
@@ -746,6 +961,10 @@ func KeyValueAsString(v any) (string, error) {
 func sliceToScalarArray(v []any) (*gnmipb.ScalarArray, error) {
 	arr := &gnmipb.ScalarArray{}
 	for _, e := range v {
+		if d, ok := e.(Decimal64); ok {
+			arr.Element = append(arr.Element, decimal64TypedValue(d))
+			continue
+		}
 		tv, err := value.FromScalar(e)
 		if err != nil {
 			return nil, err
@@ -768,7 +987,13 @@ func addToNotification(pk *path, value any, n *gnmipb.Notification, pfx *gnmiPat
 		return err
 	}
 
-	val, err := EncodeTypedValue(value, gnmipb.Encoding_JSON)
+	// GoStructs are only added as values when they are to be rendered as
+	// a JSON_IETF encoded subtree.
+	enc := gnmipb.Encoding_JSON
+	if _, ok := value.(GoStruct); ok {
+		enc = gnmipb.Encoding_JSON_IETF
+	}
+	val, err := EncodeTypedValue(value, enc, &RFC7951JSONConfig{})
 	if err != nil {
 		return err
 	}
@@ -842,9 +1067,13 @@ type EncodeTypedValueOpt interface {
 }
 
 // EncodeTypedValue encodes val into a gNMI TypedValue message, using the specified encoding
-// type if the value is a struct.
+// type if the value is a struct. If no RFC7951JSONConfig is supplied, the default
+// set by SetDefaultRFC7951JSONConfig is used.
 func EncodeTypedValue(val any, enc gnmipb.Encoding, opts ...EncodeTypedValueOpt) (*gnmipb.TypedValue, error) {
-	jc := &RFC7951JSONConfig{}
+	jc := DefaultRFC7951JSONConfig()
+	if jc == nil {
+		jc = &RFC7951JSONConfig{}
+	}
 	for _, opt := range opts {
 		if cfg, ok := opt.(*RFC7951JSONConfig); ok {
 			jc = cfg
@@ -857,9 +1086,28 @@ func EncodeTypedValue(val any, enc gnmipb.Encoding, opts ...EncodeTypedValueOpt)
 	case GoEnum:
 		en, err := EnumName(v)
 		if err != nil {
-			return nil, fmt.Errorf("cannot marshal enum, %v", err)
+			return nil, fmt.Errorf("cannot marshal enum, %w", err)
 		}
 		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: en}}, nil
+	case BinaryValue:
+		if util.IsValueNil(v) {
+			return nil, nil
+		}
+		b, err := v.YANGBinary()
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal binary value, %w", err)
+		}
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_BytesVal{BytesVal: b}}, nil
+	case EmptyValue:
+		if util.IsValueNil(v) {
+			return nil, nil
+		}
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_BoolVal{BoolVal: v.YANGEmpty()}}, nil
+	case Decimal64:
+		if v == (Decimal64{}) {
+			return nil, nil
+		}
+		return decimal64TypedValue(v), nil
 	}
 
 	vv := reflect.ValueOf(val)
@@ -888,7 +1136,7 @@ func EncodeTypedValue(val any, enc gnmipb.Encoding, opts ...EncodeTypedValueOpt)
 	case util.IsValueStructPtr(vv):
 		nv, err := unwrapUnionInterfaceValue(vv, false)
 		if err != nil {
-			return nil, fmt.Errorf("cannot resolve union field value: %v", err)
+			return nil, fmt.Errorf("cannot resolve union field value: %w", err)
 		}
 		vv = reflect.ValueOf(nv)
 		// Apart from binary, all other possible union subtypes are scalars or typedefs of scalars.
@@ -909,9 +1157,19 @@ func EncodeTypedValue(val any, enc gnmipb.Encoding, opts ...EncodeTypedValueOpt)
 		}
 	}
 
+	// Decimal64 values may be held by unions, which are resolved above.
+	if d, ok := vv.Interface().(Decimal64); ok {
+		return decimal64TypedValue(d), nil
+	}
 	return value.FromScalar(vv.Interface())
 }
 
+// decimal64TypedValue returns the gNMI TypedValue that represents d, which
+// retains its exact value.
+func decimal64TypedValue(d Decimal64) *gnmipb.TypedValue {
+	return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_DecimalVal{DecimalVal: &gnmipb.Decimal64{Digits: d.Digits, Precision: uint32(d.Precision)}}}
+}
+
 // marshalStructOrOrderedList encodes the struct/ordered list s according to
 // the encoding specified by enc. It is returned as a TypedValue gNMI message.
 func marshalStructOrOrderedList(s any, enc gnmipb.Encoding, cfg *RFC7951JSONConfig) (*gnmipb.TypedValue, error) {
@@ -948,7 +1206,7 @@ func marshalStructOrOrderedList(s any, enc gnmipb.Encoding, cfg *RFC7951JSONConf
 
 	js, err := json.MarshalIndent(j, "", "  ")
 	if err != nil {
-		return nil, fmt.Errorf("cannot encode JSON, %v", err)
+		return nil, fmt.Errorf("cannot encode JSON, %w", err)
 	}
 
 	return encfn(string(js)), nil
@@ -1037,6 +1295,12 @@ func leaflistToSlice(val reflect.Value, prependModuleNameIref bool) ([]any, erro
 				return nil, fmt.Errorf("unknown type within a slice: %v", e.Type().Name())
 			}
 			sval = append(sval, e.Bytes())
+		case reflect.Struct:
+			d, ok := e.Interface().(Decimal64)
+			if !ok {
+				return nil, fmt.Errorf("unknown struct type in leaflist: %v", e.Type())
+			}
+			sval = append(sval, d)
 		default:
 			return nil, fmt.Errorf("invalid type %s in leaflist", e.Kind())
 		}
@@ -1089,6 +1353,10 @@ func appendTypedValue(l []any, v reflect.Value, prependModuleNameIref bool) ([]a
 			return nil, fmt.Errorf("unknown type within a slice: %v", v.Type().Name())
 		}
 		return append(l, v.Bytes()), nil
+	case reflect.Struct:
+		if d, ok := ival.(Decimal64); ok {
+			return append(l, d), nil
+		}
 	}
 	return nil, fmt.Errorf("unknown kind in leaflist: %v", reflect.TypeOf(v).Kind())
 }
@@ -1124,6 +1392,26 @@ type RFC7951JSONConfig struct {
 	// is to be rewritten FROM, and the value of the map is the name of the module
 	// it is to be rewritten TO.
 	RewriteModuleNames map[string]string
+	// UseModulePrefixes specifies that, when AppendModuleName is set, the
+	// names of elements are qualified by the prefix of their module, as
+	// specified by the module's prefix statement, rather than its name, as
+	// expected by some RESTCONF servers. The prefixes are taken from the
+	// module-prefix struct tags of the GoStruct, which are generated when
+	// the AddModulePrefixTags generation option is set. Identityref values
+	// continue to be qualified by module names. Note: this output is not
+	// RFC7951-compliant, and cannot be combined with RewriteModuleNames.
+	UseModulePrefixes bool
+	// RedactSensitiveLeaves specifies that the values of leaves and
+	// leaf-lists whose fields are tagged as sensitive, e.g., passwords,
+	// are replaced with RedactedValue.
+	RedactSensitiveLeaves bool
+	// EnumsAsInts specifies that the values of enumerations are rendered
+	// as their integer values within the YANG schema, rather than their
+	// names, as expected by some tooling. Identity values have no integer
+	// value and are always rendered as names. Note: this output is not
+	// RFC7951-compliant, and can be unmarshalled by ytypes.Unmarshal only
+	// when the ytypes.AcceptEnumInts option is supplied.
+	EnumsAsInts bool
 }
 
 // IsMarshal7951Arg marks the RFC7951JSONConfig struct as a valid argument to
@@ -1137,12 +1425,33 @@ func (*RFC7951JSONConfig) IsEncodeTypedValueOpt() {}
 // ConstructIETFJSON marshals a supplied GoStruct to a map, suitable for
 // handing to json.Marshal. It complies with the convention for marshalling
 // to JSON described by RFC7951. The supplied args control options corresponding
-// to the method by which JSON is marshalled.
+// to the method by which JSON is marshalled. If args is nil, the default set by
+// SetDefaultRFC7951JSONConfig is used.
 func ConstructIETFJSON(s GoStruct, args *RFC7951JSONConfig) (map[string]any, error) {
 	return structJSON(s, "", jsonOutputConfig{
 		jType:         RFC7951,
-		rfc7951Config: args,
+		rfc7951Config: rfc7951ConfigOrDefault(args),
+	})
+}
+
+// ConstructIETFJSONContext marshals a supplied GoStruct to a map in the same
+// way as ConstructIETFJSON, but stops marshalling promptly when ctx is
+// cancelled or its deadline is exceeded, in which case ctx's error is
+// returned. The map containing the values marshalled before marshalling
+// stopped is also returned if the PartialResultsOnCancel option is supplied.
+func ConstructIETFJSONContext(ctx context.Context, s GoStruct, args *RFC7951JSONConfig, opts ...RenderContextOpt) (map[string]any, error) {
+	j, err := structJSON(s, "", jsonOutputConfig{
+		jType:         RFC7951,
+		rfc7951Config: rfc7951ConfigOrDefault(args),
+		ctx:           ctx,
 	})
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if err != nil || !hasPartialResultsOnCancel(opts) {
+			return nil, ctxErr
+		}
+		return j, ctxErr
+	}
+	return j, err
 }
 
 // ConstructInternalJSON marshals a supplied GoStruct to a map, suitable for handing
@@ -1154,6 +1463,28 @@ func ConstructInternalJSON(s GoStruct) (map[string]any, error) {
 	})
 }
 
+// InternalJSONConfig is used to control the behaviour of how internal format
+// JSON is output by the ygot library.
+type InternalJSONConfig struct {
+	// Int64AsString specifies that the values of int64 and uint64 leaves
+	// are rendered as strings, as in RFC7951 JSON, rather than as numbers,
+	// which cannot represent all such values exactly when they are parsed
+	// as float64 by the consumer of the JSON. ytypes.Unmarshal accepts only
+	// strings for these values unless the ytypes.AcceptInt64Numbers option
+	// is supplied.
+	Int64AsString bool
+}
+
+// ConstructInternalJSONWithConfig marshals a supplied GoStruct to a map in
+// the same format as ConstructInternalJSON, according to the supplied args.
+// If args is nil, the output is the same as that of ConstructInternalJSON.
+func ConstructInternalJSONWithConfig(s GoStruct, args *InternalJSONConfig) (map[string]any, error) {
+	return structJSON(s, "", jsonOutputConfig{
+		jType:          Internal,
+		internalConfig: args,
+	})
+}
+
 // Marshal7951Arg is an interface implemented by arguments to
 // the Marshal7951 function.
 type Marshal7951Arg interface {
@@ -1173,7 +1504,8 @@ func (JSONIndent) IsMarshal7951Arg() {}
 // field of a generated struct rather than the entire struct - allowing specific fields
 // to be rendered. The supplied arguments control the JSON marshalling behaviour - both
 // base JSON Marshal (e.g., indentation), as well as RFC7951 specific options such as
-// YANG module names being prepended.
+// YANG module names being prepended. If no RFC7951JSONConfig is supplied, the
+// default set by SetDefaultRFC7951JSONConfig is used.
 // The rendered JSON is returned as a byte slice - in common with json.Marshal.
 func Marshal7951(d any, args ...Marshal7951Arg) ([]byte, error) {
 	var (
@@ -1190,7 +1522,7 @@ func Marshal7951(d any, args ...Marshal7951Arg) ([]byte, error) {
 	}
 	j, err := jsonValue(reflect.ValueOf(d), "", jsonOutputConfig{
 		jType:         RFC7951,
-		rfc7951Config: rfcCfg,
+		rfc7951Config: rfc7951ConfigOrDefault(rfcCfg),
 	})
 
 	if err != nil {
@@ -1207,12 +1539,40 @@ func Marshal7951(d any, args ...Marshal7951Arg) ([]byte, error) {
 		js, err = json.MarshalIndent(j, "", indent)
 	}
 	if err != nil {
-		return nil, fmt.Errorf("could not marshal JSON, %v", err)
+		return nil, fmt.Errorf("could not marshal JSON, %w", err)
 	}
 
 	return js, nil
 }
 
+// RedactedString returns a compact RFC7951 JSON representation of the
+// supplied GoStruct, in which the values of sensitive leaves are replaced by
+// RedactedValue. The keys of each JSON object are sorted, such that the output
+// is stable. It is intended to be used to implement the String method of
+// generated GoStructs, and hence if the GoStruct cannot be rendered, a
+// description of the error is returned rather than the JSON.
+func RedactedString(s GoStruct) string {
+	if util.IsValueNil(s) {
+		return "<nil>"
+	}
+	j, err := jsonValue(reflect.ValueOf(s), "", jsonOutputConfig{
+		jType:         RFC7951,
+		rfc7951Config: &RFC7951JSONConfig{RedactSensitiveLeaves: true},
+	})
+	if err != nil {
+		return fmt.Sprintf("<invalid %T: %v>", s, err)
+	}
+	// HTML escaping is disabled such that RedactedValue is output as is,
+	// rather than with its angle brackets escaped.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(j); err != nil {
+		return fmt.Sprintf("<invalid %T: %v>", s, err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
 // jsonOutputConfig is used to determine how constructJSON should generate
 // JSON.
 type jsonOutputConfig struct {
@@ -1221,6 +1581,46 @@ type jsonOutputConfig struct {
 	// rfc7951Config stores the configuration to be used when outputting RFC7951
 	// JSON.
 	rfc7951Config *RFC7951JSONConfig
+	// internalEnumsAsInts specifies that enumerated values are rendered as
+	// integers when outputting Internal JSON. For RFC7951 JSON, the
+	// EnumsAsInts field of rfc7951Config is used.
+	internalEnumsAsInts bool
+	// internalConfig stores the configuration to be used when outputting
+	// Internal JSON.
+	internalConfig *InternalJSONConfig
+	// ctx, if set, stops the construction of the JSON, without error, when
+	// it is done, such that the values constructed so far are output.
+	ctx context.Context
+}
+
+// done returns true if the construction of the JSON should stop because the
+// context of the JSON output config is done.
+func (c jsonOutputConfig) done() bool {
+	return c.ctx != nil && c.ctx.Err() != nil
+}
+
+// enumsAsInts returns true if the values of enumerations should be rendered as
+// their integer values according to the JSON output config.
+func (c jsonOutputConfig) enumsAsInts() bool {
+	if c.jType == RFC7951 {
+		return c.rfc7951Config != nil && c.rfc7951Config.EnumsAsInts
+	}
+	return c.internalEnumsAsInts
+}
+
+// scalarJSON returns the scalar value i in the format that is expected by the
+// JSON output config.
+func (c jsonOutputConfig) scalarJSON(i any) any {
+	switch {
+	case c.jType == RFC7951:
+		return writeIETFScalarJSON(i)
+	case c.internalConfig != nil && c.internalConfig.Int64AsString:
+		switch reflect.ValueOf(i).Kind() {
+		case reflect.Uint64, reflect.Int64:
+			return fmt.Sprintf("%v", i)
+		}
+	}
+	return i
 }
 
 // rewriteModName rewrites the module mod according to the specified rewrite rules.
@@ -1251,13 +1651,31 @@ func prependmodsJSON(fType reflect.StructField, parentMod string, args jsonOutpu
 
 	mapModules, err := structTagToLibModules(fType, args.rfc7951Config.PreferShadowPath)
 	if err != nil {
-		return nil, "", fmt.Errorf("%s: %v", fType.Name, err)
+		return nil, "", fmt.Errorf("%s: %w", fType.Name, err)
 	}
 	if len(mapModules) == 0 {
 		return nil, "", nil
 	}
 
-	for _, modulePath := range mapModules {
+	// Where module prefixes are used, the modules are still compared by
+	// name, but the prefix of the module is prepended.
+	var mapPrefixes []*gnmiPath
+	if args.rfc7951Config.UseModulePrefixes {
+		if len(args.rfc7951Config.RewriteModuleNames) != 0 {
+			return nil, "", fmt.Errorf("%s: module names cannot be rewritten when module prefixes are used", fType.Name)
+		}
+		if mapPrefixes, err = structTagToLibModulePrefixes(fType, args.rfc7951Config.PreferShadowPath); err != nil {
+			return nil, "", err
+		}
+		if len(mapPrefixes) != len(mapModules) {
+			return nil, "", fmt.Errorf("%s: number of paths in module and module prefix tags not the same: (modules: %v, prefixes: %v)", fType.Name, mapModules, mapPrefixes)
+		}
+	}
+
+	for j, modulePath := range mapModules {
+		if mapPrefixes != nil && mapPrefixes[j].Len() != modulePath.Len() {
+			return nil, "", fmt.Errorf("%s: number of modules and module prefixes not the same: (modules: %v, prefixes: %v)", fType.Name, modulePath, mapPrefixes[j])
+		}
 		var prependmod []string
 		prevMod := parentMod
 		for i := 0; i != modulePath.Len(); i++ {
@@ -1268,10 +1686,16 @@ func prependmodsJSON(fType reflect.StructField, parentMod string, args jsonOutpu
 			// First we check whether we are rewriting the name of the module, so that
 			// we do the right comparison.
 			mod = rewriteModName(mod, args.rfc7951Config.RewriteModuleNames)
-			if mod == prevMod {
+			switch {
+			case mod == prevMod:
 				// The empty string indicates to not prepend a module name.
 				mod = ""
-			} else {
+			case mapPrefixes != nil:
+				prevMod = mod
+				if mod, err = mapPrefixes[j].StringElemAt(i); err != nil {
+					return nil, "", err
+				}
+			default:
 				prevMod = mod
 			}
 			prependmod = append(prependmod, mod)
@@ -1302,7 +1726,7 @@ func structJSON(s GoStruct, parentMod string, args jsonOutputConfig) (map[string
 	// json.Marshal(Text)?
 	jsonout := map[string]any{}
 
-	for i := 0; i < sval.NumField(); i++ {
+	for i := 0; i < sval.NumField() && !args.done(); i++ {
 		field := sval.Field(i)
 		fType := stype.Field(i)
 
@@ -1340,6 +1764,10 @@ func structJSON(s GoStruct, parentMod string, args jsonOutputConfig) (map[string
 			continue
 		}
 
+		if args.rfc7951Config != nil && args.rfc7951Config.RedactSensitiveLeaves && util.IsSensitive(fType) {
+			value = RedactedValue
+		}
+
 		if mp, ok := value.(map[string]any); ok && len(mp) == 0 && !util.IsYangPresence(fType) {
 			continue
 		}
@@ -1403,8 +1831,8 @@ func structJSON(s GoStruct, parentMod string, args jsonOutputConfig) (map[string
 		}
 	}
 
-	if errs.Err() != nil {
-		return nil, errs.Err()
+	if listErr(errs) != nil {
+		return nil, listErr(errs)
 	}
 
 	return jsonout, nil
@@ -1414,9 +1842,15 @@ func structJSON(s GoStruct, parentMod string, args jsonOutputConfig) (map[string
 // that is expected in IETF RFC7951 JSON. Per this specification, uint64, int64
 // and float64 values are represented as strings.
 func writeIETFScalarJSON(i any) any {
-	switch reflect.ValueOf(i).Kind() {
-	case reflect.Uint64, reflect.Int64, reflect.Float64:
+	if d, ok := i.(Decimal64); ok {
+		return d.String()
+	}
+	switch v := reflect.ValueOf(i); v.Kind() {
+	case reflect.Uint64, reflect.Int64:
 		return fmt.Sprintf("%v", i)
+	case reflect.Float64:
+		// YANG decimal64 values cannot be represented using an exponent.
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
 	}
 	return i
 }
@@ -1436,7 +1870,7 @@ func keyValue(v reflect.Value, prependModuleNameIref bool) (any, error) {
 		return nil, err
 	}
 	if !valueSet {
-		return nil, fmt.Errorf("keyValue: Unset enum value: %v", v)
+		return nil, ClassifyError(ErrUnsetKey, fmt.Errorf("keyValue: Unset enum value: %v", v))
 	}
 
 	return name, nil
@@ -1448,7 +1882,7 @@ func mapKeyToJSONString(k reflect.Value, args jsonOutputConfig) (string, error)
 	case RFC7951:
 		k, err := keyValue(k, false)
 		if err != nil {
-			return "", fmt.Errorf("invalid enumerated key: %v", err)
+			return "", fmt.Errorf("invalid enumerated key: %w", err)
 		}
 		return fmt.Sprintf("%v", k), nil
 	case Internal:
@@ -1467,14 +1901,14 @@ func mapKeyToJSONString(k reflect.Value, args jsonOutputConfig) (string, error)
 				}
 				kp = append(kp, fmt.Sprintf("%v", keyval))
 			}
-			if errs.Err() != nil {
-				return "", errs.Err()
+			if listErr(errs) != nil {
+				return "", listErr(errs)
 			}
 			return strings.Join(kp, " "), nil
 		case reflect.Int64:
 			keyval, err := keyValue(k, false)
 			if err != nil {
-				return "", fmt.Errorf("invalid enumerated key: %v", err)
+				return "", fmt.Errorf("invalid enumerated key: %w", err)
 			}
 			return fmt.Sprintf("%v", keyval), nil
 		default:
@@ -1519,6 +1953,9 @@ func mapValuePairsToJSON(pairs []mapValuePair, parentMod string, args jsonOutput
 		return nil, fmt.Errorf("invalid JSON format specified: %v", args.jType)
 	}
 	for _, pair := range pairs {
+		if args.done() {
+			break
+		}
 		goStruct, ok := pair.v.Interface().(GoStruct)
 		if !ok {
 			errs.Add(fmt.Errorf("cannot map struct %v, invalid GoStruct", pair.v.Interface()))
@@ -1541,8 +1978,8 @@ func mapValuePairsToJSON(pairs []mapValuePair, parentMod string, args jsonOutput
 			continue
 		}
 	}
-	if errs.Err() != nil {
-		return nil, errs.Err()
+	if listErr(errs) != nil {
+		return nil, listErr(errs)
 	}
 	return vals, nil
 }
@@ -1569,8 +2006,8 @@ func mapJSON(field reflect.Value, parentMod string, args jsonOutputConfig) (any,
 
 	js, err := mapValuePairsToJSON(pairs, parentMod, args)
 	errs.Add(err)
-	if errs.Err() != nil {
-		return nil, errs.Err()
+	if listErr(errs) != nil {
+		return nil, listErr(errs)
 	}
 	return js, nil
 }
@@ -1591,6 +2028,46 @@ func jsonValue(field reflect.Value, parentMod string, args jsonOutputConfig) (an
 		}
 	}
 
+	// User-specified types for binary and empty leaves are rendered in the
+	// same way as the generated Binary and YANGEmpty types.
+	if field.CanInterface() {
+		switch v := field.Interface().(type) {
+		case BinaryValue:
+			if field.IsZero() {
+				return nil, nil
+			}
+			b, err := v.YANGBinary()
+			if err != nil {
+				return nil, fmt.Errorf("cannot marshal binary value, %w", err)
+			}
+			return binaryBase64(b), nil
+		case EmptyValue:
+			switch {
+			case args.jType == RFC7951 && v.YANGEmpty():
+				return []any{nil}, nil
+			case v.YANGEmpty():
+				return true, nil
+			default:
+				return nil, nil
+			}
+		case Decimal64:
+			// An unset Decimal64 leaf has the zero value.
+			if field.IsZero() {
+				return nil, nil
+			}
+			if args.jType == RFC7951 {
+				return v.String(), nil
+			}
+			return v, nil
+		}
+	}
+
+	if v, ok, err := enumIntJSONValue(field, args); err != nil {
+		return nil, err
+	} else if ok {
+		return v, nil
+	}
+
 	prependModuleNameIref := args.rfc7951Config != nil && (args.rfc7951Config.AppendModuleName || args.rfc7951Config.PrependModuleNameIdentityref)
 
 	// When jsonValue is called using the output of reflect.ValueOf()
@@ -1626,8 +2103,8 @@ func jsonValue(field reflect.Value, parentMod string, args jsonOutputConfig) (an
 
 			js, err := mapValuePairsToJSON(pairs, parentMod, args)
 			errs.Add(err)
-			if errs.Err() != nil {
-				return nil, errs.Err()
+			if listErr(errs) != nil {
+				return nil, listErr(errs)
 			}
 			return js, nil
 		}
@@ -1645,10 +2122,7 @@ func jsonValue(field reflect.Value, parentMod string, args jsonOutputConfig) (an
 				errs.Add(err)
 			}
 		default:
-			value = field.Elem().Interface()
-			if args.jType == RFC7951 {
-				value = writeIETFScalarJSON(value)
-			}
+			value = args.scalarJSON(field.Elem().Interface())
 		}
 	case reflect.Slice:
 
@@ -1719,9 +2193,7 @@ func jsonValue(field reflect.Value, parentMod string, args jsonOutputConfig) (an
 				return nil, err
 			}
 		}
-		if args.jType == RFC7951 {
-			value = writeIETFScalarJSON(value)
-		}
+		value = args.scalarJSON(value)
 	case reflect.Bool:
 		// A non-pointer field of type boolean is an empty leaf within the YANG schema.
 		// For RFC7951 this is represented as a null JSON array (i.e., [null]). For internal
@@ -1756,13 +2228,11 @@ func jsonValue(field reflect.Value, parentMod string, args jsonOutputConfig) (an
 		if value, err = resolveUnionVal(field.Interface(), prependModuleNameIref); err != nil {
 			return nil, err
 		}
-		if args.jType == RFC7951 {
-			value = writeIETFScalarJSON(value)
-		}
+		value = args.scalarJSON(value)
 	}
 
-	if errs.Err() != nil {
-		return nil, errs.Err()
+	if listErr(errs) != nil {
+		return nil, listErr(errs)
 	}
 	return value, nil
 }
@@ -1774,11 +2244,11 @@ func jsonValue(field reflect.Value, parentMod string, args jsonOutputConfig) (an
 func normalizeJSONValue(v any) (any, error) {
 	b, err := json.Marshal(v)
 	if err != nil {
-		return nil, fmt.Errorf("cannot marshal JSON value: %v", err)
+		return nil, fmt.Errorf("cannot marshal JSON value: %w", err)
 	}
 	var nv any
 	if err = json.Unmarshal(b, &nv); err != nil {
-		return nil, fmt.Errorf("cannot unmarshal JSON value: %v", err)
+		return nil, fmt.Errorf("cannot unmarshal JSON value: %w", err)
 	}
 	return nv, nil
 }
@@ -1817,7 +2287,7 @@ func jsonSlice(field reflect.Value, parentMod string, args jsonOutputConfig) (an
 	prependModuleNameIref := args.rfc7951Config != nil && (args.rfc7951Config.AppendModuleName || args.rfc7951Config.PrependModuleNameIdentityref)
 	sl, err := leaflistToSlice(field, prependModuleNameIref)
 	if err != nil {
-		return nil, fmt.Errorf("could not map slice (leaf-list or unkeyed list): %v", err)
+		return nil, fmt.Errorf("could not map slice (leaf-list or unkeyed list): %w", err)
 	}
 	for j, e := range sl {
 		switch {
@@ -1825,27 +2295,82 @@ func jsonSlice(field reflect.Value, parentMod string, args jsonOutputConfig) (an
 			// This is a slice within a slice which can only be a binary value,
 			// so we base64 encode it.
 			sl[j] = binaryBase64(reflect.ValueOf(e).Bytes())
-		case args.jType == RFC7951:
-			sl[j] = writeIETFScalarJSON(e)
+		default:
+			sl[j] = args.scalarJSON(e)
+		}
+		if v, ok, err := enumIntJSONValue(field.Index(j), args); err != nil {
+			return nil, err
+		} else if ok {
+			sl[j] = v
 		}
 	}
 	return sl, nil
 }
 
+// enumIntJSONValue returns the integer value of the enumeration held by field,
+// which is either an enumerated leaf or a union, and true, if the JSON output
+// config specifies that enumerations are rendered as integers. A nil value is
+// returned if the enumeration is unset. False is returned if field does not
+// hold an enumeration, including where it holds an identity value.
+func enumIntJSONValue(field reflect.Value, args jsonOutputConfig) (any, bool, error) {
+	if !args.enumsAsInts() {
+		return nil, false, nil
+	}
+	v := field
+	switch field.Kind() {
+	case reflect.Int64:
+	case reflect.Interface:
+		if field.IsNil() {
+			return nil, false, nil
+		}
+		// Unions are either represented by the enumerated type itself,
+		// or by a wrapper struct with a single field.
+		v = field.Elem()
+		if v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Struct && v.Elem().NumField() == 1 {
+			v = v.Elem().Field(0)
+		}
+	default:
+		return nil, false, nil
+	}
+	if !v.CanInterface() {
+		return nil, false, nil
+	}
+	e, ok := v.Interface().(GoEnum)
+	if !ok {
+		return nil, false, nil
+	}
+	i, set, isEnum, err := enumFieldToInt(e)
+	switch {
+	case err != nil:
+		return nil, false, err
+	case !isEnum:
+		return nil, false, nil
+	case !set:
+		return nil, true, nil
+	}
+	return i, true, nil
+}
+
 // jsonAnnotationSlice takes a reflect.Value which must represent a
 // ygot Annotation field ([]ygot.Annotation), and marshals it to JSON to be
-// included in the output JSON.
+// included in the output JSON. If all of the annotations are
+// MetadataAnnotations, they are marshalled as the members of an object, as
+// described by RFC7952, otherwise they are marshalled as an array.
 func jsonAnnotationSlice(v reflect.Value) (any, error) {
 	if v.Len() == 0 {
 		return nil, nil
 	}
 
+	if md, ok, err := jsonMetadataAnnotations(v); ok || err != nil {
+		return md, err
+	}
+
 	vals := []any{}
 	for i := 0; i < v.Len(); i++ {
 		fv := v.Index(i).Interface().(Annotation)
 		jv, err := fv.MarshalJSON()
 		if err != nil {
-			return nil, fmt.Errorf("cannot marshal annotation %v type %T to JSON: %v", fv, fv, err)
+			return nil, fmt.Errorf("cannot marshal annotation %v type %T to JSON: %w", fv, fv, err)
 		}
 
 		// MarshalJSON returns []byte, but we really want to have this as the unmarshalled
@@ -1853,13 +2378,45 @@ func jsonAnnotationSlice(v reflect.Value) (any, error) {
 		// are later marshalled, we therefore unmarshal the []byte into an any
 		var nv any
 		if err := json.Unmarshal(jv, &nv); err != nil {
-			return nil, fmt.Errorf("annotation %v, type %T could not be unmarshalled from JSON: %v", fv, fv, err)
+			return nil, fmt.Errorf("annotation %v, type %T could not be unmarshalled from JSON: %w", fv, fv, err)
 		}
 		vals = append(vals, nv)
 	}
 	return vals, nil
 }
 
+// jsonMetadataAnnotations marshals the annotation field v to a map, keyed by
+// the qualified name of each annotation, of the JSON value of the annotation.
+// It returns false if any of the annotations is not a MetadataAnnotation.
+func jsonMetadataAnnotations(v reflect.Value) (map[string]any, bool, error) {
+	mds := make([]MetadataAnnotation, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		md, ok := v.Index(i).Interface().(MetadataAnnotation)
+		if !ok || util.IsValueNil(md) {
+			return nil, false, nil
+		}
+		mds = append(mds, md)
+	}
+
+	vals := map[string]any{}
+	for _, md := range mds {
+		name := md.ΛMetadataName()
+		if _, ok := vals[name]; ok {
+			return nil, false, fmt.Errorf("duplicate metadata annotation %s", name)
+		}
+		jv, err := md.MarshalJSON()
+		if err != nil {
+			return nil, false, fmt.Errorf("cannot marshal metadata annotation %s to JSON: %w", name, err)
+		}
+		var nv any
+		if err := json.Unmarshal(jv, &nv); err != nil {
+			return nil, false, fmt.Errorf("metadata annotation %s could not be unmarshalled from JSON: %w", name, err)
+		}
+		vals[name] = nv
+	}
+	return vals, true, nil
+}
+
 // unwrapUnionInterfaceValue takes an input reflect.Value which must contain
 // an interface Value, and resolves it from the generated wrapper union struct
 // to the value which should be used for the YANG leaf.
@@ -1948,5 +2505,12 @@ func resolveUnionVal(v any, prependModuleNameIref bool) (any, error) {
 		}
 		v = val
 	}
+	// The simple union type of a Decimal64 is resolved to the Decimal64, such
+	// that it is rendered in the same way.
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Struct {
+		if ut, ok := unionSingletonUnderlyingTypes[rv.Type().Name()]; ok && rv.Type().ConvertibleTo(ut) {
+			v = rv.Convert(ut).Interface()
+		}
+	}
 	return v, nil
 }
@@ -15,11 +15,14 @@
 package ygot
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -784,6 +787,7 @@ type renderExample struct {
 	UnionLeafListSimple []exampleUnion                      `path:"union-list-simple"`
 	Binary              Binary                              `path:"binary"`
 	KeylessList         []*renderExampleList                `path:"keyless-list"`
+	IndexedKeylessList  []*renderExampleList                `path:"indexed-keyless-list" keyless-index:"_index"`
 	InvalidMap          map[string]*invalidGoStruct         `path:"invalid-gostruct-map"`
 	InvalidPtr          *invalidGoStruct                    `path:"invalid-gostruct"`
 	Empty               YANGEmpty                           `path:"empty"`
@@ -1424,6 +1428,58 @@ func TestTogNMINotifications(t *testing.T) {
 			},
 		},
 		wantErr: true, //unimplemented.
+	}, {
+		name:        "keyless list with index tag",
+		inTimestamp: 42,
+		inStruct: &renderExample{
+			IndexedKeylessList: []*renderExampleList{
+				{String("trillian")},
+				{String("arthur")},
+			},
+		},
+		want: []*gnmipb.Notification{{
+			Timestamp: 42,
+			Update: []*gnmipb.Update{{
+				Path: &gnmipb.Path{Element: []string{"indexed-keyless-list", "0", "val"}},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{"trillian"}},
+			}, {
+				Path: &gnmipb.Path{Element: []string{"indexed-keyless-list", "0", "state", "val"}},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{"trillian"}},
+			}, {
+				Path: &gnmipb.Path{Element: []string{"indexed-keyless-list", "1", "val"}},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{"arthur"}},
+			}, {
+				Path: &gnmipb.Path{Element: []string{"indexed-keyless-list", "1", "state", "val"}},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{"arthur"}},
+			}},
+		}},
+	}, {
+		name:        "keyless list with index tag and path elements",
+		inTimestamp: 42,
+		inStruct: &renderExample{
+			IndexedKeylessList: []*renderExampleList{
+				{String("trillian")},
+				nil,
+				{String("arthur")},
+			},
+		},
+		inConfig: GNMINotificationsConfig{UsePathElem: true},
+		want: []*gnmipb.Notification{{
+			Timestamp: 42,
+			Update: []*gnmipb.Update{{
+				Path: &gnmipb.Path{Elem: mustPathElem("indexed-keyless-list[_index=0]/val")},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{"trillian"}},
+			}, {
+				Path: &gnmipb.Path{Elem: mustPathElem("indexed-keyless-list[_index=0]/state/val")},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{"trillian"}},
+			}, {
+				Path: &gnmipb.Path{Elem: mustPathElem("indexed-keyless-list[_index=2]/val")},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{"arthur"}},
+			}, {
+				Path: &gnmipb.Path{Elem: mustPathElem("indexed-keyless-list[_index=2]/state/val")},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{"arthur"}},
+			}},
+		}},
 	}, {
 		name:        "invalid element in leaf-list",
 		inTimestamp: 42,
@@ -1563,6 +1619,60 @@ func TestTogNMINotifications(t *testing.T) {
 				Val: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_UintVal{16}},
 			}},
 		}},
+	}, {
+		name:        "JSON_IETF update depth",
+		inTimestamp: 42,
+		inStruct: &renderExample{
+			Str: String("hello"),
+			Ch:  &renderExampleChild{Val: Uint64(42)},
+			List: map[uint32]*renderExampleList{
+				1: {Val: String("one")},
+			},
+			IndexedKeylessList: []*renderExampleList{{Val: String("two")}},
+		},
+		inConfig: GNMINotificationsConfig{UsePathElem: true, JSONIETFUpdateDepth: 1},
+		want: []*gnmipb.Notification{{
+			Timestamp: 42,
+			Update: []*gnmipb.Update{{
+				Path: &gnmipb.Path{Elem: mustPathElem("str")},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{"hello"}},
+			}, {
+				Path: &gnmipb.Path{Elem: mustPathElem("ch")},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{[]byte("{\n  \"val\": \"42\"\n}")}},
+			}, {
+				Path: &gnmipb.Path{Elem: mustPathElem("list[val=one]")},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{[]byte("{\n  \"state\": {\n    \"val\": \"one\"\n  },\n  \"val\": \"one\"\n}")}},
+			}, {
+				Path: &gnmipb.Path{Elem: mustPathElem("indexed-keyless-list[_index=0]")},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{[]byte("{\n  \"state\": {\n    \"val\": \"two\"\n  },\n  \"val\": \"two\"\n}")}},
+			}},
+		}},
+	}, {
+		name:        "JSON_IETF update depth below containers",
+		inTimestamp: 42,
+		inStruct: &renderExample{
+			Ch: &renderExampleChild{Val: Uint64(42)},
+		},
+		inConfig: GNMINotificationsConfig{UsePathElem: true, JSONIETFUpdateDepth: 2},
+		want: []*gnmipb.Notification{{
+			Timestamp: 42,
+			Update: []*gnmipb.Update{{
+				Path: &gnmipb.Path{Elem: mustPathElem("ch/val")},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_UintVal{42}},
+			}},
+		}},
+	}, {
+		name:        "JSON_IETF update depth with string slice paths",
+		inTimestamp: 42,
+		inStruct:    &renderExample{Str: String("hello")},
+		inConfig:    GNMINotificationsConfig{JSONIETFUpdateDepth: 1},
+		wantErr:     true,
+	}, {
+		name:        "negative JSON_IETF update depth",
+		inTimestamp: 42,
+		inStruct:    &renderExample{Str: String("hello")},
+		inConfig:    GNMINotificationsConfig{UsePathElem: true, JSONIETFUpdateDepth: -1},
+		wantErr:     true,
 	}}
 
 	for _, tt := range tests {
@@ -2095,6 +2205,67 @@ func (t *unmarshalableJSON) UnmarshalJSON(d []byte) error {
 	return fmt.Errorf("unimplemented")
 }
 
+func TestTogNMINotificationsContext(t *testing.T) {
+	in := &renderExample{
+		Str: String("hello"),
+		Ch:  &renderExampleChild{Val: Uint64(42)},
+		List: map[uint32]*renderExampleList{
+			42: {Val: String("forty-two")},
+		},
+	}
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Unix(0, 0))
+	defer cancel()
+
+	tests := []struct {
+		desc        string
+		inCtx       context.Context
+		inOpts      []RenderContextOpt
+		wantUpdates int
+		wantNil     bool
+		wantErr     error
+	}{{
+		desc:        "context not done",
+		inCtx:       context.Background(),
+		wantUpdates: 4,
+	}, {
+		desc:    "cancelled context",
+		inCtx:   cancelled,
+		wantNil: true,
+		wantErr: context.Canceled,
+	}, {
+		desc:    "cancelled context with partial results",
+		inCtx:   cancelled,
+		inOpts:  []RenderContextOpt{&PartialResultsOnCancel{}},
+		wantErr: context.Canceled,
+	}, {
+		desc:    "deadline exceeded",
+		inCtx:   expired,
+		wantNil: true,
+		wantErr: context.DeadlineExceeded,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := TogNMINotificationsContext(tt.inCtx, in, 42, GNMINotificationsConfig{UsePathElem: true}, tt.inOpts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TogNMINotificationsContext: got error %v, want %v", err, tt.wantErr)
+			}
+			if (got == nil) != tt.wantNil {
+				t.Fatalf("TogNMINotificationsContext: got notifications %v, want nil %v", got, tt.wantNil)
+			}
+			var updates int
+			for _, n := range got {
+				updates += len(n.GetUpdate())
+			}
+			if updates != tt.wantUpdates {
+				t.Errorf("TogNMINotificationsContext: got %d updates, want %d", updates, tt.wantUpdates)
+			}
+		})
+	}
+}
+
 func TestConstructJSON(t *testing.T) {
 	tests := []struct {
 		name                     string
@@ -3460,6 +3631,172 @@ func TestConstructJSON(t *testing.T) {
 	}
 }
 
+type modulePrefixExample struct {
+	F1 *string                    `path:"f1" module:"f1mod" module-prefix:"f1"`
+	F2 *string                    `path:"config/f2" module:"f2mod/f2mod" module-prefix:"f2/f2"`
+	F3 *modulePrefixExampleChild  `path:"f3" module:"f1mod" module-prefix:"f1"`
+	F6 *string                    `path:"config/f6" module:"f1mod/f2mod" module-prefix:"f1/f2"`
+	F7 *string                    `path:"config/f7|f7" module:"f1mod/f3mod|f3mod" module-prefix:"f1/f3|f3" shadow-path:"state/f7" shadow-module:"f1mod/f3mod" shadow-module-prefix:"f1/f3-state"`
+	F8 *modulePrefixExampleNoTags `path:"f8" module:"f1mod" module-prefix:"f1"`
+}
+
+func (*modulePrefixExample) IsYANGGoStruct()                         {}
+func (*modulePrefixExample) ΛValidate(...ValidationOption) error     { return nil }
+func (*modulePrefixExample) ΛEnumTypeMap() map[string][]reflect.Type { return nil }
+func (*modulePrefixExample) ΛBelongingModule() string                { return "f1mod" }
+
+type modulePrefixExampleChild struct {
+	F4 *string `path:"config/f4" module:"f42mod/f42mod" module-prefix:"f42/f42"`
+	F5 *string `path:"f5" module:"f1mod" module-prefix:"f1"`
+}
+
+func (*modulePrefixExampleChild) IsYANGGoStruct()                         {}
+func (*modulePrefixExampleChild) ΛValidate(...ValidationOption) error     { return nil }
+func (*modulePrefixExampleChild) ΛEnumTypeMap() map[string][]reflect.Type { return nil }
+func (*modulePrefixExampleChild) ΛBelongingModule() string                { return "f1mod" }
+
+type modulePrefixExampleNoTags struct {
+	F9 *string `path:"f9" module:"f9mod"`
+}
+
+func (*modulePrefixExampleNoTags) IsYANGGoStruct()                         {}
+func (*modulePrefixExampleNoTags) ΛValidate(...ValidationOption) error     { return nil }
+func (*modulePrefixExampleNoTags) ΛEnumTypeMap() map[string][]reflect.Type { return nil }
+func (*modulePrefixExampleNoTags) ΛBelongingModule() string                { return "f1mod" }
+
+func TestConstructIETFJSONModulePrefixes(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       GoStruct
+		inConfig *RFC7951JSONConfig
+		want     map[string]any
+		wantErr  string
+	}{{
+		name: "module prefixes",
+		in: &modulePrefixExample{
+			F1: String("foo"),
+			F2: String("bar"),
+			F3: &modulePrefixExampleChild{
+				F4: String("baz"),
+				F5: String("hat"),
+			},
+			F6: String("mat"),
+			F7: String("bat"),
+		},
+		inConfig: &RFC7951JSONConfig{AppendModuleName: true, UseModulePrefixes: true},
+		want: map[string]any{
+			"f1:f1": "foo",
+			"f1:config": map[string]any{
+				"f2:f6": "mat",
+				"f3:f7": "bat",
+			},
+			"f2:config": map[string]any{
+				"f2": "bar",
+			},
+			"f3:f7": "bat",
+			"f1:f3": map[string]any{
+				"f42:config": map[string]any{
+					"f4": "baz",
+				},
+				"f5": "hat",
+			},
+		},
+	}, {
+		name:     "module prefixes of shadow path",
+		in:       &modulePrefixExample{F7: String("bat")},
+		inConfig: &RFC7951JSONConfig{AppendModuleName: true, UseModulePrefixes: true, PreferShadowPath: true},
+		want: map[string]any{
+			"f1:state": map[string]any{
+				"f3-state:f7": "bat",
+			},
+		},
+	}, {
+		name:     "module prefixes not used without module names",
+		in:       &modulePrefixExample{F1: String("foo")},
+		inConfig: &RFC7951JSONConfig{UseModulePrefixes: true},
+		want: map[string]any{
+			"f1": "foo",
+		},
+	}, {
+		name:     "missing module prefix tag",
+		in:       &modulePrefixExample{F8: &modulePrefixExampleNoTags{F9: String("foo")}},
+		inConfig: &RFC7951JSONConfig{AppendModuleName: true, UseModulePrefixes: true},
+		wantErr:  "field F9 has a module tag but no module-prefix tag",
+	}, {
+		name:     "rewritten module names",
+		in:       &modulePrefixExample{F1: String("foo")},
+		inConfig: &RFC7951JSONConfig{AppendModuleName: true, UseModulePrefixes: true, RewriteModuleNames: map[string]string{"f2mod": "f1mod"}},
+		wantErr:  "module names cannot be rewritten when module prefixes are used",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConstructIETFJSON(tt.in, tt.inConfig)
+			if diff := errdiff.Substring(err, tt.wantErr); diff != "" {
+				t.Fatalf("ConstructIETFJSON(%v): did not get expected error, %s", tt.in, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ConstructIETFJSON(%v): did not get expected output, diff(-want,+got):\n%s", tt.in, diff)
+			}
+		})
+	}
+}
+
+func TestConstructIETFJSONContext(t *testing.T) {
+	in := &renderExample{
+		Str: String("hello"),
+		Ch:  &renderExampleChild{Val: Uint64(42)},
+	}
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Unix(0, 0))
+	defer cancel()
+
+	tests := []struct {
+		desc    string
+		inCtx   context.Context
+		inOpts  []RenderContextOpt
+		want    map[string]any
+		wantErr error
+	}{{
+		desc:  "context not done",
+		inCtx: context.Background(),
+		want: map[string]any{
+			"str": "hello",
+			"ch":  map[string]any{"val": "42"},
+		},
+	}, {
+		desc:    "cancelled context",
+		inCtx:   cancelled,
+		wantErr: context.Canceled,
+	}, {
+		desc:    "cancelled context with partial results",
+		inCtx:   cancelled,
+		inOpts:  []RenderContextOpt{&PartialResultsOnCancel{}},
+		want:    map[string]any{},
+		wantErr: context.Canceled,
+	}, {
+		desc:    "deadline exceeded",
+		inCtx:   expired,
+		wantErr: context.DeadlineExceeded,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ConstructIETFJSONContext(tt.inCtx, in, nil, tt.inOpts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ConstructIETFJSONContext: got error %v, want %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ConstructIETFJSONContext: did not get expected output, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
 // Synthesised types for TestUnionInterfaceValue
 type unionTestOne struct {
 	UField uFieldInterface
@@ -4139,6 +4476,22 @@ func TestFindUpdatedLeaves(t *testing.T) {
 		},
 		inParent:         &gnmiPath{pathElemPath: []*gnmipb.PathElem{}},
 		wantErrSubstring: "keyless list cannot be output",
+	}, {
+		name: "keyless list with index tag",
+		in: &renderExample{
+			IndexedKeylessList: []*renderExampleList{
+				{Val: String("one")},
+			},
+		},
+		inParent: &gnmiPath{pathElemPath: []*gnmipb.PathElem{}},
+		wantLeaves: map[*path]any{
+			{p: &gnmiPath{
+				pathElemPath: mustPathElem("indexed-keyless-list[_index=0]/state/val"),
+			}}: String("one"),
+			{p: &gnmiPath{
+				pathElemPath: mustPathElem("indexed-keyless-list[_index=0]/val"),
+			}}: String("one"),
+		},
 	}, {
 		name: "union",
 		in: &renderExample{
@@ -4183,7 +4536,7 @@ func TestFindUpdatedLeaves(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			gotLeaves := map[*path]any{}
-			if err := findUpdatedLeaves(gotLeaves, tt.in, tt.inParent, false); err != nil {
+			if err := findUpdatedLeaves(context.Background(), gotLeaves, tt.in, tt.inParent, false, 0); err != nil {
 				if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
 					t.Fatalf("did not get expected error, %v", err)
 				}
@@ -4480,3 +4833,516 @@ func TestMarshal7951(t *testing.T) {
 		})
 	}
 }
+
+// renderSensitiveExample is a GoStruct containing leaves that are marked as
+// sensitive.
+type renderSensitiveExample struct {
+	Name     *string                 `path:"name"`
+	Password *string                 `path:"password" sensitive:"true"`
+	Keys     []string                `path:"keys" sensitive:"true"`
+	Child    *renderSensitiveExample `path:"child"`
+}
+
+// IsYANGGoStruct implements the GoStruct interface.
+func (*renderSensitiveExample) IsYANGGoStruct()                         {}
+func (*renderSensitiveExample) ΛValidate(...ValidationOption) error     { return nil }
+func (*renderSensitiveExample) ΛEnumTypeMap() map[string][]reflect.Type { return nil }
+func (*renderSensitiveExample) ΛBelongingModule() string                { return "" }
+
+func TestRedactSensitiveLeaves(t *testing.T) {
+	in := &renderSensitiveExample{
+		Name:     String("alice"),
+		Password: String("hunter2"),
+		Keys:     []string{"k1", "k2"},
+		Child:    &renderSensitiveExample{Password: String("secret")},
+	}
+
+	tests := []struct {
+		desc   string
+		inArgs []Marshal7951Arg
+		want   string
+	}{{
+		desc: "sensitive leaves not redacted",
+		want: `{"child":{"password":"secret"},"keys":["k1","k2"],"name":"alice","password":"hunter2"}`,
+	}, {
+		desc:   "sensitive leaves redacted",
+		inArgs: []Marshal7951Arg{&RFC7951JSONConfig{RedactSensitiveLeaves: true}},
+		want:   `{"child":{"password":"\u003credacted\u003e"},"keys":"\u003credacted\u003e","name":"alice","password":"\u003credacted\u003e"}`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := Marshal7951(in, tt.inArgs...)
+			if err != nil {
+				t.Fatalf("Marshal7951: got unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, string(got)); diff != "" {
+				t.Errorf("Marshal7951: did not get expected output (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRedactedString(t *testing.T) {
+	tests := []struct {
+		desc string
+		in   GoStruct
+		want string
+	}{{
+		desc: "nil struct",
+		in:   (*renderSensitiveExample)(nil),
+		want: "<nil>",
+	}, {
+		desc: "empty struct",
+		in:   &renderSensitiveExample{},
+		want: "{}",
+	}, {
+		desc: "sensitive leaves",
+		in: &renderSensitiveExample{
+			Password: String("hunter2"),
+			Name:     String("alice"),
+			Child:    &renderSensitiveExample{Keys: []string{"k1"}},
+		},
+		want: `{"child":{"keys":"<redacted>"},"name":"alice","password":"<redacted>"}`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := RedactedString(tt.in); got != tt.want {
+				t.Errorf("RedactedString: got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// lazyBinary is a user-specified type for binary leaves, which stores the
+// base64 encoding of the value.
+type lazyBinary string
+
+func (b lazyBinary) YANGBinary() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(string(b))
+}
+
+// presence is a user-specified type for empty leaves.
+type presence bool
+
+func (p presence) YANGEmpty() bool { return bool(p) }
+
+// customTypesExample is a GoStruct with fields of user-specified types for
+// binary and empty leaves.
+type customTypesExample struct {
+	Bin   lazyBinary `path:"bin"`
+	Empty presence   `path:"empty"`
+}
+
+func (*customTypesExample) IsYANGGoStruct()                         {}
+func (*customTypesExample) ΛValidate(...ValidationOption) error     { return nil }
+func (*customTypesExample) ΛEnumTypeMap() map[string][]reflect.Type { return nil }
+func (*customTypesExample) ΛBelongingModule() string                { return "" }
+
+func TestCustomBinaryEmptyTypes(t *testing.T) {
+	tests := []struct {
+		name             string
+		in               *customTypesExample
+		wantJSON         map[string]any
+		wantInternalJSON map[string]any
+		wantUpdates      []*gnmipb.Update
+		wantErrSubstring string
+	}{{
+		name:             "unset",
+		in:               &customTypesExample{},
+		wantJSON:         map[string]any{},
+		wantInternalJSON: map[string]any{},
+	}, {
+		name:             "set",
+		in:               &customTypesExample{Bin: lazyBinary(base64testStringEncoded), Empty: true},
+		wantJSON:         map[string]any{"bin": base64testStringEncoded, "empty": []any{nil}},
+		wantInternalJSON: map[string]any{"bin": base64testStringEncoded, "empty": true},
+		wantUpdates: []*gnmipb.Update{{
+			Path: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "bin"}}},
+			Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_BytesVal{BytesVal: []byte(base64testString)}},
+		}, {
+			Path: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "empty"}}},
+			Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_BoolVal{BoolVal: true}},
+		}},
+	}, {
+		name:             "invalid binary value",
+		in:               &customTypesExample{Bin: lazyBinary("!")},
+		wantErrSubstring: "cannot marshal binary value",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotJSON, err := ConstructIETFJSON(tt.in, nil)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("ConstructIETFJSON: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.wantJSON, gotJSON); diff != "" {
+				t.Errorf("ConstructIETFJSON: did not get expected JSON, diff(-want,+got):\n%s", diff)
+			}
+
+			gotInternal, err := ConstructInternalJSON(tt.in)
+			if err != nil {
+				t.Fatalf("ConstructInternalJSON: got unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantInternalJSON, gotInternal); diff != "" {
+				t.Errorf("ConstructInternalJSON: did not get expected JSON, diff(-want,+got):\n%s", diff)
+			}
+
+			gotNotifs, err := TogNMINotifications(tt.in, 0, GNMINotificationsConfig{UsePathElem: true})
+			if err != nil {
+				t.Fatalf("TogNMINotifications: got unexpected error: %v", err)
+			}
+			wantNotifs := []*gnmipb.Notification{{Update: tt.wantUpdates}}
+			if diff := cmp.Diff(wantNotifs, gotNotifs, protocmp.Transform(), protocmp.SortRepeatedFields(&gnmipb.Notification{}, "update")); diff != "" {
+				t.Errorf("TogNMINotifications: did not get expected notifications, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// EnumValTest is a synthesised derived type which is used to represent an
+// enumeration, rather than an identity, in the YANG schema.
+type EnumValTest int64
+
+func (EnumValTest) IsYANGGoEnum()   {}
+func (EnumValTest) IsExampleUnion() {}
+
+func (EnumValTest) ΛMap() map[string]map[int64]EnumDefinition {
+	return map[string]map[int64]EnumDefinition{
+		"EnumValTest": {
+			1: EnumDefinition{Name: "ZERO"},
+			6: EnumDefinition{Name: "FIVE"},
+		},
+	}
+}
+
+func (e EnumValTest) String() string {
+	return EnumLogString(e, int64(e), "EnumValTest")
+}
+
+// enumsAsIntsExample is a GoStruct containing enumerated values that is used
+// to test the EnumsAsInts options.
+type enumsAsIntsExample struct {
+	Enum     EnumValTest   `path:"enum"`
+	Unset    EnumValTest   `path:"unset"`
+	Identity EnumTest      `path:"identity"`
+	Union    exampleUnion  `path:"union"`
+	LeafList []EnumValTest `path:"leaf-list"`
+}
+
+// IsYANGGoStruct implements the GoStruct interface.
+func (*enumsAsIntsExample) IsYANGGoStruct()                         {}
+func (*enumsAsIntsExample) ΛValidate(...ValidationOption) error     { return nil }
+func (*enumsAsIntsExample) ΛEnumTypeMap() map[string][]reflect.Type { return nil }
+func (*enumsAsIntsExample) ΛBelongingModule() string                { return "" }
+
+func TestEnumsAsInts(t *testing.T) {
+	in := &enumsAsIntsExample{
+		Enum:     6,
+		Identity: EnumTestVALONE,
+		Union:    EnumValTest(1),
+		LeafList: []EnumValTest{1, 6},
+	}
+
+	tests := []struct {
+		desc             string
+		in               GoStruct
+		inConfig         *EmitJSONConfig
+		want             string
+		wantErrSubstring string
+	}{{
+		desc:     "internal JSON with names",
+		in:       in,
+		inConfig: &EmitJSONConfig{Format: Internal, SkipValidation: true, Indent: " "},
+		want: `{
+ "enum": "FIVE",
+ "identity": "VAL_ONE",
+ "leaf-list": [
+  "ZERO",
+  "FIVE"
+ ],
+ "union": "ZERO"
+}`,
+	}, {
+		desc:     "internal JSON with integers",
+		in:       in,
+		inConfig: &EmitJSONConfig{Format: Internal, SkipValidation: true, Indent: " ", EnumsAsInts: true},
+		want: `{
+ "enum": 5,
+ "identity": "VAL_ONE",
+ "leaf-list": [
+  0,
+  5
+ ],
+ "union": 0
+}`,
+	}, {
+		desc: "RFC7951 JSON with integers",
+		in:   in,
+		inConfig: &EmitJSONConfig{
+			Format:         RFC7951,
+			RFC7951Config:  &RFC7951JSONConfig{AppendModuleName: true, EnumsAsInts: true},
+			SkipValidation: true,
+			Indent:         " ",
+		},
+		want: `{
+ "enum": 5,
+ "identity": "foo:VAL_ONE",
+ "leaf-list": [
+  0,
+  5
+ ],
+ "union": 0
+}`,
+	}, {
+		desc:             "unknown enumerated value",
+		in:               &enumsAsIntsExample{Enum: 2},
+		inConfig:         &EmitJSONConfig{Format: Internal, SkipValidation: true, EnumsAsInts: true},
+		wantErrSubstring: "has unknown value 2",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := EmitJSON(tt.in, tt.inConfig)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("EmitJSON: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("EmitJSON: did not get expected JSON, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// int64AsStringExample is a GoStruct containing 64-bit integer values that is
+// used to test the Int64AsString option.
+type int64AsStringExample struct {
+	Int64    *int64       `path:"int64"`
+	Uint64   *uint64      `path:"uint64"`
+	Int32    *int32       `path:"int32"`
+	Enum     EnumValTest  `path:"enum"`
+	Union    exampleUnion `path:"union"`
+	LeafList []uint64     `path:"leaf-list"`
+}
+
+// IsYANGGoStruct implements the GoStruct interface.
+func (*int64AsStringExample) IsYANGGoStruct()                         {}
+func (*int64AsStringExample) ΛValidate(...ValidationOption) error     { return nil }
+func (*int64AsStringExample) ΛEnumTypeMap() map[string][]reflect.Type { return nil }
+func (*int64AsStringExample) ΛBelongingModule() string                { return "" }
+
+func TestInternalJSONInt64AsString(t *testing.T) {
+	in := &int64AsStringExample{
+		Int64:    Int64(-9007199254740993),
+		Uint64:   Uint64(18446744073709551615),
+		Int32:    Int32(42),
+		Enum:     6,
+		Union:    testutil.UnionInt64(84),
+		LeafList: []uint64{1, 2},
+	}
+
+	tests := []struct {
+		desc     string
+		inConfig *InternalJSONConfig
+		want     map[string]any
+	}{{
+		desc: "nil config",
+		want: map[string]any{
+			"int64":     int64(-9007199254740993),
+			"uint64":    uint64(18446744073709551615),
+			"int32":     int32(42),
+			"enum":      "FIVE",
+			"union":     testutil.UnionInt64(84),
+			"leaf-list": []any{uint64(1), uint64(2)},
+		},
+	}, {
+		desc:     "64-bit integers as numbers",
+		inConfig: &InternalJSONConfig{},
+		want: map[string]any{
+			"int64":     int64(-9007199254740993),
+			"uint64":    uint64(18446744073709551615),
+			"int32":     int32(42),
+			"enum":      "FIVE",
+			"union":     testutil.UnionInt64(84),
+			"leaf-list": []any{uint64(1), uint64(2)},
+		},
+	}, {
+		desc:     "64-bit integers as strings",
+		inConfig: &InternalJSONConfig{Int64AsString: true},
+		want: map[string]any{
+			"int64":     "-9007199254740993",
+			"uint64":    "18446744073709551615",
+			"int32":     int32(42),
+			"enum":      "FIVE",
+			"union":     "84",
+			"leaf-list": []any{"1", "2"},
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ConstructInternalJSONWithConfig(in, tt.inConfig)
+			if err != nil {
+				t.Fatalf("ConstructInternalJSONWithConfig: got unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ConstructInternalJSONWithConfig: did not get expected JSON, diff(-want,+got):\n%s", diff)
+			}
+
+			gotJSON, err := EmitJSON(in, &EmitJSONConfig{Format: Internal, SkipValidation: true, InternalConfig: tt.inConfig})
+			if err != nil {
+				t.Fatalf("EmitJSON: got unexpected error: %v", err)
+			}
+			wantJSON, err := json.MarshalIndent(tt.want, "", indentString)
+			if err != nil {
+				t.Fatalf("cannot marshal expected JSON: %v", err)
+			}
+			if diff := cmp.Diff(string(wantJSON), gotJSON); diff != "" {
+				t.Errorf("EmitJSON: did not get expected JSON, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// UnionDecimal64 is the simple union type of Decimal64, as generated when
+// decimal64 leaves use the Decimal64 type.
+type UnionDecimal64 Decimal64
+
+func (UnionDecimal64) IsYANGLeafValue() {}
+func (UnionDecimal64) IsTestUnion()     {}
+
+// decimal64Example is a GoStruct with fields that use the Decimal64 type for
+// decimal64 leaves.
+type decimal64Example struct {
+	Dec   Decimal64          `path:"dec"`
+	Decs  []Decimal64        `path:"decs"`
+	Union testutil.TestUnion `path:"union"`
+}
+
+func (*decimal64Example) IsYANGGoStruct()                         {}
+func (*decimal64Example) ΛValidate(...ValidationOption) error     { return nil }
+func (*decimal64Example) ΛEnumTypeMap() map[string][]reflect.Type { return nil }
+func (*decimal64Example) ΛBelongingModule() string                { return "" }
+
+func TestDecimal64Leaves(t *testing.T) {
+	tests := []struct {
+		name             string
+		in               *decimal64Example
+		wantJSON         map[string]any
+		wantInternalJSON map[string]any
+		wantUpdates      []*gnmipb.Update
+	}{{
+		name:             "unset",
+		in:               &decimal64Example{},
+		wantJSON:         map[string]any{},
+		wantInternalJSON: map[string]any{},
+	}, {
+		name: "set",
+		in: &decimal64Example{
+			Dec:   Decimal64{Digits: 1050, Precision: 2},
+			Decs:  []Decimal64{{Digits: 1, Precision: 18}, {Digits: -25, Precision: 3}},
+			Union: UnionDecimal64{Digits: 12, Precision: 2},
+		},
+		wantJSON: map[string]any{
+			"dec":   "10.5",
+			"decs":  []any{"0.000000000000000001", "-0.025"},
+			"union": "0.12",
+		},
+		wantInternalJSON: map[string]any{
+			"dec":   Decimal64{Digits: 1050, Precision: 2},
+			"decs":  []any{Decimal64{Digits: 1, Precision: 18}, Decimal64{Digits: -25, Precision: 3}},
+			"union": Decimal64{Digits: 12, Precision: 2},
+		},
+		wantUpdates: []*gnmipb.Update{{
+			Path: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "dec"}}},
+			Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_DecimalVal{DecimalVal: &gnmipb.Decimal64{Digits: 1050, Precision: 2}}},
+		}, {
+			Path: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "decs"}}},
+			Val: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_LeaflistVal{LeaflistVal: &gnmipb.ScalarArray{
+				Element: []*gnmipb.TypedValue{
+					{Value: &gnmipb.TypedValue_DecimalVal{DecimalVal: &gnmipb.Decimal64{Digits: 1, Precision: 18}}},
+					{Value: &gnmipb.TypedValue_DecimalVal{DecimalVal: &gnmipb.Decimal64{Digits: -25, Precision: 3}}},
+				},
+			}}},
+		}, {
+			Path: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "union"}}},
+			Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_DecimalVal{DecimalVal: &gnmipb.Decimal64{Digits: 12, Precision: 2}}},
+		}},
+	}, {
+		name:             "union string",
+		in:               &decimal64Example{Union: testutil.UnionString("none")},
+		wantJSON:         map[string]any{"union": "none"},
+		wantInternalJSON: map[string]any{"union": testutil.UnionString("none")},
+		wantUpdates: []*gnmipb.Update{{
+			Path: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "union"}}},
+			Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: "none"}},
+		}},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotJSON, err := ConstructIETFJSON(tt.in, nil)
+			if err != nil {
+				t.Fatalf("ConstructIETFJSON: got unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantJSON, gotJSON); diff != "" {
+				t.Errorf("ConstructIETFJSON: did not get expected JSON, diff(-want,+got):\n%s", diff)
+			}
+
+			gotInternal, err := ConstructInternalJSON(tt.in)
+			if err != nil {
+				t.Fatalf("ConstructInternalJSON: got unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantInternalJSON, gotInternal); diff != "" {
+				t.Errorf("ConstructInternalJSON: did not get expected JSON, diff(-want,+got):\n%s", diff)
+			}
+
+			gotNotifs, err := TogNMINotifications(tt.in, 0, GNMINotificationsConfig{UsePathElem: true})
+			if err != nil {
+				t.Fatalf("TogNMINotifications: got unexpected error: %v", err)
+			}
+			wantNotifs := []*gnmipb.Notification{{Update: tt.wantUpdates}}
+			if diff := cmp.Diff(wantNotifs, gotNotifs, protocmp.Transform(), protocmp.SortRepeatedFields(&gnmipb.Notification{}, "update")); diff != "" {
+				t.Errorf("TogNMINotifications: did not get expected notifications, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEncodeTypedValueDecimal64(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want *gnmipb.TypedValue
+	}{{
+		name: "unset",
+		in:   Decimal64{},
+	}, {
+		name: "set",
+		in:   Decimal64{Digits: -314, Precision: 2},
+		want: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_DecimalVal{DecimalVal: &gnmipb.Decimal64{Digits: -314, Precision: 2}}},
+	}, {
+		name: "simple union",
+		in:   UnionDecimal64{Digits: 314, Precision: 2},
+		want: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_DecimalVal{DecimalVal: &gnmipb.Decimal64{Digits: 314, Precision: 2}}},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EncodeTypedValue(tt.in, gnmipb.Encoding_JSON)
+			if err != nil {
+				t.Fatalf("EncodeTypedValue(%v): got unexpected error: %v", tt.in, err)
+			}
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("EncodeTypedValue(%v): did not get expected value, diff(-want,+got):\n%s", tt.in, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,471 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/gnmi/errlist"
+	"github.com/openconfig/ygot/internal/yreflect"
+	"github.com/openconfig/ygot/util"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Refer to: https://tools.ietf.org/html/rfc7950#section-13 for the XML
+// encoding of YANG-modelled data, and https://tools.ietf.org/html/rfc6241 for
+// the NETCONF protocol.
+
+// NETCONFBaseNamespace is the XML namespace of the NETCONF base protocol, as
+// defined in RFC 6241.
+const NETCONFBaseNamespace = "urn:ietf:params:xml:ns:netconf:base:1.0"
+
+// XMLOperation is a NETCONF <edit-config> operation that can be specified for
+// a subtree of the emitted XML.
+type XMLOperation string
+
+const (
+	// XMLOperationMerge specifies that the subtree is merged with the
+	// existing configuration.
+	XMLOperationMerge XMLOperation = "merge"
+	// XMLOperationReplace specifies that the subtree replaces the existing
+	// configuration.
+	XMLOperationReplace XMLOperation = "replace"
+	// XMLOperationCreate specifies that the subtree is created, and must
+	// not already exist.
+	XMLOperationCreate XMLOperation = "create"
+	// XMLOperationDelete specifies that the subtree is deleted, and must
+	// already exist.
+	XMLOperationDelete XMLOperation = "delete"
+	// XMLOperationRemove specifies that the subtree is deleted if it
+	// exists.
+	XMLOperationRemove XMLOperation = "remove"
+)
+
+// XMLConfig specifies how XML should be created by the EmitXML function.
+type XMLConfig struct {
+	// Namespaces maps the name of each YANG module that the emitted data is
+	// defined in to its XML namespace. It must contain an entry for each
+	// module that is referenced by the "module" struct tags of the GoStruct
+	// being emitted.
+	Namespaces map[string]string
+	// Operations maps the data tree path of a node, in the string format
+	// used by PathToString (e.g., /interfaces/interface[name=eth0]/config),
+	// to the NETCONF operation that should be specified for the subtree
+	// rooted at the node. Operations are only emitted for nodes that are
+	// present in the GoStruct, such that a node that is to be deleted must
+	// be populated, e.g., with only its list keys.
+	Operations map[string]XMLOperation
+	// EnclosingConfig specifies whether the emitted XML is enclosed in a
+	// NETCONF <config> element, such that it can be used directly as the
+	// payload of an <edit-config> RPC.
+	EnclosingConfig bool
+	// Indent is the string used for indentation within the XML output. The
+	// default value is three spaces.
+	Indent string
+	// SkipValidation specifies whether the GoStruct supplied to EmitXML should
+	// be validated before emitting its content. Validation is skipped when it
+	// is set to true.
+	SkipValidation bool
+	// ValidationOpts is the set of options that should be used to determine how
+	// the schema should be validated.
+	ValidationOpts []ValidationOption
+}
+
+// xmlElement is an element of the XML document that is emitted by EmitXML.
+type xmlElement struct {
+	// name is the name of the element.
+	name string
+	// module is the name of the YANG module that the element is defined in.
+	module string
+	// operation is the NETCONF operation that is specified for the element.
+	operation XMLOperation
+	// prefixes is the set of module names whose namespaces must be declared
+	// using the module name as the prefix, as they are used within the
+	// element's value.
+	prefixes []string
+	// children is the set of child elements of the element.
+	children []*xmlElement
+	// value is the text value of the element, for leaves and leaf-lists.
+	value *string
+}
+
+// child returns the child element of e with the supplied name and module,
+// creating it if it does not exist.
+func (e *xmlElement) child(name, module string) *xmlElement {
+	for _, c := range e.children {
+		if c.name == name && c.module == module && c.value == nil {
+			return c
+		}
+	}
+	c := &xmlElement{name: name, module: module}
+	e.children = append(e.children, c)
+	return c
+}
+
+// EmitXML takes an input GoStruct (produced by ygen with validation enabled)
+// and serialises it to a namespace-qualified XML string, per the XML encoding
+// of YANG data described in RFC7950. The GoStruct must have been generated
+// with the module names of each field included, such that the namespace of
+// each element can be determined.
+func EmitXML(gs GoStruct, cfg *XMLConfig) (string, error) {
+	if cfg == nil {
+		cfg = &XMLConfig{}
+	}
+
+	if !cfg.SkipValidation {
+		if err := ValidateGoStruct(gs, cfg.ValidationOpts...); err != nil {
+			return "", fmt.Errorf("validation err: %v", err)
+		}
+	}
+
+	root := &xmlElement{}
+	if err := structXML(root, gs, "", []*gnmipb.PathElem{}, cfg); err != nil {
+		return "", err
+	}
+
+	indent := indentString
+	if cfg.Indent != "" {
+		indent = cfg.Indent
+	}
+
+	var sb strings.Builder
+	depth := 0
+	if cfg.EnclosingConfig {
+		sb.WriteString("<config")
+		if err := writeXMLAttr(&sb, "xmlns", NETCONFBaseNamespace); err != nil {
+			return "", err
+		}
+		sb.WriteString(">")
+		depth = 1
+	}
+	for _, c := range root.children {
+		if err := writeXMLElement(&sb, c, "", depth, indent, cfg); err != nil {
+			return "", err
+		}
+	}
+	if cfg.EnclosingConfig {
+		sb.WriteString("\n</config>")
+	}
+	return strings.TrimPrefix(sb.String(), "\n"), nil
+}
+
+// structXML appends the XML elements corresponding to the fields of the
+// supplied GoStruct to parent. parentMod is the module that the GoStruct is
+// defined within, and path is its data tree path.
+func structXML(parent *xmlElement, s GoStruct, parentMod string, path []*gnmipb.PathElem, cfg *XMLConfig) error {
+	var errs errlist.List
+
+	sval := reflect.ValueOf(s).Elem()
+	stype := sval.Type()
+	for i := 0; i < sval.NumField(); i++ {
+		field := sval.Field(i)
+		fType := stype.Field(i)
+
+		if util.IsYgotAnnotation(fType) || util.IsNilOrInvalidValue(field) {
+			continue
+		}
+
+		schPaths, err := util.SchemaPaths(fType)
+		if err != nil {
+			errs.Add(fmt.Errorf("%s: %v", fType.Name, err))
+			continue
+		}
+		modPaths, err := structTagToLibModules(fType, false)
+		if err != nil {
+			errs.Add(err)
+			continue
+		}
+		if modPaths != nil && len(modPaths) != len(schPaths) {
+			errs.Add(fmt.Errorf("%s: number of paths and modules in struct tag not the same: (paths: %v, modules: %v)", fType.Name, len(schPaths), len(modPaths)))
+			continue
+		}
+
+	paths:
+		for pi, sp := range schPaths {
+			if len(sp) == 0 {
+				// Fields with an empty path are not supported.
+				errs.Add(fmt.Errorf("%s: empty path specified for field", fType.Name))
+				continue
+			}
+
+			// Find the parent element of the field, creating any
+			// intermediate elements along the way. The field is not
+			// emitted if the module of any element is unknown, since
+			// it would otherwise have the wrong namespace.
+			mods := make([]string, len(sp))
+			for j := range sp {
+				mods[j] = parentMod
+				if modPaths != nil {
+					m, err := modPaths[pi].StringElemAt(j)
+					if err != nil {
+						errs.Add(fmt.Errorf("%s: %v", fType.Name, err))
+						continue paths
+					}
+					mods[j] = m
+				}
+			}
+			fieldParent := parent
+			fieldPath := append([]*gnmipb.PathElem{}, path...)
+			for j := 0; j < len(sp)-1; j++ {
+				fieldParent = fieldParent.child(sp[j], mods[j])
+				fieldPath = append(fieldPath, &gnmipb.PathElem{Name: sp[j]})
+				setXMLOperation(fieldParent, fieldPath, cfg)
+			}
+			name, mod := sp[len(sp)-1], mods[len(sp)-1]
+			fieldPath = append(fieldPath, &gnmipb.PathElem{Name: name})
+			errs.Add(fieldXML(fieldParent, field, name, mod, fieldPath, cfg))
+		}
+	}
+	return listErr(errs)
+}
+
+// fieldXML appends the XML elements that correspond to the supplied field of
+// a GoStruct to parent, using name and module as the element name and module
+// of the field. path is the data tree path of the field.
+func fieldXML(parent *xmlElement, field reflect.Value, name, module string, path []*gnmipb.PathElem, cfg *XMLConfig) error {
+	var errs errlist.List
+
+	// listEntry appends the XML element for a single entry of a keyed or
+	// unkeyed list to parent.
+	listEntry := func(v reflect.Value) {
+		gs, ok := v.Interface().(GoStruct)
+		if !ok {
+			errs.Add(fmt.Errorf("%s: list entry %v is not a valid GoStruct", name, v.Type()))
+			return
+		}
+		e := &xmlElement{name: name, module: module}
+		entryPath := append([]*gnmipb.PathElem{}, path...)
+		var keyNames []string
+		if kh, ok := gs.(KeyHelperGoStruct); ok {
+			keys, err := kh.ΛListKeyMap()
+			if err != nil {
+				errs.Add(err)
+				return
+			}
+			strKeys, err := keyMapAsStrings(keys)
+			if err != nil {
+				errs.Add(err)
+				return
+			}
+			entryPath[len(entryPath)-1] = &gnmipb.PathElem{Name: name, Key: strKeys}
+			for k := range strKeys {
+				keyNames = append(keyNames, k)
+			}
+			sort.Strings(keyNames)
+		}
+		setXMLOperation(e, entryPath, cfg)
+		if err := structXML(e, gs, module, entryPath, cfg); err != nil {
+			errs.Add(err)
+			return
+		}
+		// List keys must be the first children of a list entry.
+		sort.SliceStable(e.children, func(i, j int) bool {
+			return xmlKeyIndex(keyNames, e.children[i].name) < xmlKeyIndex(keyNames, e.children[j].name)
+		})
+		parent.children = append(parent.children, e)
+	}
+
+	if om, ok := field.Interface().(GoOrderedMap); ok {
+		if err := yreflect.RangeOrderedMap(om, func(_ reflect.Value, v reflect.Value) bool {
+			listEntry(v)
+			return true
+		}); err != nil {
+			errs.Add(err)
+		}
+		return listErr(errs)
+	}
+
+	switch {
+	case util.IsValueMap(field):
+		keys := field.MapKeys()
+		var keyStrs []string
+		byKey := map[string]reflect.Value{}
+		for _, k := range keys {
+			ks := fmt.Sprintf("%v", k.Interface())
+			keyStrs = append(keyStrs, ks)
+			byKey[ks] = field.MapIndex(k)
+		}
+		sort.Strings(keyStrs)
+		for _, ks := range keyStrs {
+			listEntry(byKey[ks])
+		}
+	case util.IsValueStructPtr(field):
+		gs, ok := field.Interface().(GoStruct)
+		if !ok {
+			return fmt.Errorf("%s: cannot map struct %T, invalid GoStruct", name, field.Interface())
+		}
+		e := parent.child(name, module)
+		setXMLOperation(e, path, cfg)
+		errs.Add(structXML(e, gs, module, path, cfg))
+	case field.Kind() == reflect.Slice && util.IsTypeStructPtr(field.Type().Elem()):
+		for i := 0; i < field.Len(); i++ {
+			listEntry(field.Index(i))
+		}
+	default:
+		v, err := jsonValue(field, module, jsonOutputConfig{
+			jType:         RFC7951,
+			rfc7951Config: &RFC7951JSONConfig{AppendModuleName: true},
+		})
+		if err != nil {
+			return err
+		}
+		vals := []any{v}
+		switch vv := v.(type) {
+		case nil:
+			return nil
+		case []any:
+			// Empty leaves are represented as [null] in RFC7951 JSON, and
+			// otherwise the value is a leaf-list.
+			if !(len(vv) == 1 && vv[0] == nil) {
+				vals = vv
+			}
+		}
+		for _, val := range vals {
+			e := &xmlElement{name: name, module: module}
+			setXMLOperation(e, path, cfg)
+			s := ""
+			if _, isEmpty := val.([]any); !isEmpty {
+				s = fmt.Sprintf("%v", val)
+			}
+			e.value = &s
+			if isXMLEnumValue(field) {
+				if pfx, _, ok := strings.Cut(s, ":"); ok {
+					e.prefixes = []string{pfx}
+				}
+			}
+			parent.children = append(parent.children, e)
+		}
+	}
+	return listErr(errs)
+}
+
+// isXMLEnumValue returns true if the supplied field may contain an enumerated
+// value, which may be rendered with a module prefix in the case that it is an
+// identityref.
+func isXMLEnumValue(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.Int64, reflect.Interface:
+		return true
+	case reflect.Slice:
+		k := field.Type().Elem().Kind()
+		return k == reflect.Int64 || k == reflect.Interface
+	}
+	return false
+}
+
+// xmlKeyIndex returns the index of name within keys, or len(keys) if name is
+// not a key.
+func xmlKeyIndex(keys []string, name string) int {
+	for i, k := range keys {
+		if k == name {
+			return i
+		}
+	}
+	return len(keys)
+}
+
+// setXMLOperation sets the operation of the supplied element to the operation
+// specified in cfg for its data tree path, if any.
+func setXMLOperation(e *xmlElement, path []*gnmipb.PathElem, cfg *XMLConfig) {
+	if len(cfg.Operations) == 0 {
+		return
+	}
+	p, err := PathToString(&gnmipb.Path{Elem: path})
+	if err != nil {
+		return
+	}
+	if op, ok := cfg.Operations[p]; ok {
+		e.operation = op
+	}
+}
+
+// writeXMLElement writes the XML for the element e to sb. parentMod is the
+// module of the parent of e, which is used to determine whether the
+// namespace of e must be declared.
+func writeXMLElement(sb *strings.Builder, e *xmlElement, parentMod string, depth int, indent string, cfg *XMLConfig) error {
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat(indent, depth))
+	sb.WriteString("<")
+	sb.WriteString(e.name)
+
+	if e.module != parentMod {
+		ns, ok := cfg.Namespaces[e.module]
+		if !ok {
+			return fmt.Errorf("no XML namespace specified for module %q of element %s", e.module, e.name)
+		}
+		if err := writeXMLAttr(sb, "xmlns", ns); err != nil {
+			return err
+		}
+	}
+	for _, pfx := range e.prefixes {
+		// Only module names are used as prefixes of values, such that
+		// prefixes that are not known modules are not declared.
+		if ns, ok := cfg.Namespaces[pfx]; ok {
+			if err := writeXMLAttr(sb, "xmlns:"+pfx, ns); err != nil {
+				return err
+			}
+		}
+	}
+	if e.operation != "" {
+		if err := writeXMLAttr(sb, "xmlns:nc", NETCONFBaseNamespace); err != nil {
+			return err
+		}
+		if err := writeXMLAttr(sb, "nc:operation", string(e.operation)); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case e.value != nil && *e.value == "":
+		sb.WriteString("/>")
+	case e.value != nil:
+		sb.WriteString(">")
+		if err := xml.EscapeText(sb, []byte(*e.value)); err != nil {
+			return err
+		}
+		fmt.Fprintf(sb, "</%s>", e.name)
+	case len(e.children) == 0:
+		sb.WriteString("/>")
+	default:
+		sb.WriteString(">")
+		for _, c := range e.children {
+			if err := writeXMLElement(sb, c, e.module, depth+1, indent, cfg); err != nil {
+				return err
+			}
+		}
+		sb.WriteString("\n")
+		sb.WriteString(strings.Repeat(indent, depth))
+		fmt.Fprintf(sb, "</%s>", e.name)
+	}
+	return nil
+}
+
+// writeXMLAttr writes an attribute with the supplied name and value, which is
+// escaped such that it may contain any character, to sb.
+func writeXMLAttr(sb *strings.Builder, name, value string) error {
+	sb.WriteString(" ")
+	sb.WriteString(name)
+	sb.WriteString(`="`)
+	if err := xml.EscapeText(sb, []byte(value)); err != nil {
+		return err
+	}
+	sb.WriteString(`"`)
+	return nil
+}
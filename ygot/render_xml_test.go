@@ -0,0 +1,227 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+type xmlRoot struct {
+	Interface map[string]*xmlInterface `path:"interfaces/interface" module:"if/if"`
+	Hostname  *string                  `path:"system/config/hostname" module:"sys/sys/sys"`
+}
+
+func (*xmlRoot) IsYANGGoStruct() {}
+
+type xmlInterface struct {
+	Name        *string   `path:"config/name|name" module:"if/if|if"`
+	Description *string   `path:"config/description" module:"if/if"`
+	Mtu         *uint16   `path:"config/mtu" module:"if/if"`
+	Enabled     *bool     `path:"config/enabled" module:"if/if"`
+	Speed       *float64  `path:"config/speed" module:"if/if"`
+	Tag         []string  `path:"config/tag" module:"if/if"`
+	Loopback    YANGEmpty `path:"config/loopback" module:"if/ext"`
+}
+
+func (*xmlInterface) IsYANGGoStruct() {}
+
+func (i *xmlInterface) ΛListKeyMap() (map[string]interface{}, error) {
+	return map[string]interface{}{"name": *i.Name}, nil
+}
+
+type xmlBadModules struct {
+	Hostname *string `path:"system/config/hostname" module:"sys/sys"`
+	Location *string `path:"system/config/location" module:"sys/sys/sys"`
+}
+
+func (*xmlBadModules) IsYANGGoStruct() {}
+
+func TestEmitXML(t *testing.T) {
+	namespaces := map[string]string{
+		"if":  "urn:test:if",
+		"sys": "urn:test:sys",
+		"ext": "urn:test:ext",
+	}
+
+	tests := []struct {
+		desc             string
+		in               GoStruct
+		inConfig         *XMLConfig
+		want             string
+		wantErrSubstring string
+	}{{
+		desc: "list with scalar types",
+		in: &xmlRoot{
+			Interface: map[string]*xmlInterface{
+				"eth1": {Name: String("eth1")},
+				"eth0": {
+					Name:        String("eth0"),
+					Description: String("a < b & c"),
+					Mtu:         Uint16(1500),
+					Enabled:     Bool(true),
+					Tag:         []string{"x", "y"},
+					Loopback:    true,
+				},
+			},
+			Hostname: String("router"),
+		},
+		inConfig: &XMLConfig{Namespaces: namespaces, SkipValidation: true},
+		want: `<interfaces xmlns="urn:test:if">
+   <interface>
+      <name>eth0</name>
+      <config>
+         <name>eth0</name>
+         <description>a &lt; b &amp; c</description>
+         <mtu>1500</mtu>
+         <enabled>true</enabled>
+         <tag>x</tag>
+         <tag>y</tag>
+         <loopback xmlns="urn:test:ext"/>
+      </config>
+   </interface>
+   <interface>
+      <name>eth1</name>
+      <config>
+         <name>eth1</name>
+      </config>
+   </interface>
+</interfaces>
+<system xmlns="urn:test:sys">
+   <config>
+      <hostname>router</hostname>
+   </config>
+</system>`,
+	}, {
+		desc: "operations within config element",
+		in: &xmlRoot{
+			Interface: map[string]*xmlInterface{
+				"eth0": {Name: String("eth0"), Mtu: Uint16(9000)},
+				"eth1": {Name: String("eth1")},
+			},
+		},
+		inConfig: &XMLConfig{
+			Namespaces: namespaces,
+			Operations: map[string]XMLOperation{
+				"/interfaces/interface[name=eth0]/config": XMLOperationReplace,
+				"/interfaces/interface[name=eth1]":        XMLOperationDelete,
+			},
+			EnclosingConfig: true,
+			Indent:          " ",
+			SkipValidation:  true,
+		},
+		want: `<config xmlns="urn:ietf:params:xml:ns:netconf:base:1.0">
+ <interfaces xmlns="urn:test:if">
+  <interface>
+   <name>eth0</name>
+   <config xmlns:nc="urn:ietf:params:xml:ns:netconf:base:1.0" nc:operation="replace">
+    <name>eth0</name>
+    <mtu>9000</mtu>
+   </config>
+  </interface>
+  <interface xmlns:nc="urn:ietf:params:xml:ns:netconf:base:1.0" nc:operation="delete">
+   <name>eth1</name>
+   <config>
+    <name>eth1</name>
+   </config>
+  </interface>
+ </interfaces>
+</config>`,
+	}, {
+		desc: "namespace requiring escaping",
+		in:   &xmlRoot{Hostname: String("router")},
+		inConfig: &XMLConfig{
+			Namespaces:     map[string]string{"sys": `urn:test:sys?a=1&b=<2>/é"q"`},
+			SkipValidation: true,
+		},
+		want: `<system xmlns="urn:test:sys?a=1&amp;b=&lt;2&gt;/é&#34;q&#34;">
+   <config>
+      <hostname>router</hostname>
+   </config>
+</system>`,
+	}, {
+		desc: "decimal values without exponent",
+		in: &xmlRoot{
+			Interface: map[string]*xmlInterface{
+				"eth0": {Name: String("eth0"), Speed: Float64(0.00001)},
+				"eth1": {Name: String("eth1"), Speed: Float64(1e21)},
+			},
+		},
+		inConfig: &XMLConfig{Namespaces: namespaces, Indent: " ", SkipValidation: true},
+		want: `<interfaces xmlns="urn:test:if">
+ <interface>
+  <name>eth0</name>
+  <config>
+   <name>eth0</name>
+   <speed>0.00001</speed>
+  </config>
+ </interface>
+ <interface>
+  <name>eth1</name>
+  <config>
+   <name>eth1</name>
+   <speed>1000000000000000000000</speed>
+  </config>
+ </interface>
+</interfaces>`,
+	}, {
+		desc:             "missing namespace",
+		in:               &xmlRoot{Hostname: String("router")},
+		inConfig:         &XMLConfig{Namespaces: map[string]string{"if": "urn:test:if"}, SkipValidation: true},
+		wantErrSubstring: `no XML namespace specified for module "sys"`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := EmitXML(tt.in, tt.inConfig)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("EmitXML: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("EmitXML: did not get expected XML, (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStructXMLInvalidModules(t *testing.T) {
+	root := &xmlElement{}
+	err := structXML(root, &xmlBadModules{Hostname: String("router"), Location: String("lab")}, "", nil, &XMLConfig{})
+	if diff := errdiff.Substring(err, "Hostname:"); diff != "" {
+		t.Fatalf("structXML: %s", diff)
+	}
+
+	// Only the field with valid modules is emitted.
+	var got []string
+	var walk func(e *xmlElement, path string)
+	walk = func(e *xmlElement, path string) {
+		for _, c := range e.children {
+			p := path + "/" + c.name + "@" + c.module
+			if len(c.children) == 0 {
+				got = append(got, p)
+			}
+			walk(c, p)
+		}
+	}
+	walk(root, "")
+	if diff := cmp.Diff([]string{"/system@sys/config@sys/location@sys"}, got); diff != "" {
+		t.Errorf("structXML: did not get expected elements, (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/openconfig/ygot/util"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// RoundTripUnmarshalFunc is a function that unmarshals the JSON produced by
+// EmitJSON into the supplied GoStruct. The Unmarshal function of a generated
+// package can be used by wrapping it in a function that supplies the
+// required unmarshal options, e.g.:
+//
+//	func(b []byte, s ygot.GoStruct) error { return oc.Unmarshal(b, s) }
+type RoundTripUnmarshalFunc func(json []byte, dst GoStruct) error
+
+// RoundTripChange describes the manner in which the value of a leaf was not
+// preserved by a round trip.
+type RoundTripChange int64
+
+const (
+	// RoundTripLost indicates that a leaf that was populated in the
+	// original GoStruct was not populated after the round trip.
+	RoundTripLost RoundTripChange = iota
+	// RoundTripAdded indicates that a leaf that was not populated in the
+	// original GoStruct was populated after the round trip.
+	RoundTripAdded
+	// RoundTripTypeChanged indicates that the value of a leaf has a
+	// different Go type after the round trip, as is the case when the
+	// value of a union is resolved to a different member type.
+	RoundTripTypeChanged
+	// RoundTripValueChanged indicates that the value of a leaf has the
+	// same Go type, but a different value, after the round trip.
+	RoundTripValueChanged
+)
+
+// String returns a human-readable name for the RoundTripChange.
+func (c RoundTripChange) String() string {
+	switch c {
+	case RoundTripLost:
+		return "lost"
+	case RoundTripAdded:
+		return "added"
+	case RoundTripTypeChanged:
+		return "type changed"
+	case RoundTripValueChanged:
+		return "value changed"
+	default:
+		return fmt.Sprintf("RoundTripChange(%d)", int64(c))
+	}
+}
+
+// RoundTripDegradation describes a leaf, leaf-list or annotation whose value
+// was not preserved when a GoStruct was marshalled to JSON and unmarshalled.
+type RoundTripDegradation struct {
+	// Path is the path of the leaf relative to the GoStruct that was
+	// checked.
+	Path *gnmipb.Path
+	// Change describes how the value of the leaf was changed.
+	Change RoundTripChange
+	// Original is the value of the leaf in the original GoStruct, which
+	// is nil if it was not populated. The values of leaves that are
+	// represented by pointers to scalar types are dereferenced.
+	Original any
+	// RoundTripped is the value of the leaf after the round trip, which is
+	// nil if it was not populated.
+	RoundTripped any
+}
+
+// String returns a human-readable description of the RoundTripDegradation.
+func (d *RoundTripDegradation) String() string {
+	p, err := PathToString(d.Path)
+	if err != nil {
+		p = d.Path.String()
+	}
+	switch d.Change {
+	case RoundTripLost:
+		return fmt.Sprintf("%s: %s, was %T(%v)", p, d.Change, d.Original, d.Original)
+	case RoundTripAdded:
+		return fmt.Sprintf("%s: %s, now %T(%v)", p, d.Change, d.RoundTripped, d.RoundTripped)
+	default:
+		return fmt.Sprintf("%s: %s from %T(%v) to %T(%v)", p, d.Change, d.Original, d.Original, d.RoundTripped, d.RoundTripped)
+	}
+}
+
+// CheckRoundTrip checks whether the GoStruct s is preserved losslessly when it
+// is marshalled to JSON using EmitJSON, with the supplied configuration, and
+// the JSON is unmarshalled into a new GoStruct of the same type using the
+// unmarshal function. If cfg is nil, RFC7951 JSON is emitted.
+//
+// Each leaf, leaf-list and annotation whose value differs after the round
+// trip is returned as a RoundTripDegradation, sorted by path. A GoStruct
+// that round trips losslessly results in no degradations. Values are
+// compared including their Go types, such that a union whose value is
+// resolved to a different member type when it is unmarshalled is reported,
+// even if the value is marshalled identically. An error is returned if the
+// GoStruct cannot be marshalled or the JSON cannot be unmarshalled.
+func CheckRoundTrip(s GoStruct, unmarshal RoundTripUnmarshalFunc, cfg *EmitJSONConfig) ([]*RoundTripDegradation, error) {
+	if util.IsValueNil(s) {
+		return nil, fmt.Errorf("cannot check the round trip of a nil GoStruct")
+	}
+	if unmarshal == nil {
+		return nil, fmt.Errorf("no unmarshal function supplied")
+	}
+	if cfg == nil {
+		cfg = &EmitJSONConfig{Format: RFC7951}
+	}
+
+	js, err := EmitJSON(s, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal GoStruct: %v", err)
+	}
+	rt, ok := reflect.New(reflect.TypeOf(s).Elem()).Interface().(GoStruct)
+	if !ok {
+		return nil, fmt.Errorf("cannot create a new GoStruct of type %T", s)
+	}
+	if err := unmarshal([]byte(js), rt); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal JSON: %v", err)
+	}
+
+	want, err := roundTripLeaves(s)
+	if err != nil {
+		return nil, err
+	}
+	got, err := roundTripLeaves(rt)
+	if err != nil {
+		return nil, err
+	}
+
+	var degraded []*RoundTripDegradation
+	for p, w := range want {
+		g, ok := got[p]
+		switch {
+		case !ok:
+			degraded = append(degraded, &RoundTripDegradation{Path: w.path, Change: RoundTripLost, Original: w.val})
+		case reflect.TypeOf(w.val) != reflect.TypeOf(g.val):
+			degraded = append(degraded, &RoundTripDegradation{Path: w.path, Change: RoundTripTypeChanged, Original: w.val, RoundTripped: g.val})
+		case !reflect.DeepEqual(w.val, g.val):
+			degraded = append(degraded, &RoundTripDegradation{Path: w.path, Change: RoundTripValueChanged, Original: w.val, RoundTripped: g.val})
+		}
+	}
+	for p, g := range got {
+		if _, ok := want[p]; !ok {
+			degraded = append(degraded, &RoundTripDegradation{Path: g.path, Change: RoundTripAdded, RoundTripped: g.val})
+		}
+	}
+
+	sort.Slice(degraded, func(i, j int) bool {
+		// The paths were successfully converted to strings by
+		// roundTripLeaves.
+		pi, _ := PathToString(degraded[i].Path)
+		pj, _ := PathToString(degraded[j].Path)
+		return pi < pj
+	})
+	return degraded, nil
+}
+
+// roundTripLeaf is a leaf visited by roundTripLeaves.
+type roundTripLeaf struct {
+	path *gnmipb.Path
+	val  any
+}
+
+// roundTripLeaves returns the populated leaves, leaf-lists and annotations of
+// s, keyed by the string representation of their paths.
+func roundTripLeaves(s GoStruct) (map[string]*roundTripLeaf, error) {
+	leaves := map[string]*roundTripLeaf{}
+	err := Walk(s, func(path *gnmipb.Path, node any) error {
+		switch n := node.(type) {
+		case GoStruct, GoOrderedMap:
+			return nil
+		default:
+			v := reflect.ValueOf(n)
+			if util.IsValueMap(v) || (util.IsValueSlice(v) && util.IsTypeStructPtr(v.Type().Elem())) {
+				return nil
+			}
+		}
+		p, err := PathToString(path)
+		if err != nil {
+			return err
+		}
+		// Leaves are represented by pointers to their values, which
+		// are dereferenced such that they can be meaningfully
+		// reported.
+		if v := reflect.ValueOf(node); v.Kind() == reflect.Ptr && !util.IsValueStructPtr(v) {
+			node = v.Elem().Interface()
+		}
+		leaves[p] = &roundTripLeaf{path: path, val: node}
+		return nil
+	}, &WalkAnnotations{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot walk GoStruct: %v", err)
+	}
+	return leaves, nil
+}
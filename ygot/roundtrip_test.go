@@ -0,0 +1,117 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygot/testutil"
+)
+
+// unmarshalRenderExample is a RoundTripUnmarshalFunc for renderExample that
+// handles the subset of its fields used in TestCheckRoundTrip. Unions are
+// always resolved to strings, and binary values are dropped.
+func unmarshalRenderExample(b []byte, dst GoStruct) error {
+	var in struct {
+		Str      *string  `json:"str"`
+		IntVal   *int32   `json:"int-val"`
+		LeafList []string `json:"leaf-list"`
+		UnionVal any      `json:"union-val"`
+	}
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	r := dst.(*renderExample)
+	r.Str, r.IntVal, r.LeafList = in.Str, in.IntVal, in.LeafList
+	if in.UnionVal != nil {
+		r.UnionVal = &renderExampleUnionString{fmt.Sprintf("%v", in.UnionVal)}
+	}
+	return nil
+}
+
+func TestCheckRoundTrip(t *testing.T) {
+	tests := []struct {
+		desc             string
+		in               GoStruct
+		inUnmarshal      RoundTripUnmarshalFunc
+		want             []string
+		wantErrSubstring string
+	}{{
+		desc: "lossless",
+		in: &renderExample{
+			Str:      String("hello"),
+			IntVal:   Int32(42),
+			LeafList: []string{"a", "b"},
+			UnionVal: &renderExampleUnionString{"world"},
+		},
+		inUnmarshal: unmarshalRenderExample,
+	}, {
+		desc: "degraded union and binary",
+		in: &renderExample{
+			Str:      String("hello"),
+			UnionVal: &renderExampleUnionInt64{42},
+			Binary:   Binary{42},
+		},
+		inUnmarshal: unmarshalRenderExample,
+		want: []string{
+			"/binary: lost, was ygot.Binary([42])",
+			"/union-val: type changed from *ygot.renderExampleUnionInt64(&{42}) to *ygot.renderExampleUnionString(&{42})",
+		},
+	}, {
+		desc: "changed and added values",
+		in:   &renderExample{Str: String("hello")},
+		inUnmarshal: func(b []byte, dst GoStruct) error {
+			r := dst.(*renderExample)
+			r.Str = String("world")
+			r.UnionValSimple = testutil.UnionString("new")
+			return nil
+		},
+		want: []string{
+			"/str: value changed from string(hello) to string(world)",
+			"/union-val-simple: added, now testutil.UnionString(new)",
+		},
+	}, {
+		desc: "unmarshal error",
+		in:   &renderExample{Str: String("hello")},
+		inUnmarshal: func([]byte, GoStruct) error {
+			return fmt.Errorf("bad json")
+		},
+		wantErrSubstring: "cannot unmarshal JSON: bad json",
+	}, {
+		desc:             "nil unmarshal function",
+		in:               &renderExample{},
+		wantErrSubstring: "no unmarshal function supplied",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := CheckRoundTrip(tt.in, tt.inUnmarshal, nil)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("CheckRoundTrip: %s", diff)
+			}
+			var gotStr []string
+			for _, d := range got {
+				gotStr = append(gotStr, d.String())
+			}
+			if diff := cmp.Diff(tt.want, gotStr); diff != "" {
+				t.Errorf("CheckRoundTrip: did not get expected degradations (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
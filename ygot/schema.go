@@ -21,6 +21,7 @@ import (
 	"io"
 
 	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/util"
 )
 
 // GzipToSchema takes an input byte slice, and returns it as
@@ -63,4 +64,7 @@ func rebuildSchemaMap(e, parent *yang.Entry, schema map[string]*yang.Entry) {
 	for _, ch := range e.Dir {
 		rebuildSchemaMap(ch, e, schema)
 	}
+	for _, io := range util.RPCInputOutput(e) {
+		rebuildSchemaMap(io, e, schema)
+	}
 }
@@ -0,0 +1,192 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/openconfig/ygot/util"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+const (
+	// secretPlaceholderPrefix is the prefix of a placeholder that is used in
+	// place of the value of a secret leaf.
+	secretPlaceholderPrefix = "__SECRET:"
+	// secretPlaceholderSuffix is the suffix of a placeholder that is used in
+	// place of the value of a secret leaf.
+	secretPlaceholderSuffix = "__"
+)
+
+// SecretStore is implemented by external stores of secret values, such that
+// secret leaves within a GoStruct can be replaced by stable placeholders when
+// the GoStruct is rendered, and the placeholders substituted with the secret
+// values when the rendered data is read back.
+type SecretStore interface {
+	// SecretName returns the name of the secret that is stored at the leaf
+	// with the supplied data tree path, and true if the leaf is a secret.
+	// If the leaf is not a secret, it returns false.
+	SecretName(path *gnmipb.Path) (string, bool)
+	// SecretValue returns the value of the secret with the supplied name.
+	SecretValue(name string) (string, error)
+}
+
+// SecretPlaceholder returns the placeholder that is used in place of the value
+// of the secret with the supplied name, e.g., "__SECRET:bgp-password__".
+func SecretPlaceholder(name string) string {
+	return secretPlaceholderPrefix + name + secretPlaceholderSuffix
+}
+
+// SecretNameFromPlaceholder returns the name of the secret that the supplied
+// placeholder refers to, and true if s is a placeholder. If s is not a
+// placeholder, it returns false.
+func SecretNameFromPlaceholder(s string) (string, bool) {
+	if !strings.HasPrefix(s, secretPlaceholderPrefix) || !strings.HasSuffix(s, secretPlaceholderSuffix) || len(s) <= len(secretPlaceholderPrefix)+len(secretPlaceholderSuffix) {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(s, secretPlaceholderPrefix), secretPlaceholderSuffix), true
+}
+
+// SecretPlaceholders is a DiffOpt that specifies that the values of leaves
+// that are secrets in Store are replaced by their placeholders in the updates
+// returned by Diff.
+type SecretPlaceholders struct {
+	// Store is the store that determines which leaves are secrets.
+	Store SecretStore
+}
+
+// IsDiffOpt marks SecretPlaceholders as a diff option.
+func (*SecretPlaceholders) IsDiffOpt() {}
+
+// hasSecretPlaceholders returns the first SecretPlaceholders from an opts
+// slice, or nil if there isn't one.
+func hasSecretPlaceholders(opts []DiffOpt) *SecretPlaceholders {
+	for _, o := range opts {
+		switch v := o.(type) {
+		case *SecretPlaceholders:
+			return v
+		}
+	}
+	return nil
+}
+
+// secretName returns the name of the secret that is stored at any of the
+// supplied paths, and true if one of the paths is a secret leaf in store.
+func secretName(store SecretStore, paths []*gnmipb.Path) (string, bool) {
+	for _, p := range paths {
+		if name, ok := store.SecretName(p); ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// ReplaceSecretsWithPlaceholders replaces the value of each set leaf within the
+// supplied GoStruct that is a secret in store with the placeholder for the
+// secret. The GoStruct is modified in place. Secret leaves must be of string
+// type, and an error is returned if a secret leaf of any other type is set.
+func ReplaceSecretsWithPlaceholders(s GoStruct, store SecretStore) error {
+	return forEachSetScalarLeaf(s, func(paths []*gnmipb.Path, v reflect.Value) error {
+		name, ok := secretName(store, paths)
+		if !ok {
+			return nil
+		}
+		if v.Type() != reflect.TypeOf((*string)(nil)) {
+			return fmt.Errorf("%v: secret leaf has non-string type %v", paths[0], v.Type())
+		}
+		v.Set(reflect.ValueOf(String(SecretPlaceholder(name))))
+		return nil
+	})
+}
+
+// ResolveSecretPlaceholders replaces each string leaf within the supplied
+// GoStruct whose value is a placeholder with the value of the corresponding
+// secret in store. The GoStruct is modified in place. A placeholder is only
+// resolved if the leaf is a secret in store with the name that the
+// placeholder refers to, such that placeholders cannot be used to read the
+// value of a secret from a leaf that is not that secret; an error is returned
+// for any other placeholder.
+func ResolveSecretPlaceholders(s GoStruct, store SecretStore) error {
+	return forEachSetScalarLeaf(s, func(paths []*gnmipb.Path, v reflect.Value) error {
+		sv, ok := v.Interface().(*string)
+		if !ok {
+			return nil
+		}
+		name, ok := SecretNameFromPlaceholder(*sv)
+		if !ok {
+			return nil
+		}
+		if secret, ok := secretName(store, paths); !ok || secret != name {
+			return fmt.Errorf("%v: placeholder for secret %s is not allowed in leaf", paths[0], name)
+		}
+		val, err := store.SecretValue(name)
+		if err != nil {
+			return fmt.Errorf("%v: cannot resolve secret %s: %v", paths[0], name, err)
+		}
+		v.Set(reflect.ValueOf(String(val)))
+		return nil
+	})
+}
+
+// forEachSetScalarLeaf calls fn for each set leaf of pointer type within the
+// supplied GoStruct with the data tree paths of the leaf and its settable value.
+func forEachSetScalarLeaf(s GoStruct, fn func([]*gnmipb.Path, reflect.Value) error) error {
+	return forEachSetLeaf(s, func(paths []*gnmipb.Path, v reflect.Value) error {
+		if !util.IsValuePtr(v) {
+			return nil
+		}
+		return fn(paths, v)
+	})
+}
+
+// forEachSetLeaf calls fn for each set leaf or leaf-list within the supplied
+// GoStruct with the data tree paths of the field and its settable value. Since
+// the data tree is traversed once for each path that a field is mapped to, fn
+// may be called more than once for the same field.
+func forEachSetLeaf(s GoStruct, fn func([]*gnmipb.Path, reflect.Value) error) error {
+	iterFunc := func(ni *util.NodeInfo, in, out interface{}) (util.IterationAction, util.Errors) {
+		if reflect.DeepEqual(ni.StructField, reflect.StructField{}) || util.IsYgotAnnotation(ni.StructField) {
+			return util.ContinueIteration, nil
+		}
+		sp, err := util.SchemaPaths(ni.StructField)
+		if err != nil {
+			return util.ContinueIteration, util.NewErrs(err)
+		}
+		vp, err := nodeValuePath(ni, sp)
+		if err != nil {
+			return util.ContinueIteration, util.NewErrs(err)
+		}
+		ni.Annotation = []interface{}{vp}
+
+		if !ni.FieldValue.CanSet() || util.IsValueStructPtr(ni.FieldValue) || util.IsValueMap(ni.FieldValue) || (util.IsTypeSlice(ni.FieldValue.Type()) && util.IsTypeStructPtr(ni.FieldValue.Type().Elem())) {
+			return util.ContinueIteration, nil
+		}
+		if _, ok := ni.FieldValue.Interface().(GoOrderedMap); ok {
+			return util.ContinueIteration, nil
+		}
+		if err := fn(vp.gNMIPaths, ni.FieldValue); err != nil {
+			return util.ContinueIteration, util.NewErrs(err)
+		}
+		return util.ContinueIteration, nil
+	}
+
+	if errs := util.ForEachDataField2(s, nil, nil, iterFunc); errs != nil {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+type secretRoot struct {
+	Peer map[string]*secretPeer `path:"peers/peer"`
+}
+
+func (*secretRoot) IsYANGGoStruct() {}
+
+type secretPeer struct {
+	Name     *string `path:"config/name|name"`
+	Password *string `path:"config/password"`
+	Port     *uint16 `path:"config/port"`
+}
+
+func (*secretPeer) IsYANGGoStruct() {}
+
+func (p *secretPeer) ΛListKeyMap() (map[string]interface{}, error) {
+	return map[string]interface{}{"name": *p.Name}, nil
+}
+
+// mapSecretStore is a SecretStore whose secrets are keyed by the string form
+// of the path of the secret leaf.
+type mapSecretStore struct {
+	// names maps a leaf path to the name of the secret stored at it.
+	names map[string]string
+	// values maps a secret name to its value.
+	values map[string]string
+}
+
+func (m *mapSecretStore) SecretName(p *gnmipb.Path) (string, bool) {
+	s, err := PathToString(p)
+	if err != nil {
+		return "", false
+	}
+	n, ok := m.names[s]
+	return n, ok
+}
+
+func (m *mapSecretStore) SecretValue(name string) (string, error) {
+	v, ok := m.values[name]
+	if !ok {
+		return "", fmt.Errorf("unknown secret %s", name)
+	}
+	return v, nil
+}
+
+func newSecretRoot(peers map[string]string) *secretRoot {
+	r := &secretRoot{Peer: map[string]*secretPeer{}}
+	for name, password := range peers {
+		r.Peer[name] = &secretPeer{Name: String(name), Password: String(password)}
+	}
+	return r
+}
+
+func TestSecretNameFromPlaceholder(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantName string
+		wantOK   bool
+	}{
+		{in: SecretPlaceholder("bgp-password"), wantName: "bgp-password", wantOK: true},
+		{in: "__SECRET:__"},
+		{in: "__SECRET:"},
+		{in: "hunter2"},
+	}
+
+	for _, tt := range tests {
+		gotName, gotOK := SecretNameFromPlaceholder(tt.in)
+		if gotName != tt.wantName || gotOK != tt.wantOK {
+			t.Errorf("SecretNameFromPlaceholder(%q): got (%q, %v), want (%q, %v)", tt.in, gotName, gotOK, tt.wantName, tt.wantOK)
+		}
+	}
+}
+
+func TestSecretPlaceholderRoundTrip(t *testing.T) {
+	store := &mapSecretStore{
+		names: map[string]string{
+			"/peers/peer[name=a]/config/password": "peer-a",
+			"/peers/peer[name=b]/config/password": "peer-b",
+		},
+		values: map[string]string{
+			"peer-a": "hunter2",
+			"peer-b": "correct-horse",
+		},
+	}
+
+	s := newSecretRoot(map[string]string{"a": "hunter2", "b": "correct-horse"})
+	if err := ReplaceSecretsWithPlaceholders(s, store); err != nil {
+		t.Fatalf("ReplaceSecretsWithPlaceholders: got unexpected error: %v", err)
+	}
+	want := newSecretRoot(map[string]string{"a": "__SECRET:peer-a__", "b": "__SECRET:peer-b__"})
+	if diff := cmp.Diff(want, s); diff != "" {
+		t.Fatalf("ReplaceSecretsWithPlaceholders: did not get expected GoStruct, (-want, +got):\n%s", diff)
+	}
+
+	if err := ResolveSecretPlaceholders(s, store); err != nil {
+		t.Fatalf("ResolveSecretPlaceholders: got unexpected error: %v", err)
+	}
+	want = newSecretRoot(map[string]string{"a": "hunter2", "b": "correct-horse"})
+	if diff := cmp.Diff(want, s); diff != "" {
+		t.Fatalf("ResolveSecretPlaceholders: did not get expected GoStruct, (-want, +got):\n%s", diff)
+	}
+}
+
+func TestSecretPlaceholderErrors(t *testing.T) {
+	store := &mapSecretStore{
+		names: map[string]string{
+			"/peers/peer[name=a]/config/port": "port",
+		},
+	}
+
+	s := &secretRoot{Peer: map[string]*secretPeer{"a": {Name: String("a"), Port: Uint16(179)}}}
+	if err := ReplaceSecretsWithPlaceholders(s, store); err == nil {
+		t.Errorf("ReplaceSecretsWithPlaceholders: did not get expected error for non-string secret")
+	}
+
+	store.names["/peers/peer[name=a]/config/password"] = "unknown"
+	s = newSecretRoot(map[string]string{"a": SecretPlaceholder("unknown")})
+	if diff := errdiff.Substring(ResolveSecretPlaceholders(s, store), "unknown secret unknown"); diff != "" {
+		t.Errorf("ResolveSecretPlaceholders: %s", diff)
+	}
+}
+
+func TestResolveSecretPlaceholdersNotSecret(t *testing.T) {
+	store := &mapSecretStore{
+		names: map[string]string{
+			"/peers/peer[name=a]/config/password": "peer-a",
+		},
+		values: map[string]string{
+			"peer-a": "hunter2",
+			"peer-b": "correct-horse",
+		},
+	}
+
+	tests := []struct {
+		desc             string
+		in               *secretRoot
+		want             *secretRoot
+		wantErrSubstring string
+	}{{
+		desc:             "placeholder in leaf that is not a secret",
+		in:               newSecretRoot(map[string]string{"b": SecretPlaceholder("peer-a")}),
+		wantErrSubstring: "placeholder for secret peer-a is not allowed",
+	}, {
+		desc:             "placeholder for a different secret",
+		in:               newSecretRoot(map[string]string{"a": SecretPlaceholder("peer-b")}),
+		wantErrSubstring: "placeholder for secret peer-b is not allowed",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := ResolveSecretPlaceholders(tt.in, store)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("ResolveSecretPlaceholders: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, tt.in); diff != "" {
+				t.Errorf("ResolveSecretPlaceholders: did not get expected GoStruct, (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEmitJSONSecretStore(t *testing.T) {
+	store := &mapSecretStore{
+		names: map[string]string{
+			"/peers/peer[name=a]/config/password": "peer-a",
+		},
+	}
+	s := newSecretRoot(map[string]string{"a": "hunter2"})
+
+	got, err := EmitJSON(s, &EmitJSONConfig{
+		Format:         RFC7951,
+		SkipValidation: true,
+		Indent:         " ",
+		SecretStore:    store,
+	})
+	if err != nil {
+		t.Fatalf("EmitJSON: got unexpected error: %v", err)
+	}
+
+	want := `{
+ "peers": {
+  "peer": [
+   {
+    "config": {
+     "name": "a",
+     "password": "__SECRET:peer-a__"
+    },
+    "name": "a"
+   }
+  ]
+ }
+}`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("EmitJSON: did not get expected JSON, (-want, +got):\n%s", diff)
+	}
+	if *s.Peer["a"].Password != "hunter2" {
+		t.Errorf("EmitJSON: input GoStruct was modified, got password %q", *s.Peer["a"].Password)
+	}
+}
+
+func TestDiffSecretPlaceholders(t *testing.T) {
+	store := &mapSecretStore{
+		names: map[string]string{
+			"/peers/peer[name=a]/config/password": "peer-a",
+		},
+	}
+
+	got, err := Diff(newSecretRoot(map[string]string{"a": "hunter2"}), newSecretRoot(map[string]string{"a": "correct-horse"}), &SecretPlaceholders{Store: store})
+	if err != nil {
+		t.Fatalf("Diff: got unexpected error: %v", err)
+	}
+
+	wantPath, err := StringToStructuredPath("/peers/peer[name=a]/config/password")
+	if err != nil {
+		t.Fatalf("cannot parse path: %v", err)
+	}
+	want := &gnmipb.Notification{
+		Update: []*gnmipb.Update{{
+			Path: wantPath,
+			Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: "__SECRET:peer-a__"}},
+		}},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("Diff: did not get expected Notification, (-want, +got):\n%s", diff)
+	}
+}
@@ -30,8 +30,11 @@ import (
 	"strings"
 
 	"github.com/openconfig/gnmi/errlist"
+	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/openconfig/ygot/internal/yreflect"
 	"github.com/openconfig/ygot/util"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
 )
 
 const (
@@ -103,7 +106,34 @@ func structTagToLibModules(f reflect.StructField, preferShadowPath bool) ([]*gnm
 			return nil, nil
 		}
 	}
+	return moduleTagToLibModules(moduleAnnotation)
+}
+
+// structTagToLibModulePrefixes takes an input struct field as a reflect.Type,
+// and extracts the set of module prefixes in its module-prefix or
+// shadow-module-prefix struct tag, which correspond to the module names
+// returned by structTagToLibModules for the same field. Returns the module
+// prefixes as a slice of gnmiPaths, or an error if the field has a module tag
+// but no corresponding module prefix tag.
+func structTagToLibModulePrefixes(f reflect.StructField, preferShadowPath bool) ([]*gnmiPath, error) {
+	tag, prefixTag := "module", "module-prefix"
+	if _, ok := f.Tag.Lookup("shadow-module"); ok && preferShadowPath {
+		tag, prefixTag = "shadow-module", "shadow-module-prefix"
+	}
+	prefixAnnotation, ok := f.Tag.Lookup(prefixTag)
+	if !ok {
+		if _, ok := f.Tag.Lookup(tag); ok {
+			return nil, fmt.Errorf("field %s has a %s tag but no %s tag", f.Name, tag, prefixTag)
+		}
+		return nil, nil
+	}
+	return moduleTagToLibModules(prefixAnnotation)
+}
 
+// moduleTagToLibModules parses the value of a module or module prefix struct
+// tag, returning the module names or prefixes of each of the paths that it
+// contains as a slice of gnmiPaths.
+func moduleTagToLibModules(moduleAnnotation string) ([]*gnmiPath, error) {
 	var mapModules []*gnmiPath
 	for _, m := range strings.Split(moduleAnnotation, "|") {
 		eModule := newStringSliceGNMIPath(nil)
@@ -136,6 +166,30 @@ func EnumName(e GoEnum) (string, error) {
 	return name, err
 }
 
+// enumFieldToInt returns the integer value within the YANG schema of the
+// GoEnum e, a bool indicating whether the value was set, and a bool indicating
+// whether e is an enumeration, rather than an identity, which has no integer
+// value. The values of generated enumerated types are offset by one from their
+// YANG values, such that zero represents an unset value.
+func enumFieldToInt(e GoEnum) (int64, bool, bool, error) {
+	v := reflect.ValueOf(e)
+	if v.Int() == 0 {
+		return 0, false, true, nil
+	}
+	lookup, ok := e.ΛMap()[v.Type().Name()]
+	if !ok {
+		return 0, false, false, ClassifyError(ErrUnknownEnumValue, fmt.Errorf("cannot map enumerated value as type %s was unknown", v.Type().Name()))
+	}
+	def, ok := lookup[v.Int()]
+	if !ok {
+		return 0, false, false, ClassifyError(ErrUnknownEnumValue, fmt.Errorf("cannot map enumerated value as type %s has unknown value %d", v.Type().Name(), v.Int()))
+	}
+	if def.DefiningModule != "" {
+		return 0, true, false, nil
+	}
+	return v.Int() - 1, true, true, nil
+}
+
 // enumFieldToString takes an input reflect.Value, which is type asserted to
 // be a GoEnum, and resolves the string name corresponding to the value within
 // the YANG schema. Returns the string name of the enum, a bool indicating
@@ -168,12 +222,12 @@ func enumFieldToString(field reflect.Value, prependModuleNameIref bool) (string,
 	// The ygen library expects input of the string names of the enumeration, so extract this.
 	lookup, ok := enumVal.ΛMap()[e.Type().Name()]
 	if !ok {
-		return "", false, fmt.Errorf("cannot map enumerated value as type %s was unknown", field.Type().Name())
+		return "", false, ClassifyError(ErrUnknownEnumValue, fmt.Errorf("cannot map enumerated value as type %s was unknown", field.Type().Name()))
 	}
 
 	def, ok := lookup[e.Int()]
 	if !ok {
-		return "", false, fmt.Errorf("cannot map enumerated value as type %s has unknown value %d", field.Type().Name(), enumVal)
+		return "", false, ClassifyError(ErrUnknownEnumValue, fmt.Errorf("cannot map enumerated value as type %s has unknown value %d", field.Type().Name(), enumVal))
 	}
 
 	n := def.Name
@@ -228,24 +282,298 @@ func initialiseTree(t reflect.Type, v reflect.Value) {
 	}
 }
 
+// BuildEmptyTreeOpts specifies how BuildEmptyTreeWithOpts initialises the
+// tree of a GoStruct.
+type BuildEmptyTreeOpts struct {
+	// MaxDepth is the maximum number of levels of structs below the root
+	// that are initialised, such that a MaxDepth of 1 initialises only the
+	// direct children of the root. If it is zero, the depth is unlimited.
+	MaxDepth int
+	// InitLists specifies that the fields representing keyed lists are
+	// initialised to empty maps (or ordered maps), such that entries can be
+	// added to them directly. Since the lists contain no entries, no
+	// structs within them are initialised.
+	InitLists bool
+	// Subtrees, if non-empty, restricts initialisation to the containers
+	// and lists that are along, or beneath, one of the supplied paths,
+	// which are relative to the root. Only the names of the path elements
+	// are considered, and keys are ignored.
+	Subtrees []*gpb.Path
+}
+
+// BuildEmptyTreeWithOpts initialises the YANG tree starting at the root
+// GoStruct provided, in the same way as BuildEmptyTree, subject to the
+// supplied options, which may be nil. It returns an error if the schema
+// paths of the fields of the structs within the tree cannot be determined.
+func BuildEmptyTreeWithOpts(s GoStruct, opts *BuildEmptyTreeOpts) error {
+	if opts == nil {
+		opts = &BuildEmptyTreeOpts{}
+	}
+	v := reflect.ValueOf(s).Elem()
+	return opts.initialiseTree(v.Type(), v, nil, 1)
+}
+
+// initialiseTree initialises the nested structs, and lists if specified,
+// within the GoStruct whose reflect.Value and reflect.Type are supplied.
+// path is the schema path of the GoStruct relative to the root, and depth is
+// the number of levels of structs below the root at which its fields are.
+func (o *BuildEmptyTreeOpts) initialiseTree(t reflect.Type, v reflect.Value, path []string, depth int) error {
+	if o.MaxDepth != 0 && depth > o.MaxDepth {
+		return nil
+	}
+	for i := 0; i < v.NumField(); i++ {
+		fVal := v.Field(i)
+		fType := t.Field(i)
+
+		_, isOrderedMap := fVal.Interface().(GoOrderedMap)
+		isList := isOrderedMap || util.IsTypeMap(fType.Type)
+		switch {
+		case isList && !o.InitLists, !isList && !util.IsTypeStructPtr(fType.Type), !fVal.IsNil():
+			continue
+		}
+
+		var fPath []string
+		if len(o.Subtrees) != 0 {
+			paths, err := util.SchemaPaths(fType)
+			if err != nil {
+				return err
+			}
+			fPath = append([]string{}, path...)
+			for _, e := range paths[0] {
+				if e != "" {
+					fPath = append(fPath, e)
+				}
+			}
+			if !o.inSubtree(fPath) {
+				continue
+			}
+		}
+
+		switch {
+		case isOrderedMap:
+			fVal.Set(reflect.New(fType.Type.Elem()))
+		case isList:
+			fVal.Set(reflect.MakeMap(fType.Type))
+		default:
+			pVal := reflect.New(fType.Type.Elem())
+			if err := o.initialiseTree(pVal.Elem().Type(), pVal.Elem(), fPath, depth+1); err != nil {
+				return err
+			}
+			fVal.Set(pVal)
+		}
+	}
+	return nil
+}
+
+// inSubtree returns true if the supplied path is along, or beneath, one of
+// the subtrees to which initialisation is restricted.
+func (o *BuildEmptyTreeOpts) inSubtree(path []string) bool {
+	for _, st := range o.Subtrees {
+		elems := st.GetElem()
+		n := len(elems)
+		if len(path) < n {
+			n = len(path)
+		}
+		match := true
+		for i := 0; i < n && match; i++ {
+			match = elems[i].GetName() == path[i]
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// PruneOpt is an interface that is implemented by options to
+// PruneEmptyBranches.
+type PruneOpt interface {
+	// IsPruneOpt is a marker method.
+	IsPruneOpt()
+}
+
+// PrunePreservePresence is a PruneOpt that specifies that empty YANG presence
+// containers should not be removed, since their existence is meaningful.
+// Presence containers are identified by the yangPresence tag of the field
+// representing them, which is generated when the AddYangPresence generator
+// option is set, or by the presence statement in their schema.
+type PrunePreservePresence struct {
+	// Schema is the schema of the GoStruct being pruned, e.g., the entry
+	// of a ytypes.Schema's SchemaTree corresponding to the GoStruct. It is
+	// used to identify presence containers whose fields are not tagged.
+	// It is optional.
+	Schema *yang.Entry
+}
+
+// IsPruneOpt marks PrunePreservePresence as a valid PruneOpt.
+func (*PrunePreservePresence) IsPruneOpt() {}
+
+// hasPrunePreservePresence returns the first PrunePreservePresence from an
+// opts slice, or nil if there isn't one.
+func hasPrunePreservePresence(opts []PruneOpt) *PrunePreservePresence {
+	for _, o := range opts {
+		switch v := o.(type) {
+		case *PrunePreservePresence:
+			return v
+		}
+	}
+	return nil
+}
+
+// PruneEmptyListEntries is a PruneOpt that specifies that list entries that
+// have no populated children other than their keys should be removed from
+// their list, and that lists that become empty as a result should be removed.
+type PruneEmptyListEntries struct{}
+
+// IsPruneOpt marks PruneEmptyListEntries as a valid PruneOpt.
+func (*PruneEmptyListEntries) IsPruneOpt() {}
+
+// hasPruneEmptyListEntries determines whether there is an instance of
+// PruneEmptyListEntries within the supplied PruneOpt slice.
+func hasPruneEmptyListEntries(opts []PruneOpt) bool {
+	for _, o := range opts {
+		if _, ok := o.(*PruneEmptyListEntries); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // PruneEmptyBranches removes branches that have no populated children from the
 // GoStruct s in-place. This allows a YANG container hierarchy that has been
 // initialised with BuildEmptyTree to have those branches that were not populated
 // removed from the tree. All subtrees rooted at the supplied GoStruct are traversed
 // and any encountered GoStruct pointer fields are removed if they equate to
-// the zero value (i.e. are unpopulated).
-func PruneEmptyBranches(s GoStruct) {
+// the zero value (i.e. are unpopulated). List entries are retained unless the
+// PruneEmptyListEntries option is supplied.
+func PruneEmptyBranches(s GoStruct, opts ...PruneOpt) {
 	v := reflect.ValueOf(s).Elem()
-	pruneBranchesInternal(v.Type(), v)
+	p := &pruner{
+		removeListEntries: hasPruneEmptyListEntries(opts),
+	}
+	if pp := hasPrunePreservePresence(opts); pp != nil {
+		p.preservePresence = true
+		p.schema = pp.Schema
+	}
+	p.pruneBranchesInternal(v.Type(), v, p.schema)
+}
+
+// pruner stores the options used when removing empty branches.
+type pruner struct {
+	// preservePresence specifies that empty presence containers are not
+	// removed.
+	preservePresence bool
+	// schema is the schema of the GoStruct being pruned, or nil if it is
+	// unknown.
+	schema *yang.Entry
+	// removeListEntries specifies that list entries with no populated
+	// children other than their keys are removed.
+	removeListEntries bool
+}
+
+// isPresence reports whether the struct field f, whose schema is the
+// (possibly nil) schema, is a presence container that should be retained.
+func (p *pruner) isPresence(f reflect.StructField, schema *yang.Entry) bool {
+	if !p.preservePresence {
+		return false
+	}
+	return util.IsYangPresence(f) || (schema != nil && len(schema.Extra["presence"]) > 0)
+}
+
+// childSchema returns the schema of the struct field f of a GoStruct with the
+// supplied schema, or nil if it cannot be determined.
+func (p *pruner) childSchema(schema *yang.Entry, f reflect.StructField) *yang.Entry {
+	if schema == nil {
+		return nil
+	}
+	cschema, err := util.ChildSchema(schema, f)
+	if err != nil {
+		return nil
+	}
+	return cschema
+}
+
+// pruneListEntry removes empty branches from the list entry v, which must be
+// a GoStruct pointer with the (possibly nil) schema. It returns true if the
+// entry should be removed from its list, which is the case only if list
+// entries are being removed and the entry has no populated children other
+// than its keys.
+func (p *pruner) pruneListEntry(v reflect.Value, schema *yang.Entry) bool {
+	if !util.IsValueStructPtr(v) {
+		return false
+	}
+	if p.pruneBranchesInternal(v.Elem().Type(), v.Elem(), schema) {
+		return p.removeListEntries
+	}
+	return p.removeListEntries && onlyKeysSet(v, schema)
+}
+
+// onlyKeysSet reports whether the only populated fields of the list entry v,
+// which must be a GoStruct pointer with the (possibly nil) schema, are its
+// key leaves. The keys are determined from the schema if it is supplied, and
+// otherwise using the ΛListKeyMap method of the entry.
+func onlyKeysSet(v reflect.Value, schema *yang.Entry) bool {
+	keys := map[string]bool{}
+	switch {
+	case schema != nil && schema.Key != "":
+		for _, k := range strings.Fields(schema.Key) {
+			keys[k] = true
+		}
+	default:
+		kh, ok := v.Interface().(KeyHelperGoStruct)
+		if !ok {
+			return false
+		}
+		km, err := kh.ΛListKeyMap()
+		if err != nil {
+			return false
+		}
+		for k := range km {
+			keys[k] = true
+		}
+	}
+	if len(keys) == 0 {
+		return false
+	}
+
+	sv := v.Elem()
+	for i := 0; i < sv.NumField(); i++ {
+		fVal := sv.Field(i)
+		switch {
+		case util.IsValueNilOrDefault(fVal.Interface()):
+			continue
+		case (util.IsValueMap(fVal) || util.IsValueSlice(fVal)) && fVal.Len() == 0:
+			continue
+		}
+		if !isKeyField(sv.Type().Field(i), keys) {
+			return false
+		}
+	}
+	return true
+}
+
+// isKeyField reports whether the struct field f maps to one of the leaves
+// named in keys, which are the keys of the list that contains the struct.
+func isKeyField(f reflect.StructField, keys map[string]bool) bool {
+	paths, err := util.SchemaPaths(f)
+	if err != nil {
+		return false
+	}
+	for _, p := range paths {
+		if len(p) == 1 && keys[p[0]] {
+			return true
+		}
+	}
+	return false
 }
 
 // pruneBranchesInternal implements the logic to remove empty branches from the
-// supplied reflect.Type, reflect.Value which must represent a GoStruct. An empty
-// tree is defined to be a struct that is equal to its zero value. Only struct
-// pointer fields are examined, since these are subtrees within the generated GoStruct
-// types. It returns a bool which indicates whether all fields of the struct were
-// removed.
-func pruneBranchesInternal(t reflect.Type, v reflect.Value) bool {
+// supplied reflect.Type, reflect.Value which must represent a GoStruct with
+// the (possibly nil) schema. An empty tree is defined to be a struct that is
+// equal to its zero value. Only struct pointer fields and lists are examined,
+// since these are subtrees within the generated GoStruct types. It returns a
+// bool which indicates whether all fields of the struct were removed.
+func (p *pruner) pruneBranchesInternal(t reflect.Type, v reflect.Value, schema *yang.Entry) bool {
 	// Track whether all fields of the GoStruct are nil, such that it can
 	// be returned to the caller. This allows parents that have all empty
 	// children to be removed. This is required because BuildEmptyTree will
@@ -254,17 +582,35 @@ func pruneBranchesInternal(t reflect.Type, v reflect.Value) bool {
 	for i := 0; i < v.NumField(); i++ {
 		fVal := v.Field(i)
 		fType := t.Field(i)
+		if om, ok := fVal.Interface().(GoOrderedMap); ok {
+			if fVal.IsNil() {
+				continue
+			}
+			if p.pruneOrderedMap(fVal, om, p.childSchema(schema, fType)) {
+				fVal.Set(reflect.Zero(fType.Type))
+			} else {
+				allChildrenPruned = false
+			}
+			continue
+		}
 		if util.IsTypeStructPtr(fType.Type) {
 			// Create an empty version of the struct that is within the struct pointer.
 			// We can safely call Elem() here since we verified above that this type
 			// is a struct pointer.
 			zVal := reflect.Zero(fType.Type.Elem())
+			cschema := p.childSchema(schema, fType)
 
 			switch {
 			case fVal.IsNil():
 				// Ensure that if the field value was actually nil, we skip over this
 				// field since its already nil.
 				continue
+			case p.isPresence(fType, cschema):
+				// A presence container is retained regardless of
+				// whether it has populated children.
+				sv := fVal.Elem()
+				_ = p.pruneBranchesInternal(sv.Type(), sv, cschema)
+				allChildrenPruned = false
 			case reflect.DeepEqual(zVal.Interface(), fVal.Elem().Interface()):
 				// In the case that the zero value's interface is the same as the
 				// dereferenced field value's nil value, then we set it to the zero value
@@ -276,7 +622,7 @@ func pruneBranchesInternal(t reflect.Type, v reflect.Value) bool {
 				// If this wasn't an empty struct then we need to recurse to remove
 				// any nil children of this struct.
 				sv := fVal.Elem()
-				childPruned := pruneBranchesInternal(sv.Type(), sv)
+				childPruned := p.pruneBranchesInternal(sv.Type(), sv, cschema)
 				if childPruned {
 					// If all fields of the downstream branches are nil, then
 					// also prune this field.
@@ -291,27 +637,47 @@ func pruneBranchesInternal(t reflect.Type, v reflect.Value) bool {
 		// If the struct field wasn't a struct pointer, then we need to check whether it
 		// is the nil value of its type.
 		switch {
+		case p.removeListEntries && util.IsTypeSlice(fType.Type) && util.IsTypeStructPtr(fType.Type.Elem()):
+			if fVal.Len() == 0 {
+				continue
+			}
+			// Remove the entries of keyless lists that are empty.
+			cschema := p.childSchema(schema, fType)
+			nv := reflect.MakeSlice(fType.Type, 0, fVal.Len())
+			for j := 0; j < fVal.Len(); j++ {
+				if !p.pruneListEntry(fVal.Index(j), cschema) {
+					nv = reflect.Append(nv, fVal.Index(j))
+				}
+			}
+			if nv.Len() == 0 {
+				fVal.Set(reflect.Zero(fType.Type))
+				continue
+			}
+			fVal.Set(nv)
+			allChildrenPruned = false
 		case util.IsTypeSlice(fType.Type):
 			if (fVal.Len() != 0) && allChildrenPruned {
 				allChildrenPruned = false
 			}
 		case util.IsTypeMap(fType.Type):
-			if fVal.Len() != 0 && allChildrenPruned {
-				allChildrenPruned = false
+			if fVal.Len() == 0 {
+				continue
 			}
 
-			// Recurse into maps where the children may have already been initialised.
+			// Recurse into maps where the children may have already been
+			// initialised, removing the entries that are empty if list
+			// entries are being removed.
+			cschema := p.childSchema(schema, fType)
 			for _, k := range fVal.MapKeys() {
-				mi := fVal.MapIndex(k)
-				if !util.IsValueStructPtr(mi) {
-					continue
+				if p.pruneListEntry(fVal.MapIndex(k), cschema) {
+					fVal.SetMapIndex(k, reflect.Value{})
 				}
-				sv := mi.Elem()
-				// We can discard the pruneBranchesInternal return value, since we
-				// know that this map field has len > 0, and therefore cannot be
-				// pruned.
-				_ = pruneBranchesInternal(sv.Type(), sv)
 			}
+			if fVal.Len() == 0 {
+				fVal.Set(reflect.Zero(fType.Type))
+				continue
+			}
+			allChildrenPruned = false
 		default:
 			// Handle the case of a non-map/slice/struct pointer field.
 			v := fVal
@@ -334,6 +700,41 @@ func pruneBranchesInternal(t reflect.Type, v reflect.Value) bool {
 	return allChildrenPruned
 }
 
+// pruneOrderedMap removes empty branches from the entries of the ordered map
+// om, stored in the field fVal, removing those entries that are empty. It
+// returns true if all entries of the ordered map were removed.
+func (p *pruner) pruneOrderedMap(fVal reflect.Value, om GoOrderedMap, schema *yang.Entry) bool {
+	var retained []reflect.Value
+	var removed bool
+	if err := yreflect.RangeOrderedMap(om, func(_, e reflect.Value) bool {
+		if p.pruneListEntry(e, schema) {
+			removed = true
+		} else {
+			retained = append(retained, e)
+		}
+		return true
+	}); err != nil {
+		// The ordered map cannot be traversed, so it is retained as-is.
+		return false
+	}
+	if len(retained) == 0 {
+		return true
+	}
+	if !removed {
+		return false
+	}
+	// Appending the retained entries to a new ordered map retains their
+	// order.
+	nv := reflect.New(fVal.Type().Elem())
+	for _, e := range retained {
+		if err := yreflect.AppendIntoOrderedMap(nv.Interface().(GoOrderedMap), e.Interface()); err != nil {
+			return false
+		}
+	}
+	fVal.Set(nv)
+	return false
+}
+
 // InitContainer initialises the container cname of the GoStruct s, it can be
 // used to initialise an arbitrary named child container within a YANG
 // structure in a generic manner. This allows the caller to generically
@@ -382,7 +783,8 @@ type EmitJSONConfig struct {
 	// format JSON will be produced.
 	Format JSONFormat
 	// RFC7951Config specifies the configuration options for RFC7951 JSON. Only
-	// valid if Format is RFC7951.
+	// valid if Format is RFC7951. If it is nil, the default set by
+	// SetDefaultRFC7951JSONConfig is used.
 	RFC7951Config *RFC7951JSONConfig
 	// Indent is the string used for indentation within the JSON output. The
 	// default value is three spaces.
@@ -400,6 +802,19 @@ type EmitJSONConfig struct {
 	// validation rules in the case that a partially populated data instance is
 	// to be emitted.
 	ValidationOpts []ValidationOption
+	// SecretStore, when set, specifies that the value of each leaf that is a
+	// secret in the store is replaced by its placeholder in the emitted JSON,
+	// such that the JSON can be stored without the secret values. The
+	// supplied GoStruct is validated prior to the replacement, and is not
+	// modified.
+	SecretStore SecretStore
+	// EnumsAsInts specifies that the values of enumerations are rendered as
+	// their integer values within the YANG schema when Format is Internal.
+	// For RFC7951 JSON, the EnumsAsInts field of RFC7951Config is used.
+	EnumsAsInts bool
+	// InternalConfig specifies the configuration options for internal
+	// format JSON. Only valid if Format is Internal.
+	InternalConfig *InternalJSONConfig
 }
 
 // EmitJSON takes an input GoStruct (produced by ygen with validation enabled)
@@ -417,10 +832,21 @@ func EmitJSON(gs GoStruct, opts *EmitJSONConfig) (string, error) {
 
 	if !skipValidation {
 		if err := ValidateGoStruct(gs, vopts...); err != nil {
-			return "", fmt.Errorf("validation err: %v", err)
+			return "", fmt.Errorf("validation err: %w", err)
 		}
 	}
 
+	if opts != nil && opts.SecretStore != nil {
+		cpy, err := DeepCopy(gs)
+		if err != nil {
+			return "", fmt.Errorf("cannot copy GoStruct to replace secrets: %w", err)
+		}
+		if err := ReplaceSecretsWithPlaceholders(cpy, opts.SecretStore); err != nil {
+			return "", fmt.Errorf("cannot replace secrets: %w", err)
+		}
+		gs = cpy
+	}
+
 	v, err := makeJSON(gs, opts)
 	if err != nil {
 		return "", err
@@ -440,7 +866,7 @@ func EmitJSON(gs GoStruct, opts *EmitJSONConfig) (string, error) {
 	enc.SetIndent("", indent)
 
 	if err := enc.Encode(v); err != nil {
-		return "", fmt.Errorf("JSON marshalling error: %v", err)
+		return "", fmt.Errorf("JSON marshalling error: %w", err)
 	}
 
 	// Exclude the last newline character:
@@ -460,8 +886,13 @@ func makeJSON(s GoStruct, opts *EmitJSONConfig) (map[string]interface{}, error)
 	var err error
 	switch f {
 	case Internal:
-		if v, err = ConstructInternalJSON(s); err != nil {
-			return nil, fmt.Errorf("ConstructInternalJSON error: %v", err)
+		c := jsonOutputConfig{jType: Internal}
+		if opts != nil {
+			c.internalEnumsAsInts = opts.EnumsAsInts
+			c.internalConfig = opts.InternalConfig
+		}
+		if v, err = structJSON(s, "", c); err != nil {
+			return nil, fmt.Errorf("ConstructInternalJSON error: %w", err)
 		}
 	case RFC7951:
 		var c *RFC7951JSONConfig
@@ -469,7 +900,7 @@ func makeJSON(s GoStruct, opts *EmitJSONConfig) (map[string]interface{}, error)
 			c = opts.RFC7951Config
 		}
 		if v, err = ConstructIETFJSON(s, c); err != nil {
-			return nil, fmt.Errorf("ConstructIETFJSON error: %v", err)
+			return nil, fmt.Errorf("ConstructIETFJSON error: %w", err)
 		}
 	}
 	return v, nil
@@ -572,6 +1003,37 @@ type MergeEmptyMaps struct{}
 // IsMergeOpt marks MergeEmptyMaps as a MergeOpt.
 func (*MergeEmptyMaps) IsMergeOpt() {}
 
+// MergeConflict describes a leaf that is set to different values in the
+// destination and source structs of a merge.
+type MergeConflict struct {
+	// Path is the programmatic access path to the leaf within the merged
+	// struct, e.g., Field1.Map2["foo"].Field3.
+	Path string
+	// Dst is the value of the leaf in the destination struct, i.e., a for
+	// MergeStructs and dst for MergeStructInto.
+	Dst interface{}
+	// Src is the value of the leaf in the source struct, i.e., b for
+	// MergeStructs and src for MergeStructInto.
+	Src interface{}
+}
+
+// MergeReportConflicts is a MergeOpt that allows control of the merge
+// behaviour of MergeStructs and MergeStructInto functions.
+//
+// When used, leaves that are set to different values in the destination and
+// source structs do not cause an error. Instead, each conflict is appended to
+// Conflicts, and the value in the destination struct is retained, unless
+// MergeOverwriteExistingFields is also used, in which case it is overwritten
+// by the value in the source struct. Conflicts between the entries of
+// leaf-lists and lists are still returned as errors.
+type MergeReportConflicts struct {
+	// Conflicts is the set of conflicting leaves found during the merge.
+	Conflicts []*MergeConflict
+}
+
+// IsMergeOpt marks MergeReportConflicts as a MergeOpt.
+func (*MergeReportConflicts) IsMergeOpt() {}
+
 // MergeStructs takes two input GoStruct and merges their contents,
 // returning a new GoStruct. If the input structs a and b are of
 // different types, an error is returned.
@@ -592,7 +1054,7 @@ func MergeStructs(a, b GoStruct, opts ...MergeOpt) (GoStruct, error) {
 	}
 
 	if err := MergeStructInto(dst, b, opts...); err != nil {
-		return nil, fmt.Errorf("error merging b to new struct: %v", err)
+		return nil, fmt.Errorf("error merging b to new struct: %w", err)
 	}
 
 	return dst, nil
@@ -610,9 +1072,21 @@ func MergeStructInto(dst, src GoStruct, opts ...MergeOpt) error {
 	return copyStruct(reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem(), "", opts...)
 }
 
+// DeepCopier is an interface implemented by GoStructs for which a method
+// that copies the struct without using reflection was generated.
+type DeepCopier interface {
+	// ΛDeepCopy returns a deep copy of the GoStruct.
+	ΛDeepCopy() GoStruct
+}
+
 // DeepCopy returns a deep copy of the supplied GoStruct. A new copy
-// of the GoStruct is created, along with any underlying values.
+// of the GoStruct is created, along with any underlying values. If the
+// GoStruct implements DeepCopier, its generated ΛDeepCopy method is used
+// to copy it.
 func DeepCopy(s GoStruct) (GoStruct, error) {
+	if c, ok := s.(DeepCopier); ok && !util.IsNilOrInvalidValue(reflect.ValueOf(s)) {
+		return c.ΛDeepCopy(), nil
+	}
 	return deepCopy(s, false)
 }
 
@@ -630,7 +1104,7 @@ func deepCopy(s GoStruct, keepEmptyMaps bool) (GoStruct, error) {
 		opts = append(opts, &MergeEmptyMaps{})
 	}
 	if err := copyStruct(n.Elem(), reflect.ValueOf(s).Elem(), "", opts...); err != nil {
-		return nil, fmt.Errorf("cannot DeepCopy struct: %v", err)
+		return nil, fmt.Errorf("cannot DeepCopy struct: %w", err)
 	}
 	return n.Interface().(GoStruct), nil
 }
@@ -647,6 +1121,40 @@ func fieldOverwriteEnabled(opts []MergeOpt) bool {
 	return false
 }
 
+// hasMergeReportConflicts returns the first MergeReportConflicts from the
+// slice of MergeOpt, or nil if there isn't one.
+func hasMergeReportConflicts(opts []MergeOpt) *MergeReportConflicts {
+	for _, o := range opts {
+		switch v := o.(type) {
+		case *MergeReportConflicts:
+			return v
+		}
+	}
+	return nil
+}
+
+// mergeConflict handles the leaf at accessPath being set to the different
+// values dst and src in the destination and source structs. It returns true
+// if the source value should be copied into the destination, or err if the
+// conflict is not permitted by opts.
+func mergeConflict(accessPath string, dst, src interface{}, err error, opts []MergeOpt) (bool, error) {
+	r := hasMergeReportConflicts(opts)
+	if r != nil {
+		r.Conflicts = append(r.Conflicts, &MergeConflict{
+			Path: strings.TrimPrefix(accessPath, "."),
+			Dst:  dst,
+			Src:  src,
+		})
+	}
+	switch {
+	case fieldOverwriteEnabled(opts):
+		return true, nil
+	case r != nil:
+		return false, nil
+	}
+	return false, err
+}
+
 // mergeEmptyMapsEnabled returns true if MergeEmptyMaps
 // is present in the slice of MergeOpt.
 func mergeEmptyMapsEnabled(opts []MergeOpt) bool {
@@ -678,44 +1186,49 @@ func copyStruct(dstVal, srcVal reflect.Value, accessPath string, opts ...MergeOp
 	var errs errlist.Error
 	errs.Separator = "\n"
 	for i := 0; i < srcVal.NumField(); i++ {
-		srcField := srcVal.Field(i)
-		dstField := dstVal.Field(i)
-		accessPath := accessPath + "." + srcVal.Type().Field(i).Name
-
-		orderedMap, isOrderedMap := srcField.Interface().(GoOrderedMap)
-		switch srcField.Kind() {
-		case reflect.Ptr:
-			if isOrderedMap {
-				errs.Add(copyOrderedMap(dstField, orderedMap, accessPath, opts...))
-			} else {
-				errs.Add(copyPtrField(dstField, srcField, accessPath, opts...))
+		errs.Add(copyField(dstVal.Field(i), srcVal.Field(i), accessPath+"."+srcVal.Type().Field(i).Name, opts...))
+	}
+	return listErr(errs)
+}
+
+// copyField copies the struct field srcField into the struct field dstField,
+// using the semantics of copyStruct. accessPath is the programmatic access
+// path to the field.
+func copyField(dstField, srcField reflect.Value, accessPath string, opts ...MergeOpt) error {
+	orderedMap, isOrderedMap := srcField.Interface().(GoOrderedMap)
+	switch srcField.Kind() {
+	case reflect.Ptr:
+		if isOrderedMap {
+			return copyOrderedMap(dstField, orderedMap, accessPath, opts...)
+		}
+		return copyPtrField(dstField, srcField, accessPath, opts...)
+	case reflect.Interface:
+		return copyInterfaceField(dstField, srcField, accessPath, opts...)
+	case reflect.Map:
+		return copyMapField(dstField, srcField, accessPath, opts...)
+	case reflect.Slice:
+		return copySliceField(dstField, srcField, accessPath, opts...)
+	case reflect.Int64:
+		// In the case of an int64 field, which represents a YANG enumeration
+		// we should only set the value in the destination if it is not set
+		// to the default value in the source.
+		vSrc, vDst := srcField.Int(), dstField.Int()
+		switch {
+		case vSrc != 0 && vDst != 0 && vSrc != vDst:
+			overwrite, err := mergeConflict(accessPath, dstField.Interface(), srcField.Interface(), fmt.Errorf("%s: destination and source values were set when merging enum field, dst: %d, src: %d", accessPath, vSrc, vDst), opts)
+			if err != nil {
+				return err
 			}
-		case reflect.Interface:
-			errs.Add(copyInterfaceField(dstField, srcField, accessPath, opts...))
-		case reflect.Map:
-			errs.Add(copyMapField(dstField, srcField, accessPath, opts...))
-		case reflect.Slice:
-			errs.Add(copySliceField(dstField, srcField, accessPath, opts...))
-		case reflect.Int64:
-			// In the case of an int64 field, which represents a YANG enumeration
-			// we should only set the value in the destination if it is not set
-			// to the default value in the source.
-			vSrc, vDst := srcField.Int(), dstField.Int()
-			switch {
-			case vSrc != 0 && vDst != 0 && vSrc != vDst:
-				if !fieldOverwriteEnabled(opts) {
-					errs.Add(fmt.Errorf("%s: destination and source values were set when merging enum field, dst: %d, src: %d", accessPath, vSrc, vDst))
-					break
-				}
-				dstField.Set(srcField)
-			case vSrc != 0 && vDst == 0:
+			if overwrite {
 				dstField.Set(srcField)
 			}
-		default:
+		case vSrc != 0 && vDst == 0:
 			dstField.Set(srcField)
 		}
+	default:
+		dstField.Set(srcField)
 	}
-	return errs.Err()
+	return nil
 }
 
 // copyPtrField copies srcField to dstField. srcField and dstField must be
@@ -757,8 +1270,11 @@ func copyPtrField(dstField, srcField reflect.Value, accessPath string, opts ...M
 
 	if !util.IsNilOrInvalidValue(dstField) {
 		s, d := srcField.Elem().Interface(), dstField.Elem().Interface()
-		if !fieldOverwriteEnabled(opts) && !reflect.DeepEqual(s, d) {
-			return fmt.Errorf("%s: destination value was set, but was not equal to source value when merging ptr field, src: %v, dst: %v", accessPath, s, d)
+		if !reflect.DeepEqual(s, d) {
+			overwrite, err := mergeConflict(accessPath, d, s, fmt.Errorf("%s: destination value was set, but was not equal to source value when merging ptr field, src: %v, dst: %v", accessPath, s, d), opts)
+			if !overwrite {
+				return err
+			}
 		}
 	}
 
@@ -785,8 +1301,11 @@ func copyInterfaceField(dstField, srcField reflect.Value, accessPath string, opt
 		s := srcField.Elem().Elem() // Dereference src to a struct.
 		if !util.IsNilOrInvalidValue(dstField) {
 			dV := dstField.Elem().Elem() // Dereference dst to a struct.
-			if !fieldOverwriteEnabled(opts) && !reflect.DeepEqual(s.Interface(), dV.Interface()) {
-				return fmt.Errorf("%s: interface field was set in both src and dst and was not equal, src: %v, dst: %v", accessPath, s.Interface(), dV.Interface())
+			if !reflect.DeepEqual(s.Interface(), dV.Interface()) {
+				overwrite, err := mergeConflict(accessPath, dstField.Interface(), srcField.Interface(), fmt.Errorf("%s: interface field was set in both src and dst and was not equal, src: %v, dst: %v", accessPath, s.Interface(), dV.Interface()), opts)
+				if !overwrite {
+					return err
+				}
 			}
 		}
 
@@ -799,8 +1318,11 @@ func copyInterfaceField(dstField, srcField reflect.Value, accessPath string, opt
 	case srcField.Elem().Kind() == reflect.Slice && srcField.Elem().Type().Name() == BinaryTypeName:
 		if !util.IsNilOrInvalidValue(dstField) {
 			s, d := srcField.Interface(), dstField.Interface()
-			if !fieldOverwriteEnabled(opts) && !reflect.DeepEqual(s, d) {
-				return fmt.Errorf("%s: interface field was set in both src and dst and was not equal, src: %v, dst: %v", accessPath, s, d)
+			if !reflect.DeepEqual(s, d) {
+				overwrite, err := mergeConflict(accessPath, d, s, fmt.Errorf("%s: interface field was set in both src and dst and was not equal, src: %v, dst: %v", accessPath, s, d), opts)
+				if !overwrite {
+					return err
+				}
 			}
 		}
 
@@ -814,8 +1336,11 @@ func copyInterfaceField(dstField, srcField reflect.Value, accessPath string, opt
 	case util.IsValueScalar(srcField.Elem()) && (isGoEnum || unionSingletonUnderlyingTypes[srcField.Elem().Type().Name()] != nil):
 		if !util.IsNilOrInvalidValue(dstField) {
 			s, d := srcField.Interface(), dstField.Interface()
-			if !fieldOverwriteEnabled(opts) && !reflect.DeepEqual(s, d) {
-				return fmt.Errorf("%s: interface field was set in both src and dst and was not equal, src: %v, dst: %v", accessPath, s, d)
+			if !reflect.DeepEqual(s, d) {
+				overwrite, err := mergeConflict(accessPath, d, s, fmt.Errorf("%s: interface field was set in both src and dst and was not equal, src: %v, dst: %v", accessPath, s, d), opts)
+				if !overwrite {
+					return err
+				}
 			}
 		}
 		dstField.Set(srcField)
@@ -878,7 +1403,7 @@ func copyMapField(dstField, srcField reflect.Value, accessPath string, opts ...M
 		}
 		dstField.SetMapIndex(k, d)
 	}
-	return errs.Err()
+	return listErr(errs)
 }
 
 // mapTypes provides a specification of a map.
@@ -1030,7 +1555,7 @@ func copyOrderedMap(dstField reflect.Value, srcOrderedMap GoOrderedMap, accessPa
 		errs.Add(err)
 	}
 
-	return errs.Err()
+	return listErr(errs)
 }
 
 // copySliceField copies srcField into dstField. Both srcField and dstField
@@ -1048,7 +1573,7 @@ func copySliceField(dstField, srcField reflect.Value, accessPath string, opts ..
 
 		unique, err := uniqueSlices(dstField, srcField)
 		if err != nil {
-			return fmt.Errorf("error checking src and dst for uniqueness, got: %v", err)
+			return fmt.Errorf("error checking src and dst for uniqueness, got: %w", err)
 		}
 
 		if !unique {
@@ -1076,7 +1601,7 @@ func copySliceField(dstField, srcField reflect.Value, accessPath string, opts ..
 		}
 		dstField.Set(reflect.Append(dstField, v))
 	}
-	return errs.Err()
+	return listErr(errs)
 }
 
 // uniqueSlices takes two reflect.Values which must represent slices, and determines
@@ -25,6 +25,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/kylelemons/godebug/pretty"
 	"github.com/openconfig/gnmi/errdiff"
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/openconfig/ygot/integration_tests/schemaops/ctestschema"
 	"github.com/openconfig/ygot/integration_tests/schemaops/utestschema"
 	"github.com/openconfig/ygot/internal/ytestutil"
@@ -327,6 +328,63 @@ func TestBuildEmptyTree(t *testing.T) {
 	}
 }
 
+func TestBuildEmptyTreeWithOpts(t *testing.T) {
+	tests := []struct {
+		name     string
+		inStruct ygot.GoStruct
+		inOpts   *ygot.BuildEmptyTreeOpts
+		want     ygot.GoStruct
+	}{{
+		name:     "lists initialised",
+		inStruct: &ctestschema.Device{},
+		inOpts:   &ygot.BuildEmptyTreeOpts{InitLists: true},
+		want: &ctestschema.Device{
+			OrderedList:           &ctestschema.OrderedList_OrderedMap{},
+			OrderedMultikeyedList: &ctestschema.OrderedMultikeyedList_OrderedMap{},
+			OtherData:             &ctestschema.OtherData{},
+			UnorderedList:         map[string]*ctestschema.UnorderedList{},
+		},
+	}, {
+		name:     "subtree of ordered list",
+		inStruct: &ctestschema.Device{},
+		inOpts: &ygot.BuildEmptyTreeOpts{
+			InitLists: true,
+			Subtrees:  []*gpb.Path{{Elem: []*gpb.PathElem{{Name: "ordered-lists"}}}},
+		},
+		want: &ctestschema.Device{
+			OrderedList: &ctestschema.OrderedList_OrderedMap{},
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ygot.BuildEmptyTreeWithOpts(tt.inStruct, tt.inOpts); err != nil {
+				t.Fatalf("BuildEmptyTreeWithOpts: unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, tt.inStruct, ytestutil.OrderedMapCmpOptions...); diff != "" {
+				t.Errorf("BuildEmptyTreeWithOpts: did not get expected output, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPruneEmptyBranchesOrderedMap(t *testing.T) {
+	got := &ctestschema.Device{
+		OrderedList: ctestschema.GetOrderedMap(t),
+		OtherData:   &ctestschema.OtherData{},
+	}
+	ygot.PruneEmptyBranches(got)
+	if diff := cmp.Diff(&ctestschema.Device{OrderedList: ctestschema.GetOrderedMap(t)}, got, ytestutil.OrderedMapCmpOptions...); diff != "" {
+		t.Errorf("PruneEmptyBranches: did not get expected output, diff(-want,+got):\n%s", diff)
+	}
+
+	empty := &ctestschema.Device{OrderedList: &ctestschema.OrderedList_OrderedMap{}}
+	ygot.PruneEmptyBranches(empty)
+	if empty.OrderedList != nil {
+		t.Errorf("PruneEmptyBranches: empty ordered map was not removed, got %v", empty.OrderedList)
+	}
+}
+
 func TestDeepCopyOrderedMap(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -430,6 +488,46 @@ func TestDeepCopyOrderedMap(t *testing.T) {
 	}
 }
 
+func TestDeepCopyGenerated(t *testing.T) {
+	in := &ctestschema.Device{
+		OrderedList:           ctestschema.GetNestedOrderedMap(t),
+		OrderedMultikeyedList: ctestschema.GetOrderedMapMultikeyed(t),
+		OtherData:             &ctestschema.OtherData{Motd: ygot.String("hello")},
+		UnorderedList: map[string]*ctestschema.UnorderedList{
+			"foo": {Key: ygot.String("foo"), Value: ygot.String("bar")},
+		},
+	}
+	if _, ok := interface{}(in).(ygot.DeepCopier); !ok {
+		t.Fatalf("%T does not implement ygot.DeepCopier", in)
+	}
+
+	got, err := ygot.DeepCopy(in)
+	if err != nil {
+		t.Fatalf("DeepCopy: unexpected error: %v", err)
+	}
+
+	// The generated copy must be equal to that made using reflection.
+	want := &ctestschema.Device{}
+	if err := ygot.MergeStructInto(want, in); err != nil {
+		t.Fatalf("MergeStructInto: unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, got, ytestutil.OrderedMapCmpOptions...); diff != "" {
+		t.Errorf("DeepCopy: generated copy differs from reflective copy, diff(-want,+got):\n%s", diff)
+	}
+
+	gotRoot := got.(*ctestschema.Device)
+	*gotRoot.OtherData.Motd = "changed"
+	*gotRoot.UnorderedList["foo"].Value = "changed"
+	if *in.OtherData.Motd != "hello" || *in.UnorderedList["foo"].Value != "bar" {
+		t.Errorf("DeepCopy: modifying the copy modified the input, got: %v", in)
+	}
+
+	var nilDevice *ctestschema.Device
+	if _, err := ygot.DeepCopy(nilDevice); err == nil {
+		t.Errorf("DeepCopy: did not get expected error for nil input")
+	}
+}
+
 func TestMergeStructsOrderedMap(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -26,6 +26,7 @@ import (
 
 	"github.com/openconfig/gnmi/errdiff"
 	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/openconfig/ygot/testutil"
 )
 
@@ -513,10 +514,126 @@ func TestBuildEmptyTree(t *testing.T) {
 	}
 }
 
+// emptyTreeOptsRoot is the root struct used in TestBuildEmptyTreeWithOpts.
+type emptyTreeOptsRoot struct {
+	A    *emptyTreeOptsA            `path:"a"`
+	B    *emptyTreeOptsB            `path:"b/c"`
+	List map[string]*emptyTreeOptsA `path:"lists/list"`
+}
+
+// IsYANGGoStruct ensures that emptyTreeOptsRoot implements the GoStruct interface.
+func (*emptyTreeOptsRoot) IsYANGGoStruct() {}
+
+// emptyTreeOptsA is a child struct used in TestBuildEmptyTreeWithOpts.
+type emptyTreeOptsA struct {
+	Leaf  *string         `path:"config/leaf"`
+	Child *emptyTreeOptsB `path:"child"`
+}
+
+// IsYANGGoStruct ensures that emptyTreeOptsA implements the GoStruct interface.
+func (*emptyTreeOptsA) IsYANGGoStruct() {}
+
+// emptyTreeOptsB is a child struct used in TestBuildEmptyTreeWithOpts.
+type emptyTreeOptsB struct {
+	Val *string `path:"val"`
+}
+
+// IsYANGGoStruct ensures that emptyTreeOptsB implements the GoStruct interface.
+func (*emptyTreeOptsB) IsYANGGoStruct() {}
+
+func TestBuildEmptyTreeWithOpts(t *testing.T) {
+	tests := []struct {
+		name             string
+		inStruct         GoStruct
+		inOpts           *BuildEmptyTreeOpts
+		want             GoStruct
+		wantErrSubstring string
+	}{{
+		name:     "nil options",
+		inStruct: &emptyTreeOptsRoot{},
+		want: &emptyTreeOptsRoot{
+			A: &emptyTreeOptsA{Child: &emptyTreeOptsB{}},
+			B: &emptyTreeOptsB{},
+		},
+	}, {
+		name:     "max depth",
+		inStruct: &emptyTreeOptsRoot{},
+		inOpts:   &BuildEmptyTreeOpts{MaxDepth: 1},
+		want: &emptyTreeOptsRoot{
+			A: &emptyTreeOptsA{},
+			B: &emptyTreeOptsB{},
+		},
+	}, {
+		name:     "lists initialised",
+		inStruct: &emptyTreeOptsRoot{},
+		inOpts:   &BuildEmptyTreeOpts{InitLists: true},
+		want: &emptyTreeOptsRoot{
+			A:    &emptyTreeOptsA{Child: &emptyTreeOptsB{}},
+			B:    &emptyTreeOptsB{},
+			List: map[string]*emptyTreeOptsA{},
+		},
+	}, {
+		name:     "populated list is not replaced",
+		inStruct: &emptyTreeOptsRoot{List: map[string]*emptyTreeOptsA{"x": {}}},
+		inOpts:   &BuildEmptyTreeOpts{InitLists: true, MaxDepth: 1},
+		want: &emptyTreeOptsRoot{
+			A:    &emptyTreeOptsA{},
+			B:    &emptyTreeOptsB{},
+			List: map[string]*emptyTreeOptsA{"x": {}},
+		},
+	}, {
+		name:     "subtree",
+		inStruct: &emptyTreeOptsRoot{},
+		inOpts: &BuildEmptyTreeOpts{
+			InitLists: true,
+			Subtrees:  []*gnmipb.Path{{Elem: []*gnmipb.PathElem{{Name: "a"}, {Name: "child"}}}},
+		},
+		want: &emptyTreeOptsRoot{
+			A: &emptyTreeOptsA{Child: &emptyTreeOptsB{}},
+		},
+	}, {
+		name:     "subtree within compressed path",
+		inStruct: &emptyTreeOptsRoot{},
+		inOpts: &BuildEmptyTreeOpts{
+			InitLists: true,
+			Subtrees: []*gnmipb.Path{
+				{Elem: []*gnmipb.PathElem{{Name: "b"}}},
+				{Elem: []*gnmipb.PathElem{{Name: "lists"}, {Name: "list", Key: map[string]string{"k": "v"}}}},
+			},
+		},
+		want: &emptyTreeOptsRoot{
+			B:    &emptyTreeOptsB{},
+			List: map[string]*emptyTreeOptsA{},
+		},
+	}, {
+		name:     "subtree with missing path tag",
+		inStruct: &emptyTreeTestTwo{},
+		inOpts: &BuildEmptyTreeOpts{
+			Subtrees: []*gnmipb.Path{{Elem: []*gnmipb.PathElem{{Name: "a"}}}},
+		},
+		want:             &emptyTreeTestTwo{},
+		wantErrSubstring: "did not specify a path",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := BuildEmptyTreeWithOpts(tt.inStruct, tt.inOpts)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("BuildEmptyTreeWithOpts: did not get expected error, %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, tt.inStruct); diff != "" {
+				t.Errorf("BuildEmptyTreeWithOpts: did not get expected output, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
 type emptyBranchTestOne struct {
-	String    *string                             `path:"string"`
-	Struct    *emptyBranchTestOneChild            `path:"child"`
-	StructMap map[string]*emptyBranchTestOneChild `path:"maps/map"`
+	String      *string                             `path:"string"`
+	Struct      *emptyBranchTestOneChild            `path:"child"`
+	StructMap   map[string]*emptyBranchTestOneChild `path:"maps/map"`
+	StructSlice []*emptyBranchTestOneChild          `path:"slices/slice"`
+	KeyedMap    map[string]*emptyBranchTestKeyed    `path:"keyed-maps/keyed-map"`
 }
 
 func (*emptyBranchTestOne) IsYANGGoStruct() {}
@@ -543,10 +660,25 @@ type emptyBranchTestOneGreatGrandchild struct {
 
 func (*emptyBranchTestOneGreatGrandchild) IsYANGGoStruct() {}
 
+type emptyBranchTestKeyed struct {
+	Name   *string                       `path:"name"`
+	Config *emptyBranchTestOneGrandchild `path:"config"`
+}
+
+func (*emptyBranchTestKeyed) IsYANGGoStruct() {}
+
+func (e *emptyBranchTestKeyed) ΛListKeyMap() (map[string]interface{}, error) {
+	if e.Name == nil {
+		return nil, fmt.Errorf("nil value for key Name")
+	}
+	return map[string]interface{}{"name": *e.Name}, nil
+}
+
 func TestPruneEmptyBranches(t *testing.T) {
 	tests := []struct {
 		name     string
 		inStruct GoStruct
+		inOpts   []PruneOpt
 		want     GoStruct
 	}{{
 		name:     "struct with no children",
@@ -650,16 +782,224 @@ func TestPruneEmptyBranches(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "struct with map with empty entry retained by default",
+		inStruct: &emptyBranchTestOne{
+			StructMap: map[string]*emptyBranchTestOneChild{
+				"empty": {
+					Struct: &emptyBranchTestOneGrandchild{},
+				},
+			},
+		},
+		want: &emptyBranchTestOne{
+			StructMap: map[string]*emptyBranchTestOneChild{
+				"empty": {},
+			},
+		},
+	}, {
+		name: "struct with keyless list with empty entries retained by default",
+		inStruct: &emptyBranchTestOne{
+			StructSlice: []*emptyBranchTestOneChild{
+				{Struct: &emptyBranchTestOneGrandchild{}},
+			},
+		},
+		want: &emptyBranchTestOne{
+			StructSlice: []*emptyBranchTestOneChild{
+				{Struct: &emptyBranchTestOneGrandchild{}},
+			},
+		},
+	}, {
+		name: "struct with keyed entry with only its key set retained by default",
+		inStruct: &emptyBranchTestOne{
+			KeyedMap: map[string]*emptyBranchTestKeyed{
+				"one": {
+					Name:   String("one"),
+					Config: &emptyBranchTestOneGrandchild{},
+				},
+			},
+		},
+		want: &emptyBranchTestOne{
+			KeyedMap: map[string]*emptyBranchTestKeyed{
+				"one": {
+					Name: String("one"),
+				},
+			},
+		},
+	}, {
+		name: "struct with map with empty entry",
+		inStruct: &emptyBranchTestOne{
+			StructMap: map[string]*emptyBranchTestOneChild{
+				"empty": {
+					Struct: &emptyBranchTestOneGrandchild{},
+				},
+				"value": {
+					String: String("value"),
+				},
+			},
+		},
+		inOpts: []PruneOpt{&PruneEmptyListEntries{}},
+		want: &emptyBranchTestOne{
+			StructMap: map[string]*emptyBranchTestOneChild{
+				"value": {
+					String: String("value"),
+				},
+			},
+		},
+	}, {
+		name: "struct with map with only empty entries",
+		inStruct: &emptyBranchTestOne{
+			StructMap: map[string]*emptyBranchTestOneChild{
+				"empty": {
+					Struct: &emptyBranchTestOneGrandchild{},
+				},
+			},
+		},
+		inOpts: []PruneOpt{&PruneEmptyListEntries{}},
+		want:   &emptyBranchTestOne{},
+	}, {
+		name: "struct with keyless list with empty entries",
+		inStruct: &emptyBranchTestOne{
+			StructSlice: []*emptyBranchTestOneChild{
+				{},
+				{String: String("value")},
+				{Struct: &emptyBranchTestOneGrandchild{}},
+			},
+		},
+		inOpts: []PruneOpt{&PruneEmptyListEntries{}},
+		want: &emptyBranchTestOne{
+			StructSlice: []*emptyBranchTestOneChild{
+				{String: String("value")},
+			},
+		},
+	}, {
+		name: "struct with keyless list with only empty entries",
+		inStruct: &emptyBranchTestOne{
+			String:      String("hello"),
+			StructSlice: []*emptyBranchTestOneChild{{}},
+		},
+		inOpts: []PruneOpt{&PruneEmptyListEntries{}},
+		want: &emptyBranchTestOne{
+			String: String("hello"),
+		},
+	}, {
+		name: "struct with keyed entry with only its key set",
+		inStruct: &emptyBranchTestOne{
+			String: String("hello"),
+			KeyedMap: map[string]*emptyBranchTestKeyed{
+				"one": {
+					Name:   String("one"),
+					Config: &emptyBranchTestOneGrandchild{},
+				},
+				"two": {
+					Name:   String("two"),
+					Config: &emptyBranchTestOneGrandchild{String: String("value")},
+				},
+			},
+		},
+		inOpts: []PruneOpt{&PruneEmptyListEntries{}},
+		want: &emptyBranchTestOne{
+			String: String("hello"),
+			KeyedMap: map[string]*emptyBranchTestKeyed{
+				"two": {
+					Name:   String("two"),
+					Config: &emptyBranchTestOneGrandchild{String: String("value")},
+				},
+			},
+		},
+	}, {
+		name: "struct with keyed entries with only their keys set",
+		inStruct: &emptyBranchTestOne{
+			KeyedMap: map[string]*emptyBranchTestKeyed{
+				"one": {Name: String("one")},
+			},
+		},
+		inOpts: []PruneOpt{&PruneEmptyListEntries{}},
+		want:   &emptyBranchTestOne{},
 	}}
 
 	for _, tt := range tests {
-		PruneEmptyBranches(tt.inStruct)
+		PruneEmptyBranches(tt.inStruct, tt.inOpts...)
 		if diff := pretty.Compare(tt.inStruct, tt.want); diff != "" {
 			t.Errorf("%s: PruneEmptyBranches(%#v): did not get expected output, diff(-got,+want):\n%s", tt.name, tt.inStruct, diff)
 		}
 	}
 }
 
+type pruneTestPresence struct {
+	Tagged   *pruneTestPresenceChild `path:"tagged" yangPresence:"true"`
+	Untagged *pruneTestPresenceChild `path:"untagged"`
+	Regular  *pruneTestPresenceChild `path:"regular"`
+}
+
+func (*pruneTestPresence) IsYANGGoStruct() {}
+
+type pruneTestPresenceChild struct {
+	Child *pruneTestPresenceGrandchild `path:"child"`
+}
+
+func (*pruneTestPresenceChild) IsYANGGoStruct() {}
+
+type pruneTestPresenceGrandchild struct {
+	String *string `path:"string"`
+}
+
+func (*pruneTestPresenceGrandchild) IsYANGGoStruct() {}
+
+func TestPruneEmptyBranchesPresence(t *testing.T) {
+	schema := &yang.Entry{
+		Name: "root",
+		Kind: yang.DirectoryEntry,
+		Dir: map[string]*yang.Entry{
+			"tagged": {Name: "tagged", Kind: yang.DirectoryEntry},
+			"untagged": {
+				Name:  "untagged",
+				Kind:  yang.DirectoryEntry,
+				Extra: map[string][]interface{}{"presence": {&yang.Value{Name: "presence container"}}},
+			},
+			"regular": {Name: "regular", Kind: yang.DirectoryEntry},
+		},
+	}
+	newStruct := func() *pruneTestPresence {
+		return &pruneTestPresence{
+			Tagged:   &pruneTestPresenceChild{Child: &pruneTestPresenceGrandchild{}},
+			Untagged: &pruneTestPresenceChild{},
+			Regular:  &pruneTestPresenceChild{},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		inOpts []PruneOpt
+		want   *pruneTestPresence
+	}{{
+		name: "presence containers not preserved",
+		want: &pruneTestPresence{},
+	}, {
+		name:   "tagged presence container preserved",
+		inOpts: []PruneOpt{&PrunePreservePresence{}},
+		want: &pruneTestPresence{
+			Tagged: &pruneTestPresenceChild{},
+		},
+	}, {
+		name:   "presence containers preserved using schema",
+		inOpts: []PruneOpt{&PrunePreservePresence{Schema: schema}},
+		want: &pruneTestPresence{
+			Tagged:   &pruneTestPresenceChild{},
+			Untagged: &pruneTestPresenceChild{},
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newStruct()
+			PruneEmptyBranches(got, tt.inOpts...)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("PruneEmptyBranches: did not get expected output, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
 // initContainerTest is a synthesised GoStruct for use in
 // testing InitContainer.
 type initContainerTest struct {
@@ -2207,6 +2547,114 @@ func TestMergeStructInto(t *testing.T) {
 	}
 }
 
+func TestMergeReportConflicts(t *testing.T) {
+	inA := &validatedMergeTest{
+		String:     String("foo"),
+		StringTwo:  String("same"),
+		EnumValue:  EnumTypeValueTwo,
+		UnionField: &copyUnionS{"foo"},
+		MapField: map[string]*validatedMergeTestTwo{
+			"foo": {String: String("foo")},
+		},
+	}
+	inB := &validatedMergeTest{
+		String:      String("bar"),
+		StringTwo:   String("same"),
+		Uint32Field: Uint32(42),
+		EnumValue:   EnumTypeValue,
+		UnionField:  &copyUnionS{"bar"},
+		MapField: map[string]*validatedMergeTestTwo{
+			"foo": {String: String("bar")},
+		},
+	}
+	wantConflicts := []*MergeConflict{{
+		Path: "String",
+		Dst:  "foo",
+		Src:  "bar",
+	}, {
+		Path: "EnumValue",
+		Dst:  EnumTypeValueTwo,
+		Src:  EnumTypeValue,
+	}, {
+		Path: "UnionField",
+		Dst:  &copyUnionS{"foo"},
+		Src:  &copyUnionS{"bar"},
+	}, {
+		Path: `MapField["foo"].String`,
+		Dst:  "foo",
+		Src:  "bar",
+	}}
+
+	tests := []struct {
+		name          string
+		inOpts        []MergeOpt
+		want          GoStruct
+		wantConflicts []*MergeConflict
+		wantErr       string
+	}{{
+		name: "conflicts retain destination values",
+		want: &validatedMergeTest{
+			String:      String("foo"),
+			StringTwo:   String("same"),
+			Uint32Field: Uint32(42),
+			EnumValue:   EnumTypeValueTwo,
+			UnionField:  &copyUnionS{"foo"},
+			MapField: map[string]*validatedMergeTestTwo{
+				"foo": {String: String("foo")},
+			},
+		},
+		wantConflicts: wantConflicts,
+	}, {
+		name:   "conflicts overwritten by source values",
+		inOpts: []MergeOpt{&MergeOverwriteExistingFields{}},
+		want: &validatedMergeTest{
+			String:      String("bar"),
+			StringTwo:   String("same"),
+			Uint32Field: Uint32(42),
+			EnumValue:   EnumTypeValue,
+			UnionField:  &copyUnionS{"bar"},
+			MapField: map[string]*validatedMergeTestTwo{
+				"foo": {String: String("bar")},
+			},
+		},
+		wantConflicts: wantConflicts,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MergeReportConflicts{}
+			got, err := MergeStructs(inA, inB, append(tt.inOpts, r)...)
+			if err != nil {
+				t.Fatalf("MergeStructs: got unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("MergeStructs: did not get expected struct (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantConflicts, r.Conflicts); diff != "" {
+				t.Errorf("MergeStructs: did not get expected conflicts (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	// Conflicts between the entries of lists are not reported.
+	r := &MergeReportConflicts{}
+	_, err := MergeStructs(&validatedMergeTest{
+		SliceContainer: &validatedMergeTestWithSlice{
+			SliceField: []*validatedMergeTestSliceField{{String: String("foo")}},
+		},
+	}, &validatedMergeTest{
+		SliceContainer: &validatedMergeTestWithSlice{
+			SliceField: []*validatedMergeTestSliceField{{String: String("foo")}, {String: String("bar")}},
+		},
+	}, r)
+	if diff := errdiff.Substring(err, "source and destination lists must be unique"); diff != "" {
+		t.Errorf("MergeStructs: %s", diff)
+	}
+	if len(r.Conflicts) != 0 {
+		t.Errorf("MergeStructs: got unexpected conflicts: %v", r.Conflicts)
+	}
+}
+
 func TestValidateMap(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,211 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openconfig/ygot/util"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// TrackerOpt is an interface implemented by options to NewTracker.
+type TrackerOpt interface {
+	// IsTrackerOpt is a marker method for each TrackerOpt.
+	IsTrackerOpt()
+}
+
+// TrackerPreferShadowPath specifies that a Tracker should use the
+// "shadow-path" tags of the fields of the GoStruct to determine the paths of
+// the leaves that are tracked, rather than the "path" tags, where both are
+// present.
+type TrackerPreferShadowPath struct{}
+
+// IsTrackerOpt marks TrackerPreferShadowPath as a valid TrackerOpt.
+func (*TrackerPreferShadowPath) IsTrackerOpt() {}
+
+// TrackerSuppressionWindow specifies that a Tracker should suppress the
+// re-emission of the unchanged value of a leaf only for a limited window after
+// the value was last emitted, rather than indefinitely. This supports
+// collectors that expect the values of leaves to be refreshed periodically,
+// as with the heartbeat interval of ON_CHANGE subscriptions. An unchanged
+// value is re-emitted when either of the limits that are set is reached.
+type TrackerSuppressionWindow struct {
+	// Duration is the time, as measured by the timestamps supplied to
+	// Update, after which an unchanged value is re-emitted. It is not used
+	// if zero.
+	Duration time.Duration
+	// Count is the number of successive snapshots in which an unchanged
+	// value is suppressed before it is re-emitted. It is not used if zero.
+	Count int
+}
+
+// IsTrackerOpt marks TrackerSuppressionWindow as a valid TrackerOpt.
+func (*TrackerSuppressionWindow) IsTrackerOpt() {}
+
+// trackedLeaf is the state that a Tracker holds for a leaf that was
+// populated in the last snapshot.
+type trackedLeaf struct {
+	// path is the path of the leaf.
+	path *gnmipb.Path
+	// hash is the hash of the value of the leaf.
+	hash uint64
+	// emitted is the timestamp of the snapshot in which the value of the
+	// leaf was last emitted.
+	emitted int64
+	// suppressed is the number of successive snapshots in which the value
+	// of the leaf has been suppressed since it was last emitted.
+	suppressed int
+}
+
+// Tracker generates gNMI Notifications for the changes between successive
+// snapshots of a data tree, such as those polled from a device or an
+// emulator. Rather than retaining the previous snapshot and computing its
+// Diff with the next, the Tracker holds the path of each populated leaf of
+// the previous snapshot along with a hash of its value, such that each
+// snapshot is walked once, and is not retained by the Tracker.
+//
+// A Tracker is safe for concurrent use, although snapshots are processed one
+// at a time. The zero value is not usable; trackers are created with
+// NewTracker.
+type Tracker struct {
+	mu       sync.Mutex
+	walkOpts []WalkOpt
+	// window is the window within which unchanged values are suppressed,
+	// or nil if they are suppressed indefinitely.
+	window *TrackerSuppressionWindow
+	// leaves are the leaves populated in the last snapshot, keyed by the
+	// string form of their path.
+	leaves map[string]*trackedLeaf
+}
+
+// NewTracker returns a new Tracker, configured with the supplied options,
+// for which no snapshot has yet been processed.
+func NewTracker(opts ...TrackerOpt) *Tracker {
+	t := &Tracker{leaves: map[string]*trackedLeaf{}}
+	for _, o := range opts {
+		switch v := o.(type) {
+		case *TrackerPreferShadowPath:
+			t.walkOpts = append(t.walkOpts, &WalkPreferShadowPath{})
+		case *TrackerSuppressionWindow:
+			t.window = v
+		}
+	}
+	return t
+}
+
+// refresh reports whether the unchanged value of the leaf l should be
+// re-emitted in the snapshot with timestamp ts.
+func (t *Tracker) refresh(l *trackedLeaf, ts int64) bool {
+	if t.window == nil {
+		return false
+	}
+	return (t.window.Duration > 0 && ts-l.emitted >= t.window.Duration.Nanoseconds()) ||
+		(t.window.Count > 0 && l.suppressed >= t.window.Count)
+}
+
+// Update processes the snapshot s, returning a gNMI Notification with the
+// supplied timestamp that contains an update for each leaf and leaf-list of s
+// that was not populated in the previous snapshot, or whose value has
+// changed, and a delete for each leaf that was populated in the previous
+// snapshot but is not populated in s. For the first snapshot processed by the
+// Tracker, the Notification contains all populated leaves of s. If there are
+// no changes, a nil Notification is returned. Where the Tracker is configured
+// with TrackerSuppressionWindow, the Notification also contains an update for
+// each unchanged leaf whose value was last emitted outside of the window.
+//
+// The paths of the updates and deletes are absolute, relative to s, and
+// values are encoded as described by EncodeTypedValue with PROTO encoding.
+// Updates are ordered as the leaves are visited by Walk, and deletes are
+// ordered by their path. Changes in the order of the entries of ordered
+// lists that do not change the values of their leaves are not reported.
+// Values are compared by a hash, such that a change to a leaf may, although
+// it is very unlikely, be missed where the hashes of the old and new values
+// collide.
+//
+// If an error is returned, the state of the Tracker is unchanged, such that
+// the next snapshot is compared with the last that was successfully
+// processed.
+func (t *Tracker) Update(s GoStruct, ts int64) (*gnmipb.Notification, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := &gnmipb.Notification{Timestamp: ts}
+	leaves := make(map[string]*trackedLeaf, len(t.leaves))
+	err := Walk(s, func(path *gnmipb.Path, node any) error {
+		switch node.(type) {
+		case GoStruct, GoOrderedMap:
+			return nil
+		}
+		v := reflect.ValueOf(node)
+		if util.IsValueMap(v) || (util.IsValueSlice(v) && util.IsTypeStructPtr(v.Type().Elem())) {
+			// Lists are represented by their entries.
+			return nil
+		}
+		p, err := PathToString(path)
+		if err != nil {
+			return err
+		}
+		tv, err := EncodeTypedValue(node, gnmipb.Encoding_PROTO)
+		if err != nil {
+			return fmt.Errorf("cannot encode value at %s: %v", p, err)
+		}
+		val, err := fingerprintTypedValue(tv)
+		if err != nil {
+			return fmt.Errorf("cannot encode value at %s: %v", p, err)
+		}
+		l := &trackedLeaf{path: path, hash: fingerprintHash(val), emitted: ts}
+		leaves[p] = l
+		if prev, ok := t.leaves[p]; ok && prev.hash == l.hash && !t.refresh(prev, ts) {
+			l.emitted, l.suppressed = prev.emitted, prev.suppressed+1
+			return nil
+		}
+		n.Update = append(n.Update, &gnmipb.Update{Path: path, Val: tv})
+		return nil
+	}, t.walkOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot process snapshot: %v", err)
+	}
+
+	var deleted []string
+	for p := range t.leaves {
+		if _, ok := leaves[p]; !ok {
+			deleted = append(deleted, p)
+		}
+	}
+	sort.Strings(deleted)
+	for _, p := range deleted {
+		n.Delete = append(n.Delete, t.leaves[p].path)
+	}
+
+	t.leaves = leaves
+	if len(n.Update) == 0 && len(n.Delete) == 0 {
+		return nil, nil
+	}
+	return n, nil
+}
+
+// Reset discards the state of the Tracker, such that the next snapshot that
+// is processed is treated as the first.
+func (t *Tracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.leaves = map[string]*trackedLeaf{}
+}
@@ -0,0 +1,217 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestTracker(t *testing.T) {
+	path := func(s string) *gnmipb.Path {
+		return &gnmipb.Path{Elem: mustPathElem(s)}
+	}
+	strUpd := func(p, val string) *gnmipb.Update {
+		return &gnmipb.Update{Path: path(p), Val: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: val}}}
+	}
+	uintUpd := func(p string, val uint64) *gnmipb.Update {
+		return &gnmipb.Update{Path: path(p), Val: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_UintVal{UintVal: val}}}
+	}
+	decimalUpd := func(p string, digits int64, precision uint32) *gnmipb.Update {
+		return &gnmipb.Update{Path: path(p), Val: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_DecimalVal{DecimalVal: &gnmipb.Decimal64{Digits: digits, Precision: precision}}}}
+	}
+
+	type step struct {
+		desc             string
+		inStruct         GoStruct
+		inReset          bool
+		want             *gnmipb.Notification
+		wantErrSubstring string
+	}
+	tests := []struct {
+		name   string
+		inOpts []TrackerOpt
+		steps  []step
+	}{{
+		name: "successive snapshots",
+		steps: []step{{
+			desc:     "first snapshot contains all leaves",
+			inStruct: &renderExample{Str: String("foo"), Ch: &renderExampleChild{Val: Uint64(1)}},
+			want: &gnmipb.Notification{
+				Timestamp: 1,
+				Update:    []*gnmipb.Update{strUpd("/str", "foo"), uintUpd("/ch/val", 1)},
+			},
+		}, {
+			desc:     "unchanged snapshot",
+			inStruct: &renderExample{Str: String("foo"), Ch: &renderExampleChild{Val: Uint64(1)}},
+		}, {
+			desc:     "changed and added leaves",
+			inStruct: &renderExample{Str: String("foo"), EnumField: EnumTestVALONE, Ch: &renderExampleChild{Val: Uint64(2)}},
+			want: &gnmipb.Notification{
+				Timestamp: 3,
+				Update:    []*gnmipb.Update{strUpd("/enum", "VAL_ONE"), uintUpd("/ch/val", 2)},
+			},
+		}, {
+			desc:     "deleted leaves",
+			inStruct: &renderExample{EnumField: EnumTestVALONE},
+			want: &gnmipb.Notification{
+				Timestamp: 4,
+				Delete:    []*gnmipb.Path{path("/ch/val"), path("/str")},
+			},
+		}, {
+			desc:     "reset",
+			inStruct: &renderExample{EnumField: EnumTestVALONE},
+			inReset:  true,
+			want: &gnmipb.Notification{
+				Timestamp: 5,
+				Update:    []*gnmipb.Update{strUpd("/enum", "VAL_ONE")},
+			},
+		}},
+	}, {
+		name:   "prefer shadow path",
+		inOpts: []TrackerOpt{&TrackerPreferShadowPath{}},
+		steps: []step{{
+			desc:     "first snapshot",
+			inStruct: &renderExample{Str: String("foo")},
+			want: &gnmipb.Notification{
+				Timestamp: 1,
+				Update:    []*gnmipb.Update{strUpd("/srt", "foo")},
+			},
+		}},
+	}, {
+		name:   "suppression window duration",
+		inOpts: []TrackerOpt{&TrackerSuppressionWindow{Duration: 3}},
+		steps: []step{{
+			desc:     "first snapshot",
+			inStruct: &renderExample{Str: String("foo")},
+			want: &gnmipb.Notification{
+				Timestamp: 1,
+				Update:    []*gnmipb.Update{strUpd("/str", "foo")},
+			},
+		}, {
+			desc:     "unchanged within window",
+			inStruct: &renderExample{Str: String("foo"), Ch: &renderExampleChild{Val: Uint64(1)}},
+			want: &gnmipb.Notification{
+				Timestamp: 2,
+				Update:    []*gnmipb.Update{uintUpd("/ch/val", 1)},
+			},
+		}, {
+			desc:     "unchanged within window again",
+			inStruct: &renderExample{Str: String("foo"), Ch: &renderExampleChild{Val: Uint64(1)}},
+		}, {
+			desc:     "unchanged outside of window is re-emitted",
+			inStruct: &renderExample{Str: String("foo"), Ch: &renderExampleChild{Val: Uint64(1)}},
+			want: &gnmipb.Notification{
+				Timestamp: 4,
+				Update:    []*gnmipb.Update{strUpd("/str", "foo")},
+			},
+		}, {
+			desc:     "window restarts after re-emission",
+			inStruct: &renderExample{Str: String("foo"), Ch: &renderExampleChild{Val: Uint64(1)}},
+			want: &gnmipb.Notification{
+				Timestamp: 5,
+				Update:    []*gnmipb.Update{uintUpd("/ch/val", 1)},
+			},
+		}},
+	}, {
+		name:   "suppression window count",
+		inOpts: []TrackerOpt{&TrackerSuppressionWindow{Count: 1}},
+		steps: []step{{
+			desc:     "first snapshot",
+			inStruct: &renderExample{Str: String("foo")},
+			want: &gnmipb.Notification{
+				Timestamp: 1,
+				Update:    []*gnmipb.Update{strUpd("/str", "foo")},
+			},
+		}, {
+			desc:     "unchanged snapshot is suppressed",
+			inStruct: &renderExample{Str: String("foo")},
+		}, {
+			desc:     "unchanged snapshot after count is re-emitted",
+			inStruct: &renderExample{Str: String("foo")},
+			want: &gnmipb.Notification{
+				Timestamp: 3,
+				Update:    []*gnmipb.Update{strUpd("/str", "foo")},
+			},
+		}, {
+			desc:     "count restarts after re-emission",
+			inStruct: &renderExample{Str: String("foo")},
+		}},
+	}, {
+		name: "decimal64 leaf",
+		steps: []step{{
+			desc:     "first snapshot",
+			inStruct: &decimalExample{Val: Decimal64{Digits: 150, Precision: 2}},
+			want: &gnmipb.Notification{
+				Timestamp: 1,
+				Update:    []*gnmipb.Update{decimalUpd("/val", 150, 2)},
+			},
+		}, {
+			desc:     "equal value with different precision",
+			inStruct: &decimalExample{Val: Decimal64{Digits: 15, Precision: 1}},
+		}, {
+			desc:     "changed value",
+			inStruct: &decimalExample{Val: Decimal64{Digits: 151, Precision: 2}},
+			want: &gnmipb.Notification{
+				Timestamp: 3,
+				Update:    []*gnmipb.Update{decimalUpd("/val", 151, 2)},
+			},
+		}},
+	}, {
+		name: "state unchanged after error",
+		steps: []step{{
+			desc:     "first snapshot",
+			inStruct: &renderExample{Str: String("foo")},
+			want: &gnmipb.Notification{
+				Timestamp: 1,
+				Update:    []*gnmipb.Update{strUpd("/str", "foo")},
+			},
+		}, {
+			desc:             "invalid snapshot",
+			inStruct:         &renderExample{Str: String("bar"), UnionVal: &renderExampleUnionInvalid{String: "baz"}},
+			wantErrSubstring: "cannot process snapshot",
+		}, {
+			desc:     "compared with last valid snapshot",
+			inStruct: &renderExample{Str: String("bar")},
+			want: &gnmipb.Notification{
+				Timestamp: 3,
+				Update:    []*gnmipb.Update{strUpd("/str", "bar")},
+			},
+		}},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewTracker(tt.inOpts...)
+			for i, s := range tt.steps {
+				if s.inReset {
+					tr.Reset()
+				}
+				got, err := tr.Update(s.inStruct, int64(i+1))
+				if diff := errdiff.Substring(err, s.wantErrSubstring); diff != "" {
+					t.Fatalf("%s: Update: did not get expected error, %s", s.desc, diff)
+				}
+				if diff := cmp.Diff(s.want, got, protocmp.Transform()); diff != "" {
+					t.Errorf("%s: Update: did not get expected Notification, diff(-want,+got):\n%s", s.desc, diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// DecodeTypedValue decodes the supplied scalar gNMI TypedValue into a value of
+// type T, which must be the Go type used for a leaf or leaf-list within a
+// generated GoStruct. Supported types are strings, booleans, integers,
+// floating point numbers, Decimal64 values, binary values, GoEnums, and slices
+// of these types for leaf-lists. Union types are not supported, since the type of the value
+// cannot be determined without the schema.
+//
+// Unsigned integer values may be supplied as a TypedValue of any integer
+// type. The value may also be JSON or RFC7951 JSON encoded, in which case
+// 64-bit integers and decimal values may be encoded as JSON strings.
+//
+// Since the number of fraction digits of a decimal64 leaf is part of its
+// schema, a Decimal64 is decoded with the precision of the supplied value,
+// with trailing fraction zeros removed, or a precision of 1 for integral
+// values.
+//
+// An error is returned if the TypedValue cannot be represented as T, e.g., if
+// it is of an incompatible type or it overflows T.
+func DecodeTypedValue[T any](tv *gnmipb.TypedValue) (T, error) {
+	var t T
+	if err := decodeTypedValue(reflect.ValueOf(&t).Elem(), tv); err != nil {
+		return t, err
+	}
+	return t, nil
+}
+
+// decodeTypedValue decodes the supplied TypedValue into the settable value v.
+func decodeTypedValue(v reflect.Value, tv *gnmipb.TypedValue) error {
+	if tv == nil || tv.GetValue() == nil {
+		return fmt.Errorf("cannot decode nil TypedValue into %v", v.Type())
+	}
+
+	switch j := tv.GetValue().(type) {
+	case *gnmipb.TypedValue_JsonIetfVal:
+		return decodeJSONTypedValue(v, j.JsonIetfVal)
+	case *gnmipb.TypedValue_JsonVal:
+		return decodeJSONTypedValue(v, j.JsonVal)
+	}
+
+	if _, ok := v.Interface().(GoEnum); ok {
+		return decodeEnumTypedValue(v, tv)
+	}
+
+	mismatch := func() error {
+		return fmt.Errorf("cannot decode TypedValue of type %T into %v", tv.GetValue(), v.Type())
+	}
+
+	if v.Type() == reflect.TypeOf(Decimal64{}) {
+		var s string
+		switch dv := tv.GetValue().(type) {
+		case *gnmipb.TypedValue_DecimalVal:
+			//lint:ignore SA1019 Decimal64 is supported for backwards compatibility.
+			p := dv.DecimalVal.GetPrecision()
+			if p > maxDecimal64Precision {
+				return fmt.Errorf("cannot decode TypedValue into %v: invalid decimal64 precision %d", v.Type(), p)
+			}
+			// The canonical form of the value has no trailing fraction
+			// zeros.
+			//lint:ignore SA1019 Decimal64 is supported for backwards compatibility.
+			s = Decimal64{Digits: dv.DecimalVal.GetDigits(), Precision: uint8(p)}.String()
+		case *gnmipb.TypedValue_DoubleVal:
+			s = strconv.FormatFloat(dv.DoubleVal, 'f', -1, 64)
+		default:
+			return mismatch()
+		}
+		d, err := parseDecimal64Value(s)
+		if err != nil {
+			return fmt.Errorf("cannot decode TypedValue into %v: %v", v.Type(), err)
+		}
+		v.Set(reflect.ValueOf(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		s, ok := tv.GetValue().(*gnmipb.TypedValue_StringVal)
+		if !ok {
+			return mismatch()
+		}
+		v.SetString(s.StringVal)
+	case reflect.Bool:
+		b, ok := tv.GetValue().(*gnmipb.TypedValue_BoolVal)
+		if !ok {
+			return mismatch()
+		}
+		v.SetBool(b.BoolVal)
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := tv.GetValue().(*gnmipb.TypedValue_IntVal)
+		if !ok {
+			return mismatch()
+		}
+		if v.OverflowInt(i.IntVal) {
+			return fmt.Errorf("value %d overflows %v", i.IntVal, v.Type())
+		}
+		v.SetInt(i.IntVal)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		switch uv := tv.GetValue().(type) {
+		case *gnmipb.TypedValue_UintVal:
+			u = uv.UintVal
+		case *gnmipb.TypedValue_IntVal:
+			// Some implementations send unsigned values as signed
+			// integers.
+			if uv.IntVal < 0 {
+				return fmt.Errorf("negative value %d cannot be decoded into %v", uv.IntVal, v.Type())
+			}
+			u = uint64(uv.IntVal)
+		default:
+			return mismatch()
+		}
+		if v.OverflowUint(u) {
+			return fmt.Errorf("value %d overflows %v", u, v.Type())
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch fv := tv.GetValue().(type) {
+		case *gnmipb.TypedValue_DoubleVal:
+			f = fv.DoubleVal
+		case *gnmipb.TypedValue_FloatVal:
+			f = float64(fv.FloatVal)
+		case *gnmipb.TypedValue_DecimalVal:
+			//lint:ignore SA1019 Decimal64 is supported for backwards compatibility.
+			f = float64(fv.DecimalVal.Digits) / math.Pow(10, float64(fv.DecimalVal.Precision))
+		default:
+			return mismatch()
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b, ok := tv.GetValue().(*gnmipb.TypedValue_BytesVal)
+			if !ok {
+				return mismatch()
+			}
+			v.SetBytes(b.BytesVal)
+			return nil
+		}
+		ll, ok := tv.GetValue().(*gnmipb.TypedValue_LeaflistVal)
+		if !ok {
+			return mismatch()
+		}
+		elems := reflect.MakeSlice(v.Type(), len(ll.LeaflistVal.GetElement()), len(ll.LeaflistVal.GetElement()))
+		for i, e := range ll.LeaflistVal.GetElement() {
+			if err := decodeTypedValue(elems.Index(i), e); err != nil {
+				return fmt.Errorf("leaf-list element %d: %w", i, err)
+			}
+		}
+		v.Set(elems)
+	default:
+		return fmt.Errorf("cannot decode TypedValue into unsupported type %v", v.Type())
+	}
+	return nil
+}
+
+// parseDecimal64Value parses the decimal number s into a Decimal64 whose
+// precision is the number of significant fraction digits of s, or 1 if s is
+// integral.
+func parseDecimal64Value(s string) (Decimal64, error) {
+	_, frac, _ := strings.Cut(s, ".")
+	p := len(strings.TrimRight(frac, "0"))
+	switch {
+	case p < 1:
+		p = 1
+	case p > maxDecimal64Precision:
+		p = maxDecimal64Precision
+	}
+	return ParseDecimal64(s, uint8(p))
+}
+
+// decodeEnumTypedValue decodes the supplied TypedValue, which must be a string
+// containing the YANG name of an enumerated value, into the settable GoEnum v.
+// The name may be prefixed by the name of the module that defines the value.
+func decodeEnumTypedValue(v reflect.Value, tv *gnmipb.TypedValue) error {
+	s, ok := tv.GetValue().(*gnmipb.TypedValue_StringVal)
+	if !ok {
+		return fmt.Errorf("cannot decode TypedValue of type %T into enumerated type %v", tv.GetValue(), v.Type())
+	}
+	lookup, ok := v.Interface().(GoEnum).ΛMap()[v.Type().Name()]
+	if !ok {
+		return ClassifyError(ErrUnknownEnumValue, fmt.Errorf("cannot decode enumerated value as type %s was unknown", v.Type().Name()))
+	}
+	name := s.StringVal
+	if i := strings.Index(name, ":"); i != -1 {
+		name = name[i+1:]
+	}
+	for val, def := range lookup {
+		if def.Name == name {
+			v.SetInt(val)
+			return nil
+		}
+	}
+	return ClassifyError(ErrUnknownEnumValue, fmt.Errorf("%q is not a valid value for enumerated type %s", s.StringVal, v.Type().Name()))
+}
+
+// decodeJSONTypedValue decodes the supplied JSON or RFC7951 JSON encoded value
+// into the settable value v.
+func decodeJSONTypedValue(v reflect.Value, b []byte) error {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var j any
+	if err := d.Decode(&j); err != nil {
+		return fmt.Errorf("cannot unmarshal JSON value %s: %v", b, err)
+	}
+	tv, err := jsonToTypedValue(v.Type(), j)
+	if err != nil {
+		return err
+	}
+	return decodeTypedValue(v, tv)
+}
+
+// jsonToTypedValue converts the unmarshalled JSON value j, whose numbers are
+// represented as json.Number, into a scalar TypedValue that can be decoded
+// into a value of type t.
+func jsonToTypedValue(t reflect.Type, j any) (*gnmipb.TypedValue, error) {
+	mismatch := func() error {
+		return fmt.Errorf("cannot decode JSON value %v (%T) into %v", j, j, t)
+	}
+
+	if _, ok := reflect.Zero(t).Interface().(GoEnum); ok {
+		s, ok := j.(string)
+		if !ok {
+			return nil, mismatch()
+		}
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: s}}, nil
+	}
+
+	// number returns the string representation of j if it is a JSON
+	// number, or a string, since RFC7951 encodes 64-bit integers and
+	// decimal values as strings.
+	number := func() (string, error) {
+		switch n := j.(type) {
+		case json.Number:
+			return n.String(), nil
+		case string:
+			return n, nil
+		}
+		return "", mismatch()
+	}
+
+	if t == reflect.TypeOf(Decimal64{}) {
+		n, err := number()
+		if err != nil {
+			return nil, err
+		}
+		d, err := parseDecimal64Value(n)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode JSON value %v into %v: %v", j, t, err)
+		}
+		return decimal64TypedValue(d), nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		s, ok := j.(string)
+		if !ok {
+			return nil, mismatch()
+		}
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: s}}, nil
+	case reflect.Bool:
+		b, ok := j.(bool)
+		if !ok {
+			return nil, mismatch()
+		}
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_BoolVal{BoolVal: b}}, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := number()
+		if err != nil {
+			return nil, err
+		}
+		i, err := strconv.ParseInt(n, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode JSON value %v into %v: %v", j, t, err)
+		}
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_IntVal{IntVal: i}}, nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := number()
+		if err != nil {
+			return nil, err
+		}
+		u, err := strconv.ParseUint(n, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode JSON value %v into %v: %v", j, t, err)
+		}
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_UintVal{UintVal: u}}, nil
+	case reflect.Float32, reflect.Float64:
+		n, err := number()
+		if err != nil {
+			return nil, err
+		}
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode JSON value %v into %v: %v", j, t, err)
+		}
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_DoubleVal{DoubleVal: f}}, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			s, ok := j.(string)
+			if !ok {
+				return nil, mismatch()
+			}
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return nil, fmt.Errorf("cannot decode JSON value %v into %v: %v", j, t, err)
+			}
+			return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_BytesVal{BytesVal: b}}, nil
+		}
+		elems, ok := j.([]any)
+		if !ok {
+			return nil, mismatch()
+		}
+		ll := &gnmipb.ScalarArray{}
+		for i, e := range elems {
+			tv, err := jsonToTypedValue(t.Elem(), e)
+			if err != nil {
+				return nil, fmt.Errorf("leaf-list element %d: %w", i, err)
+			}
+			ll.Element = append(ll.Element, tv)
+		}
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_LeaflistVal{LeaflistVal: ll}}, nil
+	}
+	return nil, fmt.Errorf("cannot decode TypedValue into unsupported type %v", t)
+}
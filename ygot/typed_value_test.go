@@ -0,0 +1,265 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// decodeTypedValueTest is a test case for DecodeTypedValue.
+type decodeTypedValueTest[T any] struct {
+	desc             string
+	in               *gnmipb.TypedValue
+	want             T
+	wantErrSubstring string
+}
+
+func runDecodeTypedValueTests[T any](t *testing.T, tests []decodeTypedValueTest[T]) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := DecodeTypedValue[T](tt.in)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("DecodeTypedValue(%v): %s", tt.in, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("DecodeTypedValue(%v): did not get expected value, (-want, +got):\n%s", tt.in, diff)
+			}
+		})
+	}
+}
+
+func TestDecodeTypedValue(t *testing.T) {
+	runDecodeTypedValueTests(t, []decodeTypedValueTest[string]{{
+		desc: "string",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: "eth0"}},
+		want: "eth0",
+	}, {
+		desc: "string from JSON_IETF",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"eth0"`)}},
+		want: "eth0",
+	}, {
+		desc:             "string from int",
+		in:               &gnmipb.TypedValue{Value: &gnmipb.TypedValue_IntVal{IntVal: 42}},
+		wantErrSubstring: "cannot decode TypedValue of type *gnmi.TypedValue_IntVal into string",
+	}, {
+		desc:             "nil value",
+		wantErrSubstring: "cannot decode nil TypedValue",
+	}})
+
+	runDecodeTypedValueTests(t, []decodeTypedValueTest[bool]{{
+		desc: "bool",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_BoolVal{BoolVal: true}},
+		want: true,
+	}, {
+		desc: "bool from JSON_IETF",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte("true")}},
+		want: true,
+	}, {
+		desc:             "invalid JSON_IETF",
+		in:               &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte("{")}},
+		wantErrSubstring: "cannot unmarshal JSON value",
+	}})
+
+	runDecodeTypedValueTests(t, []decodeTypedValueTest[int8]{{
+		desc: "int8",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_IntVal{IntVal: -42}},
+		want: -42,
+	}, {
+		desc:             "int8 overflow",
+		in:               &gnmipb.TypedValue{Value: &gnmipb.TypedValue_IntVal{IntVal: 128}},
+		wantErrSubstring: "value 128 overflows int8",
+	}})
+
+	runDecodeTypedValueTests(t, []decodeTypedValueTest[uint16]{{
+		desc: "uint16",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_UintVal{UintVal: 1500}},
+		want: 1500,
+	}, {
+		desc:             "uint16 overflow",
+		in:               &gnmipb.TypedValue{Value: &gnmipb.TypedValue_UintVal{UintVal: 65536}},
+		wantErrSubstring: "value 65536 overflows uint16",
+	}, {
+		desc: "uint16 from int",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_IntVal{IntVal: 1500}},
+		want: 1500,
+	}, {
+		desc:             "uint16 from negative int",
+		in:               &gnmipb.TypedValue{Value: &gnmipb.TypedValue_IntVal{IntVal: -1}},
+		wantErrSubstring: "negative value -1 cannot be decoded into uint16",
+	}, {
+		desc:             "uint16 from int overflow",
+		in:               &gnmipb.TypedValue{Value: &gnmipb.TypedValue_IntVal{IntVal: 65536}},
+		wantErrSubstring: "value 65536 overflows uint16",
+	}, {
+		desc: "uint16 from JSON_IETF",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte("1500")}},
+		want: 1500,
+	}, {
+		desc:             "uint16 from JSON_IETF overflow",
+		in:               &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte("65536")}},
+		wantErrSubstring: "value 65536 overflows uint16",
+	}, {
+		desc:             "uint16 from JSON_IETF string",
+		in:               &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"abc"`)}},
+		wantErrSubstring: "cannot decode JSON value abc into uint16",
+	}})
+
+	runDecodeTypedValueTests(t, []decodeTypedValueTest[uint64]{{
+		desc: "uint64 from JSON_IETF string",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"18446744073709551615"`)}},
+		want: 18446744073709551615,
+	}})
+
+	runDecodeTypedValueTests(t, []decodeTypedValueTest[int64]{{
+		desc: "int64 from JSON_IETF string",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"-9223372036854775808"`)}},
+		want: -9223372036854775808,
+	}, {
+		desc: "int64 from JSON",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonVal{JsonVal: []byte("-42")}},
+		want: -42,
+	}})
+
+	runDecodeTypedValueTests(t, []decodeTypedValueTest[float64]{{
+		desc: "double",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_DoubleVal{DoubleVal: 4.2}},
+		want: 4.2,
+	}, {
+		desc: "decimal64",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_DecimalVal{DecimalVal: &gnmipb.Decimal64{Digits: 425, Precision: 2}}},
+		want: 4.25,
+	}, {
+		desc: "decimal64 from JSON_IETF",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"4.25"`)}},
+		want: 4.25,
+	}})
+
+	runDecodeTypedValueTests(t, []decodeTypedValueTest[Decimal64]{{
+		desc: "decimal64 into Decimal64",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_DecimalVal{DecimalVal: &gnmipb.Decimal64{Digits: -1250, Precision: 3}}},
+		want: Decimal64{Digits: -125, Precision: 2},
+	}, {
+		desc: "integral decimal64 into Decimal64",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_DecimalVal{DecimalVal: &gnmipb.Decimal64{Digits: 42, Precision: 0}}},
+		want: Decimal64{Digits: 420, Precision: 1},
+	}, {
+		desc:             "decimal64 with invalid precision into Decimal64",
+		in:               &gnmipb.TypedValue{Value: &gnmipb.TypedValue_DecimalVal{DecimalVal: &gnmipb.Decimal64{Digits: 42, Precision: 19}}},
+		wantErrSubstring: "invalid decimal64 precision 19",
+	}, {
+		desc: "double into Decimal64",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_DoubleVal{DoubleVal: 4.25}},
+		want: Decimal64{Digits: 425, Precision: 2},
+	}, {
+		desc: "Decimal64 from JSON_IETF string",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"4.250"`)}},
+		want: Decimal64{Digits: 425, Precision: 2},
+	}, {
+		desc: "Decimal64 from JSON number",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonVal{JsonVal: []byte("-7")}},
+		want: Decimal64{Digits: -70, Precision: 1},
+	}, {
+		desc:             "Decimal64 from invalid JSON_IETF string",
+		in:               &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"4.2.5"`)}},
+		wantErrSubstring: "not a valid decimal number",
+	}, {
+		desc:             "string into Decimal64",
+		in:               &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: "4.25"}},
+		wantErrSubstring: "cannot decode TypedValue of type *gnmi.TypedValue_StringVal",
+	}})
+
+	runDecodeTypedValueTests(t, []decodeTypedValueTest[[]Decimal64]{{
+		desc: "leaf-list into Decimal64 slice",
+		in: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_LeaflistVal{LeaflistVal: &gnmipb.ScalarArray{Element: []*gnmipb.TypedValue{
+			{Value: &gnmipb.TypedValue_DecimalVal{DecimalVal: &gnmipb.Decimal64{Digits: 15, Precision: 1}}},
+			{Value: &gnmipb.TypedValue_DoubleVal{DoubleVal: 2}},
+		}}}},
+		want: []Decimal64{{Digits: 15, Precision: 1}, {Digits: 20, Precision: 1}},
+	}, {
+		desc: "leaf-list into Decimal64 slice from JSON_IETF",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`["1.5", "0.25"]`)}},
+		want: []Decimal64{{Digits: 15, Precision: 1}, {Digits: 25, Precision: 2}},
+	}})
+
+	runDecodeTypedValueTests(t, []decodeTypedValueTest[Binary]{{
+		desc: "binary",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_BytesVal{BytesVal: []byte("abc")}},
+		want: Binary("abc"),
+	}, {
+		desc: "binary from JSON_IETF",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"YWJj"`)}},
+		want: Binary("abc"),
+	}})
+
+	runDecodeTypedValueTests(t, []decodeTypedValueTest[EnumTest]{{
+		desc: "enum",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: "VAL_ONE"}},
+		want: EnumTestVALONE,
+	}, {
+		desc: "enum with module prefix",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: "foo:VAL_TWO"}},
+		want: EnumTestVALTWO,
+	}, {
+		desc:             "unknown enum value",
+		in:               &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: "VAL_THREE"}},
+		wantErrSubstring: `"VAL_THREE" is not a valid value for enumerated type EnumTest`,
+	}, {
+		desc: "enum from JSON_IETF with module prefix",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"foo:VAL_TWO"`)}},
+		want: EnumTestVALTWO,
+	}})
+
+	runDecodeTypedValueTests(t, []decodeTypedValueTest[[]uint32]{{
+		desc: "leaf-list",
+		in: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_LeaflistVal{LeaflistVal: &gnmipb.ScalarArray{
+			Element: []*gnmipb.TypedValue{
+				{Value: &gnmipb.TypedValue_UintVal{UintVal: 1}},
+				{Value: &gnmipb.TypedValue_UintVal{UintVal: 2}},
+			},
+		}}},
+		want: []uint32{1, 2},
+	}, {
+		desc: "leaf-list with invalid element",
+		in: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_LeaflistVal{LeaflistVal: &gnmipb.ScalarArray{
+			Element: []*gnmipb.TypedValue{
+				{Value: &gnmipb.TypedValue_StringVal{StringVal: "one"}},
+			},
+		}}},
+		wantErrSubstring: "leaf-list element 0",
+	}, {
+		desc: "leaf-list from JSON_IETF",
+		in:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte("[1, 2]")}},
+		want: []uint32{1, 2},
+	}, {
+		desc:             "leaf-list from JSON_IETF with invalid element",
+		in:               &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`[1, true]`)}},
+		wantErrSubstring: "leaf-list element 1",
+	}})
+
+	runDecodeTypedValueTests(t, []decodeTypedValueTest[map[string]string]{{
+		desc:             "unsupported type",
+		in:               &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: "a"}},
+		wantErrSubstring: "unsupported type",
+	}})
+}
@@ -59,11 +59,15 @@ type ValidatedGoStruct interface {
 
 // ValidateGoStruct validates a GoStruct.
 func ValidateGoStruct(goStruct GoStruct, vopts ...ValidationOption) error {
-	vroot, ok := goStruct.(validatedGoStruct)
-	if !ok {
-		return fmt.Errorf("GoStruct cannot be validated: (%T, %v)", goStruct, goStruct)
+	switch vroot := goStruct.(type) {
+	case validatedGoStruct:
+		return vroot.ΛValidate(vopts...)
+	case legacyValidatedGoStruct:
+		// GoStructs generated by older versions of ygot implement
+		// only Validate.
+		return vroot.Validate(vopts...)
 	}
-	return vroot.ΛValidate(vopts...)
+	return fmt.Errorf("GoStruct cannot be validated: (%T, %v): %w", goStruct, goStruct, missingMethod(goStruct, "ΛValidate"))
 }
 
 // validatedGoStruct is an interface used for validating GoStructs.
@@ -194,3 +198,38 @@ type Annotation interface {
 	// the json.Unmarshaler interface is implemented.
 	UnmarshalJSON([]byte) error
 }
+
+// BinaryValue is an interface implemented by user-specified types that are
+// used in place of the generated Binary type for leaves with a YANG type of
+// binary, such as a type which retains the base64 encoding of the value and
+// decodes it on demand. The zero value of the type indicates that the leaf
+// is not set, and hence the type must be comparable.
+type BinaryValue interface {
+	// YANGBinary returns the binary value of the leaf.
+	YANGBinary() ([]byte, error)
+}
+
+// BinaryValueSetter is an interface implemented by pointers to
+// user-specified types that are used for leaves with a YANG type of binary,
+// such that the value of the leaf can be unmarshalled.
+type BinaryValueSetter interface {
+	// SetYANGBinary sets the binary value of the leaf.
+	SetYANGBinary([]byte) error
+}
+
+// EmptyValue is an interface implemented by user-specified types that are
+// used in place of the generated YANGEmpty type for leaves with a YANG type
+// of empty. The zero value of the type indicates that the leaf is not set,
+// and hence the type must be comparable.
+type EmptyValue interface {
+	// YANGEmpty returns true if the empty leaf is present.
+	YANGEmpty() bool
+}
+
+// EmptyValueSetter is an interface implemented by pointers to
+// user-specified types that are used for leaves with a YANG type of empty,
+// such that the presence of the leaf can be unmarshalled.
+type EmptyValueSetter interface {
+	// SetYANGEmpty sets whether the empty leaf is present.
+	SetYANGEmpty(bool)
+}
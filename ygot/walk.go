@@ -0,0 +1,263 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/openconfig/ygot/internal/yreflect"
+	"github.com/openconfig/ygot/util"
+	"google.golang.org/protobuf/proto"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// SkipSubtree is used as a return value from a WalkFunc to indicate that the
+// descendants of the node that the function was called for are not to be
+// visited. It is not returned as an error by Walk.
+var SkipSubtree = errors.New("skip subtree")
+
+// WalkFunc is the type of the function called by Walk for each populated node
+// of a GoStruct. The path is the gNMI path of the node relative to the
+// GoStruct supplied to Walk, and node is the value of the node:
+//
+//   - a GoStruct for containers and list entries,
+//   - the map, GoOrderedMap or slice representing a list,
+//   - the field value for leaves and leaf-lists, or the value of the
+//     union for union leaves,
+//   - the slice of Annotations for annotation fields.
+//
+// If the function returns SkipSubtree, the descendants of the node are not
+// visited. If it returns any other error, the walk is stopped and the error
+// is returned by Walk. The path must not be modified by the function.
+type WalkFunc func(path *gnmipb.Path, node any) error
+
+// WalkOpt is an interface implemented by options to Walk.
+type WalkOpt interface {
+	// IsWalkOpt is a marker method for each WalkOpt.
+	IsWalkOpt()
+}
+
+// WalkPreferShadowPath specifies that Walk should use the "shadow-path" tags
+// of the fields of the GoStruct to determine the paths of nodes, rather than
+// the "path" tags, where both are present.
+type WalkPreferShadowPath struct{}
+
+// IsWalkOpt marks WalkPreferShadowPath as a valid WalkOpt.
+func (*WalkPreferShadowPath) IsWalkOpt() {}
+
+// WalkAnnotations specifies that Walk should visit populated annotation
+// fields. The path of an annotation is the path within its "path" tag, e.g.,
+// "@annotation", such that the last element of the path is prefixed with "@".
+// By default, annotation fields are skipped.
+type WalkAnnotations struct{}
+
+// IsWalkOpt marks WalkAnnotations as a valid WalkOpt.
+func (*WalkAnnotations) IsWalkOpt() {}
+
+// walkCfg stores the options specified to Walk.
+type walkCfg struct {
+	preferShadowPath bool
+	annotations      bool
+}
+
+// Walk traverses the GoStruct s, calling fn for s itself, with an empty path,
+// and then for each of its populated nodes in depth-first order. Fields are
+// visited in the order in which they are defined within their GoStruct, and
+// list entries are visited in the order of their paths, or for ordered lists
+// and keyless lists, in the order of the list.
+//
+// Fields that are mapped to more than one path, as is the case for list keys
+// in compressed GoStructs, are visited once for each path. Entries of keyless
+// lists are identified by their position, as described by
+// KeylessListIndexKey.
+func Walk(s GoStruct, fn WalkFunc, opts ...WalkOpt) error {
+	cfg := &walkCfg{}
+	for _, o := range opts {
+		switch o.(type) {
+		case *WalkPreferShadowPath:
+			cfg.preferShadowPath = true
+		case *WalkAnnotations:
+			cfg.annotations = true
+		}
+	}
+
+	if util.IsValueNil(s) {
+		return nil
+	}
+	return walkNode(&gnmipb.Path{}, s, fn, func() error {
+		return walkStruct(reflect.ValueOf(s), &gnmipb.Path{}, fn, cfg)
+	})
+}
+
+// walkNode calls fn for the node at path, and then calls descend to visit the
+// node's descendants unless fn returns SkipSubtree.
+func walkNode(path *gnmipb.Path, node any, fn WalkFunc, descend func() error) error {
+	switch err := fn(path, node); {
+	case errors.Is(err, SkipSubtree):
+		return nil
+	case err != nil:
+		return err
+	}
+	if descend == nil {
+		return nil
+	}
+	return descend()
+}
+
+// walkStruct visits the populated fields of the struct pointer v, which is at
+// the path parent.
+func walkStruct(v reflect.Value, parent *gnmipb.Path, fn WalkFunc, cfg *walkCfg) error {
+	if !util.IsValueStructPtr(v) {
+		return fmt.Errorf("%v: got %T, want struct pointer", parent, v.Interface())
+	}
+	sv := v.Elem()
+	for i := 0; i < sv.NumField(); i++ {
+		fv, ft := sv.Field(i), sv.Type().Field(i)
+		if util.IsNilOrInvalidValue(fv) || util.IsValueNilOrDefault(fv.Interface()) {
+			continue
+		}
+		if util.IsYgotAnnotation(ft) && !cfg.annotations {
+			continue
+		}
+
+		var paths [][]string
+		if cfg.preferShadowPath {
+			paths = util.ShadowSchemaPaths(ft)
+		}
+		if len(paths) == 0 {
+			var err error
+			if paths, err = util.SchemaPaths(ft); err != nil {
+				return fmt.Errorf("%v: %v", parent, err)
+			}
+		}
+
+		for _, p := range paths {
+			fieldPath := proto.Clone(parent).(*gnmipb.Path)
+			for _, e := range p {
+				fieldPath.Elem = append(fieldPath.Elem, &gnmipb.PathElem{Name: e})
+			}
+			if err := walkField(fv, ft, fieldPath, fn, cfg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// walkField visits the populated field value fv, described by ft, which is
+// at the supplied path.
+func walkField(fv reflect.Value, ft reflect.StructField, path *gnmipb.Path, fn WalkFunc, cfg *walkCfg) error {
+	if util.IsYgotAnnotation(ft) {
+		return walkNode(path, fv.Interface(), fn, nil)
+	}
+
+	if om, ok := fv.Interface().(GoOrderedMap); ok {
+		return walkNode(path, om, fn, func() error {
+			var err error
+			if rerr := yreflect.RangeOrderedMap(om, func(_, v reflect.Value) bool {
+				var entryPath *gnmipb.Path
+				if entryPath, err = listEntryPath(v, path); err == nil {
+					err = walkListEntry(v, entryPath, fn, cfg)
+				}
+				return err == nil
+			}); rerr != nil {
+				return rerr
+			}
+			return err
+		})
+	}
+
+	switch {
+	case util.IsValueMap(fv):
+		return walkNode(path, fv.Interface(), fn, func() error {
+			type entry struct {
+				v    reflect.Value
+				path *gnmipb.Path
+				str  string
+			}
+			var entries []*entry
+			for _, k := range fv.MapKeys() {
+				v := fv.MapIndex(k)
+				if v.IsNil() {
+					continue
+				}
+				entryPath, err := listEntryPath(v, path)
+				if err != nil {
+					return err
+				}
+				str, err := PathToString(entryPath)
+				if err != nil {
+					return fmt.Errorf("%v: %v", path, err)
+				}
+				entries = append(entries, &entry{v: v, path: entryPath, str: str})
+			}
+			sort.Slice(entries, func(i, j int) bool { return entries[i].str < entries[j].str })
+			for _, e := range entries {
+				if err := walkListEntry(e.v, e.path, fn, cfg); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case util.IsValueSlice(fv) && util.IsTypeStructPtr(fv.Type().Elem()):
+		return walkNode(path, fv.Interface(), fn, func() error {
+			for i := 0; i < fv.Len(); i++ {
+				if fv.Index(i).IsNil() {
+					continue
+				}
+				entryPath := proto.Clone(path).(*gnmipb.Path)
+				entryPath.Elem[len(entryPath.Elem)-1].Key = map[string]string{keylessListIndexKey(ft): strconv.Itoa(i)}
+				if err := walkListEntry(fv.Index(i), entryPath, fn, cfg); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case fv.Kind() == reflect.Interface:
+		// Union leaves are represented as interfaces, whose value may be
+		// a struct pointer in the case of wrapper unions.
+		return walkNode(path, fv.Elem().Interface(), fn, nil)
+	case util.IsValueStructPtr(fv):
+		return walkNode(path, fv.Interface(), fn, func() error {
+			return walkStruct(fv, path, fn, cfg)
+		})
+	default:
+		return walkNode(path, fv.Interface(), fn, nil)
+	}
+}
+
+// listEntryPath returns the path of the keyed list entry v of the list at
+// path.
+func listEntryPath(v reflect.Value, path *gnmipb.Path) (*gnmipb.Path, error) {
+	keys, err := PathKeyFromStruct(v)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	entryPath := proto.Clone(path).(*gnmipb.Path)
+	entryPath.Elem[len(entryPath.Elem)-1].Key = keys
+	return entryPath, nil
+}
+
+// walkListEntry visits the list entry v, which is at path.
+func walkListEntry(v reflect.Value, path *gnmipb.Path, fn WalkFunc, cfg *walkCfg) error {
+	return walkNode(path, v.Interface(), fn, func() error {
+		return walkStruct(v, path, fn, cfg)
+	})
+}
@@ -0,0 +1,204 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestWalk(t *testing.T) {
+	walkExample := &renderExample{
+		Str:       String("hello"),
+		EnumField: EnumTestVALONE,
+		Ch:        &renderExampleChild{Val: Uint64(42)},
+		LeafList:  []string{"one", "two"},
+		List: map[uint32]*renderExampleList{
+			2: {Val: String("two")},
+			1: {Val: String("one")},
+		},
+		UnionVal: &renderExampleUnionString{"union"},
+		KeylessList: []*renderExampleList{
+			{Val: String("first")},
+			nil,
+			{Val: String("third")},
+		},
+	}
+
+	tests := []struct {
+		desc             string
+		in               GoStruct
+		inOpts           []WalkOpt
+		inSkip           string
+		inErr            string
+		want             []string
+		wantErrSubstring string
+	}{{
+		desc: "all node types",
+		in:   walkExample,
+		want: []string{
+			"/",
+			"/str",
+			"/enum",
+			"/ch",
+			"/ch/val",
+			"/leaf-list",
+			"/list",
+			"/list[val=one]",
+			"/list[val=one]/val",
+			"/list[val=one]/state/val",
+			"/list[val=two]",
+			"/list[val=two]/val",
+			"/list[val=two]/state/val",
+			"/union-val",
+			"/keyless-list",
+			"/keyless-list[_index=0]",
+			"/keyless-list[_index=0]/val",
+			"/keyless-list[_index=0]/state/val",
+			"/keyless-list[_index=2]",
+			"/keyless-list[_index=2]/val",
+			"/keyless-list[_index=2]/state/val",
+		},
+	}, {
+		desc: "skip subtrees",
+		in: &renderExample{
+			Ch: &renderExampleChild{Val: Uint64(42)},
+			List: map[uint32]*renderExampleList{
+				1: {Val: String("one")},
+			},
+		},
+		inSkip: "/list",
+		want: []string{
+			"/",
+			"/ch",
+			"/ch/val",
+			"/list",
+		},
+	}, {
+		desc:   "shadow paths",
+		in:     &renderExample{Str: String("hello"), IntVal: Int32(42)},
+		inOpts: []WalkOpt{&WalkPreferShadowPath{}},
+		want: []string{
+			"/",
+			"/srt",
+			"/int-val",
+		},
+	}, {
+		desc: "annotations skipped by default",
+		in: &annotatedJSONTestStruct{
+			Field:  String("hello"),
+			ΛField: []Annotation{&testAnnotation{AnnotationFieldOne: "world"}},
+		},
+		want: []string{
+			"/",
+			"/field",
+		},
+	}, {
+		desc: "annotations",
+		in: &annotatedJSONTestStruct{
+			Field:       String("hello"),
+			ΛField:      []Annotation{&testAnnotation{AnnotationFieldOne: "world"}},
+			ΛFieldThree: []Annotation{&testAnnotation{AnnotationFieldOne: "three"}},
+		},
+		inOpts: []WalkOpt{&WalkAnnotations{}},
+		want: []string{
+			"/",
+			"/field",
+			"/@field",
+			"/@one",
+			"/config/@two",
+		},
+	}, {
+		desc:  "error from function",
+		in:    walkExample,
+		inErr: "/ch/val",
+		want: []string{
+			"/",
+			"/str",
+			"/enum",
+			"/ch",
+			"/ch/val",
+		},
+		wantErrSubstring: "stop",
+	}, {
+		desc: "nil struct",
+		in:   (*renderExample)(nil),
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var got []string
+			err := Walk(tt.in, func(path *gnmipb.Path, node any) error {
+				p, err := PathToString(path)
+				if err != nil {
+					t.Fatalf("cannot convert path %v to string: %v", path, err)
+				}
+				got = append(got, p)
+				switch p {
+				case tt.inSkip:
+					return SkipSubtree
+				case tt.inErr:
+					return errors.New("stop")
+				}
+				return nil
+			}, tt.inOpts...)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("Walk: %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Walk: did not visit expected paths (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWalkNodes(t *testing.T) {
+	in := &renderExample{
+		Ch:       &renderExampleChild{Val: Uint64(42)},
+		UnionVal: &renderExampleUnionString{"union"},
+	}
+	got := map[string]any{}
+	if err := Walk(in, func(path *gnmipb.Path, node any) error {
+		p, err := PathToString(path)
+		if err != nil {
+			return err
+		}
+		got[p] = node
+		return nil
+	}); err != nil {
+		t.Fatalf("Walk: got unexpected error: %v", err)
+	}
+
+	want := map[string]any{
+		"/":          in,
+		"/ch":        in.Ch,
+		"/ch/val":    in.Ch.Val,
+		"/union-val": in.UnionVal,
+	}
+	if len(got) != len(want) {
+		t.Errorf("Walk: got %d nodes, want %d", len(got), len(want))
+	}
+	for p, w := range want {
+		// The nodes must be the values within the GoStruct, rather than copies.
+		if got[p] != w {
+			t.Errorf("Walk: got node %v for path %q, want %v", got[p], p, w)
+		}
+	}
+}
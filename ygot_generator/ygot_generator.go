@@ -0,0 +1,297 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary ygot_generator generates GoStructs, path structs, and Protobuf3
+// messages corresponding to an input YANG schema in a single invocation. The
+// options that determine the shape of the generated code are specified once,
+// and are used for all outputs, such that the outputs are consistent with one
+// another.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/golang/glog"
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/genutil"
+	"github.com/openconfig/ygot/gogen"
+	"github.com/openconfig/ygot/protogen"
+	"github.com/openconfig/ygot/ygen"
+	"github.com/openconfig/ygot/ygotgen"
+	"github.com/openconfig/ygot/ypathgen"
+)
+
+var (
+	// Flags used for all outputs.
+	yangPaths                            = flag.String("path", "", "Comma separated list of paths to be recursively searched for included modules or submodules within the defined YANG modules.")
+	excludeModules                       = flag.String("exclude_modules", "", "Comma separated set of module names that should be excluded from code generation. This can be used to ensure overlapping namespaces can be ignored.")
+	includeSubtrees                      = flag.String("include_subtrees", "", "Comma separated set of uncompressed schema paths, without module prefixes, of the subtrees for which code should be generated. When set, code is only generated for these subtrees and their ancestors.")
+	excludeSubtrees                      = flag.String("exclude_subtrees", "", "Comma separated set of uncompressed schema paths, without module prefixes, of the subtrees that should be excluded from code generation.")
+	deviationModules                     = flag.String("deviation_modules", "", "Comma separated set of YANG files containing deviations, such as those supplied by a vendor, that should be applied to the input modules. Code is not generated for the deviation modules themselves.")
+	ignoreCircDeps                       = flag.Bool("ignore_circdeps", false, "If set to true, circular dependencies between submodules are ignored.")
+	ignoreUnsupportedStatements          = flag.Bool("ignore_unsupported", false, "If set to true, unsupported YANG statements are ignored.")
+	compressPaths                        = flag.Bool("compress_paths", true, "If set to true, the schema's paths are compressed, according to OpenConfig YANG module conventions. Path struct generation requires compressed paths.")
+	excludeState                         = flag.Bool("exclude_state", false, "If set to true, state (config false) fields in the YANG schema are not included in the generated code.")
+	preferOperationalState               = flag.Bool("prefer_operational_state", false, "If set to true, state (config false) fields in the YANG schema are preferred over intended config leaves in the generated code with compressed schema paths. This flag is only valid for compress_paths=true and exclude_state=false.")
+	generateFakeRoot                     = flag.Bool("generate_fakeroot", true, "If set to true, a fake element at the root of the data tree is generated. Path struct generation requires the fake root.")
+	fakeRootName                         = flag.String("fakeroot_name", "device", "The name of the fake root entity.")
+	skipEnumDedup                        = flag.Bool("skip_enum_deduplication", false, "If set to true, all leaves of type enumeration will have a unique enum output for them, rather than sharing a common type (default behaviour).")
+	shortenEnumLeafNames                 = flag.Bool("shorten_enum_leaf_names", false, "If also set to true when compress_paths=true, all leaves of type enumeration will by default not be prefixed with the name of its residing module.")
+	trimEnumOpenConfigPrefix             = flag.Bool("trim_enum_openconfig_prefix", false, `If set to true when compress_paths=true, the organizational prefix "openconfig-" is trimmed from the module part of the name of enumerated names in the generated code.`)
+	useDefiningModuleForTypedefEnumNames = flag.Bool("typedef_enum_with_defmod", false, "If set to true, all typedefs of type enumeration or identity will be prefixed with the name of its module of definition instead of its residing module.")
+	appendEnumSuffixForSimpleUnionEnums  = flag.Bool("enum_suffix_for_simple_union_enums", false, "If set to true when typedef_enum_with_defmod is also true, all inlined enumerations within unions will be suffixed with \"Enum\", instead of adding the suffix only for inlined enumerations within typedef unions.")
+	callerName                           = flag.String("caller_name", "ygot_generator", "The name of the generator binary that should be recorded in output files.")
+
+	// Flags used for GoStruct and path struct generation.
+	goOutputFile         = flag.String("go_output_file", "", "If set, GoStructs are generated and written to this file. Specify \"-\" for stdout.")
+	pathOutputFile       = flag.String("path_structs_output_file", "", "If set, path structs are generated and written to this file. Specify \"-\" for stdout.")
+	packageName          = flag.String("package_name", "ocstructs", "The name of the Go package that the GoStructs belong to.")
+	pathPackageName      = flag.String("path_package_name", "", "The name of the Go package that the path structs belong to. If unset, the path structs are generated in the same package as the GoStructs.")
+	schemaStructPath     = flag.String("schema_struct_path", "", "The Go import path of the GoStructs package, used by the path structs. This should be specified if and only if the path structs are in a different package to the GoStructs.")
+	generateSchema       = flag.Bool("include_schema", true, "If set to true, the YANG schema will be encoded as JSON and stored in the generated GoStruct code.")
+	includeDescriptions  = flag.Bool("include_descriptions", false, "If set to true when include_schema=true, the YANG descriptions will be included in the generated code artefact.")
+	generateSimpleUnions = flag.Bool("generate_simple_unions", true, "If set to true, then generated typedefs will be used to represent union subtypes within Go code instead of wrapper struct types.")
+	generateGetters      = flag.Bool("generate_getters", false, "If set to true, getter methods that retrieve or create an element are generated for YANG container (Go struct pointer) or list (Go map) fields within the generated code.")
+	generateLeafGetters  = flag.Bool("generate_leaf_getters", false, "If set to true, getters for YANG leaves are generated within the Go code.")
+	generateLeafSetters  = flag.Bool("generate_leaf_setters", false, "If set to true, setters for YANG leaves are generated within the Go code.")
+	generateAppend       = flag.Bool("generate_append", false, "If set to true, append methods are generated for YANG lists (Go maps) within the Go code.")
+	generateDelete       = flag.Bool("generate_delete", false, "If set to true, delete methods are generated for YANG lists (Go maps) within the Go code.")
+	generateOrderedMaps  = flag.Bool("generate_ordered_maps", true, "If set to true, ordered map structures satisfying the interface ygot.GoOrderedMap will be generated for `ordered-by user` lists instead of Go built-in maps.")
+	ygotImportPath       = flag.String("ygot_path", genutil.GoDefaultYgotImportPath, "The import path to use for ygot.")
+	ytypesImportPath     = flag.String("ytypes_path", genutil.GoDefaultYtypesImportPath, "The import path to use for ytypes.")
+	goyangImportPath     = flag.String("goyang_path", genutil.GoDefaultGoyangImportPath, "The import path to use for goyang's yang package.")
+	generateWildcards    = flag.Bool("generate_wildcard_paths", true, "Whether to generate methods for constructing wildcard paths.")
+
+	// Flags used for Protobuf generation.
+	protoOutputDir      = flag.String("proto_output_dir", "", "If set, Protobuf messages are generated and written to this directory, within which hierarchical folders are created for the generated messages.")
+	protoPackageName    = flag.String("proto_package_name", protogen.DefaultBasePackageName, "The name of the Proto package that generated messages should belong to as their parent.")
+	protoEnumPackage    = flag.String("proto_enum_package_name", protogen.DefaultEnumPackageName, "The name of the package within the generated package that should contain global enum definitions.")
+	protoBaseImportPath = flag.String("proto_base_import_path", "", "The base import path that should be used for the generated Protobuf packages.")
+	protoGoPackageBase  = flag.String("proto_go_package_base", "", "Base name for the Go packages that are included in the go_package option of the generated protobufs.")
+	protoHierarchy      = flag.Bool("proto_package_hierarchy", false, "If set to true, an individual protobuf package is output per level of the YANG schema tree.")
+)
+
+// splitFlag returns the comma-separated elements of the supplied flag value,
+// or nil if it is empty.
+func splitFlag(v string) []string {
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+// main parses command-line flags to determine the set of YANG modules for
+// which code generation should be performed, and the outputs that should be
+// generated, and calls the ygotgen library to generate them.
+func main() {
+	flag.Parse()
+	generateModules := flag.Args()
+	if len(generateModules) == 0 {
+		log.Exitln("Error: no input modules specified")
+	}
+	if *goOutputFile == "" && *pathOutputFile == "" && *protoOutputDir == "" {
+		log.Exitln("Error: at least one of go_output_file, path_structs_output_file, or proto_output_dir must be specified")
+	}
+
+	var includePaths []string
+	for _, path := range splitFlag(*yangPaths) {
+		includePaths = append(includePaths, filepath.Join(path, "..."))
+	}
+
+	compressBehaviour, err := genutil.TranslateToCompressBehaviour(*compressPaths, *excludeState, *preferOperationalState)
+	if err != nil {
+		log.Exitf("Error: %v", err)
+	}
+	var enumOrgPrefixesToTrim []string
+	if *compressPaths && *trimEnumOpenConfigPrefix {
+		enumOrgPrefixesToTrim = []string{"openconfig"}
+	}
+
+	cfg := &ygotgen.Config{
+		Caller: *callerName,
+		ParseOptions: ygen.ParseOpts{
+			IgnoreUnsupportedStatements: *ignoreUnsupportedStatements,
+			ExcludeModules:              splitFlag(*excludeModules),
+			IncludeSubtrees:             splitFlag(*includeSubtrees),
+			ExcludeSubtrees:             splitFlag(*excludeSubtrees),
+			DeviationModules:            splitFlag(*deviationModules),
+			YANGParseOptions: yang.Options{
+				IgnoreSubmoduleCircularDependencies: *ignoreCircDeps,
+			},
+		},
+		TransformationOptions: ygen.TransformationOpts{
+			CompressBehaviour:                    compressBehaviour,
+			GenerateFakeRoot:                     *generateFakeRoot,
+			FakeRootName:                         *fakeRootName,
+			SkipEnumDeduplication:                *skipEnumDedup,
+			ShortenEnumLeafNames:                 *shortenEnumLeafNames,
+			EnumOrgPrefixesToTrim:                enumOrgPrefixesToTrim,
+			UseDefiningModuleForTypedefEnumNames: *useDefiningModuleForTypedefEnumNames,
+			EnumerationsUseUnderscores:           true,
+		},
+		AppendEnumSuffixForSimpleUnionEnums: *appendEnumSuffixForSimpleUnionEnums,
+	}
+
+	if *goOutputFile != "" {
+		cfg.GoOptions = &gogen.GoOpts{
+			PackageName:                         *packageName,
+			GenerateJSONSchema:                  *generateSchema,
+			IncludeDescriptions:                 *includeDescriptions,
+			YgotImportPath:                      *ygotImportPath,
+			YtypesImportPath:                    *ytypesImportPath,
+			GoyangImportPath:                    *goyangImportPath,
+			GenerateSimpleUnions:                *generateSimpleUnions,
+			GenerateGetters:                     *generateGetters,
+			GenerateLeafGetters:                 *generateLeafGetters,
+			GenerateLeafSetters:                 *generateLeafSetters,
+			GenerateAppendMethod:                *generateAppend,
+			GenerateDeleteMethod:                *generateDelete,
+			GenerateOrderedListsAsUnorderedMaps: !*generateOrderedMaps,
+		}
+	}
+
+	if *pathOutputFile != "" {
+		if (*pathPackageName == "" || *pathPackageName == *packageName) != (*schemaStructPath == "") {
+			log.Exitln("Error: schema_struct_path must be specified if and only if the path structs are in a different package to the GoStructs")
+		}
+		pathOpts := ypathgen.NewDefaultConfig(*schemaStructPath)
+		pathOpts.PackageName = *packageName
+		if *pathPackageName != "" {
+			pathOpts.PackageName = *pathPackageName
+		}
+		pathOpts.GoImports.YgotImportPath = *ygotImportPath
+		pathOpts.GeneratingBinary = *callerName
+		pathOpts.GenerateWildcardPaths = *generateWildcards
+		cfg.PathOptions = pathOpts
+	}
+
+	if *protoOutputDir != "" {
+		cfg.ProtoOptions = &protogen.ProtoOpts{
+			PackageName:         *protoPackageName,
+			EnumPackageName:     *protoEnumPackage,
+			BaseImportPath:      *protoBaseImportPath,
+			GoPackageBase:       *protoGoPackageBase,
+			YwrapperPath:        protogen.DefaultYwrapperPath,
+			YextPath:            protogen.DefaultYextPath,
+			AnnotateSchemaPaths: true,
+			AnnotateEnumNames:   true,
+			NestedMessages:      !*protoHierarchy,
+		}
+	}
+
+	code, errs := ygotgen.Generate(generateModules, includePaths, cfg)
+	if errs != nil {
+		log.Exitf("ERROR Generating Code: %v\n", errs)
+	}
+
+	if code.Structs != nil {
+		if err := writeOutput(*goOutputFile, func(w io.Writer) error { return writeGoCode(w, code.Structs) }); err != nil {
+			log.Exitf("ERROR writing GoStruct code: %v", err)
+		}
+	}
+	if code.Paths != nil {
+		pathCode, ok := code.Paths[cfg.PathOptions.PackageName]
+		if !ok || len(code.Paths) != 1 {
+			log.Exitf("ERROR writing path struct code: expected a single package %s, got %d packages", cfg.PathOptions.PackageName, len(code.Paths))
+		}
+		if err := writeOutput(*pathOutputFile, func(w io.Writer) error {
+			_, err := io.WriteString(w, pathCode.String())
+			return err
+		}); err != nil {
+			log.Exitf("ERROR writing path struct code: %v", err)
+		}
+	}
+	if code.Protos != nil {
+		if err := writeProtos(*protoOutputDir, code.Protos); err != nil {
+			log.Exitf("ERROR writing Protobuf code: %v", err)
+		}
+	}
+}
+
+// writeOutput calls write with a writer for the file fn, or os.Stdout if fn
+// is "-".
+func writeOutput(fn string, write func(io.Writer) error) error {
+	if fn == "-" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeGoCode writes the supplied generated GoStruct code to w as a single
+// Go source file.
+func writeGoCode(w io.Writer, goCode *gogen.GeneratedCode) error {
+	var b strings.Builder
+	b.WriteString(goCode.CommonHeader)
+	b.WriteString(goCode.OneOffHeader)
+	for _, snippet := range goCode.Structs {
+		fmt.Fprintln(&b, snippet.String())
+	}
+	for _, snippet := range goCode.Enums {
+		fmt.Fprintln(&b, snippet)
+	}
+	fmt.Fprintln(&b, goCode.EnumMap)
+	for _, s := range []string{goCode.JSONSchemaCode, goCode.EnumTypeMap, goCode.StructRegistry} {
+		if s != "" {
+			fmt.Fprintln(&b, s)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeProtos writes each of the supplied generated Protobuf packages to a
+// file within dir.
+func writeProtos(dir string, protos *protogen.GeneratedCode) error {
+	var names []string
+	for n := range protos.Packages {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		p := protos.Packages[n]
+		fp := filepath.Join(append([]string{dir}, p.FilePath[:len(p.FilePath)-1]...)...)
+		if err := os.MkdirAll(fp, 0755); err != nil {
+			return fmt.Errorf("could not create directory %s: %v", fp, err)
+		}
+
+		var b strings.Builder
+		b.WriteString(p.Header)
+		for _, m := range p.Messages {
+			fmt.Fprintln(&b, m)
+		}
+		for _, e := range p.Enums {
+			b.WriteString(e)
+		}
+		fn := filepath.Join(fp, p.FilePath[len(p.FilePath)-1])
+		if err := os.WriteFile(fn, []byte(b.String()), 0644); err != nil {
+			return fmt.Errorf("could not write file %s: %v", fn, err)
+		}
+	}
+	return nil
+}
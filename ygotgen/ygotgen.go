@@ -0,0 +1,171 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ygotgen provides a single entry point for generating GoStructs,
+// their JSON schema, path structs, and Protobuf messages for a set of YANG
+// modules. The options that determine the shape of the generated code, such
+// as the compression behaviour and the name of the fake root, are specified
+// once and used for every output, such that the outputs are consistent with
+// one another. The GoStructs and path structs are generated from a single
+// IR.
+package ygotgen
+
+import (
+	"errors"
+
+	"github.com/openconfig/ygot/gogen"
+	"github.com/openconfig/ygot/internal/igenutil"
+	"github.com/openconfig/ygot/protogen"
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygen"
+	"github.com/openconfig/ygot/ypathgen"
+)
+
+// Config specifies the outputs that should be generated, and the options
+// that are used to generate them.
+type Config struct {
+	// Caller is the name of the binary calling the generator library, which
+	// is recorded in the generated code.
+	Caller string
+	// ParseOptions specifies how the input YANG modules are parsed. It is
+	// used for all outputs.
+	ParseOptions ygen.ParseOpts
+	// TransformationOptions specifies the transformations of the schema,
+	// such as compression, that are applied when generating code. It is
+	// used for all outputs.
+	TransformationOptions ygen.TransformationOpts
+	// AppendEnumSuffixForSimpleUnionEnums appends an "Enum" suffix to the
+	// enumeration name for simple (i.e. non-typedef) leaves which are
+	// unions with an enumeration inside. It is used for the GoStructs and
+	// path structs, and overrides the corresponding field of GoOptions and
+	// PathOptions. Protobuf generation always appends the suffix.
+	AppendEnumSuffixForSimpleUnionEnums bool
+
+	// GoOptions specifies the options for generating GoStructs, and
+	// optionally their JSON schema. GoStructs are not generated if it is
+	// nil.
+	GoOptions *gogen.GoOpts
+	// GoPlugins are the plugins that emit additional code within the
+	// generated GoStructs. They are ignored if GoOptions is nil.
+	GoPlugins []gogen.Plugin
+	// PathOptions specifies the options for generating path structs.
+	// Path structs are not generated if it is nil. The fields of
+	// PathOptions that determine the generated IR, such as ExcludeState,
+	// FakeRootName and ExcludeModules, are ignored, and ParseOptions and
+	// TransformationOptions are used instead. Generating path structs
+	// requires compression and the fake root to be enabled.
+	PathOptions *ypathgen.GenConfig
+	// ProtoOptions specifies the options for generating Protobuf messages.
+	// Protobufs are not generated if it is nil. Since Protobuf messages
+	// are named differently to Go types, they are generated from a
+	// separate IR, which is generated using the same options.
+	ProtoOptions *protogen.ProtoOpts
+}
+
+// GeneratedCode contains the outputs of Generate. Each field is nil if the
+// corresponding output was not requested.
+type GeneratedCode struct {
+	// Structs is the generated GoStruct code, including its JSON schema
+	// if requested.
+	Structs *gogen.GeneratedCode
+	// Paths is the generated path struct code, keyed by Go package name.
+	Paths map[string]*ypathgen.GeneratedPathCode
+	// PathNodeData describes each node for which a path struct is
+	// generated.
+	PathNodeData ypathgen.NodeDataMap
+	// Protos is the generated Protobuf code.
+	Protos *protogen.GeneratedCode
+}
+
+// Generate generates the outputs requested by cfg for the YANG modules in
+// yangFiles, searching includePaths for any modules that they import or
+// include. The YANG modules are parsed, and an IR generated, once for all
+// Go outputs.
+func Generate(yangFiles, includePaths []string, cfg *Config) (*GeneratedCode, util.Errors) {
+	if cfg.GoOptions == nil && cfg.PathOptions == nil && cfg.ProtoOptions == nil {
+		return nil, util.NewErrs(errors.New("ygotgen: no outputs requested"))
+	}
+	if cfg.PathOptions != nil {
+		if !cfg.TransformationOptions.CompressBehaviour.CompressEnabled() {
+			return nil, util.NewErrs(errors.New("ygotgen: path struct generation requires compression to be enabled"))
+		}
+		if !cfg.TransformationOptions.GenerateFakeRoot {
+			return nil, util.NewErrs(errors.New("ygotgen: path struct generation requires the fake root to be generated"))
+		}
+	}
+
+	gc := &GeneratedCode{}
+	var errs util.Errors
+	if cfg.GoOptions != nil || cfg.PathOptions != nil {
+		// Path structs are generated such that they refer to the
+		// GoStructs' types, so the union representation of the
+		// GoStructs is used for both.
+		simpleUnions := true
+		if cfg.GoOptions != nil {
+			simpleUnions = cfg.GoOptions.GenerateSimpleUnions
+		}
+		ir, err := ygen.GenerateIR(yangFiles, includePaths, ypathgen.NewGoLangMapper(simpleUnions), ygen.IROptions{
+			ParseOptions:                        cfg.ParseOptions,
+			TransformationOptions:               cfg.TransformationOptions,
+			AppendEnumSuffixForSimpleUnionEnums: cfg.AppendEnumSuffixForSimpleUnionEnums,
+		})
+		if err != nil {
+			return nil, util.NewErrs(err)
+		}
+
+		if cfg.GoOptions != nil {
+			goOpts := *cfg.GoOptions
+			goOpts.AppendEnumSuffixForSimpleUnionEnums = cfg.AppendEnumSuffixForSimpleUnionEnums
+			goOpts.CapabilitiesWildcardPaths = cfg.PathOptions != nil && cfg.PathOptions.GenerateWildcardPaths
+			cg := gogen.New(cfg.Caller, ygen.IROptions{
+				ParseOptions:                        cfg.ParseOptions,
+				TransformationOptions:               cfg.TransformationOptions,
+				AppendEnumSuffixForSimpleUnionEnums: cfg.AppendEnumSuffixForSimpleUnionEnums,
+			}, goOpts)
+			cg.Plugins = cfg.GoPlugins
+			structs, es := cg.GenerateFromIR(ir, yangFiles, includePaths)
+			errs = util.AppendErrs(errs, es)
+			gc.Structs = structs
+		}
+
+		if cfg.PathOptions != nil {
+			pcg := *cfg.PathOptions
+			pcg.FakeRootName = cfg.TransformationOptions.FakeRootName
+			if pcg.FakeRootName == "" {
+				pcg.FakeRootName = igenutil.DefaultRootName
+			}
+			if cfg.Caller != "" {
+				pcg.GeneratingBinary = cfg.Caller
+			}
+			paths, nodeData, es := pcg.GeneratePathCodeFromIR(ir, yangFiles, includePaths)
+			errs = util.AppendErrs(errs, es)
+			gc.Paths, gc.PathNodeData = paths, nodeData
+		}
+	}
+
+	if cfg.ProtoOptions != nil {
+		cg := protogen.New(cfg.Caller, ygen.IROptions{
+			ParseOptions:          cfg.ParseOptions,
+			TransformationOptions: cfg.TransformationOptions,
+		}, *cfg.ProtoOptions)
+		protos, es := cg.Generate(yangFiles, includePaths)
+		errs = util.AppendErrs(errs, es)
+		gc.Protos = protos
+	}
+
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	return gc, nil
+}
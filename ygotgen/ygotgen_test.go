@@ -0,0 +1,145 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygotgen
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygot/genutil"
+	"github.com/openconfig/ygot/gogen"
+	"github.com/openconfig/ygot/protogen"
+	"github.com/openconfig/ygot/ygen"
+	"github.com/openconfig/ygot/ypathgen"
+)
+
+// datapath is the path to common YANG test modules.
+const datapath = "../testdata/modules"
+
+func TestGenerate(t *testing.T) {
+	yangFiles := []string{filepath.Join(datapath, "openconfig-withlist.yang")}
+	transformOpts := ygen.TransformationOpts{
+		CompressBehaviour:                    genutil.PreferIntendedConfig,
+		GenerateFakeRoot:                     true,
+		FakeRootName:                         "device",
+		ShortenEnumLeafNames:                 true,
+		UseDefiningModuleForTypedefEnumNames: true,
+		EnumerationsUseUnderscores:           true,
+	}
+	goOpts := gogen.GoOpts{
+		PackageName:          "ocstructs",
+		GenerateJSONSchema:   true,
+		GenerateSimpleUnions: true,
+	}
+	pathOpts := ypathgen.NewDefaultConfig("github.com/openconfig/ygot/ocstructs")
+	pathOpts.GeneratingBinary = "ygotgen-test"
+	protoOpts := protogen.ProtoOpts{}
+
+	// The outputs of Generate must be the same as those of each generator
+	// when they are configured with the same options.
+	wantStructs, errs := gogen.New("ygotgen-test", ygen.IROptions{TransformationOptions: transformOpts}, goOpts).Generate(yangFiles, nil)
+	if errs != nil {
+		t.Fatalf("gogen: cannot generate GoStructs: %v", errs)
+	}
+	separatePathOpts := *pathOpts
+	separatePathOpts.FakeRootName = "device"
+	separatePathOpts.ShortenEnumLeafNames = true
+	separatePathOpts.UseDefiningModuleForTypedefEnumNames = true
+	wantPaths, wantNodeData, errs := separatePathOpts.GeneratePathCode(yangFiles, nil)
+	if errs != nil {
+		t.Fatalf("ypathgen: cannot generate path structs: %v", errs)
+	}
+	wantProtos, errs := protogen.New("ygotgen-test", ygen.IROptions{TransformationOptions: transformOpts}, protoOpts).Generate(yangFiles, nil)
+	if errs != nil {
+		t.Fatalf("protogen: cannot generate protobufs: %v", errs)
+	}
+
+	tests := []struct {
+		desc             string
+		inConfig         *Config
+		want             *GeneratedCode
+		wantErrSubstring string
+	}{{
+		desc: "all outputs",
+		inConfig: &Config{
+			Caller:                "ygotgen-test",
+			TransformationOptions: transformOpts,
+			GoOptions:             &goOpts,
+			PathOptions:           pathOpts,
+			ProtoOptions:          &protoOpts,
+		},
+		want: &GeneratedCode{
+			Structs:      wantStructs,
+			Paths:        wantPaths,
+			PathNodeData: wantNodeData,
+			Protos:       wantProtos,
+		},
+	}, {
+		desc: "structs only",
+		inConfig: &Config{
+			Caller:                "ygotgen-test",
+			TransformationOptions: transformOpts,
+			GoOptions:             &goOpts,
+		},
+		want: &GeneratedCode{
+			Structs: wantStructs,
+		},
+	}, {
+		desc: "no outputs",
+		inConfig: &Config{
+			TransformationOptions: transformOpts,
+		},
+		wantErrSubstring: "no outputs requested",
+	}, {
+		desc: "paths without compression",
+		inConfig: &Config{
+			TransformationOptions: ygen.TransformationOpts{
+				GenerateFakeRoot: true,
+			},
+			PathOptions: pathOpts,
+		},
+		wantErrSubstring: "requires compression",
+	}, {
+		desc: "paths without fake root",
+		inConfig: &Config{
+			TransformationOptions: ygen.TransformationOpts{
+				CompressBehaviour: genutil.PreferIntendedConfig,
+			},
+			PathOptions: pathOpts,
+		},
+		wantErrSubstring: "requires the fake root",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, errs := Generate(yangFiles, nil, tt.inConfig)
+			var err error
+			if errs != nil {
+				err = errs
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("Generate: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Generate: did not get expected code (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -83,6 +83,7 @@ func NewDefaultConfig(schemaStructPkgPath string) *GenConfig {
 		GoImports: GoImports{
 			SchemaStructPkgPath: schemaStructPkgPath,
 			YgotImportPath:      genutil.GoDefaultYgotImportPath,
+			GNMIProtoPath:       genutil.GoDefaultGNMIImportPath,
 		},
 		FakeRootName:     defaultFakeRootName,
 		PathStructSuffix: defaultPathStructSuffix,
@@ -152,6 +153,18 @@ type GenConfig struct {
 	// code generation. This is due to the fact that some schemas (e.g., OpenConfig
 	// interfaces) currently result in overlapping entities (e.g., /interfaces).
 	ExcludeModules []string
+	// IncludeSubtrees specifies the schema paths of the subtrees of the
+	// schema for which path structs should be generated. See
+	// ygen.ParseOpts for details.
+	IncludeSubtrees []string
+	// ExcludeSubtrees specifies the schema paths of the subtrees of the
+	// schema for which path structs should not be generated. See
+	// ygen.ParseOpts for details.
+	ExcludeSubtrees []string
+	// DeviationModules specifies the files of YANG modules containing
+	// deviations that should be applied to the input modules. See
+	// ygen.ParseOpts for details.
+	DeviationModules []string
 	// YANGParseOptions provides the options that should be handed to the
 	// github.com/openconfig/goyang/pkg/yang library. These specify how the
 	// input YANG files should be parsed.
@@ -168,6 +181,28 @@ type GenConfig struct {
 	ListBuilderKeyThreshold uint
 	// GenerateWildcardPaths means to generate wildcard nodes and paths.
 	GenerateWildcardPaths bool
+	// GenerateExtractMethods means to generate an Extract method for each
+	// leaf path struct, which decodes a gNMI TypedValue received for the
+	// leaf into the Go type used for the leaf in the generated GoStructs.
+	// Extract methods are not generated for leaves of union type.
+	GenerateExtractMethods bool
+	// GenerateGoStructLinks means to generate a ΛGoStruct method for each
+	// container and list path struct, which links it to the type of the
+	// corresponding GoStruct, along with a compile-time assertion that the
+	// path struct implements ygot.GoStructPath for that type. This allows
+	// generic functions, such as ytypes.GetGoStruct, to return values of
+	// the correct type for a path struct.
+	GenerateGoStructLinks bool
+	// SkipConfigStatePaths disables the generation of Config and State
+	// methods for leaf path structs. When generated, these methods return
+	// the path of the leaf within the config or state container of its
+	// parent respectively, and are only generated for leaves that exist
+	// within both containers.
+	SkipConfigStatePaths bool
+	// GeneratePathRegistry means to populate the PathRegistry of each
+	// GeneratedPathCode with an entry for each generated path struct,
+	// describing its path, YANG type, and defaults.
+	GeneratePathRegistry bool
 	// SimplifyWildcardPaths causes non-builder-style generated wildcard
 	// nodes, where all key values are wildcards, to omit the [key="*"] in
 	// the generated path.
@@ -193,6 +228,12 @@ type GenConfig struct {
 	BaseImportPath string
 	// PackageString is the string to apppend to the generated Go package names.
 	PackageSuffix string
+	// CompactOutput generates all path structs into a single package, and
+	// documents each child path method with a single line rather than the
+	// full description of the YANG node. It is intended for small schemas,
+	// such as vendor modules, whose path structs are vendored into other
+	// repositories. It cannot be used with SplitByModule.
+	CompactOutput bool
 }
 
 // GoImports contains package import options.
@@ -204,6 +245,9 @@ type GoImports struct {
 	// YgotImportPath specifies the path to the ygot library that should be used
 	// in the generated code.
 	YgotImportPath string
+	// GNMIProtoPath specifies the path to the generated gNMI protobuf, which
+	// is used by the generated Extract methods.
+	GNMIProtoPath string
 }
 
 type goLangMapper struct {
@@ -246,16 +290,54 @@ func (cg *GenConfig) GeneratePathCode(yangFiles, includePaths []string) (map[str
 	// many ways in which compilation may fail, coupled with the plethora
 	// of configurations, means there is an argument to force the user to
 	// debug instead of making ypathgen having to catch every error.
+	opts, err := cg.irOptions()
+	if err != nil {
+		return nil, nil, util.NewErrs(err)
+	}
+
+	ir, err := ygen.GenerateIR(yangFiles, includePaths, NewGoLangMapper(true), opts)
+	if err != nil {
+		return nil, nil, util.NewErrs(err)
+	}
+
+	return cg.GeneratePathCodeFromIR(ir, yangFiles, includePaths)
+}
+
+// GeneratePathCodeFromEntries generates path structs for the supplied
+// modules, which have already been parsed by the caller, returning the same
+// values as GeneratePathCode. The modules must satisfy the requirements of
+// ygen.GenerateIRFromEntries, and are transformed in place such that they
+// must not be reused.
+func (cg *GenConfig) GeneratePathCodeFromEntries(modules []*yang.Entry) (map[string]*GeneratedPathCode, NodeDataMap, util.Errors) {
+	opts, err := cg.irOptions()
+	if err != nil {
+		return nil, nil, util.NewErrs(err)
+	}
+
+	ir, err := ygen.GenerateIRFromEntries(modules, NewGoLangMapper(true), opts)
+	if err != nil {
+		return nil, nil, util.NewErrs(err)
+	}
+
+	return cg.GeneratePathCodeFromIR(ir, nil, nil)
+}
+
+// irOptions returns the IROptions that are used to generate the IR from which
+// path structs are generated by cg.
+func (cg *GenConfig) irOptions() (ygen.IROptions, error) {
 	compressBehaviour, err := genutil.TranslateToCompressBehaviour(true, cg.ExcludeState, cg.PreferOperationalState)
 	if err != nil {
-		return nil, nil, util.NewErrs(fmt.Errorf("ypathgen: unable to translate compress behaviour: %v", err))
+		return ygen.IROptions{}, fmt.Errorf("ypathgen: unable to translate compress behaviour: %v", err)
 	}
 
-	opts := ygen.IROptions{
+	return ygen.IROptions{
 		ParseOptions: ygen.ParseOpts{
 			IgnoreUnsupportedStatements: cg.IgnoreUnsupportedStatements,
 			YANGParseOptions:            cg.YANGParseOptions,
 			ExcludeModules:              cg.ExcludeModules,
+			IncludeSubtrees:             cg.IncludeSubtrees,
+			ExcludeSubtrees:             cg.ExcludeSubtrees,
+			DeviationModules:            cg.DeviationModules,
 		},
 		TransformationOptions: ygen.TransformationOpts{
 			CompressBehaviour:                    compressBehaviour,
@@ -270,14 +352,34 @@ func (cg *GenConfig) GeneratePathCode(yangFiles, includePaths []string) (map[str
 		NestedDirectories:                   false,
 		AbsoluteMapPaths:                    false,
 		AppendEnumSuffixForSimpleUnionEnums: cg.AppendEnumSuffixForSimpleUnionEnums,
-	}
+	}, nil
+}
 
-	var errs util.Errors
-	ir, err := ygen.GenerateIR(yangFiles, includePaths, goLangMapper{GoLangMapper: gogen.NewGoLangMapper(true)}, opts)
-	if err != nil {
-		return nil, nil, util.AppendErr(errs, err)
+// NewGoLangMapper returns a LangMapper that produces the same names as
+// gogen.GoLangMapper, and additionally populates the field flags that are
+// required to generate path structs. simpleUnions specifies whether simple
+// unions are used in the corresponding generated GoStructs. The IR generated
+// using this LangMapper can be used to generate both GoStructs and path
+// structs.
+func NewGoLangMapper(simpleUnions bool) ygen.LangMapper {
+	return goLangMapper{GoLangMapper: gogen.NewGoLangMapper(simpleUnions)}
+}
+
+// GeneratePathCodeFromIR generates path structs for the supplied IR,
+// returning the same values as GeneratePathCode. It allows an IR that has
+// already been generated to be shared by multiple code generators. The IR
+// must have been generated using the LangMapper returned by NewGoLangMapper
+// with compression and the fake root enabled, and NestedDirectories and
+// AbsoluteMapPaths unset. The fields of cg that are used only to generate the
+// IR, such as ExcludeState and ExcludeModules, are ignored. yangFiles and
+// includePaths are the inputs from which the IR was generated, and are
+// recorded in the header of the generated code.
+func (cg *GenConfig) GeneratePathCodeFromIR(ir *ygen.IR, yangFiles, includePaths []string) (map[string]*GeneratedPathCode, NodeDataMap, util.Errors) {
+	if cg.CompactOutput && cg.SplitByModule {
+		return nil, nil, util.NewErrs(fmt.Errorf("CompactOutput cannot be used with SplitByModule"))
 	}
 
+	var errs util.Errors
 	var schemaStructPkgAccessor string
 	if cg.GoImports.SchemaStructPkgPath != "" {
 		schemaStructPkgAccessor = schemaStructPkgAlias + "."
@@ -294,12 +396,7 @@ func (cg *GenConfig) GeneratePathCode(yangFiles, includePaths []string) (map[str
 	for _, directoryPath := range ir.OrderedDirectoryPathsByName() {
 		directory := ir.Directories[directoryPath]
 
-		var listBuilderKeyThreshold uint
-		if cg.GenerateWildcardPaths {
-			listBuilderKeyThreshold = cg.ListBuilderKeyThreshold
-		}
-
-		structSnippet, es := generateDirectorySnippet(directory, ir.Directories, schemaStructPkgAccessor, cg.PathStructSuffix, listBuilderKeyThreshold, cg.GenerateWildcardPaths, cg.SimplifyWildcardPaths, cg.SplitByModule, cg.PackageName, cg.PackageSuffix, cg.TrimPackagePrefix)
+		structSnippet, es := generateDirectorySnippet(directory, ir.Directories, schemaStructPkgAccessor, cg)
 		if es != nil {
 			errs = util.AppendErrs(errs, es)
 		}
@@ -324,6 +421,18 @@ func (cg *GenConfig) GeneratePathCode(yangFiles, includePaths []string) (map[str
 		util.AppendErr(errs, err)
 	}
 
+	if cg.GeneratePathRegistry {
+		registry, es := getPathRegistry(ir, cg.PathStructSuffix, cg.PackageName, cg.PackageSuffix, cg.TrimPackagePrefix, cg.SplitByModule)
+		if es != nil {
+			errs = util.AppendErrs(errs, es)
+		}
+		for name, entries := range registry {
+			if p, ok := packages[name]; ok {
+				p.PathRegistry = entries
+			}
+		}
+	}
+
 	if len(errs) == 0 {
 		errs = nil
 	}
@@ -373,6 +482,7 @@ type GeneratedPathCode struct {
 	Structs      []GoPathStructCodeSnippet // Structs is the generated set of structs representing containers or lists in the input YANG models.
 	CommonHeader string                    // CommonHeader is the header that should be used for all output Go files.
 	Deps         map[string]bool           // Deps is the list of packages that this package depends on.
+	PathRegistry []*PathRegistryEntry      // PathRegistry describes each path struct in the package, if GeneratePathRegistry is set.
 }
 
 // String method for GeneratedPathCode, which can be used to write all the
@@ -534,6 +644,9 @@ import (
 	{{ .SchemaStructPkgAlias }} "{{ .SchemaStructPkgPath }}"
 	{{- end }}
 	"{{ .YgotImportPath }}"
+	{{- if .GenerateExtractMethods }}
+	gpb "{{ .GNMIProtoPath }}"
+	{{- end }}
 {{- range $import := .ExtraImports }}
 	"{{ $import }}"
 {{- end }}
@@ -585,12 +698,16 @@ type {{ .TypeName }}{{ .WildcardSuffix }} struct {
 	// for a generated struct by returning an instantiation of the child's
 	// path struct object.
 	goPathChildConstructorTemplate = mustTemplate("childConstructor", `
+{{ if .CompactDocs -}}
+// {{ .MethodName }} returns the path struct for {{ .AbsPath }}.
+{{- else -}}
 // {{ .MethodName }} ({{ .YANGNodeType }}): {{ .YANGDescription }}
 // ----------------------------------------
 // Defining module: "{{ .DefiningModuleName }}"
 // Instantiating module: "{{ .InstantiatingModuleName }}"
 // Path from parent: "{{ .RelPath }}"
 // Path from root: "{{ .AbsPath }}"
+{{- end }}
 {{- range $paramDocStr := .KeyParamDocStrs }}
 // {{ $paramDocStr }}
 {{- end }}
@@ -603,6 +720,86 @@ func (n *{{ .Struct.TypeName }}) {{ .MethodName -}} ({{ .KeyParamListStr }}) *{{
 		),
 	}
 }
+`)
+
+	// goPathExtractTemplate generates a method for a leaf path struct that
+	// decodes a gNMI TypedValue into the Go type of the leaf.
+	goPathExtractTemplate = mustTemplate("extract", `
+// Extract decodes the supplied gNMI TypedValue, received for the
+// {{ .YANGPath }} YANG schema element, into its Go type.
+func (n *{{ .TypeName }}) Extract(val *gpb.TypedValue) ({{ .GoTypeName }}, error) {
+	return ygot.DecodeTypedValue[{{ .GoTypeName }}](val)
+}
+{{- if .GenerateWildcardPaths }}
+
+// Extract decodes the supplied gNMI TypedValue, received for the
+// {{ .YANGPath }} YANG schema element, into its Go type.
+func (n *{{ .TypeName }}{{ .WildcardSuffix }}) Extract(val *gpb.TypedValue) ({{ .GoTypeName }}, error) {
+	return ygot.DecodeTypedValue[{{ .GoTypeName }}](val)
+}
+{{- end }}
+`)
+
+	// goPathGoStructTemplate generates a method for a container or list
+	// path struct that links it to the type of the corresponding GoStruct,
+	// and asserts that it implements ygot.GoStructPath for the type.
+	goPathGoStructTemplate = mustTemplate("goStruct", `
+// ΛGoStruct returns the nil value of the GoStruct type of the
+// {{ .YANGPath }} YANG schema element.
+func (n *{{ .TypeName }}) ΛGoStruct() {{ .GoTypeName }} {
+	return nil
+}
+
+var _ ygot.GoStructPath[{{ .GoTypeName }}] = (*{{ .TypeName }})(nil)
+{{- if .GenerateWildcardPaths }}
+
+// ΛGoStruct returns the nil value of the GoStruct type of the
+// {{ .YANGPath }} YANG schema element.
+func (n *{{ .TypeName }}{{ .WildcardSuffix }}) ΛGoStruct() {{ .GoTypeName }} {
+	return nil
+}
+
+var _ ygot.GoStructPath[{{ .GoTypeName }}] = (*{{ .TypeName }}{{ .WildcardSuffix }})(nil)
+{{- end }}
+`)
+
+	// goPathConfigStateTemplate generates methods for a leaf path struct
+	// that return the path of the leaf within the config and state
+	// containers of its parent.
+	goPathConfigStateTemplate = mustTemplate("configState", `
+// Config returns the path of the config version of the
+// {{ .YANGPath }} YANG schema element.
+func (n *{{ .TypeName }}) Config() *{{ .TypeName }} {
+	return &{{ .TypeName }}{
+		{{ .PathBaseTypeName }}: ygot.WithRelSchemaPath(n.{{ .PathBaseTypeName }}, []string{ {{- .ConfigRelPathList -}} }),
+	}
+}
+
+// State returns the path of the state version of the
+// {{ .YANGPath }} YANG schema element.
+func (n *{{ .TypeName }}) State() *{{ .TypeName }} {
+	return &{{ .TypeName }}{
+		{{ .PathBaseTypeName }}: ygot.WithRelSchemaPath(n.{{ .PathBaseTypeName }}, []string{ {{- .StateRelPathList -}} }),
+	}
+}
+{{- if .GenerateWildcardPaths }}
+
+// Config returns the path of the config version of the
+// {{ .YANGPath }} YANG schema element.
+func (n *{{ .TypeName }}{{ .WildcardSuffix }}) Config() *{{ .TypeName }}{{ .WildcardSuffix }} {
+	return &{{ .TypeName }}{{ .WildcardSuffix }}{
+		{{ .PathBaseTypeName }}: ygot.WithRelSchemaPath(n.{{ .PathBaseTypeName }}, []string{ {{- .ConfigRelPathList -}} }),
+	}
+}
+
+// State returns the path of the state version of the
+// {{ .YANGPath }} YANG schema element.
+func (n *{{ .TypeName }}{{ .WildcardSuffix }}) State() *{{ .TypeName }}{{ .WildcardSuffix }} {
+	return &{{ .TypeName }}{{ .WildcardSuffix }}{
+		{{ .PathBaseTypeName }}: ygot.WithRelSchemaPath(n.{{ .PathBaseTypeName }}, []string{ {{- .StateRelPathList -}} }),
+	}
+}
+{{- end }}
 `)
 
 	// goKeyBuilderTemplate generates a setter for a list key. This is used in the
@@ -614,6 +811,20 @@ func (n *{{ .TypeName }}) {{ .MethodName }}({{ .KeyParamName }} {{ .KeyParamType
 	ygot.ModifyKey(n.NodePath, "{{ .KeySchemaName }}", {{ .KeyParamName }})
 	return n
 }
+`)
+
+	// goKeyBuildTemplate generates a method that validates the keys set by
+	// the key setters of the builder style for the list API.
+	goKeyBuildTemplate = mustTemplate("goKeyBuild", `
+// Build returns n if each of {{ .TypeName }}'s keys is either a wildcard or
+// was set exactly once, before n was used to construct another path, and an
+// error otherwise.
+func (n *{{ .TypeName }}) Build() (*{{ .TypeName }}, error) {
+	if err := ygot.ValidateKeys(n.NodePath); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
 `)
 )
 
@@ -724,6 +935,7 @@ func writeHeader(yangFiles, includePaths []string, packageName string, cg *GenCo
 		PathStructInterfaceName string   // PathStructInterfaceName is the name of the interface which all path structs implement.
 		FakeRootTypeName        string   // FakeRootTypeName is the type name of the fakeroot node in the generated code.
 		ExtraImports            []string // ExtraImports for path structs that are in a different package.
+		GenerateExtractMethods  bool     // GenerateExtractMethods determines whether the gNMI protobuf package is imported.
 	}{
 		GoImports:               cg.GoImports,
 		PackageName:             packageName,
@@ -734,6 +946,10 @@ func writeHeader(yangFiles, includePaths []string, packageName string, cg *GenCo
 		PathBaseTypeName:        ygot.PathBaseTypeName,
 		PathStructInterfaceName: ygot.PathStructInterfaceName,
 		FakeRootTypeName:        yang.CamelCase(cg.FakeRootName),
+		GenerateExtractMethods:  cg.GenerateExtractMethods,
+	}
+	if s.GNMIProtoPath == "" {
+		s.GNMIProtoPath = genutil.GoDefaultGNMIImportPath
 	}
 	// Create an ordered list of imports to include in the header.
 	for dep := range genCode.Deps {
@@ -769,6 +985,11 @@ type goPathStructData struct {
 	WildcardSuffix string
 	// GenerateWildcardPaths means to generate wildcard nodes and paths.
 	GenerateWildcardPaths bool
+	// GoTypeName is the Go type of the node in the generated GoStructs,
+	// which is only populated for leaf path structs for which an Extract
+	// method is generated, and for container and list path structs for which
+	// a ΛGoStruct method is generated.
+	GoTypeName string
 }
 
 // getStructData returns the goPathStructData corresponding to a
@@ -804,6 +1025,7 @@ type goPathFieldData struct {
 	KeyEntriesStr           string           // KeyEntriesStr is an ordered list of comma-separated ("schemaName": unique camel-case name) for a list's keys.
 	KeyParamDocStrs         []string         // KeyParamDocStrs is an ordered slice of docstrings documenting the types of each list key parameter.
 	ChildPkgAccessor        string           // ChildPkgAccessor is used if the child path struct exists in another package.
+	CompactDocs             bool             // CompactDocs documents the accessor method with a single line.
 }
 
 // generateDirectorySnippet generates all Go code associated with a schema node
@@ -817,9 +1039,8 @@ type goPathFieldData struct {
 // The code comprises of the type definition for the struct, and all accessors to
 // the fields of the struct. directory is the parsed information of a schema
 // node, and directories is a map from path to a parsed schema node for all
-// directory nodes in the schema.
-func generateDirectorySnippet(directory *ygen.ParsedDirectory, directories map[string]*ygen.ParsedDirectory, schemaStructPkgAccessor, pathStructSuffix string, listBuilderKeyThreshold uint,
-	generateWildcardPaths, simplifyWildcardPaths, splitByModule bool, pkgName, pkgSuffix, trimPkgPrefix string) ([]GoPathStructCodeSnippet, util.Errors) {
+// directory nodes in the schema. cg determines the code that is generated.
+func generateDirectorySnippet(directory *ygen.ParsedDirectory, directories map[string]*ygen.ParsedDirectory, schemaStructPkgAccessor string, cg *GenConfig) ([]GoPathStructCodeSnippet, util.Errors) {
 
 	var errs util.Errors
 	// structBuf is used to store the code associated with the struct defined for
@@ -828,7 +1049,7 @@ func generateDirectorySnippet(directory *ygen.ParsedDirectory, directories map[s
 	var methodBuf strings.Builder
 
 	// Output struct snippets.
-	structData := getStructData(directory, pathStructSuffix, generateWildcardPaths)
+	structData := getStructData(directory, cg.PathStructSuffix, cg.GenerateWildcardPaths)
 	if directory.IsFakeRoot {
 		// Fakeroot has its unique output.
 		if err := goPathFakeRootTemplate.Execute(&structBuf, structData); err != nil {
@@ -837,6 +1058,15 @@ func generateDirectorySnippet(directory *ygen.ParsedDirectory, directories map[s
 	} else if err := goPathStructTemplate.Execute(&structBuf, structData); err != nil {
 		return nil, util.AppendErr(errs, err)
 	}
+	if cg.GenerateGoStructLinks {
+		data := structData
+		data.GoTypeName = "*" + schemaStructPkgAccessor + directory.Name
+		// The fake root has no wildcard version.
+		data.GenerateWildcardPaths = cg.GenerateWildcardPaths && !directory.IsFakeRoot
+		if err := goPathGoStructTemplate.Execute(&methodBuf, data); err != nil {
+			errs = util.AppendErr(errs, err)
+		}
+	}
 
 	deps := map[string]bool{}
 	listBuilderAPIBufs := map[string]*strings.Builder{}
@@ -859,8 +1089,8 @@ func generateDirectorySnippet(directory *ygen.ParsedDirectory, directories map[s
 		// Only the fake root could be importing a child path struct from another package.
 		// If it is, add that package as a dependency and set the accessor.
 		if directory.IsFakeRoot && (field.Type == ygen.ContainerNode || field.Type == ygen.ListNode) {
-			parentPackge := goPackageName(directory.RootElementModule, splitByModule, directory.IsFakeRoot, pkgName, pkgSuffix, trimPkgPrefix)
-			childPackage := goPackageName(field.YANGDetails.RootElementModule, splitByModule, false, pkgName, pkgSuffix, trimPkgPrefix)
+			parentPackge := goPackageName(directory.RootElementModule, cg.SplitByModule, directory.IsFakeRoot, cg.PackageName, cg.PackageSuffix, cg.TrimPackagePrefix)
+			childPackage := goPackageName(field.YANGDetails.RootElementModule, cg.SplitByModule, false, cg.PackageName, cg.PackageSuffix, cg.TrimPackagePrefix)
 			if parentPackge != childPackage {
 				deps[childPackage] = true
 				childPkgAccessor = childPackage + "."
@@ -873,7 +1103,7 @@ func generateDirectorySnippet(directory *ygen.ParsedDirectory, directories map[s
 			}
 		}
 
-		if es := generateChildConstructors(&methodBuf, buildBuf, directory, fName, goFieldName, directories, schemaStructPkgAccessor, pathStructSuffix, listBuilderKeyThreshold, generateWildcardPaths, simplifyWildcardPaths, childPkgAccessor); es != nil {
+		if es := generateChildConstructors(&methodBuf, buildBuf, directory, fName, goFieldName, directories, schemaStructPkgAccessor, childPkgAccessor, cg); es != nil {
 			errs = util.AppendErrs(errs, es)
 		}
 
@@ -881,7 +1111,7 @@ func generateDirectorySnippet(directory *ygen.ParsedDirectory, directories map[s
 		// to output their struct snippets somewhere, and here is
 		// convenient.
 		if field.Type == ygen.LeafNode || field.Type == ygen.LeafListNode {
-			leafTypeName, err := getFieldTypeName(directory, fName, goFieldName, directories, pathStructSuffix)
+			leafTypeName, err := getFieldTypeName(directory, fName, goFieldName, directories, cg.PathStructSuffix)
 			if err != nil {
 				errs = util.AppendErr(errs, err)
 			} else {
@@ -891,11 +1121,30 @@ func generateDirectorySnippet(directory *ygen.ParsedDirectory, directories map[s
 					PathBaseTypeName:        ygot.PathBaseTypeName,
 					PathStructInterfaceName: ygot.PathStructInterfaceName,
 					WildcardSuffix:          WildcardSuffix,
-					GenerateWildcardPaths:   generateWildcardPaths,
+					GenerateWildcardPaths:   cg.GenerateWildcardPaths,
 				}
 				if err := goPathStructTemplate.Execute(&structBuf, structData); err != nil {
 					errs = util.AppendErr(errs, err)
 				}
+				if goTypeName, ok := extractGoTypeName(field, schemaStructPkgAccessor); cg.GenerateExtractMethods && ok {
+					structData.GoTypeName = goTypeName
+					if err := goPathExtractTemplate.Execute(&methodBuf, structData); err != nil {
+						errs = util.AppendErr(errs, err)
+					}
+				}
+				if configPath, statePath, ok := configStateRelPaths(field); !cg.SkipConfigStatePaths && ok {
+					if err := goPathConfigStateTemplate.Execute(&methodBuf, struct {
+						goPathStructData
+						ConfigRelPathList string
+						StateRelPathList  string
+					}{
+						goPathStructData:  structData,
+						ConfigRelPathList: `"` + strings.Join(configPath, `", "`) + `"`,
+						StateRelPathList:  `"` + strings.Join(statePath, `", "`) + `"`,
+					}); err != nil {
+						errs = util.AppendErr(errs, err)
+					}
+				}
 			}
 		}
 	}
@@ -907,7 +1156,7 @@ func generateDirectorySnippet(directory *ygen.ParsedDirectory, directories map[s
 		PathStructName:    structData.TypeName,
 		StructBase:        structBuf.String(),
 		ChildConstructors: methodBuf.String(),
-		Package:           goPackageName(directory.RootElementModule, splitByModule, directory.IsFakeRoot, pkgName, pkgSuffix, trimPkgPrefix),
+		Package:           goPackageName(directory.RootElementModule, cg.SplitByModule, directory.IsFakeRoot, cg.PackageName, cg.PackageSuffix, cg.TrimPackagePrefix),
 	}
 	for dep := range deps {
 		snippet.Deps = append(snippet.Deps, dep)
@@ -926,6 +1175,61 @@ func generateDirectorySnippet(directory *ygen.ParsedDirectory, directories map[s
 	return snippets, errs
 }
 
+// longestPath returns the longest of the supplied paths.
+func longestPath(ss [][]string) []string {
+	var longest []string
+	for _, s := range ss {
+		if longest == nil {
+			longest = s
+			continue
+		}
+		if len(s) > len(longest) {
+			longest = s
+		}
+	}
+	return longest
+}
+
+// configStateRelPaths returns the paths of the config and state versions of
+// the supplied leaf field relative to its parent, and false if the leaf does
+// not exist within both a config and a state container of its parent.
+func configStateRelPaths(field *ygen.NodeDetails) ([]string, []string, bool) {
+	// The longest paths are the non-key paths.
+	path, shadowPath := longestPath(field.MappedPaths), longestPath(field.ShadowMappedPaths)
+	container := func(p []string) string {
+		if len(p) < 2 {
+			return ""
+		}
+		return p[len(p)-2]
+	}
+	switch {
+	case container(path) == "config" && container(shadowPath) == "state":
+		return path, shadowPath, true
+	case container(path) == "state" && container(shadowPath) == "config":
+		return shadowPath, path, true
+	}
+	return nil, nil, false
+}
+
+// extractGoTypeName returns the Go type that the value of the supplied leaf
+// or leaf-list field is decoded into by its generated Extract method, and
+// false if an Extract method cannot be generated for the field, i.e., if it
+// is of union or unsupported type.
+func extractGoTypeName(field *ygen.NodeDetails, schemaStructPkgAccessor string) (string, bool) {
+	mType := field.LangType
+	if mType == nil || len(mType.UnionTypes) > 1 || mType.NativeType == "interface{}" {
+		return "", false
+	}
+	goTypeName := mType.NativeType
+	if ygen.IsYgenDefinedGoType(mType) {
+		goTypeName = schemaStructPkgAccessor + goTypeName
+	}
+	if field.Type == ygen.LeafListNode {
+		goTypeName = "[]" + goTypeName
+	}
+	return goTypeName, true
+}
+
 // generateChildConstructors generates and writes to methodBuf the Go methods
 // that returns an instantiation of the child node's path struct object.
 // When this is called on the fakeroot, the list builder API's methods
@@ -935,32 +1239,19 @@ func generateDirectorySnippet(directory *ygen.ParsedDirectory, directories map[s
 // of the directory identifying the child yang.Entry, a directory-level unique
 // field name to be used as the generated method's name and the incremental
 // type name of of the child path struct, and a map of all directories of the
-// whole schema keyed by their schema paths.
-func generateChildConstructors(methodBuf *strings.Builder, builderBuf *strings.Builder, directory *ygen.ParsedDirectory, directoryFieldName string, goFieldName string, directories map[string]*ygen.ParsedDirectory, schemaStructPkgAccessor, pathStructSuffix string, listBuilderKeyThreshold uint, generateWildcardPaths, simplifyWildcardPaths bool, childPkgAccessor string) []error {
+// whole schema keyed by their schema paths. cg determines the code that is
+// generated.
+func generateChildConstructors(methodBuf *strings.Builder, builderBuf *strings.Builder, directory *ygen.ParsedDirectory, directoryFieldName string, goFieldName string, directories map[string]*ygen.ParsedDirectory, schemaStructPkgAccessor, childPkgAccessor string, cg *GenConfig) []error {
 	field, ok := directory.Fields[directoryFieldName]
 	if !ok {
 		return []error{fmt.Errorf("generateChildConstructors: field %s not found in directory %v", directoryFieldName, directory)}
 	}
-	fieldTypeName, err := getFieldTypeName(directory, directoryFieldName, goFieldName, directories, pathStructSuffix)
+	fieldTypeName, err := getFieldTypeName(directory, directoryFieldName, goFieldName, directories, cg.PathStructSuffix)
 	if err != nil {
 		return []error{err}
 	}
 
-	longestPath := func(ss [][]string) []string {
-		var longest []string
-		for _, s := range ss {
-			if longest == nil {
-				longest = s
-				continue
-			}
-			if len(s) > len(longest) {
-				longest = s
-			}
-		}
-		return longest
-	}
-
-	structData := getStructData(directory, pathStructSuffix, generateWildcardPaths)
+	structData := getStructData(directory, cg.PathStructSuffix, cg.GenerateWildcardPaths)
 	// The longest path is the non-key path. This is the one we want to use
 	// since the key is "compressed out".
 	relPath := longestPath(field.MappedPaths)
@@ -980,16 +1271,23 @@ func generateChildConstructors(methodBuf *strings.Builder, builderBuf *strings.B
 		RelPath:                 strings.Join(relPath, `/`),
 		RelPathList:             `"` + strings.Join(relPath, `", "`) + `"`,
 		ChildPkgAccessor:        childPkgAccessor,
+		CompactDocs:             cg.CompactOutput,
 	}
 
 	isUnderFakeRoot := directory.IsFakeRoot
 
+	// The builder API is only used for wildcard paths.
+	var listBuilderKeyThreshold uint
+	if cg.GenerateWildcardPaths {
+		listBuilderKeyThreshold = cg.ListBuilderKeyThreshold
+	}
+
 	// This is expected to be nil for leaf fields.
 	fieldDirectory := directories[field.YANGDetails.Path]
 
 	switch {
 	case field.Type != ygen.ListNode:
-		return generateChildConstructorsForLeafOrContainer(methodBuf, fieldData, isUnderFakeRoot, generateWildcardPaths)
+		return generateChildConstructorsForLeafOrContainer(methodBuf, fieldData, isUnderFakeRoot, cg.GenerateWildcardPaths)
 	case len(fieldDirectory.ListKeys) == 0:
 		// TODO(wenbli): keyless lists as a path are not supported by gNMI, but this
 		// library is currently intended for gNMI, so need to decide on a long-term solution.
@@ -1006,7 +1304,7 @@ func generateChildConstructors(methodBuf *strings.Builder, builderBuf *strings.B
 		// The generated const
 		return generateChildConstructorsForListBuilderFormat(methodBuf, builderBuf, fieldDirectory.ListKeys, fieldDirectory.ListKeyYANGNames, fieldData, isUnderFakeRoot, schemaStructPkgAccessor)
 	default:
-		return generateChildConstructorsForList(methodBuf, fieldDirectory.ListKeys, fieldDirectory.ListKeyYANGNames, fieldData, isUnderFakeRoot, generateWildcardPaths, simplifyWildcardPaths, schemaStructPkgAccessor)
+		return generateChildConstructorsForList(methodBuf, fieldDirectory.ListKeys, fieldDirectory.ListKeyYANGNames, fieldData, isUnderFakeRoot, cg.GenerateWildcardPaths, cg.SimplifyWildcardPaths, schemaStructPkgAccessor)
 	}
 }
 
@@ -1104,6 +1402,9 @@ func generateChildConstructorsForListBuilderFormat(methodBuf *strings.Builder, b
 			errors = append(errors, err)
 		}
 	}
+	if err := goKeyBuildTemplate.Execute(builderBuf, struct{ TypeName string }{TypeName: fieldData.TypeName}); err != nil {
+		errors = append(errors, err)
+	}
 
 	return errors
 }
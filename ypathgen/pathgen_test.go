@@ -65,6 +65,14 @@ func TestGeneratePathCode(t *testing.T) {
 		inSchemaStructPkgPath   string
 		inPathStructSuffix      string
 		inSimplifyWildcardPaths bool
+		// inGenerateExtractMethods determines whether Extract methods are generated for leaf path structs.
+		inGenerateExtractMethods bool
+		// inGenerateGoStructLinks determines whether ΛGoStruct methods are generated for container and list path structs.
+		inGenerateGoStructLinks bool
+		// inSkipConfigStatePaths determines whether Config and State methods are skipped for leaf path structs.
+		inSkipConfigStatePaths bool
+		// inCompactOutput determines whether the compact output mode is used.
+		inCompactOutput bool
 		// checkYANGPath says whether to check for the YANG path in the NodeDataMap.
 		checkYANGPath bool
 		// wantStructsCodeFile is the path of the generated Go code that the output of the test should be compared to.
@@ -74,6 +82,35 @@ func TestGeneratePathCode(t *testing.T) {
 		// wantErr specifies whether the test should expect an error.
 		wantErr bool
 	}{{
+		name:                     "simple openconfig test with extract methods",
+		inFiles:                  []string{filepath.Join(datapath, "openconfig-simple.yang")},
+		wantStructsCodeFile:      filepath.Join(TestRoot, "testdata/structs/openconfig-simple.extract.path-txt"),
+		inPreferOperationalState: true,
+		inShortenEnumLeafNames:   true,
+		inGenerateWildcardPaths:  true,
+		inSchemaStructPkgPath:    "github.com/openconfig/ygot/ypathgen/testdata/exampleoc",
+		inPathStructSuffix:       "Path",
+		inGenerateExtractMethods: true,
+	}, {
+		name:                     "openconfig list test with GoStruct links",
+		inFiles:                  []string{filepath.Join(datapath, "openconfig-withlist.yang")},
+		wantStructsCodeFile:      filepath.Join(TestRoot, "testdata/structs/openconfig-withlist.gostruct.path-txt"),
+		inPreferOperationalState: true,
+		inShortenEnumLeafNames:   true,
+		inGenerateWildcardPaths:  true,
+		inSchemaStructPkgPath:    "github.com/openconfig/ygot/ypathgen/testdata/exampleoc",
+		inPathStructSuffix:       "Path",
+		inGenerateGoStructLinks:  true,
+	}, {
+		name:                     "simple openconfig test without config and state paths",
+		inFiles:                  []string{filepath.Join(datapath, "openconfig-simple.yang")},
+		wantStructsCodeFile:      filepath.Join(TestRoot, "testdata/structs/openconfig-simple.noconfigstate.path-txt"),
+		inPreferOperationalState: true,
+		inShortenEnumLeafNames:   true,
+		inGenerateWildcardPaths:  true,
+		inPathStructSuffix:       "Path",
+		inSkipConfigStatePaths:   true,
+	}, {
 		name:                     "simple openconfig test",
 		inFiles:                  []string{filepath.Join(datapath, "openconfig-simple.yang")},
 		wantStructsCodeFile:      filepath.Join(TestRoot, "testdata/structs/openconfig-simple.path-txt"),
@@ -388,6 +425,17 @@ func TestGeneratePathCode(t *testing.T) {
 		inSchemaStructPkgPath:                  "",
 		inPathStructSuffix:                     "Path",
 		wantStructsCodeFile:                    filepath.Join(TestRoot, "testdata/structs/openconfig-withlist.path-txt"),
+	}, {
+		name:                                   "simple openconfig test with list, and inCompactOutput=true",
+		inFiles:                                []string{filepath.Join(datapath, "openconfig-withlist.yang")},
+		inPreferOperationalState:               true,
+		inShortenEnumLeafNames:                 true,
+		inUseDefiningModuleForTypedefEnumNames: true,
+		inGenerateWildcardPaths:                true,
+		inSchemaStructPkgPath:                  "github.com/openconfig/ygot/ypathgen/testdata/exampleoc",
+		inPathStructSuffix:                     "Path",
+		inCompactOutput:                        true,
+		wantStructsCodeFile:                    filepath.Join(TestRoot, "testdata/structs/openconfig-withlist-compact.path-txt"),
 	}, {
 		name:                                   "simple openconfig test with list, and inSimplifyWildcardPaths=true",
 		inFiles:                                []string{filepath.Join(datapath, "openconfig-withlist.yang")},
@@ -955,6 +1003,10 @@ func TestGeneratePathCode(t *testing.T) {
 				cg.UseDefiningModuleForTypedefEnumNames = tt.inUseDefiningModuleForTypedefEnumNames
 				cg.GenerateWildcardPaths = tt.inGenerateWildcardPaths
 				cg.SimplifyWildcardPaths = tt.inSimplifyWildcardPaths
+				cg.GenerateExtractMethods = tt.inGenerateExtractMethods
+				cg.GenerateGoStructLinks = tt.inGenerateGoStructLinks
+				cg.SkipConfigStatePaths = tt.inSkipConfigStatePaths
+				cg.CompactOutput = tt.inCompactOutput
 				cg.PackageName = "ocstructs"
 
 				gotCode, gotNodeDataMap, err := cg.GeneratePathCode(tt.inFiles, tt.inIncludePaths)
@@ -1230,6 +1282,17 @@ func TestGeneratePathCodeSplitModules(t *testing.T) {
 	}
 }
 
+func TestGeneratePathCodeCompactSplitModules(t *testing.T) {
+	cg := NewDefaultConfig("")
+	cg.GeneratingBinary = "pathgen-tests"
+	cg.SplitByModule = true
+	cg.CompactOutput = true
+	_, _, err := cg.GeneratePathCode([]string{filepath.Join(datapath, "openconfig-simple.yang")}, nil)
+	if diff := errdiff.Substring(err, "cannot be used with SplitByModule"); diff != "" {
+		t.Errorf("GeneratePathCode: %s", diff)
+	}
+}
+
 // getIR is a helper returning an IR to be tested, and its corresponding
 // Directory map with relevant fields filled out that would be returned from
 // ygen.GenerateIR().
@@ -2735,10 +2798,24 @@ func (n *ListPathAny) WithUnionKey(UnionKey oc.RootElementModule_List_UnionKey_U
 	return n
 }
 
+func (n *ListPathAny) Build() (*ListPathAny, error) {
+	if err := ygot.ValidateKeys(n.NodePath); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
 func (n *ListWithStatePathAny) WithKey(Key float64) *ListWithStatePathAny {
 	ygot.ModifyKey(n.NodePath, "key", Key)
 	return n
 }
+
+func (n *ListWithStatePathAny) Build() (*ListWithStatePathAny, error) {
+	if err := ygot.ValidateKeys(n.NodePath); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
 `,
 		}},
 	}}
@@ -2746,7 +2823,15 @@ func (n *ListWithStatePathAny) WithKey(Key float64) *ListWithStatePathAny {
 	for _, tt := range tests {
 		if tt.want != nil {
 			t.Run(tt.name, func(t *testing.T) {
-				got, gotErr := generateDirectorySnippet(tt.inDirectory, directories, "oc.", tt.inPathStructSuffix, tt.inListBuilderKeyThreshold, true, false, tt.inSplitByModule, tt.inPackageName, tt.inPackageSuffix, "")
+				got, gotErr := generateDirectorySnippet(tt.inDirectory, directories, "oc.", &GenConfig{
+					PathStructSuffix:        tt.inPathStructSuffix,
+					ListBuilderKeyThreshold: tt.inListBuilderKeyThreshold,
+					GenerateWildcardPaths:   true,
+					SkipConfigStatePaths:    true,
+					SplitByModule:           tt.inSplitByModule,
+					PackageName:             tt.inPackageName,
+					PackageSuffix:           tt.inPackageSuffix,
+				})
 				if gotErr != nil {
 					t.Fatalf("func generateDirectorySnippet, unexpected error: %v", gotErr)
 				}
@@ -2762,7 +2847,15 @@ func (n *ListWithStatePathAny) WithKey(Key float64) *ListWithStatePathAny {
 
 		if tt.wantNoWildcard != nil {
 			t.Run(tt.name+" no wildcard", func(t *testing.T) {
-				got, gotErr := generateDirectorySnippet(tt.inDirectory, directories, "oc.", tt.inPathStructSuffix, tt.inListBuilderKeyThreshold, false, false, tt.inSplitByModule, tt.inPackageName, tt.inPackageSuffix, "")
+				got, gotErr := generateDirectorySnippet(tt.inDirectory, directories, "oc.", &GenConfig{
+					PathStructSuffix:        tt.inPathStructSuffix,
+					ListBuilderKeyThreshold: tt.inListBuilderKeyThreshold,
+					GenerateWildcardPaths:   false,
+					SkipConfigStatePaths:    true,
+					SplitByModule:           tt.inSplitByModule,
+					PackageName:             tt.inPackageName,
+					PackageSuffix:           tt.inPackageSuffix,
+				})
 				if gotErr != nil {
 					t.Fatalf("func generateDirectorySnippet, unexpected error: %v", gotErr)
 				}
@@ -3024,6 +3117,16 @@ func (n *ListPathAny) WithUnionKey(UnionKey oc.RootElementModule_List_UnionKey_U
 	ygot.ModifyKey(n.NodePath, "union-key", UnionKey)
 	return n
 }
+
+// Build returns n if each of ListPathAny's keys is either a wildcard or
+// was set exactly once, before n was used to construct another path, and an
+// error otherwise.
+func (n *ListPathAny) Build() (*ListPathAny, error) {
+	if err := ygot.ValidateKeys(n.NodePath); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
 `,
 	}}
 
@@ -3031,7 +3134,12 @@ func (n *ListPathAny) WithUnionKey(UnionKey oc.RootElementModule_List_UnionKey_U
 		t.Run(tt.name, func(t *testing.T) {
 			var methodBuf strings.Builder
 			var builderBuf strings.Builder
-			if errs := generateChildConstructors(&methodBuf, &builderBuf, tt.inDirectory, tt.inFieldName, tt.inUniqueFieldName, tt.inDirectories, "oc.", tt.inPathStructSuffix, tt.inListBuilderKeyThreshold, tt.inGenerateWildcardPaths, tt.inSimplifyWildcardPaths, tt.inChildAccessor); errs != nil {
+			if errs := generateChildConstructors(&methodBuf, &builderBuf, tt.inDirectory, tt.inFieldName, tt.inUniqueFieldName, tt.inDirectories, "oc.", tt.inChildAccessor, &GenConfig{
+				PathStructSuffix:        tt.inPathStructSuffix,
+				ListBuilderKeyThreshold: tt.inListBuilderKeyThreshold,
+				GenerateWildcardPaths:   tt.inGenerateWildcardPaths,
+				SimplifyWildcardPaths:   tt.inSimplifyWildcardPaths,
+			}); errs != nil {
 				t.Fatal(errs)
 			}
 
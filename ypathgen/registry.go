@@ -0,0 +1,105 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ypathgen
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygen"
+)
+
+// PathRegistryEntry describes a single schema node for which a path struct
+// is generated. A slice of PathRegistryEntry values forms a machine-readable
+// inventory of the generated paths, which can be consumed by tooling that is
+// not written in Go.
+type PathRegistryEntry struct {
+	// Path is the schema path of the node, i.e., its gNMI path with the
+	// keys of any lists omitted.
+	Path string `json:"path"`
+	// ShadowPath is the schema path of the node that was compressed out
+	// in favour of the node at Path, e.g., the path of the leaf within
+	// the "config" container when the leaf within the "state" container
+	// is preferred. It is empty if there is no such node.
+	ShadowPath string `json:"shadow_path,omitempty"`
+	// Kind is the kind of the YANG node, i.e., "container", "list",
+	// "leaf", or "leaf-list".
+	Kind string `json:"kind"`
+	// YANGType is the name of the type of the node specified in the YANG
+	// schema. It is only populated for leaves and leaf-lists.
+	YANGType string `json:"yang_type,omitempty"`
+	// Defaults is the set of default values directly specified for the
+	// node in the YANG schema.
+	Defaults []string `json:"defaults,omitempty"`
+	// Keys is the ordered set of names of the key leaves of the node. It
+	// is only populated for keyed lists.
+	Keys []string `json:"keys,omitempty"`
+	// PathStruct is the name of the generated path struct for the node.
+	PathStruct string `json:"path_struct"`
+	// Package is the name of the Go package that contains PathStruct.
+	Package string `json:"package"`
+}
+
+// MarshalPathRegistry returns the supplied path registry entries as
+// indented JSON, sorted by path.
+func MarshalPathRegistry(entries []*PathRegistryEntry) ([]byte, error) {
+	sorted := append([]*PathRegistryEntry{}, entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Path < sorted[j].Path
+	})
+	return json.MarshalIndent(sorted, "", "  ")
+}
+
+// getPathRegistry returns the path registry entries for each field of the
+// directories within the supplied IR, keyed by the name of the Go package
+// that contains the generated path struct for the field.
+// packageName, packageSuffix, trimPackagePrefix and splitByModule are used to
+// determine the generated Go package name for each path struct.
+func getPathRegistry(ir *ygen.IR, pathStructSuffix, packageName, packageSuffix, trimPackagePrefix string, splitByModule bool) (map[string][]*PathRegistryEntry, util.Errors) {
+	registry := map[string][]*PathRegistryEntry{}
+	var errs util.Errors
+	for _, dirPath := range ir.OrderedDirectoryPathsByName() {
+		dir := ir.Directories[dirPath]
+		goFieldNameMap := ygen.GoFieldNameMap(dir)
+		for _, fieldName := range dir.OrderedFieldNames() {
+			field := dir.Fields[fieldName]
+			pathStructName, err := getFieldTypeName(dir, fieldName, goFieldNameMap[fieldName], ir.Directories, pathStructSuffix)
+			if err != nil {
+				errs = util.AppendErr(errs, err)
+				continue
+			}
+
+			entry := &PathRegistryEntry{
+				Path:       field.YANGDetails.SchemaPath,
+				ShadowPath: field.YANGDetails.ShadowSchemaPath,
+				Kind:       field.Type.String(),
+				Defaults:   field.YANGDetails.Defaults,
+				PathStruct: pathStructName,
+				Package:    goPackageName(field.YANGDetails.RootElementModule, splitByModule, false, packageName, packageSuffix, trimPackagePrefix),
+			}
+			if field.Flags != nil {
+				entry.YANGType = field.Flags[yangTypeNameFlagKey]
+			}
+			if field.Type == ygen.ListNode {
+				if listDir, ok := ir.Directories[field.YANGDetails.Path]; ok {
+					entry.Keys = listDir.ListKeyYANGNames
+				}
+			}
+			registry[entry.Package] = append(registry[entry.Package], entry)
+		}
+	}
+	return registry, errs
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ypathgen
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGeneratePathRegistry(t *testing.T) {
+	tests := []struct {
+		name    string
+		inFiles []string
+		// wantEntries are the expected registry entries, keyed by
+		// path. Only the paths specified are checked.
+		wantEntries map[string]*PathRegistryEntry
+		// wantLen is the expected number of registry entries.
+		wantLen int
+	}{{
+		name:    "lists",
+		inFiles: []string{filepath.Join(datapath, "openconfig-withlist.yang")},
+		wantEntries: map[string]*PathRegistryEntry{
+			"/model": {
+				Path:       "/model",
+				Kind:       "container",
+				PathStruct: "ModelPath",
+				Package:    "ocstructs",
+			},
+			"/model/b/multi-key": {
+				Path:       "/model/b/multi-key",
+				Kind:       "list",
+				Keys:       []string{"key1", "key2"},
+				PathStruct: "Model_MultiKeyPath",
+				Package:    "ocstructs",
+			},
+			"/model/b/multi-key/state/key2": {
+				Path:       "/model/b/multi-key/state/key2",
+				ShadowPath: "/model/b/multi-key/config/key2",
+				Kind:       "leaf",
+				YANGType:   "uint64",
+				PathStruct: "Model_MultiKey_Key2Path",
+				Package:    "ocstructs",
+			},
+		},
+		wantLen: 8,
+	}, {
+		name:    "leaf-lists with defaults",
+		inFiles: []string{filepath.Join(datapath, "openconfig-leaflist-default.yang")},
+		wantEntries: map[string]*PathRegistryEntry{
+			"/parent/child/state/three": {
+				Path:       "/parent/child/state/three",
+				ShadowPath: "/parent/child/config/three",
+				Kind:       "leaf-list",
+				YANGType:   "enumeration",
+				Defaults:   []string{"ONE", "TWO"},
+				PathStruct: "Parent_Child_ThreePath",
+				Package:    "ocstructs",
+			},
+		},
+		wantLen: 6,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cg := NewDefaultConfig("")
+			cg.GeneratingBinary = "pathgen-tests"
+			cg.FakeRootName = "device"
+			cg.PackageName = "ocstructs"
+			cg.PreferOperationalState = true
+			cg.GeneratePathRegistry = true
+
+			gotCode, _, errs := cg.GeneratePathCode(tt.inFiles, nil)
+			if errs != nil {
+				t.Fatalf("GeneratePathCode(%v): got unexpected errors: %v", tt.inFiles, errs)
+			}
+
+			b, err := MarshalPathRegistry(gotCode[cg.PackageName].PathRegistry)
+			if err != nil {
+				t.Fatalf("MarshalPathRegistry: got unexpected error: %v", err)
+			}
+			var got []*PathRegistryEntry
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatalf("cannot unmarshal path registry: %v", err)
+			}
+			if len(got) != tt.wantLen {
+				t.Errorf("got %d path registry entries, want %d:\n%s", len(got), tt.wantLen, b)
+			}
+			for i := 1; i < len(got); i++ {
+				if got[i-1].Path > got[i].Path {
+					t.Errorf("path registry is not sorted by path: %q precedes %q", got[i-1].Path, got[i].Path)
+				}
+			}
+
+			gotEntries := map[string]*PathRegistryEntry{}
+			for _, e := range got {
+				if _, ok := tt.wantEntries[e.Path]; ok {
+					gotEntries[e.Path] = e
+				}
+			}
+			if diff := cmp.Diff(tt.wantEntries, gotEntries); diff != "" {
+				t.Errorf("did not get expected path registry entries, (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// ApplyBatch atomically applies a batch of gNMI deletes and updates to the
+// root GoStruct, which is described by schema. The deletes are applied before
+// the updates, and both are relative to root.
+//
+// The operations are applied to a copy of the subtrees of root that they
+// touch, and the resulting subtrees are validated. root is only modified if
+// all operations are applied and validated successfully; if an error is
+// returned, root is unchanged.
+//
+// The PreferShadowPath and IgnoreExtraFields options are supported.
+func ApplyBatch(schema *yang.Entry, root ygot.GoStruct, updates []*gpb.Update, deletes []*gpb.Path, opts ...UnmarshalOpt) error {
+	if util.IsValueNil(root) {
+		return fmt.Errorf("cannot apply batch to nil root")
+	}
+	rv := reflect.ValueOf(root)
+	if !util.IsValueStructPtr(rv) {
+		return fmt.Errorf("root must be a struct pointer, got %T", root)
+	}
+
+	paths := append([]*gpb.Path{}, deletes...)
+	for _, u := range updates {
+		paths = append(paths, u.GetPath())
+	}
+	touched, err := touchedFields(rv.Elem().Type(), paths)
+	if err != nil {
+		return err
+	}
+
+	// Copy the touched fields of root, such that the operations do not
+	// modify root until they have all been applied successfully. Fields
+	// that are not touched are shared with root.
+	partial := reflect.New(rv.Elem().Type())
+	for _, i := range touched {
+		partial.Elem().Field(i).Set(rv.Elem().Field(i))
+	}
+	cp, err := ygot.DeepCopy(partial.Interface().(ygot.GoStruct))
+	if err != nil {
+		return fmt.Errorf("cannot copy subtrees of root: %v", err)
+	}
+	work := reflect.New(rv.Elem().Type())
+	work.Elem().Set(rv.Elem())
+	for _, i := range touched {
+		work.Elem().Field(i).Set(reflect.ValueOf(cp).Elem().Field(i))
+	}
+	workRoot := work.Interface().(ygot.GoStruct)
+
+	preferShadowPath := hasPreferShadowPath(opts)
+	if err := deletePaths(schema, workRoot, nil, deletes, preferShadowPath, false); err != nil {
+		return err
+	}
+	if err := updatePaths(schema, workRoot, nil, updates, preferShadowPath, hasIgnoreExtraFields(opts), false); err != nil {
+		return err
+	}
+
+	var errs util.Errors
+	for _, i := range touched {
+		f := work.Elem().Type().Field(i)
+		cschema, err := util.ChildSchema(schema, f)
+		if err != nil {
+			errs = util.AppendErr(errs, err)
+			continue
+		}
+		errs = util.AppendErrs(errs, Validate(cschema, work.Elem().Field(i).Interface()))
+	}
+	if errs != nil {
+		return errs
+	}
+
+	rv.Elem().Set(work.Elem())
+	return nil
+}
+
+// touchedFields returns the indices of the fields of the struct type t whose
+// subtrees contain, or are contained by, any of the supplied paths.
+func touchedFields(t reflect.Type, paths []*gpb.Path) ([]int, error) {
+	// Module prefixes are not present in the path tags of the struct.
+	var stripped []*gpb.Path
+	for _, p := range paths {
+		sp := &gpb.Path{}
+		for _, e := range p.GetElem() {
+			sp.Elem = append(sp.Elem, &gpb.PathElem{Name: util.StripModulePrefix(e.GetName())})
+		}
+		stripped = append(stripped, sp)
+	}
+
+	var touched []int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if util.IsYgotAnnotation(f) {
+			continue
+		}
+		schPaths, err := util.SchemaPaths(f)
+		if err != nil {
+			return nil, err
+		}
+		schPaths = append(schPaths, util.ShadowSchemaPaths(f)...)
+	fieldLoop:
+		for _, p := range stripped {
+			for _, sp := range schPaths {
+				if util.PathPartiallyMatchesPrefix(p, sp) {
+					touched = append(touched, i)
+					break fieldLoop
+				}
+			}
+		}
+	}
+	return touched, nil
+}
@@ -0,0 +1,191 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"fmt"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/util"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// OrderBatch returns the supplied gNMI updates and deletes, which are relative
+// to the root described by schema, ordered such that they can be applied one
+// at a time without breaking the leafref constraints of the schema.
+//
+// An update that sets a leafref, or a subtree containing a leafref, is ordered
+// after each update that creates the target of the leafref, or a subtree
+// containing it. Conversely, a delete of a leafref, or a subtree containing a
+// leafref, is ordered before each delete of its target. Operations without
+// such dependencies retain their relative order.
+//
+// The dependencies are derived from the schema, rather than from the values
+// of the leafrefs, so an operation that both contains a leafref and its target
+// is not ordered with respect to other operations on the same target, e.g.,
+// other entries of the same list. An error is returned if a path cannot be
+// found in the schema, or the dependencies between operations are cyclic.
+func OrderBatch(schema *yang.Entry, updates []*gpb.Update, deletes []*gpb.Path) ([]*gpb.Update, []*gpb.Path, error) {
+	o := &batchOrderer{root: schema, leafrefs: map[*yang.Entry][]*yang.Entry{}}
+
+	var updatePaths []*gpb.Path
+	for _, u := range updates {
+		updatePaths = append(updatePaths, u.GetPath())
+	}
+	updateOrder, err := o.order(updatePaths, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot order updates: %v", err)
+	}
+	deleteOrder, err := o.order(deletes, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot order deletes: %v", err)
+	}
+
+	var orderedUpdates []*gpb.Update
+	for _, i := range updateOrder {
+		orderedUpdates = append(orderedUpdates, updates[i])
+	}
+	var orderedDeletes []*gpb.Path
+	for _, i := range deleteOrder {
+		orderedDeletes = append(orderedDeletes, deletes[i])
+	}
+	return orderedUpdates, orderedDeletes, nil
+}
+
+// batchOrderer determines the leafref dependencies between the operations
+// of a batch.
+type batchOrderer struct {
+	// root is the schema of the root that the paths of the operations are
+	// relative to.
+	root *yang.Entry
+	// leafrefs caches the targets of the leafrefs within the subtree of
+	// each schema entry.
+	leafrefs map[*yang.Entry][]*yang.Entry
+}
+
+// order returns the indices of the supplied paths, ordered such that the path
+// of an operation containing a leafref is after those containing its target,
+// or before them if reverse is set. The input order is otherwise retained.
+func (o *batchOrderer) order(paths []*gpb.Path, reverse bool) ([]int, error) {
+	entries := make([]*yang.Entry, 0, len(paths))
+	for _, p := range paths {
+		e, err := o.schemaAtPath(p)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	// before[i] holds the indices of the paths that must be ordered before
+	// the ith path.
+	before := make([][]int, len(paths))
+	for i, e := range entries {
+		for _, target := range o.leafrefTargets(e) {
+			if containsEntry(e, target) {
+				continue
+			}
+			for j, f := range entries {
+				if i == j || !containsEntry(f, target) {
+					continue
+				}
+				if reverse {
+					before[j] = append(before[j], i)
+				} else {
+					before[i] = append(before[i], j)
+				}
+			}
+		}
+	}
+
+	var order []int
+	done := make([]bool, len(paths))
+	for len(order) < len(paths) {
+		next := -1
+	candidates:
+		for i := range paths {
+			if done[i] {
+				continue
+			}
+			for _, j := range before[i] {
+				if !done[j] {
+					continue candidates
+				}
+			}
+			next = i
+			break
+		}
+		if next == -1 {
+			var cyclic []string
+			for i, p := range paths {
+				if !done[i] {
+					cyclic = append(cyclic, pathStringOrErr(p))
+				}
+			}
+			return nil, fmt.Errorf("cyclic leafref dependencies between paths %v", cyclic)
+		}
+		done[next] = true
+		order = append(order, next)
+	}
+	return order, nil
+}
+
+// schemaAtPath returns the schema entry at the supplied path, relative to the
+// root.
+func (o *batchOrderer) schemaAtPath(path *gpb.Path) (*yang.Entry, error) {
+	e := o.root
+	for _, pe := range path.GetElem() {
+		child := dataChild(e, util.StripModulePrefix(pe.GetName()))
+		if child == nil {
+			return nil, fmt.Errorf("cannot find schema for path %s", pathStringOrErr(path))
+		}
+		e = child
+	}
+	return e, nil
+}
+
+// leafrefTargets returns the schema entries of the targets of the leafrefs in
+// the subtree of e, including e itself.
+func (o *batchOrderer) leafrefTargets(e *yang.Entry) []*yang.Entry {
+	if targets, ok := o.leafrefs[e]; ok {
+		return targets
+	}
+	var targets []*yang.Entry
+	switch {
+	case util.IsLeafRef(e):
+		// Leafrefs whose target cannot be found do not create dependencies,
+		// and are reported by validation instead.
+		if t, err := util.FindLeafRefSchema(e, e.Type.Path); err == nil {
+			targets = append(targets, t)
+		}
+	case e.IsDir():
+		for _, c := range util.Children(e) {
+			targets = append(targets, o.leafrefTargets(c)...)
+		}
+	}
+	o.leafrefs[e] = targets
+	return targets
+}
+
+// containsEntry reports whether the schema entry e is, or is an ancestor of,
+// the schema entry target.
+func containsEntry(e, target *yang.Entry) bool {
+	for t := target; t != nil; t = t.Parent {
+		if t == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/integration_tests/schemaops/utestschema"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+)
+
+// cyclicSchema returns the schema of a fake root containing the leaves a and
+// b, each of which is a leafref to the other.
+func cyclicSchema() *yang.Entry {
+	root := &yang.Entry{
+		Name:       "device",
+		Kind:       yang.DirectoryEntry,
+		Annotation: map[string]interface{}{"isFakeRoot": true},
+		Dir: map[string]*yang.Entry{
+			"a": {
+				Name: "a",
+				Kind: yang.LeafEntry,
+				Type: &yang.YangType{Kind: yang.Yleafref, Path: "../b"},
+			},
+			"b": {
+				Name: "b",
+				Kind: yang.LeafEntry,
+				Type: &yang.YangType{Kind: yang.Yleafref, Path: "../a"},
+			},
+		},
+	}
+	for _, c := range root.Dir {
+		c.Parent = root
+	}
+	return root
+}
+
+func TestOrderBatch(t *testing.T) {
+	tests := []struct {
+		desc             string
+		inSchema         *yang.Entry
+		inUpdates        []string
+		inDeletes        []string
+		wantUpdates      []string
+		wantDeletes      []string
+		wantErrSubstring string
+	}{{
+		desc:        "leafref updated before its target",
+		inSchema:    utestschema.SchemaTree["Device"],
+		inUpdates:   []string{"/ref/reference[name=a]/name", "/target/entity[name=a]/name"},
+		wantUpdates: []string{"/target/entity[name=a]/name", "/ref/reference[name=a]/name"},
+	}, {
+		desc:     "subtrees containing leafref and target",
+		inSchema: utestschema.SchemaTree["Device"],
+		inUpdates: []string{
+			"/ref",
+			"/ref/reference[name=b]",
+			"/target/entity[name=a]",
+			"/target",
+		},
+		wantUpdates: []string{
+			"/target/entity[name=a]",
+			"/target",
+			"/ref",
+			"/ref/reference[name=b]",
+		},
+	}, {
+		desc:        "root contains leafref and target",
+		inSchema:    utestschema.SchemaTree["Device"],
+		inUpdates:   []string{"/ref/reference[name=a]/name", "/"},
+		wantUpdates: []string{"/", "/ref/reference[name=a]/name"},
+	}, {
+		desc:        "independent updates retain their order",
+		inSchema:    utestschema.SchemaTree["Device"],
+		inUpdates:   []string{"/ref/reference[name=b]/name", "/ref/reference[name=a]/name", "/target/entity[name=a]/name"},
+		wantUpdates: []string{"/target/entity[name=a]/name", "/ref/reference[name=b]/name", "/ref/reference[name=a]/name"},
+	}, {
+		desc:        "leafref target deleted before leafref",
+		inSchema:    utestschema.SchemaTree["Device"],
+		inDeletes:   []string{"/target/entity[name=a]", "/ref/reference[name=b]", "/ref/reference[name=a]"},
+		wantDeletes: []string{"/ref/reference[name=b]", "/ref/reference[name=a]", "/target/entity[name=a]"},
+	}, {
+		desc:        "updates and deletes",
+		inSchema:    utestschema.SchemaTree["Device"],
+		inUpdates:   []string{"/ref/reference[name=a]/name", "/target/entity[name=a]/name"},
+		inDeletes:   []string{"/target/entity[name=b]", "/ref/reference[name=b]"},
+		wantUpdates: []string{"/target/entity[name=a]/name", "/ref/reference[name=a]/name"},
+		wantDeletes: []string{"/ref/reference[name=b]", "/target/entity[name=b]"},
+	}, {
+		desc:             "unknown path",
+		inSchema:         utestschema.SchemaTree["Device"],
+		inUpdates:        []string{"/ref/unknown"},
+		wantErrSubstring: "cannot find schema for path /ref/unknown",
+	}, {
+		desc:             "cyclic leafrefs",
+		inSchema:         cyclicSchema(),
+		inUpdates:        []string{"/a", "/b"},
+		wantErrSubstring: "cyclic leafref dependencies between paths [/a /b]",
+	}, {
+		desc:        "leafref within the same operation",
+		inSchema:    cyclicSchema(),
+		inUpdates:   []string{"/", "/a"},
+		wantUpdates: []string{"/", "/a"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var updates []*gpb.Update
+			for _, p := range tt.inUpdates {
+				updates = append(updates, &gpb.Update{Path: mustPath(p)})
+			}
+			var deletes []*gpb.Path
+			for _, p := range tt.inDeletes {
+				deletes = append(deletes, mustPath(p))
+			}
+
+			gotUpdates, gotDeletes, err := ytypes.OrderBatch(tt.inSchema, updates, deletes)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("OrderBatch: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+
+			var gotUpdatePaths []string
+			for _, u := range gotUpdates {
+				gotUpdatePaths = append(gotUpdatePaths, mustPathString(t, u.GetPath()))
+			}
+			if diff := cmp.Diff(tt.wantUpdates, gotUpdatePaths); diff != "" {
+				t.Errorf("OrderBatch: did not get expected updates (-want, +got):\n%s", diff)
+			}
+			var gotDeletePaths []string
+			for _, p := range gotDeletes {
+				gotDeletePaths = append(gotDeletePaths, mustPathString(t, p))
+			}
+			if diff := cmp.Diff(tt.wantDeletes, gotDeletePaths); diff != "" {
+				t.Errorf("OrderBatch: did not get expected deletes (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func mustPathString(t *testing.T, p *gpb.Path) string {
+	t.Helper()
+	s, err := ygot.PathToString(p)
+	if err != nil {
+		t.Fatalf("cannot convert path %v to string: %v", p, err)
+	}
+	return s
+}
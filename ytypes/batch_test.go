@@ -0,0 +1,128 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestApplyBatch(t *testing.T) {
+	newRoot := func() *ListElemStruct1 {
+		return &ListElemStruct1{
+			Key1: ygot.String("hello"),
+			Outer: &OuterContainerType1{
+				Inner: &InnerContainerType1{
+					Int32LeafName:     ygot.Int32(42),
+					Int32LeafListName: []int32{1, 2},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		desc             string
+		inUpdates        []*gpb.Update
+		inDeletes        []*gpb.Path
+		want             *ListElemStruct1
+		wantErrSubstring string
+	}{{
+		desc: "updates and deletes",
+		inUpdates: []*gpb.Update{{
+			Path: mustPath("/key1"),
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "world"}},
+		}, {
+			Path: mustPath("/outer/inner/config/int32-leaf-field"),
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 43}},
+		}},
+		inDeletes: []*gpb.Path{mustPath("/outer/inner/int32-leaf-list")},
+		want: &ListElemStruct1{
+			Key1: ygot.String("world"),
+			Outer: &OuterContainerType1{
+				Inner: &InnerContainerType1{
+					Int32LeafName: ygot.Int32(43),
+				},
+			},
+		},
+	}, {
+		desc:      "delete root",
+		inDeletes: []*gpb.Path{{}},
+		want:      &ListElemStruct1{},
+	}, {
+		desc: "failed update is rolled back",
+		inUpdates: []*gpb.Update{{
+			Path: mustPath("/outer/inner/config/int32-leaf-field"),
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 43}},
+		}, {
+			Path: mustPath("/key1"),
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "world"}},
+		}, {
+			Path: mustPath("/outer/inner/config/int32-leaf-field"),
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "forty-four"}},
+		}},
+		wantErrSubstring: "failed to unmarshal",
+	}, {
+		desc:      "failed delete is rolled back",
+		inDeletes: []*gpb.Path{mustPath("/key1"), mustPath("/does-not-exist")},
+		inUpdates: []*gpb.Update{{
+			Path: mustPath("/outer/inner/config/int32-leaf-field"),
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 43}},
+		}},
+		wantErrSubstring: "does-not-exist",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			root := newRoot()
+			err := ApplyBatch(simpleSchema(), root, tt.inUpdates, tt.inDeletes)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("ApplyBatch: %s", diff)
+			}
+			want := tt.want
+			if err != nil {
+				want = newRoot()
+			}
+			if diff := cmp.Diff(want, root); diff != "" {
+				t.Errorf("ApplyBatch: (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyBatchCopiesTouchedSubtrees(t *testing.T) {
+	inner := &InnerContainerType1{Int32LeafName: ygot.Int32(42)}
+	root := &ListElemStruct1{
+		Key1:  ygot.String("hello"),
+		Outer: &OuterContainerType1{Inner: inner},
+	}
+	if err := ApplyBatch(simpleSchema(), root, []*gpb.Update{{
+		Path: mustPath("/outer/inner/config/int32-leaf-field"),
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 43}},
+	}}, nil); err != nil {
+		t.Fatalf("ApplyBatch: got unexpected error: %v", err)
+	}
+	if got, want := *root.Outer.Inner.Int32LeafName, int32(43); got != want {
+		t.Errorf("ApplyBatch: got leaf value %d, want %d", got, want)
+	}
+	// The subtree that was modified must have been copied.
+	if got, want := *inner.Int32LeafName, int32(42); got != want {
+		t.Errorf("ApplyBatch: original subtree was modified, got leaf value %d, want %d", got, want)
+	}
+}
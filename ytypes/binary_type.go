@@ -29,9 +29,16 @@ import (
 // schema's length restrictions (if any). It returns an error if the validation
 // fails.
 func ValidateBinaryRestrictions(schemaType *yang.YangType, binaryVal []byte) error {
+	return validateBinaryRestrictions(nil, schemaType, binaryVal)
+}
+
+// validateBinaryRestrictions implements ValidateBinaryRestrictions for the
+// type of the leaf schema, which, if supplied, is used to find the length
+// statement that defines the restriction that is not satisfied.
+func validateBinaryRestrictions(schema *yang.Entry, schemaType *yang.YangType, binaryVal []byte) error {
 	allowedRanges := schemaType.Length
-	if !lengthOk(allowedRanges, uint64(len(binaryVal))) {
-		return fmt.Errorf("length %d is outside range %v", len(binaryVal), allowedRanges)
+	if binLen := uint64(len(binaryVal)); !lengthOk(allowedRanges, binLen) {
+		return newLengthError(schema, schemaType, binLen, fmt.Sprintf("length %d is outside range %v", binLen, allowedRanges))
 	}
 	return nil
 }
@@ -49,14 +56,33 @@ func validateBinary(schema *yang.Entry, value interface{}) error {
 
 	// Check that type of value is the type expected from the schema.
 	if !isBinaryType(reflect.TypeOf(value)) {
-		return fmt.Errorf("non binary type %T with value %v for schema %s", value, value, schema.Name)
+		return withIssueKind(TypeIssue, fmt.Errorf("non binary type %T with value %v for schema %s", value, value, schema.Name))
 	}
 
 	// Check that the length is within the allowed range.
 	binaryVal := reflect.ValueOf(value).Bytes()
 
-	if err := ValidateBinaryRestrictions(schema.Type, binaryVal); err != nil {
-		return fmt.Errorf("schema %q: %v", schema.Name, err)
+	if err := validateBinaryRestrictions(schema, schema.Type, binaryVal); err != nil {
+		return fmt.Errorf("schema %q: %w", schema.Name, err)
+	}
+	return nil
+}
+
+// validateBinaryValue validates the value of value, which is of a
+// user-specified type for binary leaves, against the given schema.
+func validateBinaryValue(schema *yang.Entry, value ygot.BinaryValue) error {
+	if util.IsValueNilOrDefault(value) {
+		return nil
+	}
+	if err := validateBinarySchema(schema); err != nil {
+		return err
+	}
+	binaryVal, err := value.YANGBinary()
+	if err != nil {
+		return fmt.Errorf("schema %q: invalid binary value: %v", schema.Name, err)
+	}
+	if err := validateBinaryRestrictions(schema, schema.Type, binaryVal); err != nil {
+		return fmt.Errorf("schema %q: %w", schema.Name, err)
 	}
 	return nil
 }
@@ -71,7 +97,7 @@ func validateBinarySlice(schema *yang.Entry, value interface{}) error {
 
 	// Check that type of value is the type expected from the schema.
 	if !isBinarySliceType(reflect.TypeOf(value)) {
-		return fmt.Errorf("non []Binary type %T with value: %v for schema %s", value, value, schema.Name)
+		return withIssueKind(TypeIssue, fmt.Errorf("non []Binary type %T with value: %v for schema %s", value, value, schema.Name))
 	}
 
 	// Each slice element must be valid and unique.
@@ -80,7 +106,7 @@ func validateBinarySlice(schema *yang.Entry, value interface{}) error {
 	for i := 0; i < v.Len(); i++ {
 		val := v.Index(i)
 		if err := validateBinary(schema, val.Interface()); err != nil {
-			return fmt.Errorf("invalid element at index %d: %v", i, err)
+			return fmt.Errorf("invalid element at index %d: %w", i, err)
 		}
 		binaryVal := val.Bytes()
 		if tbl[string(binaryVal)] {
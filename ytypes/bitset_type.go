@@ -35,7 +35,7 @@ func validateBitset(schema *yang.Entry, value interface{}) error {
 	// Check that type of value is the type expected from the schema.
 	val, ok := value.(string)
 	if !ok {
-		return fmt.Errorf("non bitset type %T with value %v for schema %s", value, value, schema.Name)
+		return withIssueKind(TypeIssue, fmt.Errorf("non bitset type %T with value %v for schema %s", value, value, schema.Name))
 	}
 
 	// Check that the bitset names are defined.
@@ -59,14 +59,14 @@ func validateBitsetSlice(schema *yang.Entry, value interface{}) error {
 	// Check that type of value is the type expected from the schema.
 	slice, ok := value.([]string)
 	if !ok {
-		return fmt.Errorf("non []string type %T with value: %v for schema %s", value, value, schema.Name)
+		return withIssueKind(TypeIssue, fmt.Errorf("non []string type %T with value: %v for schema %s", value, value, schema.Name))
 	}
 
 	// Each slice element must be valid and unique.
 	tbl := make(map[string]bool, len(slice))
 	for i, val := range slice {
 		if err := validateBitset(schema, val); err != nil {
-			return fmt.Errorf("invalid element at index %d: %v for schema %s", i, err, schema.Name)
+			return fmt.Errorf("invalid element at index %d: %w for schema %s", i, err, schema.Name)
 		}
 		bitsetSlice := strings.Split(val, " ")
 		sort.Strings(bitsetSlice)
@@ -32,7 +32,7 @@ func validateBool(schema *yang.Entry, value interface{}) error {
 
 	// Check that type of value is the type expected from the schema.
 	if _, ok := value.(bool); !ok {
-		return fmt.Errorf("non bool type %T with value %v for schema %s", value, value, schema.Name)
+		return withIssueKind(TypeIssue, fmt.Errorf("non bool type %T with value %v for schema %s", value, value, schema.Name))
 	}
 
 	return nil
@@ -49,7 +49,7 @@ func validateBoolSlice(schema *yang.Entry, value interface{}) error {
 	// Check that type of value is the type expected from the schema.
 	slice, ok := value.([]bool)
 	if !ok {
-		return fmt.Errorf("non []bool type %T with value: %v for schema %s", value, value, schema.Name)
+		return withIssueKind(TypeIssue, fmt.Errorf("non []bool type %T with value: %v for schema %s", value, value, schema.Name))
 	}
 
 	// Each slice element must be valid and unique.
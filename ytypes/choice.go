@@ -47,7 +47,7 @@ func validateChoice(schema *yang.Entry, structValue ygot.GoStruct) (selected []s
 	}
 
 	if len(selectedCases) > 1 {
-		errors = util.AppendErr(errors, fmt.Errorf("multiple cases %v selected for choice %s", selectedCases, schema.Name))
+		errors = util.AppendErr(errors, withIssueKind(ChoiceIssue, fmt.Errorf("multiple cases %v selected for choice %s", selectedCases, schema.Name)))
 	}
 
 	return
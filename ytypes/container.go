@@ -15,8 +15,11 @@
 package ytypes
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/kylelemons/godebug/pretty"
@@ -29,7 +32,7 @@ import (
 
 // validateContainer validates each of the values in the map, keyed by the list
 // Key value, against the given list schema.
-func validateContainer(schema *yang.Entry, value ygot.GoStruct) util.Errors {
+func validateContainer(schema *yang.Entry, value ygot.GoStruct, opts ...ygot.ValidationOption) util.Errors {
 	var errors []error
 	if util.IsValueNil(value) {
 		return nil
@@ -65,12 +68,15 @@ func validateContainer(schema *yang.Entry, value ygot.GoStruct) util.Errors {
 			cschema, err := util.ChildSchema(schema, structTypes.Field(i))
 			switch {
 			case err != nil:
-				errors = util.AppendErr(errors, fmt.Errorf("%s: %v", fieldName, err))
+				errors = util.AppendErr(errors, fmt.Errorf("%s: %w", fieldName, err))
 				continue
 			case cschema != nil:
 				// Regular named child.
-				if errs := Validate(cschema, fieldValue); errs != nil {
-					errors = util.AppendErrs(errors, util.PrefixErrors(errs, cschema.Path()))
+				if util.IsValueNil(fieldValue) && isUnsetList(cschema, fieldValue) && !listAncestorExists(cschema, schema, value) {
+					continue
+				}
+				if errs := validateNode(cschema, fieldValue, opts...); errs != nil {
+					errors = util.AppendErrs(errors, withIssuePath(util.PrefixErrors(errs, cschema.Path()), fieldPathElems(fieldType)))
 				}
 			case !util.IsValueNilOrDefault(structElems.Field(i).Interface()):
 				// Either an element in choice schema subtree, or bad field.
@@ -149,6 +155,10 @@ func unmarshalContainer(schema *yang.Entry, parent interface{}, jsonTree interfa
 func unmarshalStruct(schema *yang.Entry, parent interface{}, jsonTree map[string]interface{}, enc Encoding, opts ...UnmarshalOpt) error {
 	destv := reflect.ValueOf(parent).Elem()
 	var allSchemaPaths [][]string
+	// In best-effort mode, errors unmarshalling individual fields are
+	// accumulated in ce rather than being returned immediately.
+	bestEffort := hasBestEffortUnmarshal(opts)
+	var ce *ComplianceErrors
 
 	// Range over the parent struct fields. For each field, check if the data
 	// is present in the JSON tree and if so unmarshal it into the field.
@@ -156,8 +166,9 @@ func unmarshalStruct(schema *yang.Entry, parent interface{}, jsonTree map[string
 		f := destv.Field(i)
 		ft := destv.Type().Field(i)
 
-		// Skip annotation fields since they do not have a schema.
-		// TODO(robjs): Implement unmarshalling annotations.
+		// Annotation fields do not have a schema. Only metadata
+		// annotations whose types are generated are unmarshalled.
+		// TODO(robjs): Implement unmarshalling other annotations.
 		if util.IsYgotAnnotation(ft) {
 			// We need to find the paths that we should have unmarshalled here to avoid
 			// throwing errors to users whilst there is a TODO above.
@@ -167,8 +178,16 @@ func unmarshalStruct(schema *yang.Entry, parent interface{}, jsonTree map[string
 			}
 
 			for _, s := range strings.Split(paths, "|") {
-				pp := strings.Split(s, "/")
-				allSchemaPaths = append(allSchemaPaths, []string{pp[len(pp)-1]})
+				allSchemaPaths = append(allSchemaPaths, strings.Split(s, "/"))
+			}
+
+			if mt, ok := parent.(ygot.MetadataAnnotationTypeMapper); ok && enc == JSONEncoding {
+				if err := unmarshalMetadataAnnotations(f, mt.ΛMetadataAnnotationTypeMap(), jsonTree, strings.Split(paths, "|")); err != nil {
+					if !bestEffort {
+						return err
+					}
+					ce = ce.appendSkipped(schema.Path(), err)
+				}
 			}
 			continue
 		}
@@ -183,7 +202,7 @@ func unmarshalStruct(schema *yang.Entry, parent interface{}, jsonTree map[string
 		}
 
 		if cschema == nil {
-			return fmt.Errorf("unmarshalContainer could not find schema for type %T, field name %s", parent, ft.Name)
+			return newFieldSkewError(destv.Type(), schema, ft)
 		}
 
 		// Store the data tree path of the current field. These will be used
@@ -222,7 +241,8 @@ func unmarshalStruct(schema *yang.Entry, parent interface{}, jsonTree map[string
 		// Only create a new field if it is nil, otherwise update just the
 		// fields that are in the data tree being passed to unmarshal, and
 		// preserve all other existing values.
-		if util.IsNilOrInvalidValue(f) {
+		wasNil := util.IsNilOrInvalidValue(f)
+		if wasNil {
 			makeField(destv, ft)
 		}
 
@@ -239,20 +259,114 @@ func unmarshalStruct(schema *yang.Entry, parent interface{}, jsonTree map[string
 			p = f.Interface()
 		}
 		if err := unmarshalGeneric(cschema, p, jsonValue, enc, opts...); err != nil {
-			return err
+			if !bestEffort {
+				return err
+			}
+			// Do not leave behind a field that was created only to be
+			// unmarshalled into if none of its contents could be populated.
+			if _, partial := err.(*ComplianceErrors); wasNil && !partial {
+				f.Set(reflect.Zero(ft.Type))
+			}
+			ce = ce.appendSkipped(cschema.Path(), err)
 		}
 	}
 
-	// Only check for missing fields if the IgnoreExtraFields option isn't specified.
-	if !hasIgnoreExtraFields(opts) {
+	switch {
+	case hasIgnoreExtraFields(opts):
+		// Fields in the JSON that are not covered by the struct are discarded.
+	case bestEffort:
+		// Each field that is not covered by the struct is recorded as skipped.
+		missing, unexpectedLeaves := unexpectedDataTreePaths(jsonTree, allSchemaPaths)
+		for _, p := range missing {
+			var err error = errors.New("JSON contains unexpected field")
+			if serr := unmappedSchemaNode(destv.Type(), schema, jsonTree, [][]string{p}, allSchemaPaths); serr != nil {
+				err = serr
+			}
+			ce = ce.appendSkipped(schema.Path()+"/"+strings.Join(p, "/"), err)
+		}
+		for _, p := range unexpectedLeaves {
+			ce = ce.appendSkipped(schema.Path()+"/"+strings.Join(p, "/"), errors.New("JSON contains unexpected leaf field at non-leaf node"))
+		}
+	default:
 		// Go over all JSON fields to make sure that each one is covered
 		// by a data path in the struct.
 		if err := checkDataTreeAgainstPaths(jsonTree, allSchemaPaths); err != nil {
-			return fmt.Errorf("parent container %s (type %T): %s", schema.Name, parent, err)
+			// A field in the JSON that is in the schema, but that
+			// is not covered by the struct, indicates that the
+			// struct was generated from a different schema.
+			missing, _ := unexpectedDataTreePaths(jsonTree, allSchemaPaths)
+			if serr := unmappedSchemaNode(destv.Type(), schema, jsonTree, missing, allSchemaPaths); serr != nil {
+				err = serr
+			}
+			return &ygot.SchemaMismatchError{Path: schema.Path(), Err: fmt.Errorf("parent container %s (type %T): %w", schema.Name, parent, err)}
 		}
 	}
 
 	util.DbgPrint("container after unmarshal:\n%s\n", pretty.Sprint(destv.Interface()))
+	if ce != nil {
+		return ce
+	}
+	return nil
+}
+
+// unmarshalMetadataAnnotations unmarshals the RFC7952 metadata annotations at
+// the first of the supplied paths that is present in jsonTree into the
+// annotation field f. Each annotation is unmarshalled into a new instance of
+// the type in types that has its qualified name, and replaces any existing
+// annotation with the same name. Annotations whose names are not within
+// types are ignored.
+func unmarshalMetadataAnnotations(f reflect.Value, types map[string]reflect.Type, jsonTree map[string]interface{}, paths []string) error {
+	var jv interface{}
+	for _, p := range paths {
+		if v, ok := getJSONTreeValForPath(jsonTree, strings.Split(p, "/")); ok {
+			jv = v
+			break
+		}
+	}
+	// The annotations of leaf-list entries are encoded as an array, and
+	// are not supported.
+	members, ok := jv.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var names []string
+	for name := range members {
+		if _, ok := types[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var mds []reflect.Value
+	set := map[string]bool{}
+	for _, name := range names {
+		md, ok := reflect.New(types[name]).Interface().(ygot.MetadataAnnotation)
+		if !ok {
+			return fmt.Errorf("type %v of metadata annotation %s does not implement ygot.MetadataAnnotation", types[name], name)
+		}
+		js, err := json.Marshal(members[name])
+		if err != nil {
+			return fmt.Errorf("cannot marshal value of metadata annotation %s, %v", name, err)
+		}
+		if err := md.UnmarshalJSON(js); err != nil {
+			return fmt.Errorf("cannot unmarshal metadata annotation %s, %v", name, err)
+		}
+		mds = append(mds, reflect.ValueOf(md))
+		set[name] = true
+	}
+	if len(mds) == 0 {
+		return nil
+	}
+
+	nv := reflect.MakeSlice(f.Type(), 0, f.Len()+len(mds))
+	for i := 0; i < f.Len(); i++ {
+		if md, ok := f.Index(i).Interface().(ygot.MetadataAnnotation); ok && !util.IsValueNil(md) && set[md.ΛMetadataName()] {
+			continue
+		}
+		nv = reflect.Append(nv, f.Index(i))
+	}
+	f.Set(reflect.Append(nv, mds...))
 	return nil
 }
 
@@ -264,7 +378,7 @@ func validateContainerSchema(schema *yang.Entry) error {
 	if schema == nil {
 		return fmt.Errorf("container schema is nil")
 	}
-	if !schema.IsContainer() {
+	if !schema.IsContainer() && !util.IsRPCInputOutput(schema) {
 		return fmt.Errorf("container schema %s is not a container type", schema.Name)
 	}
 
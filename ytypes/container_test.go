@@ -17,8 +17,10 @@ package ytypes
 import (
 	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/kylelemons/godebug/pretty"
 	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/openconfig/ygot/ygot"
@@ -558,3 +560,262 @@ func TestUnmarshalContainer(t *testing.T) {
 		t.Errorf("nil schema: got error: nil, want nil schema error")
 	}
 }
+
+func TestUnmarshalContainerBestEffort(t *testing.T) {
+	containerSchema := &yang.Entry{
+		Name: "parent-field",
+		Kind: yang.DirectoryEntry,
+		Dir: map[string]*yang.Entry{
+			"container-field": {
+				Name: "container-field",
+				Kind: yang.DirectoryEntry,
+				Dir: map[string]*yang.Entry{
+					"config": {
+						Name: "config",
+						Kind: yang.DirectoryEntry,
+						Dir: map[string]*yang.Entry{
+							"leaf1-field": {
+								Kind: yang.LeafEntry,
+								Name: "leaf1-field",
+								Type: &yang.YangType{Kind: yang.Yint32},
+							},
+						},
+					},
+					"leaf2-field": {
+						Kind: yang.LeafEntry,
+						Name: "leaf2-field",
+						Type: &yang.YangType{Kind: yang.Yint32},
+					},
+					"container2-field": {
+						Name: "container2-field",
+						Kind: yang.DirectoryEntry,
+						Dir: map[string]*yang.Entry{
+							"leaf3-field": {
+								Kind: yang.LeafEntry,
+								Name: "leaf3-field",
+								Type: &yang.YangType{Kind: yang.Yint32},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	populateParentField(nil, containerSchema)
+
+	type Container2Struct struct {
+		Leaf3Field *int32 `path:"leaf3-field"`
+	}
+
+	type ContainerStruct struct {
+		Leaf1Field      *int32            `path:"config/leaf1-field"`
+		Leaf2Field      *int32            `path:"leaf2-field"`
+		Container2Field *Container2Struct `path:"container2-field"`
+	}
+
+	type ParentContainerStruct struct {
+		ContainerField *ContainerStruct `path:"container-field"`
+	}
+
+	tests := []struct {
+		desc      string
+		json      string
+		want      *ParentContainerStruct
+		wantSkips []string
+	}{{
+		desc: "no errors",
+		json: `{ "container-field": { "leaf2-field": 42, "config": { "leaf1-field": 41 } } }`,
+		want: &ParentContainerStruct{ContainerField: &ContainerStruct{Leaf1Field: ygot.Int32(41), Leaf2Field: ygot.Int32(42)}},
+	}, {
+		desc: "unknown fields are skipped",
+		json: `{ "container-field": { "leaf2-field": 42, "new-field": 1, "config": { "leaf1-field": 41, "new-leaf": "a" } } }`,
+		want: &ParentContainerStruct{ContainerField: &ContainerStruct{Leaf1Field: ygot.Int32(41), Leaf2Field: ygot.Int32(42)}},
+		wantSkips: []string{
+			"/parent-field/container-field/config/new-leaf",
+			"/parent-field/container-field/new-field",
+		},
+	}, {
+		desc: "type mismatches are skipped",
+		json: `{ "container-field": { "leaf2-field": "forty-two", "config": { "leaf1-field": 41 }, "container2-field": { "leaf3-field": true } } }`,
+		want: &ParentContainerStruct{ContainerField: &ContainerStruct{Leaf1Field: ygot.Int32(41), Container2Field: &Container2Struct{}}},
+		wantSkips: []string{
+			"/parent-field/container-field/leaf2-field",
+			"/parent-field/container-field/container2-field/leaf3-field",
+		},
+	}, {
+		desc: "unknown fields are skipped in a stable order",
+		json: `{ "container-field": { "zz-field": 1, "leaf2-field": 42, "bb-field": 2, "aa-field": 3, "config": { "yy-leaf": 4, "xx-leaf": 5 } } }`,
+		want: &ParentContainerStruct{ContainerField: &ContainerStruct{Leaf2Field: ygot.Int32(42)}},
+		wantSkips: []string{
+			"/parent-field/container-field/aa-field",
+			"/parent-field/container-field/bb-field",
+			"/parent-field/container-field/config/xx-leaf",
+			"/parent-field/container-field/config/yy-leaf",
+			"/parent-field/container-field/zz-field",
+		},
+	}, {
+		desc: "container with wrong type is not created",
+		json: `{ "container-field": { "leaf2-field": 42, "container2-field": [1] } }`,
+		want: &ParentContainerStruct{ContainerField: &ContainerStruct{Leaf2Field: ygot.Int32(42)}},
+		wantSkips: []string{
+			"/parent-field/container-field/container2-field",
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var jsonTree interface{}
+			if err := json.Unmarshal([]byte(tt.json), &jsonTree); err != nil {
+				t.Fatalf("json unmarshal: %v", err)
+			}
+
+			got := &ParentContainerStruct{}
+			err := Unmarshal(containerSchema, got, jsonTree, &BestEffortUnmarshal{})
+
+			var gotSkips []string
+			if err != nil {
+				ce, ok := err.(*ComplianceErrors)
+				if !ok {
+					t.Fatalf("got error type %T, want *ComplianceErrors: %v", err, err)
+				}
+				for _, e := range ce.Errors {
+					se, ok := e.(*SkippedFieldError)
+					if !ok {
+						t.Fatalf("got compliance error type %T, want *SkippedFieldError: %v", e, e)
+					}
+					gotSkips = append(gotSkips, se.Path)
+				}
+			}
+			if diff := cmp.Diff(tt.wantSkips, gotSkips); diff != "" {
+				t.Errorf("did not get expected skipped paths, (-want, +got):\n%s", diff)
+			}
+			if !areEqual(got, tt.want) {
+				t.Errorf("got:\n%v\nwant:\n%v\n", pretty.Sprint(got), pretty.Sprint(tt.want))
+			}
+		})
+	}
+}
+
+type metadataOwner struct {
+	Value string
+}
+
+func (*metadataOwner) ΛMetadataName() string          { return "test-module:owner" }
+func (a *metadataOwner) MarshalJSON() ([]byte, error) { return ygot.MarshalMetadataValue(a.Value) }
+func (a *metadataOwner) UnmarshalJSON(b []byte) error {
+	return ygot.UnmarshalMetadataValue(b, &a.Value)
+}
+
+type metadataTimestamp struct {
+	Value uint64
+}
+
+func (*metadataTimestamp) ΛMetadataName() string          { return "test-module:timestamp" }
+func (a *metadataTimestamp) MarshalJSON() ([]byte, error) { return ygot.MarshalMetadataValue(a.Value) }
+func (a *metadataTimestamp) UnmarshalJSON(b []byte) error {
+	return ygot.UnmarshalMetadataValue(b, &a.Value)
+}
+
+type otherAnnotation struct{}
+
+func (*otherAnnotation) MarshalJSON() ([]byte, error) { return []byte("{}"), nil }
+func (*otherAnnotation) UnmarshalJSON([]byte) error   { return nil }
+
+type MetadataContainerStruct struct {
+	ΛMetadata   []ygot.Annotation `path:"@" ygotAnnotation:"true"`
+	Leaf1Field  *int32            `path:"config/leaf1-field"`
+	ΛLeaf1Field []ygot.Annotation `path:"config/@leaf1-field" ygotAnnotation:"true"`
+	Leaf2Field  *int32            `path:"leaf2-field"`
+	ΛLeaf2Field []ygot.Annotation `path:"@leaf2-field" ygotAnnotation:"true"`
+}
+
+func (*MetadataContainerStruct) IsYANGGoStruct() {}
+func (*MetadataContainerStruct) ΛMetadataAnnotationTypeMap() map[string]reflect.Type {
+	return map[string]reflect.Type{
+		"test-module:owner":     reflect.TypeOf(metadataOwner{}),
+		"test-module:timestamp": reflect.TypeOf(metadataTimestamp{}),
+	}
+}
+
+func TestUnmarshalMetadataAnnotations(t *testing.T) {
+	containerSchema := &yang.Entry{
+		Name: "container-field",
+		Kind: yang.DirectoryEntry,
+		Dir: map[string]*yang.Entry{
+			"config": {
+				Name: "config",
+				Kind: yang.DirectoryEntry,
+				Dir: map[string]*yang.Entry{
+					"leaf1-field": {
+						Kind: yang.LeafEntry,
+						Name: "leaf1-field",
+						Type: &yang.YangType{Kind: yang.Yint32},
+					},
+				},
+			},
+			"leaf2-field": {
+				Kind: yang.LeafEntry,
+				Name: "leaf2-field",
+				Type: &yang.YangType{Kind: yang.Yint32},
+			},
+		},
+	}
+	populateParentField(nil, containerSchema)
+
+	tests := []struct {
+		desc    string
+		json    string
+		in      *MetadataContainerStruct
+		want    *MetadataContainerStruct
+		wantErr string
+	}{{
+		desc: "annotations of container and leaves",
+		json: `{
+			"@": { "test-module:owner": "alice", "test-module:timestamp": "18446744073709551615" },
+			"config": { "leaf1-field": 1, "@leaf1-field": { "test-module:owner": "bob" } },
+			"leaf2-field": 2,
+			"@test-module:leaf2-field": { "test-module:timestamp": 42, "other-module:unknown": true }
+		}`,
+		in: &MetadataContainerStruct{},
+		want: &MetadataContainerStruct{
+			ΛMetadata:   []ygot.Annotation{&metadataOwner{Value: "alice"}, &metadataTimestamp{Value: 18446744073709551615}},
+			Leaf1Field:  ygot.Int32(1),
+			ΛLeaf1Field: []ygot.Annotation{&metadataOwner{Value: "bob"}},
+			Leaf2Field:  ygot.Int32(2),
+			ΛLeaf2Field: []ygot.Annotation{&metadataTimestamp{Value: 42}},
+		},
+	}, {
+		desc: "existing annotations with the same name are replaced",
+		json: `{ "@": { "test-module:owner": "alice" } }`,
+		in: &MetadataContainerStruct{
+			ΛMetadata: []ygot.Annotation{&otherAnnotation{}, &metadataOwner{Value: "bob"}, &metadataTimestamp{Value: 1}},
+		},
+		want: &MetadataContainerStruct{
+			ΛMetadata: []ygot.Annotation{&otherAnnotation{}, &metadataTimestamp{Value: 1}, &metadataOwner{Value: "alice"}},
+		},
+	}, {
+		desc:    "invalid annotation value",
+		json:    `{ "@": { "test-module:timestamp": "soon" } }`,
+		in:      &MetadataContainerStruct{},
+		wantErr: "cannot unmarshal metadata annotation test-module:timestamp",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var jsonTree interface{}
+			if err := json.Unmarshal([]byte(tt.json), &jsonTree); err != nil {
+				t.Fatalf("cannot unmarshal JSON: %v", err)
+			}
+			err := Unmarshal(containerSchema, tt.in, jsonTree)
+			if got := errToString(err); !strings.Contains(got, tt.wantErr) || (tt.wantErr == "") != (got == "") {
+				t.Fatalf("Unmarshal: got error %q, want error containing %q", got, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, tt.in); diff != "" {
+				t.Errorf("Unmarshal (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
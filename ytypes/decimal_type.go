@@ -16,8 +16,14 @@ package ytypes
 
 import (
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
 )
 
 // Refer to: https://tools.ietf.org/html/rfc6020#section-9.3.
@@ -26,28 +32,80 @@ import (
 // schema's range restrictions (if any). It returns an error if the validation
 // fails.
 func ValidateDecimalRestrictions(schemaType *yang.YangType, floatVal float64) error {
+	return validateDecimalRestrictions(nil, schemaType, floatVal)
+}
+
+// validateDecimalRestrictions implements ValidateDecimalRestrictions for the
+// type of the leaf schema, which, if supplied, is used to find the range
+// statement that defines the restriction that is not satisfied.
+func validateDecimalRestrictions(schema *yang.Entry, schemaType *yang.YangType, floatVal float64) error {
 	if !isInRanges(schemaType.Range, yang.FromFloat(floatVal)) {
-		return fmt.Errorf("decimal value %v is outside specified ranges", floatVal)
+		return newRangeError(schema, schemaType, floatVal, fmt.Sprintf("decimal value %v is outside specified ranges", floatVal))
 	}
 	return nil
 }
 
-// validateDecimal validates value, which must be a Go float64 type, against the
-// given schema.
+// ValidateDecimal64Restrictions checks that the given ygot.Decimal64 can be
+// represented using the fraction digits of the schema type, and that it
+// matches the schema's range restrictions (if any). Unlike
+// ValidateDecimalRestrictions, the value is compared with the ranges exactly.
+// It returns an error if the validation fails.
+func ValidateDecimal64Restrictions(schemaType *yang.YangType, d ygot.Decimal64) error {
+	return validateDecimal64Restrictions(nil, schemaType, d)
+}
+
+// validateDecimal64Restrictions implements ValidateDecimal64Restrictions for
+// the type of the leaf schema, which, if supplied, is used to find the range
+// statement that defines the restriction that is not satisfied.
+func validateDecimal64Restrictions(schema *yang.Entry, schemaType *yang.YangType, d ygot.Decimal64) error {
+	if fd := schemaType.FractionDigits; fd != 0 {
+		if _, err := ygot.ParseDecimal64(d.String(), uint8(fd)); err != nil {
+			return withIssueKind(RangeIssue, fmt.Errorf("decimal value %v cannot be represented with %d fraction digits", d, fd))
+		}
+	}
+	if !isInRanges(schemaType.Range, decimal64Number(d)) {
+		return newRangeError(schema, schemaType, d, fmt.Sprintf("decimal value %v is outside specified ranges", d))
+	}
+	return nil
+}
+
+// decimal64Number returns d as a yang.Number, such that it can be compared
+// with the ranges of a schema exactly.
+func decimal64Number(d ygot.Decimal64) yang.Number {
+	n := yang.Number{Value: uint64(d.Digits), FractionDigits: d.Precision}
+	if d.Digits < 0 {
+		n.Value, n.Negative = uint64(-(d.Digits+1))+1, true
+	}
+	return n
+}
+
+// validateDecimal validates value, which must be a Go float64 or a
+// ygot.Decimal64 type, against the given schema.
 func validateDecimal(schema *yang.Entry, value interface{}) error {
 	// Check that the schema itself is valid.
 	if err := validateDecimalSchema(schema); err != nil {
 		return err
 	}
 
+	if d, ok := value.(ygot.Decimal64); ok {
+		// The zero value of a Decimal64 indicates that it is unset.
+		if d == (ygot.Decimal64{}) {
+			return nil
+		}
+		if err := validateDecimal64Restrictions(schema, schema.Type, d); err != nil {
+			return fmt.Errorf("schema %q: %w", schema.Name, err)
+		}
+		return nil
+	}
+
 	// Check that type of value is the type expected from the schema.
 	f, ok := value.(float64)
 	if !ok {
-		return fmt.Errorf("non float64 type %T with value %v for schema %s", value, value, schema.Name)
+		return withIssueKind(TypeIssue, fmt.Errorf("non float64 type %T with value %v for schema %s", value, value, schema.Name))
 	}
 
-	if err := ValidateDecimalRestrictions(schema.Type, f); err != nil {
-		return fmt.Errorf("schema %q: %v", schema.Name, err)
+	if err := validateDecimalRestrictions(schema, schema.Type, f); err != nil {
+		return fmt.Errorf("schema %q: %w", schema.Name, err)
 	}
 
 	return nil
@@ -64,14 +122,14 @@ func validateDecimalSlice(schema *yang.Entry, value interface{}) error {
 	// Check that type of value is the type expected from the schema.
 	slice, ok := value.([]float64)
 	if !ok {
-		return fmt.Errorf("non []float64 type %T with value: %v for schema %s", value, value, schema.Name)
+		return withIssueKind(TypeIssue, fmt.Errorf("non []float64 type %T with value: %v for schema %s", value, value, schema.Name))
 	}
 
 	// Each slice element must be valid and unique.
 	tbl := make(map[float64]bool, len(slice))
 	for i, val := range slice {
 		if err := validateDecimal(schema, val); err != nil {
-			return fmt.Errorf("invalid element at index %d: %v for schema %s", i, err, schema.Name)
+			return fmt.Errorf("invalid element at index %d: %w for schema %s", i, err, schema.Name)
 		}
 		if tbl[val] {
 			return fmt.Errorf("duplicate decimal: %v for schema %s", val, schema.Name)
@@ -98,3 +156,65 @@ func validateDecimalSchema(schema *yang.Entry) error {
 
 	return nil
 }
+
+// decimal64Type is the reflect.Type of ygot.Decimal64.
+var decimal64Type = reflect.TypeOf(ygot.Decimal64{})
+
+// isDecimal64Field reports whether the field fieldName of parent, or the
+// elements of it if it is a slice, have the ygot.Decimal64 type, such that
+// decimal64 values are unmarshalled into it exactly.
+func isDecimal64Field(parent interface{}, fieldName string) bool {
+	ft := structFieldType(parent, fieldName)
+	if ft.Kind() == reflect.Slice {
+		ft = ft.Elem()
+	}
+	return ft == decimal64Type
+}
+
+// decimal64Value returns the ygot.Decimal64 held by v, and true, if v is a
+// ygot.Decimal64 or a simple union type of which it is the underlying type.
+// It returns false otherwise.
+func decimal64Value(v reflect.Value) (ygot.Decimal64, bool) {
+	if !v.IsValid() || v.Kind() != reflect.Struct || !v.Type().ConvertibleTo(decimal64Type) {
+		return ygot.Decimal64{}, false
+	}
+	return v.Convert(decimal64Type).Interface().(ygot.Decimal64), true
+}
+
+// parseDecimal64 parses the decimal number s as a ygot.Decimal64 with the
+// fraction digits of schemaType. If schemaType does not specify its fraction
+// digits, such as where it is the type of a union subtype that was found
+// by its kind, the number of fraction digits of s, which is at least one, is
+// used.
+func parseDecimal64(s string, schemaType *yang.YangType) (ygot.Decimal64, error) {
+	fd := uint8(schemaType.FractionDigits)
+	if fd == 0 {
+		fd = 1
+		if _, frac, ok := strings.Cut(s, "."); ok && len(frac) > 1 {
+			fd = uint8(len(frac))
+		}
+	}
+	return ygot.ParseDecimal64(s, fd)
+}
+
+// gNMIToDecimal64 returns the decimal64 value of the gNMI TypedValue tv, which
+// must be a DecimalVal, FloatVal or DoubleVal, as a ygot.Decimal64 with the
+// fraction digits of schemaType. Float values are converted using the
+// shortest decimal representation that identifies them.
+func gNMIToDecimal64(tv *gpb.TypedValue, schemaType *yang.YangType) (ygot.Decimal64, error) {
+	var s string
+	switch v := tv.GetValue().(type) {
+	case *gpb.TypedValue_DecimalVal:
+		if v.DecimalVal == nil {
+			return ygot.Decimal64{}, fmt.Errorf("received DecimalVal is nil -- this is invalid")
+		}
+		s = ygot.Decimal64{Digits: v.DecimalVal.Digits, Precision: uint8(v.DecimalVal.Precision)}.String()
+	case *gpb.TypedValue_FloatVal:
+		s = strconv.FormatFloat(float64(v.FloatVal), 'f', -1, 32)
+	case *gpb.TypedValue_DoubleVal:
+		s = strconv.FormatFloat(v.DoubleVal, 'f', -1, 64)
+	default:
+		return ygot.Decimal64{}, fmt.Errorf("got %T, want a decimal, float or double value", tv.GetValue())
+	}
+	return parseDecimal64(s, schemaType)
+}
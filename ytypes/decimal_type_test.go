@@ -17,7 +17,9 @@ package ytypes
 import (
 	"testing"
 
+	"github.com/openconfig/gnmi/errdiff"
 	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/ygot"
 )
 
 const (
@@ -152,6 +154,63 @@ func TestValidateDecimalType(t *testing.T) {
 	}
 }
 
+func TestValidateDecimal64Type(t *testing.T) {
+	ranges, err := yang.ParseRangesDecimal("-0.05..0.05 | 10.10..100.50", 2)
+	if err != nil {
+		t.Fatalf("cannot parse ranges: %v", err)
+	}
+	schema := &yang.Entry{
+		Name: "decimal64-schema",
+		Type: &yang.YangType{
+			Kind:           yang.Ydecimal64,
+			FractionDigits: 2,
+			Range:          ranges,
+		},
+	}
+
+	tests := []struct {
+		desc             string
+		val              interface{}
+		wantErrSubstring string
+	}{{
+		desc: "unset",
+		val:  ygot.Decimal64{},
+	}, {
+		desc: "lower bound of range",
+		val:  ygot.Decimal64{Digits: -5, Precision: 2},
+	}, {
+		desc: "upper bound of range",
+		val:  ygot.Decimal64{Digits: 10050, Precision: 2},
+	}, {
+		desc: "upper bound of range with more precision",
+		val:  ygot.Decimal64{Digits: 1005000, Precision: 4},
+	}, {
+		desc: "fewer fraction digits",
+		val:  ygot.Decimal64{Digits: 505, Precision: 1},
+	}, {
+		desc:             "just outside range",
+		val:              ygot.Decimal64{Digits: 10051, Precision: 2},
+		wantErrSubstring: "decimal value 100.51 is outside specified ranges",
+	}, {
+		desc:             "between ranges",
+		val:              ygot.Decimal64{Digits: 1009, Precision: 2},
+		wantErrSubstring: "decimal value 10.09 is outside specified ranges",
+	}, {
+		desc:             "too many fraction digits",
+		val:              ygot.Decimal64{Digits: 1, Precision: 3},
+		wantErrSubstring: "cannot be represented with 2 fraction digits",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := validateDecimal(schema, tt.val)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Errorf("validateDecimal(%v): did not get expected error, %s", tt.val, diff)
+			}
+		})
+	}
+}
+
 func TestValidateDecimalValue(t *testing.T) {
 	tests := []struct {
 		desc      string
@@ -0,0 +1,373 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/internal/yreflect"
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+)
+
+// PopulateDefaultsOpt is an interface that is implemented by the options
+// that can be supplied to PopulateDefaults.
+type PopulateDefaultsOpt interface {
+	IsPopulateDefaultsOpt()
+}
+
+// ConfigDefaultsOnly is a PopulateDefaultsOpt that restricts the leaves that
+// are populated by PopulateDefaults to those that are config true, such that
+// only the effective configuration is populated.
+type ConfigDefaultsOnly struct{}
+
+// IsPopulateDefaultsOpt implements the PopulateDefaultsOpt interface.
+func (*ConfigDefaultsOnly) IsPopulateDefaultsOpt() {}
+
+// hasConfigDefaultsOnly determines whether there is an instance of
+// ConfigDefaultsOnly within the supplied PopulateDefaultsOpt slice.
+func hasConfigDefaultsOnly(opts []PopulateDefaultsOpt) bool {
+	for _, o := range opts {
+		if _, ok := o.(*ConfigDefaultsOnly); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// PopulateDefaults walks the data tree rooted at root, whose schema is
+// supplied, and sets each unset leaf and leaf-list that has a default value
+// to that value, such that the tree describes the effective configuration of
+// the node, for example before it is validated or compared to the state of a
+// device.
+//
+// The default value of a leaf is that of its default statement or, where it
+// has none and is not mandatory, that of its type. Non-presence containers
+// that are not populated are created where they contain a leaf that has a
+// default value, and are otherwise left unset. Presence containers and list
+// entries are never created, but the defaults within those that exist are
+// populated. The defaults within the cases of a choice are populated only
+// within the case that is populated, or, where no case of the choice is
+// populated, within its default case. Leaves and leaf-lists that are already
+// set are not modified.
+//
+// Unlike the PopulateDefaults methods that are optionally generated for
+// GoStructs, PopulateDefaults requires only the schema, and does not create
+// empty containers.
+func PopulateDefaults(schema *yang.Entry, root ygot.GoStruct, opts ...PopulateDefaultsOpt) error {
+	if schema == nil {
+		return fmt.Errorf("nil schema for root %T", root)
+	}
+	if util.IsValueNil(root) {
+		return fmt.Errorf("nil root for schema %s", schema.Name)
+	}
+	_, err := populateStructDefaults(schema, root, hasConfigDefaultsOnly(opts))
+	return err
+}
+
+// populateStructDefaults populates the defaults within the GoStruct parent,
+// whose schema is supplied. It returns true if any default was populated.
+func populateStructDefaults(schema *yang.Entry, parent ygot.GoStruct, configOnly bool) (bool, error) {
+	v := reflect.ValueOf(parent).Elem()
+	t := v.Type()
+
+	schemas := make([]*yang.Entry, t.NumField())
+	selected := map[*yang.Entry]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i)
+		if util.IsYgotAnnotation(ft) {
+			continue
+		}
+		cschema, err := util.ChildSchema(schema, ft)
+		if err != nil {
+			return false, fmt.Errorf("%s: %v", ft.Name, err)
+		}
+		if cschema == nil {
+			return false, fmt.Errorf("cannot find schema for field %s of %T", ft.Name, parent)
+		}
+		schemas[i] = cschema
+		if !isUnsetField(v.Field(i)) {
+			selectCases(schema, cschema, selected)
+		}
+	}
+
+	var populated bool
+	for i, cschema := range schemas {
+		if cschema == nil || !inActiveCase(schema, cschema, selected) {
+			continue
+		}
+		ft, fv := t.Field(i), v.Field(i)
+
+		var ok bool
+		var err error
+		switch {
+		case cschema.IsLeaf() || cschema.IsLeafList():
+			if configOnly && cschema.ReadOnly() {
+				continue
+			}
+			ok, err = populateLeafDefault(cschema, parent, fv)
+		case cschema.IsContainer():
+			ok, err = populateContainerDefaults(cschema, ft, fv, configOnly)
+		case cschema.IsList():
+			err = populateListDefaults(cschema, fv, configOnly)
+		}
+		if err != nil {
+			return false, err
+		}
+		populated = populated || ok
+	}
+	return populated, nil
+}
+
+// isUnsetField reports whether the struct field value v is unset.
+func isUnsetField(v reflect.Value) bool {
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Map {
+		return v.Len() == 0
+	}
+	return util.IsValueNilOrDefault(v.Interface())
+}
+
+// selectCases marks the cases, and choices, between the schema of a GoStruct
+// and the schema cschema of one of its populated fields as selected. A data
+// node that is a direct child of a choice is its own case.
+func selectCases(schema, cschema *yang.Entry, selected map[*yang.Entry]bool) {
+	for n := cschema; n.Parent != nil && n.Parent != schema; n = n.Parent {
+		if n.Parent.IsChoice() {
+			selected[n] = true
+			selected[n.Parent] = true
+		}
+	}
+}
+
+// inActiveCase reports whether the defaults of the field of a GoStruct with
+// schema cschema apply, based on the cases that are selected within the
+// GoStruct. They apply if, for each choice between the schema of the GoStruct
+// and cschema, the case containing cschema is selected, or no case is
+// selected and it is the default case of the choice.
+func inActiveCase(schema, cschema *yang.Entry, selected map[*yang.Entry]bool) bool {
+	for n := cschema; n.Parent != nil && n.Parent != schema; n = n.Parent {
+		c := n.Parent
+		if !c.IsChoice() {
+			continue
+		}
+		switch {
+		case selected[c] && !selected[n]:
+			return false
+		case !selected[c] && (len(c.Default) == 0 || c.Default[0] != n.Name):
+			return false
+		}
+	}
+	return true
+}
+
+// populateContainerDefaults populates the defaults within the container held
+// in the struct field ft, whose value is fv and whose schema is supplied. If
+// the container is unset and is not a presence container, it is created, and
+// is retained only if a default is populated within it. It returns true if
+// any default was populated.
+func populateContainerDefaults(schema *yang.Entry, ft reflect.StructField, fv reflect.Value, configOnly bool) (bool, error) {
+	if fv.Kind() != reflect.Ptr || fv.Type().Elem().Kind() != reflect.Struct {
+		return false, fmt.Errorf("field %s for container %s is not a struct pointer, got %v", ft.Name, schema.Name, fv.Type())
+	}
+
+	if !fv.IsNil() {
+		gs, ok := fv.Interface().(ygot.GoStruct)
+		if !ok {
+			return false, fmt.Errorf("field %s for container %s is not a GoStruct, got %T", ft.Name, schema.Name, fv.Interface())
+		}
+		return populateStructDefaults(schema, gs, configOnly)
+	}
+
+	if util.IsYangPresence(ft) || len(schema.Extra["presence"]) > 0 {
+		return false, nil
+	}
+	nv := reflect.New(fv.Type().Elem())
+	gs, ok := nv.Interface().(ygot.GoStruct)
+	if !ok {
+		return false, fmt.Errorf("field %s for container %s is not a GoStruct, got %T", ft.Name, schema.Name, nv.Interface())
+	}
+	populated, err := populateStructDefaults(schema, gs, configOnly)
+	if err != nil || !populated {
+		return false, err
+	}
+	fv.Set(nv)
+	return true, nil
+}
+
+// populateListDefaults populates the defaults within each entry of the list
+// held in the struct field value fv, whose schema is supplied.
+func populateListDefaults(schema *yang.Entry, fv reflect.Value, configOnly bool) error {
+	var err error
+	populateEntry := func(v reflect.Value) bool {
+		gs, ok := v.Interface().(ygot.GoStruct)
+		if !ok {
+			err = fmt.Errorf("entry of list %s is not a GoStruct, got %T", schema.Name, v.Interface())
+			return false
+		}
+		_, err = populateStructDefaults(schema, gs, configOnly)
+		return err == nil
+	}
+
+	if orderedMap, ok := fv.Interface().(ygot.GoOrderedMap); ok {
+		if util.IsValueNil(orderedMap) {
+			return nil
+		}
+		if rerr := yreflect.RangeOrderedMap(orderedMap, func(_ reflect.Value, v reflect.Value) bool {
+			return populateEntry(v)
+		}); rerr != nil {
+			return rerr
+		}
+		return err
+	}
+
+	switch fv.Kind() {
+	case reflect.Map:
+		for _, k := range fv.MapKeys() {
+			if !populateEntry(fv.MapIndex(k)) {
+				return err
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < fv.Len(); i++ {
+			if !populateEntry(fv.Index(i)) {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("list %s is not a map, slice or GoOrderedMap, got %v", schema.Name, fv.Type())
+	}
+	return nil
+}
+
+// populateLeafDefault sets the leaf or leaf-list within the GoStruct parent,
+// whose schema is supplied and whose value is fv, to its default value if it
+// is unset and has one. It returns true if the default was populated.
+func populateLeafDefault(schema *yang.Entry, parent ygot.GoStruct, fv reflect.Value) (bool, error) {
+	if !isUnsetField(fv) {
+		return false, nil
+	}
+	dvals := schemaDefaultValues(schema)
+	if len(dvals) == 0 {
+		return false, nil
+	}
+
+	var vals []interface{}
+	for _, d := range dvals {
+		v, err := defaultJSONValue(schema, parent, d)
+		if err != nil {
+			return false, err
+		}
+		vals = append(vals, v)
+	}
+
+	var value interface{} = vals[0]
+	if schema.IsLeafList() {
+		value = vals
+	}
+	if err := unmarshalGeneric(schema, parent, value, JSONEncoding); err != nil {
+		return false, fmt.Errorf("cannot populate default value %v of %s: %v", dvals, schema.Path(), err)
+	}
+	return true, nil
+}
+
+// schemaDefaultValues returns the default values of the leaf or leaf-list
+// schema. Where the schema has no default of its own, the default of its type
+// is returned, unless the leaf is mandatory, or the leaf-list has a minimum
+// number of elements. Unlike the DefaultValues method of yang.Entry, the
+// default of the type is also returned for a schema that has been
+// deserialized, and hence retains no YANG statements.
+func schemaDefaultValues(schema *yang.Entry) []string {
+	if len(schema.Default) > 0 {
+		return schema.Default
+	}
+	t := schema.Type
+	if t == nil || !t.HasDefault {
+		return nil
+	}
+	switch {
+	case schema.IsLeaf() && schema.Mandatory != yang.TSTrue, schema.IsLeafList() && (schema.ListAttr == nil || schema.ListAttr.MinElements == 0):
+		return []string{t.Default}
+	}
+	return nil
+}
+
+// defaultJSONValue returns the RFC7951 JSON representation of the default
+// value s of the leaf or leaf-list schema within the GoStruct parent. Where
+// the type of the schema is a union, the representation is that of the first
+// member type of the union for which s is a valid value.
+func defaultJSONValue(schema *yang.Entry, parent ygot.GoStruct, s string) (interface{}, error) {
+	rschema, err := util.ResolveIfLeafRef(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := defaultJSONCandidates(rschema.Type, s)
+	switch {
+	case len(candidates) == 0:
+		return nil, fmt.Errorf("invalid default value %q for %s of type %s", s, schema.Path(), rschema.Type.Kind)
+	case rschema.Type.Kind != yang.Yunion:
+		return candidates[0], nil
+	}
+
+	// Each representation is unmarshalled into a new instance of the
+	// parent, such that the parent is not modified by those that are not
+	// valid values of the union.
+	var errs util.Errors
+	for _, c := range candidates {
+		var v interface{} = c
+		if schema.IsLeafList() {
+			v = []interface{}{c}
+		}
+		scratch := reflect.New(reflect.TypeOf(parent).Elem()).Interface()
+		if err := unmarshalGeneric(schema, scratch, v, JSONEncoding); err != nil {
+			errs = util.AppendErr(errs, err)
+			continue
+		}
+		return c, nil
+	}
+	return nil, fmt.Errorf("invalid default value %q for union %s: %v", s, schema.Path(), errs)
+}
+
+// defaultJSONCandidates returns the possible RFC7951 JSON representations of
+// the default value s of a leaf of type t, in order of preference. It returns
+// no representations if s is not a valid value of a numerical or boolean type.
+func defaultJSONCandidates(t *yang.YangType, s string) []interface{} {
+	switch t.Kind {
+	case yang.Yunion:
+		var c []interface{}
+		for _, m := range t.Type {
+			c = append(c, defaultJSONCandidates(m, s)...)
+		}
+		return c
+	case yang.Yint8, yang.Yint16, yang.Yint32, yang.Yuint8, yang.Yuint16, yang.Yuint32:
+		// 64-bit integers and decimal64 values are represented as strings
+		// in RFC7951 JSON, whereas other numerical values are numbers.
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil
+		}
+		return []interface{}{f}
+	case yang.Ybool:
+		b, err := strconv.ParseBool(s)
+		if err != nil || (s != "true" && s != "false") {
+			return nil
+		}
+		return []interface{}{b}
+	}
+	return []interface{}{s}
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ygot/exampleoc"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+)
+
+func TestPopulateDefaultsMatchesGenerated(t *testing.T) {
+	build := func() *exampleoc.Device {
+		d := &exampleoc.Device{}
+		i := d.GetOrCreateInterface("eth0")
+		i.GetOrCreateSubinterface(1).GetOrCreateIpv4().GetOrCreateAddress("192.0.2.1").GetOrCreateVrrpGroup(1).AdvertisementInterval = ygot.Uint16(84)
+		d.GetOrCreateNetworkInstance("default")
+		return d
+	}
+
+	got := build()
+	if err := ytypes.PopulateDefaults(exampleoc.SchemaTree["Device"], got); err != nil {
+		t.Fatalf("PopulateDefaults: %v", err)
+	}
+	if i := got.GetInterface("eth0"); i.Enabled == nil || i.Tpid != exampleoc.VlanTypes_TPID_TYPES_TPID_0X8100 {
+		t.Errorf("PopulateDefaults: did not populate defaults of interface, got enabled: %v, tpid: %v", i.Enabled, i.Tpid)
+	}
+
+	want := build()
+	want.PopulateDefaults()
+	ygot.PruneEmptyBranches(want)
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-generated, +got):\n%s", diff)
+	}
+}
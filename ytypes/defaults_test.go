@@ -0,0 +1,263 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/testutil"
+	"github.com/openconfig/ygot/ygot"
+)
+
+type defaultsRootStruct struct {
+	Name     *string                         `path:"name"`
+	Mtu      *uint16                         `path:"mtu"`
+	Id       *uint32                         `path:"id"`
+	Enabled  *bool                           `path:"enabled"`
+	Big      *int64                          `path:"big"`
+	Tags     []string                        `path:"tags"`
+	Port     testutil.TestUnion              `path:"port"`
+	Label    testutil.TestUnion              `path:"label"`
+	TcpPort  *uint16                         `path:"tcp-port"`
+	UdpPort  *uint16                         `path:"udp-port"`
+	Counter  *uint64                         `path:"counter"`
+	Inner    *defaultsInnerStruct            `path:"inner"`
+	Presence *defaultsInnerStruct            `path:"presence"`
+	Empty    *defaultsEmptyStruct            `path:"empty"`
+	Entry    map[string]*defaultsEntryStruct `path:"entry"`
+}
+
+func (*defaultsRootStruct) IsYANGGoStruct()                          {}
+func (*defaultsRootStruct) ΛValidate(...ygot.ValidationOption) error { return nil }
+func (*defaultsRootStruct) ΛEnumTypeMap() map[string][]reflect.Type  { return nil }
+func (*defaultsRootStruct) ΛBelongingModule() string                 { return "foo" }
+
+func (*defaultsRootStruct) To_TestUnion(i interface{}) (testutil.TestUnion, error) {
+	switch v := i.(type) {
+	case string:
+		return testutil.UnionString(v), nil
+	case int16:
+		return testutil.UnionInt16(v), nil
+	}
+	return nil, fmt.Errorf("cannot convert %v to TestUnion, unknown union type, got: %T, want any of [string, int16]", i, i)
+}
+
+type defaultsInnerStruct struct {
+	Timer *uint32 `path:"timer"`
+}
+
+func (*defaultsInnerStruct) IsYANGGoStruct()                          {}
+func (*defaultsInnerStruct) ΛValidate(...ygot.ValidationOption) error { return nil }
+func (*defaultsInnerStruct) ΛEnumTypeMap() map[string][]reflect.Type  { return nil }
+func (*defaultsInnerStruct) ΛBelongingModule() string                 { return "foo" }
+
+type defaultsEmptyStruct struct {
+	Description *string `path:"description"`
+}
+
+func (*defaultsEmptyStruct) IsYANGGoStruct()                          {}
+func (*defaultsEmptyStruct) ΛValidate(...ygot.ValidationOption) error { return nil }
+func (*defaultsEmptyStruct) ΛEnumTypeMap() map[string][]reflect.Type  { return nil }
+func (*defaultsEmptyStruct) ΛBelongingModule() string                 { return "foo" }
+
+type defaultsEntryStruct struct {
+	Key    *string `path:"key"`
+	Weight *uint8  `path:"weight"`
+}
+
+func (*defaultsEntryStruct) IsYANGGoStruct()                          {}
+func (*defaultsEntryStruct) ΛValidate(...ygot.ValidationOption) error { return nil }
+func (*defaultsEntryStruct) ΛEnumTypeMap() map[string][]reflect.Type  { return nil }
+func (*defaultsEntryStruct) ΛBelongingModule() string                 { return "foo" }
+
+// defaultsSchema returns the schema of defaultsRootStruct.
+func defaultsSchema() *yang.Entry {
+	root := &yang.Entry{
+		Name: "root",
+		Kind: yang.DirectoryEntry,
+	}
+	add := func(parent, e *yang.Entry) *yang.Entry {
+		e.Parent = parent
+		if parent.Dir == nil {
+			parent.Dir = map[string]*yang.Entry{}
+		}
+		parent.Dir[e.Name] = e
+		return e
+	}
+	leaf := func(parent *yang.Entry, name string, kind yang.TypeKind, dflt ...string) *yang.Entry {
+		return add(parent, &yang.Entry{Name: name, Kind: yang.LeafEntry, Type: &yang.YangType{Kind: kind}, Default: dflt})
+	}
+	union := &yang.YangType{
+		Kind: yang.Yunion,
+		Type: []*yang.YangType{{Kind: yang.Yint16}, {Kind: yang.Ystring}},
+	}
+
+	leaf(root, "name", yang.Ystring, "default-name")
+	// The default of the mtu leaf is that of its typedef.
+	add(root, &yang.Entry{Name: "mtu", Kind: yang.LeafEntry, Type: &yang.YangType{Kind: yang.Yuint16, HasDefault: true, Default: "1500"}})
+	// The default of the typedef of a mandatory leaf does not apply.
+	add(root, &yang.Entry{Name: "id", Kind: yang.LeafEntry, Mandatory: yang.TSTrue, Type: &yang.YangType{Kind: yang.Yuint32, HasDefault: true, Default: "1"}})
+	leaf(root, "enabled", yang.Ybool, "true")
+	leaf(root, "big", yang.Yint64, "10")
+	add(root, &yang.Entry{Name: "tags", Kind: yang.LeafEntry, ListAttr: yang.NewDefaultListAttr(), Type: &yang.YangType{Kind: yang.Ystring}, Default: []string{"a", "b"}})
+	add(root, &yang.Entry{Name: "port", Kind: yang.LeafEntry, Type: union, Default: []string{"42"}})
+	add(root, &yang.Entry{Name: "label", Kind: yang.LeafEntry, Type: union, Default: []string{"auto"}})
+
+	choice := add(root, &yang.Entry{Name: "transport", Kind: yang.ChoiceEntry, Default: []string{"tcp"}})
+	leaf(add(choice, &yang.Entry{Name: "tcp", Kind: yang.CaseEntry}), "tcp-port", yang.Yuint16, "80")
+	leaf(add(choice, &yang.Entry{Name: "udp", Kind: yang.CaseEntry}), "udp-port", yang.Yuint16, "53")
+
+	counter := leaf(root, "counter", yang.Yuint64, "0")
+	counter.Config = yang.TSFalse
+
+	leaf(add(root, &yang.Entry{Name: "inner", Kind: yang.DirectoryEntry}), "timer", yang.Yuint32, "30")
+	leaf(add(root, &yang.Entry{
+		Name:  "presence",
+		Kind:  yang.DirectoryEntry,
+		Extra: map[string][]interface{}{"presence": {"true"}},
+	}), "timer", yang.Yuint32, "30")
+	leaf(add(root, &yang.Entry{Name: "empty", Kind: yang.DirectoryEntry}), "description", yang.Ystring)
+
+	list := add(root, &yang.Entry{Name: "entry", Kind: yang.DirectoryEntry, ListAttr: yang.NewDefaultListAttr(), Key: "key"})
+	leaf(list, "key", yang.Ystring)
+	leaf(list, "weight", yang.Yuint8, "1")
+	return root
+}
+
+func TestPopulateDefaults(t *testing.T) {
+	badDefaultSchema := defaultsSchema()
+	badDefaultSchema.Dir["mtu"].Type.Default = "jumbo"
+
+	badUnionDefaultSchema := defaultsSchema()
+	badUnionDefaultSchema.Dir["port"].Type = &yang.YangType{
+		Kind: yang.Yunion,
+		Type: []*yang.YangType{{Kind: yang.Yint16}, {Kind: yang.Yint64}},
+	}
+	badUnionDefaultSchema.Dir["port"].Default = []string{"abc"}
+
+	tests := []struct {
+		desc             string
+		inSchema         *yang.Entry
+		in               *defaultsRootStruct
+		inOpts           []PopulateDefaultsOpt
+		want             *defaultsRootStruct
+		wantErrSubstring string
+	}{{
+		desc:     "empty root",
+		inSchema: defaultsSchema(),
+		in:       &defaultsRootStruct{},
+		want: &defaultsRootStruct{
+			Name:    ygot.String("default-name"),
+			Mtu:     ygot.Uint16(1500),
+			Enabled: ygot.Bool(true),
+			Big:     ygot.Int64(10),
+			Tags:    []string{"a", "b"},
+			Port:    testutil.UnionInt16(42),
+			Label:   testutil.UnionString("auto"),
+			TcpPort: ygot.Uint16(80),
+			Counter: ygot.Uint64(0),
+			Inner:   &defaultsInnerStruct{Timer: ygot.Uint32(30)},
+		},
+	}, {
+		desc:     "set values, selected case, presence container and list entries",
+		inSchema: defaultsSchema(),
+		in: &defaultsRootStruct{
+			Name:     ygot.String("name"),
+			Tags:     []string{"c"},
+			UdpPort:  ygot.Uint16(5353),
+			Inner:    &defaultsInnerStruct{Timer: ygot.Uint32(10)},
+			Presence: &defaultsInnerStruct{},
+			Entry: map[string]*defaultsEntryStruct{
+				"one": {Key: ygot.String("one")},
+				"two": {Key: ygot.String("two"), Weight: ygot.Uint8(2)},
+			},
+		},
+		want: &defaultsRootStruct{
+			Name:     ygot.String("name"),
+			Mtu:      ygot.Uint16(1500),
+			Enabled:  ygot.Bool(true),
+			Big:      ygot.Int64(10),
+			Tags:     []string{"c"},
+			Port:     testutil.UnionInt16(42),
+			Label:    testutil.UnionString("auto"),
+			UdpPort:  ygot.Uint16(5353),
+			Counter:  ygot.Uint64(0),
+			Inner:    &defaultsInnerStruct{Timer: ygot.Uint32(10)},
+			Presence: &defaultsInnerStruct{Timer: ygot.Uint32(30)},
+			Entry: map[string]*defaultsEntryStruct{
+				"one": {Key: ygot.String("one"), Weight: ygot.Uint8(1)},
+				"two": {Key: ygot.String("two"), Weight: ygot.Uint8(2)},
+			},
+		},
+	}, {
+		desc:     "config defaults only",
+		inSchema: defaultsSchema(),
+		in:       &defaultsRootStruct{},
+		inOpts:   []PopulateDefaultsOpt{&ConfigDefaultsOnly{}},
+		want: &defaultsRootStruct{
+			Name:    ygot.String("default-name"),
+			Mtu:     ygot.Uint16(1500),
+			Enabled: ygot.Bool(true),
+			Big:     ygot.Int64(10),
+			Tags:    []string{"a", "b"},
+			Port:    testutil.UnionInt16(42),
+			Label:   testutil.UnionString("auto"),
+			TcpPort: ygot.Uint16(80),
+			Inner:   &defaultsInnerStruct{Timer: ygot.Uint32(30)},
+		},
+	}, {
+		desc:             "nil schema",
+		in:               &defaultsRootStruct{},
+		wantErrSubstring: "nil schema",
+	}, {
+		desc:             "nil root",
+		inSchema:         defaultsSchema(),
+		wantErrSubstring: "nil root",
+	}, {
+		desc:             "invalid default",
+		inSchema:         badDefaultSchema,
+		in:               &defaultsRootStruct{},
+		wantErrSubstring: `invalid default value "jumbo" for /root/mtu`,
+	}, {
+		desc:             "default that is not valid for any member of union",
+		inSchema:         badUnionDefaultSchema,
+		in:               &defaultsRootStruct{},
+		wantErrSubstring: `invalid default value "abc" for union /root/port`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var root ygot.GoStruct
+			if tt.in != nil {
+				root = tt.in
+			}
+			err := PopulateDefaults(tt.inSchema, root, tt.inOpts...)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, tt.in); diff != "" {
+				t.Errorf("(-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+)
+
+// RootDispatcher unmarshals RFC7951 JSON documents into the root GoStruct of
+// one of a set of registered schemas, such as the fake roots generated for the
+// models of different vendors. The schema is selected by inspecting the
+// top-level members of the document, such that a document is unmarshalled
+// once, rather than into each candidate root in turn.
+//
+// Schemas must be registered before the RootDispatcher is used. Once all
+// schemas are registered, the RootDispatcher may be used concurrently.
+type RootDispatcher struct {
+	roots []*dispatchRoot
+}
+
+// dispatchRoot is a schema that is registered with a RootDispatcher.
+type dispatchRoot struct {
+	// name is the name with which the schema was registered.
+	name string
+	// schema is the registered schema.
+	schema *Schema
+	// members is the set of top-level RFC7951 member names, qualified by
+	// the name of their module, that are accepted by the root.
+	members map[string]bool
+	// names is the set of unqualified top-level member names that are
+	// accepted by the root.
+	names map[string]bool
+}
+
+// NewRootDispatcher returns a RootDispatcher with no registered schemas.
+func NewRootDispatcher() *RootDispatcher {
+	return &RootDispatcher{}
+}
+
+// Register registers the schema under the supplied name as a candidate for
+// unmarshalling documents. The schema must have a Root, which is used only
+// to determine the type of root that is instantiated for each document, and
+// an Unmarshal function. Where a document is accepted by multiple schemas,
+// the schema that was registered first is selected.
+func (d *RootDispatcher) Register(name string, schema *Schema) error {
+	if schema == nil || util.IsValueNil(schema.Root) || schema.Unmarshal == nil {
+		return fmt.Errorf("cannot register schema %s: schema must have a root and an unmarshal function", name)
+	}
+	for _, r := range d.roots {
+		if r.name == name {
+			return fmt.Errorf("cannot register schema %s: name is already registered", name)
+		}
+	}
+	root := &dispatchRoot{
+		name:    name,
+		schema:  schema,
+		members: map[string]bool{},
+		names:   map[string]bool{},
+	}
+	t := reflect.TypeOf(schema.Root).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if util.IsYgotAnnotation(f) {
+			continue
+		}
+		paths, err := util.SchemaPaths(f)
+		if err != nil {
+			return fmt.Errorf("cannot register schema %s: %v", name, err)
+		}
+		modules := strings.Split(f.Tag.Get("module"), "|")
+		for j, p := range paths {
+			if len(p) == 0 {
+				continue
+			}
+			root.names[p[0]] = true
+			if j < len(modules) && modules[j] != "" {
+				root.members[fmt.Sprintf("%s:%s", strings.Split(modules[j], "/")[0], p[0])] = true
+			}
+		}
+	}
+	d.roots = append(d.roots, root)
+	return nil
+}
+
+// unknownMembers returns the top-level members of a document that are not
+// accepted by the root r. Members that are qualified by a module name must
+// match both the module and name of a member of the root, whereas unqualified
+// members need only match its name.
+func (r *dispatchRoot) unknownMembers(members []string) []string {
+	var unknown []string
+	for _, m := range members {
+		if strings.Contains(m, ":") {
+			if !r.members[m] {
+				unknown = append(unknown, m)
+			}
+			continue
+		}
+		if !r.names[m] {
+			unknown = append(unknown, m)
+		}
+	}
+	return unknown
+}
+
+// Select returns the name of the registered schema whose root accepts all of
+// the top-level members of the RFC7951 JSON document in data.
+func (d *RootDispatcher) Select(data []byte) (string, error) {
+	r, err := d.selectRoot(data)
+	if err != nil {
+		return "", err
+	}
+	return r.name, nil
+}
+
+// Unmarshal unmarshals the RFC7951 JSON document in data into a new instance
+// of the root of the registered schema that accepts all of its top-level
+// members, as determined by Select. It returns the name of the selected
+// schema along with the populated root. The supplied options are passed to
+// the Unmarshal function of the selected schema.
+func (d *RootDispatcher) Unmarshal(data []byte, opts ...UnmarshalOpt) (string, ygot.GoStruct, error) {
+	r, err := d.selectRoot(data)
+	if err != nil {
+		return "", nil, err
+	}
+	root := reflect.New(reflect.TypeOf(r.schema.Root).Elem()).Interface().(ygot.GoStruct)
+	if err := r.schema.Unmarshal(data, root, opts...); err != nil {
+		return r.name, nil, fmt.Errorf("cannot unmarshal document into schema %s: %v", r.name, err)
+	}
+	return r.name, root, nil
+}
+
+// selectRoot returns the first registered root that accepts all of the
+// top-level members of the RFC7951 JSON document in data. Metadata members,
+// whose names are prefixed with "@", are ignored.
+func (d *RootDispatcher) selectRoot(data []byte) (*dispatchRoot, error) {
+	if len(d.roots) == 0 {
+		return nil, fmt.Errorf("no schemas are registered")
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("cannot parse document: %v", err)
+	}
+	var members []string
+	for m := range doc {
+		if !strings.HasPrefix(m, "@") {
+			members = append(members, m)
+		}
+	}
+	sort.Strings(members)
+
+	var errs []string
+	for _, r := range d.roots {
+		unknown := r.unknownMembers(members)
+		if len(unknown) == 0 {
+			return r, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s does not contain %v", r.name, unknown))
+	}
+	return nil, fmt.Errorf("no registered schema accepts the top-level members of the document: %s", strings.Join(errs, "; "))
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygot/exampleoc"
+	"github.com/openconfig/ygot/integration_tests/schemaops/ctestschema"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+)
+
+func newTestRootDispatcher(t *testing.T) *ytypes.RootDispatcher {
+	t.Helper()
+	d := ytypes.NewRootDispatcher()
+	// The schemas are registered in order, since this determines the
+	// order in which they are considered.
+	for _, r := range []struct {
+		name   string
+		schema func() (*ytypes.Schema, error)
+	}{
+		{"ctestschema", ctestschema.Schema},
+		{"exampleoc", exampleoc.Schema},
+	} {
+		s, err := r.schema()
+		if err != nil {
+			t.Fatalf("cannot load schema %s: %v", r.name, err)
+		}
+		if err := d.Register(r.name, s); err != nil {
+			t.Fatalf("cannot register schema %s: %v", r.name, err)
+		}
+	}
+	return d
+}
+
+func TestRootDispatcher(t *testing.T) {
+	d := newTestRootDispatcher(t)
+
+	tests := []struct {
+		desc             string
+		in               string
+		wantName         string
+		wantRoot         ygot.GoStruct
+		wantErrSubstring string
+	}{{
+		desc:     "ctestschema document",
+		in:       `{"ctestschema:other-data": {"config": {"motd": "hello"}}}`,
+		wantName: "ctestschema",
+		wantRoot: &ctestschema.Device{
+			OtherData: &ctestschema.OtherData{Motd: ygot.String("hello")},
+		},
+	}, {
+		desc:     "ctestschema document with augmented member",
+		in:       `{"ctestschema:other-data": {}, "ctestschema-rootmod:ordered-multikeyed-lists": {}}`,
+		wantName: "ctestschema",
+		wantRoot: &ctestschema.Device{
+			OtherData: &ctestschema.OtherData{},
+		},
+	}, {
+		desc:     "exampleoc document",
+		in:       `{"openconfig-system:system": {"config": {"hostname": "dev"}}, "@": {}}`,
+		wantName: "exampleoc",
+		wantRoot: &exampleoc.Device{
+			System: &exampleoc.System{Hostname: ygot.String("dev")},
+		},
+	}, {
+		desc:     "unqualified member",
+		in:       `{"system": {"config": {"hostname": "dev"}}}`,
+		wantName: "exampleoc",
+		wantRoot: &exampleoc.Device{
+			System: &exampleoc.System{Hostname: ygot.String("dev")},
+		},
+	}, {
+		desc:             "member of wrong module",
+		in:               `{"ctestschema:system": {}}`,
+		wantErrSubstring: "ctestschema does not contain [ctestschema:system]; exampleoc does not contain [ctestschema:system]",
+	}, {
+		desc:             "members of different schemas",
+		in:               `{"ctestschema:other-data": {}, "openconfig-system:system": {}}`,
+		wantErrSubstring: "ctestschema does not contain [openconfig-system:system]; exampleoc does not contain [ctestschema:other-data]",
+	}, {
+		desc:             "invalid JSON",
+		in:               `{`,
+		wantErrSubstring: "cannot parse document",
+	}, {
+		desc:             "invalid document for selected schema",
+		in:               `{"openconfig-system:system": {"config": {"hostname": 42}}}`,
+		wantName:         "exampleoc",
+		wantErrSubstring: "cannot unmarshal document into schema exampleoc",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			gotName, gotRoot, err := d.Unmarshal([]byte(tt.in))
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("Unmarshal: %s", diff)
+			}
+			if gotName != tt.wantName {
+				t.Errorf("Unmarshal: got schema %q, want %q", gotName, tt.wantName)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.wantRoot, gotRoot); diff != "" {
+				t.Errorf("Unmarshal: did not get expected root (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRootDispatcherRegister(t *testing.T) {
+	d := newTestRootDispatcher(t)
+
+	s, err := exampleoc.Schema()
+	if err != nil {
+		t.Fatalf("cannot load schema: %v", err)
+	}
+	if err := d.Register("exampleoc", s); err == nil {
+		t.Errorf("Register: did not get expected error for duplicate name")
+	}
+	if err := d.Register("invalid", &ytypes.Schema{}); err == nil {
+		t.Errorf("Register: did not get expected error for invalid schema")
+	}
+
+	// A document accepted by multiple schemas is unmarshalled using the
+	// schema that was registered first.
+	if err := d.Register("exampleoc-copy", s); err != nil {
+		t.Fatalf("Register: got unexpected error: %v", err)
+	}
+	got, err := d.Select([]byte(`{"openconfig-interfaces:interfaces": {}}`))
+	if err != nil {
+		t.Fatalf("Select: got unexpected error: %v", err)
+	}
+	if got != "exampleoc" {
+		t.Errorf("Select: got schema %q, want exampleoc", got)
+	}
+
+	if _, err := ytypes.NewRootDispatcher().Select([]byte(`{}`)); err == nil {
+		t.Errorf("Select: did not get expected error with no registered schemas")
+	}
+}
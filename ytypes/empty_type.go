@@ -34,7 +34,7 @@ func validateEmpty(schema *yang.Entry, value interface{}) error {
 
 	if schema.Type.Kind == yang.Yempty {
 		if reflect.TypeOf(value).Name() != ygot.EmptyTypeName {
-			return fmt.Errorf("non derived type %T with value %v for schema %s", value, value, schema.Name)
+			return withIssueKind(TypeIssue, fmt.Errorf("non derived type %T with value %v for schema %s", value, value, schema.Name))
 		}
 	}
 
@@ -0,0 +1,30 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"github.com/openconfig/ygot/ygot"
+)
+
+// Fingerprint returns a hash of the populated leaves and leaf-lists of the
+// data tree rooted at root, covering both their paths and values, such that
+// a change in the tree can be detected by comparing the fingerprints of two
+// snapshots rather than computing the Diff between them.
+//
+// It is equivalent to calling ygot.Fingerprint without options, which should
+// be used where paths are to be excluded from the fingerprint.
+func Fingerprint(root ygot.GoStruct) (uint64, error) {
+	return ygot.Fingerprint(root)
+}
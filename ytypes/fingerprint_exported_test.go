@@ -0,0 +1,72 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes_test
+
+import (
+	"testing"
+
+	"github.com/openconfig/ygot/integration_tests/schemaops/ctestschema"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+)
+
+func TestFingerprint(t *testing.T) {
+	device := func(motd string) *ctestschema.Device {
+		return &ctestschema.Device{
+			OtherData: &ctestschema.OtherData{Motd: ygot.String(motd)},
+			UnorderedList: map[string]*ctestschema.UnorderedList{
+				"a": {Key: ygot.String("a"), Value: ygot.String("one")},
+			},
+		}
+	}
+
+	tests := []struct {
+		desc string
+		in   *ctestschema.Device
+	}{{
+		desc: "empty device",
+		in:   &ctestschema.Device{},
+	}, {
+		desc: "populated device",
+		in:   device("hello"),
+	}, {
+		desc: "modified device",
+		in:   device("goodbye"),
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ytypes.Fingerprint(tt.in)
+			if err != nil {
+				t.Fatalf("Fingerprint: got unexpected error: %v", err)
+			}
+			want, err := ygot.Fingerprint(tt.in)
+			if err != nil {
+				t.Fatalf("ygot.Fingerprint: got unexpected error: %v", err)
+			}
+			if got != want {
+				t.Errorf("Fingerprint: got %d, want %d as returned by ygot.Fingerprint", got, want)
+			}
+		})
+	}
+
+	hello, err := ytypes.Fingerprint(device("hello"))
+	if err != nil {
+		t.Fatalf("Fingerprint: got unexpected error: %v", err)
+	}
+	if goodbye, err := ytypes.Fingerprint(device("goodbye")); err != nil || goodbye == hello {
+		t.Errorf("Fingerprint of modified Device: got (%d, %v), want fingerprint distinct from %d", goodbye, err, hello)
+	}
+}
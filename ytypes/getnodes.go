@@ -0,0 +1,237 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"reflect"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// GetNodes retrieves the nodes specified by each of the supplied paths from
+// the specified root, whose schema must also be supplied. It returns the
+// nodes matching each path, in the same order as the input paths, such that
+// the ith element of the returned slice contains the nodes that GetNode
+// returns for the ith path.
+//
+// Paths that share a common prefix are retrieved by traversing the nodes
+// along the prefix once, rather than once per path, which reduces the cost of
+// retrieving many paths within the same subtree, e.g., those of a gNMI
+// GetRequest. The prefix is only shared up to the last GoStruct along it,
+// since the elements of a compressed path do not each correspond to a node.
+//
+// The options are handled in the same way as by GetNode, and a
+// GetTraversalBudget is shared between all paths. If any path cannot be
+// retrieved, the error for the first such path in the input order is
+// returned, along with the nodes of the paths that were retrieved.
+func GetNodes(schema *yang.Entry, root interface{}, paths []*gpb.Path, opts ...GetNodeOpt) ([][]*TreeNode, error) {
+	budget := getTraversalBudget(opts)
+	g := &bulkGetter{
+		args: retrieveNodeArgs{
+			partialKeyMatch:  hasPartialKeyMatch(opts),
+			handleWildcards:  hasHandleWildcards(opts),
+			tolerateNil:      hasGetTolerateNil(opts),
+			preferShadowPath: hasGetNodePreferShadowPath(opts),
+			budget:           budget,
+		},
+		results: make([][]*TreeNode, len(paths)),
+		errs:    make([]error, len(paths)),
+	}
+
+	items := make([]bulkGetItem, 0, len(paths))
+	for i, p := range paths {
+		items = append(items, bulkGetItem{idx: i, elems: p.GetElem()})
+	}
+	g.get(&TreeNode{Schema: schema, Data: root}, items)
+	for _, nodes := range g.results {
+		populateMetadata(nodes, opts)
+	}
+
+	if budget.exceeded() {
+		return g.results, budget.err()
+	}
+	for _, err := range g.errs {
+		if err != nil {
+			return g.results, err
+		}
+	}
+	return g.results, nil
+}
+
+// bulkGetItem is a path being retrieved by GetNodes.
+type bulkGetItem struct {
+	// idx is the index of the path in the input to GetNodes.
+	idx int
+	// elems are the elements of the path that remain to be traversed.
+	elems []*gpb.PathElem
+}
+
+// bulkGetter stores the state of a call to GetNodes.
+type bulkGetter struct {
+	// args are the arguments used for each traversal.
+	args retrieveNodeArgs
+	// results stores the nodes matched by each input path.
+	results [][]*TreeNode
+	// errs stores the error encountered when retrieving each input path.
+	errs []error
+}
+
+// get retrieves the remaining elements of each of the supplied items from
+// node, grouping the items whose paths share a prefix such that the prefix is
+// traversed once.
+func (g *bulkGetter) get(node *TreeNode, items []bulkGetItem) {
+	var groups [][]bulkGetItem
+	for _, it := range items {
+		if len(it.elems) == 0 {
+			g.results[it.idx] = append(g.results[it.idx], node)
+			continue
+		}
+		grouped := false
+		for i, grp := range groups {
+			if util.PathElemsEqual(grp[0].elems[0], it.elems[0]) {
+				groups[i] = append(grp, it)
+				grouped = true
+				break
+			}
+		}
+		if !grouped {
+			groups = append(groups, []bulkGetItem{it})
+		}
+	}
+
+	for _, grp := range groups {
+		n := 0
+		if len(grp) > 1 {
+			n = sharedNodeDepth(reflect.TypeOf(node.Data), commonPrefix(grp), g.args.preferShadowPath)
+		}
+		if n == 0 {
+			for _, it := range grp {
+				g.retrieve(node, it.elems, []bulkGetItem{it})
+			}
+			continue
+		}
+		g.retrieve(node, grp[0].elems[:n], grp)
+	}
+}
+
+// retrieve retrieves the nodes at prefix from node, and then the remainder
+// of each of the supplied items, which must share the prefix, from each of
+// the retrieved nodes.
+func (g *bulkGetter) retrieve(node *TreeNode, prefix []*gpb.PathElem, items []bulkGetItem) {
+	if g.args.budget.exceeded() {
+		return
+	}
+	matches, err := retrieveNode(node.Schema, node.Data, &gpb.Path{Elem: prefix}, node.Path, g.args)
+	if err != nil {
+		for _, it := range items {
+			g.errs[it.idx] = err
+		}
+		return
+	}
+	rem := make([]bulkGetItem, 0, len(items))
+	for _, it := range items {
+		rem = append(rem, bulkGetItem{idx: it.idx, elems: it.elems[len(prefix):]})
+	}
+	for _, m := range matches {
+		g.get(m, rem)
+	}
+}
+
+// commonPrefix returns the path elements that are shared by the paths of all
+// of the supplied items.
+func commonPrefix(items []bulkGetItem) []*gpb.PathElem {
+	prefix := items[0].elems
+	for _, it := range items[1:] {
+		i := 0
+		for ; i < len(prefix) && i < len(it.elems); i++ {
+			if !util.PathElemsEqual(prefix[i], it.elems[i]) {
+				break
+			}
+		}
+		prefix = prefix[:i]
+	}
+	return prefix
+}
+
+// sharedNodeDepth returns the number of elements of the supplied path,
+// relative to a node of type t, at which the deepest GoStruct along the path
+// is found, or 0 if there is no such GoStruct. It determines the depth using
+// the path tags of the fields of the GoStructs along the path, in the same
+// way as retrieveNodeContainer, such that a traversal can be stopped at the
+// returned depth and resumed from the node found there.
+func sharedNodeDepth(t reflect.Type, path []*gpb.PathElem, preferShadowPath bool) int {
+	var depth, last int
+	for t != nil && util.IsTypeStructPtr(t) && depth < len(path) {
+		rest := &gpb.Path{Elem: path[depth:]}
+		var (
+			ft      reflect.StructField
+			matched []string
+		)
+		for i := 0; i < t.Elem().NumField() && matched == nil; i++ {
+			f := t.Elem().Field(i)
+			if util.IsYgotAnnotation(f) {
+				continue
+			}
+			schPaths, err := util.SchemaPaths(f)
+			if err != nil {
+				return last
+			}
+			if preferShadowPath {
+				schPaths = append(util.ShadowSchemaPaths(f), schPaths...)
+			} else {
+				schPaths = append(schPaths, util.ShadowSchemaPaths(f)...)
+			}
+			for _, p := range schPaths {
+				if util.PathMatchesPrefix(rest, p) {
+					ft, matched = f, trimEmptyTrailing(p)
+					break
+				}
+			}
+		}
+		if len(matched) == 0 {
+			return last
+		}
+
+		if _, isOrderedMap := reflect.Zero(ft.Type).Interface().(ygot.GoOrderedMap); isOrderedMap {
+			// The type of the elements of an ordered map is not known
+			// without a value, so the traversal is not shared within it.
+			return last
+		}
+		depth += len(matched)
+		switch {
+		case util.IsTypeMap(ft.Type):
+			t = ft.Type.Elem()
+		case util.IsTypeStructPtr(ft.Type):
+			t = ft.Type
+		default:
+			return last
+		}
+		last = depth
+	}
+	return last
+}
+
+// trimEmptyTrailing returns the supplied schema path without its trailing
+// empty elements.
+func trimEmptyTrailing(p []string) []string {
+	for len(p) != 0 && p[len(p)-1] == "" {
+		p = p[:len(p)-1]
+	}
+	return p
+}
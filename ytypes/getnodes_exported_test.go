@@ -0,0 +1,144 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes_test
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmi/errdiff"
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/ygot/integration_tests/schemaops/ctestschema"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+)
+
+func TestGetNodes(t *testing.T) {
+	d := &ctestschema.Device{
+		OrderedList: ctestschema.GetOrderedMap(t),
+		OtherData:   &ctestschema.OtherData{Motd: ygot.String("hello")},
+		UnorderedList: map[string]*ctestschema.UnorderedList{
+			"one": {Key: ygot.String("one"), Value: ygot.String("one-val")},
+			"two": {Key: ygot.String("two"), Value: ygot.String("two-val")},
+		},
+	}
+
+	tests := []struct {
+		desc             string
+		inPaths          []*gpb.Path
+		inOpts           []ytypes.GetNodeOpt
+		wantErrSubstring string
+	}{{
+		desc: "paths sharing a list entry",
+		inPaths: []*gpb.Path{
+			mustPath("/unordered-lists/unordered-list[key=one]/config/value"),
+			mustPath("/other-data/config/motd"),
+			mustPath("/unordered-lists/unordered-list[key=one]/config/key"),
+			mustPath("/unordered-lists/unordered-list[key=one]"),
+			mustPath("/unordered-lists/unordered-list[key=two]/config/value"),
+		},
+	}, {
+		desc: "paths sharing a compressed container",
+		inPaths: []*gpb.Path{
+			mustPath("/unordered-lists/unordered-list[key=two]/state/value"),
+			mustPath("/unordered-lists/unordered-list[key=two]/state/key"),
+		},
+		inOpts: []ytypes.GetNodeOpt{&ytypes.PreferShadowPath{}},
+	}, {
+		desc: "paths sharing a wildcard",
+		inPaths: []*gpb.Path{
+			mustPath("/unordered-lists/unordered-list[key=*]/config/value"),
+			mustPath("/unordered-lists/unordered-list[key=*]/config/key"),
+			mustPath("/ordered-lists/ordered-list[key=*]/config/value"),
+		},
+		inOpts: []ytypes.GetNodeOpt{&ytypes.GetHandleWildcards{}},
+	}, {
+		desc: "the same path twice",
+		inPaths: []*gpb.Path{
+			mustPath("/other-data/config/motd"),
+			mustPath("/other-data/config/motd"),
+		},
+	}, {
+		desc: "missing list entry",
+		inPaths: []*gpb.Path{
+			mustPath("/other-data/config/motd"),
+			mustPath("/unordered-lists/unordered-list[key=three]/config/value"),
+			mustPath("/unordered-lists/unordered-list[key=three]/config/key"),
+		},
+	}, {
+		desc: "invalid path",
+		inPaths: []*gpb.Path{
+			mustPath("/unordered-lists/unordered-list[key=one]/config/does-not-exist"),
+			mustPath("/unordered-lists/unordered-list[key=one]/config/value"),
+		},
+		wantErrSubstring: "no match found",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ytypes.GetNodes(ctestschema.SchemaTree["Device"], d, tt.inPaths, tt.inOpts...)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("GetNodes: %s", diff)
+			}
+			if len(got) != len(tt.inPaths) {
+				t.Fatalf("GetNodes: got %d results, want %d", len(got), len(tt.inPaths))
+			}
+			for i, p := range tt.inPaths {
+				want, err := ytypes.GetNode(ctestschema.SchemaTree["Device"], d, p, tt.inOpts...)
+				if err != nil {
+					if got[i] != nil {
+						t.Errorf("GetNodes: got nodes %v for path %v, for which GetNode returned error %v", got[i], p, err)
+					}
+					continue
+				}
+				if err := treeNodesEqual(got[i], want); err != nil {
+					t.Errorf("GetNodes: did not get the same nodes as GetNode for path %v: %v", p, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGetNodesSharesTraversal(t *testing.T) {
+	d := &ctestschema.Device{
+		UnorderedList: map[string]*ctestschema.UnorderedList{
+			"one": {Key: ygot.String("one"), Value: ygot.String("one-val")},
+		},
+	}
+	paths := []*gpb.Path{
+		mustPath("/unordered-lists/unordered-list[key=one]/config/value"),
+		mustPath("/unordered-lists/unordered-list[key=one]/config/key"),
+	}
+
+	// Each path takes 4 steps to retrieve individually, whereas the
+	// shared traversal takes 3 steps to the list entry, and 2 steps from it
+	// to each leaf.
+	budget := &ytypes.GetTraversalBudget{MaxSteps: 7}
+	if _, err := ytypes.GetNodes(ctestschema.SchemaTree["Device"], d, paths, budget); err != nil {
+		t.Errorf("GetNodes: got unexpected error: %v", err)
+	}
+
+	var steps int
+	for _, p := range paths {
+		for n := 1; ; n++ {
+			if _, err := ytypes.GetNode(ctestschema.SchemaTree["Device"], d, p, &ytypes.GetTraversalBudget{MaxSteps: n}); err == nil {
+				steps += n
+				break
+			}
+		}
+	}
+	if steps <= budget.MaxSteps {
+		t.Errorf("GetNode: retrieving the paths individually took %d steps, want more than %d", steps, budget.MaxSteps)
+	}
+}
@@ -16,7 +16,6 @@ package ytypes
 
 import (
 	"fmt"
-	"reflect"
 
 	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/openconfig/ygot/util"
@@ -33,22 +32,69 @@ import (
 // using ygot.DeepCopy() if you wish to retain the value at schema.Root prior
 // to calling this function.
 //
+// If an OutOfOrderPolicy is supplied, updates and deletions whose timestamp
+// is older than that of a change already applied to the same path are handled
+// according to the policy.
+//
 // If an error occurs during unmarshalling, schema.Root may already be
 // modified. A rollback is not performed.
 func UnmarshalNotifications(schema *Schema, ns []*gpb.Notification, opts ...UnmarshalOpt) error {
+	return ApplyNotifications(schema.RootSchema(), schema.Root, ns, opts...)
+}
+
+// ApplyNotifications applies a slice of Notifications, in order, to the
+// supplied root GoStruct, whose schema must also be supplied. For each
+// Notification, the prefix is joined to the path of each deletion and
+// update, the deletions are applied, and then the updates are applied, as
+// specified by the gNMI specification. Updates may carry either scalar or
+// JSON-encoded values. An atomic Notification replaces the entire subtree at
+// its prefix. It *does not* perform validation after unmarshalling is
+// complete.
+//
+// By default, an error is returned if a path does not exist within the
+// schema. If a CollectUnknownPaths option is supplied, such deletions and
+// updates are instead skipped, and their paths are recorded within it, such
+// that they can be reported as warnings by the caller.
+//
+// If an OutOfOrderPolicy is supplied, updates and deletions whose timestamp
+// is older than that of a change already applied to the same path are handled
+// according to the policy.
+//
+// If an error occurs during unmarshalling, root may already be modified. A
+// rollback is not performed.
+func ApplyNotifications(schema *yang.Entry, root ygot.GoStruct, ns []*gpb.Notification, opts ...UnmarshalOpt) error {
+	ooPolicy := hasOutOfOrderPolicy(opts)
+	unknown := hasCollectUnknownPaths(opts)
 	for _, n := range ns {
 		deletePaths := n.Delete
 		if n.Atomic {
 			deletePaths = append(deletePaths, &gpb.Path{})
 		}
-		err := UnmarshalSetRequest(schema, &gpb.SetRequest{
+		updates := n.Update
+		if unknown != nil {
+			var err error
+			if updates, deletePaths, err = unknown.filter(schema, n.Prefix, updates, deletePaths); err != nil {
+				return err
+			}
+		}
+		var commit func()
+		if ooPolicy != nil {
+			var err error
+			if updates, deletePaths, commit, err = ooPolicy.filter(schema, n, deletePaths); err != nil {
+				return err
+			}
+		}
+		err := applySetRequest(schema, root, &gpb.SetRequest{
 			Prefix: n.Prefix,
 			Delete: deletePaths,
-			Update: n.Update,
+			Update: updates,
 		}, opts...)
 		if err != nil {
 			return err
 		}
+		if commit != nil {
+			commit()
+		}
 	}
 	return nil
 }
@@ -61,35 +107,85 @@ func UnmarshalNotifications(schema *Schema, ns []*gpb.Notification, opts ...Unma
 // to calling this function.
 //
 // If an error occurs during unmarshalling, schema.Root may already be
-// modified. A rollback is not performed.
+// modified. A rollback is not performed; use ApplyBatch to apply updates and
+// deletes atomically.
 func UnmarshalSetRequest(schema *Schema, req *gpb.SetRequest, opts ...UnmarshalOpt) error {
-	preferShadowPath := hasPreferShadowPath(opts)
-	ignoreExtraFields := hasIgnoreExtraFields(opts)
-	bestEffortUnmarshal := hasBestEffortUnmarshal(opts)
 	if req == nil {
 		return nil
 	}
-	root := schema.Root
-	rootName := reflect.TypeOf(root).Elem().Name()
+	return applySetRequest(schema.RootSchema(), schema.Root, req, opts...)
+}
+
+// CheckSetRequestIdempotent checks that the SetRequest req is idempotent
+// against the root GoStruct specified by "schema", i.e., that applying it to
+// the result of applying it to the root makes no further changes. This
+// catches bugs in the generation of SetRequests, such as merging the entries
+// of a user-ordered list, which succeed when first sent to a device but whose
+// retries do not.
+//
+// The SetRequest is applied to copies of schema.Root, which is not modified.
+// If the second application of req changes the tree, a Notification
+// containing the differences between the results of the first and second
+// applications is returned. If the second application fails, an error stating
+// that req is not idempotent is returned. The supplied UnmarshalOpts are used
+// for both applications.
+func CheckSetRequestIdempotent(schema *Schema, req *gpb.SetRequest, opts ...UnmarshalOpt) (*gpb.Notification, error) {
+	if req == nil {
+		return nil, nil
+	}
+	if schema == nil || util.IsValueNil(schema.Root) {
+		return nil, fmt.Errorf("invalid schema, root is nil: %v", schema)
+	}
+	first, err := ygot.DeepCopy(schema.Root)
+	if err != nil {
+		return nil, fmt.Errorf("cannot copy root: %v", err)
+	}
+	if err := applySetRequest(schema.RootSchema(), first, req, opts...); err != nil {
+		return nil, fmt.Errorf("cannot apply SetRequest: %v", err)
+	}
+	second, err := ygot.DeepCopy(first)
+	if err != nil {
+		return nil, fmt.Errorf("cannot copy root: %v", err)
+	}
+	if err := applySetRequest(schema.RootSchema(), second, req, opts...); err != nil {
+		return nil, fmt.Errorf("SetRequest is not idempotent, its second application failed: %v", err)
+	}
+
+	diff, err := ygot.Diff(first, second)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compare the results of applying the SetRequest: %v", err)
+	}
+	if len(diff.GetUpdate()) == 0 && len(diff.GetDelete()) == 0 {
+		return nil, nil
+	}
+	return diff, nil
+}
+
+// applySetRequest applies the SetRequest req to the GoStruct root, whose
+// schema is supplied.
+func applySetRequest(schema *yang.Entry, root ygot.GoStruct, req *gpb.SetRequest, opts ...UnmarshalOpt) error {
+	preferShadowPath := hasPreferShadowPath(opts)
+	ignoreExtraFields := hasIgnoreExtraFields(opts)
+	bestEffortUnmarshal := hasBestEffortUnmarshal(opts)
 
 	var complianceErrs *ComplianceErrors
 
 	// Process deletes, then replace, then updates.
-	if err := deletePaths(schema.SchemaTree[rootName], root, req.Prefix, req.Delete, preferShadowPath, bestEffortUnmarshal); err != nil {
+	if err := deletePaths(schema, root, req.Prefix, req.Delete, preferShadowPath, bestEffortUnmarshal); err != nil {
 		if bestEffortUnmarshal {
 			complianceErrs = complianceErrs.append(err.(*ComplianceErrors).Errors...)
 		} else {
 			return err
 		}
 	}
-	if err := replacePaths(schema.SchemaTree[rootName], root, req.Prefix, req.Replace, preferShadowPath, ignoreExtraFields, bestEffortUnmarshal); err != nil {
+	if err := replacePaths(schema, root, req.Prefix, req.Replace, preferShadowPath, ignoreExtraFields, bestEffortUnmarshal); err != nil {
 		if bestEffortUnmarshal {
 			complianceErrs = complianceErrs.append(err.(*ComplianceErrors).Errors...)
 		} else {
 			return err
 		}
 	}
-	if err := updatePaths(schema.SchemaTree[rootName], root, req.Prefix, req.Update, preferShadowPath, ignoreExtraFields, bestEffortUnmarshal); err != nil {
+	if err := updatePaths(schema, root, req.Prefix, req.Update, preferShadowPath, ignoreExtraFields, bestEffortUnmarshal); err != nil {
 		if bestEffortUnmarshal {
 			complianceErrs = complianceErrs.append(err.(*ComplianceErrors).Errors...)
 		} else {
@@ -103,6 +199,150 @@ func UnmarshalSetRequest(schema *Schema, req *gpb.SetRequest, opts ...UnmarshalO
 	return nil
 }
 
+// filter returns the subset of the supplied updates and deletions, whose
+// paths are relative to prefix, that refer to paths that exist within the
+// supplied schema, appending the paths of the remainder to c.Paths.
+func (c *CollectUnknownPaths) filter(schema *yang.Entry, prefix *gpb.Path, updates []*gpb.Update, dels []*gpb.Path) ([]*gpb.Update, []*gpb.Path, error) {
+	known := func(path *gpb.Path) (bool, error) {
+		p, err := util.JoinPaths(prefix, path)
+		if err != nil {
+			return false, fmt.Errorf("cannot join prefix with path: %v", err)
+		}
+		if !pathInSchema(schema, p) {
+			c.Paths = append(c.Paths, p)
+			return false, nil
+		}
+		return true, nil
+	}
+
+	var keptDels []*gpb.Path
+	for _, d := range dels {
+		ok, err := known(d)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			keptDels = append(keptDels, d)
+		}
+	}
+	var keptUpdates []*gpb.Update
+	for _, u := range updates {
+		ok, err := known(u.GetPath())
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			keptUpdates = append(keptUpdates, u)
+		}
+	}
+	return keptUpdates, keptDels, nil
+}
+
+// pathInSchema reports whether the data tree path p exists within the
+// supplied schema. Keys of the elements of p are not considered.
+func pathInSchema(schema *yang.Entry, p *gpb.Path) bool {
+	return schemaAtPath(schema, p) != nil
+}
+
+// schemaAtPath returns the schema of the node at the data tree path p within
+// the supplied schema, or nil if the path does not exist. Keys of the
+// elements of p are not considered.
+func schemaAtPath(schema *yang.Entry, p *gpb.Path) *yang.Entry {
+	e := schema
+	for _, pe := range p.GetElem() {
+		if e = childSchemaByName(e, pe.GetName()); e == nil {
+			return nil
+		}
+	}
+	return e
+}
+
+// childSchemaByName returns the schema of the data tree child of e with the
+// supplied name, which may be prefixed by a module name, or nil if there is
+// no such child.
+func childSchemaByName(e *yang.Entry, name string) *yang.Entry {
+	name = util.StripModulePrefix(name)
+	if child := e.Dir[name]; child != nil && !util.IsChoiceOrCase(child) {
+		return child
+	}
+	// The child may be within a choice or case node, which does not
+	// appear in data tree paths.
+	for _, c := range util.FindFirstNonChoiceOrCase(e) {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// GetNotifications retrieves the nodes specified by the supplied path from the
+// specified root, whose schema must also be supplied, and returns them as gNMI
+// Notifications with the timestamp ts, ready to be sent in response to a Get
+// or a Subscribe poll. The options have the same semantics as for GetNode.
+//
+// For the JSON and JSON_IETF encodings, each matched node is returned as a
+// single update whose path is the path of the node. For other encodings,
+// leaves are returned as scalar updates, and the leaves of matched containers
+// and list entries are returned as per ygot.TogNMINotifications, using the
+// path of the node as the prefix of the notifications.
+//
+// If a GetTraversalBudget option is supplied and the traversal exceeds it, a
+// *TraversalBudgetExceededError is returned, along with the notifications for
+// the nodes that were matched before the budget was exceeded if partial
+// results were requested.
+func GetNotifications(schema *yang.Entry, root interface{}, path *gpb.Path, enc gpb.Encoding, ts int64, opts ...GetNodeOpt) ([]*gpb.Notification, error) {
+	leaves := &gpb.Notification{Timestamp: ts}
+	var ns []*gpb.Notification
+	budget := getTraversalBudget(opts)
+	_, err := retrieveNode(schema, root, path, nil, retrieveNodeArgs{
+		partialKeyMatch:  hasPartialKeyMatch(opts),
+		handleWildcards:  hasHandleWildcards(opts),
+		tolerateNil:      hasGetTolerateNil(opts),
+		preferShadowPath: hasGetNodePreferShadowPath(opts),
+		budget:           budget,
+		visit: func(node *TreeNode) error {
+			if util.IsValueNil(node.Data) {
+				return nil
+			}
+			s, isStruct := node.Data.(ygot.GoStruct)
+			if isStruct && enc != gpb.Encoding_JSON && enc != gpb.Encoding_JSON_IETF {
+				sns, err := ygot.TogNMINotifications(s, ts, ygot.GNMINotificationsConfig{
+					UsePathElem:    true,
+					PathElemPrefix: node.Path.GetElem(),
+				})
+				if err != nil {
+					return fmt.Errorf("cannot render notifications for path %v: %v", node.Path, err)
+				}
+				ns = append(ns, sns...)
+				return nil
+			}
+			val, err := ygot.EncodeTypedValue(node.Data, enc, &ygot.RFC7951JSONConfig{})
+			if err != nil {
+				return fmt.Errorf("cannot encode value for path %v: %v", node.Path, err)
+			}
+			p := node.Path
+			if p == nil {
+				p = &gpb.Path{}
+			}
+			leaves.Update = append(leaves.Update, &gpb.Update{Path: p, Val: val})
+			return nil
+		},
+	})
+	switch {
+	case budget.exceeded():
+		if !budget.partial {
+			return nil, budget.err()
+		}
+		err = budget.err()
+	case err != nil:
+		return nil, err
+	}
+	if len(leaves.Update) != 0 {
+		ns = append([]*gpb.Notification{leaves}, ns...)
+	}
+	return ns, err
+}
+
 // deletePaths deletes a slice of paths from the given GoStruct.
 func deletePaths(schema *yang.Entry, goStruct ygot.GoStruct, prefix *gpb.Path, paths []*gpb.Path, preferShadowPath, bestEffortUnmarshal bool) error {
 	var dopts []DelNodeOpt
@@ -18,12 +18,15 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/openconfig/ygot/integration_tests/schemaops/ctestschema"
 	"github.com/openconfig/ygot/integration_tests/schemaops/utestschema"
 	"github.com/openconfig/ygot/internal/ytestutil"
+	"github.com/openconfig/ygot/testutil"
 	"github.com/openconfig/ygot/ygot"
 	"github.com/openconfig/ygot/ytypes"
+	"google.golang.org/protobuf/testing/protocmp"
 )
 
 func TestUnmarshalNotificationsOrderedMap(t *testing.T) {
@@ -149,3 +152,208 @@ func TestUnmarshalNotificationsOrderedMap(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckSetRequestIdempotent(t *testing.T) {
+	jsonVal := func(s string) *gpb.TypedValue {
+		return &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(s)}}
+	}
+	orderedList := `{"ctestschema:ordered-lists":{"ordered-list":[{"key":"foo","config":{"key":"foo"}}]}}`
+
+	tests := []struct {
+		desc             string
+		inRoot           ygot.GoStruct
+		inSetRequest     *gpb.SetRequest
+		want             *gpb.Notification
+		wantErrSubstring string
+	}{{
+		desc:   "idempotent deletes, replaces and updates",
+		inRoot: &ctestschema.Device{OrderedList: ctestschema.GetOrderedMap(t)},
+		inSetRequest: &gpb.SetRequest{
+			Delete: []*gpb.Path{mustPath("/ordered-lists/ordered-list[key=bar]")},
+			Replace: []*gpb.Update{{
+				Path: mustPath("/ordered-lists/ordered-list[key=foo]/config/value"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo-new"}},
+			}},
+			Update: []*gpb.Update{{
+				Path: mustPath("/unordered-lists/unordered-list[key=baz]/config/value"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "baz-val"}},
+			}},
+		},
+	}, {
+		desc:   "nil SetRequest",
+		inRoot: &ctestschema.Device{},
+	}, {
+		desc:   "merge of user-ordered list fails when reapplied",
+		inRoot: &ctestschema.Device{},
+		inSetRequest: &gpb.SetRequest{
+			Update: []*gpb.Update{{
+				Path: mustPath("/"),
+				Val:  jsonVal(orderedList),
+			}},
+		},
+		wantErrSubstring: "SetRequest is not idempotent",
+	}, {
+		desc:   "SetRequest cannot be applied",
+		inRoot: &ctestschema.Device{},
+		inSetRequest: &gpb.SetRequest{
+			Update: []*gpb.Update{{
+				Path: mustPath("/does-not-exist"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+			}},
+		},
+		wantErrSubstring: "cannot apply SetRequest",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			inRoot, err := ygot.DeepCopy(tt.inRoot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			schema := &ytypes.Schema{Root: inRoot, SchemaTree: ctestschema.SchemaTree}
+			got, err := ytypes.CheckSetRequestIdempotent(schema, tt.inSetRequest)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("CheckSetRequestIdempotent: did not get expected error, %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("CheckSetRequestIdempotent: did not get expected differences, diff(-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.inRoot, schema.Root, ytestutil.OrderedMapCmpOptions...); diff != "" {
+				t.Errorf("CheckSetRequestIdempotent: root was modified, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetNotifications(t *testing.T) {
+	newDevice := func() *ctestschema.Device {
+		return &ctestschema.Device{
+			UnorderedList: map[string]*ctestschema.UnorderedList{
+				"one": {Key: ygot.String("one"), Value: ygot.String("one-val")},
+				"two": {Key: ygot.String("two"), Value: ygot.String("two-val")},
+				"six": {Key: ygot.String("six")},
+			},
+		}
+	}
+	strVal := func(s string) *gpb.TypedValue {
+		return &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: s}}
+	}
+
+	tests := []struct {
+		desc             string
+		inPath           *gpb.Path
+		inEncoding       gpb.Encoding
+		inOpts           []ytypes.GetNodeOpt
+		want             []*gpb.Notification
+		wantErrSubstring string
+	}{{
+		desc:       "leaf",
+		inPath:     mustPath("/unordered-lists/unordered-list[key=one]/config/value"),
+		inEncoding: gpb.Encoding_JSON_IETF,
+		want: []*gpb.Notification{{
+			Timestamp: 42,
+			Update: []*gpb.Update{{
+				Path: mustPath("/unordered-lists/unordered-list[key=one]/config/value"),
+				Val:  strVal("one-val"),
+			}},
+		}},
+	}, {
+		desc:       "wildcard leaves",
+		inPath:     mustPath("/unordered-lists/unordered-list[key=*]/config/value"),
+		inEncoding: gpb.Encoding_PROTO,
+		inOpts:     []ytypes.GetNodeOpt{&ytypes.GetHandleWildcards{}},
+		want: []*gpb.Notification{{
+			Timestamp: 42,
+			Update: []*gpb.Update{{
+				Path: mustPath("/unordered-lists/unordered-list[key=one]/config/value"),
+				Val:  strVal("one-val"),
+			}, {
+				Path: mustPath("/unordered-lists/unordered-list[key=two]/config/value"),
+				Val:  strVal("two-val"),
+			}},
+		}},
+	}, {
+		desc:       "list entry in JSON_IETF",
+		inPath:     mustPath("/unordered-lists/unordered-list[key=two]"),
+		inEncoding: gpb.Encoding_JSON_IETF,
+		want: []*gpb.Notification{{
+			Timestamp: 42,
+			Update: []*gpb.Update{{
+				Path: mustPath("/unordered-lists/unordered-list[key=two]"),
+				Val: &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`{
+  "ctestschema:config": {
+    "key": "two",
+    "value": "two-val"
+  },
+  "ctestschema:key": "two"
+}`)}},
+			}},
+		}},
+	}, {
+		desc:       "list entry in PROTO",
+		inPath:     mustPath("/unordered-lists/unordered-list[key=two]"),
+		inEncoding: gpb.Encoding_PROTO,
+		want: []*gpb.Notification{{
+			Timestamp: 42,
+			Prefix:    mustPath("/unordered-lists/unordered-list[key=two]"),
+			Update: []*gpb.Update{{
+				Path: mustPath("config/key"),
+				Val:  strVal("two"),
+			}, {
+				Path: mustPath("key"),
+				Val:  strVal("two"),
+			}, {
+				Path: mustPath("config/value"),
+				Val:  strVal("two-val"),
+			}},
+		}},
+	}, {
+		desc:       "unpopulated leaf",
+		inPath:     mustPath("/unordered-lists/unordered-list[key=six]/config/value"),
+		inEncoding: gpb.Encoding_JSON_IETF,
+	}, {
+		desc:             "invalid path",
+		inPath:           mustPath("/unordered-lists/unordered-list[key=one]/config/does-not-exist"),
+		inEncoding:       gpb.Encoding_JSON_IETF,
+		wantErrSubstring: "no match found",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ytypes.GetNotifications(ctestschema.SchemaTree["Device"], newDevice(), tt.inPath, tt.inEncoding, 42, tt.inOpts...)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("GetNotifications: %s", diff)
+			}
+			if !testutil.NotificationSetEqual(got, tt.want) {
+				diff := cmp.Diff(tt.want, got, protocmp.Transform())
+				t.Errorf("GetNotifications: did not get expected notifications, (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetNotificationsTraversalBudget(t *testing.T) {
+	d := &ctestschema.Device{
+		UnorderedList: map[string]*ctestschema.UnorderedList{
+			"one": {Key: ygot.String("one"), Value: ygot.String("one-val")},
+			"two": {Key: ygot.String("two"), Value: ygot.String("two-val")},
+		},
+	}
+	path := mustPath("/unordered-lists/unordered-list[key=*]/config/value")
+
+	ns, err := ytypes.GetNotifications(ctestschema.SchemaTree["Device"], d, path, gpb.Encoding_JSON_IETF, 42, &ytypes.GetHandleWildcards{}, &ytypes.GetTraversalBudget{MaxSteps: 5})
+	if diff := errdiff.Substring(err, "traversal exceeded the budget of 5 steps"); diff != "" {
+		t.Fatalf("GetNotifications: %s", diff)
+	}
+	if len(ns) != 0 {
+		t.Errorf("GetNotifications: got %d notifications without partial results, want 0", len(ns))
+	}
+
+	ns, err = ytypes.GetNotifications(ctestschema.SchemaTree["Device"], d, path, gpb.Encoding_JSON_IETF, 42, &ytypes.GetHandleWildcards{}, &ytypes.GetTraversalBudget{MaxSteps: 5, PartialResults: true})
+	if diff := errdiff.Substring(err, "traversal exceeded the budget of 5 steps"); diff != "" {
+		t.Fatalf("GetNotifications: %s", diff)
+	}
+	if len(ns) != 1 || len(ns[0].Update) != 1 {
+		t.Errorf("GetNotifications: got notifications %v with partial results, want one update", ns)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
 	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/openconfig/ygot/ygot"
 
@@ -949,3 +950,380 @@ func TestUnmarshalNotifications(t *testing.T) {
 		})
 	}
 }
+
+func TestUnmarshalNotificationsOutOfOrder(t *testing.T) {
+	stringUpdate := func(path, val string) *gpb.Update {
+		return &gpb.Update{
+			Path: mustPath(path),
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: val}},
+		}
+	}
+
+	outOfOrder := []*gpb.Notification{{
+		Timestamp: 20,
+		Update: []*gpb.Update{
+			stringUpdate("/key1", "new"),
+			stringUpdate("/outer/inner/string-leaf-field", "new"),
+		},
+	}, {
+		Timestamp: 10,
+		Update: []*gpb.Update{
+			stringUpdate("/key1", "old"),
+			stringUpdate("/outer/inner/string-leaf-field", "old"),
+		},
+	}}
+
+	tests := []struct {
+		desc             string
+		inRoot           *ListElemStruct1
+		inNotifications  []*gpb.Notification
+		inPolicy         *OutOfOrderPolicy
+		want             ygot.GoStruct
+		wantErrSubstring string
+	}{{
+		desc:            "last write wins",
+		inNotifications: outOfOrder,
+		inPolicy:        &OutOfOrderPolicy{Default: TimestampLastWriteWins},
+		want: &ListElemStruct1{
+			Key1: ygot.String("old"),
+			Outer: &OuterContainerType1{
+				Inner: &InnerContainerType1{
+					StringLeafName: ygot.String("old"),
+				},
+			},
+		},
+	}, {
+		desc:            "drop older",
+		inNotifications: outOfOrder,
+		inPolicy:        &OutOfOrderPolicy{Default: TimestampDropOlder},
+		want: &ListElemStruct1{
+			Key1: ygot.String("new"),
+			Outer: &OuterContainerType1{
+				Inner: &InnerContainerType1{
+					StringLeafName: ygot.String("new"),
+				},
+			},
+		},
+	}, {
+		desc:             "error",
+		inNotifications:  outOfOrder,
+		inPolicy:         &OutOfOrderPolicy{Default: TimestampError},
+		wantErrSubstring: "change to path /key1 has timestamp 10, which is older than the previously applied timestamp 20",
+	}, {
+		desc:            "per-path policy",
+		inNotifications: outOfOrder,
+		inPolicy: &OutOfOrderPolicy{
+			Default: TimestampDropOlder,
+			Paths: []*PathTimestampPolicy{{
+				Path:   mustPath("/outer"),
+				Policy: TimestampError,
+			}, {
+				Path:   mustPath("/outer/inner"),
+				Policy: TimestampLastWriteWins,
+			}},
+		},
+		want: &ListElemStruct1{
+			Key1: ygot.String("new"),
+			Outer: &OuterContainerType1{
+				Inner: &InnerContainerType1{
+					StringLeafName: ygot.String("old"),
+				},
+			},
+		},
+	}, {
+		desc: "in order notifications with prefix",
+		inNotifications: []*gpb.Notification{{
+			Timestamp: 10,
+			Prefix:    mustPath("/outer/inner"),
+			Update:    []*gpb.Update{stringUpdate("string-leaf-field", "old")},
+		}, {
+			Timestamp: 20,
+			Update:    []*gpb.Update{stringUpdate("/outer/inner/string-leaf-field", "new")},
+		}},
+		inPolicy: &OutOfOrderPolicy{Default: TimestampError},
+		want: &ListElemStruct1{
+			Outer: &OuterContainerType1{
+				Inner: &InnerContainerType1{
+					StringLeafName: ygot.String("new"),
+				},
+			},
+		},
+	}, {
+		desc: "stale update within deleted subtree",
+		inRoot: &ListElemStruct1{
+			Outer: &OuterContainerType1{
+				Inner: &InnerContainerType1{
+					StringLeafName: ygot.String("bear"),
+				},
+			},
+		},
+		inNotifications: []*gpb.Notification{{
+			Timestamp: 20,
+			Delete:    []*gpb.Path{mustPath("/outer/inner")},
+		}, {
+			Timestamp: 10,
+			Update:    []*gpb.Update{stringUpdate("/outer/inner/string-leaf-field", "old")},
+		}},
+		inPolicy: &OutOfOrderPolicy{Default: TimestampDropOlder},
+		want:     &ListElemStruct1{},
+	}, {
+		desc: "stale deletion",
+		inNotifications: []*gpb.Notification{{
+			Timestamp: 20,
+			Update:    []*gpb.Update{stringUpdate("/key1", "new")},
+		}, {
+			Timestamp: 10,
+			Delete:    []*gpb.Path{mustPath("/key1")},
+		}},
+		inPolicy: &OutOfOrderPolicy{Default: TimestampDropOlder},
+		want: &ListElemStruct1{
+			Key1: ygot.String("new"),
+		},
+	}, {
+		desc: "rejected notification does not record timestamps",
+		inNotifications: []*gpb.Notification{{
+			Timestamp: 20,
+			Update:    []*gpb.Update{stringUpdate("/outer/inner/string-leaf-field", "new")},
+		}, {
+			Timestamp: 15,
+			Update: []*gpb.Update{
+				stringUpdate("/key1", "rejected"),
+				stringUpdate("/outer/inner/string-leaf-field", "rejected"),
+			},
+		}, {
+			Timestamp: 12,
+			Update:    []*gpb.Update{stringUpdate("/key1", "accepted")},
+		}},
+		inPolicy:         &OutOfOrderPolicy{Default: TimestampError},
+		wantErrSubstring: "change to path /outer/inner/string-leaf-field has timestamp 15, which is older than the previously applied timestamp 20",
+		want: &ListElemStruct1{
+			Key1: ygot.String("accepted"),
+			Outer: &OuterContainerType1{
+				Inner: &InnerContainerType1{
+					StringLeafName: ygot.String("new"),
+				},
+			},
+		},
+	}, {
+		desc: "stale deletion of ancestor",
+		inNotifications: []*gpb.Notification{{
+			Timestamp: 10,
+			Update:    []*gpb.Update{stringUpdate("/outer/inner/string-leaf-field", "new")},
+		}, {
+			Timestamp: 5,
+			Delete:    []*gpb.Path{mustPath("/outer")},
+		}},
+		inPolicy:         &OutOfOrderPolicy{Default: TimestampError},
+		wantErrSubstring: "change to path /outer has timestamp 5, which is older than the previously applied timestamp 10",
+		want: &ListElemStruct1{
+			Outer: &OuterContainerType1{
+				Inner: &InnerContainerType1{
+					StringLeafName: ygot.String("new"),
+				},
+			},
+		},
+	}, {
+		desc: "stale update of leaf within JSON container update",
+		inNotifications: []*gpb.Notification{{
+			Timestamp: 20,
+			Update: []*gpb.Update{{
+				Path: mustPath("/outer"),
+				Val: &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{
+					JsonIetfVal: []byte(`{"inner": {"string-leaf-field": "new"}}`),
+				}},
+			}},
+		}, {
+			Timestamp: 10,
+			Update:    []*gpb.Update{stringUpdate("/outer/inner/string-leaf-field", "old")},
+		}},
+		inPolicy: &OutOfOrderPolicy{Default: TimestampDropOlder},
+		want: &ListElemStruct1{
+			Outer: &OuterContainerType1{
+				Inner: &InnerContainerType1{
+					StringLeafName: ygot.String("new"),
+				},
+			},
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			root := tt.inRoot
+			if root == nil {
+				root = &ListElemStruct1{}
+			}
+			schema := &Schema{
+				Root: root,
+				SchemaTree: map[string]*yang.Entry{
+					"ListElemStruct1": simpleSchema(),
+				},
+			}
+			// Each Notification is applied separately, such that the
+			// timestamps recorded by the policy must be retained
+			// between calls. Notifications following one that is
+			// rejected are still applied.
+			var err error
+			for _, n := range tt.inNotifications {
+				if nerr := UnmarshalNotifications(schema, []*gpb.Notification{n}, tt.inPolicy); nerr != nil && err == nil {
+					err = nerr
+				}
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("UnmarshalNotifications: %s", diff)
+			}
+			if tt.want == nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, schema.Root); diff != "" {
+				t.Errorf("UnmarshalNotifications: (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyNotifications(t *testing.T) {
+	stringUpdate := func(path, val string) *gpb.Update {
+		return &gpb.Update{
+			Path: mustPath(path),
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: val}},
+		}
+	}
+
+	tests := []struct {
+		desc             string
+		inRoot           *ListElemStruct1
+		inNotifications  []*gpb.Notification
+		inCollect        bool
+		want             *ListElemStruct1
+		wantUnknown      []string
+		wantErrSubstring string
+	}{{
+		desc: "scalar and JSON updates, and deletes, with prefix",
+		inRoot: &ListElemStruct1{
+			Key1: ygot.String("hello"),
+			Outer: &OuterContainerType1{
+				Inner: &InnerContainerType1{
+					Int32LeafName: ygot.Int32(42),
+				},
+			},
+		},
+		inNotifications: []*gpb.Notification{{
+			Prefix: mustPath("/outer/config/inner"),
+			Delete: []*gpb.Path{mustPath("int32-leaf-field")},
+			Update: []*gpb.Update{
+				stringUpdate("string-leaf-field", "bear"),
+				{
+					Path: mustPath("int32-leaf-list"),
+					Val: &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{
+						JsonIetfVal: []byte(`[1, 2]`),
+					}},
+				},
+			},
+		}, {
+			Delete: []*gpb.Path{mustPath("/key1")},
+		}},
+		want: &ListElemStruct1{
+			Outer: &OuterContainerType1{
+				Inner: &InnerContainerType1{
+					Int32LeafListName: []int32{1, 2},
+					StringLeafName:    ygot.String("bear"),
+				},
+			},
+		},
+	}, {
+		desc: "notifications applied in order",
+		inNotifications: []*gpb.Notification{{
+			Update: []*gpb.Update{stringUpdate("/key1", "first")},
+		}, {
+			Delete: []*gpb.Path{mustPath("/key1")},
+			Update: []*gpb.Update{stringUpdate("/key1", "second")},
+		}},
+		want: &ListElemStruct1{
+			Key1: ygot.String("second"),
+		},
+	}, {
+		desc: "atomic notification replaces prefix",
+		inRoot: &ListElemStruct1{
+			Outer: &OuterContainerType1{
+				Inner: &InnerContainerType1{
+					Int32LeafName: ygot.Int32(42),
+				},
+			},
+		},
+		inNotifications: []*gpb.Notification{{
+			Prefix: mustPath("/outer/config/inner"),
+			Atomic: true,
+			Update: []*gpb.Update{stringUpdate("string-leaf-field", "bear")},
+		}},
+		want: &ListElemStruct1{
+			Outer: &OuterContainerType1{
+				Inner: &InnerContainerType1{
+					StringLeafName: ygot.String("bear"),
+				},
+			},
+		},
+	}, {
+		desc: "unknown path rejected",
+		inNotifications: []*gpb.Notification{{
+			Update: []*gpb.Update{stringUpdate("/outer/unknown", "bear")},
+		}},
+		wantErrSubstring: "no match found in *ytypes.OuterContainerType1",
+	}, {
+		desc: "unknown paths collected",
+		inNotifications: []*gpb.Notification{{
+			Prefix: mustPath("/outer"),
+			Delete: []*gpb.Path{mustPath("does-not-exist")},
+			Update: []*gpb.Update{
+				stringUpdate("unknown", "bear"),
+				stringUpdate("config/inner/string-leaf-field", "bear"),
+			},
+		}, {
+			Update: []*gpb.Update{stringUpdate("/key1", "hello")},
+		}},
+		inCollect: true,
+		want: &ListElemStruct1{
+			Key1: ygot.String("hello"),
+			Outer: &OuterContainerType1{
+				Inner: &InnerContainerType1{
+					StringLeafName: ygot.String("bear"),
+				},
+			},
+		},
+		wantUnknown: []string{"/outer/does-not-exist", "/outer/unknown"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			root := tt.inRoot
+			if root == nil {
+				root = &ListElemStruct1{}
+			}
+			var opts []UnmarshalOpt
+			collect := &CollectUnknownPaths{}
+			if tt.inCollect {
+				opts = append(opts, collect)
+			}
+			err := ApplyNotifications(simpleSchema(), root, tt.inNotifications, opts...)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("ApplyNotifications: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, root); diff != "" {
+				t.Errorf("ApplyNotifications: (-want, +got):\n%s", diff)
+			}
+			var gotUnknown []string
+			for _, p := range collect.Paths {
+				s, err := ygot.PathToString(p)
+				if err != nil {
+					t.Fatalf("cannot convert path %v to string: %v", p, err)
+				}
+				gotUnknown = append(gotUnknown, s)
+			}
+			if diff := cmp.Diff(tt.wantUnknown, gotUnknown); diff != "" {
+				t.Errorf("ApplyNotifications: unknown paths (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -18,7 +18,6 @@ import (
 	"fmt"
 	"reflect"
 
-	log "github.com/golang/glog"
 	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/openconfig/ygot/util"
 )
@@ -60,8 +59,15 @@ var (
 // schema's range restrictions (if any). It returns an error if the validation
 // fails.
 func ValidateIntRestrictions(schemaType *yang.YangType, intVal int64) error {
+	return validateIntRestrictions(nil, schemaType, intVal)
+}
+
+// validateIntRestrictions implements ValidateIntRestrictions for the type of
+// the leaf schema, which, if supplied, is used to find the range statement
+// that defines the restriction that is not satisfied.
+func validateIntRestrictions(schema *yang.Entry, schemaType *yang.YangType, intVal int64) error {
 	if !isInRanges(schemaType.Range, yang.FromInt(intVal)) {
-		return fmt.Errorf("signed integer value %v is outside specified ranges", intVal)
+		return newRangeError(schema, schemaType, intVal, fmt.Sprintf("signed integer value %v is outside specified ranges", intVal))
 	}
 	return nil
 }
@@ -70,8 +76,15 @@ func ValidateIntRestrictions(schemaType *yang.YangType, intVal int64) error {
 // schema's range restrictions (if any). It returns an error if the validation
 // fails.
 func ValidateUintRestrictions(schemaType *yang.YangType, uintVal uint64) error {
+	return validateUintRestrictions(nil, schemaType, uintVal)
+}
+
+// validateUintRestrictions implements ValidateUintRestrictions for the type of
+// the leaf schema, which, if supplied, is used to find the range statement
+// that defines the restriction that is not satisfied.
+func validateUintRestrictions(schema *yang.Entry, schemaType *yang.YangType, uintVal uint64) error {
 	if !isInRanges(schemaType.Range, yang.FromUint(uintVal)) {
-		return fmt.Errorf("unsigned integer value %v is outside specified ranges", uintVal)
+		return newRangeError(schema, schemaType, uintVal, fmt.Sprintf("unsigned integer value %v is outside specified ranges", uintVal))
 	}
 	return nil
 }
@@ -90,17 +103,17 @@ func validateInt(schema *yang.Entry, value interface{}) error {
 
 	// Check that type of value is the type expected from the schema.
 	if typeKindFromKind[reflect.TypeOf(value).Kind()] != kind {
-		return fmt.Errorf("non %v type %T with value %v for schema %s", kind, value, value, schema.Name)
+		return withIssueKind(TypeIssue, fmt.Errorf("non %v type %T with value %v for schema %s", kind, value, value, schema.Name))
 	}
 
 	// Check that the value satisfies any range restrictions.
 	if isSigned(kind) {
-		if err := ValidateIntRestrictions(schema.Type, reflect.ValueOf(value).Int()); err != nil {
-			return fmt.Errorf("schema %q: %v", schema.Name, err)
+		if err := validateIntRestrictions(schema, schema.Type, reflect.ValueOf(value).Int()); err != nil {
+			return fmt.Errorf("schema %q: %w", schema.Name, err)
 		}
 	} else {
-		if err := ValidateUintRestrictions(schema.Type, reflect.ValueOf(value).Uint()); err != nil {
-			return fmt.Errorf("schema %q: %v", schema.Name, err)
+		if err := validateUintRestrictions(schema, schema.Type, reflect.ValueOf(value).Uint()); err != nil {
+			return fmt.Errorf("schema %q: %w", schema.Name, err)
 		}
 	}
 
@@ -128,7 +141,7 @@ func validateIntSlice(schema *yang.Entry, value interface{}) error {
 	// Each slice element must be valid.
 	for i := 0; i < val.Len(); i++ {
 		if err := validateInt(schema, val.Index(i).Interface()); err != nil {
-			return fmt.Errorf("invalid element at index %d: %v for schema %s", i, err, schema.Name)
+			return fmt.Errorf("invalid element at index %d: %w for schema %s", i, err, schema.Name)
 		}
 	}
 
@@ -215,7 +228,7 @@ func legalValue(schema *yang.Entry, val yang.Number) bool {
 	case yang.Yuint64:
 		return yang.Uint64Range.Contains(yr)
 	default:
-		log.Errorf("illegal type %v in legalValue", schema.Type.Kind)
+		util.Log().Errorf("illegal type %v in legalValue", schema.Type.Kind)
 	}
 	return false
 }
@@ -16,12 +16,12 @@ package ytypes
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"reflect"
 	"strconv"
 
-	log "github.com/golang/glog"
 	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/openconfig/ygot/internal/yreflect"
 	"github.com/openconfig/ygot/util"
@@ -57,28 +57,51 @@ func validateLeaf(inSchema *yang.Entry, value interface{}) util.Errors {
 
 	rv := value
 	ykind := schema.Type.Kind
+
+	// User-specified types for binary and empty leaves are validated
+	// according to the values that they represent, regardless of their kind.
+	switch v := value.(type) {
+	case ygot.BinaryValue:
+		if ykind != yang.Ybinary {
+			return util.NewErrs(withIssueKind(TypeIssue, fmt.Errorf("bad leaf type: got binary value type %T for schema %s, have type %v", value, schema.Name, ykind)))
+		}
+		return util.NewErrs(validateBinaryValue(schema, v))
+	case ygot.EmptyValue:
+		if ykind != yang.Yempty {
+			return util.NewErrs(withIssueKind(TypeIssue, fmt.Errorf("bad leaf type: got empty value type %T for schema %s, have type %v", value, schema.Name, ykind)))
+		}
+		return util.NewErrs(validateEmptySchema(schema))
+	}
+
 	rkind := reflect.ValueOf(value).Kind()
 	switch rkind {
 	case reflect.Ptr:
 		rv = reflect.ValueOf(value).Elem().Interface()
 	case reflect.Slice:
 		if ykind != yang.Ybinary && ykind != yang.Yunion {
-			return util.NewErrs(fmt.Errorf("bad leaf type: expect []byte for binary value %v for schema %s, have type %v", value, schema.Name, ykind))
+			return util.NewErrs(withIssueKind(TypeIssue, fmt.Errorf("bad leaf type: expect []byte for binary value %v for schema %s, have type %v", value, schema.Name, ykind)))
 		}
 	case reflect.Int64:
 		if ykind != yang.Yenum && ykind != yang.Yidentityref && ykind != yang.Yunion {
-			return util.NewErrs(fmt.Errorf("bad leaf type: expect Int64 for enum type for schema %s, have type %v", schema.Name, ykind))
+			return util.NewErrs(withIssueKind(TypeIssue, fmt.Errorf("bad leaf type: expect Int64 for enum type for schema %s, have type %v", schema.Name, ykind)))
 		}
 	case reflect.Bool:
 		if ykind != yang.Yempty {
-			return util.NewErrs(fmt.Errorf("bad leaf type: expect Bool for empty type for schema %s, have type %v", schema.Name, ykind))
+			return util.NewErrs(withIssueKind(TypeIssue, fmt.Errorf("bad leaf type: expect Bool for empty type for schema %s, have type %v", schema.Name, ykind)))
 		}
 	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float64, reflect.String:
 		if ykind != yang.Yunion {
-			return util.NewErrs(fmt.Errorf("bad leaf type: expect %v for union type for schema %s, have type %v", rkind, schema.Name, ykind))
+			return util.NewErrs(withIssueKind(TypeIssue, fmt.Errorf("bad leaf type: expect %v for union type for schema %s, have type %v", rkind, schema.Name, ykind)))
+		}
+	case reflect.Struct:
+		// Decimal64 leaves are not pointers, since the zero value of
+		// ygot.Decimal64 indicates that the leaf is unset. Simple union
+		// values of the Decimal64 type are also structs.
+		if _, ok := decimal64Value(reflect.ValueOf(value)); !ok || ykind != yang.Ydecimal64 && ykind != yang.Yunion {
+			return util.NewErrs(withIssueKind(TypeIssue, fmt.Errorf("bad leaf value type %T for schema %s, have type %v", value, schema.Name, ykind)))
 		}
 	default:
-		return util.NewErrs(fmt.Errorf("bad leaf value type %v, expect Ptr or Int64 for schema %s", rkind, schema.Name))
+		return util.NewErrs(withIssueKind(TypeIssue, fmt.Errorf("bad leaf value type %v, expect Ptr or Int64 for schema %s", rkind, schema.Name)))
 	}
 
 	switch ykind {
@@ -98,7 +121,7 @@ func validateLeaf(inSchema *yang.Entry, value interface{}) util.Errors {
 		return util.NewErrs(validateDecimal(schema, rv))
 	case yang.Yenum, yang.Yidentityref:
 		if rvkind := reflect.TypeOf(rv).Kind(); rvkind != reflect.Int64 {
-			return util.NewErrs(fmt.Errorf("bad leaf value type %v, expect Int64 for schema %s, type %v", rvkind, schema.Name, ykind))
+			return util.NewErrs(withIssueKind(TypeIssue, fmt.Errorf("bad leaf value type %v, expect Int64 for schema %s, type %v", rvkind, schema.Name, ykind)))
 		}
 		return nil
 	case yang.Yunion:
@@ -241,6 +264,13 @@ func validateUnion(schema *yang.Entry, value interface{}) util.Errors {
 		}
 	}
 
+	// Decimal64 values, including those of the simple union type of
+	// Decimal64, are structs that are validated against the decimal64
+	// types of the union.
+	if d, ok := decimal64Value(v); ok {
+		return validateMatchingSchemas(schema, d)
+	}
+
 	if v.Type().Kind() == reflect.Struct {
 		if v.NumField() != 1 {
 			return util.NewErrs(fmt.Errorf("union %s should only have one field, but has %d", schema.Name, v.NumField()))
@@ -264,9 +294,12 @@ func validateMatchingSchemas(schema *yang.Entry, value interface{}) util.Errors
 	}
 	util.DbgPrint("validateMatchingSchemas for value %v (%T) for schema %s with types %v", value, value, schema.Name, kk)
 	if len(ss) == 0 {
-		return util.NewErrs(fmt.Errorf("no types in schema %s match the type of value %v, which is %T", schema.Name, util.ValueStr(value), value))
+		return util.NewErrs(withIssueKind(TypeIssue, fmt.Errorf("no types in schema %s match the type of value %v, which is %T", schema.Name, util.ValueStr(value), value)))
 	}
 	for _, s := range ss {
+		// The statement of the leaf allows the restrictions of the
+		// member types of its union to be found when validating them.
+		s.Node = schema.Node
 		var errs []error
 		if reflect.ValueOf(value).Kind() == reflect.Ptr {
 			errs = validateLeaf(s, value)
@@ -299,12 +332,19 @@ func findMatchingSchemasInUnion(ytype *yang.YangType, value interface{}) []*yang
 			continue
 		}
 
+		if _, ok := value.(ygot.Decimal64); ok {
+			if t.Kind == yang.Ydecimal64 {
+				matches = append(matches, yangTypeToLeafEntry(t))
+			}
+			continue
+		}
+
 		ybt := yangBuiltinTypeToGoType(t.Kind)
 		if reflect.ValueOf(value).Kind() == reflect.Ptr {
 			ybt = ygot.ToPtr(yangBuiltinTypeToGoType(t.Kind))
 		}
 		if ybt == nil {
-			log.Warningf("no matching Go type for type %v in union value %s", t.Kind, util.ValueStr(value))
+			util.Log().Warningf("no matching Go type for type %v in union value %s", t.Kind, util.ValueStr(value))
 			continue
 		}
 		if reflect.TypeOf(ybt).Kind() == reflect.TypeOf(value).Kind() {
@@ -369,8 +409,18 @@ func unmarshalLeaf(inSchema *yang.Entry, parent interface{}, value interface{},
 
 	ykind := schema.Type.Kind
 
+	if enc == JSONEncoding && hasAcceptEnumInts(opts) {
+		if value, err = enumIntToName(schema, parent, fieldName, value); err != nil {
+			return err
+		}
+	}
+
+	if enc == JSONEncoding && hasAcceptInt64Numbers(opts) {
+		value = int64NumberToString(ykind, value)
+	}
+
 	if ykind == yang.Yunion {
-		return unmarshalUnion(schema, parent, fieldName, value, enc)
+		return unmarshalUnion(schema, parent, fieldName, value, enc, hasReportUnionSelection(opts))
 	}
 
 	if ykind == yang.Ybits {
@@ -382,6 +432,16 @@ func unmarshalLeaf(inSchema *yang.Entry, parent interface{}, value interface{},
 	if err != nil {
 		return err
 	}
+	if ykind == yang.Ybinary || ykind == yang.Yempty {
+		// The field may have a user-specified type, which is set
+		// from the unmarshalled value.
+		if cv, ok, err := customLeafValue(parent, fieldName, v); err != nil {
+			return err
+		} else if ok {
+			return util.UpdateField(parent, fieldName, cv)
+		}
+	}
+
 	fieldIsSliceofSlice, err := isFieldSliceofSlice(parent, fieldName)
 	if err != nil {
 		return err
@@ -401,6 +461,100 @@ func unmarshalLeaf(inSchema *yang.Entry, parent interface{}, value interface{},
 	return util.UpdateField(parent, fieldName, v)
 }
 
+// int64NumberToString returns the JSON number value, which is to be
+// unmarshalled into a leaf of kind ykind, as a string if ykind is int64 or
+// uint64, such that it is unmarshalled in the same way as the RFC7951
+// representation of the value. value is returned unchanged otherwise.
+func int64NumberToString(ykind yang.TypeKind, value interface{}) interface{} {
+	if ykind != yang.Yint64 && ykind != yang.Yuint64 {
+		return value
+	}
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return v.String()
+	}
+	return value
+}
+
+// enumIntToName returns the name of the enumerated value represented by the
+// JSON number value, which is to be unmarshalled into the field fieldName of
+// parent, whose schema is an enumeration or a union. value is returned
+// unchanged if it is not a number, or does not represent an enumerated value.
+func enumIntToName(schema *yang.Entry, parent interface{}, fieldName string, value interface{}) (interface{}, error) {
+	f, ok := value.(float64)
+	if !ok {
+		return value, nil
+	}
+
+	var ets []reflect.Type
+	switch schema.Type.Kind {
+	case yang.Yenum:
+		ets = []reflect.Type{structFieldType(parent, fieldName)}
+	case yang.Yunion:
+		var sks []yang.TypeKind
+		var err error
+		if ets, sks, err = enumAndNonEnumTypesForUnion(schema, reflect.TypeOf(parent)); err != nil {
+			return nil, err
+		}
+		for _, sk := range sks {
+			if isIntegerType(sk) || sk == yang.Ydecimal64 {
+				return value, nil
+			}
+		}
+	default:
+		return value, nil
+	}
+
+	for _, et := range ets {
+		name, ok, err := castIntToEnumName(et, f)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return name, nil
+		}
+	}
+	return value, nil
+}
+
+// customLeafValue returns the value of the user-specified type of the field
+// fieldName of parentStruct that represents the binary or empty value v, and
+// true, if the field has a type that implements ygot.BinaryValueSetter or
+// ygot.EmptyValueSetter. It returns false if the field has another type.
+func customLeafValue(parentStruct interface{}, fieldName string, v interface{}) (interface{}, bool, error) {
+	pt := reflect.TypeOf(parentStruct)
+	if !util.IsTypeStructPtr(pt) {
+		return nil, false, fmt.Errorf("parent type %T must be a struct ptr", parentStruct)
+	}
+	ft, ok := pt.Elem().FieldByName(fieldName)
+	if !ok {
+		return nil, false, fmt.Errorf("parent type %T does not have a field name %s", parentStruct, fieldName)
+	}
+
+	nv := reflect.New(ft.Type)
+	switch s := nv.Interface().(type) {
+	case ygot.BinaryValueSetter:
+		b, ok := v.([]byte)
+		if !ok {
+			return nil, false, fmt.Errorf("got %T, want []byte for binary field %s of %T", v, fieldName, parentStruct)
+		}
+		if err := s.SetYANGBinary(b); err != nil {
+			return nil, false, fmt.Errorf("cannot set binary field %s of %T: %v", fieldName, parentStruct, err)
+		}
+	case ygot.EmptyValueSetter:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, false, fmt.Errorf("got %T, want bool for empty field %s of %T", v, fieldName, parentStruct)
+		}
+		s.SetYANGEmpty(b)
+	default:
+		return nil, false, nil
+	}
+	return nv.Elem().Interface(), true, nil
+}
+
 func isFieldSliceofSlice(parentStruct interface{}, fieldName string) (bool, error) {
 	if util.IsValueNil(parentStruct) {
 		return false, fmt.Errorf("parent is nil in UpdateField for field %s", fieldName)
@@ -445,7 +599,70 @@ RouteReflectorClusterId set with the type Bgp_Neighbor_RouteReflector_RouteRefle
 with field String set to "forty-two".
 */
 
-func unmarshalUnion(schema *yang.Entry, parent interface{}, fieldName string, value interface{}, enc Encoding) error {
+// UnionMember identifies a member type of a YANG union.
+type UnionMember struct {
+	// Kind is the YANG type kind of the member. Enumerated members,
+	// including identityrefs, have the kind yang.Yenum.
+	Kind yang.TypeKind
+	// EnumType is the generated Go type of an enumerated member. It is
+	// nil for all other members.
+	EnumType reflect.Type
+}
+
+// String returns a human-readable description of the union member.
+func (m UnionMember) String() string {
+	if m.EnumType != nil {
+		return fmt.Sprintf("%s (%s)", m.Kind, m.EnumType)
+	}
+	return m.Kind.String()
+}
+
+// UnionSelection describes the member type that was selected when a value
+// was unmarshalled into a union leaf or leaf-list. It is supplied to the
+// ReportUnionSelection unmarshal option.
+type UnionSelection struct {
+	// Schema is the schema of the union leaf or leaf-list.
+	Schema *yang.Entry
+	// Value is the value that was unmarshalled, as supplied to the
+	// unmarshal function.
+	Value interface{}
+	// Selected is the union member that the value was unmarshalled into.
+	Selected UnionMember
+	// Alternatives are the other union members that could also have
+	// accepted the value, in the order in which they would have been
+	// tried. Where Alternatives is non-empty, the selection is ambiguous.
+	// Restrictions on the member types, such as patterns and ranges, are
+	// not considered.
+	Alternatives []UnionMember
+}
+
+// unionAlternatives returns the union members other than selected that could
+// also accept value, from the enumerated types ets, which are only tried when
+// valueStr is set, and the non-enumerated YANG kinds sks.
+func unionAlternatives(parent interface{}, fieldName string, value interface{}, enc Encoding, valueStr string, isStr bool, ets []reflect.Type, sks []yang.TypeKind, selected UnionMember) []UnionMember {
+	var alts []UnionMember
+	if isStr {
+		for _, et := range ets {
+			if et == selected.EnumType {
+				continue
+			}
+			if ev, err := castToEnumValue(et, valueStr); err == nil && ev != nil {
+				alts = append(alts, UnionMember{Kind: yang.Yenum, EnumType: et})
+			}
+		}
+	}
+	for _, sk := range sks {
+		if selected.EnumType == nil && sk == selected.Kind {
+			continue
+		}
+		if _, err := unmarshalScalar(parent, yangKindToLeafEntry(sk), fieldName, value, enc); err == nil {
+			alts = append(alts, UnionMember{Kind: sk})
+		}
+	}
+	return alts
+}
+
+func unmarshalUnion(schema *yang.Entry, parent interface{}, fieldName string, value interface{}, enc Encoding, report *ReportUnionSelection) error {
 	util.DbgPrint("unmarshalUnion value %v, type %T, into parent type %T field name %s, schema name %s", util.ValueStrDebug(value), value, parent, fieldName, schema.Name)
 	parentV, parentT := reflect.ValueOf(parent), reflect.TypeOf(parent)
 	if !util.IsTypeStructPtr(parentT) {
@@ -478,8 +695,17 @@ func unmarshalUnion(schema *yang.Entry, parent interface{}, fieldName string, va
 			return fmt.Errorf("could not unmarshal %v into type %s", value, loneType)
 		}
 
-		if !util.IsTypeSlice(destUnionFieldElemT) {
+		if report != nil {
+			m := UnionMember{Kind: loneType}
 			if isEnum {
+				m = UnionMember{Kind: yang.Yenum, EnumType: ets[0]}
+			}
+			report.Report(&UnionSelection{Schema: schema, Value: value, Selected: m})
+		}
+
+		if !util.IsTypeSlice(destUnionFieldElemT) {
+			// Enumerated and Decimal64 fields are not pointers.
+			if isEnum || destUnionFieldElemT == decimal64Type {
 				destUnionFieldV.Set(reflect.ValueOf(goValue))
 			} else {
 				destUnionFieldV.Set(reflect.ValueOf(ygot.ToPtr(goValue)))
@@ -527,6 +753,15 @@ func unmarshalUnion(schema *yang.Entry, parent interface{}, fieldName string, va
 			return err
 		}
 		if ev != nil {
+			if report != nil {
+				m := UnionMember{Kind: yang.Yenum, EnumType: reflect.TypeOf(ev)}
+				report.Report(&UnionSelection{
+					Schema:       schema,
+					Value:        value,
+					Selected:     m,
+					Alternatives: unionAlternatives(parent, fieldName, value, enc, valueStr, ok, ets, sks, m),
+				})
+			}
 			return setUnionFieldWithTypedValue(parentT, destUnionFieldV, destUnionFieldElemT, ev)
 		}
 	}
@@ -535,7 +770,24 @@ func unmarshalUnion(schema *yang.Entry, parent interface{}, fieldName string, va
 		util.DbgPrint("try to unmarshal into type %s", sk)
 		sch := yangKindToLeafEntry(sk)
 		gv, err := unmarshalScalar(parent, sch, fieldName, value, enc)
+		if err == nil && sk == yang.Ydecimal64 {
+			// The union may have been generated using the Decimal64
+			// type rather than float64, in which case the value is
+			// unmarshalled exactly.
+			if dv, ok := unionDecimal64Val(parentT, destUnionFieldElemT, sch, value, enc); ok {
+				gv = dv
+			}
+		}
 		if err == nil {
+			if report != nil {
+				m := UnionMember{Kind: sk}
+				report.Report(&UnionSelection{
+					Schema:       schema,
+					Value:        value,
+					Selected:     m,
+					Alternatives: unionAlternatives(parent, fieldName, value, enc, valueStr, ok, ets, sks, m),
+				})
+			}
 			return setUnionFieldWithTypedValue(parentT, destUnionFieldV, destUnionFieldElemT, gv)
 		}
 		util.DbgPrint("could not unmarshal %v into type %s: %s", value, sk, err)
@@ -544,6 +796,37 @@ func unmarshalUnion(schema *yang.Entry, parent interface{}, fieldName string, va
 	return fmt.Errorf("could not find suitable union type to unmarshal value %v type %T into parent struct type %T field %s", value, value, parent, fieldName)
 }
 
+// unionDecimal64Val returns the decimal64 value, which is encoded according
+// to enc, as a ygot.Decimal64, and true, if the union type destElemT, whose
+// conversion function is defined by the parent type, accepts a Decimal64. It
+// returns false otherwise, including if the value cannot be unmarshalled as a
+// Decimal64.
+func unionDecimal64Val(parentT, destElemT reflect.Type, schema *yang.Entry, value interface{}, enc Encoding) (ygot.Decimal64, bool) {
+	var d ygot.Decimal64
+	var err error
+	switch enc {
+	case JSONEncoding:
+		s, ok := value.(string)
+		if !ok {
+			return d, false
+		}
+		d, err = parseDecimal64(s, schema.Type)
+	default:
+		tv, ok := value.(*gpb.TypedValue)
+		if !ok {
+			return d, false
+		}
+		d, err = gNMIToDecimal64(tv, schema.Type)
+	}
+	if err != nil {
+		return d, false
+	}
+	if _, err := getUnionVal(parentT, destElemT, d); err != nil {
+		return d, false
+	}
+	return d, true
+}
+
 // setUnionFieldWithTypedValue sets the field destV with value v after converting it
 // to destElemT using the union conversion function of the given parent type.
 func setUnionFieldWithTypedValue(parentT reflect.Type, destV reflect.Value, destElemT reflect.Type, v interface{}) error {
@@ -594,7 +877,7 @@ func getUnionVal(parentT reflect.Type, destElemT reflect.Type, v interface{}) (r
 func getUnionKindsNotEnums(schema *yang.Entry) ([]yang.TypeKind, error) {
 	var uks []yang.TypeKind
 	m := make(map[yang.TypeKind]interface{})
-	uts, err := getUnionTypesNotEnums(schema, schema.Type)
+	uts, err := getUnionTypesNotEnums(nil, schema, schema.Type)
 	if err != nil {
 		return nil, err
 	}
@@ -608,22 +891,23 @@ func getUnionKindsNotEnums(schema *yang.Entry) ([]yang.TypeKind, error) {
 }
 
 // getUnionTypesNotEnums returns all the non-enum YANG types under the given
-// schema node, dereferencing any refs.
-func getUnionTypesNotEnums(schema *yang.Entry, yt *yang.YangType) ([]*yang.YangType, error) {
+// schema node, dereferencing any refs. followed are the schemas of the
+// leafrefs that have been dereferenced in order to reach schema.
+func getUnionTypesNotEnums(followed []*yang.Entry, schema *yang.Entry, yt *yang.YangType) ([]*yang.YangType, error) {
 	var uts []*yang.YangType
 	switch yt.Kind {
 	case yang.Yenum, yang.Yidentityref:
 		// Enum types handled separately.
 		return nil, nil
 	case yang.Yleafref:
-		ns, err := util.FindLeafRefSchema(schema, yt.Path)
+		ns, err := util.FollowLeafRef(followed, schema, yt.Path)
 		if err != nil {
 			return nil, err
 		}
-		return getUnionTypesNotEnums(ns, ns.Type)
+		return getUnionTypesNotEnums(append(followed[:len(followed):len(followed)], schema), ns, ns.Type)
 	case yang.Yunion:
 		for _, t := range yt.Type {
-			nt, err := getUnionTypesNotEnums(schema, t)
+			nt, err := getUnionTypesNotEnums(followed, schema, t)
 			if err != nil {
 				return nil, err
 			}
@@ -738,6 +1022,13 @@ func sanitizeJSON(parent interface{}, schema *yang.Entry, fieldName string, valu
 		return value.(string), nil
 
 	case yang.Ydecimal64:
+		if isDecimal64Field(parent, fieldName) {
+			d, err := parseDecimal64(value.(string), schema.Type)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing %v for schema %s: %v", value, schema.Name, err)
+			}
+			return d, nil
+		}
 		floatV, err := strconv.ParseFloat(value.(string), 64)
 		if err != nil {
 			return nil, fmt.Errorf("error parsing %v for schema %s: %v", value, schema.Name, err)
@@ -830,6 +1121,13 @@ func sanitizeGNMI(parent interface{}, schema *yang.Entry, fieldName string, tv *
 		}
 		return bytes, nil
 	case yang.Ydecimal64:
+		if isDecimal64Field(parent, fieldName) {
+			d, err := gNMIToDecimal64(tv, schema.Type)
+			if err != nil {
+				return nil, fmt.Errorf("cannot unmarshal %v into decimal64 schema %s: %v", tv.GetValue(), schema.Name, err)
+			}
+			return d, nil
+		}
 		switch v := tv.GetValue().(type) {
 		case *gpb.TypedValue_DecimalVal:
 			if v.DecimalVal == nil {
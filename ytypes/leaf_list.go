@@ -43,6 +43,8 @@ func validateLeafList(schema *yang.Entry, value interface{}) util.Errors {
 
 	switch reflect.TypeOf(value).Kind() {
 	case reflect.Slice:
+		// Check the size constraints of the leaf-list.
+		errors = util.AppendErrs(errors, validateListAttr(schema, value))
 		v := reflect.ValueOf(value)
 		for i := 0; i < v.Len(); i++ {
 			cv := v.Index(i).Interface()
@@ -86,10 +86,17 @@ func TestValidateLeafList(t *testing.T) {
 		Type:     &yang.YangType{Kind: yang.Ystring},
 		Name:     "leaf-list-schema",
 	}
+	boundedLeafListSchema := &yang.Entry{
+		Kind:     yang.LeafEntry,
+		ListAttr: &yang.ListAttr{MinElements: 1, MaxElements: 2},
+		Type:     &yang.YangType{Kind: yang.Ystring},
+		Name:     "bounded-leaf-list",
+	}
 	tests := []struct {
 		desc    string
 		schema  *yang.Entry
 		val     interface{}
+		inOpts  []ygot.ValidationOption
 		wantErr string
 	}{
 		{
@@ -114,11 +121,34 @@ func TestValidateLeafList(t *testing.T) {
 			val:     []int32{1},
 			wantErr: `non string type int32 with value 1 for schema leaf-list-schema`,
 		},
+		{
+			desc:   "success within size bounds",
+			schema: boundedLeafListSchema,
+			val:    []string{"test1", "test2"},
+		},
+		{
+			desc:   "unset leaf-list",
+			schema: boundedLeafListSchema,
+			val:    []string(nil),
+		},
+		{
+			desc:    "too few elements",
+			schema:  boundedLeafListSchema,
+			val:     []string(nil),
+			inOpts:  []ygot.ValidationOption{&EnforceUnsetListMinElements{}},
+			wantErr: `list /bounded-leaf-list contains fewer than min required elements: 0 < 1`,
+		},
+		{
+			desc:    "too many elements",
+			schema:  boundedLeafListSchema,
+			val:     []string{"test1", "test2", "test3"},
+			wantErr: `list /bounded-leaf-list contains more than max allowed elements: 3 > 2`,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			errs := Validate(tt.schema, tt.val)
+			errs := Validate(tt.schema, tt.val, tt.inOpts...)
 			if got, want := errs.String(), tt.wantErr; got != want {
 				t.Errorf("%s: Validate(%v) got error: %v, want error: %v", tt.desc, tt.val, got, want)
 			}
@@ -17,6 +17,7 @@ package ytypes
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -25,6 +26,7 @@ import (
 	"github.com/openconfig/gnmi/errdiff"
 	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/openconfig/ygot/testutil"
+	"github.com/openconfig/ygot/util"
 	"github.com/openconfig/ygot/ygot"
 
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
@@ -1174,11 +1176,71 @@ func TestUnmarshalLeafJSONEncoding(t *testing.T) {
 			json: `{"uint64-leaf" : "42"}`,
 			want: LeafContainerStruct{Uint64Leaf: ygot.Uint64(42)},
 		},
+		{
+			desc: "int64 number success with AcceptInt64Numbers",
+			json: `{"int64-leaf" : -42}`,
+			opts: []UnmarshalOpt{&AcceptInt64Numbers{}},
+			want: LeafContainerStruct{Int64Leaf: ygot.Int64(-42)},
+		},
+		{
+			desc: "uint64 number success with AcceptInt64Numbers",
+			json: `{"uint64-leaf" : 9007199254740992}`,
+			opts: []UnmarshalOpt{&AcceptInt64Numbers{}},
+			want: LeafContainerStruct{Uint64Leaf: ygot.Uint64(9007199254740992)},
+		},
+		{
+			desc: "int64 string success with AcceptInt64Numbers",
+			json: `{"int64-leaf" : "-42"}`,
+			opts: []UnmarshalOpt{&AcceptInt64Numbers{}},
+			want: LeafContainerStruct{Int64Leaf: ygot.Int64(-42)},
+		},
+		{
+			desc:    "int64 fractional number with AcceptInt64Numbers",
+			json:    `{"int64-leaf" : 4.2}`,
+			opts:    []UnmarshalOpt{&AcceptInt64Numbers{}},
+			wantErr: `error parsing 4.2 for schema int64-leaf: strconv.ParseInt: parsing "4.2": invalid syntax`,
+		},
+		{
+			desc:    "int32 string with AcceptInt64Numbers",
+			json:    `{"int32-leaf" : "42"}`,
+			opts:    []UnmarshalOpt{&AcceptInt64Numbers{}},
+			wantErr: `got string type for field int32-leaf, expect float64`,
+		},
 		{
 			desc: "enum success",
 			json: `{"enum-leaf" : "E_VALUE_FORTY_TWO"}`,
 			want: LeafContainerStruct{EnumLeaf: 42},
 		},
+		{
+			desc: "enum integer success with AcceptEnumInts",
+			json: `{"enum-leaf" : 41}`,
+			opts: []UnmarshalOpt{&AcceptEnumInts{}},
+			want: LeafContainerStruct{EnumLeaf: 42},
+		},
+		{
+			desc: "enum name success with AcceptEnumInts",
+			json: `{"enum-leaf" : "E_VALUE_FORTY_ONE"}`,
+			opts: []UnmarshalOpt{&AcceptEnumInts{}},
+			want: LeafContainerStruct{EnumLeaf: 41},
+		},
+		{
+			desc: "single enum union integer success with AcceptEnumInts",
+			json: `{"union-enum-leaf" : 40}`,
+			opts: []UnmarshalOpt{&AcceptEnumInts{}},
+			want: LeafContainerStruct{UnionEnumLeaf: 41},
+		},
+		{
+			desc: "single enum union leaf-list integers success with AcceptEnumInts",
+			json: `{"union-enum-leaflist" : [40, 41]}`,
+			opts: []UnmarshalOpt{&AcceptEnumInts{}},
+			want: LeafContainerStruct{UnionEnumLeaflist: []EnumType{41, 42}},
+		},
+		{
+			desc: "union with numeric member ignores AcceptEnumInts",
+			json: `{"union-leaf-simple" : 41}`,
+			opts: []UnmarshalOpt{&AcceptEnumInts{}},
+			want: LeafContainerStruct{UnionLeafSimple: testutil.UnionUint32(41)},
+		},
 		{
 			desc: "binary success",
 			json: `{"binary-leaf" : "` + base64testStringEncoded + `"}`,
@@ -1344,6 +1406,17 @@ func TestUnmarshalLeafJSONEncoding(t *testing.T) {
 			json:    `{"uint64-leaf" : "-42"}`,
 			wantErr: `error parsing -42 for schema uint64-leaf: strconv.ParseUint: parsing "-42": invalid syntax`,
 		},
+		{
+			desc:    "enum integer without AcceptEnumInts",
+			json:    `{"enum-leaf" : 41}`,
+			wantErr: `got float64 type for field enum-leaf, expect string`,
+		},
+		{
+			desc:    "enum bad integer value with AcceptEnumInts",
+			json:    `{"enum-leaf" : 7}`,
+			opts:    []UnmarshalOpt{&AcceptEnumInts{}},
+			wantErr: `got float64 type for field enum-leaf, expect string`,
+		},
 		{
 			desc:    "enum bad value",
 			json:    `{"enum-leaf" : "E_BAD_VALUE"}`,
@@ -1686,12 +1759,12 @@ func TestUnmarshalLeafJSONEncoding(t *testing.T) {
 	}
 	// Additional tests through private API.
 	// bad parent type
-	err = unmarshalUnion(containerSchema, LeafContainerStruct{}, "int8-leaf", 42, JSONEncoding)
+	err = unmarshalUnion(containerSchema, LeafContainerStruct{}, "int8-leaf", 42, JSONEncoding, nil)
 	wantErr = `ytypes.LeafContainerStruct is not a struct ptr in unmarshalUnion`
 	if got, want := errToString(err), wantErr; got != want {
 		t.Errorf("bad parent type: Unmarshal got error: %v, want error: %v", got, want)
 	}
-	err = unmarshalUnion(containerSchema, &LeafContainerStruct{}, "i-dont-exist", 42, JSONEncoding)
+	err = unmarshalUnion(containerSchema, &LeafContainerStruct{}, "i-dont-exist", 42, JSONEncoding, nil)
 	wantErr = `i-dont-exist is not a valid field name in *ytypes.LeafContainerStruct`
 	if got, want := errToString(err), wantErr; got != want {
 		t.Errorf("bad parent type: Unmarshal got error: %v, want error: %v", got, want)
@@ -1716,6 +1789,106 @@ func TestUnmarshalLeafJSONEncoding(t *testing.T) {
 	}
 }
 
+func TestUnmarshalUnionSelection(t *testing.T) {
+	containerSchema := &yang.Entry{
+		Name: "container-schema",
+		Kind: yang.DirectoryEntry,
+		Dir:  map[string]*yang.Entry{},
+	}
+	for _, s := range []*yang.Entry{{
+		Name: "union-leaf-simple",
+		Kind: yang.LeafEntry,
+		Type: &yang.YangType{
+			Kind: yang.Yunion,
+			Type: []*yang.YangType{
+				{Kind: yang.Ystring},
+				{Kind: yang.Ybinary},
+				{Kind: yang.Yuint32},
+				{Kind: yang.Yenum},
+			},
+		},
+	}, {
+		Name: "union-enum-leaf",
+		Kind: yang.LeafEntry,
+		Type: &yang.YangType{
+			Kind: yang.Yunion,
+			Type: []*yang.YangType{
+				{Kind: yang.Yenum},
+			},
+		},
+	}} {
+		s.Parent = containerSchema
+		containerSchema.Dir[s.Name] = s
+	}
+
+	tests := []struct {
+		desc     string
+		inSchema string
+		inValue  interface{}
+		want     []*UnionSelection
+	}{{
+		desc:     "string with binary alternative",
+		inSchema: "union-leaf-simple",
+		inValue:  "abcd",
+		want: []*UnionSelection{{
+			Schema:       containerSchema.Dir["union-leaf-simple"],
+			Value:        "abcd",
+			Selected:     UnionMember{Kind: yang.Ystring},
+			Alternatives: []UnionMember{{Kind: yang.Ybinary}},
+		}},
+	}, {
+		desc:     "enum with string alternative",
+		inSchema: "union-leaf-simple",
+		inValue:  "E_VALUE_FORTY_TWO",
+		want: []*UnionSelection{{
+			Schema:       containerSchema.Dir["union-leaf-simple"],
+			Value:        "E_VALUE_FORTY_TWO",
+			Selected:     UnionMember{Kind: yang.Yenum, EnumType: reflect.TypeOf(EnumType(0))},
+			Alternatives: []UnionMember{{Kind: yang.Ystring}},
+		}},
+	}, {
+		desc:     "unambiguous uint32",
+		inSchema: "union-leaf-simple",
+		inValue:  float64(42),
+		want: []*UnionSelection{{
+			Schema:   containerSchema.Dir["union-leaf-simple"],
+			Value:    float64(42),
+			Selected: UnionMember{Kind: yang.Yuint32},
+		}},
+	}, {
+		desc:     "single type union",
+		inSchema: "union-enum-leaf",
+		inValue:  "E_VALUE_FORTY_TWO",
+		want: []*UnionSelection{{
+			Schema:   containerSchema.Dir["union-enum-leaf"],
+			Value:    "E_VALUE_FORTY_TWO",
+			Selected: UnionMember{Kind: yang.Yenum, EnumType: reflect.TypeOf(EnumType(0))},
+		}},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var got []*UnionSelection
+			opt := &ReportUnionSelection{Report: func(s *UnionSelection) { got = append(got, s) }}
+			if err := Unmarshal(containerSchema.Dir[tt.inSchema], &LeafContainerStruct{}, tt.inValue, opt); err != nil {
+				t.Fatalf("Unmarshal: got unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(func(a, b reflect.Type) bool { return a == b }), cmp.Comparer(func(a, b *yang.Entry) bool { return a == b })); diff != "" {
+				t.Errorf("Unmarshal: did not get expected union selections (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUnionMemberString(t *testing.T) {
+	if got, want := (UnionMember{Kind: yang.Yuint32}).String(), "uint32"; got != want {
+		t.Errorf("String: got %q, want %q", got, want)
+	}
+	if got, want := (UnionMember{Kind: yang.Yenum, EnumType: reflect.TypeOf(EnumType(0))}).String(), "enumeration (ytypes.EnumType)"; got != want {
+		t.Errorf("String: got %q, want %q", got, want)
+	}
+}
+
 func TestUnmarshalLeafRef(t *testing.T) {
 	containerSchema := &yang.Entry{
 		Name: "container",
@@ -1742,10 +1915,30 @@ func TestUnmarshalLeafRef(t *testing.T) {
 				Path: "../config/leaf-type",
 			},
 		},
+		"cycle-a": {
+			Parent: containerSchema,
+			Kind:   yang.LeafEntry,
+			Name:   "cycle-a",
+			Type: &yang.YangType{
+				Kind: yang.Yleafref,
+				Path: "../cycle-b",
+			},
+		},
+		"cycle-b": {
+			Parent: containerSchema,
+			Kind:   yang.LeafEntry,
+			Name:   "cycle-b",
+			Type: &yang.YangType{
+				Kind: yang.Yleafref,
+				Path: "../cycle-a",
+			},
+		},
 	}
 
 	type ContainerStruct struct {
-		Leaf1 *int32 `path:"leaf1"`
+		Leaf1  *int32 `path:"leaf1"`
+		CycleA *int32 `path:"cycle-a"`
+		CycleB *int32 `path:"cycle-b"`
 	}
 
 	tests := []struct {
@@ -1764,6 +1957,11 @@ func TestUnmarshalLeafRef(t *testing.T) {
 			json:    `{ "bad-field" : 42}`,
 			wantErr: `parent container container (type *ytypes.ContainerStruct): JSON contains unexpected field bad-field`,
 		},
+		{
+			desc:    "leafref cycle",
+			json:    `{ "cycle-a" : 42}`,
+			wantErr: `leafref cycle: /container/cycle-a -> /container/cycle-b -> /container/cycle-a`,
+		},
 	}
 
 	var jsonTree interface{}
@@ -1790,6 +1988,48 @@ func TestUnmarshalLeafRef(t *testing.T) {
 	}
 }
 
+func TestGetUnionKindsNotEnumsLeafRefCycle(t *testing.T) {
+	containerSchema := &yang.Entry{
+		Name: "container",
+		Kind: yang.DirectoryEntry,
+	}
+	containerSchema.Dir = map[string]*yang.Entry{
+		"union": {
+			Parent: containerSchema,
+			Kind:   yang.LeafEntry,
+			Name:   "union",
+			Type: &yang.YangType{
+				Kind: yang.Yunion,
+				Type: []*yang.YangType{
+					{Kind: yang.Ystring},
+					{Kind: yang.Yleafref, Path: "../ref"},
+				},
+			},
+		},
+		"ref": {
+			Parent: containerSchema,
+			Kind:   yang.LeafEntry,
+			Name:   "ref",
+			Type: &yang.YangType{
+				Kind: yang.Yunion,
+				Type: []*yang.YangType{
+					{Kind: yang.Yint32},
+					{Kind: yang.Yleafref, Path: "../union"},
+				},
+			},
+		},
+	}
+
+	_, err := getUnionKindsNotEnums(containerSchema.Dir["union"])
+	if diff := errdiff.Substring(err, "leafref cycle: /container/union -> /container/ref -> /container/union"); diff != "" {
+		t.Fatalf("getUnionKindsNotEnums: did not get expected error, %s", diff)
+	}
+	var cycleErr *util.LeafRefCycleError
+	if !errors.As(err, &cycleErr) {
+		t.Errorf("getUnionKindsNotEnums: got error %v, want *util.LeafRefCycleError", err)
+	}
+}
+
 func TestUnmarshalLeafGNMIEncoding(t *testing.T) {
 	containerSchema := &yang.Entry{
 		Name: "container-schema",
@@ -2292,3 +2532,310 @@ func TestUnmarshalLeafGNMIEncoding(t *testing.T) {
 		}
 	}
 }
+
+// lazyBinary is a user-specified type for binary leaves, which stores the
+// base64 encoding of the value.
+type lazyBinary string
+
+func (b lazyBinary) YANGBinary() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(string(b))
+}
+
+func (b *lazyBinary) SetYANGBinary(v []byte) error {
+	*b = lazyBinary(base64.StdEncoding.EncodeToString(v))
+	return nil
+}
+
+// presence is a user-specified type for empty leaves.
+type presence bool
+
+func (p presence) YANGEmpty() bool { return bool(p) }
+
+func (p *presence) SetYANGEmpty(v bool) { *p = presence(v) }
+
+type customLeafStruct struct {
+	BinaryLeaf lazyBinary `path:"binary-leaf"`
+	EmptyLeaf  presence   `path:"empty-leaf"`
+}
+
+func (*customLeafStruct) IsYANGGoStruct() {}
+
+func TestCustomBinaryEmptyLeaves(t *testing.T) {
+	lengthSchema := yrangeToLeafSchema("binary-leaf", yang.YRange{Min: yang.FromInt(2), Max: yang.FromInt(4)})
+
+	validateTests := []struct {
+		desc             string
+		inSchema         *yang.Entry
+		inVal            interface{}
+		wantErrSubstring string
+	}{{
+		desc:     "binary success",
+		inSchema: lengthSchema,
+		inVal:    lazyBinary(base64.StdEncoding.EncodeToString([]byte("aaa"))),
+	}, {
+		desc:     "unset binary",
+		inSchema: lengthSchema,
+		inVal:    lazyBinary(""),
+	}, {
+		desc:             "binary too long",
+		inSchema:         lengthSchema,
+		inVal:            lazyBinary(base64.StdEncoding.EncodeToString([]byte("aaaaa"))),
+		wantErrSubstring: "length 5 is outside range 2..4",
+	}, {
+		desc:             "invalid binary value",
+		inSchema:         lengthSchema,
+		inVal:            lazyBinary("!"),
+		wantErrSubstring: "invalid binary value",
+	}, {
+		desc:             "binary value for string schema",
+		inSchema:         typeToLeafSchema("string-leaf", yang.Ystring),
+		inVal:            lazyBinary("YQ=="),
+		wantErrSubstring: "got binary value type",
+	}, {
+		desc:     "empty success",
+		inSchema: typeToLeafSchema("empty-leaf", yang.Yempty),
+		inVal:    presence(true),
+	}, {
+		desc:             "empty value for bool schema",
+		inSchema:         typeToLeafSchema("bool-leaf", yang.Ybool),
+		inVal:            presence(true),
+		wantErrSubstring: "got empty value type",
+	}}
+
+	for _, tt := range validateTests {
+		t.Run("validate "+tt.desc, func(t *testing.T) {
+			var err error
+			if errs := validateLeaf(tt.inSchema, tt.inVal); errs != nil {
+				err = errs
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Errorf("validateLeaf: did not get expected error, %s", diff)
+			}
+		})
+	}
+
+	unmarshalTests := []struct {
+		desc     string
+		inSchema *yang.Entry
+		inVal    interface{}
+		inEnc    Encoding
+		want     *customLeafStruct
+	}{{
+		desc:     "binary from JSON",
+		inSchema: typeToLeafSchema("binary-leaf", yang.Ybinary),
+		inVal:    base64testStringEncoded,
+		inEnc:    JSONEncoding,
+		want:     &customLeafStruct{BinaryLeaf: lazyBinary(base64testStringEncoded)},
+	}, {
+		desc:     "binary from gNMI",
+		inSchema: typeToLeafSchema("binary-leaf", yang.Ybinary),
+		inVal:    &gpb.TypedValue{Value: &gpb.TypedValue_BytesVal{BytesVal: []byte(base64testString)}},
+		inEnc:    GNMIEncoding,
+		want:     &customLeafStruct{BinaryLeaf: lazyBinary(base64testStringEncoded)},
+	}, {
+		desc:     "empty from JSON",
+		inSchema: typeToLeafSchema("empty-leaf", yang.Yempty),
+		inVal:    []interface{}{nil},
+		inEnc:    JSONEncoding,
+		want:     &customLeafStruct{EmptyLeaf: true},
+	}}
+
+	for _, tt := range unmarshalTests {
+		t.Run("unmarshal "+tt.desc, func(t *testing.T) {
+			got := &customLeafStruct{}
+			if err := unmarshalLeaf(tt.inSchema, got, tt.inVal, tt.inEnc); err != nil {
+				t.Fatalf("unmarshalLeaf: got unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unmarshalLeaf: did not get expected struct, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// UnionDecimal64 is the simple union type of ygot.Decimal64, as generated
+// when decimal64 leaves use the Decimal64 type.
+type UnionDecimal64 ygot.Decimal64
+
+func (UnionDecimal64) IsYANGLeafValue() {}
+func (UnionDecimal64) IsTestUnion()     {}
+
+type decimal64LeafStruct struct {
+	Leaf     ygot.Decimal64     `path:"decimal-leaf"`
+	LeafList []ygot.Decimal64   `path:"decimal-leaflist"`
+	Union    testutil.TestUnion `path:"union-leaf"`
+}
+
+func (*decimal64LeafStruct) IsYANGGoStruct() {}
+
+func (*decimal64LeafStruct) ΛEnumTypeMap() map[string][]reflect.Type { return nil }
+
+func (*decimal64LeafStruct) To_TestUnion(i interface{}) (testutil.TestUnion, error) {
+	if v, ok := i.(testutil.TestUnion); ok {
+		return v, nil
+	}
+	switch v := i.(type) {
+	case ygot.Decimal64:
+		return UnionDecimal64(v), nil
+	case string:
+		return testutil.UnionString(v), nil
+	}
+	return nil, fmt.Errorf("cannot convert %v to TestUnion, unknown union type, got: %T, want any of [Decimal64, string]", i, i)
+}
+
+func TestDecimal64Leaves(t *testing.T) {
+	decimalType := &yang.YangType{
+		Kind:           yang.Ydecimal64,
+		FractionDigits: 2,
+		Range:          yang.YangRange{{Min: yang.FromFloat(0), Max: yang.FromFloat(100.5)}},
+	}
+	leafSchema := &yang.Entry{Name: "decimal-leaf", Kind: yang.LeafEntry, Type: decimalType}
+	leafListSchema := &yang.Entry{Name: "decimal-leaflist", Kind: yang.LeafEntry, ListAttr: yang.NewDefaultListAttr(), Type: decimalType}
+	unionSchema := &yang.Entry{
+		Name: "union-leaf",
+		Kind: yang.LeafEntry,
+		Type: &yang.YangType{
+			Kind: yang.Yunion,
+			Type: []*yang.YangType{decimalType, {Kind: yang.Ystring}},
+		},
+	}
+
+	validateTests := []struct {
+		desc             string
+		inSchema         *yang.Entry
+		inVal            interface{}
+		wantErrSubstring string
+	}{{
+		desc:     "success",
+		inSchema: leafSchema,
+		inVal:    ygot.Decimal64{Digits: 1005, Precision: 2},
+	}, {
+		desc:     "unset",
+		inSchema: leafSchema,
+		inVal:    ygot.Decimal64{},
+	}, {
+		desc:             "outside range",
+		inSchema:         leafSchema,
+		inVal:            ygot.Decimal64{Digits: 10051, Precision: 2},
+		wantErrSubstring: "outside specified ranges",
+	}, {
+		desc:             "decimal64 value for string schema",
+		inSchema:         typeToLeafSchema("string-leaf", yang.Ystring),
+		inVal:            ygot.Decimal64{Digits: 1, Precision: 1},
+		wantErrSubstring: "bad leaf value type",
+	}, {
+		desc:     "union success",
+		inSchema: unionSchema,
+		inVal:    UnionDecimal64{Digits: 1005, Precision: 2},
+	}, {
+		desc:             "union outside range",
+		inSchema:         unionSchema,
+		inVal:            UnionDecimal64{Digits: 10051, Precision: 2},
+		wantErrSubstring: "outside specified ranges",
+	}}
+
+	for _, tt := range validateTests {
+		t.Run("validate "+tt.desc, func(t *testing.T) {
+			var err error
+			if errs := validateLeaf(tt.inSchema, tt.inVal); errs != nil {
+				err = errs
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Errorf("validateLeaf: did not get expected error, %s", diff)
+			}
+		})
+	}
+
+	unmarshalTests := []struct {
+		desc             string
+		inSchema         *yang.Entry
+		inVal            interface{}
+		inEnc            Encoding
+		want             *decimal64LeafStruct
+		wantErrSubstring string
+	}{{
+		desc:     "leaf from JSON",
+		inSchema: leafSchema,
+		inVal:    "10.05",
+		inEnc:    JSONEncoding,
+		want:     &decimal64LeafStruct{Leaf: ygot.Decimal64{Digits: 1005, Precision: 2}},
+	}, {
+		desc:     "leaf from JSON with fewer fraction digits",
+		inSchema: leafSchema,
+		inVal:    "10",
+		inEnc:    JSONEncoding,
+		want:     &decimal64LeafStruct{Leaf: ygot.Decimal64{Digits: 1000, Precision: 2}},
+	}, {
+		desc:             "leaf from JSON with too many fraction digits",
+		inSchema:         leafSchema,
+		inVal:            "10.055",
+		inEnc:            JSONEncoding,
+		wantErrSubstring: "more than 2 fraction digits",
+	}, {
+		desc:     "leaf from gNMI decimal",
+		inSchema: leafSchema,
+		inVal:    &gpb.TypedValue{Value: &gpb.TypedValue_DecimalVal{DecimalVal: &gpb.Decimal64{Digits: 10050, Precision: 3}}},
+		inEnc:    GNMIEncoding,
+		want:     &decimal64LeafStruct{Leaf: ygot.Decimal64{Digits: 1005, Precision: 2}},
+	}, {
+		desc:     "leaf from gNMI double",
+		inSchema: leafSchema,
+		inVal:    &gpb.TypedValue{Value: &gpb.TypedValue_DoubleVal{DoubleVal: 10.05}},
+		inEnc:    GNMIEncoding,
+		want:     &decimal64LeafStruct{Leaf: ygot.Decimal64{Digits: 1005, Precision: 2}},
+	}, {
+		desc:     "leaf-list from JSON",
+		inSchema: leafListSchema,
+		inVal:    []interface{}{"0.1", "-2.25"},
+		inEnc:    JSONEncoding,
+		want:     &decimal64LeafStruct{LeafList: []ygot.Decimal64{{Digits: 10, Precision: 2}, {Digits: -225, Precision: 2}}},
+	}, {
+		desc:     "leaf-list from gNMI",
+		inSchema: leafListSchema,
+		inVal: &gpb.TypedValue{Value: &gpb.TypedValue_LeaflistVal{LeaflistVal: &gpb.ScalarArray{
+			Element: []*gpb.TypedValue{
+				{Value: &gpb.TypedValue_DecimalVal{DecimalVal: &gpb.Decimal64{Digits: 1, Precision: 1}}},
+			},
+		}}},
+		inEnc: GNMIEncoding,
+		want:  &decimal64LeafStruct{LeafList: []ygot.Decimal64{{Digits: 10, Precision: 2}}},
+	}, {
+		desc:     "union from JSON",
+		inSchema: unionSchema,
+		inVal:    "10.05",
+		inEnc:    JSONEncoding,
+		want:     &decimal64LeafStruct{Union: UnionDecimal64{Digits: 1005, Precision: 2}},
+	}, {
+		desc:     "union string from JSON",
+		inSchema: unionSchema,
+		inVal:    "none",
+		inEnc:    JSONEncoding,
+		want:     &decimal64LeafStruct{Union: testutil.UnionString("none")},
+	}, {
+		desc:     "union from gNMI",
+		inSchema: unionSchema,
+		inVal:    &gpb.TypedValue{Value: &gpb.TypedValue_DecimalVal{DecimalVal: &gpb.Decimal64{Digits: 1005, Precision: 2}}},
+		inEnc:    GNMIEncoding,
+		want:     &decimal64LeafStruct{Union: UnionDecimal64{Digits: 1005, Precision: 2}},
+	}}
+
+	for _, tt := range unmarshalTests {
+		t.Run("unmarshal "+tt.desc, func(t *testing.T) {
+			unmarshal := unmarshalLeaf
+			if tt.inSchema.IsLeafList() {
+				unmarshal = unmarshalLeafList
+			}
+			got := &decimal64LeafStruct{}
+			err := unmarshal(tt.inSchema, got, tt.inVal, tt.inEnc)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("unmarshal: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unmarshal: did not get expected struct, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -16,14 +16,15 @@ package ytypes
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 
-	log "github.com/golang/glog"
 	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/openconfig/ygot/util"
 	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/protobuf/proto"
 
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
 )
@@ -41,6 +42,21 @@ import (
 // entire data tree. The supplied LeafrefOptions specify particular behaviours
 // of the leafref validation such as ignoring missing pointed to elements.
 func ValidateLeafRefData(schema *yang.Entry, value interface{}, opt *LeafrefOptions) util.Errors {
+	return issueErrors(validateLeafRefData(schema, value, opt, false))
+}
+
+// nonLocalRefError is returned when a relative leafref path refers to a node
+// above the root of the data tree being validated.
+type nonLocalRefError struct {
+	error
+}
+
+// validateLeafRefData implements ValidateLeafRefData. If skipNonLocal is set,
+// value is treated as the root of a detached subtree, and leafrefs that have
+// absolute paths, or relative paths that refer to nodes above value, are not
+// validated. Leafrefs that are not satisfied, but which are ignored due to the
+// supplied LeafrefOptions, are returned as issues with WarningSeverity.
+func validateLeafRefData(schema *yang.Entry, value interface{}, opt *LeafrefOptions, skipNonLocal bool) util.Errors {
 	// If the IgnoreMissingData flag is set, then we do not need to iterate through nodes,
 	// so immediately return no error.
 	if opt != nil && opt.IgnoreMissingData {
@@ -53,69 +69,103 @@ func ValidateLeafRefData(schema *yang.Entry, value interface{}, opt *LeafrefOpti
 		if util.IsValueNil(ni) || util.IsNilOrInvalidValue(ni.FieldValue) {
 			return nil
 		}
-		schema := ni.Schema
-		if schema == nil {
+		if ni.Schema == nil {
 			return util.NewErrs(fmt.Errorf("schema is nil for value %s, type %T", util.ValueStr(value), value))
 		}
-		if !util.IsLeafRef(schema) || schema.IsLeafList() {
-			return nil
-		}
-
-		pathQueryNode, ok := in.(*util.PathQueryNodeMemo)
-		if !ok {
-			return util.NewErrs(fmt.Errorf("expected input to validateLeafRefDataIterFunc to be type *util.PathQueryNodeMemo, but got %T", in))
-		}
-		gNMIPath, err := leafRefToGNMIPath(ni, schema.Type.Path, pathQueryNode)
-		if err != nil {
-			return util.NewErrs(err)
+		_, match, err := checkLeafRef(ni, in, skipNonLocal)
+		var errs util.Errors
+		switch {
+		case err != nil && !match:
+			errs = leafrefErrOrLog(util.NewErrs(err), opt)
+		case err != nil:
+			errs = util.NewErrs(err)
+		case !match:
+			e := fmt.Errorf("field name %s value %s schema path %s has leafref path %s not equal to any target nodes",
+				ni.StructField.Name, util.ValueStr(ni.FieldValue.Interface()), ni.Schema.Path(), util.StripModulePrefixesStr(ni.Schema.Type.Path))
+			util.DbgPrint("ERR: %s", e)
+			errs = leafrefErrOrLog(util.NewErrs(e), opt)
 		}
-		matchNodes, err := dataNodesAtPath(ni, gNMIPath, pathQueryNode)
-		if err != nil {
-			return util.NewErrs(err)
+		if errs == nil {
+			return nil
 		}
+		return withIssuePath(withIssueKindErrs(LeafrefIssue, errs), nodeInfoPath(ni))
+	}
 
-		pathStr := util.StripModulePrefixesStr(schema.Type.Path)
-		util.DbgPrint("Verifying leafref at %s, matching nodes are: %v", pathStr, util.ValueStrDebug(matchNodes))
+	pathQueryRootNode := &util.PathQueryNodeMemo{Memo: util.PathQueryMemo{}}
+	return util.ForEachField(schema, value, pathQueryRootNode, nil, validateLeafRefDataIterFunc)
+}
 
-		match, err := matchesNodes(ni, matchNodes)
-		if err != nil {
-			return leafrefErrOrLog(util.NewErrs(err), opt)
+// checkLeafRef checks whether the value of the node ni, which is visited by
+// ForEachField with the supplied input memo, is equal to any of the nodes to
+// which its leafref path refers. It returns the path of the referenced nodes,
+// with the keys of any lists resolved, and whether the value matched. Nodes
+// that are not leafrefs, or are skipped when skipNonLocal is set, are reported
+// as matching with a nil path. An error that is returned with match set to
+// false indicates that the referenced nodes do not exist, whereas an error
+// returned with match set to true indicates that the leafref could not be
+// checked.
+func checkLeafRef(ni *util.NodeInfo, in interface{}, skipNonLocal bool) (*gpb.Path, bool, error) {
+	schema := ni.Schema
+	if !util.IsLeafRef(schema) || schema.IsLeafList() {
+		return nil, true, nil
+	}
+	if skipNonLocal && strings.HasPrefix(schema.Type.Path, "/") {
+		return nil, true, nil
+	}
+
+	pathQueryNode, ok := in.(*util.PathQueryNodeMemo)
+	if !ok {
+		return nil, true, fmt.Errorf("expected input to validateLeafRefDataIterFunc to be type *util.PathQueryNodeMemo, but got %T", in)
+	}
+	gNMIPath, err := leafRefToGNMIPath(ni, schema.Type.Path, pathQueryNode, skipNonLocal)
+	if err != nil {
+		if skipNonLocal && errors.As(err, new(nonLocalRefError)) {
+			return nil, true, nil
 		}
-		if !match {
-			e := fmt.Errorf("field name %s value %s schema path %s has leafref path %s not equal to any target nodes",
-				ni.StructField.Name, util.ValueStr(ni.FieldValue.Interface()), ni.Schema.Path(), pathStr)
-			util.DbgPrint("ERR: %s", e)
-			return leafrefErrOrLog(util.NewErrs(e), opt)
+		return nil, true, err
+	}
+	// dataNodesAtPath modifies the path that it is supplied.
+	target := proto.Clone(gNMIPath).(*gpb.Path)
+	matchNodes, err := dataNodesAtPath(ni, gNMIPath, pathQueryNode)
+	if err != nil {
+		if skipNonLocal && errors.As(err, new(nonLocalRefError)) {
+			return nil, true, nil
 		}
-
-		return nil
+		return target, true, err
 	}
 
-	pathQueryRootNode := &util.PathQueryNodeMemo{Memo: util.PathQueryMemo{}}
-	return util.ForEachField(schema, value, pathQueryRootNode, nil, validateLeafRefDataIterFunc)
+	util.DbgPrint("Verifying leafref at %s, matching nodes are: %v", util.StripModulePrefixesStr(schema.Type.Path), util.ValueStrDebug(matchNodes))
+
+	match, err := matchesNodes(ni, matchNodes)
+	if err != nil {
+		return target, false, err
+	}
+	return target, match, nil
 }
 
 // leafrefErrOrLog returns an error if the global ValidationOptions specifies
 // that missing data should cause an error to be thrown. If the missing data is to
 // be ignored by leafrefs, it logs the error that would have been returned if the
-// Log field of the LeafrefOptions is set to true.
+// Log field of the LeafrefOptions is set to true, and returns it as a warning.
 func leafrefErrOrLog(e util.Errors, opt *LeafrefOptions) util.Errors {
 	if opt == nil {
 		return e
 	}
 
 	if opt.Log {
-		log.Errorf("%v", e)
+		util.Log().Errorf("%v", e)
 	}
 
-	return nil
+	return withIssueSeverity(WarningSeverity, e)
 }
 
 // leafRefToGNMIPath takes a leafref path string and transforms any leafref
 // path references of the form a[k1 = ../path/to/val and k2 = ...] to a GNMI
 // path where the key values are the values being referenced i.e.
-// ../path/to/val above is replaced with the actual value at that path.
-func leafRefToGNMIPath(root *util.NodeInfo, path string, pathQueryNode *util.PathQueryNodeMemo) (*gpb.Path, error) {
+// ../path/to/val above is replaced with the actual value at that path. If
+// skipNonLocal is set, an error is returned when such a value refers to a node
+// above the root of the data tree.
+func leafRefToGNMIPath(root *util.NodeInfo, path string, pathQueryNode *util.PathQueryNodeMemo, skipNonLocal bool) (*gpb.Path, error) {
 	pv := util.SplitPath(path)
 	out := &gpb.Path{}
 
@@ -145,6 +195,9 @@ func leafRefToGNMIPath(root *util.NodeInfo, path string, pathQueryNode *util.Pat
 			var j string
 			switch len(ns) {
 			case 0:
+				if skipNonLocal && errors.As(err, new(nonLocalRefError)) {
+					return nil, err
+				}
 			case 1:
 				if err != nil {
 					return nil, err
@@ -188,7 +241,7 @@ func dataNodesAtPath(ni *util.NodeInfo, path *gpb.Path, pathQueryNode *util.Path
 		pathQueryRoot = pathQueryNode
 		for len(path.GetElem()) != 0 && path.GetElem()[0].GetName() == ".." {
 			if root.Parent == nil {
-				return nil, fmt.Errorf("no parent for leafref path at %v, with remaining path %s", ni.Schema.Path(), path)
+				return nil, nonLocalRefError{fmt.Errorf("no parent for leafref path at %v, with remaining path %s", ni.Schema.Path(), path)}
 			}
 			_, isOrderedMap := root.Parent.FieldValue.Interface().(ygot.GoOrderedMap)
 			if (root.Parent.Schema.IsList() && (util.IsValueMap(root.Parent.FieldValue) || isOrderedMap)) || (root.Parent.Schema.IsLeafList() && util.IsValueSlice(root.Parent.FieldValue)) {
@@ -0,0 +1,238 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/protobuf/proto"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// LeafrefIntegrityOpt is an interface that is implemented by the options
+// that can be supplied to CheckLeafrefIntegrity.
+type LeafrefIntegrityOpt interface {
+	IsLeafrefIntegrityOpt()
+}
+
+// LeafrefSubtree is a LeafrefIntegrityOpt that restricts the leafrefs that are
+// checked by CheckLeafrefIntegrity to those within the subtree at Path, which
+// is relative to the supplied root. The keys of list elements within Path may
+// be omitted, or set to "*", in which case all entries of the list are
+// checked. The nodes to which the leafrefs refer may be outside of the
+// subtree.
+type LeafrefSubtree struct {
+	// Path is the path of the subtree to be checked.
+	Path *gpb.Path
+}
+
+// IsLeafrefIntegrityOpt implements the LeafrefIntegrityOpt interface.
+func (*LeafrefSubtree) IsLeafrefIntegrityOpt() {}
+
+// hasLeafrefSubtree returns the first LeafrefSubtree from an opts slice, or
+// nil if there isn't one.
+func hasLeafrefSubtree(opts []LeafrefIntegrityOpt) *LeafrefSubtree {
+	for _, o := range opts {
+		switch v := o.(type) {
+		case *LeafrefSubtree:
+			return v
+		}
+	}
+	return nil
+}
+
+// DanglingLeafref describes a leaf or leaf-list whose value is not equal to
+// any of the nodes to which its leafref path refers.
+type DanglingLeafref struct {
+	// Path is the data tree path of the referring leaf or leaf-list.
+	Path *gpb.Path
+	// Value is the value of the referring leaf, or leaf-list element.
+	Value any
+	// LeafrefPath is the path statement of the leafref, with module
+	// prefixes removed.
+	LeafrefPath string
+	// TargetPath is the data tree path of the nodes to which the leafref
+	// refers, with the keys of lists resolved using the values within the
+	// data tree.
+	TargetPath *gpb.Path
+}
+
+// LeafrefIntegrityReport is the result of checking the leafrefs within a
+// data tree using CheckLeafrefIntegrity.
+type LeafrefIntegrityReport struct {
+	// Checked is the number of populated leafrefs that were checked.
+	Checked int
+	// Dangling are the leafrefs whose values are not equal to any of the
+	// nodes to which they refer, sorted by the path of the referring leaf.
+	Dangling []*DanglingLeafref
+}
+
+// CheckLeafrefIntegrity checks each populated leafref within the data tree
+// rooted at root, whose schema is supplied, and returns a report of all of
+// the leafrefs whose values are not equal to any of the nodes to which they
+// refer. Unlike ValidateLeafRefData, which returns an error describing each
+// such leafref, the report describes the referring leaf and the path of the
+// nodes to which it refers, such that callers can act upon them. root should
+// be the root of the entire data tree, since leafrefs may refer to any node
+// within it.
+//
+// An error is returned, along with the report of the leafrefs that could be
+// checked, if any leafref cannot be resolved.
+func CheckLeafrefIntegrity(schema *yang.Entry, root ygot.GoStruct, opts ...LeafrefIntegrityOpt) (*LeafrefIntegrityReport, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("nil schema for root %T", root)
+	}
+	subtree := hasLeafrefSubtree(opts)
+
+	report := &LeafrefIntegrityReport{}
+	iterFunc := func(ni *util.NodeInfo, in, out interface{}) util.Errors {
+		if util.IsValueNil(ni) || util.IsNilOrInvalidValue(ni.FieldValue) || ni.Schema == nil {
+			return nil
+		}
+		if !util.IsLeafRef(ni.Schema) || ni.Schema.IsLeafList() || util.IsValueNilOrDefault(ni.FieldValue.Interface()) {
+			return nil
+		}
+		path, err := nodeInfoDataPath(ni)
+		if err != nil {
+			return util.NewErrs(err)
+		}
+		if subtree != nil && !util.PathMatchesQuery(path, subtree.Path) {
+			return nil
+		}
+
+		report.Checked++
+		target, match, err := checkLeafRef(ni, in, false)
+		if err != nil && match {
+			return util.NewErrs(fmt.Errorf("cannot check leafref at %s: %v", pathStringOrErr(path), err))
+		}
+		if !match {
+			report.Dangling = append(report.Dangling, &DanglingLeafref{
+				Path:        path,
+				Value:       derefValue(ni.FieldValue.Interface()),
+				LeafrefPath: util.StripModulePrefixesStr(ni.Schema.Type.Path),
+				TargetPath:  leafrefTargetPath(path, target),
+			})
+		}
+		return nil
+	}
+
+	errs := util.ForEachField(schema, root, &util.PathQueryNodeMemo{Memo: util.PathQueryMemo{}}, nil, iterFunc)
+
+	sort.SliceStable(report.Dangling, func(i, j int) bool {
+		return pathStringOrErr(report.Dangling[i].Path) < pathStringOrErr(report.Dangling[j].Path)
+	})
+	if errs != nil {
+		return report, errs
+	}
+	return report, nil
+}
+
+// nodeInfoDataPath returns the data tree path of the node ni, which is
+// visited by ForEachField, relative to the root of the traversal.
+func nodeInfoDataPath(ni *util.NodeInfo) (*gpb.Path, error) {
+	var elems []*gpb.PathElem
+	// keys are the keys of the list entry that was most recently
+	// traversed, which are added to the path element of the list.
+	var keys map[string]string
+	for n := ni; n != nil; n = n.Parent {
+		if n.Parent != nil && isListNodeInfo(n.Parent) {
+			// Each entry of a list, or element of a leaf-list, is
+			// visited as a child of the node of the list, which
+			// contributes the path elements.
+			if n.FieldKey.IsValid() {
+				k, err := ygot.PathKeyFromStruct(n.FieldValue)
+				if err != nil {
+					return nil, err
+				}
+				keys = k
+			}
+			continue
+		}
+		pe := make([]*gpb.PathElem, 0, len(n.PathFromParent))
+		for _, p := range n.PathFromParent {
+			pe = append(pe, &gpb.PathElem{Name: p})
+		}
+		if keys != nil && len(pe) != 0 {
+			pe[len(pe)-1].Key = keys
+			keys = nil
+		}
+		elems = append(pe, elems...)
+	}
+	return &gpb.Path{Elem: elems}, nil
+}
+
+// isListNodeInfo reports whether the node ni is a list or leaf-list, whose
+// entries are visited as its children by ForEachField.
+func isListNodeInfo(ni *util.NodeInfo) bool {
+	if util.IsNilOrInvalidValue(ni.FieldValue) {
+		return false
+	}
+	if _, ok := ni.FieldValue.Interface().(ygot.GoOrderedMap); ok {
+		return true
+	}
+	return util.IsValueMap(ni.FieldValue) || util.IsValueSlice(ni.FieldValue)
+}
+
+// leafrefTargetPath returns the absolute data tree path of target, which is
+// the path of the nodes referred to by the leafref at path. Relative target
+// paths are resolved against path, whereas absolute target paths, which
+// begin with an element with an empty name, are returned without it.
+func leafrefTargetPath(path, target *gpb.Path) *gpb.Path {
+	if target == nil {
+		return nil
+	}
+	te := target.GetElem()
+	if len(te) > 0 && te[0].GetName() == "" {
+		return &gpb.Path{Elem: te[1:]}
+	}
+	out := proto.Clone(path).(*gpb.Path)
+	for _, e := range te {
+		switch {
+		case e.GetName() == "..":
+			if len(out.Elem) > 0 {
+				out.Elem = out.Elem[:len(out.Elem)-1]
+			}
+		default:
+			out.Elem = append(out.Elem, e)
+		}
+	}
+	return out
+}
+
+// derefValue returns the value pointed to by v if it is a pointer, or v
+// otherwise.
+func derefValue(v any) any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() && !util.IsValueStructPtr(rv) {
+		return rv.Elem().Interface()
+	}
+	return v
+}
+
+// pathStringOrErr returns the string representation of path, or a
+// description of the error if it cannot be represented as a string.
+func pathStringOrErr(path *gpb.Path) string {
+	s, err := ygot.PathToString(path)
+	if err != nil {
+		return fmt.Sprintf("<invalid path: %v>", err)
+	}
+	return s
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ygot/integration_tests/schemaops/utestschema"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// leafrefIntegrityDevice returns a Device containing both valid and dangling
+// leafrefs with relative and absolute paths.
+func leafrefIntegrityDevice(t *testing.T) *utestschema.Device {
+	t.Helper()
+	d := &utestschema.Device{}
+	ul := d.GetOrCreateUnorderedLists()
+	// The key of entry "a" refers to its config/key leaf, whereas the
+	// config/key leaf of entry "b" is not populated.
+	ul.GetOrCreateUnorderedList("a").GetOrCreateConfig().Key = ygot.String("a")
+	ul.GetOrCreateUnorderedList("b")
+
+	d.GetOrCreateTarget().GetOrCreateEntity("e1")
+	ref := d.GetOrCreateRef()
+	ref.GetOrCreateReference("e1")
+	ref.GetOrCreateReference("e2")
+	return d
+}
+
+func TestCheckLeafrefIntegrity(t *testing.T) {
+	tests := []struct {
+		desc        string
+		inOpts      []ytypes.LeafrefIntegrityOpt
+		wantChecked int
+		want        []*ytypes.DanglingLeafref
+	}{{
+		desc:        "whole tree",
+		wantChecked: 4,
+		want: []*ytypes.DanglingLeafref{{
+			Path:        mustPath("/ref/reference[name=e2]/name"),
+			Value:       "e2",
+			LeafrefPath: "/target/entity/name",
+			TargetPath:  mustPath("/target/entity/name"),
+		}, {
+			Path:        mustPath("/unordered-lists/unordered-list[key=b]/key"),
+			Value:       "b",
+			LeafrefPath: "../config/key",
+			TargetPath:  mustPath("/unordered-lists/unordered-list[key=b]/config/key"),
+		}},
+	}, {
+		desc:        "subtree",
+		inOpts:      []ytypes.LeafrefIntegrityOpt{&ytypes.LeafrefSubtree{Path: mustPath("/unordered-lists")}},
+		wantChecked: 2,
+		want: []*ytypes.DanglingLeafref{{
+			Path:        mustPath("/unordered-lists/unordered-list[key=b]/key"),
+			Value:       "b",
+			LeafrefPath: "../config/key",
+			TargetPath:  mustPath("/unordered-lists/unordered-list[key=b]/config/key"),
+		}},
+	}, {
+		desc:        "subtree with list keys",
+		inOpts:      []ytypes.LeafrefIntegrityOpt{&ytypes.LeafrefSubtree{Path: mustPath("/ref/reference[name=e1]")}},
+		wantChecked: 1,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ytypes.CheckLeafrefIntegrity(utestschema.SchemaTree["Device"], leafrefIntegrityDevice(t), tt.inOpts...)
+			if err != nil {
+				t.Fatalf("CheckLeafrefIntegrity: got unexpected error: %v", err)
+			}
+			if got.Checked != tt.wantChecked {
+				t.Errorf("CheckLeafrefIntegrity: got %d checked leafrefs, want %d", got.Checked, tt.wantChecked)
+			}
+			if diff := cmp.Diff(tt.want, got.Dangling, protocmp.Transform()); diff != "" {
+				t.Errorf("CheckLeafrefIntegrity: did not get expected dangling leafrefs (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -788,6 +788,159 @@ func TestValidateLeafRefDataCompressedSchemaListOnly(t *testing.T) {
 	}
 }
 
+func TestValidateSkipNonLocalRefs(t *testing.T) {
+	// YANG Schema:
+	// leaf hostname {
+	//   type string;
+	// }
+	// container components {
+	//   list component {
+	//     key "name";
+	//     leaf name {
+	//       type string;
+	//     }
+	//   }
+	// }
+	// container interfaces {
+	//   list interface {
+	//     key "name";
+	//     leaf name {
+	//       type leafref {
+	//         path "../config/name";
+	//       }
+	//     }
+	//     container config {
+	//       leaf name {
+	//         type string;
+	//       }
+	//       leaf name-ref {
+	//         type leafref {
+	//           path "../name";
+	//         }
+	//       }
+	//       leaf hostname-ref {
+	//         type leafref {
+	//           path "../../../../hostname";
+	//         }
+	//       }
+	//       leaf component-ref {
+	//         type leafref {
+	//           path "/components/component/name";
+	//         }
+	//       }
+	//     }
+	//   }
+	// }
+	stringLeaf := func(name string) *yang.Entry {
+		return &yang.Entry{Name: name, Kind: yang.LeafEntry, Type: &yang.YangType{Kind: yang.Ystring}}
+	}
+	leafrefLeaf := func(name, path string) *yang.Entry {
+		return &yang.Entry{Name: name, Kind: yang.LeafEntry, Type: &yang.YangType{Kind: yang.Yleafref, Path: path}}
+	}
+	fakeRootSchema := &yang.Entry{
+		Name: "device",
+		Kind: yang.DirectoryEntry,
+		Dir: map[string]*yang.Entry{
+			"hostname": stringLeaf("hostname"),
+			"components": {
+				Name: "components",
+				Kind: yang.DirectoryEntry,
+				Dir: map[string]*yang.Entry{
+					"component": {
+						Name:     "component",
+						Kind:     yang.DirectoryEntry,
+						ListAttr: yang.NewDefaultListAttr(),
+						Key:      "name",
+						Dir:      map[string]*yang.Entry{"name": stringLeaf("name")},
+					},
+				},
+			},
+			"interfaces": {
+				Name: "interfaces",
+				Kind: yang.DirectoryEntry,
+				Dir: map[string]*yang.Entry{
+					"interface": {
+						Name:     "interface",
+						Kind:     yang.DirectoryEntry,
+						ListAttr: yang.NewDefaultListAttr(),
+						Key:      "name",
+						Dir: map[string]*yang.Entry{
+							"name": leafrefLeaf("name", "../config/name"),
+							"config": {
+								Name: "config",
+								Kind: yang.DirectoryEntry,
+								Dir: map[string]*yang.Entry{
+									"name":          stringLeaf("name"),
+									"name-ref":      leafrefLeaf("name-ref", "../name"),
+									"hostname-ref":  leafrefLeaf("hostname-ref", "../../../../hostname"),
+									"component-ref": leafrefLeaf("component-ref", "/components/component/name"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Annotation: map[string]interface{}{"isCompressedSchema": true, "isFakeRoot": true},
+	}
+	addParents(fakeRootSchema)
+	interfaceSchema := fakeRootSchema.Dir["interfaces"].Dir["interface"]
+
+	type Interface struct {
+		Name         *string `path:"config/name|name"`
+		NameRef      *string `path:"config/name-ref"`
+		HostnameRef  *string `path:"config/hostname-ref"`
+		ComponentRef *string `path:"config/component-ref"`
+	}
+
+	tests := []struct {
+		desc    string
+		in      *Interface
+		inOpts  []ygot.ValidationOption
+		wantErr string
+	}{{
+		desc: "non-local refs are skipped",
+		in: &Interface{
+			Name:         String("eth0"),
+			NameRef:      String("eth0"),
+			HostnameRef:  String("router1"),
+			ComponentRef: String("linecard0"),
+		},
+		inOpts: []ygot.ValidationOption{&SkipNonLocalRefs{}},
+	}, {
+		desc: "local ref is validated",
+		in: &Interface{
+			Name:    String("eth0"),
+			NameRef: String("eth1"),
+		},
+		inOpts:  []ygot.ValidationOption{&SkipNonLocalRefs{}},
+		wantErr: `field name NameRef value eth1 (string ptr) schema path /device/interfaces/interface/config/name-ref has leafref path ../name not equal to any target nodes`,
+	}, {
+		desc: "local ref is validated with other options",
+		in: &Interface{
+			Name:    String("eth0"),
+			NameRef: String("eth1"),
+		},
+		inOpts: []ygot.ValidationOption{&SkipNonLocalRefs{}, &LeafrefOptions{IgnoreMissingData: true}},
+	}, {
+		desc: "leafrefs are not validated without the option",
+		in: &Interface{
+			Name:    String("eth0"),
+			NameRef: String("eth1"),
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			errs := Validate(interfaceSchema, tt.in, tt.inOpts...)
+			if got, want := errs.String(), tt.wantErr; got != want {
+				t.Errorf("Validate: got error: %s, want error: %s", got, want)
+			}
+			testErrLog(t, tt.desc, errs)
+		})
+	}
+}
+
 func TestSplitUnescaped(t *testing.T) {
 	tests := []struct {
 		desc string
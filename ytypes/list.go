@@ -17,6 +17,7 @@ package ytypes
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/kylelemons/godebug/pretty"
@@ -24,13 +25,15 @@ import (
 	"github.com/openconfig/ygot/internal/yreflect"
 	"github.com/openconfig/ygot/util"
 	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
 )
 
 // Refer to: https://tools.ietf.org/html/rfc6020#section-7.8.
 
 // validateList validates each of the values in the map, keyed by the list Key
 // value, against the given list schema.
-func validateList(schema *yang.Entry, value interface{}) util.Errors {
+func validateList(schema *yang.Entry, value interface{}, opts ...ygot.ValidationOption) util.Errors {
 	var errors []error
 	if util.IsValueNil(value) {
 		return nil
@@ -50,15 +53,19 @@ func validateList(schema *yang.Entry, value interface{}) util.Errors {
 		// Skip this check if not a list type - in this case value may be a list
 		// element which shares the list schema (excluding ListAttr).
 		errors = util.AppendErrs(errors, validateListAttr(schema, value))
+		// Check that the entries satisfy the unique statements of the
+		// list.
+		errors = util.AppendErrs(errors, validateListUnique(schema, value))
 	}
 
 	checkMapElement := func(key, val reflect.Value) {
 		structElems := val.Elem()
 		// Check that keys are present and have correct values.
-		errors = util.AppendErrs(errors, checkKeys(schema, structElems, key))
+		errs := withIssueKindErrs(KeyIssue, checkKeys(schema, structElems, key))
 
 		// Verify each elements's fields.
-		errors = util.AppendErrs(errors, validateStructElems(schema, val.Interface()))
+		errs = util.AppendErrs(errs, validateStructElems(schema, val.Interface(), opts...))
+		errors = util.AppendErrs(errors, withIssuePath(errs, []*gpb.PathElem{listEntryElem(val.Interface())}))
 	}
 
 	switch {
@@ -71,7 +78,7 @@ func validateList(schema *yang.Entry, value interface{}) util.Errors {
 		// List without key is a slice in the data tree.
 		sv := reflect.ValueOf(value)
 		for i := 0; i < sv.Len(); i++ {
-			errors = util.AppendErrs(errors, validateStructElems(schema, sv.Index(i).Interface()))
+			errors = util.AppendErrs(errors, validateStructElems(schema, sv.Index(i).Interface(), opts...))
 		}
 	case kind == reflect.Map:
 		// List with key is a map in the data tree, with the key being the value
@@ -83,7 +90,7 @@ func validateList(schema *yang.Entry, value interface{}) util.Errors {
 		// Validate was called on a list element rather than the whole list, or
 		// on a completely bogus struct. In either case, evaluate just the
 		// element against the list schema without considering list attributes.
-		errors = util.AppendErrs(errors, validateStructElems(schema, value))
+		errors = util.AppendErrs(errors, validateStructElems(schema, value, opts...))
 
 	default:
 		errors = util.AppendErr(errors, fmt.Errorf("validateList expected map/slice/GoOrderedMap type for %s, got %T", schema.Name, value))
@@ -176,10 +183,101 @@ func checkStructKeyValues(structElems reflect.Value, keyStruct reflect.Value) ut
 	return errors
 }
 
+// validateListUnique checks that the entries of the list value, which must be
+// a map, slice or GoOrderedMap, satisfy each of the unique statements of the
+// list schema. Per RFC7950 Section 7.8.3, the combined values of the leaves
+// referred to by a unique statement must be unique within all entries of the
+// list in which all of the leaves are populated. An error identifying the
+// clashing entries is returned for each entry whose values are equal to those
+// of an earlier entry.
+func validateListUnique(schema *yang.Entry, value interface{}) util.Errors {
+	constraints := util.ListUniqueConstraints(schema)
+	if len(constraints) == 0 {
+		return nil
+	}
+
+	type listEntry struct {
+		id  string
+		val interface{}
+	}
+	var entries []listEntry
+	orderedMap, isOrderedMap := value.(ygot.GoOrderedMap)
+	rv := reflect.ValueOf(value)
+	switch {
+	case isOrderedMap:
+		if err := yreflect.RangeOrderedMap(orderedMap, func(k, v reflect.Value) bool {
+			entries = append(entries, listEntry{id: fmt.Sprintf("%v", k.Interface()), val: v.Interface()})
+			return true
+		}); err != nil {
+			return util.NewErrs(err)
+		}
+	case rv.Kind() == reflect.Map:
+		for _, k := range rv.MapKeys() {
+			entries = append(entries, listEntry{id: fmt.Sprintf("%v", k.Interface()), val: rv.MapIndex(k).Interface()})
+		}
+		// Map iteration order is random, hence sort the entries such
+		// that the same entries are reported as clashing each time.
+		sort.Slice(entries, func(i, j int) bool { return entries[i].id < entries[j].id })
+	case rv.Kind() == reflect.Slice:
+		for i := 0; i < rv.Len(); i++ {
+			entries = append(entries, listEntry{id: fmt.Sprintf("#%d", i), val: rv.Index(i).Interface()})
+		}
+	}
+
+	var errors []error
+	for _, c := range constraints {
+		// seen maps the values of the leaves of the constraint to the
+		// first entry in which they were found.
+		seen := map[string]string{}
+		for _, e := range entries {
+			vals, ok, err := uniqueLeafValues(schema, e.val, c)
+			if err != nil {
+				errors = util.AppendErr(errors, fmt.Errorf("list %s entry %s: %v", schema.Name, e.id, err))
+				continue
+			}
+			if !ok {
+				continue
+			}
+			if prev, ok := seen[vals]; ok {
+				errors = util.AppendErr(errors, withIssueKind(UniqueIssue, fmt.Errorf("list %s entries %s and %s have the same values %s for unique statement %q", schema.Name, prev, e.id, vals, strings.Join(c, " "))))
+				continue
+			}
+			seen[vals] = e.id
+		}
+	}
+	return errors
+}
+
+// uniqueLeafValues returns the string representation of the values of the
+// leaves at paths, which are relative to the list entry, and whose schema is
+// the list schema. It returns false if any of the leaves is not populated.
+func uniqueLeafValues(schema *yang.Entry, entry interface{}, paths []string) (string, bool, error) {
+	var vals []string
+	for _, p := range paths {
+		path := &gpb.Path{}
+		for _, e := range strings.Split(p, "/") {
+			path.Elem = append(path.Elem, &gpb.PathElem{Name: e})
+		}
+		nodes, err := GetNode(schema, entry, path, &GetTolerateNil{})
+		if err != nil {
+			return "", false, fmt.Errorf("cannot retrieve leaf %s: %v", p, err)
+		}
+		if len(nodes) == 0 || util.IsValueNil(nodes[0].Data) {
+			return "", false, nil
+		}
+		v := nodes[0].Data
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr {
+			v = rv.Elem().Interface()
+		}
+		vals = append(vals, fmt.Sprintf("%s=%v", p, v))
+	}
+	return "[" + strings.Join(vals, ", ") + "]", true, nil
+}
+
 // validateStructElems validates each of the struct fields against the schema.
 // TODO(mostrowski): choice directly under list is not handled here.
 // Also, there's code duplication with a very similar operation in container.
-func validateStructElems(schema *yang.Entry, value interface{}) util.Errors {
+func validateStructElems(schema *yang.Entry, value interface{}, opts ...ygot.ValidationOption) util.Errors {
 	var errors []error
 	structElems := reflect.ValueOf(value).Elem()
 	structTypes := structElems.Type()
@@ -205,10 +303,13 @@ func validateStructElems(schema *yang.Entry, value interface{}) util.Errors {
 			errors = util.AppendErr(errors, err)
 			continue
 		}
-		if cschema == nil {
+		switch {
+		case cschema == nil:
 			errors = util.AppendErr(errors, fmt.Errorf("child schema not found for struct %s field %s", schema.Name, fieldName))
-		} else {
-			errors = util.AppendErrs(errors, Validate(cschema, fieldValue))
+		case util.IsValueNil(fieldValue) && isUnsetList(cschema, fieldValue) && !listAncestorExists(cschema, schema, value):
+			// The min-elements constraint of the list does not apply.
+		default:
+			errors = util.AppendErrs(errors, withIssuePath(validateNode(cschema, fieldValue, opts...), fieldPathElems(ft)))
 		}
 	}
 
@@ -346,13 +447,29 @@ func unmarshalList(schema *yang.Entry, parent interface{}, jsonList interface{},
 	// types respectively.
 	// For a keyed list, the value(s) of the key are derived from the key fields
 	// in the new list element.
+	//
+	// In best-effort mode, list elements that are only partially unmarshalled
+	// are still inserted, and the errors are accumulated in ce.
+	bestEffort := hasBestEffortUnmarshal(opts)
+	var ce *ComplianceErrors
 	for _, le := range jl {
 		var err error
-		jt := le.(map[string]interface{})
+		jt, ok := le.(map[string]interface{})
+		if !ok {
+			err := fmt.Errorf("unmarshalList for schema %s: got list element type %T, expect map[string]interface{}", schema.Name, le)
+			if bestEffort {
+				ce = ce.appendSkipped(schema.Path(), err)
+				continue
+			}
+			return err
+		}
 		newVal := reflect.New(listElementType.Elem())
 		util.DbgPrint("creating a new list element val of type %v", newVal.Type())
 		if err := unmarshalStruct(schema, newVal.Interface(), jt, enc, opts...); err != nil {
-			return err
+			if _, ok := err.(*ComplianceErrors); !ok || !bestEffort {
+				return err
+			}
+			ce = ce.appendSkipped(schema.Path(), err)
 		}
 
 		switch {
@@ -362,6 +479,10 @@ func unmarshalList(schema *yang.Entry, parent interface{}, jsonList interface{},
 			var newKey reflect.Value
 			newKey, err = makeKeyForInsert(schema, parent, newVal)
 			if err != nil {
+				if bestEffort {
+					ce = ce.appendSkipped(schema.Path(), err)
+					continue
+				}
 				return err
 			}
 			// First try to get the existing element.
@@ -372,7 +493,11 @@ func unmarshalList(schema *yang.Entry, parent interface{}, jsonList interface{},
 				val = newVal
 			} else {
 				if err := unmarshalStruct(schema, val.Interface(), jt, enc, opts...); err != nil {
-					return err
+					if _, ok := err.(*ComplianceErrors); !ok || !bestEffort {
+						return err
+					}
+					// Errors for this element have already been recorded
+					// when unmarshalling into newVal.
 				}
 			}
 
@@ -388,6 +513,9 @@ func unmarshalList(schema *yang.Entry, parent interface{}, jsonList interface{},
 	}
 	util.DbgPrint("list after unmarshal:\n%s\n", pretty.Sprint(parent))
 
+	if ce != nil {
+		return ce
+	}
 	return nil
 }
 
@@ -367,6 +367,10 @@ func TestValidatedOrderedMap(t *testing.T) {
 			return om
 		}(),
 		wantErr: true,
+	}, {
+		desc:     "single-keyed list unset",
+		inSchema: ctestschema.SchemaTree["OrderedList"],
+		inVal:    (*ctestschema.OrderedList_OrderedMap)(nil),
 	}, {
 		desc:     "single-keyed list with too many elements",
 		inSchema: ctestschema.SchemaTree["OrderedList"],
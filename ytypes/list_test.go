@@ -215,6 +215,180 @@ func TestValidateListNoKey(t *testing.T) {
 	}
 }
 
+type boundedListElemStruct struct {
+	LeafName *string `path:"leaf-name"`
+}
+
+type boundedListParentStruct struct {
+	List []*boundedListElemStruct `path:"list-schema"`
+}
+
+func (*boundedListParentStruct) IsYANGGoStruct()                          {}
+func (*boundedListParentStruct) ΛValidate(...ygot.ValidationOption) error { return nil }
+func (*boundedListParentStruct) ΛEnumTypeMap() map[string][]reflect.Type  { return nil }
+func (*boundedListParentStruct) ΛBelongingModule() string                 { return "bar" }
+
+type boundedListChoiceStruct struct {
+	List  []*boundedListElemStruct `path:"list-schema"`
+	AName *string                  `path:"a-name"`
+	BName *string                  `path:"b-name"`
+}
+
+func (*boundedListChoiceStruct) IsYANGGoStruct()                          {}
+func (*boundedListChoiceStruct) ΛValidate(...ygot.ValidationOption) error { return nil }
+func (*boundedListChoiceStruct) ΛEnumTypeMap() map[string][]reflect.Type  { return nil }
+func (*boundedListChoiceStruct) ΛBelongingModule() string                 { return "bar" }
+
+func TestValidateListElements(t *testing.T) {
+	containerSchema := &yang.Entry{
+		Name: "parent",
+		Kind: yang.DirectoryEntry,
+		Dir:  map[string]*yang.Entry{},
+	}
+	listSchema := &yang.Entry{
+		Name:     "list-schema",
+		Kind:     yang.DirectoryEntry,
+		ListAttr: &yang.ListAttr{MinElements: 1, MaxElements: 2},
+		Parent:   containerSchema,
+		Dir: map[string]*yang.Entry{
+			"leaf-name": {
+				Kind: yang.LeafEntry,
+				Name: "leaf-name",
+				Type: &yang.YangType{Kind: yang.Ystring},
+			},
+		},
+	}
+	containerSchema.Dir["list-schema"] = listSchema
+
+	// choiceSchema is a container with a choice, one of whose cases
+	// contains a list with the same constraints as listSchema.
+	choiceSchema := &yang.Entry{
+		Name: "choice-parent",
+		Kind: yang.DirectoryEntry,
+		Dir:  map[string]*yang.Entry{},
+	}
+	choice := &yang.Entry{
+		Name:   "choice",
+		Kind:   yang.ChoiceEntry,
+		Parent: choiceSchema,
+		Dir:    map[string]*yang.Entry{},
+	}
+	choiceSchema.Dir["choice"] = choice
+	for _, c := range []struct{ name, leaf string }{{"a", "a-name"}, {"b", "b-name"}} {
+		caseSchema := &yang.Entry{
+			Name:   c.name,
+			Kind:   yang.CaseEntry,
+			Parent: choice,
+			Dir:    map[string]*yang.Entry{},
+		}
+		caseSchema.Dir[c.leaf] = &yang.Entry{
+			Name:   c.leaf,
+			Kind:   yang.LeafEntry,
+			Type:   &yang.YangType{Kind: yang.Ystring},
+			Parent: caseSchema,
+		}
+		choice.Dir[c.name] = caseSchema
+	}
+	caseListSchema := &yang.Entry{
+		Name:     "list-schema",
+		Kind:     yang.DirectoryEntry,
+		ListAttr: &yang.ListAttr{MinElements: 1},
+		Parent:   choice.Dir["a"],
+		Dir: map[string]*yang.Entry{
+			"leaf-name": {
+				Kind: yang.LeafEntry,
+				Name: "leaf-name",
+				Type: &yang.YangType{Kind: yang.Ystring},
+			},
+		},
+	}
+	choice.Dir["a"].Dir["list-schema"] = caseListSchema
+
+	tests := []struct {
+		desc    string
+		schema  *yang.Entry
+		val     interface{}
+		inOpts  []ygot.ValidationOption
+		wantErr string
+	}{{
+		desc:   "success",
+		schema: listSchema,
+		val:    []*boundedListElemStruct{{LeafName: ygot.String("one")}},
+	}, {
+		desc:   "success with list element",
+		schema: listSchema,
+		val:    &boundedListElemStruct{LeafName: ygot.String("one")},
+	}, {
+		desc:   "success with nil list element",
+		schema: listSchema,
+		val:    (*boundedListElemStruct)(nil),
+	}, {
+		desc:    "too few elements",
+		schema:  listSchema,
+		val:     []*boundedListElemStruct{},
+		wantErr: `list /parent/list-schema contains fewer than min required elements: 0 < 1`,
+	}, {
+		desc:   "nil list",
+		schema: listSchema,
+		val:    []*boundedListElemStruct(nil),
+	}, {
+		desc:    "too few elements with nil list",
+		schema:  listSchema,
+		val:     []*boundedListElemStruct(nil),
+		inOpts:  []ygot.ValidationOption{&EnforceUnsetListMinElements{}},
+		wantErr: `list /parent/list-schema contains fewer than min required elements: 0 < 1`,
+	}, {
+		desc:   "too many elements",
+		schema: listSchema,
+		val: []*boundedListElemStruct{
+			{LeafName: ygot.String("one")},
+			{LeafName: ygot.String("two")},
+			{LeafName: ygot.String("three")},
+		},
+		wantErr: `list /parent/list-schema contains more than max allowed elements: 3 > 2`,
+	}, {
+		desc:   "partial tree without list within container",
+		schema: containerSchema,
+		val:    &boundedListParentStruct{},
+	}, {
+		desc:    "unset list within container",
+		schema:  containerSchema,
+		val:     &boundedListParentStruct{},
+		inOpts:  []ygot.ValidationOption{&EnforceUnsetListMinElements{}},
+		wantErr: `/parent/list-schema: list /parent/list-schema contains fewer than min required elements: 0 < 1`,
+	}, {
+		desc:   "set list within container",
+		schema: containerSchema,
+		val:    &boundedListParentStruct{List: []*boundedListElemStruct{{LeafName: ygot.String("one")}}},
+	}, {
+		desc:   "unset list within case that does not exist",
+		schema: choiceSchema,
+		val:    &boundedListChoiceStruct{},
+		inOpts: []ygot.ValidationOption{&EnforceUnsetListMinElements{}},
+	}, {
+		desc:   "unset list within case that is not selected",
+		schema: choiceSchema,
+		val:    &boundedListChoiceStruct{BName: ygot.String("b")},
+		inOpts: []ygot.ValidationOption{&EnforceUnsetListMinElements{}},
+	}, {
+		desc:    "unset list within selected case",
+		schema:  choiceSchema,
+		val:     &boundedListChoiceStruct{AName: ygot.String("a")},
+		inOpts:  []ygot.ValidationOption{&EnforceUnsetListMinElements{}},
+		wantErr: `/choice-parent/choice/a/list-schema: list /choice-parent/list-schema contains fewer than min required elements: 0 < 1`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			errs := Validate(tt.schema, tt.val, tt.inOpts...)
+			if got, want := errs.String(), tt.wantErr; got != want {
+				t.Errorf("%s: Validate got error: %v, want error: %v", tt.desc, got, want)
+			}
+			testErrLog(t, tt.desc, errs)
+		})
+	}
+}
+
 func TestValidateListSimpleKey(t *testing.T) {
 	listSchema := &yang.Entry{
 		Name:     "list-schema",
@@ -389,6 +563,105 @@ func TestValidateListStructKey(t *testing.T) {
 	}
 }
 
+func TestValidateListUnique(t *testing.T) {
+	listSchema := &yang.Entry{
+		Name:     "list-schema",
+		Kind:     yang.DirectoryEntry,
+		ListAttr: yang.NewDefaultListAttr(),
+		Key:      "key",
+		Config:   yang.TSTrue,
+		Dir: map[string]*yang.Entry{
+			"key": {
+				Kind: yang.LeafEntry,
+				Name: "key",
+				Type: &yang.YangType{Kind: yang.Ystring},
+			},
+			"a": {
+				Kind: yang.LeafEntry,
+				Name: "a",
+				Type: &yang.YangType{Kind: yang.Ystring},
+			},
+			"b": {
+				Kind: yang.LeafEntry,
+				Name: "b",
+				Type: &yang.YangType{Kind: yang.Yuint32},
+			},
+			"config": {
+				Kind: yang.DirectoryEntry,
+				Name: "config",
+				Dir: map[string]*yang.Entry{
+					"c": {
+						Kind: yang.LeafEntry,
+						Name: "c",
+						Type: &yang.YangType{Kind: yang.Ystring},
+					},
+				},
+			},
+		},
+		Extra: map[string][]interface{}{
+			"unique": {
+				&yang.Value{Name: "a b"},
+				// Schemas that are unmarshalled from JSON store
+				// the statement as a map.
+				map[string]interface{}{"Name": "pfx:config/pfx:c"},
+			},
+		},
+	}
+
+	type ListElemStruct struct {
+		Key *string `path:"key"`
+		A   *string `path:"a"`
+		B   *uint32 `path:"b"`
+		C   *string `path:"config/c"`
+	}
+
+	tests := []struct {
+		desc             string
+		val              map[string]*ListElemStruct
+		wantErrSubstring string
+	}{{
+		desc: "unique values",
+		val: map[string]*ListElemStruct{
+			"k1": {Key: ygot.String("k1"), A: ygot.String("x"), B: ygot.Uint32(1), C: ygot.String("one")},
+			"k2": {Key: ygot.String("k2"), A: ygot.String("x"), B: ygot.Uint32(2), C: ygot.String("two")},
+		},
+	}, {
+		desc: "duplicate values for multiple leaves",
+		val: map[string]*ListElemStruct{
+			"k1": {Key: ygot.String("k1"), A: ygot.String("x"), B: ygot.Uint32(1)},
+			"k2": {Key: ygot.String("k2"), A: ygot.String("x"), B: ygot.Uint32(1)},
+		},
+		wantErrSubstring: `list list-schema entries k1 and k2 have the same values [a=x, b=1] for unique statement "a b"`,
+	}, {
+		desc: "duplicate values with unpopulated leaf",
+		val: map[string]*ListElemStruct{
+			"k1": {Key: ygot.String("k1"), A: ygot.String("x")},
+			"k2": {Key: ygot.String("k2"), A: ygot.String("x")},
+		},
+	}, {
+		desc: "duplicate values for descendant leaf",
+		val: map[string]*ListElemStruct{
+			"k1": {Key: ygot.String("k1"), C: ygot.String("one")},
+			"k2": {Key: ygot.String("k2"), C: ygot.String("two")},
+			"k3": {Key: ygot.String("k3"), C: ygot.String("one")},
+		},
+		wantErrSubstring: `list list-schema entries k1 and k3 have the same values [config/c=one] for unique statement "config/c"`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			errs := Validate(listSchema, tt.val)
+			var err error
+			if errs != nil {
+				err = errs
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Errorf("Validate: %s", diff)
+			}
+		})
+	}
+}
+
 func TestUnmarshalList(t *testing.T) {
 	// nil value
 	if got := unmarshalList(nil, nil, nil, JSONEncoding); got != nil {
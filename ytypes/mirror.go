@@ -0,0 +1,146 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// MirrorConfigToState copies the value of each leaf within a config container
+// of the data tree into the corresponding leaf of its sibling state container,
+// i.e., it reflects the intended configuration as the applied configuration,
+// as described by the OpenConfig operational state conventions. Any parents
+// of the state leaves that do not exist are created.
+//
+// Only the subtrees of root at the supplied paths, which may contain
+// wildcards, are mirrored. Paths that do not exist within root are ignored. If
+// no paths are supplied, the whole of root is mirrored. schema is the schema
+// of root, e.g., uexampleoc.SchemaTree["Device"].
+//
+// When the GoStructs were generated with compressed paths, each config leaf
+// and its state counterpart are represented by the same field, whose path and
+// shadow-path tags refer to the config and state leaves, hence there is
+// nothing to mirror and root is left unchanged.
+func MirrorConfigToState(schema *yang.Entry, root ygot.GoStruct, paths []*gpb.Path) error {
+	if schema == nil {
+		return fmt.Errorf("nil schema for root %T", root)
+	}
+	if util.IsValueNil(root) {
+		return fmt.Errorf("invalid input to MirrorConfigToState, got nil value: %v", root)
+	}
+	if util.IsCompressedSchema(schema) {
+		return nil
+	}
+	if len(paths) == 0 {
+		paths = []*gpb.Path{{}}
+	}
+
+	for _, p := range paths {
+		nodes, err := GetNode(schema, root, p, &GetHandleWildcards{})
+		switch {
+		case status.Code(err) == codes.NotFound:
+			continue
+		case err != nil:
+			return fmt.Errorf("cannot retrieve nodes at %s: %v", pathString(p), err)
+		}
+		for _, n := range nodes {
+			leaves, err := subtreeLeaves(n)
+			if err != nil {
+				return fmt.Errorf("cannot retrieve leaves of %s: %v", pathString(n.Path), err)
+			}
+			for _, l := range leaves {
+				if err := mirrorLeaf(schema, root, l); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// subtreeLeaves returns the populated leaves of the data tree node n as gNMI
+// updates with absolute paths.
+func subtreeLeaves(n *TreeNode) ([]*gpb.Update, error) {
+	if util.IsValueNil(n.Data) {
+		return nil, nil
+	}
+	if !n.Schema.IsDir() {
+		tv, err := ygot.EncodeTypedValue(n.Data, gpb.Encoding_JSON_IETF, &ygot.RFC7951JSONConfig{})
+		if err != nil {
+			return nil, err
+		}
+		return []*gpb.Update{{Path: n.Path, Val: tv}}, nil
+	}
+
+	s, ok := n.Data.(ygot.GoStruct)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a GoStruct", n.Data)
+	}
+	notifs, err := ygot.TogNMINotifications(s, 0, ygot.GNMINotificationsConfig{
+		UsePathElem:    true,
+		PathElemPrefix: n.Path.GetElem(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var leaves []*gpb.Update
+	for _, notif := range notifs {
+		for _, u := range notif.GetUpdate() {
+			p := proto.Clone(notif.GetPrefix()).(*gpb.Path)
+			if p == nil {
+				p = &gpb.Path{}
+			}
+			p.Elem = append(p.Elem, u.GetPath().GetElem()...)
+			leaves = append(leaves, &gpb.Update{Path: p, Val: u.GetVal()})
+		}
+	}
+	return leaves, nil
+}
+
+// mirrorLeaf sets the state leaf that corresponds to the leaf l to its value
+// if l is within a config container and the state leaf exists in the schema.
+func mirrorLeaf(schema *yang.Entry, root ygot.GoStruct, l *gpb.Update) error {
+	elems := l.GetPath().GetElem()
+	if len(elems) < 2 || util.StripModulePrefix(elems[len(elems)-2].GetName()) != "config" {
+		return nil
+	}
+
+	statePath := proto.Clone(l.GetPath()).(*gpb.Path)
+	sp := statePath.Elem[len(elems)-2]
+	sp.Name = strings.TrimSuffix(sp.Name, "config") + "state"
+
+	var names []string
+	for _, e := range statePath.Elem {
+		names = append(names, e.GetName())
+	}
+	if util.FirstChild(schema, names) == nil {
+		return nil
+	}
+
+	if err := SetNode(schema, root, statePath, l.GetVal(), &InitMissingElements{}); err != nil {
+		return fmt.Errorf("cannot mirror %s to %s: %v", pathString(l.GetPath()), pathString(statePath), err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/exampleoc"
+	"github.com/openconfig/ygot/uexampleoc"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestMirrorConfigToState(t *testing.T) {
+	uncompressed := func() *uexampleoc.Device {
+		d := &uexampleoc.Device{}
+		for _, name := range []string{"eth0", "eth1"} {
+			i := d.GetOrCreateInterfaces().GetOrCreateInterface(name)
+			c := i.GetOrCreateConfig()
+			c.Name = ygot.String(name)
+			c.Mtu = ygot.Uint16(1500)
+			c.Description = ygot.String("uplink")
+		}
+		d.GetOrCreateSystem().GetOrCreateConfig().Hostname = ygot.String("dev")
+		return d
+	}
+	mirrored := func(d *uexampleoc.Device, names ...string) *uexampleoc.Device {
+		for _, name := range names {
+			s := d.GetOrCreateInterfaces().GetOrCreateInterface(name).GetOrCreateState()
+			s.Name = ygot.String(name)
+			s.Mtu = ygot.Uint16(1500)
+			s.Description = ygot.String("uplink")
+		}
+		return d
+	}
+
+	tests := []struct {
+		desc             string
+		inSchema         *yang.Entry
+		inRoot           ygot.GoStruct
+		inPaths          []*gpb.Path
+		want             ygot.GoStruct
+		wantErrSubstring string
+	}{{
+		desc:     "uncompressed, whole tree",
+		inSchema: uexampleoc.SchemaTree["Device"],
+		inRoot:   uncompressed(),
+		want: func() ygot.GoStruct {
+			d := mirrored(uncompressed(), "eth0", "eth1")
+			d.GetOrCreateSystem().GetOrCreateState().Hostname = ygot.String("dev")
+			return d
+		}(),
+	}, {
+		desc:     "uncompressed, single list entry",
+		inSchema: uexampleoc.SchemaTree["Device"],
+		inRoot:   uncompressed(),
+		inPaths:  []*gpb.Path{ygot.MustStringToPath("/interfaces/interface[name=eth1]")},
+		want:     mirrored(uncompressed(), "eth1"),
+	}, {
+		desc:     "uncompressed, wildcard list entries and single leaf",
+		inSchema: uexampleoc.SchemaTree["Device"],
+		inRoot:   uncompressed(),
+		inPaths: []*gpb.Path{
+			ygot.MustStringToPath("/interfaces/interface[name=*]"),
+			ygot.MustStringToPath("/system/config/hostname"),
+		},
+		want: func() ygot.GoStruct {
+			d := mirrored(uncompressed(), "eth0", "eth1")
+			d.GetOrCreateSystem().GetOrCreateState().Hostname = ygot.String("dev")
+			return d
+		}(),
+	}, {
+		desc:     "uncompressed, path to absent subtree",
+		inSchema: uexampleoc.SchemaTree["Device"],
+		inRoot:   uncompressed(),
+		inPaths:  []*gpb.Path{ygot.MustStringToPath("/interfaces/interface[name=eth2]")},
+		want:     uncompressed(),
+	}, {
+		desc:     "compressed, config and state share fields",
+		inSchema: exampleoc.SchemaTree["Device"],
+		inRoot: func() ygot.GoStruct {
+			d := &exampleoc.Device{}
+			d.GetOrCreateInterface("eth0").Mtu = ygot.Uint16(1500)
+			return d
+		}(),
+		want: func() ygot.GoStruct {
+			d := &exampleoc.Device{}
+			d.GetOrCreateInterface("eth0").Mtu = ygot.Uint16(1500)
+			return d
+		}(),
+	}, {
+		desc:             "nil schema",
+		inRoot:           uncompressed(),
+		want:             uncompressed(),
+		wantErrSubstring: "nil schema",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := ytypes.MirrorConfigToState(tt.inSchema, tt.inRoot, tt.inPaths)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("MirrorConfigToState: did not get expected error, %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, tt.inRoot); diff != "" {
+				t.Errorf("MirrorConfigToState: did not get expected root, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
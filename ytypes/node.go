@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/openconfig/ygot/internal/yreflect"
@@ -70,6 +71,23 @@ type retrieveNodeArgs struct {
 	// ignoreExtraFields avoids generating an error when the input path
 	// refers to a field that does not exist in the GoStruct.
 	ignoreExtraFields bool
+	// If visit is set, then retrieveNode calls it for each matched node
+	// instead of returning the matched nodes.
+	visit func(*TreeNode) error
+	// If budget is set, then it limits the number of steps that
+	// retrieveNode takes when traversing the tree. It is shared between
+	// the recursive calls of retrieveNode.
+	budget *traversalBudget
+	// If onListChange is set, then retrieveNode calls it for each entry
+	// that it adds to or removes from a keyed list.
+	onListChange func(*ListEntryChange)
+	// If caseInsensitive is set, then the names of path elements that do
+	// not match the schema are matched case-insensitively.
+	caseInsensitive *CaseInsensitivePathMatch
+	// If replaceGoStruct is set, then a GoStruct val replaces the
+	// container or list entry at the path, rather than being merged into
+	// it.
+	replaceGoStruct bool
 }
 
 // retrieveNode is an internal function that retrieves the node specified by
@@ -78,6 +96,10 @@ type retrieveNodeArgs struct {
 // retrieveNode returns the list of matching nodes and their schemas, and error.
 // Note that retrieveNode may mutate the tree even if it fails.
 func retrieveNode(schema *yang.Entry, root interface{}, path, traversedPath *gpb.Path, args retrieveNodeArgs) ([]*TreeNode, error) {
+	if err := args.budget.step(); err != nil {
+		return nil, err
+	}
+
 	switch {
 	case path == nil || len(path.Elem) == 0:
 		// When args.val is non-nil and the schema isn't nil, further check whether
@@ -87,7 +109,11 @@ func retrieveNode(schema *yang.Entry, root interface{}, path, traversedPath *gpb
 			// When the payload is JSON, however, we are able to unmarshal into the root element.
 			// Note: handling for unmarshalling leaf nodes is done in another location since
 			// we need to know the parent struct of the leaf.
-			if args.val.(*gpb.TypedValue).GetJsonIetfVal() != nil {
+			if gs, ok := args.val.(ygot.GoStruct); ok {
+				if err := args.setGoStruct(schema, root, traversedPath, gs); err != nil {
+					return nil, err
+				}
+			} else if args.val.(*gpb.TypedValue).GetJsonIetfVal() != nil {
 				var jsonTree interface{}
 				if err := json.Unmarshal(args.val.(*gpb.TypedValue).GetJsonIetfVal(), &jsonTree); err != nil {
 					return nil, status.Errorf(codes.Unknown, "failed to update struct %T with value %v; %v", root, args.val, err)
@@ -99,9 +125,16 @@ func retrieveNode(schema *yang.Entry, root interface{}, path, traversedPath *gpb
 				if args.ignoreExtraFields {
 					opts = append(opts, &IgnoreExtraFields{})
 				}
+				before, err := args.listEntries(schema, root, traversedPath)
+				if err != nil {
+					return nil, status.Errorf(codes.Unknown, "failed to update struct %T with value %v; %v", root, args.val, err)
+				}
 				if err := Unmarshal(schema, root, jsonTree, opts...); err != nil {
 					return nil, status.Errorf(codes.Unknown, "failed to update struct %T with value %v; %v", root, args.val, err)
 				}
+				if err := args.notifyAddedListEntries(schema, root, traversedPath, before); err != nil {
+					return nil, status.Errorf(codes.Unknown, "failed to update struct %T with value %v; %v", root, args.val, err)
+				}
 			} else {
 				return nil, status.Errorf(codes.Unknown, "path %v points to a node with non-leaf schema %v", traversedPath, schema)
 			}
@@ -111,16 +144,24 @@ func retrieveNode(schema *yang.Entry, root interface{}, path, traversedPath *gpb
 				return nil, nil
 			}
 			if rt, rv := reflect.TypeOf(root), reflect.ValueOf(root); rt.Kind() == reflect.Pointer && rv.Elem().CanSet() {
+				if err := args.notifyRemovedListEntries(schema, root, traversedPath); err != nil {
+					return nil, err
+				}
 				rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
 			} else {
 				return nil, fmt.Errorf("cannot delete on unsettable element: (%T, %v)", root, root)
 			}
 		}
-		return []*TreeNode{{
+		node := &TreeNode{
 			Path:   traversedPath,
 			Schema: schema,
 			Data:   root,
-		}}, nil
+		}
+		args.budget.match(node)
+		if args.visit != nil {
+			return nil, args.visit(node)
+		}
+		return []*TreeNode{node}, nil
 	case util.IsValueNil(root):
 		if args.delete || args.tolerateNil {
 			// No-op in case of a delete on a field whose value is not populated.
@@ -159,6 +200,9 @@ func retrieveNodeContainer(schema *yang.Entry, root interface{}, path *gpb.Path,
 
 	// dereference reflect value as it points to a pointer.
 	v := rv.Elem()
+	if args.caseInsensitive != nil {
+		path = args.caseInsensitive.foldPath(v.Type(), path)
+	}
 
 	for i := 0; i < v.NumField(); i++ {
 		fv, ft := v.Field(i), v.Type().Field(i)
@@ -173,7 +217,7 @@ func retrieveNodeContainer(schema *yang.Entry, root interface{}, path *gpb.Path,
 			case err != nil:
 				return nil, status.Errorf(codes.Unknown, "failed to get child schema for %T, field %s: %s", root, ft.Name, err)
 			case cschema == nil:
-				return nil, status.Errorf(codes.InvalidArgument, "could not find schema for type %T, field %s", root, ft.Name)
+				return nil, status.Error(codes.InvalidArgument, newFieldSkewError(v.Type(), schema, ft).Error())
 			}
 		}
 
@@ -202,6 +246,9 @@ func retrieveNodeContainer(schema *yang.Entry, root interface{}, path *gpb.Path,
 					return nil, status.Errorf(codes.InvalidArgument, "could not find schema for path %v", np)
 				case !cschema.IsLeaf() && !cschema.IsLeafList():
 					return nil, status.Errorf(codes.InvalidArgument, "shadow path traverses a non-leaf node, this is not allowed, path: %v", np)
+				case args.visit != nil:
+					// There is no data to visit for a shadow leaf.
+					return nil, nil
 				default:
 					return []*TreeNode{{
 						Path: np,
@@ -220,6 +267,9 @@ func retrieveNodeContainer(schema *yang.Entry, root interface{}, path *gpb.Path,
 			// corresponding field to its zero value. The zero value is the unset value for
 			// any node type, whether leaf or non-leaf.
 			if args.delete && len(path.Elem) == to {
+				if err := args.notifyRemovedListEntries(cschema, fv.Interface(), np); err != nil {
+					return nil, err
+				}
 				fv.Set(reflect.Zero(ft.Type))
 				return nil, nil
 			}
@@ -234,6 +284,13 @@ func retrieveNodeContainer(schema *yang.Entry, root interface{}, path *gpb.Path,
 					if err := util.UpdateField(root, ft.Name, args.val); err != nil {
 						return nil, status.Errorf(codes.Unknown, "failed to update struct field %s in %T with value %v, because of %v", ft.Name, root, args.val, err)
 					}
+				case reflect.TypeOf(args.val) == ft.Type && util.IsTypeStructPtr(ft.Type):
+					// A GoStruct value creates the container if it
+					// is not populated, such that it can be merged
+					// into or replaced by retrieveNode.
+					if fv.IsNil() {
+						fv.Set(reflect.New(ft.Type.Elem()))
+					}
 				case cschema.IsLeaf() || cschema.IsLeafList():
 					// With GNMIEncoding, unmarshalGeneric can only unmarshal leaf or leaf list
 					// nodes. Schema provided must be the schema of the leaf or leaf list node.
@@ -299,6 +356,23 @@ func retrieveNodeContainer(schema *yang.Entry, root interface{}, path *gpb.Path,
 			return matches, nil
 		}
 
+		// deleteOrderedMap deletes the ordered map field, whose schema path
+		// is p.
+		deleteOrderedMap := func(p []string) error {
+			np := &gpb.Path{}
+			if traversedPath != nil {
+				np = proto.Clone(traversedPath).(*gpb.Path)
+			}
+			for _, e := range p[:len(p)-1] {
+				np.Elem = append(np.Elem, &gpb.PathElem{Name: e})
+			}
+			if err := args.notifyRemovedListEntries(cschema, fv.Interface(), np); err != nil {
+				return err
+			}
+			fv.Set(reflect.Zero(ft.Type))
+			return nil
+		}
+
 		// Continue traversal on the first-encountered annotated
 		// GoStruct path that forms a prefix of the input path.
 		//
@@ -321,8 +395,7 @@ func retrieveNodeContainer(schema *yang.Entry, root interface{}, path *gpb.Path,
 					// Handle ordered map deletion at the container level in compressed GoStructs.
 					if _, isOrderedMap := fv.Interface().(ygot.GoOrderedMap); isOrderedMap {
 						if args.delete {
-							fv.Set(reflect.Zero(ft.Type))
-							return nil, nil
+							return nil, deleteOrderedMap(p)
 						}
 					}
 				}
@@ -345,8 +418,7 @@ func retrieveNodeContainer(schema *yang.Entry, root interface{}, path *gpb.Path,
 				// Handle ordered map deletion at the container level in compressed GoStructs.
 				if _, isOrderedMap := fv.Interface().(ygot.GoOrderedMap); isOrderedMap {
 					if args.delete {
-						fv.Set(reflect.Zero(ft.Type))
-						return nil, nil
+						return nil, deleteOrderedMap(p)
 					}
 				}
 			}
@@ -479,6 +551,10 @@ func retrieveNodeOrderedList(schema *yang.Entry, root ygot.GoOrderedMap, path, t
 					outerErr = err
 					return false
 				}
+				if err := args.notifyListEntry(schema, traversedPath, keyMap, v.Interface(), true); err != nil {
+					outerErr = err
+					return false
+				}
 				deleteMethod.Call([]reflect.Value{k})
 				return true
 			}
@@ -496,6 +572,10 @@ func retrieveNodeOrderedList(schema *yang.Entry, root ygot.GoOrderedMap, path, t
 					outerErr = err
 					return false
 				}
+				if err := args.notifyListEntry(schema, traversedPath, keyMap, v.Interface(), true); err != nil {
+					outerErr = err
+					return false
+				}
 				deleteMethod.Call([]reflect.Value{k})
 			}
 
@@ -529,6 +609,9 @@ func retrieveNodeOrderedList(schema *yang.Entry, root ygot.GoOrderedMap, path, t
 		if err := ret[1].Interface(); err != nil {
 			return nil, fmt.Errorf("unable to append new ordered map element (this is unexpected since this element should not already exist): %v", err)
 		}
+		if err := args.notifyListEntry(schema, traversedPath, pathKeyVals, ret[0].Interface(), false); err != nil {
+			return nil, err
+		}
 
 		nodes, err := retrieveNode(schema, ret[0].Interface(), util.PopGNMIPath(path), appendElem(traversedPath, path.GetElem()[0]), args)
 		if err != nil {
@@ -628,8 +711,12 @@ func retrieveNodeList(schema *yang.Entry, root interface{}, path, traversedPath
 				return nil, status.Errorf(codes.InvalidArgument, "failed to convert %v to a string, path %v: %v", kv, path, err)
 			}
 			if keyAsString == pathKey {
+				keys := map[string]string{schema.Key: keyAsString}
 				remainingPath := util.PopGNMIPath(path)
 				if args.delete && len(remainingPath.GetElem()) == 0 {
+					if err := args.notifyListEntry(schema, traversedPath, keys, listElemV.Interface(), true); err != nil {
+						return nil, err
+					}
 					rv.SetMapIndex(k, reflect.Value{})
 					return nil, nil
 				}
@@ -641,6 +728,9 @@ func retrieveNodeList(schema *yang.Entry, root interface{}, path, traversedPath
 				// deletion operation is executed, then remove
 				// the map element from the map.
 				if args.delete && listElemV.Elem().IsZero() {
+					if err := args.notifyListEntry(schema, traversedPath, keys, listElemV.Interface(), true); err != nil {
+						return nil, err
+					}
 					rv.SetMapIndex(k, reflect.Value{})
 				}
 				return nodes, nil
@@ -697,6 +787,9 @@ func retrieveNodeList(schema *yang.Entry, root interface{}, path, traversedPath
 			}
 			remainingPath := util.PopGNMIPath(path)
 			if args.delete && len(remainingPath.GetElem()) == 0 {
+				if err := args.notifyListEntry(schema, traversedPath, keys, listElemV.Interface(), true); err != nil {
+					return nil, err
+				}
 				rv.SetMapIndex(k, reflect.Value{})
 				return nil, nil
 			}
@@ -708,6 +801,9 @@ func retrieveNodeList(schema *yang.Entry, root interface{}, path, traversedPath
 			// deletion operation is executed, then remove
 			// the map element from the map.
 			if args.delete && listElemV.Elem().IsZero() {
+				if err := args.notifyListEntry(schema, traversedPath, keys, listElemV.Interface(), true); err != nil {
+					return nil, err
+				}
 				rv.SetMapIndex(k, reflect.Value{})
 			}
 
@@ -722,6 +818,14 @@ func retrieveNodeList(schema *yang.Entry, root interface{}, path, traversedPath
 		if err != nil {
 			return nil, err
 		}
+		kv := reflect.ValueOf(key)
+		keys, err := getKeyFields(kv, rv.MapIndex(kv), schema.Key)
+		if err != nil {
+			return nil, err
+		}
+		if err := args.notifyListEntry(schema, traversedPath, keys, rv.MapIndex(kv).Interface(), false); err != nil {
+			return nil, err
+		}
 		nodes, err := retrieveNode(schema, rv.MapIndex(reflect.ValueOf(key)).Interface(), util.PopGNMIPath(path), appendElem(traversedPath, path.GetElem()[0]), args)
 		if err != nil {
 			return nil, err
@@ -771,20 +875,107 @@ type TreeNode struct {
 	Data interface{}
 	// Path is the path of the data node that is being returned.
 	Path *gpb.Path
+	// Metadata describes the data tree node using its schema. It is only
+	// populated when the GetSchemaMetadata option is supplied.
+	Metadata *TreeNodeMetadata
+}
+
+// TreeNodeMetadata describes a data tree node using its schema, such that
+// callers can handle the node without inspecting its schema.
+type TreeNodeMetadata struct {
+	// IsConfig indicates whether the node is configuration, rather than
+	// state, data.
+	IsConfig bool
+	// YANGTypeName is the name of the YANG type of a leaf or leaf-list,
+	// which is the name of its typedef if it has one. It is empty for
+	// other nodes.
+	YANGTypeName string
+	// Defaults are the default values of a leaf or leaf-list, taken from
+	// its type if it has no default of its own.
+	Defaults []string
+}
+
+// newTreeNodeMetadata returns the TreeNodeMetadata for a node with the
+// supplied schema.
+func newTreeNodeMetadata(schema *yang.Entry) *TreeNodeMetadata {
+	if schema == nil {
+		return nil
+	}
+	md := &TreeNodeMetadata{IsConfig: util.IsConfig(schema)}
+	if schema.IsLeaf() || schema.IsLeafList() {
+		if schema.Type != nil {
+			md.YANGTypeName = schema.Type.Name
+		}
+		md.Defaults = schema.DefaultValues()
+	}
+	return md
+}
+
+// populateMetadata populates the Metadata of the supplied nodes if the
+// GetSchemaMetadata option is within opts.
+func populateMetadata(nodes []*TreeNode, opts []GetNodeOpt) {
+	if !hasGetSchemaMetadata(opts) {
+		return
+	}
+	for _, n := range nodes {
+		n.Metadata = newTreeNodeMetadata(n.Schema)
+	}
 }
 
 // GetNode retrieves the node specified by the supplied path from the specified root, whose schema must
 // also be supplied. It takes a set of options which can be used to specify get behaviours, such as
 // allowing partial match. If there are no matches for the path, an error is returned.
+//
+// If a GetTraversalBudget option is supplied and the traversal exceeds it, a
+// *TraversalBudgetExceededError is returned.
 func GetNode(schema *yang.Entry, root interface{}, path *gpb.Path, opts ...GetNodeOpt) ([]*TreeNode, error) {
-	return retrieveNode(schema, root, path, nil, retrieveNodeArgs{
+	budget := getTraversalBudget(opts)
+	nodes, err := retrieveNode(schema, root, path, nil, retrieveNodeArgs{
 		// We never want to modify the input root, so we specify modifyRoot.
 		modifyRoot:       false,
 		partialKeyMatch:  hasPartialKeyMatch(opts),
 		handleWildcards:  hasHandleWildcards(opts),
 		tolerateNil:      hasGetTolerateNil(opts),
 		preferShadowPath: hasGetNodePreferShadowPath(opts),
+		budget:           budget,
+		caseInsensitive:  getNodeCaseInsensitivePathMatch(opts),
 	})
+	if budget.exceeded() {
+		partial := budget.partialResults()
+		populateMetadata(partial, opts)
+		return partial, budget.err()
+	}
+	populateMetadata(nodes, opts)
+	return nodes, err
+}
+
+// GetGoStruct retrieves the GoStruct at the path described by the supplied
+// path struct from the specified root, whose schema must also be supplied. The
+// path struct must be linked to its GoStruct type T by ypathgen, such that the
+// retrieved node is returned as a value of type T. The options are handled in
+// the same way as by GetNode. If the path does not match exactly one node, or
+// the node is not of type T, an error is returned.
+func GetGoStruct[T ygot.GoStruct](schema *yang.Entry, root interface{}, p ygot.GoStructPath[T], opts ...GetNodeOpt) (T, error) {
+	var zero T
+	path, _, errs := ygot.ResolvePath(p)
+	if errs != nil {
+		return zero, status.Errorf(codes.InvalidArgument, "cannot resolve path struct %T: %v", p, util.Errors(errs))
+	}
+	nodes, err := GetNode(schema, root, path, opts...)
+	if err != nil {
+		return zero, err
+	}
+	switch {
+	case len(nodes) == 0:
+		return zero, status.Errorf(codes.NotFound, "path %v matched no nodes", path)
+	case len(nodes) > 1:
+		return zero, status.Errorf(codes.InvalidArgument, "path %v matched %d nodes, want exactly one", path, len(nodes))
+	}
+	v, ok := nodes[0].Data.(T)
+	if !ok {
+		return zero, status.Errorf(codes.InvalidArgument, "node at path %v has type %T, want %T", path, nodes[0].Data, zero)
+	}
+	return v, nil
 }
 
 // GetNodeOpt defines an interface that can be used to supply arguments to functions using GetNode.
@@ -829,6 +1020,107 @@ func hasHandleWildcards(opts []GetNodeOpt) bool {
 	return false
 }
 
+// GetTraversalBudget specifies the maximum number of steps that GetNode may
+// take when traversing the data tree, such that a query that matches a large
+// part of the tree, e.g., using wildcards, cannot consume an unbounded amount
+// of CPU. A step is taken for each node of the data tree that is visited,
+// such that the retrieval of a single node takes one step more than the
+// number of elements in its path.
+type GetTraversalBudget struct {
+	// MaxSteps is the maximum number of steps that may be taken.
+	MaxSteps int
+	// PartialResults specifies that the nodes that were matched before
+	// the budget was exceeded should be returned along with the error.
+	PartialResults bool
+}
+
+// IsGetNodeOpt implements the GetNodeOpt interface.
+func (*GetTraversalBudget) IsGetNodeOpt() {}
+
+// getTraversalBudget returns a traversalBudget for the first instance of
+// GetTraversalBudget within the supplied GetNodeOpt slice, or nil if there is
+// none.
+func getTraversalBudget(opts []GetNodeOpt) *traversalBudget {
+	for _, o := range opts {
+		if b, ok := o.(*GetTraversalBudget); ok {
+			return &traversalBudget{max: b.MaxSteps, partial: b.PartialResults}
+		}
+	}
+	return nil
+}
+
+// TraversalBudgetExceededError is the error returned when the traversal of a
+// data tree exceeds the budget specified by GetTraversalBudget.
+type TraversalBudgetExceededError struct {
+	// MaxSteps is the budget that was exceeded.
+	MaxSteps int
+}
+
+// Error implements the error interface.
+func (e *TraversalBudgetExceededError) Error() string {
+	return fmt.Sprintf("traversal exceeded the budget of %d steps", e.MaxSteps)
+}
+
+// GRPCStatus returns the gRPC status corresponding to the error, such that
+// it is returned with the ResourceExhausted code by gRPC servers.
+func (e *TraversalBudgetExceededError) GRPCStatus() *status.Status {
+	return status.New(codes.ResourceExhausted, e.Error())
+}
+
+// traversalBudget tracks the number of steps taken by retrieveNode against
+// a budget. All methods can be called on a nil traversalBudget, which
+// represents an unlimited budget.
+type traversalBudget struct {
+	// max is the maximum number of steps that may be taken.
+	max int
+	// steps is the number of steps that have been taken.
+	steps int
+	// partial specifies whether matched nodes are recorded, such that they
+	// can be returned if the budget is exceeded.
+	partial bool
+	// matched stores the nodes that were matched when partial is set.
+	matched []*TreeNode
+}
+
+// step records that a step was taken, and returns an error if the budget was
+// exceeded.
+func (b *traversalBudget) step() error {
+	if b == nil {
+		return nil
+	}
+	b.steps++
+	if b.exceeded() {
+		return b.err()
+	}
+	return nil
+}
+
+// match records that node was matched.
+func (b *traversalBudget) match(node *TreeNode) {
+	if b != nil && b.partial {
+		b.matched = append(b.matched, node)
+	}
+}
+
+// exceeded returns true if the budget was exceeded.
+func (b *traversalBudget) exceeded() bool {
+	return b != nil && b.steps > b.max
+}
+
+// partialResults returns the nodes matched before the budget was exceeded,
+// if they are to be returned.
+func (b *traversalBudget) partialResults() []*TreeNode {
+	if b == nil || !b.partial {
+		return nil
+	}
+	return b.matched
+}
+
+// err returns the error indicating that the budget was exceeded.
+func (b *traversalBudget) err() error {
+	return &TraversalBudgetExceededError{MaxSteps: b.max}
+}
+
 // GetTolerateNil specifies that a match within GetNode should not return an
 // error if a nil object is hit during path traversal with remaining path
 // elements, and should instead simply return an empty set of nodes.
@@ -848,6 +1140,128 @@ func hasGetTolerateNil(opts []GetNodeOpt) bool {
 	return false
 }
 
+// GetSchemaMetadata specifies that GetNode should populate the Metadata of
+// each returned TreeNode from its schema.
+type GetSchemaMetadata struct{}
+
+// IsGetNodeOpt implements the GetNodeOpt interface.
+func (*GetSchemaMetadata) IsGetNodeOpt() {}
+
+// hasGetSchemaMetadata determines whether there is an instance of
+// GetSchemaMetadata within the supplied GetNodeOpt slice.
+func hasGetSchemaMetadata(opts []GetNodeOpt) bool {
+	for _, o := range opts {
+		if _, ok := o.(*GetSchemaMetadata); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CaseInsensitivePathMatch specifies that the names of the elements of the
+// path supplied to GetNode or SetNode that do not match the schema are matched
+// case-insensitively, e.g., such that a path emitted by a device as
+// /Interfaces/Interface[name=eth0]/Config/MTU matches
+// /interfaces/interface[name=eth0]/config/mtu. Exact matches are always
+// preferred. The names of list keys are matched exactly. The paths of the
+// returned nodes use the names within the schema.
+type CaseInsensitivePathMatch struct {
+	// Warn, if set, is called for each path element whose name matched
+	// the schema only case-insensitively, with its name and the name
+	// within the schema.
+	Warn func(name, schemaName string)
+}
+
+// IsGetNodeOpt implements the GetNodeOpt interface.
+func (*CaseInsensitivePathMatch) IsGetNodeOpt() {}
+
+// IsSetNodeOpt implements the SetNodeOpt interface.
+func (*CaseInsensitivePathMatch) IsSetNodeOpt() {}
+
+// getNodeCaseInsensitivePathMatch returns the first instance of
+// CaseInsensitivePathMatch within the supplied GetNodeOpt slice, or nil if
+// there is none.
+func getNodeCaseInsensitivePathMatch(opts []GetNodeOpt) *CaseInsensitivePathMatch {
+	for _, o := range opts {
+		if c, ok := o.(*CaseInsensitivePathMatch); ok {
+			return c
+		}
+	}
+	return nil
+}
+
+// setNodeCaseInsensitivePathMatch returns the first instance of
+// CaseInsensitivePathMatch within the supplied SetNodeOpt slice, or nil if
+// there is none.
+func setNodeCaseInsensitivePathMatch(opts []SetNodeOpt) *CaseInsensitivePathMatch {
+	for _, o := range opts {
+		if c, ok := o.(*CaseInsensitivePathMatch); ok {
+			return c
+		}
+	}
+	return nil
+}
+
+// foldPath returns path with the names of its leading elements replaced by
+// the schema path of the first field of the struct type t whose schema path
+// matches them case-insensitively. path is returned unchanged if the schema
+// path of any field of t matches it exactly, or if there is no
+// case-insensitive match. path itself is not modified.
+func (c *CaseInsensitivePathMatch) foldPath(t reflect.Type, path *gpb.Path) *gpb.Path {
+	var candidates [][]string
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i)
+		schPaths, err := util.SchemaPaths(ft)
+		if err != nil {
+			// Errors are reported when the path is matched.
+			return path
+		}
+		for _, p := range append(schPaths, util.ShadowSchemaPaths(ft)...) {
+			if util.PathMatchesPrefix(path, p) {
+				return path
+			}
+			candidates = append(candidates, p)
+		}
+	}
+
+	for _, p := range candidates {
+		if !pathMatchesPrefixFold(path, p) {
+			continue
+		}
+		np := &gpb.Path{Origin: path.GetOrigin(), Target: path.GetTarget(), Elem: append([]*gpb.PathElem{}, path.GetElem()...)}
+		for i, name := range p {
+			if name == "" || np.Elem[i].GetName() == name {
+				continue
+			}
+			if c.Warn != nil {
+				c.Warn(np.Elem[i].GetName(), name)
+			}
+			e := proto.Clone(np.Elem[i]).(*gpb.PathElem)
+			e.Name = name
+			np.Elem[i] = e
+		}
+		return np
+	}
+	return path
+}
+
+// pathMatchesPrefixFold reports whether the names of the leading elements of
+// path are equal to the elements of prefix under Unicode case-folding.
+func pathMatchesPrefixFold(path *gpb.Path, prefix []string) bool {
+	for len(prefix) != 0 && prefix[len(prefix)-1] == "" {
+		prefix = prefix[:len(prefix)-1]
+	}
+	if len(path.GetElem()) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if !strings.EqualFold(prefix[i], path.GetElem()[i].GetName()) {
+			return false
+		}
+	}
+	return true
+}
+
 // appendElem adds the element e to the path p and returns the resulting
 // path.
 func appendElem(p *gpb.Path, e *gpb.PathElem) *gpb.Path {
@@ -864,6 +1278,15 @@ func appendElem(p *gpb.Path, e *gpb.PathElem) *gpb.Path {
 // behaviours, such as whether or not to ensure that the node's ancestors are initialized.
 // Note that SetNode does not do a full validation -- e.g., it does not do the string
 // regex restriction validation done by ytypes.Validate().
+//
+// val is a *gpb.TypedValue, or, where the path specifies a container or a
+// list entry, a GoStruct of the same type as the node. A GoStruct is merged
+// into the node, such that the fields that are populated within it overwrite
+// those of the node, unless the ReplaceGoStruct option is supplied, in which
+// case the node is replaced by a copy of it. A container is created if it is
+// not populated, whereas a list entry must exist, or the InitMissingElements
+// option must be supplied. The keys of a list entry must match those of the
+// path.
 func SetNode(schema *yang.Entry, root interface{}, path *gpb.Path, val interface{}, opts ...SetNodeOpt) error {
 	nodes, err := retrieveNode(schema, root, path, nil, retrieveNodeArgs{
 		modifyRoot:                        hasInitMissingElements(opts),
@@ -871,6 +1294,9 @@ func SetNode(schema *yang.Entry, root interface{}, path *gpb.Path, val interface
 		tolerateJSONInconsistenciesForVal: hasTolerateJSONInconsistencies(opts),
 		preferShadowPath:                  hasSetNodePreferShadowPath(opts),
 		ignoreExtraFields:                 hasIgnoreExtraFieldsSetNode(opts),
+		onListChange:                      setNodeListEntryCallback(opts),
+		caseInsensitive:                   setNodeCaseInsensitivePathMatch(opts),
+		replaceGoStruct:                   hasReplaceGoStruct(opts),
 	})
 
 	if err != nil {
@@ -941,6 +1367,100 @@ func hasTolerateJSONInconsistencies(opts []SetNodeOpt) bool {
 	return false
 }
 
+// ReplaceGoStruct signals SetNode to replace the container or list entry
+// specified by the path with a copy of the supplied GoStruct value, rather than
+// merging the value into it. It has no effect for other values.
+type ReplaceGoStruct struct{}
+
+// IsSetNodeOpt implements the SetNodeOpt interface.
+func (*ReplaceGoStruct) IsSetNodeOpt() {}
+
+// hasReplaceGoStruct determines whether there is an instance of
+// ReplaceGoStruct within the supplied SetNodeOpt slice.
+func hasReplaceGoStruct(opts []SetNodeOpt) bool {
+	for _, o := range opts {
+		if _, ok := o.(*ReplaceGoStruct); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// setGoStruct sets the container or list entry root, which is described by
+// schema and is at path, to val, which must be a GoStruct of the same type.
+// val is merged into root, such that the fields that are populated in val
+// overwrite those of root, unless args.replaceGoStruct is set, in which case
+// root is replaced by a copy of val.
+func (args retrieveNodeArgs) setGoStruct(schema *yang.Entry, root interface{}, path *gpb.Path, val ygot.GoStruct) error {
+	if reflect.TypeOf(root) != reflect.TypeOf(val) {
+		return status.Errorf(codes.InvalidArgument, "cannot set node at path %v of type %T to value of type %T", path, root, val)
+	}
+	if util.IsValueNil(root) || util.IsValueNil(val) {
+		return status.Errorf(codes.InvalidArgument, "cannot set node at path %v of type %T to nil value", path, root)
+	}
+	if schema.IsList() {
+		if err := checkListKeys(schema, reflect.ValueOf(root).Elem(), reflect.ValueOf(val).Elem()); err != nil {
+			return status.Errorf(codes.InvalidArgument, "cannot set list entry at path %v: %v", path, err)
+		}
+	}
+
+	before, err := args.listEntries(schema, root, path)
+	if err != nil {
+		return status.Errorf(codes.Unknown, "failed to update struct %T with value %v; %v", root, val, err)
+	}
+	if args.replaceGoStruct {
+		cp, err := ygot.DeepCopy(val)
+		if err != nil {
+			return status.Errorf(codes.Unknown, "failed to copy value %T; %v", val, err)
+		}
+		if err := args.notifyRemovedListEntries(schema, root, path); err != nil {
+			return err
+		}
+		reflect.ValueOf(root).Elem().Set(reflect.ValueOf(cp).Elem())
+		before = nil
+	} else if err := ygot.MergeStructInto(root.(ygot.GoStruct), val, &ygot.MergeOverwriteExistingFields{}); err != nil {
+		return status.Errorf(codes.Unknown, "failed to merge value %T into struct at path %v; %v", val, path, err)
+	}
+	if err := args.notifyAddedListEntries(schema, root, path, before); err != nil {
+		return status.Errorf(codes.Unknown, "failed to update struct %T with value %v; %v", root, val, err)
+	}
+	return nil
+}
+
+// checkListKeys returns an error if the key leaves of the list entry structs
+// a and b, which are described by schema, are not equal.
+func checkListKeys(schema *yang.Entry, a, b reflect.Value) error {
+	keys := map[string]bool{}
+	for _, k := range strings.Fields(schema.Key) {
+		keys[k] = true
+	}
+	for i := 0; i < a.NumField(); i++ {
+		ft := a.Type().Field(i)
+		if util.IsYgotAnnotation(ft) {
+			continue
+		}
+		paths, err := util.SchemaPaths(ft)
+		if err != nil {
+			return err
+		}
+		for _, p := range paths {
+			if len(p) == 1 && keys[p[0]] && !reflect.DeepEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+				return fmt.Errorf("key %s of value %v does not match %v", p[0], keyValue(b.Field(i)), keyValue(a.Field(i)))
+			}
+		}
+	}
+	return nil
+}
+
+// keyValue returns the value of the key leaf field v, dereferencing it if it
+// is a non-nil pointer.
+func keyValue(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		return v.Elem().Interface()
+	}
+	return v.Interface()
+}
+
 // DelNodeOpt defines an interface that can be used to supply arguments to functions using DeleteNode.
 type DelNodeOpt interface {
 	// IsDelNodeOpt is a marker method that is used to identify an instance of DelNodeOpt.
@@ -1017,6 +1537,79 @@ func hasDelNodePreferShadowPath(opts []DelNodeOpt) bool {
 	return false
 }
 
+// ListEntryChange describes an entry that was added to or removed from a
+// keyed list by SetNode or DeleteNode.
+type ListEntryChange struct {
+	// Path is the path of the list, relative to the root supplied to
+	// SetNode or DeleteNode. It does not contain the keys of the entry.
+	Path *gpb.Path
+	// Keys are the values of the keys of the entry, keyed by the name of
+	// the key leaf.
+	Keys map[string]string
+	// Schema is the schema of the list.
+	Schema *yang.Entry
+	// Deleted is set to true if the entry was removed from the list, and
+	// false if it was added to the list.
+	Deleted bool
+}
+
+// ListEntryCallback signals SetNode and DeleteNode to call Fn for each entry
+// that they add to or remove from a keyed list. This allows indices that are
+// maintained outside of the GoStruct, e.g. a map of interface names to
+// ifindex values, to be kept consistent with it.
+//
+// When a node that contains lists is removed, Fn is called for each entry of
+// the lists within it, and when a node is set using a JSON value, Fn is called
+// for each entry that the value adds. Fn is called for an entry before it is
+// removed, and after it is added. If more than one ListEntryCallback is
+// supplied, then each is called in the order supplied.
+type ListEntryCallback struct {
+	Fn func(*ListEntryChange)
+}
+
+// IsSetNodeOpt implements the SetNodeOpt interface.
+func (*ListEntryCallback) IsSetNodeOpt() {}
+
+// IsDelNodeOpt implements the DelNodeOpt interface.
+func (*ListEntryCallback) IsDelNodeOpt() {}
+
+// listEntryCallback returns a function that calls each of the supplied
+// ListEntryCallbacks, or nil if none are supplied.
+func listEntryCallback(cbs []*ListEntryCallback) func(*ListEntryChange) {
+	if len(cbs) == 0 {
+		return nil
+	}
+	return func(c *ListEntryChange) {
+		for _, cb := range cbs {
+			cb.Fn(c)
+		}
+	}
+}
+
+// setNodeListEntryCallback returns a function that calls each of the
+// ListEntryCallbacks within the supplied SetNodeOpt slice.
+func setNodeListEntryCallback(opts []SetNodeOpt) func(*ListEntryChange) {
+	var cbs []*ListEntryCallback
+	for _, o := range opts {
+		if cb, ok := o.(*ListEntryCallback); ok && cb.Fn != nil {
+			cbs = append(cbs, cb)
+		}
+	}
+	return listEntryCallback(cbs)
+}
+
+// delNodeListEntryCallback returns a function that calls each of the
+// ListEntryCallbacks within the supplied DelNodeOpt slice.
+func delNodeListEntryCallback(opts []DelNodeOpt) func(*ListEntryChange) {
+	var cbs []*ListEntryCallback
+	for _, o := range opts {
+		if cb, ok := o.(*ListEntryCallback); ok && cb.Fn != nil {
+			cbs = append(cbs, cb)
+		}
+	}
+	return listEntryCallback(cbs)
+}
+
 // DeleteNode zeroes the value of the node specified by the supplied path from
 // the specified root, whose schema must also be supplied. If the node
 // specified by that path is already its zero value, or an intermediate node
@@ -1030,7 +1623,362 @@ func DeleteNode(schema *yang.Entry, root interface{}, path *gpb.Path, opts ...De
 	_, err := retrieveNode(schema, root, path, nil, retrieveNodeArgs{
 		delete:           true,
 		preferShadowPath: hasDelNodePreferShadowPath(opts),
+		onListChange:     delNodeListEntryCallback(opts),
 	})
 
 	return err
 }
+
+// DeleteNodes deletes the nodes specified by the supplied path from the
+// specified root, whose schema must also be supplied. Unlike DeleteNode, the
+// path may contain wildcards: a key value of "*" matches all entries of a
+// list, as per GetNode with the GetHandleWildcards option, and a path element
+// name of "*" matches any single path element of a child of the GoStruct at
+// that point in the path. Paths that a name wildcard expands to which do not
+// correspond to a node in the GoStruct are ignored.
+//
+// DeleteNodes returns the number of populated nodes that were deleted. The
+// semantics of each deletion are the same as those of DeleteNode.
+func DeleteNodes(schema *yang.Entry, root interface{}, path *gpb.Path, opts ...DelNodeOpt) (int, error) {
+	if !hasWildcards(path) {
+		var n int
+		if nodes, err := GetNode(schema, root, path, getNodeOptsForDelete(opts)...); err == nil {
+			n = countPopulated(nodes)
+		}
+		if err := DeleteNode(schema, root, path, opts...); err != nil {
+			return 0, err
+		}
+		return n, nil
+	}
+
+	nodes, err := expandWildcardPath(schema, root, path, &gpb.Path{}, opts)
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	for _, node := range nodes {
+		if util.IsValueNil(node.Data) {
+			continue
+		}
+		if err := DeleteNode(schema, root, node.Path, opts...); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// hasWildcards reports whether the supplied path contains a wildcard path
+// element name or key value.
+func hasWildcards(path *gpb.Path) bool {
+	for _, e := range path.GetElem() {
+		if e.GetName() == "*" || e.GetName() == "..." {
+			return true
+		}
+		for _, v := range e.GetKey() {
+			if v == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// countPopulated returns the number of nodes whose data is not nil.
+func countPopulated(nodes []*TreeNode) int {
+	var n int
+	for _, node := range nodes {
+		if !util.IsValueNil(node.Data) {
+			n++
+		}
+	}
+	return n
+}
+
+// getNodeOptsForDelete returns the GetNode options that correspond to the
+// supplied DeleteNode options, such that the nodes retrieved are those that
+// would be deleted.
+func getNodeOptsForDelete(opts []DelNodeOpt) []GetNodeOpt {
+	getOpts := []GetNodeOpt{&GetHandleWildcards{}, &GetTolerateNil{}}
+	if hasDelNodePreferShadowPath(opts) {
+		getOpts = append(getOpts, &PreferShadowPath{})
+	}
+	return getOpts
+}
+
+// expandWildcardPath returns the nodes within root that match the supplied
+// path, which may contain wildcards. The path is relative to root, and
+// prefix is the path at which root resides within the tree described by
+// schema. The returned nodes contain the concrete paths of the matched nodes.
+func expandWildcardPath(schema *yang.Entry, root interface{}, path, prefix *gpb.Path, opts []DelNodeOpt) ([]*TreeNode, error) {
+	hasNameWildcard := false
+	for _, e := range path.GetElem() {
+		switch e.GetName() {
+		case "...":
+			return nil, status.Errorf(codes.Unimplemented, "multi-level wildcards are not supported, path %v", path)
+		case "*":
+			hasNameWildcard = true
+		}
+	}
+
+	withPrefix := func(nodes []*TreeNode) []*TreeNode {
+		for _, node := range nodes {
+			np := proto.Clone(prefix).(*gpb.Path)
+			np.Elem = append(np.Elem, node.Path.GetElem()...)
+			node.Path = np
+		}
+		return nodes
+	}
+
+	if !hasNameWildcard {
+		nodes, err := GetNode(schema, root, path, getNodeOptsForDelete(opts)...)
+		if err != nil {
+			return nil, err
+		}
+		return withPrefix(nodes), nil
+	}
+
+	rv := reflect.ValueOf(root)
+	if util.IsValueNil(root) || !util.IsValueStructPtr(rv) {
+		return nil, nil
+	}
+
+	// Match the path against the path tags of each field of the GoStruct,
+	// replacing name wildcards with the names of the path elements in the
+	// tag. The matched nodes are then traversed with the remainder of the
+	// path.
+	var matches []*TreeNode
+	seen := map[string]bool{}
+	t := rv.Elem().Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if util.IsYgotAnnotation(f) {
+			continue
+		}
+		schPaths, err := util.SchemaPaths(f)
+		if err != nil {
+			return nil, status.Errorf(codes.Unknown, "failed to get schema paths for %T, field %s: %s", root, f.Name, err)
+		}
+		if hasDelNodePreferShadowPath(opts) {
+			schPaths = append(schPaths, util.ShadowSchemaPaths(f)...)
+		}
+		for _, p := range schPaths {
+			head, ok := matchWildcardNames(path, p)
+			if !ok {
+				continue
+			}
+			nodes, err := GetNode(schema, root, head, getNodeOptsForDelete(opts)...)
+			if err != nil {
+				// The path cannot be traversed using this tag,
+				// e.g., since it refers to a list without
+				// specifying its keys.
+				continue
+			}
+			rest := &gpb.Path{Elem: path.GetElem()[len(head.GetElem()):]}
+			for _, node := range withPrefix(nodes) {
+				children := []*TreeNode{node}
+				if len(rest.GetElem()) != 0 {
+					if children, err = expandWildcardPath(node.Schema, node.Data, rest, node.Path, opts); err != nil {
+						// The remainder of the path does not
+						// correspond to a node within the child.
+						continue
+					}
+				}
+				for _, c := range children {
+					ps, err := ygot.PathToString(c.Path)
+					if err != nil {
+						return nil, err
+					}
+					if !seen[ps] {
+						seen[ps] = true
+						matches = append(matches, c)
+					}
+				}
+			}
+		}
+	}
+	return matches, nil
+}
+
+// matchWildcardNames reports whether the names of the path elements of the
+// supplied path tag p form a prefix of path, treating "*" as a name that
+// matches any single path element. If they do, it returns the matched prefix
+// of path with the names of p substituted for the wildcard names.
+func matchWildcardNames(path *gpb.Path, p []string) (*gpb.Path, bool) {
+	for len(p) != 0 && p[len(p)-1] == "" {
+		p = p[:len(p)-1]
+	}
+	if len(p) == 0 || len(path.GetElem()) < len(p) {
+		return nil, false
+	}
+	head := &gpb.Path{}
+	for i, name := range p {
+		e := path.GetElem()[i]
+		if e.GetName() != "*" && e.GetName() != name {
+			return nil, false
+		}
+		head.Elem = append(head.Elem, &gpb.PathElem{Name: name, Key: e.GetKey()})
+	}
+	return head, true
+}
+
+// visitListEntries calls visit for each entry of a keyed list within value,
+// which is described by schema, in the same manner as retrieveNode: if value
+// is a list, then path is the path of its parent, otherwise path is the path
+// of value.
+func visitListEntries(schema *yang.Entry, value interface{}, path *gpb.Path, visit func(*ListEntryChange)) error {
+	if schema == nil || util.IsValueNil(value) {
+		return nil
+	}
+	rv := reflect.ValueOf(value)
+
+	visitEntry := func(k, v reflect.Value) error {
+		keys, err := getKeyFields(k, v, schema.Key)
+		if err != nil {
+			return err
+		}
+		visit(&ListEntryChange{
+			Path:   appendElem(path, &gpb.PathElem{Name: schema.Name}),
+			Keys:   keys,
+			Schema: schema,
+		})
+		return visitListEntries(schema, v.Interface(), appendElem(path, &gpb.PathElem{Name: schema.Name, Key: keys}), visit)
+	}
+
+	if orderedMap, ok := value.(ygot.GoOrderedMap); ok {
+		var outerErr error
+		if err := yreflect.RangeOrderedMap(orderedMap, func(k, v reflect.Value) bool {
+			outerErr = visitEntry(k, v)
+			return outerErr == nil
+		}); err != nil {
+			return err
+		}
+		return outerErr
+	}
+
+	switch {
+	case util.IsValueMap(rv):
+		for _, k := range rv.MapKeys() {
+			if err := visitEntry(k, rv.MapIndex(k)); err != nil {
+				return err
+			}
+		}
+	case util.IsTypeStructPtr(rv.Type()):
+		v := rv.Elem()
+		for i := 0; i < v.NumField(); i++ {
+			fv, ft := v.Field(i), v.Type().Field(i)
+			if util.IsYgotAnnotation(ft) || util.IsValueNil(fv.Interface()) {
+				continue
+			}
+			cschema, err := util.ChildSchema(schema, ft)
+			if err != nil {
+				return err
+			}
+			if cschema == nil || cschema.IsLeaf() || cschema.IsLeafList() {
+				continue
+			}
+			schPaths, err := util.SchemaPaths(ft)
+			if err != nil {
+				return err
+			}
+			p := schPaths[0]
+			if _, isOrderedMap := fv.Interface().(ygot.GoOrderedMap); util.IsTypeMap(ft.Type) || isOrderedMap {
+				p = p[:len(p)-1]
+			}
+			np := &gpb.Path{}
+			if path != nil {
+				np = proto.Clone(path).(*gpb.Path)
+			}
+			for _, e := range p {
+				np.Elem = append(np.Elem, &gpb.PathElem{Name: e})
+			}
+			if err := visitListEntries(cschema, fv.Interface(), np, visit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// notifyListEntry calls args.onListChange for the entry with the supplied
+// keys of the list described by schema, whose parent is at path, and for each
+// entry of the lists within it.
+func (args retrieveNodeArgs) notifyListEntry(schema *yang.Entry, path *gpb.Path, keys map[string]string, entry interface{}, deleted bool) error {
+	if args.onListChange == nil {
+		return nil
+	}
+	args.onListChange(&ListEntryChange{
+		Path:    appendElem(path, &gpb.PathElem{Name: schema.Name}),
+		Keys:    keys,
+		Schema:  schema,
+		Deleted: deleted,
+	})
+	return visitListEntries(schema, entry, appendElem(path, &gpb.PathElem{Name: schema.Name, Key: keys}), func(c *ListEntryChange) {
+		c.Deleted = deleted
+		args.onListChange(c)
+	})
+}
+
+// notifyRemovedListEntries calls args.onListChange for each entry of the lists
+// within value, which is described by schema and is about to be removed. path
+// is as per visitListEntries.
+func (args retrieveNodeArgs) notifyRemovedListEntries(schema *yang.Entry, value interface{}, path *gpb.Path) error {
+	if args.onListChange == nil {
+		return nil
+	}
+	return visitListEntries(schema, value, path, func(c *ListEntryChange) {
+		c.Deleted = true
+		args.onListChange(c)
+	})
+}
+
+// listEntries returns the entries of the lists within value, keyed by the
+// string form of their path, if args.onListChange is set. value, schema and
+// path are as per visitListEntries.
+func (args retrieveNodeArgs) listEntries(schema *yang.Entry, value interface{}, path *gpb.Path) (map[string]bool, error) {
+	if args.onListChange == nil {
+		return nil, nil
+	}
+	entries := map[string]bool{}
+	var outerErr error
+	if err := visitListEntries(schema, value, path, func(c *ListEntryChange) {
+		k, err := listEntryKey(c)
+		if err != nil {
+			outerErr = err
+			return
+		}
+		entries[k] = true
+	}); err != nil {
+		return nil, err
+	}
+	return entries, outerErr
+}
+
+// notifyAddedListEntries calls args.onListChange for each entry of the lists
+// within value that is not in before, as returned by listEntries.
+func (args retrieveNodeArgs) notifyAddedListEntries(schema *yang.Entry, value interface{}, path *gpb.Path, before map[string]bool) error {
+	if args.onListChange == nil {
+		return nil
+	}
+	var outerErr error
+	if err := visitListEntries(schema, value, path, func(c *ListEntryChange) {
+		k, err := listEntryKey(c)
+		switch {
+		case err != nil:
+			outerErr = err
+		case !before[k]:
+			args.onListChange(c)
+		}
+	}); err != nil {
+		return err
+	}
+	return outerErr
+}
+
+// listEntryKey returns the string form of the path of the list entry
+// described by c, including its keys.
+func listEntryKey(c *ListEntryChange) (string, error) {
+	p := proto.Clone(c.Path).(*gpb.Path)
+	p.Elem[len(p.Elem)-1].Key = c.Keys
+	return ygot.PathToString(p)
+}
@@ -15,11 +15,13 @@
 package ytypes_test
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/openconfig/gnmi/errdiff"
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/openconfig/goyang/pkg/yang"
@@ -28,6 +30,8 @@ import (
 	"github.com/openconfig/ygot/internal/ytestutil"
 	"github.com/openconfig/ygot/ygot"
 	"github.com/openconfig/ygot/ytypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
 )
@@ -1188,3 +1192,567 @@ func TestDeleteNodeOrderedMap(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteNodes(t *testing.T) {
+	newDevice := func() *ctestschema.Device {
+		return &ctestschema.Device{
+			OrderedList: ctestschema.GetOrderedMap(t),
+			UnorderedList: map[string]*ctestschema.UnorderedList{
+				"one": {Key: ygot.String("one"), Value: ygot.String("one-val")},
+				"two": {Key: ygot.String("two"), Value: ygot.String("two-val")},
+				"six": {Key: ygot.String("six")},
+			},
+		}
+	}
+	withoutValues := func(d *ctestschema.Device) *ctestschema.Device {
+		for _, v := range d.UnorderedList {
+			v.Value = nil
+		}
+		return d
+	}
+
+	tests := []struct {
+		desc             string
+		inPath           *gpb.Path
+		inOpts           []ytypes.DelNodeOpt
+		want             *ctestschema.Device
+		wantCount        int
+		wantErrSubstring string
+	}{{
+		desc:   "path without wildcards",
+		inPath: mustPath("/unordered-lists/unordered-list[key=one]/config/value"),
+		want: func() *ctestschema.Device {
+			d := newDevice()
+			d.UnorderedList["one"].Value = nil
+			return d
+		}(),
+		wantCount: 1,
+	}, {
+		desc:      "path without wildcards to an unpopulated leaf",
+		inPath:    mustPath("/unordered-lists/unordered-list[key=six]/config/value"),
+		want:      newDevice(),
+		wantCount: 0,
+	}, {
+		desc:      "wildcard key",
+		inPath:    mustPath("/unordered-lists/unordered-list[key=*]/config/value"),
+		want:      withoutValues(newDevice()),
+		wantCount: 2,
+	}, {
+		desc:   "wildcard key matching list entries",
+		inPath: mustPath("/unordered-lists/unordered-list[key=*]"),
+		want: &ctestschema.Device{
+			OrderedList: ctestschema.GetOrderedMap(t),
+		},
+		wantCount: 3,
+	}, {
+		desc:   "wildcard key matching ordered list entries",
+		inPath: mustPath("/ordered-lists/ordered-list[key=*]"),
+		want: func() *ctestschema.Device {
+			d := newDevice()
+			d.OrderedList.Delete("foo")
+			d.OrderedList.Delete("bar")
+			return d
+		}(),
+		wantCount: 2,
+	}, {
+		desc:      "wildcard key and name",
+		inPath:    mustPath("/unordered-lists/unordered-list[key=*]/*/value"),
+		want:      withoutValues(newDevice()),
+		wantCount: 2,
+	}, {
+		desc:   "wildcard name with keys",
+		inPath: mustPath("/*/*[key=two]/config/value"),
+		want: func() *ctestschema.Device {
+			d := newDevice()
+			d.UnorderedList["two"].Value = nil
+			return d
+		}(),
+		wantCount: 1,
+	}, {
+		desc:      "wildcard name matching shadow paths",
+		inPath:    mustPath("/unordered-lists/unordered-list[key=*]/*/value"),
+		inOpts:    []ytypes.DelNodeOpt{&ytypes.PreferShadowPath{}},
+		want:      withoutValues(newDevice()),
+		wantCount: 2,
+	}, {
+		desc:      "wildcard name without matches",
+		inPath:    mustPath("/unordered-lists/unordered-list[key=*]/*/does-not-exist"),
+		want:      newDevice(),
+		wantCount: 0,
+	}, {
+		desc:             "multi-level wildcard",
+		inPath:           mustPath("/unordered-lists/.../value"),
+		want:             newDevice(),
+		wantErrSubstring: "multi-level wildcards are not supported",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := newDevice()
+			count, err := ytypes.DeleteNodes(ctestschema.SchemaTree["Device"], got, tt.inPath, tt.inOpts...)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("DeleteNodes: %s", diff)
+			}
+			if count != tt.wantCount {
+				t.Errorf("DeleteNodes: got %d deleted nodes, want %d", count, tt.wantCount)
+			}
+			if diff := cmp.Diff(tt.want, got, ytestutil.OrderedMapCmpOptions...); diff != "" {
+				t.Errorf("DeleteNodes (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestListEntryCallback(t *testing.T) {
+	newDevice := func() *ctestschema.Device {
+		return &ctestschema.Device{
+			OrderedList: ctestschema.GetOrderedMap(t),
+			UnorderedList: map[string]*ctestschema.UnorderedList{
+				"one": {Key: ygot.String("one"), Value: ygot.String("one-val")},
+				"six": {Key: ygot.String("six")},
+			},
+		}
+	}
+	jsonVal := func(d *ctestschema.Device) *gpb.TypedValue {
+		j, err := ygot.Marshal7951(d)
+		if err != nil {
+			t.Fatalf("cannot marshal JSON: %v", err)
+		}
+		return &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: j}}
+	}
+
+	tests := []struct {
+		desc             string
+		inMutate         func(*ctestschema.Device, ytypes.ListEntryCallback) error
+		want             []string
+		wantErrSubstring string
+	}{{
+		desc: "set leaf of new unordered list entry",
+		inMutate: func(d *ctestschema.Device, cb ytypes.ListEntryCallback) error {
+			return ytypes.SetNode(ctestschema.SchemaTree["Device"], d, mustPath("/unordered-lists/unordered-list[key=two]/config/value"), &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "two-val"}}, &ytypes.InitMissingElements{}, &cb)
+		},
+		want: []string{"+ /unordered-lists/unordered-list[key=two]"},
+	}, {
+		desc: "set leaf of existing unordered list entry",
+		inMutate: func(d *ctestschema.Device, cb ytypes.ListEntryCallback) error {
+			return ytypes.SetNode(ctestschema.SchemaTree["Device"], d, mustPath("/unordered-lists/unordered-list[key=one]/config/value"), &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "new-val"}}, &ytypes.InitMissingElements{}, &cb)
+		},
+	}, {
+		desc: "set leaf of new ordered list entry",
+		inMutate: func(d *ctestschema.Device, cb ytypes.ListEntryCallback) error {
+			return ytypes.SetNode(ctestschema.SchemaTree["Device"], d, mustPath("/ordered-lists/ordered-list[key=baz]/config/value"), &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "baz-val"}}, &ytypes.InitMissingElements{}, &cb)
+		},
+		want: []string{"+ /ordered-lists/ordered-list[key=baz]"},
+	}, {
+		desc: "set root using JSON",
+		inMutate: func(d *ctestschema.Device, cb ytypes.ListEntryCallback) error {
+			return ytypes.SetNode(ctestschema.SchemaTree["Device"], d, &gpb.Path{}, jsonVal(&ctestschema.Device{
+				UnorderedList: map[string]*ctestschema.UnorderedList{
+					"one":   {Key: ygot.String("one")},
+					"three": {Key: ygot.String("three")},
+					"four":  {Key: ygot.String("four")},
+				},
+			}), &cb)
+		},
+		want: []string{
+			"+ /unordered-lists/unordered-list[key=four]",
+			"+ /unordered-lists/unordered-list[key=three]",
+		},
+	}, {
+		desc: "delete unordered list entry",
+		inMutate: func(d *ctestschema.Device, cb ytypes.ListEntryCallback) error {
+			return ytypes.DeleteNode(ctestschema.SchemaTree["Device"], d, mustPath("/unordered-lists/unordered-list[key=one]"), &cb)
+		},
+		want: []string{"- /unordered-lists/unordered-list[key=one]"},
+	}, {
+		desc: "delete absent unordered list entry",
+		inMutate: func(d *ctestschema.Device, cb ytypes.ListEntryCallback) error {
+			return ytypes.DeleteNode(ctestschema.SchemaTree["Device"], d, mustPath("/unordered-lists/unordered-list[key=two]"), &cb)
+		},
+	}, {
+		desc: "delete leaf leaving empty unordered list entry",
+		inMutate: func(d *ctestschema.Device, cb ytypes.ListEntryCallback) error {
+			return ytypes.DeleteNode(ctestschema.SchemaTree["Device"], d, mustPath("/unordered-lists/unordered-list[key=six]/config/key"), &cb)
+		},
+		want: []string{"- /unordered-lists/unordered-list[key=six]"},
+	}, {
+		desc: "delete ordered list entry",
+		inMutate: func(d *ctestschema.Device, cb ytypes.ListEntryCallback) error {
+			return ytypes.DeleteNode(ctestschema.SchemaTree["Device"], d, mustPath("/ordered-lists/ordered-list[key=bar]"), &cb)
+		},
+		want: []string{"- /ordered-lists/ordered-list[key=bar]"},
+	}, {
+		desc: "delete container of ordered list",
+		inMutate: func(d *ctestschema.Device, cb ytypes.ListEntryCallback) error {
+			return ytypes.DeleteNode(ctestschema.SchemaTree["Device"], d, mustPath("/ordered-lists"), &cb)
+		},
+		want: []string{
+			"- /ordered-lists/ordered-list[key=bar]",
+			"- /ordered-lists/ordered-list[key=foo]",
+		},
+	}, {
+		desc: "delete root",
+		inMutate: func(d *ctestschema.Device, cb ytypes.ListEntryCallback) error {
+			return ytypes.DeleteNode(ctestschema.SchemaTree["Device"], d, &gpb.Path{}, &cb)
+		},
+		want: []string{
+			"- /ordered-lists/ordered-list[key=bar]",
+			"- /ordered-lists/ordered-list[key=foo]",
+			"- /unordered-lists/unordered-list[key=one]",
+			"- /unordered-lists/unordered-list[key=six]",
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var got []string
+			cb := ytypes.ListEntryCallback{Fn: func(c *ytypes.ListEntryChange) {
+				p := proto.Clone(c.Path).(*gpb.Path)
+				p.Elem[len(p.Elem)-1].Key = c.Keys
+				ps, err := ygot.PathToString(p)
+				if err != nil {
+					t.Fatalf("cannot convert path %v to string: %v", p, err)
+				}
+				op := "+"
+				if c.Deleted {
+					op = "-"
+				}
+				got = append(got, fmt.Sprintf("%s %s", op, ps))
+			}}
+			err := tt.inMutate(newDevice(), cb)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("did not get expected error, %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+				t.Errorf("did not get expected list entry changes, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetNodeTraversalBudget(t *testing.T) {
+	d := &ctestschema.Device{
+		UnorderedList: map[string]*ctestschema.UnorderedList{
+			"one":   {Key: ygot.String("one"), Value: ygot.String("one-val")},
+			"two":   {Key: ygot.String("two"), Value: ygot.String("two-val")},
+			"three": {Key: ygot.String("three"), Value: ygot.String("three-val")},
+		},
+	}
+
+	tests := []struct {
+		desc         string
+		inBudget     *ytypes.GetTraversalBudget
+		wantNodes    int
+		wantExceeded bool
+	}{{
+		desc:      "sufficient budget",
+		inBudget:  &ytypes.GetTraversalBudget{MaxSteps: 8},
+		wantNodes: 3,
+	}, {
+		desc:         "exceeded budget",
+		inBudget:     &ytypes.GetTraversalBudget{MaxSteps: 5},
+		wantExceeded: true,
+	}, {
+		desc:         "exceeded budget with partial results",
+		inBudget:     &ytypes.GetTraversalBudget{MaxSteps: 5, PartialResults: true},
+		wantNodes:    1,
+		wantExceeded: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			nodes, err := ytypes.GetNode(ctestschema.SchemaTree["Device"], d, mustPath("/unordered-lists/unordered-list[key=*]/config/value"), &ytypes.GetHandleWildcards{}, tt.inBudget)
+			var budgetErr *ytypes.TraversalBudgetExceededError
+			if got := errors.As(err, &budgetErr); got != tt.wantExceeded {
+				t.Fatalf("GetNode: got error %v, want budget exceeded: %v", err, tt.wantExceeded)
+			}
+			if !tt.wantExceeded && err != nil {
+				t.Fatalf("GetNode: got unexpected error: %v", err)
+			}
+			if tt.wantExceeded {
+				if got, want := status.Code(err), codes.ResourceExhausted; got != want {
+					t.Errorf("GetNode: got error code %v, want %v", got, want)
+				}
+				if got, want := budgetErr.MaxSteps, tt.inBudget.MaxSteps; got != want {
+					t.Errorf("GetNode: got MaxSteps %d in error, want %d", got, want)
+				}
+			}
+			if got := len(nodes); got != tt.wantNodes {
+				t.Errorf("GetNode: got %d nodes, want %d", got, tt.wantNodes)
+			}
+		})
+	}
+}
+
+func TestCaseInsensitivePathMatch(t *testing.T) {
+	type warning struct{ name, schemaName string }
+
+	tests := []struct {
+		desc             string
+		inPath           string
+		inMatch          bool
+		wantPath         string
+		wantVal          string
+		wantWarnings     []warning
+		wantErrSubstring string
+	}{{
+		desc:     "exact match",
+		inPath:   "/unordered-lists/unordered-list[key=one]/config/value",
+		inMatch:  true,
+		wantPath: "/unordered-lists/unordered-list[key=one]/config/value",
+		wantVal:  "one-val",
+	}, {
+		desc:     "case-insensitive match",
+		inPath:   "/Unordered-Lists/unordered-LIST[key=one]/Config/value",
+		inMatch:  true,
+		wantPath: "/unordered-lists/unordered-list[key=one]/config/value",
+		wantVal:  "one-val",
+		wantWarnings: []warning{
+			{"Unordered-Lists", "unordered-lists"},
+			{"unordered-LIST", "unordered-list"},
+			{"Config", "config"},
+		},
+	}, {
+		desc:             "key names are matched exactly",
+		inPath:           "/unordered-lists/unordered-list[KEY=one]/config/value",
+		inMatch:          true,
+		wantErrSubstring: "KEY",
+	}, {
+		desc:             "case-insensitive match not enabled",
+		inPath:           "/Unordered-Lists/unordered-list[key=one]/config/value",
+		wantErrSubstring: "no match found",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var gotWarnings []warning
+			match := &ytypes.CaseInsensitivePathMatch{
+				Warn: func(name, schemaName string) { gotWarnings = append(gotWarnings, warning{name, schemaName}) },
+			}
+			var getOpts []ytypes.GetNodeOpt
+			var setOpts []ytypes.SetNodeOpt
+			if tt.inMatch {
+				getOpts = append(getOpts, match)
+				setOpts = append(setOpts, match)
+			}
+			d := &ctestschema.Device{
+				UnorderedList: map[string]*ctestschema.UnorderedList{
+					"one": {Key: ygot.String("one"), Value: ygot.String("one-val")},
+				},
+			}
+
+			nodes, err := ytypes.GetNode(ctestschema.SchemaTree["Device"], d, mustPath(tt.inPath), getOpts...)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("GetNode: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if len(nodes) != 1 {
+				t.Fatalf("GetNode: got %d nodes, want 1", len(nodes))
+			}
+			if diff := cmp.Diff(mustPath(tt.wantPath), nodes[0].Path, cmp.Comparer(proto.Equal)); diff != "" {
+				t.Errorf("GetNode: did not get expected path (-want, +got):\n%s", diff)
+			}
+			if got := nodes[0].Data.(*string); *got != tt.wantVal {
+				t.Errorf("GetNode: got value %q, want %q", *got, tt.wantVal)
+			}
+			if diff := cmp.Diff(tt.wantWarnings, gotWarnings, cmp.AllowUnexported(warning{})); diff != "" {
+				t.Errorf("GetNode: did not get expected warnings (-want, +got):\n%s", diff)
+			}
+
+			if err := ytypes.SetNode(ctestschema.SchemaTree["Device"], d, mustPath(tt.inPath), &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "new-val"}}, setOpts...); err != nil {
+				t.Fatalf("SetNode: got unexpected error: %v", err)
+			}
+			if got, want := d.UnorderedList["one"].GetValue(), "new-val"; got != want {
+				t.Errorf("SetNode: got value %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// The following path structs are equivalent to those generated by ypathgen
+// for ctestschema when GoStruct links are generated.
+
+type deviceGoStructPath struct {
+	*ygot.DeviceRootBase
+}
+
+func (*deviceGoStructPath) ΛGoStruct() *ctestschema.Device { return nil }
+
+type otherDataGoStructPath struct {
+	*ygot.NodePath
+}
+
+func (*otherDataGoStructPath) ΛGoStruct() *ctestschema.OtherData { return nil }
+
+type unorderedListGoStructPath struct {
+	*ygot.NodePath
+}
+
+func (*unorderedListGoStructPath) ΛGoStruct() *ctestschema.UnorderedList { return nil }
+
+func TestGetGoStruct(t *testing.T) {
+	d := &ctestschema.Device{
+		OtherData: &ctestschema.OtherData{Motd: ygot.String("hello")},
+		UnorderedList: map[string]*ctestschema.UnorderedList{
+			"one": {Key: ygot.String("one"), Value: ygot.String("one-val")},
+			"two": {Key: ygot.String("two"), Value: ygot.String("two-val")},
+		},
+	}
+	root := &deviceGoStructPath{ygot.NewDeviceRootBase("dev")}
+	unorderedList := func(key string) *unorderedListGoStructPath {
+		return &unorderedListGoStructPath{ygot.NewNodePath([]string{"unordered-lists", "unordered-list"}, map[string]interface{}{"key": key}, root)}
+	}
+
+	tests := []struct {
+		desc             string
+		inGet            func() (ygot.GoStruct, error)
+		want             ygot.GoStruct
+		wantErrSubstring string
+	}{{
+		desc: "root",
+		inGet: func() (ygot.GoStruct, error) {
+			return ytypes.GetGoStruct(ctestschema.SchemaTree["Device"], d, root)
+		},
+		want: d,
+	}, {
+		desc: "container",
+		inGet: func() (ygot.GoStruct, error) {
+			return ytypes.GetGoStruct(ctestschema.SchemaTree["Device"], d, &otherDataGoStructPath{ygot.NewNodePath([]string{"other-data"}, nil, root)})
+		},
+		want: d.OtherData,
+	}, {
+		desc: "list entry",
+		inGet: func() (ygot.GoStruct, error) {
+			return ytypes.GetGoStruct(ctestschema.SchemaTree["Device"], d, unorderedList("two"))
+		},
+		want: d.UnorderedList["two"],
+	}, {
+		desc: "missing list entry",
+		inGet: func() (ygot.GoStruct, error) {
+			return ytypes.GetGoStruct(ctestschema.SchemaTree["Device"], d, unorderedList("three"))
+		},
+		wantErrSubstring: "NotFound",
+	}, {
+		desc: "wildcard matching multiple entries",
+		inGet: func() (ygot.GoStruct, error) {
+			return ytypes.GetGoStruct(ctestschema.SchemaTree["Device"], d, unorderedList("*"), &ytypes.GetHandleWildcards{})
+		},
+		wantErrSubstring: "matched 2 nodes, want exactly one",
+	}, {
+		desc: "invalid key",
+		inGet: func() (ygot.GoStruct, error) {
+			return ytypes.GetGoStruct(ctestschema.SchemaTree["Device"], d, &unorderedListGoStructPath{ygot.NewNodePath([]string{"unordered-lists", "unordered-list"}, map[string]interface{}{"key": struct{}{}}, root)})
+		},
+		wantErrSubstring: "cannot resolve path struct",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := tt.inGet()
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("GetGoStruct: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetGoStruct: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetNodeSchemaMetadata(t *testing.T) {
+	d := &ctestschema.Device{
+		OtherData: &ctestschema.OtherData{Motd: ygot.String("hello")},
+		UnorderedList: map[string]*ctestschema.UnorderedList{
+			"one": {Key: ygot.String("one"), Value: ygot.String("one-val")},
+			"two": {Key: ygot.String("two"), Value: ygot.String("two-val")},
+		},
+	}
+
+	tests := []struct {
+		desc         string
+		inPath       *gpb.Path
+		inOpts       []ytypes.GetNodeOpt
+		wantMetadata []*ytypes.TreeNodeMetadata
+	}{{
+		desc:         "metadata not requested",
+		inPath:       mustPath("/other-data/config/motd"),
+		wantMetadata: []*ytypes.TreeNodeMetadata{nil},
+	}, {
+		desc:   "container",
+		inPath: mustPath("/other-data"),
+		inOpts: []ytypes.GetNodeOpt{&ytypes.GetSchemaMetadata{}},
+		wantMetadata: []*ytypes.TreeNodeMetadata{{
+			IsConfig: true,
+		}},
+	}, {
+		desc:   "config leaf",
+		inPath: mustPath("/other-data/config/motd"),
+		inOpts: []ytypes.GetNodeOpt{&ytypes.GetSchemaMetadata{}},
+		wantMetadata: []*ytypes.TreeNodeMetadata{{
+			IsConfig:     true,
+			YANGTypeName: "string",
+		}},
+	}, {
+		desc:   "state leaf",
+		inPath: mustPath("/other-data/state/motd"),
+		inOpts: []ytypes.GetNodeOpt{&ytypes.GetSchemaMetadata{}, &ytypes.PreferShadowPath{}},
+		wantMetadata: []*ytypes.TreeNodeMetadata{{
+			IsConfig:     false,
+			YANGTypeName: "string",
+		}},
+	}, {
+		desc:   "wildcard query of leaves with defaults",
+		inPath: mustPath("/unordered-lists/unordered-list[key=*]/config/value"),
+		inOpts: []ytypes.GetNodeOpt{&ytypes.GetSchemaMetadata{}, &ytypes.GetHandleWildcards{}},
+		wantMetadata: []*ytypes.TreeNodeMetadata{{
+			IsConfig:     true,
+			YANGTypeName: "string",
+			Defaults:     []string{"default-value"},
+		}, {
+			IsConfig:     true,
+			YANGTypeName: "string",
+			Defaults:     []string{"default-value"},
+		}},
+	}, {
+		desc:   "list key compressed to config leaf",
+		inPath: mustPath("/unordered-lists/unordered-list[key=one]/key"),
+		inOpts: []ytypes.GetNodeOpt{&ytypes.GetSchemaMetadata{}},
+		wantMetadata: []*ytypes.TreeNodeMetadata{{
+			IsConfig:     true,
+			YANGTypeName: "string",
+		}},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			nodes, err := ytypes.GetNode(ctestschema.SchemaTree["Device"], d, tt.inPath, tt.inOpts...)
+			if err != nil {
+				t.Fatalf("GetNode: got unexpected error: %v", err)
+			}
+			var got []*ytypes.TreeNodeMetadata
+			for _, n := range nodes {
+				got = append(got, n.Metadata)
+			}
+			if diff := cmp.Diff(tt.wantMetadata, got); diff != "" {
+				t.Errorf("GetNode: did not get expected metadata (-want, +got):\n%s", diff)
+			}
+
+			bulk, err := ytypes.GetNodes(ctestschema.SchemaTree["Device"], d, []*gpb.Path{tt.inPath}, tt.inOpts...)
+			if err != nil {
+				t.Fatalf("GetNodes: got unexpected error: %v", err)
+			}
+			got = nil
+			for _, n := range bulk[0] {
+				got = append(got, n.Metadata)
+			}
+			if diff := cmp.Diff(tt.wantMetadata, got); diff != "" {
+				t.Errorf("GetNodes: did not get expected metadata (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -2879,6 +2879,136 @@ func TestSetNode(t *testing.T) {
 	}
 }
 
+func TestSetNodeGoStruct(t *testing.T) {
+	tests := []struct {
+		inDesc           string
+		inSchema         *yang.Entry
+		inParent         interface{}
+		inPath           *gpb.Path
+		inVal            ygot.GoStruct
+		inOpts           []SetNodeOpt
+		wantErrSubstring string
+		wantParent       interface{}
+	}{{
+		inDesc:     "success creating container",
+		inSchema:   simpleSchema(),
+		inParent:   &ListElemStruct1{Outer: &OuterContainerType1{}},
+		inPath:     mustPath("/outer/inner"),
+		inVal:      &InnerContainerType1{Int32LeafName: ygot.Int32(42)},
+		wantParent: &ListElemStruct1{Outer: &OuterContainerType1{Inner: &InnerContainerType1{Int32LeafName: ygot.Int32(42)}}},
+	}, {
+		inDesc:   "success merging into container",
+		inSchema: simpleSchema(),
+		inParent: &ListElemStruct1{Outer: &OuterContainerType1{Inner: &InnerContainerType1{
+			Int32LeafName:  ygot.Int32(41),
+			StringLeafName: ygot.String("hello"),
+		}}},
+		inPath: mustPath("/outer/config/inner"),
+		inVal:  &InnerContainerType1{Int32LeafName: ygot.Int32(42)},
+		wantParent: &ListElemStruct1{Outer: &OuterContainerType1{Inner: &InnerContainerType1{
+			Int32LeafName:  ygot.Int32(42),
+			StringLeafName: ygot.String("hello"),
+		}}},
+	}, {
+		inDesc:   "success replacing container",
+		inSchema: simpleSchema(),
+		inParent: &ListElemStruct1{Outer: &OuterContainerType1{Inner: &InnerContainerType1{
+			Int32LeafName:  ygot.Int32(41),
+			StringLeafName: ygot.String("hello"),
+		}}},
+		inPath:     mustPath("/outer/inner"),
+		inVal:      &InnerContainerType1{Int32LeafName: ygot.Int32(42)},
+		inOpts:     []SetNodeOpt{&ReplaceGoStruct{}},
+		wantParent: &ListElemStruct1{Outer: &OuterContainerType1{Inner: &InnerContainerType1{Int32LeafName: ygot.Int32(42)}}},
+	}, {
+		inDesc:     "success setting container with missing ancestors",
+		inSchema:   simpleSchema(),
+		inParent:   &ListElemStruct1{},
+		inPath:     mustPath("/outer/inner"),
+		inVal:      &InnerContainerType1{Int32LeafName: ygot.Int32(42)},
+		inOpts:     []SetNodeOpt{&InitMissingElements{}},
+		wantParent: &ListElemStruct1{Outer: &OuterContainerType1{Inner: &InnerContainerType1{Int32LeafName: ygot.Int32(42)}}},
+	}, {
+		inDesc:   "success merging into list entry",
+		inSchema: containerWithStringKey(),
+		inParent: &ContainerStruct1{StructKeyList: map[string]*ListElemStruct1{
+			"forty-two": {Key1: ygot.String("forty-two"), Outer: &OuterContainerType1{Inner: &InnerContainerType1{StringLeafName: ygot.String("hello")}}},
+		}},
+		inPath: mustPath("/config/simple-key-list[key1=forty-two]"),
+		inVal:  &ListElemStruct1{Key1: ygot.String("forty-two"), Outer: &OuterContainerType1{Inner: &InnerContainerType1{Int32LeafName: ygot.Int32(42)}}},
+		wantParent: &ContainerStruct1{StructKeyList: map[string]*ListElemStruct1{
+			"forty-two": {Key1: ygot.String("forty-two"), Outer: &OuterContainerType1{Inner: &InnerContainerType1{
+				Int32LeafName:  ygot.Int32(42),
+				StringLeafName: ygot.String("hello"),
+			}}},
+		}},
+	}, {
+		inDesc:   "success creating list entry",
+		inSchema: containerWithStringKey(),
+		inParent: &ContainerStruct1{},
+		inPath:   mustPath("/config/simple-key-list[key1=forty-two]"),
+		inVal:    &ListElemStruct1{Key1: ygot.String("forty-two"), Outer: &OuterContainerType1{}},
+		inOpts:   []SetNodeOpt{&InitMissingElements{}},
+		wantParent: &ContainerStruct1{StructKeyList: map[string]*ListElemStruct1{
+			"forty-two": {Key1: ygot.String("forty-two"), Outer: &OuterContainerType1{}},
+		}},
+	}, {
+		inDesc:           "failure setting list entry with mismatched key",
+		inSchema:         containerWithStringKey(),
+		inParent:         &ContainerStruct1{StructKeyList: map[string]*ListElemStruct1{"forty-two": {Key1: ygot.String("forty-two")}}},
+		inPath:           mustPath("/config/simple-key-list[key1=forty-two]"),
+		inVal:            &ListElemStruct1{Key1: ygot.String("forty-three")},
+		wantErrSubstring: "key key1 of value forty-three does not match forty-two",
+		wantParent:       &ContainerStruct1{StructKeyList: map[string]*ListElemStruct1{"forty-two": {Key1: ygot.String("forty-two")}}},
+	}, {
+		inDesc:           "failure setting list entry with missing key",
+		inSchema:         containerWithStringKey(),
+		inParent:         &ContainerStruct1{StructKeyList: map[string]*ListElemStruct1{"forty-two": {Key1: ygot.String("forty-two")}}},
+		inPath:           mustPath("/config/simple-key-list[key1=forty-two]"),
+		inVal:            &ListElemStruct1{},
+		wantErrSubstring: "key key1 of value <nil> does not match forty-two",
+		wantParent:       &ContainerStruct1{StructKeyList: map[string]*ListElemStruct1{"forty-two": {Key1: ygot.String("forty-two")}}},
+	}, {
+		inDesc:           "failure setting missing list entry",
+		inSchema:         containerWithStringKey(),
+		inParent:         &ContainerStruct1{},
+		inPath:           mustPath("/config/simple-key-list[key1=forty-two]"),
+		inVal:            &ListElemStruct1{Key1: ygot.String("forty-two")},
+		wantErrSubstring: "could not find children",
+		wantParent:       &ContainerStruct1{},
+	}, {
+		inDesc:           "failure setting container to value of wrong type",
+		inSchema:         simpleSchema(),
+		inParent:         &ListElemStruct1{Outer: &OuterContainerType1{}},
+		inPath:           mustPath("/outer/inner"),
+		inVal:            &OuterContainerType1{},
+		wantErrSubstring: "cannot set node at path",
+		wantParent:       &ListElemStruct1{Outer: &OuterContainerType1{}},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.inDesc, func(t *testing.T) {
+			err := SetNode(tt.inSchema, tt.inParent, tt.inPath, tt.inVal, tt.inOpts...)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("SetNode: did not get expected error, %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantParent, tt.inParent); diff != "" {
+				t.Errorf("SetNode (-wantParent, +got):\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+			nodes, err := GetNode(tt.inSchema, tt.inParent, tt.inPath)
+			if err != nil {
+				t.Fatalf("GetNode: unexpected error: %v", err)
+			}
+			if len(nodes) != 1 || nodes[0].Data == tt.inVal {
+				t.Errorf("SetNode: got nodes %v, want a single node that is not the supplied value", nodes)
+			}
+		})
+	}
+}
+
 func TestDeleteNode(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -3543,7 +3673,7 @@ func TestRetrieveContainerListError(t *testing.T) {
 		inRoot:           &BadSchemaRoot{Ok: ygot.String("haddock")},
 		inPath:           &gpb.Path{Elem: []*gpb.PathElem{{Name: "field"}}},
 		inTestFunc:       retrieveNodeContainer,
-		wantErrSubstring: "could not find schema",
+		wantErrSubstring: "field Field of struct github.com/openconfig/ygot/ytypes.BadSchemaRoot has no schema node",
 	}, {
 		desc:             "error case - leafref unresolved",
 		inSchema:         lrSchema,
@@ -0,0 +1,353 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// TimestampPolicy specifies how an update or deletion that is received with a
+// timestamp older than one already applied to the same path is handled.
+type TimestampPolicy int64
+
+const (
+	// TimestampLastWriteWins specifies that every update and deletion is
+	// applied in the order that it is received, regardless of its
+	// timestamp.
+	TimestampLastWriteWins TimestampPolicy = iota
+	// TimestampDropOlder specifies that an update or deletion that has a
+	// timestamp older than the latest already applied to the same path
+	// is silently discarded.
+	TimestampDropOlder
+	// TimestampError specifies that an error is returned when an update or
+	// deletion has a timestamp older than the latest already applied to
+	// the same path.
+	TimestampError
+)
+
+// String returns a human-readable name for the TimestampPolicy.
+func (p TimestampPolicy) String() string {
+	switch p {
+	case TimestampLastWriteWins:
+		return "last-write-wins"
+	case TimestampDropOlder:
+		return "drop-older"
+	case TimestampError:
+		return "error"
+	}
+	return fmt.Sprintf("TimestampPolicy(%d)", int64(p))
+}
+
+// PathTimestampPolicy specifies the TimestampPolicy used for the subtree of
+// the data tree matching Path.
+type PathTimestampPolicy struct {
+	// Path is the path of the subtree to which Policy applies. It may
+	// contain wildcard names and keys.
+	Path *gpb.Path
+	// Policy is the policy that applies to the subtree.
+	Policy TimestampPolicy
+}
+
+// OutOfOrderPolicy is an unmarshal option for UnmarshalNotifications that
+// specifies how Notifications that are received out of timestamp order are
+// handled. This allows a cache that is populated from multiple upstream
+// sources, each of which may deliver updates with a different latency, to
+// avoid overwriting a value with a stale one.
+//
+// The timestamp of each update and deletion that is applied is recorded per
+// path, such that the same OutOfOrderPolicy must be supplied to each call to
+// UnmarshalNotifications for a particular root. A deletion of a subtree is
+// treated as a change to every path within that subtree, such that an older
+// update to a descendant of a deleted path is also considered out of order,
+// as is a deletion that is older than a change to any path within the
+// subtree. An update with a JSON value is treated as a change to each of the
+// leaves that it contains. Timestamps are only recorded once the Notification
+// containing the change has been applied.
+// An OutOfOrderPolicy must not be used by concurrent calls to
+// UnmarshalNotifications.
+type OutOfOrderPolicy struct {
+	// Default is the policy used for paths that do not match any entry of
+	// Paths.
+	Default TimestampPolicy
+	// Paths specifies the policy used for particular subtrees of the data
+	// tree. Where a path matches more than one entry, the policy with the
+	// longest matching Path is used.
+	Paths []*PathTimestampPolicy
+
+	// updated is the timestamp of the latest update applied, keyed by the
+	// string form of its path.
+	updated map[string]int64
+	// deleted is the timestamp of the latest deletion applied, keyed by
+	// the string form of its path.
+	deleted map[string]int64
+}
+
+// IsUnmarshalOpt marks OutOfOrderPolicy as a valid UnmarshalOpt.
+func (*OutOfOrderPolicy) IsUnmarshalOpt() {}
+
+// hasOutOfOrderPolicy returns the first OutOfOrderPolicy from the supplied
+// slice of UnmarshalOpts, or nil if there isn't one.
+func hasOutOfOrderPolicy(opts []UnmarshalOpt) *OutOfOrderPolicy {
+	for _, o := range opts {
+		if v, ok := o.(*OutOfOrderPolicy); ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// policyFor returns the TimestampPolicy that applies to the supplied path.
+func (o *OutOfOrderPolicy) policyFor(path *gpb.Path) TimestampPolicy {
+	policy, matchLen := o.Default, -1
+	for _, p := range o.Paths {
+		if l := len(p.Path.GetElem()); l > matchLen && util.PathMatchesQuery(path, p.Path) {
+			policy, matchLen = p.Policy, l
+		}
+	}
+	return policy
+}
+
+// timestampRecord is the timestamp of a change to the path key, which is
+// recorded by an OutOfOrderPolicy once the Notification containing the change
+// has been applied.
+type timestampRecord struct {
+	key      string
+	ts       int64
+	isDelete bool
+}
+
+// filter returns the updates and deletions within the supplied Notification
+// that should be applied according to the policy. The deletions are those in
+// deletePaths, which are relative to the Notification's prefix, and schema is
+// the schema of the root to which the Notification is applied. The returned
+// function records the timestamps of the changes that are applied, and must
+// be called once the Notification has been applied, such that no timestamp is
+// recorded if the policy or the application of the Notification fails.
+func (o *OutOfOrderPolicy) filter(schema *yang.Entry, n *gpb.Notification, deletePaths []*gpb.Path) ([]*gpb.Update, []*gpb.Path, func(), error) {
+	var records []*timestampRecord
+
+	var dels []*gpb.Path
+	for _, p := range deletePaths {
+		key, full, err := changeKey(n.Prefix, p)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		ok, err := o.check(key, full, n.Timestamp, true)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if ok {
+			dels = append(dels, p)
+			records = append(records, &timestampRecord{key: key, ts: n.Timestamp, isDelete: true})
+		}
+	}
+
+	var upds []*gpb.Update
+	for _, u := range n.Update {
+		// An update with a JSON value changes each of the leaves
+		// within the value, as well as the path of the update itself.
+		paths := []*gpb.Path{u.Path}
+		leaves, err := jsonLeafPaths(schema, n.Prefix, u)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		paths = append(paths, leaves...)
+
+		var urecords []*timestampRecord
+		apply := true
+		for _, p := range paths {
+			key, full, err := changeKey(n.Prefix, p)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			ok, err := o.check(key, full, n.Timestamp, false)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			apply = apply && ok
+			urecords = append(urecords, &timestampRecord{key: key, ts: n.Timestamp})
+		}
+		if apply {
+			upds = append(upds, u)
+			records = append(records, urecords...)
+		}
+	}
+
+	commit := func() {
+		for _, r := range records {
+			m := &o.updated
+			if r.isDelete {
+				m = &o.deleted
+			}
+			if *m == nil {
+				*m = map[string]int64{}
+			}
+			(*m)[r.key] = r.ts
+		}
+	}
+	return upds, dels, commit, nil
+}
+
+// changeKey returns the key by which the timestamp of a change to path,
+// which is relative to prefix, is recorded, along with the joined path.
+func changeKey(prefix, path *gpb.Path) (string, *gpb.Path, error) {
+	full, err := util.JoinPaths(prefix, path)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot join prefix with path: %v", err)
+	}
+	elems, err := ygot.PathToStrings(full)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot convert path %v to string: %v", full, err)
+	}
+	return "/" + strings.Join(elems, "/"), full, nil
+}
+
+// check determines whether a change to the path full, whose key is key, with
+// timestamp ts should be applied. An error is returned if the change is out of
+// order and the policy for the path is TimestampError. Since a deletion, as
+// specified by isDelete, removes the entire subtree at the path, it is out of
+// order if it is older than a change to any path within the subtree.
+func (o *OutOfOrderPolicy) check(key string, full *gpb.Path, ts int64, isDelete bool) (bool, error) {
+	last, seen := o.latest(key, isDelete)
+	if seen && ts < last {
+		switch o.policyFor(full) {
+		case TimestampDropOlder:
+			return false, nil
+		case TimestampError:
+			return false, fmt.Errorf("change to path %s has timestamp %d, which is older than the previously applied timestamp %d", key, ts, last)
+		}
+	}
+	return true, nil
+}
+
+// latest returns the timestamp of the latest change recorded for the path
+// key, and whether there is one. A deletion of any ancestor of the path, or
+// the path itself, counts as a change to the path. If subtree is set, changes
+// to the descendants of the path are also considered.
+func (o *OutOfOrderPolicy) latest(key string, subtree bool) (int64, bool) {
+	var last int64
+	var seen bool
+	record := func(ts int64) {
+		if !seen || ts > last {
+			last, seen = ts, true
+		}
+	}
+
+	if ts, ok := o.updated[key]; ok {
+		record(ts)
+	}
+	for k, ts := range o.deleted {
+		if k == key || isAncestorKey(k, key) || (subtree && isAncestorKey(key, k)) {
+			record(ts)
+		}
+	}
+	if subtree {
+		for k, ts := range o.updated {
+			if isAncestorKey(key, k) {
+				record(ts)
+			}
+		}
+	}
+	return last, seen
+}
+
+// isAncestorKey reports whether the path key a is a strict ancestor of the
+// path key b.
+func isAncestorKey(a, b string) bool {
+	return a != b && strings.HasPrefix(b, strings.TrimSuffix(a, "/")+"/")
+}
+
+// jsonLeafPaths returns the paths, relative to prefix, of the leaves and
+// leaf-lists that are set by the JSON value of the update u, whose path is
+// relative to prefix, or nil if the value of u is not JSON. The schema of the
+// root is used to determine the keys of list entries within the value.
+func jsonLeafPaths(schema *yang.Entry, prefix *gpb.Path, u *gpb.Update) ([]*gpb.Path, error) {
+	var b []byte
+	switch v := u.GetVal().GetValue().(type) {
+	case *gpb.TypedValue_JsonIetfVal:
+		b = v.JsonIetfVal
+	case *gpb.TypedValue_JsonVal:
+		b = v.JsonVal
+	default:
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var jv any
+	if err := dec.Decode(&jv); err != nil {
+		return nil, fmt.Errorf("cannot decode JSON value of update to %v: %v", u.GetPath(), err)
+	}
+
+	full, err := util.JoinPaths(prefix, u.GetPath())
+	if err != nil {
+		return nil, fmt.Errorf("cannot join prefix with path: %v", err)
+	}
+	var paths []*gpb.Path
+	addJSONLeafPaths(schemaAtPath(schema, full), jv, &gpb.Path{Elem: append([]*gpb.PathElem{}, u.GetPath().GetElem()...)}, &paths)
+	return paths, nil
+}
+
+// addJSONLeafPaths appends the paths of the leaves and leaf-lists set by the
+// decoded JSON value v, whose path is path and whose schema is schema, to
+// paths. Where the schema is unknown, the members of objects are still
+// traversed, but the entries of lists cannot be identified.
+func addJSONLeafPaths(schema *yang.Entry, v any, path *gpb.Path, paths *[]*gpb.Path) {
+	switch jv := v.(type) {
+	case map[string]any:
+		for name, cv := range jv {
+			var cs *yang.Entry
+			if schema != nil {
+				cs = childSchemaByName(schema, name)
+			}
+			cp := &gpb.Path{Elem: append(append([]*gpb.PathElem{}, path.Elem...), &gpb.PathElem{Name: util.StripModulePrefix(name)})}
+			addJSONLeafPaths(cs, cv, cp, paths)
+		}
+	case []any:
+		if schema == nil || !schema.IsList() || len(path.Elem) == 0 {
+			*paths = append(*paths, path)
+			return
+		}
+		for _, ev := range jv {
+			entry, ok := ev.(map[string]any)
+			if !ok {
+				continue
+			}
+			keys := map[string]string{}
+			for _, k := range strings.Fields(schema.Key) {
+				for name, kv := range entry {
+					if util.StripModulePrefix(name) == k {
+						keys[k] = fmt.Sprint(kv)
+					}
+				}
+			}
+			ep := &gpb.Path{Elem: append([]*gpb.PathElem{}, path.Elem...)}
+			last := ep.Elem[len(ep.Elem)-1]
+			ep.Elem[len(ep.Elem)-1] = &gpb.PathElem{Name: last.Name, Key: keys}
+			addJSONLeafPaths(schema, entry, ep, paths)
+		}
+	default:
+		*paths = append(*paths, path)
+	}
+}
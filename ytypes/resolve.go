@@ -0,0 +1,206 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/openconfig/ygot/internal/yreflect"
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// ResolvePathOpt is an interface used for any option to be supplied
+// to the ResolvePath and ResolveField functions. Types implementing it can
+// be used as options.
+type ResolvePathOpt interface {
+	IsResolvePathOpt()
+}
+
+// IsResolvePathOpt implements the ResolvePathOpt interface.
+func (*PreferShadowPath) IsResolvePathOpt() {}
+
+// hasResolvePathPreferShadowPath determines whether there is an instance of
+// PreferShadowPath within the supplied ResolvePathOpt slice.
+func hasResolvePathPreferShadowPath(opts []ResolvePathOpt) bool {
+	for _, o := range opts {
+		if _, ok := o.(*PreferShadowPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// PathResolution describes the field of a GoStruct that a path, or a part of
+// a path, resolves to, and the value of the path tags of the field that it
+// matched.
+type PathResolution struct {
+	// Struct is the type of the GoStruct that contains the field.
+	Struct reflect.Type
+	// Field is the name of the field.
+	Field string
+	// Tag is the name of the struct tag whose value the path matched,
+	// either "path" or "shadow-path".
+	Tag string
+	// Alternatives are the "|"-separated values of the tag.
+	Alternatives []string
+	// Alternative is the index within Alternatives of the value that the
+	// path matched.
+	Alternative int
+	// Shadowed indicates that the value that the path matched is treated
+	// as a shadow path, such that the data at the path is not stored in the
+	// field: it is ignored when set, and has no value when retrieved.
+	Shadowed bool
+}
+
+// SchemaPath returns the value of the path tag that the path matched.
+func (r *PathResolution) SchemaPath() string {
+	return r.Alternatives[r.Alternative]
+}
+
+// String returns a human-readable description of the resolution.
+func (r *PathResolution) String() string {
+	s := fmt.Sprintf("%v.%s: %s:%q matched %q", r.Struct, r.Field, r.Tag, strings.Join(r.Alternatives, "|"), r.SchemaPath())
+	if r.Shadowed {
+		s += " (shadowed)"
+	}
+	return s
+}
+
+// ResolvePath reports how each part of the supplied path is resolved to a
+// field of a GoStruct when it is traversed from root, which must be a
+// GoStruct pointer, by GetNode, SetNode and DeleteNode with options
+// equivalent to the supplied options. It returns the resolution for each
+// GoStruct along the path, the first of which is for a field of root. Only
+// the types of the GoStructs are used, such that the nodes along the path
+// need not exist within root.
+//
+// The resolution reports which of the "path" and "shadow-path" tags of each
+// field, and which of their "|"-separated values, the path matched, in order
+// that it can be determined whether, for example, a value that is set is
+// stored in a field for config or state data, or is ignored. Traversal stops
+// at a shadowed field, since such a field stores no data.
+func ResolvePath(root interface{}, path *gpb.Path, opts ...ResolvePathOpt) ([]*PathResolution, error) {
+	preferShadowPath := hasResolvePathPreferShadowPath(opts)
+
+	var res []*PathResolution
+	t := reflect.TypeOf(root)
+	elems := path.GetElem()
+	for len(elems) != 0 {
+		if !util.IsTypeStructPtr(t) {
+			return res, ygot.ClassifyError(ygot.ErrInvalidPath, fmt.Errorf("path %v traverses non-struct type %v", path, t))
+		}
+		r, n, err := resolveStruct(t, &gpb.Path{Elem: elems}, preferShadowPath)
+		if err != nil {
+			return res, err
+		}
+		res = append(res, r)
+		elems = elems[n:]
+		if r.Shadowed {
+			break
+		}
+
+		ft, _ := t.Elem().FieldByName(r.Field)
+		switch {
+		case util.IsTypeMap(ft.Type), util.IsTypeSlice(ft.Type) && util.IsTypeStructPtr(ft.Type.Elem()):
+			t = ft.Type.Elem()
+		case util.IsTypeStructPtr(ft.Type):
+			t = ft.Type
+			om, isOrderedMap := reflect.Zero(ft.Type).Interface().(ygot.GoOrderedMap)
+			if !isOrderedMap {
+				break
+			}
+			if t, err = yreflect.OrderedMapElementType(om); err != nil {
+				return res, err
+			}
+		default:
+			t = ft.Type
+		}
+	}
+	return res, nil
+}
+
+// ResolveField reports how the supplied path, which is relative to the
+// GoStruct s, is resolved to the field of s with the supplied name when it
+// is traversed by GetNode, SetNode and DeleteNode with options equivalent to
+// the supplied options. It returns an error if the path does not resolve to
+// the field, including if it resolves to an earlier field of s.
+func ResolveField(s interface{}, fieldName string, path *gpb.Path, opts ...ResolvePathOpt) (*PathResolution, error) {
+	t := reflect.TypeOf(s)
+	if !util.IsTypeStructPtr(t) {
+		return nil, fmt.Errorf("got %T, want struct ptr", s)
+	}
+	if _, ok := t.Elem().FieldByName(fieldName); !ok {
+		return nil, fmt.Errorf("%T has no field %s", s, fieldName)
+	}
+	r, _, err := resolveStruct(t, path, hasResolvePathPreferShadowPath(opts))
+	if err != nil {
+		return nil, err
+	}
+	if r.Field != fieldName {
+		return nil, fmt.Errorf("path %v resolves to field %s of %T, not %s", path, r.Field, s, fieldName)
+	}
+	return r, nil
+}
+
+// resolveStruct returns the resolution of the supplied path to the first
+// field of the GoStruct pointer type t that it matches, considering the
+// fields and their path tags in the same order as retrieveNodeContainer,
+// along with the number of elements of the path that the field consumes.
+func resolveStruct(t reflect.Type, path *gpb.Path, preferShadowPath bool) (*PathResolution, int, error) {
+	for i := 0; i < t.Elem().NumField(); i++ {
+		ft := t.Elem().Field(i)
+		r := &PathResolution{Struct: t, Field: ft.Name}
+
+		// match reports whether the path matches one of paths, which are
+		// the values of tag, populating the resolution if so.
+		match := func(tag string, paths [][]string, shadowed bool) (int, bool) {
+			for j, p := range paths {
+				if util.PathMatchesPrefix(path, p) {
+					r.Tag, r.Alternative, r.Shadowed = tag, j, shadowed
+					r.Alternatives = strings.Split(ft.Tag.Get(tag), "|")
+					return len(p), true
+				}
+			}
+			return 0, false
+		}
+
+		shadowPaths := util.ShadowSchemaPaths(ft)
+		if preferShadowPath {
+			if n, ok := match("shadow-path", shadowPaths, false); ok {
+				return r, n, nil
+			}
+		}
+		schPaths, err := util.SchemaPaths(ft)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get schema paths for %v, field %s: %v", t, ft.Name, err)
+		}
+		// If the user prefers the "shadow-path" tag and there are shadow
+		// paths, then the "path" tag values are treated as shadow paths.
+		if n, ok := match("path", schPaths, preferShadowPath && len(shadowPaths) != 0); ok {
+			return r, n, nil
+		}
+		if !preferShadowPath {
+			if n, ok := match("shadow-path", shadowPaths, true); ok {
+				return r, n, nil
+			}
+		}
+	}
+	return nil, 0, ygot.ClassifyError(ygot.ErrInvalidPath, fmt.Errorf("no match found in %v, for path %v", t, path))
+}
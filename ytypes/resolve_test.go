@@ -0,0 +1,200 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygot/ygot"
+)
+
+func TestResolvePath(t *testing.T) {
+	tests := []struct {
+		desc             string
+		inPath           string
+		inOpts           []ResolvePathOpt
+		want             []string
+		wantErrSubstring string
+	}{{
+		desc:   "leaf",
+		inPath: "/leaf",
+		want:   []string{`*ytypes.rootStruct.Leaf: path:"leaf" matched "leaf"`},
+	}, {
+		desc:   "leaf within list",
+		inPath: "/list[key=foo]/key",
+		want: []string{
+			`*ytypes.rootStruct.List: path:"list" matched "list"`,
+			`*ytypes.listEntry.Key: path:"key" matched "key"`,
+		},
+	}, {
+		desc:   "first alternative of path tag",
+		inPath: "/state/childlist[key=foo]/child-container/value",
+		want: []string{
+			`*ytypes.rootStruct.ChildList: path:"state/childlist" matched "state/childlist"`,
+			`*ytypes.childList.ChildContainer: path:"child-container" matched "child-container"`,
+			`*ytypes.listChildContainer.Value: path:"value|config/value" matched "value"`,
+		},
+	}, {
+		desc:   "first alternative of shadow-path tag with preferShadowPath",
+		inPath: "/state/childlist[key=foo]/child-container/value",
+		inOpts: []ResolvePathOpt{&PreferShadowPath{}},
+		want: []string{
+			`*ytypes.rootStruct.ChildList: path:"state/childlist" matched "state/childlist"`,
+			`*ytypes.childList.ChildContainer: path:"child-container" matched "child-container"`,
+			`*ytypes.listChildContainer.Value: shadow-path:"value|state/value" matched "value"`,
+		},
+	}, {
+		desc:   "second alternative of path tag",
+		inPath: "/state/childlist[key=foo]/child-container/config/value",
+		want: []string{
+			`*ytypes.rootStruct.ChildList: path:"state/childlist" matched "state/childlist"`,
+			`*ytypes.childList.ChildContainer: path:"child-container" matched "child-container"`,
+			`*ytypes.listChildContainer.Value: path:"value|config/value" matched "config/value"`,
+		},
+	}, {
+		desc:   "path tag is shadowed with preferShadowPath",
+		inPath: "/state/childlist[key=foo]/child-container/config/value",
+		inOpts: []ResolvePathOpt{&PreferShadowPath{}},
+		want: []string{
+			`*ytypes.rootStruct.ChildList: path:"state/childlist" matched "state/childlist"`,
+			`*ytypes.childList.ChildContainer: path:"child-container" matched "child-container"`,
+			`*ytypes.listChildContainer.Value: path:"value|config/value" matched "config/value" (shadowed)`,
+		},
+	}, {
+		desc:   "shadow-path tag is shadowed",
+		inPath: "/state/childlist[key=foo]/child-container/state/value",
+		want: []string{
+			`*ytypes.rootStruct.ChildList: path:"state/childlist" matched "state/childlist"`,
+			`*ytypes.childList.ChildContainer: path:"child-container" matched "child-container"`,
+			`*ytypes.listChildContainer.Value: shadow-path:"value|state/value" matched "state/value" (shadowed)`,
+		},
+	}, {
+		desc:   "traversal stops at shadowed field",
+		inPath: "/shadow-container/grandchild/val",
+		want:   []string{`*ytypes.rootStruct.Container: shadow-path:"shadow-container" matched "shadow-container" (shadowed)`},
+	}, {
+		desc:   "traversal continues through shadow path with preferShadowPath",
+		inPath: "/shadow-container/grandchild/val",
+		inOpts: []ResolvePathOpt{&PreferShadowPath{}},
+		want: []string{
+			`*ytypes.rootStruct.Container: shadow-path:"shadow-container" matched "shadow-container"`,
+			`*ytypes.childContainer.Container: path:"grandchild" matched "grandchild"`,
+			`*ytypes.grandchildContainer.Val: path:"val" matched "val"`,
+		},
+	}, {
+		desc:             "no matching field",
+		inPath:           "/container/missing",
+		want:             []string{`*ytypes.rootStruct.Container: path:"container" matched "container"`},
+		wantErrSubstring: "no match found in *ytypes.childContainer",
+	}, {
+		desc:             "path beyond leaf",
+		inPath:           "/leaf/child",
+		want:             []string{`*ytypes.rootStruct.Leaf: path:"leaf" matched "leaf"`},
+		wantErrSubstring: "traverses non-struct type *string",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ResolvePath(&rootStruct{}, mustPath(tt.inPath), tt.inOpts...)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("ResolvePath: did not get expected error, %s", diff)
+			}
+			if err != nil && !errors.Is(err, ygot.ErrInvalidPath) {
+				t.Errorf("ResolvePath: got error %v, want error of class %v", err, ygot.ErrInvalidPath)
+			}
+			var gotStrs []string
+			for _, r := range got {
+				gotStrs = append(gotStrs, r.String())
+			}
+			if diff := cmp.Diff(tt.want, gotStrs); diff != "" {
+				t.Errorf("ResolvePath: did not get expected resolutions, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResolveField(t *testing.T) {
+	tests := []struct {
+		desc             string
+		inStruct         interface{}
+		inField          string
+		inPath           string
+		inOpts           []ResolvePathOpt
+		wantTag          string
+		wantSchemaPath   string
+		wantShadowed     bool
+		wantErrSubstring string
+	}{{
+		desc:           "path tag",
+		inStruct:       &InnerContainerType1{},
+		inField:        "Int32LeafName",
+		inPath:         "/int32-leaf-field",
+		wantTag:        "path",
+		wantSchemaPath: "int32-leaf-field",
+	}, {
+		desc:           "state path is shadowed",
+		inStruct:       &InnerContainerType1{},
+		inField:        "Int32LeafName",
+		inPath:         "/state/int32-leaf-field",
+		wantTag:        "shadow-path",
+		wantSchemaPath: "state/int32-leaf-field",
+		wantShadowed:   true,
+	}, {
+		desc:           "config path is shadowed with preferShadowPath",
+		inStruct:       &InnerContainerType1{},
+		inField:        "Int32LeafName",
+		inPath:         "/config/int32-leaf-field",
+		inOpts:         []ResolvePathOpt{&PreferShadowPath{}},
+		wantTag:        "path",
+		wantSchemaPath: "config/int32-leaf-field",
+		wantShadowed:   true,
+	}, {
+		desc:             "path resolves to another field",
+		inStruct:         &InnerContainerType1{},
+		inField:          "StringLeafName",
+		inPath:           "/int32-leaf-field",
+		wantErrSubstring: "resolves to field Int32LeafName",
+	}, {
+		desc:             "no such field",
+		inStruct:         &InnerContainerType1{},
+		inField:          "Missing",
+		inPath:           "/int32-leaf-field",
+		wantErrSubstring: "has no field Missing",
+	}, {
+		desc:             "non-struct input",
+		inStruct:         "foo",
+		inField:          "Int32LeafName",
+		inPath:           "/int32-leaf-field",
+		wantErrSubstring: "want struct ptr",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ResolveField(tt.inStruct, tt.inField, mustPath(tt.inPath), tt.inOpts...)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("ResolveField: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if got.Tag != tt.wantTag || got.SchemaPath() != tt.wantSchemaPath || got.Shadowed != tt.wantShadowed {
+				t.Errorf("ResolveField: got %s, want tag %s, schema path %q, shadowed %v", got, tt.wantTag, tt.wantSchemaPath, tt.wantShadowed)
+			}
+		})
+	}
+}
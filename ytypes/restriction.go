@@ -0,0 +1,182 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"fmt"
+
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// RestrictionError is the error returned when a value does not satisfy a
+// range, length or pattern restriction of its YANG type. It can be retrieved
+// from the errors returned by validation using errors.As.
+type RestrictionError struct {
+	// Kind is the kind of the restriction, which is one of RangeIssue,
+	// LengthIssue or PatternIssue.
+	Kind ValidationIssueKind
+	// Value is the offending value, or, for a length restriction, its
+	// length.
+	Value string
+	// Restriction is the argument of the range, length or pattern statement
+	// that defines the restriction. If the statement cannot be found in the
+	// schema, for example because the schema was not parsed from YANG, it is
+	// the restriction of the resolved type.
+	Restriction string
+	// ErrorMessage is the argument of the error-message substatement of
+	// the restriction, if any.
+	ErrorMessage string
+	// ErrorAppTag is the argument of the error-app-tag substatement of the
+	// restriction, if any.
+	ErrorAppTag string
+
+	// msg describes how the value does not satisfy the restriction.
+	msg string
+}
+
+// Error returns a description of how the value does not satisfy the
+// restriction. The restriction itself is available from the fields of the
+// RestrictionError.
+func (e *RestrictionError) Error() string {
+	return e.msg
+}
+
+// newRangeError returns an error describing that value, described by msg, is
+// outside the range of schemaType, which is the type of the leaf schema.
+func newRangeError(schema *yang.Entry, schemaType *yang.YangType, value any, msg string) error {
+	e := &RestrictionError{
+		Kind:        RangeIssue,
+		Value:       fmt.Sprint(value),
+		Restriction: schemaType.Range.String(),
+		msg:         msg,
+	}
+	for _, t := range typeStatements(schema, schemaType) {
+		if r := t.Range; r != nil {
+			e.Restriction, e.ErrorMessage, e.ErrorAppTag = r.Name, valueName(r.ErrorMessage), valueName(r.ErrorAppTag)
+			break
+		}
+	}
+	return withIssueKind(RangeIssue, e)
+}
+
+// newLengthError returns an error describing that a value of the supplied
+// length, described by msg, is outside the allowed lengths of schemaType,
+// which is the type of the leaf schema.
+func newLengthError(schema *yang.Entry, schemaType *yang.YangType, length uint64, msg string) error {
+	e := &RestrictionError{
+		Kind:        LengthIssue,
+		Value:       fmt.Sprint(length),
+		Restriction: schemaType.Length.String(),
+		msg:         msg,
+	}
+	for _, t := range typeStatements(schema, schemaType) {
+		if l := t.Length; l != nil {
+			e.Restriction, e.ErrorMessage, e.ErrorAppTag = l.Name, valueName(l.ErrorMessage), valueName(l.ErrorAppTag)
+			break
+		}
+	}
+	return withIssueKind(LengthIssue, e)
+}
+
+// newPatternError returns an error describing that value, described by msg,
+// does not match pattern, which is a pattern or POSIX pattern of schemaType,
+// which is the type of the leaf schema.
+func newPatternError(schema *yang.Entry, schemaType *yang.YangType, value, pattern, msg string) error {
+	e := &RestrictionError{
+		Kind:        PatternIssue,
+		Value:       value,
+		Restriction: pattern,
+		msg:         msg,
+	}
+search:
+	for _, t := range typeStatements(schema, schemaType) {
+		for _, p := range t.Pattern {
+			if p.Name == pattern {
+				e.ErrorMessage, e.ErrorAppTag = valueName(p.ErrorMessage), valueName(p.ErrorAppTag)
+				break search
+			}
+		}
+	}
+	return withIssueKind(PatternIssue, e)
+}
+
+// typeStatements returns the type statements that define schemaType, which is
+// the type of the leaf schema, or one of the members of its union type, from
+// the most to the least specific, such that the first statement with a given
+// restriction defines it. The statement of schemaType itself can only be
+// found if schema is supplied and refers to the YANG statement that it was
+// created from, in which case the statements of the types that it is derived
+// from are also returned.
+func typeStatements(schema *yang.Entry, schemaType *yang.YangType) []*yang.Type {
+	if schemaType == nil {
+		return nil
+	}
+	t := schemaType.Base
+	if schema != nil {
+		var leafType *yang.Type
+		switch n := schema.Node.(type) {
+		case *yang.Leaf:
+			leafType = n.Type
+		case *yang.LeafList:
+			leafType = n.Type
+		}
+		if s := findTypeStatement(leafType, schemaType, map[*yang.Type]bool{}); s != nil {
+			t = s
+		}
+	}
+
+	var stmts []*yang.Type
+	seen := map[*yang.Type]bool{}
+	for ; t != nil && !seen[t]; t = t.YangType.Base {
+		seen[t] = true
+		stmts = append(stmts, t)
+		if t.YangType == nil {
+			break
+		}
+	}
+	return stmts
+}
+
+// findTypeStatement returns the type statement whose resolved type is
+// schemaType, searching t, the members of its union type, and the
+// statements of the types that it is derived from. It returns nil if no
+// such statement is found.
+func findTypeStatement(t *yang.Type, schemaType *yang.YangType, seen map[*yang.Type]bool) *yang.Type {
+	if t == nil || seen[t] {
+		return nil
+	}
+	seen[t] = true
+	if t.YangType == schemaType {
+		return t
+	}
+	for _, m := range t.Type {
+		if s := findTypeStatement(m, schemaType, seen); s != nil {
+			return s
+		}
+	}
+	if t.YangType == nil {
+		return nil
+	}
+	return findTypeStatement(t.YangType.Base, schemaType, seen)
+}
+
+// valueName returns the argument of the substatement v, or the empty string if
+// v is nil.
+func valueName(v *yang.Value) string {
+	if v == nil {
+		return ""
+	}
+	return v.Name
+}
@@ -0,0 +1,293 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/ygot"
+)
+
+// restrictionModule is a module whose leaves have types with range, length
+// and pattern restrictions, some of which have error-message and
+// error-app-tag substatements.
+const restrictionModule = `module restriction {
+  prefix "r";
+  namespace "urn:r";
+
+  typedef vlan-id {
+    type uint16 {
+      range "1..4094" {
+        error-message "VLAN ID is out of range";
+        error-app-tag "vlan-range";
+      }
+    }
+  }
+
+  typedef lower-name {
+    type string {
+      pattern "[a-z]+" {
+        error-message "name must be lower case";
+      }
+    }
+  }
+
+  container c {
+    leaf vlan { type vlan-id; }
+    leaf inner-vlan {
+      type vlan-id { range "2..100"; }
+    }
+    leaf name {
+      type lower-name {
+        length "1..4" { error-app-tag "name-length"; }
+      }
+    }
+    leaf-list names { type lower-name; }
+    leaf mtu {
+      type int32 { range "68..9000"; }
+    }
+    leaf bin {
+      type binary {
+        length "2" { error-message "two bytes are required"; }
+      }
+    }
+    leaf dec {
+      type decimal64 {
+        fraction-digits 2;
+        range "0..1.5";
+      }
+    }
+    leaf u {
+      type union {
+        type string {
+          pattern "a.*" { error-message "must start with a"; }
+        }
+        type int8 { range "0..10"; }
+      }
+    }
+  }
+}
+`
+
+// restrictionSchema returns the schema of the container of restrictionModule.
+func restrictionSchema(t *testing.T) *yang.Entry {
+	t.Helper()
+	ms := yang.NewModules()
+	if err := ms.Parse(restrictionModule, "restriction.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); errs != nil {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	m, errs := ms.GetModule("restriction")
+	if errs != nil {
+		t.Fatalf("cannot get module: %v", errs)
+	}
+	return m.Dir["c"]
+}
+
+func TestRestrictionError(t *testing.T) {
+	c := restrictionSchema(t)
+
+	tests := []struct {
+		desc             string
+		inLeaf           string
+		inVal            any
+		want             *RestrictionError
+		wantErrSubstring string
+	}{{
+		desc:   "range from typedef",
+		inLeaf: "vlan",
+		inVal:  ygot.Uint16(4095),
+		want: &RestrictionError{
+			Kind:         RangeIssue,
+			Value:        "4095",
+			Restriction:  "1..4094",
+			ErrorMessage: "VLAN ID is out of range",
+			ErrorAppTag:  "vlan-range",
+		},
+		wantErrSubstring: `unsigned integer value 4095 is outside specified ranges`,
+	}, {
+		desc:   "range refined by leaf type",
+		inLeaf: "inner-vlan",
+		inVal:  ygot.Uint16(101),
+		want: &RestrictionError{
+			Kind:        RangeIssue,
+			Value:       "101",
+			Restriction: "2..100",
+		},
+		wantErrSubstring: `unsigned integer value 101 is outside specified ranges`,
+	}, {
+		desc:   "signed range",
+		inLeaf: "mtu",
+		inVal:  ygot.Int32(42),
+		want: &RestrictionError{
+			Kind:        RangeIssue,
+			Value:       "42",
+			Restriction: "68..9000",
+		},
+		wantErrSubstring: `signed integer value 42 is outside specified ranges`,
+	}, {
+		desc:   "length of leaf type",
+		inLeaf: "name",
+		inVal:  ygot.String("abcde"),
+		want: &RestrictionError{
+			Kind:        LengthIssue,
+			Value:       "5",
+			Restriction: "1..4",
+			ErrorAppTag: "name-length",
+		},
+		wantErrSubstring: `length 5 is outside range 1..4`,
+	}, {
+		desc:   "pattern from typedef",
+		inLeaf: "name",
+		inVal:  ygot.String("ABC"),
+		want: &RestrictionError{
+			Kind:         PatternIssue,
+			Value:        "ABC",
+			Restriction:  "[a-z]+",
+			ErrorMessage: "name must be lower case",
+		},
+		wantErrSubstring: `"ABC" does not match regular expression pattern "^([a-z]+)$"`,
+	}, {
+		desc:   "pattern of leaf-list",
+		inLeaf: "names",
+		inVal:  []string{"abc", "DEF"},
+		want: &RestrictionError{
+			Kind:         PatternIssue,
+			Value:        "DEF",
+			Restriction:  "[a-z]+",
+			ErrorMessage: "name must be lower case",
+		},
+		wantErrSubstring: `"DEF" does not match regular expression pattern "^([a-z]+)$"`,
+	}, {
+		desc:   "binary length",
+		inLeaf: "bin",
+		inVal:  Binary{0x1},
+		want: &RestrictionError{
+			Kind:         LengthIssue,
+			Value:        "1",
+			Restriction:  "2",
+			ErrorMessage: "two bytes are required",
+		},
+		wantErrSubstring: `length 1 is outside range 2`,
+	}, {
+		desc:   "decimal range",
+		inLeaf: "dec",
+		inVal:  ygot.Float64(1.75),
+		want: &RestrictionError{
+			Kind:        RangeIssue,
+			Value:       "1.75",
+			Restriction: "0..1.5",
+		},
+		wantErrSubstring: `decimal value 1.75 is outside specified ranges`,
+	}, {
+		desc:   "pattern of union member",
+		inLeaf: "u",
+		inVal:  ygot.String("bcd"),
+		want: &RestrictionError{
+			Kind:         PatternIssue,
+			Value:        "bcd",
+			Restriction:  "a.*",
+			ErrorMessage: "must start with a",
+		},
+		wantErrSubstring: `"bcd" does not match regular expression pattern "^(a.*)$"`,
+	}, {
+		desc:   "range of union member",
+		inLeaf: "u",
+		inVal:  ygot.ToPtr(int8(11)),
+		want: &RestrictionError{
+			Kind:        RangeIssue,
+			Value:       "11",
+			Restriction: "0..10",
+		},
+		wantErrSubstring: `signed integer value 11 is outside specified ranges`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			errs := Validate(c.Dir[tt.inLeaf], tt.inVal)
+			if len(errs) != 1 {
+				t.Fatalf("Validate: got errors %v, want one error", errs)
+			}
+			if diff := errdiff.Substring(errs[0], tt.wantErrSubstring); diff != "" {
+				t.Errorf("Validate: did not get expected error, %s", diff)
+			}
+			var got *RestrictionError
+			if !errors.As(errs[0], &got) {
+				t.Fatalf("Validate: got error %v, want RestrictionError", errs[0])
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreUnexported(RestrictionError{})); diff != "" {
+				t.Errorf("Validate: did not get expected RestrictionError (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateRestrictionsWithoutSchema(t *testing.T) {
+	c := restrictionSchema(t)
+
+	tests := []struct {
+		desc string
+		in   error
+		want *RestrictionError
+	}{{
+		desc: "range from typedef",
+		in:   ValidateUintRestrictions(c.Dir["vlan"].Type, 0),
+		want: &RestrictionError{
+			Kind:         RangeIssue,
+			Value:        "0",
+			Restriction:  "1..4094",
+			ErrorMessage: "VLAN ID is out of range",
+			ErrorAppTag:  "vlan-range",
+		},
+	}, {
+		desc: "range of leaf type is resolved range",
+		in:   ValidateIntRestrictions(c.Dir["mtu"].Type, 42),
+		want: &RestrictionError{
+			Kind:        RangeIssue,
+			Value:       "42",
+			Restriction: "68..9000",
+		},
+	}, {
+		desc: "schema not parsed from YANG",
+		in: ValidateStringRestrictions(&yang.YangType{
+			Kind:    yang.Ystring,
+			Pattern: []string{"a.*"},
+		}, "b"),
+		want: &RestrictionError{
+			Kind:        PatternIssue,
+			Value:       "b",
+			Restriction: "a.*",
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var got *RestrictionError
+			if !errors.As(tt.in, &got) {
+				t.Fatalf("got error %v, want RestrictionError", tt.in)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreUnexported(RestrictionError{})); diff != "" {
+				t.Errorf("did not get expected RestrictionError (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
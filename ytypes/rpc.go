@@ -0,0 +1,140 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// rpcModule returns the name of the module that defines the namespace of
+// the GoStruct s, which represents the input or output statement described
+// by schema. An error is returned if schema does not describe an input or
+// output statement, or the module of s cannot be determined.
+func rpcModule(schema *yang.Entry, s ygot.GoStruct) (string, error) {
+	if schema == nil || !util.IsRPCInputOutput(schema) || schema.Parent == nil {
+		return "", fmt.Errorf("schema for %T is not an RPC or action input or output", s)
+	}
+	mod, err := ygot.BelongingModule(s)
+	switch {
+	case err != nil:
+		return "", fmt.Errorf("cannot determine the module of %T: %w", s, err)
+	case mod == "":
+		return "", fmt.Errorf("cannot determine the module of %T", s)
+	}
+	return mod, nil
+}
+
+// rpcJSON validates the GoStruct s against the supplied input or output
+// schema, and returns its RFC7951 JSON representation along with the name
+// of its module.
+func rpcJSON(schema *yang.Entry, s ygot.GoStruct, cfg *ygot.RFC7951JSONConfig) (map[string]any, string, error) {
+	mod, err := rpcModule(schema, s)
+	if err != nil {
+		return nil, "", err
+	}
+	if errs := Validate(schema, s); errs != nil {
+		return nil, "", fmt.Errorf("invalid %s for %s: %v", schema.Name, schema.Parent.Name, errs)
+	}
+	j, err := ygot.ConstructIETFJSON(s, cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	return j, mod, nil
+}
+
+// MarshalRPCJSON validates the GoStruct s, which represents the input or
+// output statement of an RPC or action described by schema, and returns it
+// as the RFC7951 JSON that is carried in the message body of a RESTCONF
+// operation invocation or its response, as described by RFC8040 section
+// 3.6. The data nodes of s are contained within a member named "input" or
+// "output", qualified by the module that defines the RPC or action, e.g.:
+//
+//	{"example-ops:input": {"delay": 600}}
+//
+// Members that are defined by other modules, such as those added by
+// augments, are qualified by their module only where cfg specifies that
+// module names are to be appended, as is required by RFC8040.
+func MarshalRPCJSON(schema *yang.Entry, s ygot.GoStruct, cfg *ygot.RFC7951JSONConfig) ([]byte, error) {
+	j, mod, err := rpcJSON(schema, s, cfg)
+	if err != nil {
+		return nil, err
+	}
+	// Members that are in the same namespace as the input or output
+	// member are not qualified by their module.
+	body := make(map[string]any, len(j))
+	for k, v := range j {
+		body[strings.TrimPrefix(k, mod+":")] = v
+	}
+	return json.Marshal(map[string]any{fmt.Sprintf("%s:%s", mod, schema.Name): body})
+}
+
+// UnmarshalRPCJSON unmarshals the RFC7951 JSON in the message body of a
+// RESTCONF operation invocation or its response, as returned by
+// MarshalRPCJSON, into the GoStruct s, which represents the input or output
+// statement of an RPC or action described by schema.
+func UnmarshalRPCJSON(schema *yang.Entry, s ygot.GoStruct, j []byte, opts ...UnmarshalOpt) error {
+	mod, err := rpcModule(schema, s)
+	if err != nil {
+		return err
+	}
+	var body map[string]any
+	if err := json.Unmarshal(j, &body); err != nil {
+		return fmt.Errorf("cannot unmarshal JSON for %s: %v", schema.Name, err)
+	}
+	name := fmt.Sprintf("%s:%s", mod, schema.Name)
+	v, ok := body[name]
+	if !ok || len(body) != 1 {
+		return fmt.Errorf("JSON for %s does not consist of a single member named %s", schema.Name, name)
+	}
+	return Unmarshal(schema, s, v, opts...)
+}
+
+// MarshalRPCUpdate validates the GoStruct s, which represents the input or
+// output statement of an RPC or action described by schema, and returns a
+// gNMI Update whose value is its JSON_IETF representation. The path of the
+// update is that of the input or output statement: for an action, parent
+// specifies the path of the data node on which the action is invoked,
+// including the keys of any lists, and for an RPC, parent must be nil or
+// empty.
+func MarshalRPCUpdate(schema *yang.Entry, s ygot.GoStruct, parent *gpb.Path, cfg *ygot.RFC7951JSONConfig) (*gpb.Update, error) {
+	j, _, err := rpcJSON(schema, s, cfg)
+	if err != nil {
+		return nil, err
+	}
+	js, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &gpb.Path{}
+	if parent != nil {
+		p.Origin = parent.Origin
+		p.Target = parent.Target
+		p.Elem = append(p.Elem, parent.Elem...)
+	}
+	p.Elem = append(p.Elem, &gpb.PathElem{Name: schema.Parent.Name}, &gpb.PathElem{Name: schema.Name})
+	return &gpb.Update{
+		Path: p,
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: js}},
+	}, nil
+}
@@ -0,0 +1,214 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+type rebootInput struct {
+	Delay   *uint32 `path:"delay" module:"ops"`
+	Message *string `path:"message" module:"ops"`
+	Extra   *string `path:"extra" module:"ops-augment"`
+}
+
+func (*rebootInput) IsYANGGoStruct()          {}
+func (*rebootInput) ΛBelongingModule() string { return "ops" }
+
+// rebootSchema returns the schema of an RPC named reboot, along with the
+// schema of its input statement.
+func rebootSchema() (*yang.Entry, *yang.Entry) {
+	rpc := &yang.Entry{
+		Name: "reboot",
+		Kind: yang.DirectoryEntry,
+		RPC:  &yang.RPCEntry{},
+	}
+	input := &yang.Entry{
+		Name:   "input",
+		Kind:   yang.InputEntry,
+		Parent: rpc,
+		Dir:    map[string]*yang.Entry{},
+	}
+	rpc.RPC.Input = input
+	input.Dir["delay"] = &yang.Entry{
+		Name:   "delay",
+		Kind:   yang.LeafEntry,
+		Parent: input,
+		Type: &yang.YangType{
+			Kind:  yang.Yuint32,
+			Range: yang.YangRange{{Min: yang.FromInt(0), Max: yang.FromInt(3600)}},
+		},
+	}
+	input.Dir["message"] = &yang.Entry{
+		Name:   "message",
+		Kind:   yang.LeafEntry,
+		Parent: input,
+		Type:   &yang.YangType{Kind: yang.Ystring},
+	}
+	input.Dir["extra"] = &yang.Entry{
+		Name:   "extra",
+		Kind:   yang.LeafEntry,
+		Parent: input,
+		Type:   &yang.YangType{Kind: yang.Ystring},
+	}
+	return rpc, input
+}
+
+func TestMarshalRPCJSON(t *testing.T) {
+	rpc, input := rebootSchema()
+	tests := []struct {
+		desc             string
+		inSchema         *yang.Entry
+		inStruct         ygot.GoStruct
+		want             string
+		wantErrSubstring string
+	}{{
+		desc:     "input",
+		inSchema: input,
+		inStruct: &rebootInput{Delay: ygot.Uint32(600), Message: ygot.String("maintenance")},
+		want:     `{"ops:input": {"delay": 600, "message": "maintenance"}}`,
+	}, {
+		desc:     "member from another module",
+		inSchema: input,
+		inStruct: &rebootInput{Extra: ygot.String("foo")},
+		want:     `{"ops:input": {"ops-augment:extra": "foo"}}`,
+	}, {
+		desc:     "empty input",
+		inSchema: input,
+		inStruct: &rebootInput{},
+		want:     `{"ops:input": {}}`,
+	}, {
+		desc:             "invalid input",
+		inSchema:         input,
+		inStruct:         &rebootInput{Delay: ygot.Uint32(7200)},
+		wantErrSubstring: "invalid input for reboot",
+	}, {
+		desc:             "schema is not an input or output",
+		inSchema:         rpc,
+		inStruct:         &rebootInput{},
+		wantErrSubstring: "is not an RPC or action input or output",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := MarshalRPCJSON(tt.inSchema, tt.inStruct, &ygot.RFC7951JSONConfig{AppendModuleName: true})
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("MarshalRPCJSON: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			var gotJSON, wantJSON any
+			if err := json.Unmarshal(got, &gotJSON); err != nil {
+				t.Fatalf("MarshalRPCJSON: cannot unmarshal output %s: %v", got, err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &wantJSON); err != nil {
+				t.Fatalf("cannot unmarshal want: %v", err)
+			}
+			if diff := cmp.Diff(wantJSON, gotJSON); diff != "" {
+				t.Errorf("MarshalRPCJSON: did not get expected JSON, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUnmarshalRPCJSON(t *testing.T) {
+	_, input := rebootSchema()
+	tests := []struct {
+		desc             string
+		inJSON           string
+		want             *rebootInput
+		wantErrSubstring string
+	}{{
+		desc:   "input",
+		inJSON: `{"ops:input": {"delay": 600, "ops-augment:extra": "foo"}}`,
+		want:   &rebootInput{Delay: ygot.Uint32(600), Extra: ygot.String("foo")},
+	}, {
+		desc:             "unqualified member",
+		inJSON:           `{"input": {"delay": 600}}`,
+		wantErrSubstring: "single member named ops:input",
+	}, {
+		desc:             "invalid value",
+		inJSON:           `{"ops:input": {"delay": "six hundred"}}`,
+		wantErrSubstring: "got string type for field delay",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := &rebootInput{}
+			err := UnmarshalRPCJSON(input, got, []byte(tt.inJSON))
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("UnmarshalRPCJSON: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("UnmarshalRPCJSON: did not get expected struct, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMarshalRPCUpdate(t *testing.T) {
+	_, input := rebootSchema()
+	tests := []struct {
+		desc             string
+		inStruct         ygot.GoStruct
+		inParent         *gpb.Path
+		want             *gpb.Update
+		wantErrSubstring string
+	}{{
+		desc:     "rpc",
+		inStruct: &rebootInput{Delay: ygot.Uint32(600)},
+		want: &gpb.Update{
+			Path: mustPath("/reboot/input"),
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`{"delay":600}`)}},
+		},
+	}, {
+		desc:     "action on list entry",
+		inStruct: &rebootInput{Delay: ygot.Uint32(600)},
+		inParent: mustPath("/components/component[name=linecard0]"),
+		want: &gpb.Update{
+			Path: mustPath("/components/component[name=linecard0]/reboot/input"),
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`{"delay":600}`)}},
+		},
+	}, {
+		desc:             "invalid input",
+		inStruct:         &rebootInput{Delay: ygot.Uint32(7200)},
+		wantErrSubstring: "invalid input for reboot",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := MarshalRPCUpdate(input, tt.inStruct, tt.inParent, nil)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("MarshalRPCUpdate: did not get expected error, %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("MarshalRPCUpdate: did not get expected update, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,581 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// The types within this file are a compact representation of the fields of
+// a yang.Entry tree that are retained when the schema is serialised to JSON
+// by the code generator, and which can be encoded using encoding/gob. Maps
+// are represented as slices sorted by key, such that the encoding of a
+// schema is deterministic.
+
+// binaryEntry is the serialised form of a yang.Entry.
+type binaryEntry struct {
+	Name        string
+	Description string
+	Default     []string
+	Units       string
+	Kind        yang.EntryKind
+	Config      yang.TriState
+	Mandatory   yang.TriState
+	Prefix      *binaryValue
+	Dir         []*binaryEntry
+	Key         string
+	Type        *binaryType
+	Exts        []*binaryStatement
+	ListAttr    *binaryListAttr
+	RPC         *binaryRPC
+	Identities  []*binaryIdentity
+	Augments    []*binaryEntry
+	Augmented   []*binaryEntry
+	Extra       []*binaryExtra
+	Annotation  []*binaryAnnotation
+}
+
+// binaryType is the serialised form of a yang.YangType.
+type binaryType struct {
+	Name             string
+	Kind             yang.TypeKind
+	IdentityBase     *binaryIdentity
+	Bit              []*binaryEnumValue
+	Enum             []*binaryEnumValue
+	HasBit           bool
+	HasEnum          bool
+	Units            string
+	Default          string
+	HasDefault       bool
+	FractionDigits   int
+	Length           yang.YangRange
+	OptionalInstance bool
+	Path             string
+	Pattern          []string
+	POSIXPattern     []string
+	Range            yang.YangRange
+	Type             []*binaryType
+}
+
+// binaryEnumValue is the serialised form of a value of a yang.EnumType.
+type binaryEnumValue struct {
+	Name  string
+	Value int64
+}
+
+// binaryIdentity is the serialised form of a yang.Identity.
+type binaryIdentity struct {
+	Name   string
+	Values []*binaryIdentity
+}
+
+// binaryValue is the serialised form of a yang.Value.
+type binaryValue struct {
+	Name   string
+	Source *binaryStatement
+}
+
+// binaryStatement is the serialised form of a yang.Statement.
+type binaryStatement struct {
+	Keyword     string
+	HasArgument bool
+	Argument    string
+}
+
+// binaryListAttr is the serialised form of a yang.ListAttr.
+type binaryListAttr struct {
+	MinElements   uint64
+	MaxElements   uint64
+	OrderedBy     *binaryValue
+	OrderedByUser bool
+}
+
+// binaryRPC is the serialised form of a yang.RPCEntry.
+type binaryRPC struct {
+	Input  *binaryEntry
+	Output *binaryEntry
+}
+
+// binaryExtra is the serialised form of an entry of the Extra map of a
+// yang.Entry. Each value is represented by its name, since the values are
+// not otherwise used at runtime.
+type binaryExtra struct {
+	Key    string
+	Values []string
+}
+
+// binaryAnnotation is the serialised form of an entry of the Annotation map
+// of a yang.Entry. Values of a basic Go type are stored in Value, and other
+// values are stored as JSON, such that they are loaded in the same form as
+// when the schema is serialised to JSON.
+type binaryAnnotation struct {
+	Key   string
+	Value any
+	JSON  []byte
+}
+
+// MarshalSchemaBinary serialises the schema tree rooted at root to a compact,
+// gzip compressed, binary representation that can be loaded using
+// LoadSchemaBinary. It is intended to be used by code generators as an
+// alternative to serialising the schema to JSON, since the binary
+// representation is significantly faster to load.
+//
+// Only the fields of the yang.Entry that are retained when the schema is
+// serialised to JSON are serialised, with the exception of the groupings
+// that were used by each entry. Annotations are loaded in the same form
+// as when they are serialised to JSON, and the values stored within the Extra
+// field of each entry are retained only by name.
+func MarshalSchemaBinary(root *yang.Entry) ([]byte, error) {
+	be, err := toBinaryEntry(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	gzw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if err := gob.NewEncoder(gzw).Encode(be); err != nil {
+		return nil, fmt.Errorf("cannot encode schema, %v", err)
+	}
+	if err := gzw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// LoadSchemaBinary loads a schema that was serialised by MarshalSchemaBinary,
+// returning it as a map of yang.Entry nodes, keyed by the name of the struct
+// that the yang.Entry describes the schema for, in the same form as
+// ygot.GzipToSchema.
+func LoadSchemaBinary(b []byte) (map[string]*yang.Entry, error) {
+	gzr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer gzr.Close()
+
+	be := &binaryEntry{}
+	if err := gob.NewDecoder(gzr).Decode(be); err != nil {
+		return nil, fmt.Errorf("cannot decode schema, %v", err)
+	}
+
+	schema := map[string]*yang.Entry{}
+	if _, err := fromBinaryEntry(be, nil, schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// toBinaryEntry returns the serialised form of the yang.Entry e, and its
+// descendants.
+func toBinaryEntry(e *yang.Entry) (*binaryEntry, error) {
+	if e == nil {
+		return nil, nil
+	}
+	be := &binaryEntry{
+		Name:        e.Name,
+		Description: e.Description,
+		Default:     e.Default,
+		Units:       e.Units,
+		Kind:        e.Kind,
+		Config:      e.Config,
+		Mandatory:   e.Mandatory,
+		Prefix:      toBinaryValue(e.Prefix),
+		Key:         e.Key,
+		Type:        toBinaryType(e.Type),
+	}
+
+	for _, k := range sortedKeys(e.Dir) {
+		ch, err := toBinaryEntry(e.Dir[k])
+		if err != nil {
+			return nil, err
+		}
+		// The key of a child within the Dir map is always its name.
+		ch.Name = k
+		be.Dir = append(be.Dir, ch)
+	}
+
+	for _, s := range e.Exts {
+		be.Exts = append(be.Exts, toBinaryStatement(s))
+	}
+
+	if e.ListAttr != nil {
+		be.ListAttr = &binaryListAttr{
+			MinElements:   e.ListAttr.MinElements,
+			MaxElements:   e.ListAttr.MaxElements,
+			OrderedBy:     toBinaryValue(e.ListAttr.OrderedBy),
+			OrderedByUser: e.ListAttr.OrderedByUser,
+		}
+	}
+
+	if e.RPC != nil {
+		in, err := toBinaryEntry(e.RPC.Input)
+		if err != nil {
+			return nil, err
+		}
+		out, err := toBinaryEntry(e.RPC.Output)
+		if err != nil {
+			return nil, err
+		}
+		be.RPC = &binaryRPC{Input: in, Output: out}
+	}
+
+	for _, i := range e.Identities {
+		be.Identities = append(be.Identities, toBinaryIdentity(i))
+	}
+
+	for _, a := range e.Augments {
+		ba, err := toBinaryEntry(a)
+		if err != nil {
+			return nil, err
+		}
+		be.Augments = append(be.Augments, ba)
+	}
+
+	for _, a := range e.Augmented {
+		ba, err := toBinaryEntry(a)
+		if err != nil {
+			return nil, err
+		}
+		be.Augmented = append(be.Augmented, ba)
+	}
+
+	for _, k := range sortedKeys(e.Extra) {
+		x := &binaryExtra{Key: k}
+		for _, v := range e.Extra[k] {
+			x.Values = append(x.Values, extraName(v))
+		}
+		be.Extra = append(be.Extra, x)
+	}
+
+	for _, k := range sortedKeys(e.Annotation) {
+		switch v := e.Annotation[k].(type) {
+		case string, bool, float64:
+			be.Annotation = append(be.Annotation, &binaryAnnotation{Key: k, Value: v})
+		default:
+			j, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: cannot serialise annotation %s, %v", e.Path(), k, err)
+			}
+			be.Annotation = append(be.Annotation, &binaryAnnotation{Key: k, JSON: j})
+		}
+	}
+
+	return be, nil
+}
+
+// toBinaryType returns the serialised form of the yang.YangType t.
+func toBinaryType(t *yang.YangType) *binaryType {
+	if t == nil {
+		return nil
+	}
+	bt := &binaryType{
+		Name:             t.Name,
+		Kind:             t.Kind,
+		IdentityBase:     toBinaryIdentity(t.IdentityBase),
+		Bit:              toBinaryEnumValues(t.Bit),
+		Enum:             toBinaryEnumValues(t.Enum),
+		HasBit:           t.Bit != nil,
+		HasEnum:          t.Enum != nil,
+		Units:            t.Units,
+		Default:          t.Default,
+		HasDefault:       t.HasDefault,
+		FractionDigits:   t.FractionDigits,
+		Length:           t.Length,
+		OptionalInstance: t.OptionalInstance,
+		Path:             t.Path,
+		Pattern:          t.Pattern,
+		POSIXPattern:     t.POSIXPattern,
+		Range:            t.Range,
+	}
+	for _, st := range t.Type {
+		bt.Type = append(bt.Type, toBinaryType(st))
+	}
+	return bt
+}
+
+// toBinaryEnumValues returns the serialised form of the values of the
+// yang.EnumType e, sorted by value.
+func toBinaryEnumValues(e *yang.EnumType) []*binaryEnumValue {
+	if e == nil {
+		return nil
+	}
+	var vals []*binaryEnumValue
+	for n, v := range e.ToInt {
+		vals = append(vals, &binaryEnumValue{Name: n, Value: v})
+	}
+	sort.Slice(vals, func(i, j int) bool {
+		if vals[i].Value != vals[j].Value {
+			return vals[i].Value < vals[j].Value
+		}
+		return vals[i].Name < vals[j].Name
+	})
+	return vals
+}
+
+// toBinaryIdentity returns the serialised form of the yang.Identity i.
+func toBinaryIdentity(i *yang.Identity) *binaryIdentity {
+	if i == nil {
+		return nil
+	}
+	bi := &binaryIdentity{Name: i.Name}
+	for _, v := range i.Values {
+		bi.Values = append(bi.Values, toBinaryIdentity(v))
+	}
+	return bi
+}
+
+// fromBinaryEntry returns the yang.Entry described by be, whose parent is
+// parent. Each yang.Entry that is annotated with the name of a struct is
+// added to the schema map.
+func fromBinaryEntry(be *binaryEntry, parent *yang.Entry, schema map[string]*yang.Entry) (*yang.Entry, error) {
+	if be == nil {
+		return nil, nil
+	}
+	e := &yang.Entry{
+		Parent:      parent,
+		Name:        be.Name,
+		Description: be.Description,
+		Default:     be.Default,
+		Units:       be.Units,
+		Kind:        be.Kind,
+		Config:      be.Config,
+		Mandatory:   be.Mandatory,
+		Prefix:      fromBinaryValue(be.Prefix),
+		Key:         be.Key,
+		Type:        fromBinaryType(be.Type),
+	}
+
+	if len(be.Dir) != 0 {
+		e.Dir = make(map[string]*yang.Entry, len(be.Dir))
+		for _, ch := range be.Dir {
+			var err error
+			if e.Dir[ch.Name], err = fromBinaryEntry(ch, e, schema); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, s := range be.Exts {
+		e.Exts = append(e.Exts, fromBinaryStatement(s))
+	}
+
+	if be.ListAttr != nil {
+		e.ListAttr = &yang.ListAttr{
+			MinElements:   be.ListAttr.MinElements,
+			MaxElements:   be.ListAttr.MaxElements,
+			OrderedBy:     fromBinaryValue(be.ListAttr.OrderedBy),
+			OrderedByUser: be.ListAttr.OrderedByUser,
+		}
+	}
+
+	if be.RPC != nil {
+		in, err := fromBinaryEntry(be.RPC.Input, e, schema)
+		if err != nil {
+			return nil, err
+		}
+		out, err := fromBinaryEntry(be.RPC.Output, e, schema)
+		if err != nil {
+			return nil, err
+		}
+		e.RPC = &yang.RPCEntry{Input: in, Output: out}
+	}
+
+	for _, i := range be.Identities {
+		e.Identities = append(e.Identities, fromBinaryIdentity(i))
+	}
+
+	// Augments are not part of the schema tree, and hence are not linked to
+	// a parent or added to the schema map.
+	for _, ba := range be.Augments {
+		a, err := fromBinaryEntry(ba, nil, map[string]*yang.Entry{})
+		if err != nil {
+			return nil, err
+		}
+		e.Augments = append(e.Augments, a)
+	}
+
+	for _, ba := range be.Augmented {
+		a, err := fromBinaryEntry(ba, nil, map[string]*yang.Entry{})
+		if err != nil {
+			return nil, err
+		}
+		e.Augmented = append(e.Augmented, a)
+	}
+
+	if len(be.Extra) != 0 {
+		e.Extra = make(map[string][]any, len(be.Extra))
+		for _, x := range be.Extra {
+			vals := []any{}
+			for _, v := range x.Values {
+				vals = append(vals, &yang.Value{Name: v})
+			}
+			e.Extra[x.Key] = vals
+		}
+	}
+
+	if len(be.Annotation) != 0 {
+		e.Annotation = make(map[string]any, len(be.Annotation))
+		for _, a := range be.Annotation {
+			v := a.Value
+			if a.JSON != nil {
+				if err := json.Unmarshal(a.JSON, &v); err != nil {
+					return nil, fmt.Errorf("%s: cannot load annotation %s, %v", e.Path(), a.Key, err)
+				}
+			}
+			e.Annotation[a.Key] = v
+		}
+		if n, ok := e.Annotation["structname"].(string); ok {
+			schema[n] = e
+		}
+	}
+
+	return e, nil
+}
+
+// fromBinaryType returns the yang.YangType described by bt.
+func fromBinaryType(bt *binaryType) *yang.YangType {
+	if bt == nil {
+		return nil
+	}
+	t := &yang.YangType{
+		Name:             bt.Name,
+		Kind:             bt.Kind,
+		IdentityBase:     fromBinaryIdentity(bt.IdentityBase),
+		Units:            bt.Units,
+		Default:          bt.Default,
+		HasDefault:       bt.HasDefault,
+		FractionDigits:   bt.FractionDigits,
+		Length:           bt.Length,
+		OptionalInstance: bt.OptionalInstance,
+		Path:             bt.Path,
+		Pattern:          bt.Pattern,
+		POSIXPattern:     bt.POSIXPattern,
+		Range:            bt.Range,
+	}
+	if bt.HasBit {
+		t.Bit = fromBinaryEnumValues(bt.Bit)
+	}
+	if bt.HasEnum {
+		t.Enum = fromBinaryEnumValues(bt.Enum)
+	}
+	for _, st := range bt.Type {
+		t.Type = append(t.Type, fromBinaryType(st))
+	}
+	return t
+}
+
+// fromBinaryEnumValues returns a yang.EnumType containing the supplied
+// values.
+func fromBinaryEnumValues(vals []*binaryEnumValue) *yang.EnumType {
+	e := &yang.EnumType{
+		ToString: map[int64]string{},
+		ToInt:    map[string]int64{},
+	}
+	for _, v := range vals {
+		e.ToString[v.Value] = v.Name
+		e.ToInt[v.Name] = v.Value
+	}
+	return e
+}
+
+// fromBinaryIdentity returns the yang.Identity described by bi.
+func fromBinaryIdentity(bi *binaryIdentity) *yang.Identity {
+	if bi == nil {
+		return nil
+	}
+	i := &yang.Identity{Name: bi.Name}
+	for _, v := range bi.Values {
+		i.Values = append(i.Values, fromBinaryIdentity(v))
+	}
+	return i
+}
+
+// toBinaryValue returns the serialised form of the yang.Value v.
+func toBinaryValue(v *yang.Value) *binaryValue {
+	if v == nil {
+		return nil
+	}
+	return &binaryValue{Name: v.Name, Source: toBinaryStatement(v.Source)}
+}
+
+// fromBinaryValue returns the yang.Value described by bv.
+func fromBinaryValue(bv *binaryValue) *yang.Value {
+	if bv == nil {
+		return nil
+	}
+	return &yang.Value{Name: bv.Name, Source: fromBinaryStatement(bv.Source)}
+}
+
+// toBinaryStatement returns the serialised form of the yang.Statement s.
+// The substatements of s are not retained.
+func toBinaryStatement(s *yang.Statement) *binaryStatement {
+	if s == nil {
+		return nil
+	}
+	return &binaryStatement{
+		Keyword:     s.Keyword,
+		HasArgument: s.HasArgument,
+		Argument:    s.Argument,
+	}
+}
+
+// fromBinaryStatement returns the yang.Statement described by bs.
+func fromBinaryStatement(bs *binaryStatement) *yang.Statement {
+	if bs == nil {
+		return nil
+	}
+	return &yang.Statement{
+		Keyword:     bs.Keyword,
+		HasArgument: bs.HasArgument,
+		Argument:    bs.Argument,
+	}
+}
+
+// extraName returns the name of a value stored within the Extra field of a
+// yang.Entry.
+func extraName(v any) string {
+	switch v := v.(type) {
+	case *yang.Value:
+		return v.Name
+	case yang.Node:
+		return v.NName()
+	case map[string]any:
+		// Values that were unmarshalled from JSON.
+		if n, ok := v["Name"].(string); ok {
+			return n
+		}
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// sortedKeys returns the keys of the map m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	var ks []string
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/integration_tests/schemaops/ctestschema"
+	"github.com/openconfig/ygot/ytypes"
+)
+
+func TestSchemaBinaryRoundTrip(t *testing.T) {
+	want, err := ctestschema.UnzipSchema()
+	if err != nil {
+		t.Fatalf("cannot unzip schema: %v", err)
+	}
+
+	b, err := ytypes.MarshalSchemaBinary(want["Device"])
+	if err != nil {
+		t.Fatalf("MarshalSchemaBinary: got unexpected error: %v", err)
+	}
+	// The serialised schema must be deterministic.
+	b2, err := ytypes.MarshalSchemaBinary(want["Device"])
+	if err != nil {
+		t.Fatalf("MarshalSchemaBinary: got unexpected error: %v", err)
+	}
+	if !bytes.Equal(b, b2) {
+		t.Errorf("MarshalSchemaBinary: serialised schema is not deterministic")
+	}
+
+	got, err := ytypes.LoadSchemaBinary(b)
+	if err != nil {
+		t.Fatalf("LoadSchemaBinary: got unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff(want, got,
+		// Parents are checked by path below, and the values of Extra are
+		// retained only by name.
+		cmpopts.IgnoreFields(yang.Entry{}, "Parent", "Extra"),
+		cmpopts.IgnoreUnexported(yang.Entry{}, yang.EnumType{}, yang.Statement{}),
+	); diff != "" {
+		t.Errorf("LoadSchemaBinary: did not get expected schema (-want, +got):\n%s", diff)
+	}
+	for n, e := range want {
+		if got, want := got[n].Path(), e.Path(); got != want {
+			t.Errorf("LoadSchemaBinary: got path %s for %s, want %s", got, n, want)
+		}
+	}
+}
+
+func TestSchemaBinaryErrors(t *testing.T) {
+	_, err := ytypes.MarshalSchemaBinary(&yang.Entry{
+		Name:       "root",
+		Annotation: map[string]any{"invalid": make(chan int)},
+	})
+	if diff := errdiff.Substring(err, "cannot serialise annotation invalid"); diff != "" {
+		t.Errorf("MarshalSchemaBinary: %s", diff)
+	}
+
+	if _, err := ytypes.LoadSchemaBinary([]byte("not a schema")); err == nil {
+		t.Errorf("LoadSchemaBinary: did not get expected error for invalid input")
+	}
+}
@@ -0,0 +1,395 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/util"
+)
+
+// LazySchemaIndex describes how the GoStructs of a generated schema map to
+// the directories that are the children of the schema root. It is computed
+// by the code generator such that a LazySchema can determine which parts of
+// the schema must be unmarshalled to return the schema of a particular
+// GoStruct.
+type LazySchemaIndex struct {
+	// Structs maps the name of each GoStruct to the name of the child of
+	// the schema root that contains its schema. The GoStruct that
+	// corresponds to the schema root itself is mapped to the empty string.
+	Structs map[string]string
+	// Deps maps the name of a child of the schema root to the names of the
+	// other children of the root that must be loaded alongside it, since
+	// leafrefs within it refer to them.
+	Deps map[string][]string
+}
+
+// NewLazySchemaIndex returns the LazySchemaIndex for the schema tree with
+// the supplied root, which is the root entry of a schema serialised by the
+// code generator. The Parent fields of the entries within the tree are
+// populated as a side-effect.
+func NewLazySchemaIndex(root *yang.Entry) (*LazySchemaIndex, error) {
+	if root == nil {
+		return nil, fmt.Errorf("nil schema root")
+	}
+	idx := &LazySchemaIndex{
+		Structs: map[string]string{},
+		Deps:    map[string][]string{},
+	}
+	if n, ok := structName(root); ok {
+		idx.Structs[n] = ""
+	}
+	root.Parent = nil
+	for name, ch := range root.Dir {
+		deps := map[string]bool{}
+		if err := indexLazySchemaEntry(idx, name, ch, root, deps); err != nil {
+			return nil, err
+		}
+		delete(deps, name)
+		if len(deps) == 0 {
+			continue
+		}
+		for d := range deps {
+			if _, ok := root.Dir[d]; !ok {
+				return nil, fmt.Errorf("leafref within %s refers to unknown root child %s", name, d)
+			}
+			idx.Deps[name] = append(idx.Deps[name], d)
+		}
+		sort.Strings(idx.Deps[name])
+	}
+	return idx, nil
+}
+
+// indexLazySchemaEntry adds e, which is within the child of the schema root
+// named top, and its children to the supplied index. The names of the
+// children of the schema root that are referred to by leafrefs within e are
+// added to deps.
+func indexLazySchemaEntry(idx *LazySchemaIndex, top string, e, parent *yang.Entry, deps map[string]bool) error {
+	e.Parent = parent
+	if n, ok := structName(e); ok {
+		idx.Structs[n] = top
+	}
+	if e.Type != nil {
+		if err := leafrefRootChildren(e, e.Type, deps); err != nil {
+			return err
+		}
+	}
+	for _, ch := range e.Dir {
+		if err := indexLazySchemaEntry(idx, top, ch, e, deps); err != nil {
+			return err
+		}
+	}
+	for _, io := range util.RPCInputOutput(e) {
+		if err := indexLazySchemaEntry(idx, top, io, e, deps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// leafrefRootChildren adds the name of the child of the schema root that
+// contains the target of each leafref within the type t of the leaf e to
+// deps. Union types are examined recursively.
+func leafrefRootChildren(e *yang.Entry, t *yang.YangType, deps map[string]bool) error {
+	for _, ut := range t.Type {
+		if err := leafrefRootChildren(e, ut, deps); err != nil {
+			return err
+		}
+	}
+	if t.Kind != yang.Yleafref {
+		return nil
+	}
+
+	parts := strings.Split(t.Path, "/")
+	if strings.HasPrefix(t.Path, "/") {
+		if len(parts) < 2 {
+			return fmt.Errorf("invalid leafref path %q in %s", t.Path, e.Path())
+		}
+		deps[stripLeafrefElem(parts[1])] = true
+		return nil
+	}
+
+	n := e
+	for i, p := range parts {
+		if p != ".." {
+			return nil
+		}
+		// Choice and case statements do not appear in leafref paths.
+		for n = n.Parent; n != nil && (n.IsChoice() || n.IsCase()); n = n.Parent {
+		}
+		switch {
+		case n == nil:
+			return fmt.Errorf("leafref path %q in %s is above the schema root", t.Path, e.Path())
+		case n.Parent == nil:
+			// The path leads to the schema root, such that the next
+			// element names a child of the root.
+			if i+1 >= len(parts) {
+				return fmt.Errorf("invalid leafref path %q in %s", t.Path, e.Path())
+			}
+			deps[stripLeafrefElem(parts[i+1])] = true
+			return nil
+		}
+	}
+	return nil
+}
+
+// stripLeafrefElem returns the name of the schema node referred to by the
+// element p of a leafref path, removing any prefix and predicates.
+func stripLeafrefElem(p string) string {
+	if i := strings.Index(p, "["); i != -1 {
+		p = p[:i]
+	}
+	if i := strings.Index(p, ":"); i != -1 {
+		p = p[i+1:]
+	}
+	return strings.TrimSpace(p)
+}
+
+// structName returns the name of the GoStruct that is annotated on the
+// supplied entry, if any.
+func structName(e *yang.Entry) (string, bool) {
+	n, ok := e.Annotation["structname"].(string)
+	return n, ok
+}
+
+// LazySchema is a schema serialised by the code generator as gzip compressed
+// JSON, which is decompressed and unmarshalled only when it is first used.
+// Each child of the schema root, and hence the schema of the GoStructs within
+// it, is unmarshalled only when the schema of one of those GoStructs is
+// first requested, such that programs that only make use of a small subset of
+// a large schema do not pay the cost of unmarshalling all of it.
+//
+// The entries returned by a LazySchema are memoised and must not be modified.
+// It is safe to use a LazySchema concurrently from multiple goroutines.
+type LazySchema struct {
+	gzj []byte
+	idx *LazySchemaIndex
+
+	mu sync.Mutex
+	// raw contains the serialised children of the schema root, and is nil
+	// until the schema has been decompressed.
+	raw map[string]json.RawMessage
+	// root is the most recently created root entry, whose Dir contains
+	// all of the children of the root that have been loaded.
+	root *yang.Entry
+	// tree contains the entries that have been loaded, keyed by the name
+	// of their GoStruct.
+	tree map[string]*yang.Entry
+	// complete indicates that the entire schema has been loaded.
+	complete bool
+}
+
+// NewLazySchema returns a LazySchema for the supplied gzip compressed JSON
+// schema, whose GoStructs are described by idx. No work is performed until
+// the schema is first used.
+func NewLazySchema(gzj []byte, idx *LazySchemaIndex) *LazySchema {
+	return &LazySchema{gzj: gzj, idx: idx}
+}
+
+// Entry returns the schema of the GoStruct with the supplied name, loading
+// the part of the schema that contains it, along with those parts that it
+// refers to, if it has not already been loaded.
+func (l *LazySchema) Entry(name string) (*yang.Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e, ok := l.tree[name]; ok {
+		return e, nil
+	}
+	top, ok := l.idx.Structs[name]
+	if !ok {
+		return nil, fmt.Errorf("could not find schema for type %s", name)
+	}
+	if top == "" {
+		if err := l.loadAll(); err != nil {
+			return nil, err
+		}
+	} else if err := l.load(top); err != nil {
+		return nil, err
+	}
+	e, ok := l.tree[name]
+	if !ok {
+		return nil, fmt.Errorf("could not find schema for type %s", name)
+	}
+	return e, nil
+}
+
+// SchemaTree loads the entire schema, and returns a map of yang.Entry nodes,
+// keyed by the name of the struct that the yang.Entry describes the schema
+// for.
+func (l *LazySchema) SchemaTree() (map[string]*yang.Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.loadAll(); err != nil {
+		return nil, err
+	}
+	return l.tree, nil
+}
+
+// loadAll loads all of the children of the schema root. It must be called
+// with l.mu held.
+func (l *LazySchema) loadAll() error {
+	if l.complete {
+		return nil
+	}
+	if err := l.decompress(); err != nil {
+		return err
+	}
+	var names []string
+	for n := range l.raw {
+		names = append(names, n)
+	}
+	if err := l.loadChildren(names); err != nil {
+		return err
+	}
+	if n, ok := structName(l.root); ok {
+		l.tree[n] = l.root
+	}
+	l.complete = true
+	return nil
+}
+
+// load loads the child of the schema root with the supplied name, and those
+// children that it depends upon. It must be called with l.mu held.
+func (l *LazySchema) load(top string) error {
+	if err := l.decompress(); err != nil {
+		return err
+	}
+	var names []string
+	seen := map[string]bool{}
+	var visit func(string)
+	visit = func(n string) {
+		if seen[n] {
+			return
+		}
+		seen[n] = true
+		names = append(names, n)
+		for _, d := range l.idx.Deps[n] {
+			visit(d)
+		}
+	}
+	visit(top)
+	return l.loadChildren(names)
+}
+
+// loadChildren unmarshals the children of the schema root with the supplied
+// names that have not already been loaded. It must be called with l.mu held.
+//
+// Entries that have previously been returned may be in use by other
+// goroutines, and hence are never modified. Rather, a new root entry is
+// created whose Dir contains both the previously loaded children and the new
+// ones, which become the children of the new root. Since the children that
+// are loaded together include all of those that they refer to, the
+// root that is reachable from any entry contains all of the children that
+// its leafrefs may traverse to.
+func (l *LazySchema) loadChildren(names []string) error {
+	newCh := map[string]*yang.Entry{}
+	for _, n := range names {
+		if _, ok := l.root.Dir[n]; ok {
+			continue
+		}
+		rc, ok := l.raw[n]
+		if !ok {
+			return fmt.Errorf("schema root does not contain %s", n)
+		}
+		e := &yang.Entry{}
+		if err := json.Unmarshal(rc, e); err != nil {
+			return fmt.Errorf("could not unmarshal schema for %s: %v", n, err)
+		}
+		newCh[n] = e
+	}
+	if len(newCh) == 0 {
+		return nil
+	}
+
+	root := *l.root
+	root.Dir = make(map[string]*yang.Entry, len(l.root.Dir)+len(newCh))
+	for n, e := range l.root.Dir {
+		root.Dir[n] = e
+	}
+	for n, e := range newCh {
+		root.Dir[n] = e
+		addLazySchemaEntry(e, &root, l.tree)
+	}
+	l.root = &root
+	return nil
+}
+
+// addLazySchemaEntry sets the parent of e, and adds e and its children to
+// the supplied tree, keyed by the name of their GoStruct.
+func addLazySchemaEntry(e, parent *yang.Entry, tree map[string]*yang.Entry) {
+	e.Parent = parent
+	if n, ok := structName(e); ok {
+		tree[n] = e
+	}
+	for _, ch := range e.Dir {
+		addLazySchemaEntry(ch, e, tree)
+	}
+	for _, io := range util.RPCInputOutput(e) {
+		addLazySchemaEntry(io, e, tree)
+	}
+}
+
+// decompress decompresses the schema, and unmarshals its root entry, such
+// that its children are retained in serialised form. It must be called with
+// l.mu held.
+func (l *LazySchema) decompress() error {
+	if l.raw != nil {
+		return nil
+	}
+	gzr, err := gzip.NewReader(bytes.NewReader(l.gzj))
+	if err != nil {
+		return fmt.Errorf("could not decompress schema: %v", err)
+	}
+	defer gzr.Close()
+	s, err := io.ReadAll(gzr)
+	if err != nil {
+		return fmt.Errorf("could not decompress schema: %v", err)
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(s, &fields); err != nil {
+		return fmt.Errorf("could not unmarshal schema root: %v", err)
+	}
+	raw := map[string]json.RawMessage{}
+	if d, ok := fields["Dir"]; ok {
+		if err := json.Unmarshal(d, &raw); err != nil {
+			return fmt.Errorf("could not unmarshal schema root: %v", err)
+		}
+		delete(fields, "Dir")
+	}
+	rs, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("could not unmarshal schema root: %v", err)
+	}
+	root := &yang.Entry{}
+	if err := json.Unmarshal(rs, root); err != nil {
+		return fmt.Errorf("could not unmarshal schema root: %v", err)
+	}
+	// The root is a directory, even if it has no children.
+	root.Dir = map[string]*yang.Entry{}
+
+	l.raw = raw
+	l.root = root
+	l.tree = map[string]*yang.Entry{}
+	return nil
+}
@@ -0,0 +1,251 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// lazyTestSchema returns a schema of the form serialised by the code
+// generator, in which the child b of the root refers to a using an absolute
+// leafref, c refers to a using a relative leafref, and d has no leafrefs.
+func lazyTestSchema() *yang.Entry {
+	dir := func(name, sn string, ch ...*yang.Entry) *yang.Entry {
+		e := &yang.Entry{
+			Name:       name,
+			Kind:       yang.DirectoryEntry,
+			Dir:        map[string]*yang.Entry{},
+			Annotation: map[string]any{"structname": sn},
+		}
+		for _, c := range ch {
+			e.Dir[c.Name] = c
+		}
+		return e
+	}
+	leaf := func(name string, t *yang.YangType) *yang.Entry {
+		return &yang.Entry{Name: name, Kind: yang.LeafEntry, Type: t}
+	}
+
+	root := dir("device", "Device",
+		dir("a", "A", leaf("x", &yang.YangType{Kind: yang.Ystring})),
+		dir("b", "B", leaf("y", &yang.YangType{Kind: yang.Yleafref, Path: "/pfx:a/pfx:x"})),
+		dir("c", "C", dir("inner", "C_Inner", leaf("z", &yang.YangType{
+			Kind: yang.Yunion,
+			Type: []*yang.YangType{
+				{Kind: yang.Yleafref, Path: "../../../a/x"},
+				{Kind: yang.Yleafref, Path: "../../inner/z"},
+			},
+		}))),
+		dir("d", "D", leaf("w", &yang.YangType{Kind: yang.Yint8})),
+	)
+	root.Annotation["isFakeRoot"] = true
+	return root
+}
+
+func gzipLazyTestSchema(t *testing.T) []byte {
+	t.Helper()
+	js, err := json.Marshal(lazyTestSchema())
+	if err != nil {
+		t.Fatalf("cannot marshal schema: %v", err)
+	}
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	if _, err := w.Write(js); err != nil {
+		t.Fatalf("cannot compress schema: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("cannot compress schema: %v", err)
+	}
+	return b.Bytes()
+}
+
+func TestNewLazySchemaIndex(t *testing.T) {
+	got, err := NewLazySchemaIndex(lazyTestSchema())
+	if err != nil {
+		t.Fatalf("NewLazySchemaIndex: got unexpected error: %v", err)
+	}
+	want := &LazySchemaIndex{
+		Structs: map[string]string{
+			"Device":  "",
+			"A":       "a",
+			"B":       "b",
+			"C":       "c",
+			"C_Inner": "c",
+			"D":       "d",
+		},
+		Deps: map[string][]string{
+			"b": {"a"},
+			"c": {"a"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("NewLazySchemaIndex: did not get expected index (-want, +got):\n%s", diff)
+	}
+
+	bad := lazyTestSchema()
+	bad.Dir["d"].Dir["w"].Type = &yang.YangType{Kind: yang.Yleafref, Path: "/e/f"}
+	if _, err := NewLazySchemaIndex(bad); err == nil {
+		t.Errorf("NewLazySchemaIndex: did not get expected error for leafref to unknown root child")
+	}
+}
+
+func TestLazySchema(t *testing.T) {
+	idx, err := NewLazySchemaIndex(lazyTestSchema())
+	if err != nil {
+		t.Fatalf("NewLazySchemaIndex: got unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		desc             string
+		inNames          []string
+		wantRootDir      []string
+		wantErrSubstring string
+	}{{
+		desc:        "nothing loaded",
+		wantRootDir: nil,
+	}, {
+		desc:        "child without dependencies",
+		inNames:     []string{"D"},
+		wantRootDir: []string{"d"},
+	}, {
+		desc:        "nested child with dependencies",
+		inNames:     []string{"C_Inner"},
+		wantRootDir: []string{"a", "c"},
+	}, {
+		desc:        "multiple children",
+		inNames:     []string{"B", "D"},
+		wantRootDir: []string{"a", "b", "d"},
+	}, {
+		desc:        "root",
+		inNames:     []string{"Device"},
+		wantRootDir: []string{"a", "b", "c", "d"},
+	}, {
+		desc:             "unknown struct",
+		inNames:          []string{"Unknown"},
+		wantErrSubstring: "could not find schema for type Unknown",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			l := NewLazySchema(gzipLazyTestSchema(t), idx)
+			for _, n := range tt.inNames {
+				e, err := l.Entry(n)
+				if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+					t.Fatalf("Entry(%s): %s", n, diff)
+				}
+				if err != nil {
+					return
+				}
+				if got, _ := structName(e); got != n {
+					t.Errorf("Entry(%s): got entry for %s", n, got)
+				}
+				// The root that is reachable from the entry must
+				// contain the entries it depends on.
+				r := e
+				for r.Parent != nil {
+					r = r.Parent
+				}
+				if r != l.root && n != "Device" {
+					for _, d := range idx.Deps[idx.Structs[n]] {
+						if r.Dir[d] == nil {
+							t.Errorf("Entry(%s): root does not contain dependency %s", n, d)
+						}
+					}
+				}
+			}
+
+			var got []string
+			if l.root != nil {
+				for n := range l.root.Dir {
+					got = append(got, n)
+				}
+			}
+			sort.Strings(got)
+			if diff := cmp.Diff(tt.wantRootDir, got); diff != "" {
+				t.Errorf("did not get expected loaded children (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLazySchemaSchemaTree(t *testing.T) {
+	idx, err := NewLazySchemaIndex(lazyTestSchema())
+	if err != nil {
+		t.Fatalf("NewLazySchemaIndex: got unexpected error: %v", err)
+	}
+	l := NewLazySchema(gzipLazyTestSchema(t), idx)
+
+	b, err := l.Entry("B")
+	if err != nil {
+		t.Fatalf("Entry(B): got unexpected error: %v", err)
+	}
+	tree, err := l.SchemaTree()
+	if err != nil {
+		t.Fatalf("SchemaTree: got unexpected error: %v", err)
+	}
+	var got []string
+	for n := range tree {
+		got = append(got, n)
+	}
+	sort.Strings(got)
+	if diff := cmp.Diff([]string{"A", "B", "C", "C_Inner", "D", "Device"}, got); diff != "" {
+		t.Errorf("SchemaTree: did not get expected structs (-want, +got):\n%s", diff)
+	}
+	if tree["B"] != b {
+		t.Errorf("SchemaTree: entry for B was not memoised")
+	}
+	if tree["Device"].Dir["b"] != b || tree["C_Inner"].Parent != tree["C"] {
+		t.Errorf("SchemaTree: did not get expected tree structure")
+	}
+}
+
+func TestLazySchemaConcurrent(t *testing.T) {
+	idx, err := NewLazySchemaIndex(lazyTestSchema())
+	if err != nil {
+		t.Fatalf("NewLazySchemaIndex: got unexpected error: %v", err)
+	}
+	l := NewLazySchema(gzipLazyTestSchema(t), idx)
+
+	names := []string{"A", "B", "C", "C_Inner", "D", "Device"}
+	var wg sync.WaitGroup
+	entries := make([]*yang.Entry, len(names)*10)
+	for i := range entries {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			e, err := l.Entry(names[i%len(names)])
+			if err != nil {
+				t.Errorf("Entry(%s): got unexpected error: %v", names[i%len(names)], err)
+			}
+			entries[i] = e
+		}(i)
+	}
+	wg.Wait()
+
+	for i, e := range entries {
+		if want := entries[i%len(names)]; e != want {
+			t.Errorf("Entry(%s): got different entries for concurrent calls", names[i%len(names)])
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/util"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// SchemaLeaf describes a leaf or leaf-list within a schema tree.
+type SchemaLeaf struct {
+	// Path is the path of the leaf within the data tree. The keys of
+	// lists that are not specified by the path supplied to SchemaLeaves
+	// are wildcards ("*").
+	Path *gpb.Path
+	// Schema is the schema entry of the leaf.
+	Schema *yang.Entry
+	// Type is the YANG type of the leaf. For a leafref, it is the type of
+	// the leaf that it references.
+	Type *yang.YangType
+}
+
+// SchemaLeaves returns the leaves and leaf-lists within the supplied schema
+// tree that are at, or are descendants of, the supplied path, which is
+// relative to the root of the schema. It requires no data tree, and is
+// intended for uses such as building the paths of subscriptions.
+//
+// The returned paths are those of the data tree, and hence do not contain
+// choice or case statements, and are the same for a schema tree generated
+// with or without compression, since the schema of a compressed GoStruct
+// retains the config and state containers and the containers surrounding
+// lists. The leaves are returned in the depth-first order of their paths,
+// sorted by name at each level. Both config true and config false leaves
+// are returned; the ReadOnly method of their schema distinguishes them.
+//
+// An error is returned if the path does not exist within the schema, or the
+// type of a leafref cannot be resolved.
+func SchemaLeaves(schema *yang.Entry, path *gpb.Path) ([]*SchemaLeaf, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("nil schema for path %s", pathStringOrErr(path))
+	}
+
+	e := schema
+	var elems []*gpb.PathElem
+	for _, pe := range path.GetElem() {
+		if e = dataChild(e, util.StripModulePrefix(pe.GetName())); e == nil {
+			return nil, fmt.Errorf("cannot find schema for path %s", pathStringOrErr(path))
+		}
+		elems = append(elems, schemaPathElem(e, pe.GetKey()))
+	}
+
+	var leaves []*SchemaLeaf
+	if err := appendSchemaLeaves(&leaves, e, elems, path); err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+// appendSchemaLeaves appends the leaves at, or descending from, the schema
+// entry e, which is at the path described by elems, to leaves. The origin and
+// target of the returned paths are those of the supplied path.
+func appendSchemaLeaves(leaves *[]*SchemaLeaf, e *yang.Entry, elems []*gpb.PathElem, path *gpb.Path) error {
+	if !e.IsDir() {
+		t := e.Type
+		if util.IsLeafRef(e) {
+			target, err := util.ResolveIfLeafRef(e)
+			if err != nil {
+				return fmt.Errorf("cannot resolve type of leaf %s: %v", e.Path(), err)
+			}
+			t = target.Type
+		}
+		*leaves = append(*leaves, &SchemaLeaf{
+			Path: &gpb.Path{
+				Origin: path.GetOrigin(),
+				Target: path.GetTarget(),
+				Elem:   append([]*gpb.PathElem{}, elems...),
+			},
+			Schema: e,
+			Type:   t,
+		})
+		return nil
+	}
+
+	children := dataChildren(e)
+	names := make([]string, 0, len(children))
+	for n := range children {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		c := children[n]
+		if c.RPC != nil {
+			continue
+		}
+		cElems := append(elems[:len(elems):len(elems)], schemaPathElem(c, nil))
+		if err := appendSchemaLeaves(leaves, c, cElems, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schemaPathElem returns the path element of the schema entry e. If e is a
+// keyed list, the path element has the supplied keys, and its keys that are
+// not supplied are wildcards.
+func schemaPathElem(e *yang.Entry, keys map[string]string) *gpb.PathElem {
+	pe := &gpb.PathElem{Name: e.Name}
+	if !util.IsKeyedList(e) {
+		return pe
+	}
+	pe.Key = map[string]string{}
+	for _, k := range strings.Fields(e.Key) {
+		v, ok := keys[k]
+		if !ok {
+			v = "*"
+		}
+		pe.Key[k] = v
+	}
+	return pe
+}
@@ -0,0 +1,166 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/exampleoc"
+	"github.com/openconfig/ygot/uexampleoc"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// choiceSchema returns a schema tree whose root has a choice, a leaf-list
+// and an RPC as children.
+func choiceSchema() *yang.Entry {
+	root := &yang.Entry{
+		Name: "device",
+		Kind: yang.DirectoryEntry,
+		Dir:  map[string]*yang.Entry{},
+	}
+	add := func(parent, e *yang.Entry) *yang.Entry {
+		e.Parent = parent
+		if parent.Dir == nil {
+			parent.Dir = map[string]*yang.Entry{}
+		}
+		parent.Dir[e.Name] = e
+		return e
+	}
+	choice := add(root, &yang.Entry{Name: "transport", Kind: yang.ChoiceEntry})
+	tcp := add(choice, &yang.Entry{Name: "tcp", Kind: yang.CaseEntry})
+	add(tcp, &yang.Entry{Name: "port", Kind: yang.LeafEntry, Type: &yang.YangType{Kind: yang.Yuint16}})
+	udp := add(choice, &yang.Entry{Name: "udp", Kind: yang.CaseEntry})
+	add(udp, &yang.Entry{Name: "udp-port", Kind: yang.LeafEntry, Type: &yang.YangType{Kind: yang.Yuint16}})
+	add(root, &yang.Entry{Name: "names", Kind: yang.LeafEntry, ListAttr: yang.NewDefaultListAttr(), Type: &yang.YangType{Kind: yang.Ystring}})
+	add(root, &yang.Entry{Name: "reboot", Kind: yang.DirectoryEntry, RPC: &yang.RPCEntry{}})
+	return root
+}
+
+func TestSchemaLeaves(t *testing.T) {
+	tests := []struct {
+		desc             string
+		inSchema         *yang.Entry
+		inPath           *gpb.Path
+		want             []string
+		wantErrSubstring string
+	}{{
+		desc:     "compressed schema",
+		inSchema: exampleoc.SchemaTree["Device"],
+		inPath:   ygot.MustStringToPath("/system/clock"),
+		want: []string{
+			"/system/clock/config/timezone-name string rw",
+			"/system/clock/state/timezone-name string ro",
+		},
+	}, {
+		desc:     "uncompressed schema",
+		inSchema: uexampleoc.SchemaTree["Device"],
+		inPath:   ygot.MustStringToPath("/system/clock"),
+		want: []string{
+			"/system/clock/config/timezone-name string rw",
+			"/system/clock/state/timezone-name string ro",
+		},
+	}, {
+		desc:     "leaf with module prefix",
+		inSchema: exampleoc.SchemaTree["Device"],
+		inPath:   ygot.MustStringToPath("/openconfig-system:system/config/hostname"),
+		want: []string{
+			"/system/config/hostname string rw",
+		},
+	}, {
+		desc:     "wildcarded list keys and leafref type",
+		inSchema: exampleoc.SchemaTree["Device"],
+		inPath:   ygot.MustStringToPath("/interfaces/interface/name"),
+		want: []string{
+			"/interfaces/interface[name=*]/name string rw",
+		},
+	}, {
+		desc:     "specified and unspecified list keys",
+		inSchema: exampleoc.SchemaTree["Device"],
+		inPath:   ygot.MustStringToPath("/network-instances/network-instance[name=DEFAULT]/protocols/protocol[name=BGP]/bgp/global/config"),
+		want: []string{
+			"/network-instances/network-instance[name=DEFAULT]/protocols/protocol[identifier=*][name=BGP]/bgp/global/config/as uint32 rw",
+			"/network-instances/network-instance[name=DEFAULT]/protocols/protocol[identifier=*][name=BGP]/bgp/global/config/router-id string rw",
+		},
+	}, {
+		desc:     "choice and case statements, leaf-lists and RPCs",
+		inSchema: choiceSchema(),
+		inPath:   &gpb.Path{},
+		want: []string{
+			"/names string rw",
+			"/port uint16 rw",
+			"/udp-port uint16 rw",
+		},
+	}, {
+		desc:             "unknown path",
+		inSchema:         exampleoc.SchemaTree["Device"],
+		inPath:           ygot.MustStringToPath("/system/fish"),
+		wantErrSubstring: "cannot find schema for path /system/fish",
+	}, {
+		desc:             "nil schema",
+		inPath:           ygot.MustStringToPath("/system"),
+		wantErrSubstring: "nil schema",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			leaves, err := ytypes.SchemaLeaves(tt.inSchema, tt.inPath)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("SchemaLeaves: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			var got []string
+			for _, l := range leaves {
+				p, err := ygot.PathToString(l.Path)
+				if err != nil {
+					t.Fatalf("cannot convert path %v to string: %v", l.Path, err)
+				}
+				access := "rw"
+				if l.Schema.ReadOnly() {
+					access = "ro"
+				}
+				got = append(got, fmt.Sprintf("%s %s %s", p, l.Type.Kind, access))
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("SchemaLeaves: did not get expected leaves, diff(-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSchemaLeavesOriginAndTarget(t *testing.T) {
+	leaves, err := ytypes.SchemaLeaves(exampleoc.SchemaTree["Device"], &gpb.Path{
+		Origin: "openconfig",
+		Target: "dut",
+		Elem:   []*gpb.PathElem{{Name: "system"}, {Name: "config"}, {Name: "hostname"}},
+	})
+	if err != nil {
+		t.Fatalf("SchemaLeaves: got unexpected error: %v", err)
+	}
+	if len(leaves) != 1 {
+		t.Fatalf("SchemaLeaves: got %d leaves, want 1", len(leaves))
+	}
+	if got := leaves[0].Path; got.GetOrigin() != "openconfig" || got.GetTarget() != "dut" {
+		t.Errorf("SchemaLeaves: got path %v, want origin openconfig and target dut", got)
+	}
+}
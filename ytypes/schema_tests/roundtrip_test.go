@@ -0,0 +1,139 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+
+	oc "github.com/openconfig/ygot/exampleoc"
+)
+
+// roundTripAnnotation is a ygot.Annotation used to check that annotations
+// are reported by ygot.CheckRoundTrip.
+type roundTripAnnotation struct {
+	Comment string `json:"comment"`
+}
+
+// MarshalJSON marshals the annotation to JSON.
+func (a *roundTripAnnotation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(*a)
+}
+
+// UnmarshalJSON unmarshals the annotation from JSON.
+func (a *roundTripAnnotation) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, a)
+}
+
+// unmarshalOC is a ygot.RoundTripUnmarshalFunc for the exampleoc schema,
+// which ignores the annotations that are not unmarshalled by ytypes.
+func unmarshalOC(b []byte, dst ygot.GoStruct) error {
+	return oc.Unmarshal(b, dst, &ytypes.IgnoreExtraFields{})
+}
+
+// TestRoundTripTestdata checks that the example data used within the tests
+// round trips losslessly through RFC7951 JSON.
+func TestRoundTripTestdata(t *testing.T) {
+	for _, f := range []string{
+		"basic.json",
+		"bgp-example.json",
+		"interfaces-example.json",
+		"local-routing-example.json",
+		"policy-example.json",
+		"relay-agent.json",
+		"system-cpu.json",
+	} {
+		t.Run(f, func(t *testing.T) {
+			j, err := os.ReadFile(filepath.Join(testRoot, "testdata", f))
+			if err != nil {
+				t.Fatalf("cannot read %s: %v", f, err)
+			}
+			d := &oc.Device{}
+			if err := oc.Unmarshal(j, d); err != nil {
+				t.Fatalf("cannot unmarshal %s: %v", f, err)
+			}
+			got, err := ygot.CheckRoundTrip(d, unmarshalOC, &ygot.EmitJSONConfig{Format: ygot.RFC7951, SkipValidation: true})
+			if err != nil {
+				t.Fatalf("CheckRoundTrip: got unexpected error: %v", err)
+			}
+			for _, d := range got {
+				t.Errorf("CheckRoundTrip: leaf was not preserved: %v", d)
+			}
+		})
+	}
+}
+
+func TestRoundTripEdgeTypes(t *testing.T) {
+	tests := []struct {
+		desc string
+		in   func(*oc.Device)
+		want []string
+	}{{
+		desc: "unions and binary values that round trip",
+		in: func(d *oc.Device) {
+			tr := d.GetOrCreateAcl().GetOrCreateAclSet("acl", oc.Acl_ACL_TYPE_ACL_IPV4).GetOrCreateAclEntry(1).GetOrCreateTransport()
+			tr.DestinationPort = oc.UnionUint16(443)
+			tr.SourcePort = oc.PacketMatchTypes_PortNumRange_Enum_ANY
+			n := d.GetOrCreateLldp().GetOrCreateInterface("eth0").GetOrCreateNeighbor("n1")
+			n.GetOrCreateTlv(1, "oui", "subtype").Value = oc.Binary("\x00\x01\xff")
+		},
+	}, {
+		desc: "union string resolved to an enumerated value",
+		in: func(d *oc.Device) {
+			tr := d.GetOrCreateAcl().GetOrCreateAclSet("acl", oc.Acl_ACL_TYPE_ACL_IPV4).GetOrCreateAclEntry(1).GetOrCreateTransport()
+			tr.DestinationPort = oc.UnionString("ANY")
+		},
+		want: []string{
+			"/acl/acl-sets/acl-set[name=acl][type=ACL_IPV4]/acl-entries/acl-entry[sequence-id=1]/transport/config/destination-port: type changed",
+		},
+	}, {
+		desc: "annotations are not unmarshalled",
+		in: func(d *oc.Device) {
+			d.GetOrCreateSystem().Hostname = ygot.String("device")
+			d.GetOrCreateSystem().ΛHostname = []ygot.Annotation{&roundTripAnnotation{Comment: "hello"}}
+		},
+		want: []string{
+			"/system/config/@hostname: lost",
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			d := &oc.Device{}
+			tt.in(d)
+			got, err := ygot.CheckRoundTrip(d, unmarshalOC, &ygot.EmitJSONConfig{Format: ygot.RFC7951, SkipValidation: true})
+			if err != nil {
+				t.Fatalf("CheckRoundTrip: got unexpected error: %v", err)
+			}
+			var gotStr []string
+			for _, d := range got {
+				p, err := ygot.PathToString(d.Path)
+				if err != nil {
+					t.Fatalf("cannot convert path %v to string: %v", d.Path, err)
+				}
+				gotStr = append(gotStr, p+": "+d.Change.String())
+			}
+			if diff := cmp.Diff(tt.want, gotStr); diff != "" {
+				t.Errorf("CheckRoundTrip: did not get expected degradations (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"testing"
+
+	oc "github.com/openconfig/ygot/exampleoc"
+	"github.com/openconfig/ygot/ytypes"
+)
+
+func BenchmarkLoadSchemaJSON(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := oc.UnzipSchema(); err != nil {
+			b.Fatalf("cannot unzip schema: %v", err)
+		}
+	}
+}
+
+func BenchmarkLoadSchemaBinary(b *testing.B) {
+	bs, err := ytypes.MarshalSchemaBinary(oc.SchemaTree["Device"])
+	if err != nil {
+		b.Fatalf("cannot marshal schema: %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ytypes.LoadSchemaBinary(bs); err != nil {
+			b.Fatalf("cannot load schema: %v", err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	oc "github.com/openconfig/ygot/exampleoc"
+)
+
+func TestSetRequestBuilder(t *testing.T) {
+	schema, err := oc.Schema()
+	if err != nil {
+		t.Fatalf("cannot load schema: %v", err)
+	}
+	root := oc.DeviceRoot("dut")
+
+	tests := []struct {
+		desc             string
+		build            func(*ytypes.SetRequestBuilder)
+		want             *gpb.SetRequest
+		wantErrSubstring []string
+	}{{
+		desc: "deletes, replaces and updates",
+		build: func(b *ytypes.SetRequestBuilder) {
+			b.Delete(root.Interface("eth1")).
+				Replace(root.Interface("eth0"), &oc.Interface{Name: ygot.String("eth0"), Mtu: ygot.Uint16(1500)}).
+				Update(root.System().Hostname(), ygot.String("dut")).
+				Update(root.Interface("eth0").Mtu(), ygot.Uint16(9000)).
+				Update(root.Interface("eth0").Type(), oc.IETFInterfaces_InterfaceType_ethernetCsmacd)
+		},
+		want: &gpb.SetRequest{
+			Prefix: &gpb.Path{Target: "dut"},
+			Delete: []*gpb.Path{mustPath("/interfaces/interface[name=eth1]")},
+			Replace: []*gpb.Update{{
+				Path: mustPath("/interfaces/interface[name=eth0]"),
+				Val: &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`{
+  "openconfig-interfaces:config": {
+    "mtu": 1500,
+    "name": "eth0"
+  },
+  "openconfig-interfaces:name": "eth0"
+}`)}},
+			}},
+			Update: []*gpb.Update{{
+				Path: mustPath("/system/config/hostname"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "dut"}},
+			}, {
+				Path: mustPath("/interfaces/interface[name=eth0]/config/mtu"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_UintVal{UintVal: 9000}},
+			}, {
+				Path: mustPath("/interfaces/interface[name=eth0]/config/type"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "ethernetCsmacd"}},
+			}},
+		},
+	}, {
+		desc: "invalid values",
+		build: func(b *ytypes.SetRequestBuilder) {
+			b.Update(root.Interface("eth0").Mtu(), ygot.String("big")).
+				Replace(root.System(), &oc.Interface{}).
+				Update(root.System(), ygot.String("dut")).
+				Update(root.System().Hostname(), nil).
+				Update(root.System().Hostname(), ygot.String("not a valid hostname!"))
+		},
+		wantErrSubstring: []string{
+			"invalid value for path /interfaces/interface[name=eth0]/config/mtu",
+			"got GoStruct *exampleoc.Interface, want *exampleoc.System",
+			"got value of type *string for non-leaf schema node system, want GoStruct",
+			"nil value supplied for path /system/config/hostname",
+			"does not match regular expression pattern",
+		},
+	}, {
+		desc: "different targets",
+		build: func(b *ytypes.SetRequestBuilder) {
+			b.Delete(root.System()).Delete(oc.DeviceRoot("other").System())
+		},
+		wantErrSubstring: []string{`path /system has target "other", but previous paths have target "dut"`},
+	}, {
+		desc: "wildcard path",
+		build: func(b *ytypes.SetRequestBuilder) {
+			b.Delete(root.InterfaceAny())
+		},
+		wantErrSubstring: []string{"wildcard path /interfaces/interface[name=*] cannot be used in a SetRequest"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b := ytypes.NewSetRequestBuilder(schema)
+			tt.build(b)
+			got, err := b.SetRequest()
+			for _, want := range tt.wantErrSubstring {
+				if diff := errdiff.Substring(err, want); diff != "" {
+					t.Errorf("SetRequest: %s", diff)
+				}
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("SetRequest: did not get expected SetRequest (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetRequestBuilderInvalidSchema(t *testing.T) {
+	_, err := ytypes.NewSetRequestBuilder(nil).Delete(oc.DeviceRoot("dut").System()).SetRequest()
+	if diff := errdiff.Substring(err, "invalid schema"); diff != "" {
+		t.Errorf("SetRequest: %s", diff)
+	}
+}
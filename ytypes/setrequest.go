@@ -0,0 +1,222 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// SetRequestBuilder builds a gNMI SetRequest from paths, specified as
+// generated path structs, and the values to be set at them, which are either
+// GoStructs or the values of leaves and leaf-lists, as would be stored in the
+// corresponding field of a GoStruct. Each value is validated against the
+// schema at its path as it is added, and is encoded as JSON_IETF if it is a
+// GoStruct, or as a scalar TypedValue otherwise.
+//
+// The target of the device root of the path structs is specified in the
+// prefix of the SetRequest. Errors are accumulated, such that operations can
+// be chained, and are returned by SetRequest.
+type SetRequestBuilder struct {
+	schema *Schema
+	req    *gpb.SetRequest
+	errs   util.Errors
+}
+
+// NewSetRequestBuilder returns a SetRequestBuilder for the supplied schema,
+// which is typically returned by the Schema function of a generated package.
+// The path structs supplied to the builder must be rooted at the root of the
+// schema.
+func NewSetRequestBuilder(schema *Schema) *SetRequestBuilder {
+	if schema == nil {
+		schema = &Schema{}
+	}
+	b := &SetRequestBuilder{schema: schema, req: &gpb.SetRequest{}}
+	if !schema.IsValid() {
+		b.errs = util.AppendErr(b.errs, fmt.Errorf("invalid schema: not fully populated"))
+	}
+	return b
+}
+
+// Delete adds the path p to the deletes of the SetRequest.
+func (b *SetRequestBuilder) Delete(p ygot.PathStruct) *SetRequestBuilder {
+	if !b.schema.IsValid() {
+		return b
+	}
+	path, err := b.resolve(p)
+	if err != nil {
+		b.errs = util.AppendErr(b.errs, err)
+		return b
+	}
+	if _, _, err := GetOrCreateNode(b.schema.RootSchema(), b.scratchRoot(), path); err != nil {
+		b.errs = util.AppendErr(b.errs, fmt.Errorf("invalid delete path %s: %v", pathString(path), err))
+		return b
+	}
+	b.req.Delete = append(b.req.Delete, path)
+	return b
+}
+
+// Replace adds an update that replaces the contents of the path p with v to
+// the replaces of the SetRequest.
+func (b *SetRequestBuilder) Replace(p ygot.PathStruct, v any) *SetRequestBuilder {
+	if u := b.update(p, v); u != nil {
+		b.req.Replace = append(b.req.Replace, u)
+	}
+	return b
+}
+
+// Update adds an update that merges v into the contents of the path p to the
+// updates of the SetRequest.
+func (b *SetRequestBuilder) Update(p ygot.PathStruct, v any) *SetRequestBuilder {
+	if u := b.update(p, v); u != nil {
+		b.req.Update = append(b.req.Update, u)
+	}
+	return b
+}
+
+// SetRequest returns the SetRequest that has been built, or the errors that
+// were encountered when adding operations to the builder.
+func (b *SetRequestBuilder) SetRequest() (*gpb.SetRequest, error) {
+	if len(b.errs) != 0 {
+		return nil, b.errs
+	}
+	return b.req, nil
+}
+
+// update validates that v can be set at the path p, and returns the Update
+// that sets it. It returns nil if an error is encountered, which is recorded
+// in the builder.
+func (b *SetRequestBuilder) update(p ygot.PathStruct, v any) *gpb.Update {
+	// The error for an invalid schema is recorded by NewSetRequestBuilder.
+	if !b.schema.IsValid() {
+		return nil
+	}
+	path, err := b.resolve(p)
+	if err != nil {
+		b.errs = util.AppendErr(b.errs, err)
+		return nil
+	}
+	if util.IsValueNil(v) {
+		b.errs = util.AppendErr(b.errs, fmt.Errorf("nil value supplied for path %s", pathString(path)))
+		return nil
+	}
+
+	tv, err := b.encode(path, v)
+	if err != nil {
+		b.errs = util.AppendErr(b.errs, fmt.Errorf("invalid value for path %s: %v", pathString(path), err))
+		return nil
+	}
+	return &gpb.Update{Path: path, Val: tv}
+}
+
+// encode validates the value v against the schema at path, and returns its
+// encoded form.
+func (b *SetRequestBuilder) encode(path *gpb.Path, v any) (*gpb.TypedValue, error) {
+	root := b.scratchRoot()
+	node, schema, err := GetOrCreateNode(b.schema.RootSchema(), root, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if gs, ok := v.(ygot.GoStruct); ok {
+		if !schema.IsDir() {
+			return nil, fmt.Errorf("got GoStruct %T for non-directory schema node %s", v, schema.Name)
+		}
+		if want := reflect.TypeOf(node); reflect.TypeOf(v) != want {
+			return nil, fmt.Errorf("got GoStruct %T, want %v", v, want)
+		}
+		if errs := Validate(schema, gs, &SkipNonLocalRefs{}); errs != nil {
+			return nil, errs
+		}
+		return ygot.EncodeTypedValue(gs, gpb.Encoding_JSON_IETF, &ygot.RFC7951JSONConfig{AppendModuleName: true})
+	}
+
+	if !schema.IsLeaf() && !schema.IsLeafList() {
+		return nil, fmt.Errorf("got value of type %T for non-leaf schema node %s, want GoStruct", v, schema.Name)
+	}
+	tv, err := ygot.EncodeTypedValue(v, gpb.Encoding_JSON_IETF, &ygot.RFC7951JSONConfig{})
+	if err != nil {
+		return nil, err
+	}
+	// Set the encoded value within the scratch root, such that its type is
+	// checked against the schema, and validate the resulting leaf.
+	if err := SetNode(b.schema.RootSchema(), root, path, tv, &InitMissingElements{}); err != nil {
+		return nil, err
+	}
+	nodes, err := GetNode(b.schema.RootSchema(), root, path)
+	if err != nil {
+		return nil, err
+	}
+	if errs := Validate(nodes[0].Schema, nodes[0].Data); errs != nil {
+		return nil, errs
+	}
+	return tv, nil
+}
+
+// resolve returns the gNMI path of the path struct p, which must not contain
+// wildcards.
+func (b *SetRequestBuilder) resolve(p ygot.PathStruct) (*gpb.Path, error) {
+	if util.IsValueNil(p) {
+		return nil, fmt.Errorf("nil path supplied")
+	}
+	path, _, errs := ygot.ResolvePath(p)
+	if errs != nil {
+		return nil, fmt.Errorf("cannot resolve path: %v", util.Errors(errs))
+	}
+	// The target of the path is specified by the root of the path struct,
+	// and is common to all paths of the SetRequest, such that it is
+	// specified within its prefix.
+	if t := path.GetTarget(); t != "" {
+		switch pt := b.req.GetPrefix().GetTarget(); {
+		case b.req.Prefix == nil:
+			b.req.Prefix = &gpb.Path{Target: t}
+		case pt != t:
+			return nil, fmt.Errorf("path %s has target %q, but previous paths have target %q", pathString(path), t, pt)
+		}
+		path.Target = ""
+	}
+	for _, e := range path.GetElem() {
+		if e.GetName() == "*" || e.GetName() == "..." {
+			return nil, fmt.Errorf("wildcard path %s cannot be used in a SetRequest", pathString(path))
+		}
+		for _, v := range e.GetKey() {
+			if v == "*" {
+				return nil, fmt.Errorf("wildcard path %s cannot be used in a SetRequest", pathString(path))
+			}
+		}
+	}
+	return path, nil
+}
+
+// scratchRoot returns a new, empty root GoStruct of the schema, into which
+// values are set to validate them.
+func (b *SetRequestBuilder) scratchRoot() ygot.GoStruct {
+	return reflect.New(reflect.TypeOf(b.schema.Root).Elem()).Interface().(ygot.GoStruct)
+}
+
+// pathString returns the string representation of path for use in error
+// messages.
+func pathString(path *gpb.Path) string {
+	s, err := ygot.PathToString(path)
+	if err != nil {
+		return path.String()
+	}
+	return s
+}
@@ -0,0 +1,366 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+)
+
+// SchemaSkewError is the error returned when a generated GoStruct does not
+// correspond to the schema with which it is used, either because a field of
+// the struct has no node in the schema, or because a node in the schema has
+// no field in the struct. It typically indicates that the Go package was
+// generated from different revisions of the YANG modules than its schema,
+// such that both should be regenerated from the same modules.
+type SchemaSkewError struct {
+	// Package is the import path of the Go package containing the struct.
+	Package string
+	// Struct is the name of the struct.
+	Struct string
+	// Field is the name of the field of the struct that has no node in the
+	// schema. It is empty if the skew is a schema node that has no field.
+	Field string
+	// Path is the schema path of the offending node. For a field that has
+	// no node in the schema, it is the path that the field is annotated
+	// with, relative to the schema of the struct.
+	Path string
+	// Revision is the revision of the YANG module that defines the schema
+	// of the struct. It is empty if it cannot be determined from the
+	// schema, e.g., when the schema was deserialised from generated code.
+	Revision string
+}
+
+// Error implements the error interface.
+func (e *SchemaSkewError) Error() string {
+	var b strings.Builder
+	if e.Field != "" {
+		fmt.Fprintf(&b, "schema skew: field %s of struct %s.%s has no schema node at %s", e.Field, e.Package, e.Struct, e.Path)
+	} else {
+		fmt.Fprintf(&b, "schema skew: schema node %s has no field in struct %s.%s", e.Path, e.Package, e.Struct)
+	}
+	if e.Revision != "" {
+		fmt.Fprintf(&b, " (schema revision %s)", e.Revision)
+	}
+	b.WriteString("; regenerate the Go package and its schema from the same YANG modules")
+	return b.String()
+}
+
+// newFieldSkewError returns a SchemaSkewError for the field ft of the struct
+// type t, which has no node in the supplied schema of the struct.
+func newFieldSkewError(t reflect.Type, schema *yang.Entry, ft reflect.StructField) *SchemaSkewError {
+	path := schema.Path()
+	if p, err := pathTagFromField(ft); err == nil {
+		path = strings.TrimSuffix(path, "/") + "/" + strings.Split(p, "|")[0]
+	}
+	return &SchemaSkewError{
+		Package:  t.PkgPath(),
+		Struct:   t.Name(),
+		Field:    ft.Name,
+		Path:     path,
+		Revision: schemaRevision(schema),
+	}
+}
+
+// newNodeSkewError returns a SchemaSkewError for the schema node at path,
+// relative to the supplied schema of the struct type t, which has no field
+// in the struct.
+func newNodeSkewError(t reflect.Type, schema *yang.Entry, path []string) *SchemaSkewError {
+	return &SchemaSkewError{
+		Package:  t.PkgPath(),
+		Struct:   t.Name(),
+		Path:     strings.TrimSuffix(schema.Path(), "/") + "/" + strings.Join(path, "/"),
+		Revision: schemaRevision(schema),
+	}
+}
+
+// schemaRevision returns the most recent revision of the module that defines
+// the supplied schema entry, or the empty string if it is not known.
+func schemaRevision(e *yang.Entry) string {
+	if e == nil || e.Node == nil {
+		return ""
+	}
+	if m := yang.RootNode(e.Node); m != nil {
+		return m.Current()
+	}
+	return ""
+}
+
+// schemaDescendant returns the descendant of schema at the supplied path,
+// whose elements do not include choice and case statements, or nil if there
+// is no such descendant.
+func schemaDescendant(schema *yang.Entry, path []string) *yang.Entry {
+	e := schema
+	for _, p := range path {
+		if e = dataChild(e, p); e == nil {
+			return nil
+		}
+	}
+	return e
+}
+
+// dataChild returns the child of e named name, looking through any choice
+// and case statements that are children of e, or nil if it is not found.
+func dataChild(e *yang.Entry, name string) *yang.Entry {
+	if c, ok := e.Dir[name]; ok && !util.IsChoiceOrCase(c) {
+		return c
+	}
+	for _, c := range e.Dir {
+		if util.IsChoiceOrCase(c) {
+			if d := dataChild(c, name); d != nil {
+				return d
+			}
+		}
+	}
+	return nil
+}
+
+// unmappedSchemaNode returns a SchemaSkewError for the first of the supplied
+// paths, relative to jsonTree and the schema of the struct type t, that are
+// not covered by the dataPaths of the fields of the struct and that correspond
+// to a node in the schema. It returns nil if none of the paths correspond to a
+// schema node. A path within a config or state container is not reported if
+// the corresponding path within the other is covered, since only one of them
+// is mapped to the fields of the struct when the schema is compressed, but the
+// fields within it in jsonTree are checked in the same way.
+func unmappedSchemaNode(t reflect.Type, schema *yang.Entry, jsonTree map[string]interface{}, paths, dataPaths [][]string) *SchemaSkewError {
+	sorted := append([][]string{}, paths...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return strings.Join(sorted[i], "/") < strings.Join(sorted[j], "/")
+	})
+	for _, p := range sorted {
+		if schemaDescendant(schema, p) == nil {
+			continue
+		}
+		if !counterpartCovered(p, dataPaths) {
+			return newNodeSkewError(t, schema, p)
+		}
+		var children [][]string
+		for k := range jsonSubtree(jsonTree, p) {
+			children = append(children, append(append([]string{}, p...), util.StripModulePrefix(k)))
+		}
+		if serr := unmappedSchemaNode(t, schema, jsonTree, children, dataPaths); serr != nil {
+			return serr
+		}
+	}
+	return nil
+}
+
+// jsonSubtree returns the JSON object at the supplied path, whose elements do
+// not have module prefixes, within jsonTree, or nil if there is none.
+func jsonSubtree(jsonTree map[string]interface{}, path []string) map[string]interface{} {
+	m := jsonTree
+	for _, p := range path {
+		var next map[string]interface{}
+		for k, v := range m {
+			if util.StripModulePrefix(k) == p {
+				next, _ = v.(map[string]interface{})
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		m = next
+	}
+	return m
+}
+
+// counterpartCovered reports whether the path that results from swapping a
+// config element of p for state, or vice versa, is covered by dataPaths.
+func counterpartCovered(p []string, dataPaths [][]string) bool {
+	for i, e := range p {
+		var other string
+		switch e {
+		case "config":
+			other = "state"
+		case "state":
+			other = "config"
+		default:
+			continue
+		}
+		c := append(append(append([]string{}, p[:i]...), other), p[i+1:]...)
+		for _, d := range dataPaths {
+			if isPathPrefix(c, d) || isPathPrefix(d, c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isPathPrefix reports whether prefix is a prefix of p, disregarding the
+// module prefixes of the elements of p.
+func isPathPrefix(prefix, p []string) bool {
+	if len(prefix) > len(p) {
+		return false
+	}
+	for i, e := range prefix {
+		if util.StripModulePrefix(e) != util.StripModulePrefix(p[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// SchemaConsistencyCheck checks that the type of the supplied root GoStruct,
+// and those of the GoStructs that it contains, correspond to the supplied
+// schema of the root, such that skew between a generated Go package and its
+// schema is found up front rather than when data is processed. Only the types
+// of the structs are inspected, and so root may be empty. A SchemaSkewError is
+// returned for each field that has no schema node, and for each schema node
+// that has no field.
+func SchemaConsistencyCheck(schema *yang.Entry, root ygot.GoStruct) util.Errors {
+	if schema == nil {
+		return util.NewErrs(fmt.Errorf("nil schema for type %T", root))
+	}
+	if util.IsValueNil(root) {
+		return util.NewErrs(fmt.Errorf("nil root for schema %s", schema.Name))
+	}
+	var errs util.Errors
+	checkStructSchema(reflect.TypeOf(root).Elem(), schema, map[reflect.Type]bool{}, &errs)
+	return errs
+}
+
+// checkStructSchema appends to errs a SchemaSkewError for each skew between
+// the struct type t and its schema, and for those of the struct types of its
+// fields. Types that are in checked are not checked again.
+func checkStructSchema(t reflect.Type, schema *yang.Entry, checked map[reflect.Type]bool, errs *util.Errors) {
+	if checked[t] {
+		return
+	}
+	checked[t] = true
+
+	// covered is a trie of the schema paths that are mapped to fields.
+	covered := map[string]interface{}{}
+	addPath := func(p []string) {
+		m := covered
+		for _, e := range p[:len(p)-1] {
+			e = util.StripModulePrefix(e)
+			switch v := m[e].(type) {
+			case bool:
+				// The node is mapped to a field in its entirety.
+				return
+			case map[string]interface{}:
+				m = v
+			default:
+				n := map[string]interface{}{}
+				m[e] = n
+				m = n
+			}
+		}
+		m[util.StripModulePrefix(p[len(p)-1])] = true
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i)
+		if util.IsYgotAnnotation(ft) {
+			continue
+		}
+		cschema, err := util.ChildSchema(schema, ft)
+		switch {
+		case err != nil:
+			*errs = util.AppendErr(*errs, fmt.Errorf("%s: %w", ft.Name, err))
+			continue
+		case cschema == nil:
+			*errs = util.AppendErr(*errs, newFieldSkewError(t, schema, ft))
+			continue
+		}
+		paths, err := util.SchemaPaths(ft)
+		if err != nil {
+			*errs = util.AppendErr(*errs, fmt.Errorf("%s: %w", ft.Name, err))
+			continue
+		}
+		for _, p := range append(paths, util.ShadowSchemaPaths(ft)...) {
+			if len(p) != 0 {
+				addPath(p)
+			}
+		}
+		if et := goStructElemType(ft.Type); et != nil {
+			checkStructSchema(et, cschema, checked, errs)
+		}
+	}
+
+	// Each data node within the schema should be mapped to a field, or be
+	// a container that is removed by compression within the path of one.
+	// When the schema is compressed, the config and state containers are
+	// removed, and a leaf within one of them need not be mapped to a field
+	// if the corresponding leaf within the other is.
+	compressed := util.IsCompressedSchema(schema)
+	var checkNodes func(e *yang.Entry, prefix []string, covered, counterpart map[string]interface{})
+	checkNodes = func(e *yang.Entry, prefix []string, covered, counterpart map[string]interface{}) {
+		var names []string
+		for n := range e.Dir {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			c := e.Dir[n]
+			switch {
+			case util.IsChoiceOrCase(c):
+				checkNodes(c, prefix, covered, counterpart)
+				continue
+			case c.Kind == yang.NotificationEntry || c.RPC != nil:
+				continue
+			}
+			p := append(append([]string{}, prefix...), n)
+			v, ok := covered[n].(map[string]interface{})
+			var o map[string]interface{}
+			isConfigOrState := compressed && c.IsContainer() && (n == "config" || n == "state")
+			if isConfigOrState {
+				other := "state"
+				if n == "state" {
+					other = "config"
+				}
+				o, _ = covered[other].(map[string]interface{})
+			}
+			switch {
+			case covered[n] == true, counterpart[n] == true:
+			case ok, isConfigOrState:
+				checkNodes(c, p, v, o)
+			default:
+				*errs = util.AppendErr(*errs, newNodeSkewError(t, schema, p))
+			}
+		}
+	}
+	checkNodes(schema, nil, covered, nil)
+}
+
+// goStructElemType returns the struct type of the GoStructs stored in a field
+// of type t, which is a pointer to a GoStruct, or a map, slice, or ordered map
+// of them. It returns nil if the field does not store GoStructs.
+func goStructElemType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr && t.Implements(reflect.TypeOf((*ygot.GoOrderedMap)(nil)).Elem()) {
+		m, ok := t.MethodByName("Values")
+		if !ok || m.Type.NumOut() != 1 {
+			return nil
+		}
+		t = m.Type.Out(0)
+	}
+	switch t.Kind() {
+	case reflect.Map, reflect.Slice:
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct || !t.Implements(reflect.TypeOf((*ygot.GoStruct)(nil)).Elem()) {
+		return nil
+	}
+	return t.Elem()
+}
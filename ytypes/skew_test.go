@@ -0,0 +1,262 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+)
+
+// skewSchema returns a compressed schema for a root containing the container
+// system, within which the leaf hostname is in both the config and state
+// containers, and the list server is within the servers container.
+func skewSchema() *yang.Entry {
+	leaf := func(name string) *yang.Entry {
+		return &yang.Entry{Name: name, Kind: yang.LeafEntry, Type: &yang.YangType{Kind: yang.Ystring}}
+	}
+	root := &yang.Entry{
+		Name:       "device",
+		Kind:       yang.DirectoryEntry,
+		Annotation: map[string]interface{}{util.CompressedSchemaAnnotation: true},
+		Dir: map[string]*yang.Entry{
+			"system": {
+				Name: "system",
+				Kind: yang.DirectoryEntry,
+				Dir: map[string]*yang.Entry{
+					"config": {
+						Name: "config",
+						Kind: yang.DirectoryEntry,
+						Dir:  map[string]*yang.Entry{"hostname": leaf("hostname")},
+					},
+					"state": {
+						Name:   "state",
+						Kind:   yang.DirectoryEntry,
+						Config: yang.TSFalse,
+						Dir: map[string]*yang.Entry{
+							"hostname":  leaf("hostname"),
+							"boot-time": leaf("boot-time"),
+						},
+					},
+					"servers": {
+						Name: "servers",
+						Kind: yang.DirectoryEntry,
+						Dir: map[string]*yang.Entry{
+							"server": {
+								Name:     "server",
+								Kind:     yang.DirectoryEntry,
+								Key:      "address",
+								ListAttr: yang.NewDefaultListAttr(),
+								Dir:      map[string]*yang.Entry{"address": leaf("address")},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	var setParents func(e *yang.Entry)
+	setParents = func(e *yang.Entry) {
+		for _, c := range e.Dir {
+			c.Parent = e
+			setParents(c)
+		}
+	}
+	setParents(root)
+	return root
+}
+
+type skewDevice struct {
+	System *skewSystem `path:"system"`
+}
+
+func (*skewDevice) IsYANGGoStruct()                          {}
+func (*skewDevice) ΛValidate(...ygot.ValidationOption) error { return nil }
+func (*skewDevice) ΛEnumTypeMap() map[string][]reflect.Type  { return nil }
+func (*skewDevice) ΛBelongingModule() string                 { return "" }
+
+type skewSystem struct {
+	Hostname *string                `path:"config/hostname"`
+	BootTime *string                `path:"state/boot-time"`
+	Server   map[string]*skewServer `path:"servers/server"`
+}
+
+func (*skewSystem) IsYANGGoStruct()                          {}
+func (*skewSystem) ΛValidate(...ygot.ValidationOption) error { return nil }
+func (*skewSystem) ΛEnumTypeMap() map[string][]reflect.Type  { return nil }
+func (*skewSystem) ΛBelongingModule() string                 { return "" }
+
+type skewServer struct {
+	Address *string `path:"address"`
+	Port    *uint16 `path:"port"`
+}
+
+func (*skewServer) IsYANGGoStruct()                          {}
+func (*skewServer) ΛValidate(...ygot.ValidationOption) error { return nil }
+func (*skewServer) ΛEnumTypeMap() map[string][]reflect.Type  { return nil }
+func (*skewServer) ΛBelongingModule() string                 { return "" }
+
+type skewDeviceOlder struct {
+	System *skewSystemOlder `path:"system"`
+}
+
+func (*skewDeviceOlder) IsYANGGoStruct()                          {}
+func (*skewDeviceOlder) ΛValidate(...ygot.ValidationOption) error { return nil }
+func (*skewDeviceOlder) ΛEnumTypeMap() map[string][]reflect.Type  { return nil }
+func (*skewDeviceOlder) ΛBelongingModule() string                 { return "" }
+
+type skewSystemOlder struct {
+	Hostname *string `path:"config/hostname"`
+}
+
+func (*skewSystemOlder) IsYANGGoStruct()                          {}
+func (*skewSystemOlder) ΛValidate(...ygot.ValidationOption) error { return nil }
+func (*skewSystemOlder) ΛEnumTypeMap() map[string][]reflect.Type  { return nil }
+func (*skewSystemOlder) ΛBelongingModule() string                 { return "" }
+
+func TestSchemaConsistencyCheck(t *testing.T) {
+	const pkg = "github.com/openconfig/ygot/ytypes"
+	tests := []struct {
+		desc   string
+		inRoot ygot.GoStruct
+		want   []*SchemaSkewError
+	}{{
+		desc:   "field without schema node",
+		inRoot: &skewDevice{},
+		want: []*SchemaSkewError{{
+			Package: pkg,
+			Struct:  "skewServer",
+			Field:   "Port",
+			Path:    "/device/system/servers/server/port",
+		}},
+	}, {
+		desc:   "schema node without field",
+		inRoot: &skewDeviceOlder{},
+		want: []*SchemaSkewError{{
+			Package: pkg,
+			Struct:  "skewSystemOlder",
+			Path:    "/device/system/servers",
+		}, {
+			Package: pkg,
+			Struct:  "skewSystemOlder",
+			Path:    "/device/system/state/boot-time",
+		}},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			errs := SchemaConsistencyCheck(skewSchema(), tt.inRoot)
+			var got []*SchemaSkewError
+			for _, err := range errs {
+				var serr *SchemaSkewError
+				if !errors.As(err, &serr) {
+					t.Fatalf("SchemaConsistencyCheck: got unexpected error: %v", err)
+				}
+				got = append(got, serr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("SchemaConsistencyCheck: did not get expected skews (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUnmarshalSchemaSkew(t *testing.T) {
+	tests := []struct {
+		desc     string
+		inParent ygot.GoStruct
+		inJSON   map[string]interface{}
+		want     *SchemaSkewError
+	}{{
+		desc:     "field without schema node",
+		inParent: &skewServer{},
+		inJSON:   map[string]interface{}{"address": "192.0.2.1"},
+		want: &SchemaSkewError{
+			Package: "github.com/openconfig/ygot/ytypes",
+			Struct:  "skewServer",
+			Field:   "Port",
+			Path:    "/device/system/servers/server/port",
+		},
+	}, {
+		desc:     "schema node without field",
+		inParent: &skewSystemOlder{},
+		inJSON: map[string]interface{}{
+			"config": map[string]interface{}{"hostname": "a"},
+			"state":  map[string]interface{}{"boot-time": "b"},
+		},
+		want: &SchemaSkewError{
+			Package: "github.com/openconfig/ygot/ytypes",
+			Struct:  "skewSystemOlder",
+			Path:    "/device/system/state/boot-time",
+		},
+	}, {
+		desc:     "config and state counterparts",
+		inParent: &skewSystemOlder{},
+		inJSON: map[string]interface{}{
+			"config": map[string]interface{}{"hostname": "a"},
+			"state":  map[string]interface{}{"hostname": "a"},
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			schema := skewSchema().Dir["system"]
+			if _, ok := tt.inParent.(*skewServer); ok {
+				schema = schema.Dir["servers"].Dir["server"]
+			}
+			err := Unmarshal(schema, tt.inParent, tt.inJSON)
+			var got *SchemaSkewError
+			if err != nil && !errors.As(err, &got) {
+				// Errors other than skew are expected only
+				// when no skew is wanted.
+				if tt.want != nil {
+					t.Fatalf("Unmarshal: got unexpected error: %v", err)
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unmarshal: did not get expected skew (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSchemaSkewErrorMessage(t *testing.T) {
+	tests := []struct {
+		desc string
+		in   *SchemaSkewError
+		want string
+	}{{
+		desc: "field without schema node",
+		in:   &SchemaSkewError{Package: "example.com/oc", Struct: "System", Field: "Port", Path: "/system/port", Revision: "2024-01-01"},
+		want: "schema skew: field Port of struct example.com/oc.System has no schema node at /system/port (schema revision 2024-01-01); regenerate the Go package and its schema from the same YANG modules",
+	}, {
+		desc: "schema node without field",
+		in:   &SchemaSkewError{Package: "example.com/oc", Struct: "System", Path: "/system/port"},
+		want: "schema skew: schema node /system/port has no field in struct example.com/oc.System; regenerate the Go package and its schema from the same YANG modules",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := tt.in.Error(); got != tt.want {
+				t.Errorf("Error: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
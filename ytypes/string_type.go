@@ -100,22 +100,33 @@ func (c *regexpCache) compilePattern(pattern string, isPOSIX bool) (*regexp.Rege
 // schema's length and pattern restrictions (if any). It returns an error if
 // the validation fails.
 func ValidateStringRestrictions(schemaType *yang.YangType, stringVal string) error {
+	return validateStringRestrictions(nil, schemaType, stringVal)
+}
+
+// validateStringRestrictions implements ValidateStringRestrictions for the
+// type of the leaf schema, which, if supplied, is used to find the length or
+// pattern statement that defines the restriction that is not satisfied.
+func validateStringRestrictions(schema *yang.Entry, schemaType *yang.YangType, stringVal string) error {
 	// Check that the length is within the allowed range.
 	allowedRanges := schemaType.Length
 	strLen := uint64(utf8.RuneCountInString(stringVal))
 	if !lengthOk(allowedRanges, strLen) {
-		return fmt.Errorf("length %d is outside range %v", strLen, allowedRanges)
+		return newLengthError(schema, schemaType, strLen, fmt.Sprintf("length %d is outside range %v", strLen, allowedRanges))
 	}
 
 	// Check that the value satisfies any regex patterns.
 	patterns, isPOSIX := util.SanitizedPattern(schemaType)
-	for _, p := range patterns {
+	yangPatterns := schemaType.Pattern
+	if isPOSIX {
+		yangPatterns = schemaType.POSIXPattern
+	}
+	for i, p := range patterns {
 		r, err := reCache.compilePattern(p, isPOSIX)
 		if err != nil {
 			return err
 		}
 		if !r.MatchString(stringVal) {
-			return fmt.Errorf("%q does not match regular expression pattern %q", stringVal, r)
+			return newPatternError(schema, schemaType, stringVal, yangPatterns[i], fmt.Sprintf("%q does not match regular expression pattern %q", stringVal, r))
 		}
 	}
 	return nil
@@ -133,15 +144,15 @@ func validateString(schema *yang.Entry, value interface{}) error {
 
 	// Check that type of value is the type expected from the schema.
 	if vv.Kind() != reflect.String {
-		return fmt.Errorf("non string type %T with value %v for schema %s", value, value, schema.Name)
+		return withIssueKind(TypeIssue, fmt.Errorf("non string type %T with value %v for schema %s", value, value, schema.Name))
 	}
 
 	// This value could be a union typedef string, so convert it to make
 	// sure it's the primitive string type.
 	stringVal := vv.Convert(reflect.TypeOf("")).Interface().(string)
 
-	if err := ValidateStringRestrictions(schema.Type, stringVal); err != nil {
-		return fmt.Errorf("schema %q: %v", schema.Name, err)
+	if err := validateStringRestrictions(schema, schema.Type, stringVal); err != nil {
+		return fmt.Errorf("schema %q: %w", schema.Name, err)
 	}
 	return nil
 }
@@ -157,14 +168,14 @@ func validateStringSlice(schema *yang.Entry, value interface{}) error {
 	// Check that type of value is the type expected from the schema.
 	slice, ok := value.([]string)
 	if !ok {
-		return fmt.Errorf("non []string type %T with value %v for schema %s", value, value, schema.Name)
+		return withIssueKind(TypeIssue, fmt.Errorf("non []string type %T with value %v for schema %s", value, value, schema.Name))
 	}
 
 	// Each slice element must be valid and unique.
 	tbl := make(map[string]bool, len(slice))
 	for i, val := range slice {
 		if err := validateString(schema, val); err != nil {
-			return fmt.Errorf("invalid element at index %d: %v for schema %s", i, err, schema.Name)
+			return fmt.Errorf("invalid element at index %d: %w for schema %s", i, err, schema.Name)
 		}
 		if tbl[val] {
 			return fmt.Errorf("duplicate string: %q for schema %s", val, schema.Name)
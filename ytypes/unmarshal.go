@@ -17,10 +17,14 @@ package ytypes
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
 )
 
 // UnmarshalOpt is an interface used for any option to be supplied
@@ -65,9 +69,44 @@ func (c *ComplianceErrors) append(errs ...error) *ComplianceErrors {
 	return c
 }
 
+// SkippedFieldError is the error recorded within a ComplianceErrors for each
+// field of the input data that could not be unmarshalled during a best-effort
+// unmarshal. Such fields are skipped, and the remainder of the data tree is
+// still unmarshalled.
+type SkippedFieldError struct {
+	// Path is the schema path of the field that was skipped.
+	Path string
+	// Err is the error that caused the field to be skipped.
+	Err error
+}
+
+func (s *SkippedFieldError) Error() string {
+	return fmt.Sprintf("%s: %v", s.Path, s.Err)
+}
+
+// Unwrap returns the underlying error that caused the field to be skipped.
+func (s *SkippedFieldError) Unwrap() error {
+	return s.Err
+}
+
+// appendSkipped records that the field at path was skipped due to err. If
+// err is itself a ComplianceErrors, the errors that it contains are merged
+// into c.
+func (c *ComplianceErrors) appendSkipped(path string, err error) *ComplianceErrors {
+	if ce, ok := err.(*ComplianceErrors); ok {
+		return c.append(ce.Errors...)
+	}
+	return c.append(&SkippedFieldError{Path: path, Err: err})
+}
+
 // BestEffortUnmarshal is an unmarshal option that accumulates errors while unmarshalling,
 // and continues the unmarshaling process. An unmarshal now return a ComplianceErrors struct,
 // instead of a single error.
+//
+// When supplied to Unmarshal, fields in the input JSON that are unknown to the
+// schema, or whose values cannot be unmarshalled into the GoStruct (e.g., due
+// to a type mismatch), are skipped. Each skipped field is reported as a
+// SkippedFieldError within the returned ComplianceErrors.
 type BestEffortUnmarshal struct{}
 
 // IsUnmarshalOpt marks BestEffortUnmarshal as a valid UnmarshalOpt.
@@ -83,6 +122,71 @@ type IgnoreExtraFields struct{}
 // IsUnmarshalOpt marks IgnoreExtraFields as a valid UnmarshalOpt.
 func (*IgnoreExtraFields) IsUnmarshalOpt() {}
 
+// ResolveSecrets is an unmarshal option that specifies that string leaves
+// whose values are secret placeholders (as produced by ygot.EmitJSON with a
+// SecretStore) are replaced with the value of the corresponding secret in
+// Store once unmarshalling is complete. Only the leaves that are unmarshalled
+// are resolved, and an error is returned if any of them holds a placeholder
+// but is not the secret that the placeholder refers to.
+type ResolveSecrets struct {
+	// Store is the store from which secret values are retrieved.
+	Store ygot.SecretStore
+}
+
+// IsUnmarshalOpt marks ResolveSecrets as a valid UnmarshalOpt.
+func (*ResolveSecrets) IsUnmarshalOpt() {}
+
+// ReportUnionSelection is an unmarshal option that specifies that Report
+// is called each time that a value is unmarshalled into a union leaf or
+// leaf-list, describing the union member type that the value was
+// unmarshalled into, and any other member types that could also have
+// accepted the value. It is intended for debugging cases where a device
+// expects a value to be encoded as a specific member of a union.
+type ReportUnionSelection struct {
+	// Report is called with the selection made for each union value.
+	Report func(*UnionSelection)
+}
+
+// IsUnmarshalOpt marks ReportUnionSelection as a valid UnmarshalOpt.
+func (*ReportUnionSelection) IsUnmarshalOpt() {}
+
+// CollectUnknownPaths is an unmarshal option for ApplyNotifications and
+// UnmarshalNotifications that specifies that deletions and updates of paths
+// that do not exist within the schema are skipped, rather than causing an
+// error to be returned. The path of each skipped deletion or update,
+// including the prefix of its Notification, is appended to Paths, such that
+// it can be reported as a warning.
+type CollectUnknownPaths struct {
+	// Paths are the paths that were skipped since they do not exist
+	// within the schema.
+	Paths []*gpb.Path
+}
+
+// IsUnmarshalOpt marks CollectUnknownPaths as a valid UnmarshalOpt.
+func (*CollectUnknownPaths) IsUnmarshalOpt() {}
+
+// AcceptEnumInts is an unmarshal option that specifies that the values of
+// enumerations within the input JSON may be represented by their integer
+// values within the YANG schema, as rendered by ygot when the EnumsAsInts
+// option is set, as well as by their names. Within a union, a number is
+// interpreted as the value of an enumeration only where the union has no
+// numeric member types.
+type AcceptEnumInts struct{}
+
+// IsUnmarshalOpt marks AcceptEnumInts as a valid UnmarshalOpt.
+func (*AcceptEnumInts) IsUnmarshalOpt() {}
+
+// AcceptInt64Numbers is an unmarshal option that specifies that the values of
+// int64 and uint64 leaves and leaf-lists within the input JSON may be
+// represented by numbers, as rendered by ygot in internal format JSON unless
+// its Int64AsString option is set, as well as by strings. Values that are
+// decoded as float64 are only exact up to 2^53, and hence json.Number values,
+// as produced by a json.Decoder with UseNumber set, are also accepted.
+type AcceptInt64Numbers struct{}
+
+// IsUnmarshalOpt marks AcceptInt64Numbers as a valid UnmarshalOpt.
+func (*AcceptInt64Numbers) IsUnmarshalOpt() {}
+
 // IsUnmarshalOpt marks PreferShadowPath as a valid UnmarshalOpt.
 // See PreferShadowPath's definition in node.go.
 func (*PreferShadowPath) IsUnmarshalOpt() {}
@@ -92,7 +196,72 @@ func (*PreferShadowPath) IsUnmarshalOpt() {}
 // not present in value are preserved. If provided schema is a leaf or leaf
 // list, parent must be referencing the parent GoStruct.
 func Unmarshal(schema *yang.Entry, parent interface{}, value interface{}, opts ...UnmarshalOpt) error {
-	return unmarshalGeneric(schema, parent, value, JSONEncoding, opts...)
+	rs := hasResolveSecrets(opts)
+	gs, isGoStruct := parent.(ygot.GoStruct)
+	if rs == nil || !isGoStruct {
+		return unmarshalGeneric(schema, parent, value, JSONEncoding, opts...)
+	}
+
+	// Only the placeholders that are unmarshalled from value are resolved,
+	// such that those that were already within parent are unchanged.
+	leaves, err := placeholderLeaves(gs)
+	if err != nil {
+		return err
+	}
+	existing := map[*string]bool{}
+	for _, f := range leaves {
+		existing[f.Interface().(*string)] = true
+	}
+	err = unmarshalGeneric(schema, parent, value, JSONEncoding, opts...)
+	if _, partial := err.(*ComplianceErrors); err != nil && !partial {
+		return err
+	}
+
+	// The leaves that still hold an existing placeholder are unset while
+	// the placeholders are resolved, and then restored.
+	if leaves, err = placeholderLeaves(gs); err != nil {
+		return err
+	}
+	var unset []reflect.Value
+	for _, f := range leaves {
+		if existing[f.Interface().(*string)] {
+			unset = append(unset, f)
+		}
+	}
+	saved := make([]reflect.Value, len(unset))
+	for i, f := range unset {
+		saved[i] = reflect.ValueOf(f.Interface())
+		f.Set(reflect.Zero(f.Type()))
+	}
+	rerr := ygot.ResolveSecretPlaceholders(gs, rs.Store)
+	for i, f := range unset {
+		f.Set(saved[i])
+	}
+	if rerr != nil {
+		return rerr
+	}
+	return err
+}
+
+// placeholderLeaves returns the settable values of the string leaves within
+// s whose values are secret placeholders.
+func placeholderLeaves(s ygot.GoStruct) ([]reflect.Value, error) {
+	var leaves []reflect.Value
+	errs := util.ForEachDataField2(s, nil, nil, func(ni *util.NodeInfo, _, _ any) (util.IterationAction, util.Errors) {
+		if !ni.FieldValue.IsValid() || !ni.FieldValue.CanSet() {
+			return util.ContinueIteration, nil
+		}
+		if sv, ok := ni.FieldValue.Interface().(*string); ok && sv != nil {
+			if _, ok := ygot.SecretNameFromPlaceholder(*sv); ok {
+				leaves = append(leaves, ni.FieldValue)
+			}
+		}
+		return util.ContinueIteration, nil
+	})
+	if errs != nil {
+		return nil, errs
+	}
+	return leaves, nil
 }
 
 // Encoding specifies how the value provided to UnmarshalGeneric function is encoded.
@@ -131,10 +300,6 @@ func unmarshalGeneric(schema *yang.Entry, parent interface{}, value interface{},
 		return errors.New("unmarshalling a non leaf node isn't supported in GNMIEncoding mode")
 	}
 
-	if hasBestEffortUnmarshal(opts) {
-		return errors.New("unmarshalGeneric passed unsupported option BestEffortUnmarshal")
-	}
-
 	switch {
 	case schema.IsLeaf():
 		return unmarshalLeaf(schema, parent, value, enc, opts...)
@@ -144,7 +309,7 @@ func unmarshalGeneric(schema *yang.Entry, parent interface{}, value interface{},
 		return unmarshalList(schema, parent, value, enc, opts...)
 	case schema.IsChoice():
 		return fmt.Errorf("cannot pass choice schema %s to Unmarshal", schema.Name)
-	case schema.IsContainer():
+	case schema.IsContainer(), util.IsRPCInputOutput(schema):
 		return unmarshalContainer(schema, parent, value, enc, opts...)
 	}
 	return fmt.Errorf("unknown schema type for type %T, value %v", value, value)
@@ -172,6 +337,62 @@ func hasPreferShadowPath(opts []UnmarshalOpt) bool {
 	return false
 }
 
+// hasResolveSecrets returns the first ResolveSecrets option from the supplied
+// slice of UnmarshalOpts, or nil if there isn't one.
+func hasResolveSecrets(opts []UnmarshalOpt) *ResolveSecrets {
+	for _, o := range opts {
+		if v, ok := o.(*ResolveSecrets); ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// hasReportUnionSelection returns the first ReportUnionSelection option with
+// a non-nil Report function from the supplied slice of UnmarshalOpts, or nil
+// if there isn't one.
+func hasReportUnionSelection(opts []UnmarshalOpt) *ReportUnionSelection {
+	for _, o := range opts {
+		if v, ok := o.(*ReportUnionSelection); ok && v.Report != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// hasCollectUnknownPaths returns the first CollectUnknownPaths option within
+// the supplied slice of UnmarshalOpts, or nil if there is none.
+func hasCollectUnknownPaths(opts []UnmarshalOpt) *CollectUnknownPaths {
+	for _, o := range opts {
+		if v, ok := o.(*CollectUnknownPaths); ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// hasAcceptEnumInts determines whether the supplied slice of UnmarshalOpts
+// contains the AcceptEnumInts option.
+func hasAcceptEnumInts(opts []UnmarshalOpt) bool {
+	for _, o := range opts {
+		if _, ok := o.(*AcceptEnumInts); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAcceptInt64Numbers determines whether the supplied slice of
+// UnmarshalOpts contains the AcceptInt64Numbers option.
+func hasAcceptInt64Numbers(opts []UnmarshalOpt) bool {
+	for _, o := range opts {
+		if _, ok := o.(*AcceptInt64Numbers); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // hasBestEffortUnmarshal determines whether the supplied slice of UnmarshalOpts
 // contains the BestEffortUnmarshal option.
 func hasBestEffortUnmarshal(opts []UnmarshalOpt) bool {
@@ -0,0 +1,72 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ygot/exampleoc"
+	"github.com/openconfig/ygot/integration_tests/schemaops/ctestschema"
+	"github.com/openconfig/ygot/ygot"
+)
+
+func TestUnmarshalSchemaMismatchError(t *testing.T) {
+	err := ctestschema.Unmarshal([]byte(`{"other-data": {"config": {"does-not-exist": 42}}}`), &ctestschema.Device{})
+	var sme *ygot.SchemaMismatchError
+	if !errors.As(err, &sme) {
+		t.Fatalf("Unmarshal: got error %v, want SchemaMismatchError", err)
+	}
+	if got, want := sme.Path, "/device/other-data"; got != want {
+		t.Errorf("Unmarshal: got SchemaMismatchError with path %s, want %s", got, want)
+	}
+}
+
+func TestUnmarshalModulePrefixes(t *testing.T) {
+	want := &exampleoc.Device{}
+	intf := want.GetOrCreateInterface("eth0")
+	intf.Description = ygot.String("foo")
+	intf.Type = exampleoc.IETFInterfaces_InterfaceType_ethernetCsmacd
+
+	tests := []struct {
+		desc string
+		in   string
+	}{{
+		desc: "module names",
+		in: `{"openconfig-interfaces:interfaces": {"interface": [{
+			"name": "eth0",
+			"config": {"name": "eth0", "description": "foo", "type": "iana-if-type:ethernetCsmacd"}
+		}]}}`,
+	}, {
+		desc: "module prefixes",
+		in: `{"oc-if:interfaces": {"interface": [{
+			"name": "eth0",
+			"config": {"name": "eth0", "oc-if:description": "foo", "type": "ianaift:ethernetCsmacd"}
+		}]}}`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := &exampleoc.Device{}
+			if err := exampleoc.Unmarshal([]byte(tt.in), got); err != nil {
+				t.Fatalf("Unmarshal: got unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Unmarshal: did not get expected device, diff(-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
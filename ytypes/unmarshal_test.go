@@ -15,9 +15,15 @@
 package ytypes
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/openconfig/gnmi/errdiff"
 	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
 )
 
 func TestUnmarshal(t *testing.T) {
@@ -66,11 +72,10 @@ func TestUnmarshal(t *testing.T) {
 			opts:   []UnmarshalOpt{&IgnoreExtraFields{}},
 		},
 		{
-			desc:    "passing invalid option to Unmarshal",
-			schema:  validSchema,
-			value:   nil,
-			opts:    []UnmarshalOpt{&BestEffortUnmarshal{}},
-			wantErr: `unmarshalGeneric passed unsupported option BestEffortUnmarshal`,
+			desc:   "passing best effort option to Unmarshal",
+			schema: validSchema,
+			value:  nil,
+			opts:   []UnmarshalOpt{&BestEffortUnmarshal{}},
 		},
 	}
 
@@ -86,3 +91,113 @@ func TestUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+type secretContainer struct {
+	Description *string `path:"description"`
+	Password    *string `path:"password"`
+}
+
+func (*secretContainer) IsYANGGoStruct() {}
+
+// pathSecretStore is a SecretStore whose secret names are keyed by the string
+// form of the path of the secret leaf.
+type pathSecretStore struct {
+	names  map[string]string
+	values map[string]string
+}
+
+func (s *pathSecretStore) SecretName(p *gpb.Path) (string, bool) {
+	ps, err := ygot.PathToString(p)
+	if err != nil {
+		return "", false
+	}
+	n, ok := s.names[ps]
+	return n, ok
+}
+
+func (s *pathSecretStore) SecretValue(name string) (string, error) {
+	v, ok := s.values[name]
+	if !ok {
+		return "", fmt.Errorf("unknown secret %s", name)
+	}
+	return v, nil
+}
+
+func TestUnmarshalResolveSecrets(t *testing.T) {
+	schema := &yang.Entry{
+		Name: "container",
+		Kind: yang.DirectoryEntry,
+		Dir: map[string]*yang.Entry{
+			"description": {
+				Name: "description",
+				Kind: yang.LeafEntry,
+				Type: &yang.YangType{Kind: yang.Ystring},
+			},
+			"password": {
+				Name: "password",
+				Kind: yang.LeafEntry,
+				Type: &yang.YangType{Kind: yang.Ystring},
+			},
+		},
+	}
+	populateParentField(nil, schema)
+	store := &pathSecretStore{
+		names:  map[string]string{"/password": "bgp-password"},
+		values: map[string]string{"bgp-password": "hunter2"},
+	}
+
+	tests := []struct {
+		desc             string
+		inParent         *secretContainer
+		inValue          map[string]interface{}
+		want             *secretContainer
+		wantErrSubstring string
+	}{{
+		desc:     "secret leaf",
+		inParent: &secretContainer{},
+		inValue:  map[string]interface{}{"password": "__SECRET:bgp-password__"},
+		want:     &secretContainer{Password: ygot.String("hunter2")},
+	}, {
+		desc:             "placeholder in leaf that is not a secret",
+		inParent:         &secretContainer{},
+		inValue:          map[string]interface{}{"description": "__SECRET:bgp-password__"},
+		wantErrSubstring: "placeholder for secret bgp-password is not allowed",
+	}, {
+		desc:     "existing placeholder is not resolved",
+		inParent: &secretContainer{Description: ygot.String("__SECRET:bgp-password__")},
+		inValue:  map[string]interface{}{"password": "__SECRET:bgp-password__"},
+		want: &secretContainer{
+			Description: ygot.String("__SECRET:bgp-password__"),
+			Password:    ygot.String("hunter2"),
+		},
+	}, {
+		desc:     "existing placeholder in secret leaf is not resolved",
+		inParent: &secretContainer{Password: ygot.String("__SECRET:bgp-password__")},
+		inValue:  map[string]interface{}{"description": "peer"},
+		want: &secretContainer{
+			Description: ygot.String("peer"),
+			Password:    ygot.String("__SECRET:bgp-password__"),
+		},
+	}, {
+		desc:     "existing placeholder replaced by unmarshalled placeholder",
+		inParent: &secretContainer{Password: ygot.String("__SECRET:bgp-password__")},
+		inValue:  map[string]interface{}{"password": "__SECRET:bgp-password__"},
+		want:     &secretContainer{Password: ygot.String("hunter2")},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := tt.inParent
+			err := Unmarshal(schema, got, tt.inValue, &ResolveSecrets{Store: store})
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("Unmarshal: did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if !areEqual(got, tt.want) {
+				t.Errorf("Unmarshal: got %v, want %v", pretty.Sprint(got), pretty.Sprint(tt.want))
+			}
+		})
+	}
+}
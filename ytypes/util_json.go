@@ -17,6 +17,7 @@ package ytypes
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/openconfig/ygot/util"
@@ -70,7 +71,7 @@ func getJSONTreeValForPath(tree interface{}, path []string) (interface{}, bool)
 	}
 
 	for k, v := range t {
-		if path[0] == util.StripModulePrefix(k) {
+		if path[0] == jsonMemberName(k) {
 			if ret, ok := getJSONTreeValForPath(v, path[1:]); ok {
 				return ret, true
 			}
@@ -78,3 +79,13 @@ func getJSONTreeValForPath(tree interface{}, path []string) (interface{}, bool)
 	}
 	return nil, false
 }
+
+// jsonMemberName returns the name of the JSON member k without its module
+// prefix. The module prefix of the name of an RFC7952 annotation member
+// follows its "@", e.g., "@mod:leaf" is named "@leaf".
+func jsonMemberName(k string) string {
+	if strings.HasPrefix(k, "@") {
+		return "@" + util.StripModulePrefix(k[1:])
+	}
+	return util.StripModulePrefix(k)
+}
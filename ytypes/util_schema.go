@@ -108,13 +108,13 @@ func validateListAttr(schema *yang.Entry, value interface{}) util.Errors {
 	// leaf-list. Check that the data tree falls within the required size
 	// bounds.
 	if size < schema.ListAttr.MinElements {
-		errors = util.AppendErr(errors, fmt.Errorf("list %s contains fewer than min required elements: %d < %d", schema.Name, size, schema.ListAttr.MinElements))
+		errors = util.AppendErr(errors, withIssueKind(ElementsIssue, fmt.Errorf("list %s contains fewer than min required elements: %d < %d", absoluteSchemaDataPath(schema), size, schema.ListAttr.MinElements)))
 	}
 	// 0 is an invalid value for MaxElements
 	// (https://tools.ietf.org/html/rfc7950#section-7.7.6).
 	// For useability it best represents the value "unbounded".
 	if schema.ListAttr.MaxElements != 0 && size > schema.ListAttr.MaxElements {
-		errors = util.AppendErr(errors, fmt.Errorf("list %s contains more than max allowed elements: %d > %d", schema.Name, size, schema.ListAttr.MaxElements))
+		errors = util.AppendErr(errors, withIssueKind(ElementsIssue, fmt.Errorf("list %s contains more than max allowed elements: %d > %d", absoluteSchemaDataPath(schema), size, schema.ListAttr.MaxElements)))
 	}
 	return errors
 }
@@ -233,6 +233,40 @@ func removeNonDataPathElements(parentSchema, schema *yang.Entry, paths [][]strin
 //
 // checkDataTreePaths returns an error if there are fields that are in the JSON that are not specified in the dataPaths.
 func checkDataTreeAgainstPaths(jsonTree map[string]interface{}, dataPaths [][]string) error {
+	var missingKeys []string
+	var unexpectedLeafNodes []string
+	missingPaths, unexpectedLeafPaths := unexpectedDataTreePaths(jsonTree, dataPaths)
+	for _, p := range missingPaths {
+		missingKeys = append(missingKeys, p[len(p)-1])
+	}
+	for _, p := range unexpectedLeafPaths {
+		unexpectedLeafNodes = append(unexpectedLeafNodes, p[len(p)-1])
+	}
+	switch len(missingKeys) {
+	case 0:
+	case 1:
+		// Retain backwards compatibility with previous implementation that reported
+		// only the first error key.
+		return fmt.Errorf("JSON contains unexpected field %s", missingKeys[0])
+	default:
+		sort.Strings(missingKeys)
+		return fmt.Errorf("JSON contains unexpected field %v", missingKeys)
+	}
+
+	if len(unexpectedLeafNodes) != 0 {
+		return fmt.Errorf("JSON contains unexpected leaf field(s) %v at non-leaf node", unexpectedLeafNodes)
+	}
+	return nil
+}
+
+// unexpectedDataTreePaths returns the paths, relative to jsonTree and with
+// module prefixes removed, of the fields within jsonTree that do not match any
+// of the supplied dataPaths, using the matching rules described for
+// checkDataTreeAgainstPaths. The first return value contains the paths of
+// fields that are not found in dataPaths, and the second contains the paths of
+// fields that are leaves in jsonTree but non-leaf nodes in dataPaths. Both
+// are sorted, such that errors reporting the paths are deterministic.
+func unexpectedDataTreePaths(jsonTree map[string]interface{}, dataPaths [][]string) ([][]string, [][]string) {
 	// Primarily, we build a trie that consists of all the valid paths that we were provided
 	// in the dataPaths tree.
 	tree := map[string]interface{}{}
@@ -248,16 +282,17 @@ func checkDataTreeAgainstPaths(jsonTree map[string]interface{}, dataPaths [][]st
 		parent[util.StripModulePrefix(ch[len(ch)-1])] = true
 	}
 
-	var missingKeys []string
-	var unexpectedLeafNodes []string
+	var missingPaths [][]string
+	var unexpectedLeafPaths [][]string
 	// We have to define the function up-front so that we can recursively call the
 	// anonymous function.
-	var checkTree func(map[string]interface{}, map[string]interface{})
-	checkTree = func(jsonTree map[string]interface{}, keyTree map[string]interface{}) {
+	var checkTree func([]string, map[string]interface{}, map[string]interface{})
+	checkTree = func(prefix []string, jsonTree map[string]interface{}, keyTree map[string]interface{}) {
 		for key := range jsonTree {
 			shortKey := util.StripModulePrefix(key)
+			p := append(append([]string{}, prefix...), shortKey)
 			if _, ok := keyTree[shortKey]; !ok {
-				missingKeys = append(missingKeys, shortKey)
+				missingPaths = append(missingPaths, p)
 			}
 			if ct, ok := keyTree[shortKey].(map[string]interface{}); ok {
 				// If this is a non-leaf node for keyTree, then
@@ -265,29 +300,24 @@ func checkDataTreeAgainstPaths(jsonTree map[string]interface{}, dataPaths [][]st
 				// The converse is not true, since keyTree is
 				// just a partial path.
 				if jt, ok := jsonTree[key].(map[string]interface{}); ok {
-					checkTree(jt, ct)
+					checkTree(p, jt, ct)
 				} else {
-					unexpectedLeafNodes = append(unexpectedLeafNodes, shortKey)
+					unexpectedLeafPaths = append(unexpectedLeafPaths, p)
 				}
 			}
 		}
 	}
-	checkTree(jsonTree, tree)
-	switch len(missingKeys) {
-	case 0:
-	case 1:
-		// Retain backwards compatibility with previous implementation that reported
-		// only the first error key.
-		return fmt.Errorf("JSON contains unexpected field %s", missingKeys[0])
-	default:
-		sort.Strings(missingKeys)
-		return fmt.Errorf("JSON contains unexpected field %v", missingKeys)
-	}
+	checkTree(nil, jsonTree, tree)
+	sortPaths(missingPaths)
+	sortPaths(unexpectedLeafPaths)
+	return missingPaths, unexpectedLeafPaths
+}
 
-	if len(unexpectedLeafNodes) != 0 {
-		return fmt.Errorf("JSON contains unexpected leaf field(s) %v at non-leaf node", unexpectedLeafNodes)
-	}
-	return nil
+// sortPaths sorts paths by their string form.
+func sortPaths(paths [][]string) {
+	sort.Slice(paths, func(i, j int) bool {
+		return strings.Join(paths[i], "/") < strings.Join(paths[j], "/")
+	})
 }
 
 // schemaToStructFieldName returns the string name of the field, which must be
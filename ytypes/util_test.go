@@ -15,6 +15,7 @@
 package ytypes
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -207,9 +208,10 @@ type testStruct struct {
 func TestStringToType(t *testing.T) {
 	ts := testStruct{}
 	tests := []struct {
-		s       string
-		t       reflect.Type
-		wantErr bool
+		s         string
+		t         reflect.Type
+		wantErr   bool
+		wantClass error
 	}{
 		{s: "hehehe", t: reflect.TypeOf("")},
 		{s: "123", t: reflect.TypeOf(uint16(10))},
@@ -224,7 +226,7 @@ func TestStringToType(t *testing.T) {
 		{s: "257", t: reflect.TypeOf(uint8(0)), wantErr: true},
 		{s: "test_enum3", t: reflect.TypeOf(ts.Test)},
 		// invalid enum for the enum type
-		{s: "fortytwo", t: reflect.TypeOf(ts.Test), wantErr: true},
+		{s: "fortytwo", t: reflect.TypeOf(ts.Test), wantErr: true, wantClass: ygot.ErrUnknownEnumValue},
 	}
 
 	for i, tt := range tests {
@@ -233,6 +235,9 @@ func TestStringToType(t *testing.T) {
 			t.Errorf("#%d got %v, want error %v", i+1, e, tt.wantErr)
 			continue
 		}
+		if tt.wantClass != nil && !errors.Is(e, tt.wantClass) {
+			t.Errorf("#%d got error %v, want error of class %v", i+1, e, tt.wantClass)
+		}
 		if e != nil {
 			continue
 		}
@@ -17,6 +17,7 @@ package ytypes
 import (
 	"encoding/base64"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 
@@ -24,8 +25,6 @@ import (
 	"github.com/openconfig/ygot/internal/yreflect"
 	"github.com/openconfig/ygot/util"
 	"github.com/openconfig/ygot/ygot"
-
-	log "github.com/golang/glog"
 )
 
 // enumStringToValue returns the enum type value that enumerated string value
@@ -46,7 +45,7 @@ func enumStringToValue(parent interface{}, fieldName, value string) (interface{}
 		return nil, err
 	}
 	if ev == nil {
-		return 0, fmt.Errorf("%s is not a valid value for enum field %s, type %s", value, fieldName, field.Type())
+		return 0, ygot.ClassifyError(ygot.ErrUnknownEnumValue, fmt.Errorf("%s is not a valid value for enum field %s, type %s", value, fieldName, field.Type()))
 	}
 	return ev, nil
 }
@@ -125,35 +124,72 @@ func castToEnumValue(ft reflect.Type, value string) (interface{}, error) {
 		ft = ft.Elem()
 	}
 
+	m, err := enumValueMap(ft)
+	if err != nil {
+		return 0, err
+	}
+
+	for k, v := range m {
+		if util.StripModulePrefix(v.Name) == util.StripModulePrefix(value) {
+			// Convert to destination enum type.
+			return reflect.ValueOf(k).Convert(ft).Interface(), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// castIntToEnumName returns the name of the value of the enumeration of the
+// given type ft whose integer value within the YANG schema is value, and true.
+// False is returned if there is no such value, including where ft is the type
+// of an identity, which has no integer values.
+func castIntToEnumName(ft reflect.Type, value float64) (string, bool, error) {
+	if ft.Kind() == reflect.Slice {
+		// leaf-list case
+		ft = ft.Elem()
+	}
+	if value != math.Trunc(value) {
+		return "", false, nil
+	}
+
+	m, err := enumValueMap(ft)
+	if err != nil {
+		return "", false, err
+	}
+
+	// The values of generated enumerated types are offset by one from
+	// their YANG values, such that zero represents an unset value.
+	def, ok := m[int64(value)+1]
+	if !ok || def.DefiningModule != "" {
+		return "", false, nil
+	}
+	return def.Name, true, nil
+}
+
+// enumValueMap returns the map of the values of the enumerated type ft to
+// their definitions, as returned by its ΛMap method.
+func enumValueMap(ft reflect.Type) (map[int64]ygot.EnumDefinition, error) {
 	util.DbgPrint("checking for matching enum value for type %s", ft)
 	mapMethod, err := yreflect.MethodByName(reflect.New(ft), "ΛMap")
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	ec := mapMethod.Call(nil)
 	if len(ec) == 0 {
-		return 0, fmt.Errorf("%s ΛMap function returns empty value", ft)
+		return nil, fmt.Errorf("%s ΛMap function returns empty value", ft)
 	}
 	ei := ec[0].Interface()
 	enumMap, ok := ei.(map[string]map[int64]ygot.EnumDefinition)
 	if !ok {
-		return 0, fmt.Errorf("%s ΛMap function returned wrong type %T, want map[string]map[int64]ygot.EnumDefinition", ft, ei)
+		return nil, fmt.Errorf("%s ΛMap function returned wrong type %T, want map[string]map[int64]ygot.EnumDefinition", ft, ei)
 	}
 
 	m, ok := enumMap[ft.Name()]
 	if !ok {
-		return 0, fmt.Errorf("%s is not a valid enum field name", ft.Name())
+		return nil, fmt.Errorf("%s is not a valid enum field name", ft.Name())
 	}
-
-	for k, v := range m {
-		if util.StripModulePrefix(v.Name) == util.StripModulePrefix(value) {
-			// Convert to destination enum type.
-			return reflect.ValueOf(k).Convert(ft).Interface(), nil
-		}
-	}
-
-	return nil, nil
+	return m, nil
 }
 
 func structFieldType(parent interface{}, fieldName string) reflect.Type {
@@ -181,7 +217,7 @@ func StringToType(t reflect.Type, s string) (reflect.Value, error) {
 	if t.Implements(reflect.TypeOf((*ygot.GoEnum)(nil)).Elem()) {
 		i, err := castToEnumValue(t, s)
 		if err != nil || i == nil {
-			return reflect.ValueOf(nil), fmt.Errorf("no enum matching with %s: %v", s, err)
+			return reflect.ValueOf(nil), ygot.ClassifyError(ygot.ErrUnknownEnumValue, fmt.Errorf("no enum matching with %s: %v", s, err))
 		}
 		return reflect.ValueOf(i), nil
 	}
@@ -281,9 +317,9 @@ func stringToKeyType(schema *yang.Entry, parent interface{}, fieldName string, v
 	case yang.Yunion:
 		return stringToUnionType(schema, parent, fieldName, value)
 	case yang.Yleafref:
-		schema, err := util.FindLeafRefSchema(schema, schema.Type.Path)
+		schema, err := util.ResolveIfLeafRef(schema)
 		if err != nil {
-			return reflect.ValueOf(nil), fmt.Errorf("stringToKeyType: unable to find target schema from leafref schema: %s", err)
+			return reflect.ValueOf(nil), fmt.Errorf("stringToKeyType: unable to find target schema from leafref schema: %w", err)
 		}
 		return stringToKeyType(schema, parent, fieldName, value)
 	}
@@ -381,7 +417,7 @@ func yangBuiltinTypeToGoType(t yang.TypeKind) interface{} {
 		return int64(0)
 	default:
 		// TODO(mostrowski): handle bitset.
-		log.Errorf("unexpected type %v in yangBuiltinTypeToGoPtrType", t)
+		util.Log().Errorf("unexpected type %v in yangBuiltinTypeToGoPtrType", t)
 	}
 	return nil
 }
@@ -403,7 +439,7 @@ func yangToJSONType(t yang.TypeKind) reflect.Type {
 		return reflect.TypeOf(nil)
 	default:
 		// TODO(mostrowski): handle bitset.
-		log.Errorf("unexpected type %v in yangToJSONType", t)
+		util.Log().Errorf("unexpected type %v in yangToJSONType", t)
 	}
 	return reflect.TypeOf(nil)
 }
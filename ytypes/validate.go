@@ -16,6 +16,7 @@ package ytypes
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/openconfig/ygot/util"
@@ -54,17 +55,124 @@ type CustomValidationOptions struct {
 // interface.
 func (*CustomValidationOptions) IsValidationOption() {}
 
+// SkipNonLocalRefs is a ValidationOption that allows a detached subtree of the
+// data tree, such as a single list entry or container, to be validated. When
+// it is specified, leafrefs within the value passed to Validate are validated
+// as though the value were the root of the data tree, and leafrefs whose
+// target cannot be resolved within it are not checked. Leafrefs with absolute
+// paths are only checked when the value is the fake root.
+type SkipNonLocalRefs struct{}
+
+// IsValidationOption ensures that SkipNonLocalRefs implements the
+// ValidationOption interface.
+func (*SkipNonLocalRefs) IsValidationOption() {}
+
+// EnforceUnsetListMinElements is a ValidationOption that specifies that the
+// min-elements constraint of lists and leaf-lists is enforced when they are
+// unset, i.e., when the Go map, slice or ordered map representing them is nil.
+// By default, only lists and leaf-lists that are set are checked, such that a
+// partially populated data tree can be validated. An unset list is only
+// checked when its closest ancestor that is not a non-presence container
+// exists (RFC 7950 §7.7.5).
+type EnforceUnsetListMinElements struct{}
+
+// IsValidationOption ensures that EnforceUnsetListMinElements implements the
+// ValidationOption interface.
+func (*EnforceUnsetListMinElements) IsValidationOption() {}
+
+// hasEnforceUnsetListMinElements returns true if there is an
+// EnforceUnsetListMinElements option within opts.
+func hasEnforceUnsetListMinElements(opts []ygot.ValidationOption) bool {
+	for _, o := range opts {
+		if _, ok := o.(*EnforceUnsetListMinElements); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate recursively validates the value of the given data tree struct
-// against the given schema.
+// against the given schema. The supplied options are passed on when
+// validating the descendants of value. It returns an error for each of the
+// issues with ErrorSeverity that ValidateReport would return. The
+// min-elements constraint of lists and leaf-lists that are unset is only
+// enforced when the EnforceUnsetListMinElements option is supplied.
 func Validate(schema *yang.Entry, value interface{}, opts ...ygot.ValidationOption) util.Errors {
+	var errs util.Errors
+	for _, issue := range ValidateReport(schema, value, opts...) {
+		if issue.Severity == ErrorSeverity {
+			errs = append(errs, issue.Err)
+		}
+	}
+	return errs
+}
+
+// validateNode implements Validate, returning errors for the issues of all
+// severities.
+func validateNode(schema *yang.Entry, value interface{}, opts ...ygot.ValidationOption) util.Errors {
 	// Nil value means the field is unset.
 	if util.IsValueNil(value) {
+		// An unset list or leaf-list has no elements, but must still
+		// satisfy its min-elements constraint if it is enforced. The
+		// caller is responsible for not validating an unset list whose
+		// min-elements constraint does not apply, see listAncestorExists.
+		if isUnsetList(schema, value) && hasEnforceUnsetListMinElements(opts) {
+			return validateListAttr(schema, nil)
+		}
 		return nil
 	}
 	if schema == nil {
 		return util.NewErrs(fmt.Errorf("nil schema for type %T, value %v", value, value))
 	}
 
+	if stats := hasValidationStats(opts); stats != nil {
+		stats.begin()
+		errs := validate(schema, value, opts...)
+		stats.end(schema.Path(), len(issueErrors(errs)))
+		return errs
+	}
+	return validate(schema, value, opts...)
+}
+
+// isUnsetList reports whether the nil value is the Go representation of the
+// list or leaf-list described by schema - i.e., a map, slice or ordered map
+// rather than an individual list entry.
+func isUnsetList(schema *yang.Entry, value interface{}) bool {
+	if schema == nil || schema.ListAttr == nil || (!schema.IsList() && !schema.IsLeafList()) || value == nil {
+		return false
+	}
+	if _, ok := value.(ygot.GoOrderedMap); ok {
+		return true
+	}
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Map, reflect.Slice:
+		return true
+	}
+	return false
+}
+
+// listAncestorExists reports whether the closest ancestor of the list or
+// leaf-list described by schema that is not a non-presence container exists,
+// such that the min-elements constraint of the list is enforced (RFC 7950
+// §7.7.5). The list is a field of the GoStruct structValue, which exists and
+// is described by structSchema. Since choice and case nodes do not have a Go
+// representation, the only such ancestor that may not exist is a case whose
+// data nodes are fields of structValue, which exists only if one of them is
+// set.
+func listAncestorExists(schema, structSchema *yang.Entry, structValue interface{}) bool {
+	for p := schema.Parent; p != nil && p != structSchema; p = p.Parent {
+		if p.IsCase() {
+			selected, _ := IsCaseSelected(p, structValue)
+			return len(selected) > 0
+		}
+	}
+	return true
+}
+
+// validate validates the non-nil value of the given data tree struct against
+// the given non-nil schema, using the supplied options.
+func validate(schema *yang.Entry, value interface{}, opts ...ygot.ValidationOption) util.Errors {
+
 	// TODO(robjs): Consider making this function a utility function when
 	// additional validation options are added here. Note that this code
 	// currently will accept multiple of the same option being specified,
@@ -72,20 +180,31 @@ func Validate(schema *yang.Entry, value interface{}, opts ...ygot.ValidationOpti
 	// explicitly returning an error.
 	var leafrefOpt *LeafrefOptions
 	var customValidOpt *CustomValidationOptions
+	var skipNonLocalRefs bool
 	for _, o := range opts {
 		switch v := o.(type) {
 		case *LeafrefOptions:
 			leafrefOpt = v
 		case *CustomValidationOptions:
 			customValidOpt = v
+		case *SkipNonLocalRefs:
+			skipNonLocalRefs = true
 		}
 	}
 
 	var errs util.Errors
+	if skipNonLocalRefs && schema.IsDir() {
+		// Leafref validation traverses the entire subtree, hence the option
+		// is not passed on when validating the descendants of value.
+		opts = withoutSkipNonLocalRefs(opts)
+		if !util.IsFakeRoot(schema) {
+			errs = validateLeafRefData(schema, value, leafrefOpt, true)
+		}
+	}
 	if util.IsFakeRoot(schema) {
 		// Leafref validation traverses entire tree from the root. Do this only
 		// once from the fakeroot.
-		errs = ValidateLeafRefData(schema, value, leafrefOpt)
+		errs = validateLeafRefData(schema, value, leafrefOpt, false)
 		// If CustomValidation is enabled, call the CustomValidateFunc
 		// and append the error, if any
 		gsv, ok := value.(ygot.GoStruct)
@@ -101,18 +220,30 @@ func Validate(schema *yang.Entry, value interface{}, opts ...ygot.ValidationOpti
 	switch {
 	case schema.IsLeaf():
 		return util.AppendErrs(errs, validateLeaf(schema, value))
-	case schema.IsContainer():
+	case schema.IsContainer(), util.IsRPCInputOutput(schema):
 		gsv, ok := value.(ygot.GoStruct)
 		if !ok {
 			return util.AppendErr(errs, fmt.Errorf("type %T is not a GoStruct for schema %s", value, schema.Name))
 		}
-		return util.AppendErrs(errs, validateContainer(schema, gsv))
+		return util.AppendErrs(errs, validateContainer(schema, gsv, opts...))
 	case schema.IsLeafList():
 		return util.AppendErrs(errs, validateLeafList(schema, value))
 	case schema.IsList():
-		return util.AppendErrs(errs, validateList(schema, value))
+		return util.AppendErrs(errs, validateList(schema, value, opts...))
 	case schema.IsChoice():
 		return util.AppendErrs(errs, util.NewErrs(fmt.Errorf("cannot pass choice schema %s to Validate", schema.Name)))
 	}
 	return util.AppendErrs(errs, util.NewErrs(fmt.Errorf("unknown schema type for type %T, value %v", value, value)))
 }
+
+// withoutSkipNonLocalRefs returns a copy of opts with any SkipNonLocalRefs
+// options removed.
+func withoutSkipNonLocalRefs(opts []ygot.ValidationOption) []ygot.ValidationOption {
+	var out []ygot.ValidationOption
+	for _, o := range opts {
+		if _, ok := o.(*SkipNonLocalRefs); !ok {
+			out = append(out, o)
+		}
+	}
+	return out
+}
@@ -17,6 +17,7 @@ package ytypes
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/openconfig/goyang/pkg/yang"
@@ -307,3 +308,211 @@ func TestValidate(t *testing.T) {
 	}
 
 }
+
+func TestValidateStats(t *testing.T) {
+	schema := &yang.Entry{
+		Name: "device",
+		Kind: yang.DirectoryEntry,
+		Annotation: map[string]interface{}{
+			"isFakeRoot": true,
+		},
+		Dir: map[string]*yang.Entry{
+			"leaf-one": {
+				Name: "leaf-one",
+				Kind: yang.LeafEntry,
+				Type: &yang.YangType{Kind: yang.Ystring},
+			},
+			"leaf-three": {
+				Name: "leaf-three",
+				Kind: yang.LeafEntry,
+				Type: &yang.YangType{
+					Kind:    yang.Ystring,
+					Pattern: []string{"^a.*"},
+				},
+			},
+		},
+	}
+	populateParentField(nil, schema)
+
+	stats := &ValidationStats{}
+	for i := 0; i < 2; i++ {
+		errs := Validate(schema, &FakeRootStruct{
+			LeafOne:   ygot.String("one"),
+			LeafThree: ygot.String("bad"),
+		}, stats)
+		if len(errs) != 1 {
+			t.Fatalf("Validate: got %d errors, want 1: %v", len(errs), errs)
+		}
+	}
+
+	want := map[string]struct {
+		count, violations int
+	}{
+		"/device":            {count: 2, violations: 2},
+		"/device/leaf-one":   {count: 2, violations: 0},
+		"/device/leaf-three": {count: 2, violations: 2},
+	}
+	if len(stats.Subtrees) != len(want) {
+		t.Fatalf("ValidationStats: got subtrees %v, want %v", stats.Subtrees, want)
+	}
+	for path, w := range want {
+		st, ok := stats.Subtrees[path]
+		if !ok {
+			t.Errorf("ValidationStats: did not get statistics for %s", path)
+			continue
+		}
+		if st.Count != w.count || st.Violations != w.violations {
+			t.Errorf("ValidationStats %s: got (count: %d, violations: %d), want (count: %d, violations: %d)", path, st.Count, st.Violations, w.count, w.violations)
+		}
+		if st.SelfDuration < 0 || st.SelfDuration > st.Duration {
+			t.Errorf("ValidationStats %s: got invalid durations (self: %v, total: %v)", path, st.SelfDuration, st.Duration)
+		}
+	}
+	if root, leaf := stats.Subtrees["/device"], stats.Subtrees["/device/leaf-three"]; root.Duration < leaf.Duration {
+		t.Errorf("ValidationStats: root duration %v is less than child duration %v", root.Duration, leaf.Duration)
+	}
+
+	hs := stats.Hotspots(2)
+	if len(hs) != 2 || hs[0].SelfDuration < hs[1].SelfDuration {
+		t.Errorf("Hotspots(2): got unexpected hotspots %v", hs)
+	}
+	if got := len(stats.Hotspots(0)); got != 3 {
+		t.Errorf("Hotspots(0): got %d hotspots, want 3", got)
+	}
+	if got := stats.Report(1); !strings.Contains(got, hs[0].Path) {
+		t.Errorf("Report(1): did not contain hottest path %s, got:\n%s", hs[0].Path, got)
+	}
+}
+
+type optsDevice struct {
+	Interfaces *optsInterfaces `path:"interfaces"`
+}
+
+func (*optsDevice) IsYANGGoStruct() {}
+
+type optsInterfaces struct {
+	Interface map[string]*optsInterface `path:"interface"`
+}
+
+func (*optsInterfaces) IsYANGGoStruct() {}
+
+type optsInterface struct {
+	Name   *string              `path:"name"`
+	Config *optsInterfaceConfig `path:"config"`
+}
+
+func (*optsInterface) IsYANGGoStruct() {}
+
+type optsInterfaceConfig struct {
+	Name    *string `path:"name"`
+	NameRef *string `path:"name-ref"`
+}
+
+func (*optsInterfaceConfig) IsYANGGoStruct() {}
+
+// TestValidateOptionsInNestedNodes checks that the options supplied to
+// Validate, which are passed on when validating the descendants of the
+// supplied value, are applied once rather than at each node that they
+// reach.
+func TestValidateOptionsInNestedNodes(t *testing.T) {
+	fakeRootSchema := &yang.Entry{
+		Name: "device",
+		Kind: yang.DirectoryEntry,
+		Dir: map[string]*yang.Entry{
+			"interfaces": {
+				Name: "interfaces",
+				Kind: yang.DirectoryEntry,
+				Dir: map[string]*yang.Entry{
+					"interface": {
+						Name:     "interface",
+						Kind:     yang.DirectoryEntry,
+						ListAttr: yang.NewDefaultListAttr(),
+						Key:      "name",
+						Dir: map[string]*yang.Entry{
+							"name": {Name: "name", Kind: yang.LeafEntry, Type: &yang.YangType{Kind: yang.Ystring}},
+							"config": {
+								Name: "config",
+								Kind: yang.DirectoryEntry,
+								Dir: map[string]*yang.Entry{
+									"name":     {Name: "name", Kind: yang.LeafEntry, Type: &yang.YangType{Kind: yang.Ystring}},
+									"name-ref": {Name: "name-ref", Kind: yang.LeafEntry, Type: &yang.YangType{Kind: yang.Yleafref, Path: "../name"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Annotation: map[string]interface{}{"isFakeRoot": true},
+	}
+	addParents(fakeRootSchema)
+
+	// newDevice returns a device with three interfaces whose name-ref
+	// leaves do not refer to their names.
+	newDevice := func() *optsDevice {
+		return &optsDevice{
+			Interfaces: &optsInterfaces{
+				Interface: map[string]*optsInterface{
+					"eth0": {Name: ygot.String("eth0"), Config: &optsInterfaceConfig{Name: ygot.String("eth0"), NameRef: ygot.String("eth1")}},
+					"eth1": {Name: ygot.String("eth1"), Config: &optsInterfaceConfig{Name: ygot.String("eth1"), NameRef: ygot.String("eth0")}},
+					"eth2": {Name: ygot.String("eth2"), Config: &optsInterfaceConfig{NameRef: ygot.String("eth2")}},
+				},
+			},
+		}
+	}
+
+	var customCalls int
+	countingValidate := func(ygot.GoStruct) error {
+		customCalls++
+		return nil
+	}
+
+	tests := []struct {
+		desc            string
+		inSchema        *yang.Entry
+		inValue         interface{}
+		inOpts          []ygot.ValidationOption
+		wantErrs        int
+		wantCustomCalls int
+	}{{
+		desc:     "leafrefs in nested list validated once",
+		inSchema: fakeRootSchema,
+		inValue:  newDevice(),
+		wantErrs: 3,
+	}, {
+		desc:     "leafref options apply to nested list",
+		inSchema: fakeRootSchema,
+		inValue:  newDevice(),
+		inOpts:   []ygot.ValidationOption{&LeafrefOptions{IgnoreMissingData: true}},
+	}, {
+		desc:            "custom validation called once",
+		inSchema:        fakeRootSchema,
+		inValue:         newDevice(),
+		inOpts:          []ygot.ValidationOption{&CustomValidationOptions{FakeRootCustomValidate: countingValidate}},
+		wantErrs:        3,
+		wantCustomCalls: 1,
+	}, {
+		desc:     "local leafrefs of detached subtree validated once",
+		inSchema: fakeRootSchema.Dir["interfaces"],
+		inValue:  newDevice().Interfaces,
+		inOpts:   []ygot.ValidationOption{&SkipNonLocalRefs{}},
+		wantErrs: 3,
+	}, {
+		desc:     "leafrefs of detached subtree not validated without option",
+		inSchema: fakeRootSchema.Dir["interfaces"],
+		inValue:  newDevice().Interfaces,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			customCalls = 0
+			errs := Validate(tt.inSchema, tt.inValue, tt.inOpts...)
+			if len(errs) != tt.wantErrs {
+				t.Errorf("Validate: got %d errors, want %d: %v", len(errs), tt.wantErrs, errs)
+			}
+			if customCalls != tt.wantCustomCalls {
+				t.Errorf("Validate: custom validation called %d times, want %d", customCalls, tt.wantCustomCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/protobuf/proto"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// ValidationIssueKind describes the kind of constraint that a node of a data
+// tree does not satisfy.
+type ValidationIssueKind int
+
+const (
+	// OtherIssue is used for issues that do not have a more specific kind,
+	// for example those caused by an invalid schema.
+	OtherIssue ValidationIssueKind = iota
+	// TypeIssue indicates that the Go type of a value does not match the
+	// type of its schema.
+	TypeIssue
+	// RangeIssue indicates that a numeric value is outside of the ranges
+	// allowed by its type.
+	RangeIssue
+	// LengthIssue indicates that the length of a string or binary value is
+	// outside of the lengths allowed by its type.
+	LengthIssue
+	// PatternIssue indicates that a string value does not match a pattern of
+	// its type.
+	PatternIssue
+	// LeafrefIssue indicates that the value of a leafref does not match any
+	// of the nodes that its path refers to.
+	LeafrefIssue
+	// KeyIssue indicates that the key of a list entry does not match the
+	// values of its key leaves.
+	KeyIssue
+	// ElementsIssue indicates that a list has fewer than its min-elements or
+	// more than its max-elements entries.
+	ElementsIssue
+	// UniqueIssue indicates that the entries of a list do not satisfy one of
+	// its unique statements.
+	UniqueIssue
+	// ChoiceIssue indicates that more than one case of a choice is selected.
+	ChoiceIssue
+)
+
+// String returns a human readable name for the ValidationIssueKind.
+func (k ValidationIssueKind) String() string {
+	switch k {
+	case OtherIssue:
+		return "other"
+	case TypeIssue:
+		return "type"
+	case RangeIssue:
+		return "range"
+	case LengthIssue:
+		return "length"
+	case PatternIssue:
+		return "pattern"
+	case LeafrefIssue:
+		return "leafref"
+	case KeyIssue:
+		return "key"
+	case ElementsIssue:
+		return "elements"
+	case UniqueIssue:
+		return "unique"
+	case ChoiceIssue:
+		return "choice"
+	}
+	return fmt.Sprintf("ValidationIssueKind(%d)", int(k))
+}
+
+// ValidationSeverity describes whether a ValidationIssue causes validation to
+// fail.
+type ValidationSeverity int
+
+const (
+	// ErrorSeverity is used for issues that cause validation to fail. Each
+	// such issue corresponds to an error returned by Validate.
+	ErrorSeverity ValidationSeverity = iota
+	// WarningSeverity is used for issues that do not cause validation to
+	// fail, such as unsatisfied leafrefs when LeafrefOptions are supplied.
+	WarningSeverity
+)
+
+// String returns a human readable name for the ValidationSeverity.
+func (s ValidationSeverity) String() string {
+	switch s {
+	case ErrorSeverity:
+		return "error"
+	case WarningSeverity:
+		return "warning"
+	}
+	return fmt.Sprintf("ValidationSeverity(%d)", int(s))
+}
+
+// ValidationIssue is a single issue found when validating a data tree.
+type ValidationIssue struct {
+	// Path is the path of the node that the issue relates to, relative to
+	// the value that was validated. The keys of the entries of keyed lists
+	// are included in the path.
+	Path *gpb.Path
+	// Kind is the kind of constraint that the node does not satisfy.
+	Kind ValidationIssueKind
+	// Msg is a human readable description of the issue.
+	Msg string
+	// Severity is the severity of the issue.
+	Severity ValidationSeverity
+	// Err is the error describing the issue, whose message is Msg. For
+	// issues of kind RangeIssue, LengthIssue and PatternIssue, its chain
+	// contains a RestrictionError describing the restriction.
+	Err error
+}
+
+// ValidateReport validates the value of the given data tree struct against the
+// given schema in the same way as Validate, returning each of the issues that
+// are found, along with the path of the node that they relate to.
+func ValidateReport(schema *yang.Entry, value interface{}, opts ...ygot.ValidationOption) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, err := range validateNode(schema, value, opts...) {
+		issue := ValidationIssue{
+			Path: &gpb.Path{},
+			Msg:  err.Error(),
+			Err:  err,
+		}
+		var ie *issueError
+		if errors.As(err, &ie) {
+			issue.Kind = ie.kind
+			issue.Severity = ie.severity
+			for _, e := range ie.path {
+				issue.Path.Elem = append(issue.Path.Elem, proto.Clone(e).(*gpb.PathElem))
+			}
+			// Issues found within the entries of a list that was
+			// validated directly start with the keys of the entry.
+			if len(issue.Path.Elem) > 0 && issue.Path.Elem[0].Name == "" && schema != nil {
+				issue.Path.Elem[0].Name = schema.Name
+			}
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// issueError annotates an error found during validation with the details that
+// are reported in the corresponding ValidationIssue. An error chain contains at
+// most one issueError.
+type issueError struct {
+	kind     ValidationIssueKind
+	severity ValidationSeverity
+	// path is the path of the node that the issue relates to, relative to
+	// the node whose validation returned the error. If the first element
+	// has no name, it holds the keys of the list entry that was validated.
+	path []*gpb.PathElem
+	err  error
+}
+
+// Error returns the message of the annotated error.
+func (e *issueError) Error() string { return e.err.Error() }
+
+// Unwrap returns the annotated error.
+func (e *issueError) Unwrap() error { return e.err }
+
+// asIssueError returns the issueError in the chain of err, adding one that
+// wraps err, which is then returned as the error to use, if there is none.
+func asIssueError(err error) (*issueError, error) {
+	var ie *issueError
+	if !errors.As(err, &ie) {
+		ie = &issueError{err: err}
+		err = ie
+	}
+	return ie, err
+}
+
+// withIssueKind returns err annotated with the supplied kind, unless err
+// already has a kind, or is nil.
+func withIssueKind(kind ValidationIssueKind, err error) error {
+	if err == nil {
+		return nil
+	}
+	ie, err := asIssueError(err)
+	if ie.kind == OtherIssue {
+		ie.kind = kind
+	}
+	return err
+}
+
+// withIssueKindErrs returns errs with each error annotated with the supplied
+// kind, unless it already has a kind.
+func withIssueKindErrs(kind ValidationIssueKind, errs util.Errors) util.Errors {
+	var out util.Errors
+	for _, err := range errs {
+		out = append(out, withIssueKind(kind, err))
+	}
+	return out
+}
+
+// withIssueSeverity returns errs with each error annotated with the supplied
+// severity.
+func withIssueSeverity(severity ValidationSeverity, errs util.Errors) util.Errors {
+	var out util.Errors
+	for _, err := range errs {
+		ie, err := asIssueError(err)
+		ie.severity = severity
+		out = append(out, err)
+	}
+	return out
+}
+
+// withIssuePath returns errs with the supplied path elements prepended to the
+// path of the issue that each error describes. This is used as errors are
+// returned from the validation of a child node to that of its parent. If the
+// path of an issue starts with the keys of a list entry, they are added to the
+// last of the supplied elements, which must be the name of the list.
+func withIssuePath(errs util.Errors, elems []*gpb.PathElem) util.Errors {
+	var out util.Errors
+	for _, err := range errs {
+		ie, err := asIssueError(err)
+		path := make([]*gpb.PathElem, 0, len(elems)+len(ie.path))
+		for _, e := range elems {
+			path = append(path, proto.Clone(e).(*gpb.PathElem))
+		}
+		rest := ie.path
+		if len(path) > 0 && len(rest) > 0 && rest[0].Name == "" {
+			path[len(path)-1].Key = rest[0].Key
+			rest = rest[1:]
+		}
+		ie.path = append(path, rest...)
+		out = append(out, err)
+	}
+	return out
+}
+
+// hasErrorSeverity reports whether err describes an issue with ErrorSeverity.
+func hasErrorSeverity(err error) bool {
+	var ie *issueError
+	return !errors.As(err, &ie) || ie.severity == ErrorSeverity
+}
+
+// issueErrors returns the errors in errs that describe issues with
+// ErrorSeverity.
+func issueErrors(errs util.Errors) util.Errors {
+	var out util.Errors
+	for _, err := range errs {
+		if hasErrorSeverity(err) {
+			out = append(out, err)
+		}
+	}
+	return out
+}
+
+// fieldPathElems returns the path elements of the first path in the path tag
+// of the supplied struct field, with any module prefixes removed.
+func fieldPathElems(ft reflect.StructField) []*gpb.PathElem {
+	paths, err := util.SchemaPaths(ft)
+	if err != nil || len(paths) == 0 {
+		return nil
+	}
+	var elems []*gpb.PathElem
+	for _, p := range paths[0] {
+		elems = append(elems, &gpb.PathElem{Name: util.StripModulePrefix(p)})
+	}
+	return elems
+}
+
+// listEntryElem returns the path element that holds the keys of the supplied
+// list entry, to be used as the first element of the paths of the issues found
+// within it.
+func listEntryElem(entry interface{}) *gpb.PathElem {
+	return &gpb.PathElem{Key: listEntryKeys(entry)}
+}
+
+// listEntryKeys returns the values of the keys of the supplied list entry, or
+// nil if they cannot be determined.
+func listEntryKeys(entry interface{}) map[string]string {
+	kh, ok := entry.(ygot.KeyHelperGoStruct)
+	if !ok || util.IsValueNil(entry) {
+		return nil
+	}
+	km, err := kh.ΛListKeyMap()
+	if err != nil {
+		return nil
+	}
+	keys := map[string]string{}
+	for k, v := range km {
+		s, err := ygot.KeyValueAsString(v)
+		if err != nil {
+			return nil
+		}
+		keys[k] = s
+	}
+	return keys
+}
+
+// nodeInfoPath returns the path elements of the node ni, which is visited by
+// ForEachField, relative to the root of the traversal.
+func nodeInfoPath(ni *util.NodeInfo) []*gpb.PathElem {
+	var rev []*gpb.PathElem
+	var keys map[string]string
+	for ; ni != nil && ni.Parent != nil; ni = ni.Parent {
+		if ni.Parent.Schema != nil && ni.Parent.Schema.IsList() {
+			// ni is an entry of the list at its parent, whose path
+			// holds the name of the list.
+			if ni.FieldValue.IsValid() {
+				keys = listEntryKeys(ni.FieldValue.Interface())
+			}
+			continue
+		}
+		for i := len(ni.PathFromParent) - 1; i >= 0; i-- {
+			e := &gpb.PathElem{Name: util.StripModulePrefix(ni.PathFromParent[i])}
+			if i == len(ni.PathFromParent)-1 {
+				e.Key = keys
+			}
+			rev = append(rev, e)
+		}
+		keys = nil
+	}
+	elems := make([]*gpb.PathElem, 0, len(rev))
+	for i := len(rev) - 1; i >= 0; i-- {
+		elems = append(elems, rev[i])
+	}
+	return elems
+}
@@ -0,0 +1,223 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// reportSchema returns the schema of a fake root containing a list interface,
+// keyed by name, within the container interfaces, and the leafref ref to the
+// names of the interfaces.
+func reportSchema() *yang.Entry {
+	root := &yang.Entry{
+		Name:       "device",
+		Kind:       yang.DirectoryEntry,
+		Annotation: map[string]interface{}{"isFakeRoot": true},
+		Dir: map[string]*yang.Entry{
+			"interfaces": {
+				Name: "interfaces",
+				Kind: yang.DirectoryEntry,
+				Dir: map[string]*yang.Entry{
+					"interface": {
+						Name:     "interface",
+						Kind:     yang.DirectoryEntry,
+						Key:      "name",
+						Config:   yang.TSTrue,
+						ListAttr: yang.NewDefaultListAttr(),
+						Dir: map[string]*yang.Entry{
+							"name": {
+								Name: "name",
+								Kind: yang.LeafEntry,
+								Type: &yang.YangType{Kind: yang.Ystring, Pattern: []string{"eth[0-9]+"}},
+							},
+							"mtu": {
+								Name: "mtu",
+								Kind: yang.LeafEntry,
+								Type: &yang.YangType{Kind: yang.Yuint16, Range: yang.YangRange{{Min: yang.FromInt(68), Max: yang.FromInt(9000)}}},
+							},
+							"description": {
+								Name: "description",
+								Kind: yang.LeafEntry,
+								Type: &yang.YangType{Kind: yang.Ystring, Length: yang.YangRange{{Min: yang.FromInt(0), Max: yang.FromInt(8)}}},
+							},
+						},
+					},
+				},
+			},
+			"ref": {
+				Name: "ref",
+				Kind: yang.LeafEntry,
+				Type: &yang.YangType{Kind: yang.Yleafref, Path: "/interfaces/interface/name"},
+			},
+		},
+	}
+	var setParents func(e *yang.Entry)
+	setParents = func(e *yang.Entry) {
+		for _, c := range e.Dir {
+			c.Parent = e
+			setParents(c)
+		}
+	}
+	setParents(root)
+	return root
+}
+
+type reportDevice struct {
+	Interface map[string]*reportInterface `path:"interfaces/interface"`
+	Ref       *string                     `path:"ref"`
+}
+
+func (*reportDevice) IsYANGGoStruct()                          {}
+func (*reportDevice) ΛValidate(...ygot.ValidationOption) error { return nil }
+func (*reportDevice) ΛEnumTypeMap() map[string][]reflect.Type  { return nil }
+func (*reportDevice) ΛBelongingModule() string                 { return "" }
+
+type reportInterface struct {
+	Name        *string `path:"name"`
+	Mtu         *uint16 `path:"mtu"`
+	Description *string `path:"description"`
+}
+
+func (*reportInterface) IsYANGGoStruct()                          {}
+func (*reportInterface) ΛValidate(...ygot.ValidationOption) error { return nil }
+func (*reportInterface) ΛEnumTypeMap() map[string][]reflect.Type  { return nil }
+func (*reportInterface) ΛBelongingModule() string                 { return "" }
+func (i *reportInterface) ΛListKeyMap() (map[string]interface{}, error) {
+	return map[string]interface{}{"name": *i.Name}, nil
+}
+
+func TestValidateReport(t *testing.T) {
+	intf := func(name string, mtu uint16, desc string) *reportInterface {
+		return &reportInterface{Name: ygot.String(name), Mtu: ygot.Uint16(mtu), Description: ygot.String(desc)}
+	}
+
+	tests := []struct {
+		desc     string
+		inSchema *yang.Entry
+		inValue  interface{}
+		inOpts   []ygot.ValidationOption
+		want     []ValidationIssue
+	}{{
+		desc:     "valid",
+		inSchema: reportSchema(),
+		inValue: &reportDevice{
+			Interface: map[string]*reportInterface{"eth0": intf("eth0", 1500, "uplink")},
+			Ref:       ygot.String("eth0"),
+		},
+	}, {
+		desc:     "range",
+		inSchema: reportSchema(),
+		inValue: &reportDevice{
+			Interface: map[string]*reportInterface{"eth0": intf("eth0", 10, "uplink")},
+		},
+		want: []ValidationIssue{{
+			Path: mustPath("/interfaces/interface[name=eth0]/mtu"),
+			Kind: RangeIssue,
+		}},
+	}, {
+		desc:     "length and pattern",
+		inSchema: reportSchema(),
+		inValue: &reportDevice{
+			Interface: map[string]*reportInterface{"xe0": intf("xe0", 1500, "uplink to core")},
+		},
+		want: []ValidationIssue{{
+			Path: mustPath("/interfaces/interface[name=xe0]/description"),
+			Kind: LengthIssue,
+		}, {
+			Path: mustPath("/interfaces/interface[name=xe0]/name"),
+			Kind: PatternIssue,
+		}},
+	}, {
+		desc:     "key",
+		inSchema: reportSchema(),
+		inValue: &reportDevice{
+			Interface: map[string]*reportInterface{"eth0": intf("eth1", 1500, "uplink")},
+		},
+		want: []ValidationIssue{{
+			Path: mustPath("/interfaces/interface[name=eth1]"),
+			Kind: KeyIssue,
+		}},
+	}, {
+		desc:     "leafref",
+		inSchema: reportSchema(),
+		inValue: &reportDevice{
+			Interface: map[string]*reportInterface{"eth0": intf("eth0", 1500, "uplink")},
+			Ref:       ygot.String("eth1"),
+		},
+		want: []ValidationIssue{{
+			Path: mustPath("/ref"),
+			Kind: LeafrefIssue,
+		}},
+	}, {
+		desc:     "leafref ignored by options",
+		inSchema: reportSchema(),
+		inValue: &reportDevice{
+			Interface: map[string]*reportInterface{"eth0": intf("eth0", 1500, "uplink")},
+			Ref:       ygot.String("eth1"),
+		},
+		inOpts: []ygot.ValidationOption{&LeafrefOptions{}},
+		want: []ValidationIssue{{
+			Path:     mustPath("/ref"),
+			Kind:     LeafrefIssue,
+			Severity: WarningSeverity,
+		}},
+	}, {
+		desc:     "list validated directly",
+		inSchema: reportSchema().Dir["interfaces"].Dir["interface"],
+		inValue:  map[string]*reportInterface{"eth0": intf("eth0", 10, "uplink")},
+		want: []ValidationIssue{{
+			Path: mustPath("/interface[name=eth0]/mtu"),
+			Kind: RangeIssue,
+		}},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := ValidateReport(tt.inSchema, tt.inValue, tt.inOpts...)
+			if diff := cmp.Diff(tt.want, got,
+				protocmp.Transform(),
+				cmpopts.IgnoreFields(ValidationIssue{}, "Msg", "Err"),
+				cmpopts.SortSlices(func(a, b ValidationIssue) bool { return a.Kind < b.Kind }),
+			); diff != "" {
+				t.Errorf("ValidateReport: did not get expected issues (-want, +got):\n%s", diff)
+			}
+
+			var wantErrs []string
+			for _, issue := range got {
+				if issue.Msg != issue.Err.Error() {
+					t.Errorf("ValidateReport: got issue with message %q, want the message of its error %q", issue.Msg, issue.Err.Error())
+				}
+				if issue.Severity == ErrorSeverity {
+					wantErrs = append(wantErrs, issue.Msg)
+				}
+			}
+			var gotErrs []string
+			for _, err := range Validate(tt.inSchema, tt.inValue, tt.inOpts...) {
+				gotErrs = append(gotErrs, err.Error())
+			}
+			if diff := cmp.Diff(wantErrs, gotErrs, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+				t.Errorf("Validate: did not get errors for the issues with ErrorSeverity (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openconfig/ygot/ygot"
+)
+
+// ValidationStats is a ValidationOption that causes Validate to collect the
+// time spent validating, and the number of violations found within, each
+// region of the schema. After validation, the collected statistics can be
+// inspected using Subtrees, or summarised using Hotspots and Report, to
+// determine which parts of a data tree are the most expensive to validate.
+//
+// A ValidationStats may be reused across multiple calls to Validate, in
+// which case the statistics are accumulated. It must not be used by
+// concurrent calls to Validate.
+type ValidationStats struct {
+	// Subtrees is the set of statistics collected, keyed by the schema path
+	// of the node at the root of each subtree.
+	Subtrees map[string]*SubtreeStats
+
+	// starts is the stack of start times of the subtrees that are
+	// currently being validated.
+	starts []time.Time
+	// childTimes is the stack of the total time spent validating the
+	// children of the subtrees that are currently being validated.
+	childTimes []time.Duration
+}
+
+// IsValidationOption ensures that ValidationStats implements the
+// ValidationOption interface.
+func (*ValidationStats) IsValidationOption() {}
+
+// SubtreeStats is the set of statistics collected for the subtree of the data
+// tree rooted at a particular schema node.
+type SubtreeStats struct {
+	// Path is the schema path of the node at the root of the subtree.
+	Path string
+	// Count is the number of times that a data tree node with the schema
+	// path was validated.
+	Count int
+	// Duration is the total time spent validating the subtree, including
+	// the time spent validating its descendants.
+	Duration time.Duration
+	// SelfDuration is the time spent validating the subtree, excluding the
+	// time spent validating its descendants.
+	SelfDuration time.Duration
+	// Violations is the number of validation errors found within the
+	// subtree, including those found within its descendants.
+	Violations int
+}
+
+// hasValidationStats returns the first ValidationStats from an opts slice, or
+// nil if there isn't one.
+func hasValidationStats(opts []ygot.ValidationOption) *ValidationStats {
+	for _, o := range opts {
+		switch v := o.(type) {
+		case *ValidationStats:
+			return v
+		}
+	}
+	return nil
+}
+
+// begin records the start of the validation of a subtree.
+func (s *ValidationStats) begin() {
+	s.starts = append(s.starts, time.Now())
+	s.childTimes = append(s.childTimes, 0)
+}
+
+// end records the end of the validation of the subtree that was most recently
+// begun, whose root has the supplied schema path, and in which the supplied
+// number of violations were found.
+func (s *ValidationStats) end(path string, violations int) {
+	n := len(s.starts) - 1
+	d := time.Since(s.starts[n])
+	childTime := s.childTimes[n]
+	s.starts, s.childTimes = s.starts[:n], s.childTimes[:n]
+	if n > 0 {
+		s.childTimes[n-1] += d
+	}
+
+	if s.Subtrees == nil {
+		s.Subtrees = map[string]*SubtreeStats{}
+	}
+	st, ok := s.Subtrees[path]
+	if !ok {
+		st = &SubtreeStats{Path: path}
+		s.Subtrees[path] = st
+	}
+	st.Count++
+	st.Duration += d
+	st.SelfDuration += d - childTime
+	st.Violations += violations
+}
+
+// Hotspots returns the statistics of the n schema regions with the highest
+// SelfDuration, i.e., the regions in which the most validation time was spent
+// excluding the time spent in their descendants, in descending order. If n is
+// less than or equal to zero, all regions are returned.
+func (s *ValidationStats) Hotspots(n int) []*SubtreeStats {
+	var st []*SubtreeStats
+	for _, v := range s.Subtrees {
+		st = append(st, v)
+	}
+	sort.Slice(st, func(i, j int) bool {
+		if st[i].SelfDuration != st[j].SelfDuration {
+			return st[i].SelfDuration > st[j].SelfDuration
+		}
+		return st[i].Path < st[j].Path
+	})
+	if n > 0 && n < len(st) {
+		st = st[:n]
+	}
+	return st
+}
+
+// Report returns a human-readable report of the n schema regions that are
+// the most expensive to validate, as determined by Hotspots.
+func (s *ValidationStats) Report(n int) string {
+	var b strings.Builder
+	b.WriteString("self\ttotal\tcount\tviolations\tpath\n")
+	for _, st := range s.Hotspots(n) {
+		b.WriteString(fmt.Sprintf("%v\t%v\t%d\t%d\t%s\n", st.SelfDuration, st.Duration, st.Count, st.Violations, st.Path))
+	}
+	return b.String()
+}
@@ -0,0 +1,376 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+)
+
+// Refer to: https://tools.ietf.org/html/rfc7950#section-13 for the XML
+// encoding of YANG-modelled data, and https://tools.ietf.org/html/rfc6241 for
+// the NETCONF protocol.
+
+// netconfWrapperElements is the set of NETCONF protocol elements that may
+// enclose YANG-modelled data in a NETCONF message, and which are removed
+// before the data is unmarshalled.
+var netconfWrapperElements = map[string]bool{
+	"rpc-reply": true,
+	"data":      true,
+	"config":    true,
+}
+
+// xmlNode is a node of a parsed XML document.
+type xmlNode struct {
+	// name is the namespace-qualified name of the element.
+	name xml.Name
+	// children is the set of child elements of the element, in document
+	// order.
+	children []*xmlNode
+	// text is the character data contained directly within the element.
+	text string
+	// ns is the set of XML namespaces that are in scope for the element,
+	// keyed by prefix. The default namespace is keyed by the empty prefix.
+	ns map[string]string
+}
+
+// UnmarshalXML unmarshals the YANG-modelled XML document in data, as used by
+// NETCONF, into the supplied GoStruct, using the given schema, which must be
+// the schema of dest. The document may contain either the children of the
+// node described by schema, or a single element corresponding to the node
+// itself. Data that is enclosed in a NETCONF <rpc-reply>, <data> or <config>
+// element is unwrapped prior to being unmarshalled.
+//
+// The XML document is converted into the equivalent RFC7951 JSON tree, and
+// then unmarshalled using the same rules as Unmarshal, such that the supplied
+// UnmarshalOpts, along with union and enumerated type handling, behave as
+// they do when unmarshalling JSON. Any values already in dest that are not
+// present in data are preserved.
+func UnmarshalXML(schema *yang.Entry, data []byte, dest ygot.GoStruct, opts ...UnmarshalOpt) error {
+	if schema == nil {
+		return fmt.Errorf("nil schema for parent type %T", dest)
+	}
+	if !schema.IsContainer() && !schema.IsList() {
+		return fmt.Errorf("UnmarshalXML: schema %s must be a container or list, got %v", schema.Name, schema.Kind)
+	}
+
+	nodes, err := parseXML(data)
+	if err != nil {
+		return err
+	}
+
+	// Remove any NETCONF protocol elements that enclose the data.
+	for len(nodes) == 1 && nodes[0].name.Space == ygot.NETCONFBaseNamespace && netconfWrapperElements[nodes[0].name.Local] {
+		nodes = nodes[0].children
+	}
+
+	// The document may contain the element corresponding to the schema
+	// itself rather than its children.
+	if len(nodes) == 1 && !util.IsFakeRoot(schema) && nodes[0].name.Local == schema.Name {
+		nodes = nodes[0].children
+	}
+
+	jsonTree, err := xmlToJSONTree(schema, nodes)
+	if err != nil {
+		return err
+	}
+	return Unmarshal(schema, dest, jsonTree, opts...)
+}
+
+// parseXML parses the supplied XML document, returning its top-level elements.
+func parseXML(data []byte) ([]*xmlNode, error) {
+	root := &xmlNode{}
+	stack := []*xmlNode{root}
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse XML: %v", err)
+		}
+
+		cur := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{name: t.Name, ns: inScopeNamespaces(cur.ns, t.Attr)}
+			cur.children = append(cur.children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			cur.text += string(t)
+		}
+	}
+	return root.children, nil
+}
+
+// inScopeNamespaces returns the XML namespaces that are in scope for an
+// element with the supplied attributes, whose parent has the in-scope
+// namespaces parent. The parent's map is not modified.
+func inScopeNamespaces(parent map[string]string, attrs []xml.Attr) map[string]string {
+	ns, copied := parent, false
+	for _, a := range attrs {
+		var prefix string
+		switch {
+		case a.Name.Space == "xmlns":
+			prefix = a.Name.Local
+		case a.Name.Space == "" && a.Name.Local == "xmlns":
+		default:
+			continue
+		}
+		if !copied {
+			// Copy on the first declaration, such that the parent's scope
+			// is unchanged.
+			ns, copied = make(map[string]string, len(parent)+1), true
+			for k, v := range parent {
+				ns[k] = v
+			}
+		}
+		ns[prefix] = a.Value
+	}
+	return ns
+}
+
+// dataChildren returns the data tree children of the supplied schema entry,
+// keyed by their names. The children of any choice and case statements are
+// included directly.
+func dataChildren(schema *yang.Entry) map[string]*yang.Entry {
+	m := map[string]*yang.Entry{}
+	for _, e := range util.FindFirstNonChoiceOrCase(schema) {
+		m[e.Name] = e
+	}
+	return m
+}
+
+// xmlToJSONTree converts the supplied XML elements, which are the children of
+// the node described by schema, into the JSON tree that represents the same
+// data per RFC7951. Elements that do not correspond to a node in the schema
+// are retained such that they are reported by the JSON unmarshalling in the
+// same way as unknown JSON fields.
+func xmlToJSONTree(schema *yang.Entry, nodes []*xmlNode) (map[string]interface{}, error) {
+	children := dataChildren(schema)
+	jt := map[string]interface{}{}
+	for _, n := range nodes {
+		name := n.name.Local
+		cschema, ok := children[name]
+		if !ok {
+			jt[name] = unknownXMLToJSON(n)
+			continue
+		}
+		if ns := cschema.Namespace(); ns != nil && ns.Name != "" && n.name.Space != "" && n.name.Space != ns.Name {
+			return nil, fmt.Errorf("element %s has namespace %s, expected %s", name, n.name.Space, ns.Name)
+		}
+
+		switch {
+		case cschema.IsList():
+			v, err := xmlToJSONTree(cschema, n.children)
+			if err != nil {
+				return nil, err
+			}
+			l, _ := jt[name].([]interface{})
+			jt[name] = append(l, v)
+		case cschema.IsContainer():
+			v, err := xmlToJSONTree(cschema, n.children)
+			if err != nil {
+				return nil, err
+			}
+			// Merge any repeated instances of the same container.
+			if existing, ok := jt[name].(map[string]interface{}); ok {
+				for k, ev := range v {
+					existing[k] = ev
+				}
+				continue
+			}
+			jt[name] = v
+		case cschema.IsLeafList():
+			v, err := xmlToJSONScalar(cschema, n)
+			if err != nil {
+				return nil, err
+			}
+			l, _ := jt[name].([]interface{})
+			jt[name] = append(l, v)
+		case cschema.IsLeaf():
+			v, err := xmlToJSONScalar(cschema, n)
+			if err != nil {
+				return nil, err
+			}
+			jt[name] = v
+		default:
+			jt[name] = unknownXMLToJSON(n)
+		}
+	}
+	return jt, nil
+}
+
+// unknownXMLToJSON converts an XML element for which there is no schema to
+// a JSON value, by treating elements with children as containers, and all
+// other elements as strings.
+func unknownXMLToJSON(n *xmlNode) interface{} {
+	if len(n.children) == 0 {
+		return strings.TrimSpace(n.text)
+	}
+	m := map[string]interface{}{}
+	for _, c := range n.children {
+		m[c.name.Local] = unknownXMLToJSON(c)
+	}
+	return m
+}
+
+// xmlToJSONScalar converts the text of the XML element n, corresponding to
+// the leaf or leaf-list with the supplied schema, into the value that is used
+// for the leaf's type in RFC7951 JSON.
+func xmlToJSONScalar(schema *yang.Entry, n *xmlNode) (interface{}, error) {
+	rs, err := util.ResolveIfLeafRef(schema)
+	if err != nil {
+		return nil, err
+	}
+	if rs.Type == nil {
+		return nil, fmt.Errorf("schema %s has nil type", schema.Name)
+	}
+	return xmlTextToJSON(rs.Type, strings.TrimSpace(n.text), n.ns)
+}
+
+// xmlTextToJSON converts the XML text of a value of the supplied YANG type to
+// the value used for the type in RFC7951 JSON. ns is the set of XML
+// namespaces in scope for the element containing the value, keyed by prefix.
+func xmlTextToJSON(t *yang.YangType, text string, ns map[string]string) (interface{}, error) {
+	switch t.Kind {
+	case yang.Yint8, yang.Yint16, yang.Yint32, yang.Yuint8, yang.Yuint16, yang.Yuint32:
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q as %v: %v", text, t.Kind, err)
+		}
+		return f, nil
+	case yang.Ybool:
+		b, err := strconv.ParseBool(text)
+		if err != nil || (text != "true" && text != "false") {
+			return nil, fmt.Errorf("cannot parse %q as boolean", text)
+		}
+		return b, nil
+	case yang.Yempty:
+		return []interface{}{nil}, nil
+	case yang.Yidentityref:
+		return xmlIdentityrefToJSON(t, text, ns)
+	case yang.Yunion:
+		return xmlUnionTextToJSON(t, text, ns), nil
+	default:
+		// All other types, including 64-bit integers and decimal64, are
+		// represented as strings in RFC7951 JSON.
+		return text, nil
+	}
+}
+
+// xmlUnionTextToJSON converts the XML text of a union value to the value used
+// in RFC7951 JSON. Since the XML encoding does not distinguish between the
+// member types of the union, the member types are checked in the order in
+// which they are defined, and the value is converted according to the first
+// member type that the text is valid for and has a distinct JSON
+// representation. Since the JSON unmarshalling of unions checks each member
+// type, the value is otherwise left as a string.
+func xmlUnionTextToJSON(t *yang.YangType, text string, ns map[string]string) interface{} {
+	for _, mt := range util.FlattenedTypes(t.Type) {
+		switch mt.Kind {
+		case yang.Ystring:
+			return text
+		case yang.Yint8, yang.Yint16, yang.Yint32, yang.Yuint8, yang.Yuint16, yang.Yuint32, yang.Ybool, yang.Yidentityref:
+			if v, err := xmlTextToJSON(mt, text, ns); err == nil {
+				return v
+			}
+		}
+	}
+	return text
+}
+
+// xmlIdentityrefToJSON converts the XML text of an identityref value, which
+// is of the form prefix:name, to the module-qualified name used in RFC7951
+// JSON. Per RFC7950 Section 9.10.3, the prefix is resolved using the XML
+// namespaces in scope for the element, ns, with the default namespace being
+// used where the value has no prefix. Where the module that defines an
+// identity is not known from the schema, the identity is matched by name
+// alone.
+func xmlIdentityrefToJSON(t *yang.YangType, text string, ns map[string]string) (interface{}, error) {
+	prefix, name := "", text
+	if i := strings.Index(text, ":"); i != -1 {
+		prefix, name = text[:i], text[i+1:]
+	}
+	uri, ok := ns[prefix]
+	if !ok {
+		return nil, fmt.Errorf("no XML namespace is declared for prefix %q of identityref value %q", prefix, text)
+	}
+	if t.IdentityBase == nil {
+		return nil, fmt.Errorf("identityref type %s has nil base", t.Name)
+	}
+
+	for _, id := range derivedIdentities(t.IdentityBase) {
+		if id.Name != name {
+			continue
+		}
+		m := identityModule(id)
+		if m == nil || m.Namespace == nil {
+			return name, nil
+		}
+		if m.Namespace.Name == uri {
+			return fmt.Sprintf("%s:%s", m.Name, name), nil
+		}
+	}
+	return nil, fmt.Errorf("%q is not a valid value for identityref with base %s, no identity %s is defined in namespace %s", text, t.IdentityBase.Name, name, uri)
+}
+
+// derivedIdentities returns all identities that are derived from the
+// supplied base identity, including those derived indirectly.
+func derivedIdentities(base *yang.Identity) []*yang.Identity {
+	var ids []*yang.Identity
+	seen := map[*yang.Identity]bool{}
+	var walk func(*yang.Identity)
+	walk = func(i *yang.Identity) {
+		for _, v := range i.Values {
+			if seen[v] {
+				continue
+			}
+			seen[v] = true
+			ids = append(ids, v)
+			walk(v)
+		}
+	}
+	walk(base)
+	return ids
+}
+
+// identityModule returns the module in which the supplied identity is
+// defined, or nil if it cannot be determined. For identities defined in a
+// submodule, the module to which the submodule belongs is returned.
+func identityModule(id *yang.Identity) *yang.Module {
+	if id.Parent == nil {
+		return nil
+	}
+	m := yang.RootNode(id)
+	if m == nil || m.Kind() != "submodule" {
+		return m
+	}
+	if m.BelongsTo == nil || m.Modules == nil {
+		return nil
+	}
+	return m.Modules.Modules[m.BelongsTo.Name]
+}
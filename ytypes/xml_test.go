@@ -0,0 +1,279 @@
+// Copyright 2024 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/testutil"
+	"github.com/openconfig/ygot/ygot"
+)
+
+type xmlTestRoot struct {
+	Interface map[string]*xmlTestInterface `path:"interfaces/interface"`
+	Hostname  *string                      `path:"system/config/hostname"`
+}
+
+func (*xmlTestRoot) IsYANGGoStruct() {}
+
+type xmlTestInterface struct {
+	Name     *string             `path:"config/name|name"`
+	Mtu      *uint16             `path:"config/mtu"`
+	Enabled  *bool               `path:"config/enabled"`
+	Counter  *uint64             `path:"config/counter"`
+	Tag      []string            `path:"config/tag"`
+	Type     EnumType            `path:"config/type"`
+	Kind     EnumType            `path:"config/kind"`
+	Loopback YANGEmpty           `path:"config/loopback"`
+	Id       UnionLeafTypeSimple `path:"config/id"`
+}
+
+func (*xmlTestInterface) IsYANGGoStruct()                         {}
+func (*xmlTestInterface) ΛEnumTypeMap() map[string][]reflect.Type { return nil }
+
+func (*xmlTestInterface) To_UnionLeafTypeSimple(i interface{}) (UnionLeafTypeSimple, error) {
+	switch v := i.(type) {
+	case string:
+		return testutil.UnionString(v), nil
+	case uint32:
+		return testutil.UnionUint32(v), nil
+	}
+	return nil, fmt.Errorf("cannot convert %v to UnionLeafTypeSimple, unknown union type, got: %T, want any of [string, uint32]", i, i)
+}
+
+func xmlTestSchema() *yang.Entry {
+	leaf := func(name string, k yang.TypeKind) *yang.Entry {
+		return &yang.Entry{Name: name, Kind: yang.LeafEntry, Type: &yang.YangType{Kind: k}}
+	}
+	identMod := &yang.Module{Name: "test-ident", Namespace: &yang.Value{Name: "urn:test-ident"}}
+	identBase := &yang.Identity{Name: "BASE", Parent: identMod}
+	identBase.Values = []*yang.Identity{{Name: "E_VALUE_FORTY_TWO", Parent: identMod}}
+	config := &yang.Entry{
+		Name: "config",
+		Kind: yang.DirectoryEntry,
+		Dir: map[string]*yang.Entry{
+			"name":     leaf("name", yang.Ystring),
+			"mtu":      leaf("mtu", yang.Yuint16),
+			"enabled":  leaf("enabled", yang.Ybool),
+			"counter":  leaf("counter", yang.Yuint64),
+			"type":     leaf("type", yang.Yenum),
+			"loopback": leaf("loopback", yang.Yempty),
+			"kind": {
+				Name: "kind",
+				Kind: yang.LeafEntry,
+				Type: &yang.YangType{Kind: yang.Yidentityref, IdentityBase: identBase},
+			},
+			"tag": {
+				Name:     "tag",
+				Kind:     yang.LeafEntry,
+				ListAttr: yang.NewDefaultListAttr(),
+				Type:     &yang.YangType{Kind: yang.Ystring},
+			},
+			"id": {
+				Name: "id",
+				Kind: yang.LeafEntry,
+				Type: &yang.YangType{
+					Kind: yang.Yunion,
+					Type: []*yang.YangType{{Kind: yang.Yuint32}, {Kind: yang.Ystring}},
+				},
+			},
+		},
+	}
+	root := &yang.Entry{
+		Name: "device",
+		Kind: yang.DirectoryEntry,
+		Annotation: map[string]interface{}{
+			"isFakeRoot": true,
+		},
+		Dir: map[string]*yang.Entry{
+			"interfaces": {
+				Name: "interfaces",
+				Kind: yang.DirectoryEntry,
+				Dir: map[string]*yang.Entry{
+					"interface": {
+						Name:     "interface",
+						Kind:     yang.DirectoryEntry,
+						ListAttr: yang.NewDefaultListAttr(),
+						Key:      "name",
+						Dir: map[string]*yang.Entry{
+							"name":   leaf("name", yang.Ystring),
+							"config": config,
+						},
+					},
+				},
+			},
+			"system": {
+				Name: "system",
+				Kind: yang.DirectoryEntry,
+				Dir: map[string]*yang.Entry{
+					"config": {
+						Name: "config",
+						Kind: yang.DirectoryEntry,
+						Dir: map[string]*yang.Entry{
+							"hostname": leaf("hostname", yang.Ystring),
+						},
+					},
+				},
+			},
+		},
+	}
+	populateParentField(nil, root)
+	return root
+}
+
+func TestUnmarshalXML(t *testing.T) {
+	tests := []struct {
+		desc             string
+		in               string
+		inOpts           []UnmarshalOpt
+		want             *xmlTestRoot
+		wantErrSubstring string
+	}{{
+		desc: "list with scalar types",
+		in: `<interfaces xmlns="urn:test">
+  <interface>
+    <name>eth0</name>
+    <config>
+      <name>eth0</name>
+      <mtu>1500</mtu>
+      <enabled>true</enabled>
+      <counter>18446744073709551615</counter>
+      <type>E_VALUE_FORTY_TWO</type>
+      <loopback/>
+      <tag>a</tag>
+      <tag>b</tag>
+    </config>
+  </interface>
+  <interface>
+    <name>eth1</name>
+    <config><name>eth1</name></config>
+  </interface>
+</interfaces>`,
+		want: &xmlTestRoot{
+			Interface: map[string]*xmlTestInterface{
+				"eth0": {
+					Name:     ygot.String("eth0"),
+					Mtu:      ygot.Uint16(1500),
+					Enabled:  ygot.Bool(true),
+					Counter:  ygot.Uint64(18446744073709551615),
+					Type:     42,
+					Loopback: true,
+					Tag:      []string{"a", "b"},
+				},
+				"eth1": {
+					Name: ygot.String("eth1"),
+				},
+			},
+		},
+	}, {
+		desc: "NETCONF reply wrapper",
+		in: `<rpc-reply xmlns="urn:ietf:params:xml:ns:netconf:base:1.0" message-id="1">
+  <data>
+    <system xmlns="urn:test"><config><hostname>router</hostname></config></system>
+  </data>
+</rpc-reply>`,
+		want: &xmlTestRoot{Hostname: ygot.String("router")},
+	}, {
+		desc: "union with numeric member",
+		in:   `<interfaces><interface><name>eth0</name><config><name>eth0</name><id>42</id></config></interface></interfaces>`,
+		want: &xmlTestRoot{
+			Interface: map[string]*xmlTestInterface{
+				"eth0": {Name: ygot.String("eth0"), Id: testutil.UnionUint32(42)},
+			},
+		},
+	}, {
+		desc: "union with string member",
+		in:   `<interfaces><interface><name>eth0</name><config><name>eth0</name><id>forty-two</id></config></interface></interfaces>`,
+		want: &xmlTestRoot{
+			Interface: map[string]*xmlTestInterface{
+				"eth0": {Name: ygot.String("eth0"), Id: testutil.UnionString("forty-two")},
+			},
+		},
+	}, {
+		desc: "identityref with declared prefix",
+		in:   `<interfaces><interface><name>eth0</name><config><name>eth0</name><kind xmlns:ti="urn:test-ident">ti:E_VALUE_FORTY_TWO</kind></config></interface></interfaces>`,
+		want: &xmlTestRoot{
+			Interface: map[string]*xmlTestInterface{
+				"eth0": {Name: ygot.String("eth0"), Kind: 42},
+			},
+		},
+	}, {
+		desc: "identityref with prefix declared on ancestor",
+		in:   `<interfaces xmlns:x="urn:test-ident"><interface><name>eth0</name><config><name>eth0</name><kind>x:E_VALUE_FORTY_TWO</kind></config></interface></interfaces>`,
+		want: &xmlTestRoot{
+			Interface: map[string]*xmlTestInterface{
+				"eth0": {Name: ygot.String("eth0"), Kind: 42},
+			},
+		},
+	}, {
+		desc: "identityref in default namespace",
+		in:   `<interfaces><interface><name>eth0</name><config><name>eth0</name><kind xmlns="urn:test-ident">E_VALUE_FORTY_TWO</kind></config></interface></interfaces>`,
+		want: &xmlTestRoot{
+			Interface: map[string]*xmlTestInterface{
+				"eth0": {Name: ygot.String("eth0"), Kind: 42},
+			},
+		},
+	}, {
+		desc:             "identityref with undeclared prefix",
+		in:               `<interfaces><interface><name>eth0</name><config><name>eth0</name><kind>ti:E_VALUE_FORTY_TWO</kind></config></interface></interfaces>`,
+		wantErrSubstring: `no XML namespace is declared for prefix "ti"`,
+	}, {
+		desc:             "identityref with prefix in wrong namespace",
+		in:               `<interfaces><interface><name>eth0</name><config><name>eth0</name><kind xmlns:ti="urn:other">ti:E_VALUE_FORTY_TWO</kind></config></interface></interfaces>`,
+		wantErrSubstring: "no identity E_VALUE_FORTY_TWO is defined in namespace urn:other",
+	}, {
+		desc:             "identityref with prefix declaration out of scope",
+		in:               `<interfaces><interface><name xmlns:ti="urn:test-ident">eth0</name><config><name>eth0</name><kind>ti:E_VALUE_FORTY_TWO</kind></config></interface></interfaces>`,
+		wantErrSubstring: `no XML namespace is declared for prefix "ti"`,
+	}, {
+		desc:             "unknown element",
+		in:               `<system><config><hostname>router</hostname><location>lab</location></config></system>`,
+		wantErrSubstring: "JSON contains unexpected field location",
+	}, {
+		desc:   "unknown element ignored",
+		in:     `<system><config><hostname>router</hostname><location>lab</location></config></system>`,
+		inOpts: []UnmarshalOpt{&IgnoreExtraFields{}},
+		want:   &xmlTestRoot{Hostname: ygot.String("router")},
+	}, {
+		desc:             "invalid integer",
+		in:               `<interfaces><interface><name>eth0</name><config><mtu>big</mtu></config></interface></interfaces>`,
+		wantErrSubstring: `cannot parse "big"`,
+	}, {
+		desc:             "malformed XML",
+		in:               `<system>`,
+		wantErrSubstring: "cannot parse XML",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := &xmlTestRoot{}
+			err := UnmarshalXML(xmlTestSchema(), []byte(tt.in), got, tt.inOpts...)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("UnmarshalXML: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("UnmarshalXML: did not get expected GoStruct, (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}